@@ -0,0 +1,78 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/sathyabhat/ynab-weekly-wrap/internal/config"
+	"github.com/sathyabhat/ynab-weekly-wrap/internal/history"
+	"github.com/sathyabhat/ynab-weekly-wrap/internal/processor"
+	"github.com/sathyabhat/ynab-weekly-wrap/internal/scheduler"
+)
+
+// runBackfill handles `backfill -weeks 4`, sending the weekly wraps of the
+// last finished weeks, oldest first, e.g. after downtime. With -out they are
+// written to files instead. A failing week doesn't stop the others.
+func runBackfill(args []string) error {
+	fs := flag.NewFlagSet("backfill", flag.ExitOnError)
+	weeks := fs.Int("weeks", 0, "Number of finished Monday-to-Sunday weeks to generate wraps for")
+	out := fs.String("out", "", "Write each wrap to a file in this directory, named by ISO week, instead of sending it")
+	dryRun := fs.Bool("dry-run", false, "Print the wraps to stdout instead of sending them")
+	profile := fs.String("profile", "", "Configuration profile to load from the profiles file")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *weeks < 1 {
+		return fmt.Errorf("usage: backfill -weeks N [-out DIR]")
+	}
+
+	cfg, err := config.LoadProfile(*profile)
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+	quiet := *dryRun || *out != ""
+	if err := config.ValidateConfig(cfg, quiet); err != nil {
+		return fmt.Errorf("invalid configuration: %w", err)
+	}
+	if *out != "" {
+		if err := os.MkdirAll(*out, 0o755); err != nil {
+			return fmt.Errorf("failed to create output directory: %w", err)
+		}
+	}
+
+	opts := []scheduler.SchedulerOption{scheduler.WithDryRun(quiet)}
+	if quiet {
+		opts = append(opts, scheduler.WithSkipTelegram(true))
+	}
+	sched := scheduler.NewScheduler(cfg, opts...)
+
+	var errs []error
+	for _, monday := range processor.LastWeeks(*weeks, time.Now().In(cfg.Schedule.Location())) {
+		sunday := monday.AddDate(0, 0, 6)
+		week := history.WeekOf(sunday)
+		if *out == "" {
+			if err := sched.RunRangeOnce(monday, sunday); err != nil {
+				errs = append(errs, fmt.Errorf("%s: %w", week, err))
+			}
+			continue
+		}
+
+		run, err := sched.RenderRange(monday, sunday)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", week, err))
+			continue
+		}
+		path := filepath.Join(*out, week+".md")
+		if err := os.WriteFile(path, []byte(run.Report.Message), 0o644); err != nil {
+			errs = append(errs, fmt.Errorf("%s: failed to write wrap: %w", week, err))
+			continue
+		}
+		log.Printf("Wrote %s", path)
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,89 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/sathyabhat/ynab-weekly-wrap/internal/config"
+	"github.com/sathyabhat/ynab-weekly-wrap/internal/pipeline"
+	"github.com/sathyabhat/ynab-weekly-wrap/internal/processor"
+	"github.com/sathyabhat/ynab-weekly-wrap/internal/scheduler"
+)
+
+// runCompare handles `compare -b proposed`, printing the weekly wrap under
+// two configuration profiles and the categories they flag differently, to
+// try out thresholds before switching to them. Nothing is sent or stored.
+func runCompare(args []string) error {
+	fs := flag.NewFlagSet("compare", flag.ExitOnError)
+	a := fs.String("a", "", "First profile, e.g. current (default: the environment without a profile)")
+	b := fs.String("b", "", "Second profile to compare against the first, e.g. proposed")
+	weekOf := fs.String("week-of", "", "Compare the Monday-to-Sunday week holding this date (default: last week)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *b == "" {
+		return fmt.Errorf("usage: compare [-a PROFILE] -b PROFILE [-week-of YYYY-MM-DD]")
+	}
+
+	configs, err := config.LoadProfiles(*a, *b)
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+
+	var runs []*pipeline.Run
+	var labels []string
+	for _, cfg := range configs {
+		label := cfg.Profile
+		if label == "" {
+			label = "environment"
+		}
+		if err := config.ValidateConfig(cfg, true); err != nil {
+			return fmt.Errorf("invalid configuration for %s: %w", label, err)
+		}
+
+		now := time.Now().In(cfg.Schedule.Location())
+		monday := processor.LastWeeks(1, now)[0]
+		start, end := monday, monday.AddDate(0, 0, 6)
+		if *weekOf != "" {
+			if start, end, err = processor.ParseDateRange("", "", *weekOf, now); err != nil {
+				return err
+			}
+		}
+
+		// Dry-run keeps the scheduler from setting up publishers or storing history
+		sched := scheduler.NewScheduler(cfg, scheduler.WithDryRun(true), scheduler.WithSkipTelegram(true))
+		run, err := sched.RenderRange(start, end)
+		if err != nil {
+			return fmt.Errorf("%s: %w", label, err)
+		}
+		runs = append(runs, run)
+		labels = append(labels, label)
+	}
+
+	for i, run := range runs {
+		fmt.Printf("════ %s ════\n\n%s\n", labels[i], run.Report.Message)
+	}
+	fmt.Print(formatFlagChanges(labels[0], labels[1], processor.CompareFlags(runs[0].Analysis, runs[1].Analysis)))
+	return nil
+}
+
+// formatFlagChanges lists the categories only one of the two wraps flags,
+// with the profile that flags them
+func formatFlagChanges(labelA, labelB string, changes []processor.FlagChange) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "════ Flagged differently: %s vs %s ════\n\n", labelA, labelB)
+	if len(changes) == 0 {
+		b.WriteString("Both flag the same categories\n")
+		return b.String()
+	}
+	for _, change := range changes {
+		only := labelB
+		if change.InA {
+			only = labelA
+		}
+		fmt.Fprintf(&b, "• %s: %s, %s only\n", change.Category, change.Flag, only)
+	}
+	return b.String()
+}
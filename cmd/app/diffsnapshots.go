@@ -0,0 +1,110 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"strings"
+
+	"github.com/sathyabhat/ynab-weekly-wrap/internal/config"
+	"github.com/sathyabhat/ynab-weekly-wrap/internal/history"
+	"github.com/sathyabhat/ynab-weekly-wrap/internal/money"
+	"github.com/sathyabhat/ynab-weekly-wrap/internal/ynab"
+)
+
+// runDiffSnapshots handles `diff-snapshots 2024-W11 2024-W12`, printing the
+// categories whose budgeted, activity or balance figures differ between two
+// stored snapshots, to work out why a wrap's numbers differ from another
+// week's or from what YNAB shows
+func runDiffSnapshots(args []string) error {
+	fs := flag.NewFlagSet("diff-snapshots", flag.ExitOnError)
+	profile := fs.String("profile", "", "Configuration profile to load from the profiles file")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 2 {
+		return fmt.Errorf("usage: diff-snapshots [-profile PROFILE] YYYY-Www YYYY-Www")
+	}
+	weeks := fs.Args()
+	for _, week := range weeks {
+		if _, err := history.ParseWeek(week); err != nil {
+			return err
+		}
+	}
+
+	cfg, err := config.LoadProfile(*profile)
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+	if cfg.History.Dir == "" {
+		return fmt.Errorf("HISTORY_DIR is not set, so no snapshots have been stored")
+	}
+
+	store := history.NewStore(cfg.History.Dir)
+	before, err := store.LoadWeek(weeks[0])
+	if err != nil {
+		return err
+	}
+	after, err := store.LoadWeek(weeks[1])
+	if err != nil {
+		return err
+	}
+
+	fmt.Print(formatSnapshotDiff(before, after, history.DiffSnapshots(before, after)))
+	return nil
+}
+
+// formatSnapshotDiff describes both snapshots, then lists each category that
+// differs with its figures before and after
+func formatSnapshotDiff(before, after *history.Snapshot, diffs []history.CategoryDiff) string {
+	var currency money.Format
+	if after.Weekly != nil && after.Weekly.Budget != nil {
+		currency = after.Weekly.Budget.Currency
+	}
+
+	var b strings.Builder
+	for _, snapshot := range []*history.Snapshot{before, after} {
+		fmt.Fprintf(&b, "%s: %s to %s, captured %s\n", snapshot.Week, snapshot.PeriodStart.Format("2006-01-02"),
+			snapshot.PeriodEnd.Format("2006-01-02"), snapshot.CapturedAt.Format("2006-01-02 15:04 MST"))
+	}
+	b.WriteString("\n")
+	if len(diffs) == 0 {
+		b.WriteString("No category differs\n")
+		return b.String()
+	}
+
+	figures := []struct {
+		label string
+		value func(*ynab.Category) int64
+	}{
+		{"budgeted", func(c *ynab.Category) int64 { return c.Budgeted }},
+		{"activity", func(c *ynab.Category) int64 { return c.Activity }},
+		{"balance", func(c *ynab.Category) int64 { return c.Balance }},
+	}
+	for _, diff := range diffs {
+		switch {
+		case diff.Before == nil:
+			fmt.Fprintf(&b, "%s: only in %s\n", diff.Name(), after.Week)
+		case diff.After == nil:
+			fmt.Fprintf(&b, "%s: only in %s\n", diff.Name(), before.Week)
+		case diff.Renamed():
+			fmt.Fprintf(&b, "%s: renamed from %s\n", diff.Name(), diff.Before.Name)
+		default:
+			fmt.Fprintf(&b, "%s\n", diff.Name())
+		}
+		for _, figure := range figures {
+			switch {
+			case diff.Before == nil:
+				fmt.Fprintf(&b, "  %-9s %s\n", figure.label, currency.Amount(figure.value(diff.After)))
+			case diff.After == nil:
+				fmt.Fprintf(&b, "  %-9s %s\n", figure.label, currency.Amount(figure.value(diff.Before)))
+			default:
+				from, to := figure.value(diff.Before), figure.value(diff.After)
+				if from == to {
+					continue
+				}
+				fmt.Fprintf(&b, "  %-9s %s → %s (%s)\n", figure.label, currency.Amount(from), currency.Amount(to), currency.Delta(to-from))
+			}
+		}
+	}
+	return b.String()
+}
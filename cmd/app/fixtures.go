@@ -0,0 +1,68 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/sathyabhat/ynab-weekly-wrap/internal/config"
+	"github.com/sathyabhat/ynab-weekly-wrap/internal/fixtures"
+	"github.com/sathyabhat/ynab-weekly-wrap/internal/ynab"
+)
+
+// runFixtures handles `fixtures capture [flags]`
+func runFixtures(args []string) error {
+	if len(args) == 0 || args[0] != "capture" {
+		return fmt.Errorf("usage: fixtures capture [-anonymize] [-out dir] [-week-end YYYY-MM-DD] [-scale n] [-seed s]")
+	}
+
+	fs := flag.NewFlagSet("fixtures capture", flag.ExitOnError)
+	anonymize := fs.Bool("anonymize", false, "Scrub payees, memos, names and IDs and scale amounts")
+	out := fs.String("out", "testdata/fixtures", "Directory to write the fixture to")
+	weekEnd := fs.String("week-end", "", "Last day of the week to capture (default today)")
+	scale := fs.Float64("scale", 0.75, "Factor applied to every amount when anonymizing")
+	seed := fs.String("seed", "ynab-weekly-wrap", "Seed for anonymized replacements; reuse it to keep them stable across captures")
+	profile := fs.String("profile", "", "Configuration profile to load from the profiles file")
+	if err := fs.Parse(args[1:]); err != nil {
+		return err
+	}
+
+	end := time.Now()
+	if *weekEnd != "" {
+		parsed, err := time.Parse("2006-01-02", *weekEnd)
+		if err != nil {
+			return fmt.Errorf("invalid -week-end %q: expected YYYY-MM-DD", *weekEnd)
+		}
+		end = parsed
+	}
+	if *anonymize && *scale <= 0 {
+		return fmt.Errorf("invalid -scale %v: must be greater than 0", *scale)
+	}
+
+	cfg, err := config.LoadProfile(*profile)
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+	// Only the YNAB settings are needed to capture
+	if err := config.ValidateConfig(cfg, true); err != nil {
+		return fmt.Errorf("invalid configuration: %w", err)
+	}
+
+	fixture, err := fixtures.Capture(ynab.NewClient(cfg.YNAB), end)
+	if err != nil {
+		return err
+	}
+	if *anonymize {
+		fixtures.NewAnonymizer(*seed, *scale).Anonymize(fixture)
+	} else {
+		log.Println("Warning: fixture is not anonymized and contains real payees, memos and amounts")
+	}
+
+	path, err := fixtures.Save(fixture, *out)
+	if err != nil {
+		return err
+	}
+	log.Printf("Fixture written to %s (%d transactions)", path, len(fixture.Weekly.Transactions))
+	return nil
+}
@@ -4,36 +4,118 @@ import (
 	"flag"
 	"log"
 	"os"
+	"time"
 
 	"github.com/sathyabhat/ynab-weekly-wrap/internal/config"
+	"github.com/sathyabhat/ynab-weekly-wrap/internal/processor"
 	"github.com/sathyabhat/ynab-weekly-wrap/internal/scheduler"
+	"github.com/sathyabhat/ynab-weekly-wrap/internal/trigger"
 )
 
 func main() {
+	// Subcommands come before the flags
+	if len(os.Args) > 1 {
+		switch os.Args[1] {
+		case "fixtures":
+			if err := runFixtures(os.Args[2:]); err != nil {
+				log.Fatalf("Fixture capture failed: %v", err)
+			}
+			os.Exit(0)
+		case "rerender":
+			if err := runRerender(os.Args[2:]); err != nil {
+				log.Fatalf("Rerender failed: %v", err)
+			}
+			os.Exit(0)
+		case "backfill":
+			if err := runBackfill(os.Args[2:]); err != nil {
+				log.Fatalf("Backfill failed: %v", err)
+			}
+			os.Exit(0)
+		case "schema":
+			runSchema()
+			os.Exit(0)
+		case "summary":
+			if err := runSummary(os.Args[2:]); err != nil {
+				log.Fatalf("Summary failed: %v", err)
+			}
+			os.Exit(0)
+		case "compare":
+			if err := runCompare(os.Args[2:]); err != nil {
+				log.Fatalf("Comparison failed: %v", err)
+			}
+			os.Exit(0)
+		case "diff-snapshots":
+			if err := runDiffSnapshots(os.Args[2:]); err != nil {
+				log.Fatalf("Snapshot diff failed: %v", err)
+			}
+			os.Exit(0)
+		case "schedule-once":
+			if err := runScheduleOnce(os.Args[2:]); err != nil {
+				log.Fatalf("Scheduling failed: %v", err)
+			}
+			os.Exit(0)
+		case "notify":
+			if err := runNotify(os.Args[2:]); err != nil {
+				log.Fatalf("Notification failed: %v", err)
+			}
+			os.Exit(0)
+		case "pause":
+			if err := runPause(os.Args[2:]); err != nil {
+				log.Fatalf("Pause failed: %v", err)
+			}
+			os.Exit(0)
+		case "resume":
+			if err := runResume(os.Args[2:]); err != nil {
+				log.Fatalf("Resume failed: %v", err)
+			}
+			os.Exit(0)
+		}
+	}
+
 	// Command-line flags
 	dryRun := flag.Bool("dry-run", false, "Run once and print output to stdout without sending to Telegram")
+	dryRunTo := flag.Int64("dry-run-to", 0, "Run once and send the message to this Telegram chat ID instead of stdout")
 	once := flag.Bool("once", false, "Run once and exit (for manual testing)")
 	onceMonthly := flag.Bool("once-monthly", false, "Run monthly wrap once and exit")
+	onceDaily := flag.Bool("once-daily", false, "Run the daily digest once and exit")
+	onceMeeting := flag.Bool("once-meeting", false, "Send the budget meeting agenda once and exit")
+	onceAlerts := flag.Bool("once-alerts", false, "Run critical alert checks once and exit")
+	from := flag.String("from", "", "Run the wrap once for the days from this date, e.g. 2026-01-19, and exit")
+	to := flag.String("to", "", "Last day of the -from wrap, inclusive (default: today)")
+	weekOf := flag.String("week-of", "", "Run the wrap once for the Monday-to-Sunday week holding this date and exit")
+	profile := flag.String("profile", "", "Configuration profile to load from the profiles file, e.g. test or prod")
 	flag.Parse()
 
 	log.Println("Starting YNAB Weekly Wrap...")
 
 	// Load configuration
-	cfg, err := config.LoadConfig()
+	cfg, err := config.LoadProfile(*profile)
 	if err != nil {
 		log.Fatalf("Failed to load configuration: %v", err)
 	}
 
 	// Validate configuration (skip Telegram validation in test modes)
-	testMode := *dryRun || *once || *onceMonthly
+	dateRange := *from != "" || *to != "" || *weekOf != ""
+	testMode := *dryRun || *dryRunTo != 0 || *once || *onceMonthly || *onceDaily || *onceMeeting || *onceAlerts || dateRange
 	if err := config.ValidateConfig(cfg, testMode); err != nil {
 		log.Fatalf("Invalid configuration: %v", err)
 	}
 
 	log.Printf("Configuration loaded successfully")
+	if cfg.Profile != "" {
+		log.Printf("Profile: %s", cfg.Profile)
+	}
 	log.Printf("Budget ID: %s", cfg.YNAB.BudgetID)
+	if cfg.YNAB.ReadOnly {
+		log.Println("Read-only mode: writes to YNAB are disabled")
+	}
 
-	if *dryRun {
+	if *dryRunTo != 0 {
+		if cfg.Telegram.BotToken == "" {
+			log.Fatalf("Invalid configuration: -dry-run-to requires TELEGRAM_BOT_TOKEN")
+		}
+		log.Printf("[DRY RUN MODE] Will send output to Telegram chat %d instead of publishers", *dryRunTo)
+	} else if *dryRun {
 		log.Println("[DRY RUN MODE] Will print output to stdout instead of sending to publishers")
 	}
 	if *once {
@@ -42,6 +124,15 @@ func main() {
 	if *onceMonthly {
 		log.Println("[ONCE MONTHLY MODE] Will run monthly wrap once and exit")
 	}
+	if *onceDaily {
+		log.Println("[ONCE DAILY MODE] Will run the daily digest once and exit")
+	}
+	if *onceMeeting {
+		log.Println("[ONCE MEETING MODE] Will send the meeting agenda once and exit")
+	}
+	if *onceAlerts {
+		log.Println("[ONCE ALERTS MODE] Will run alert checks once and exit")
+	}
 
 	// Initialize scheduler (skip Telegram only in dry-run mode)
 	dryRunMode := *dryRun
@@ -49,19 +140,61 @@ func main() {
 	if *dryRun {
 		opts = append(opts, scheduler.WithSkipTelegram(true))
 	}
+	if *dryRunTo != 0 {
+		opts = append(opts, scheduler.WithDryRunTo(*dryRunTo))
+	}
 	sched := scheduler.NewScheduler(cfg, opts...)
 
 	// Monthly once — check before the weekly once/dry-run block
 	if *onceMonthly {
 		log.Println("Running monthly wrap once and exiting...")
-		sched.RunMonthlyOnce()
+		if err := sched.RunMonthlyOnce(); err != nil {
+			log.Fatalf("Monthly wrap failed: %v", err)
+		}
+		os.Exit(0)
+	}
+
+	if *onceDaily {
+		log.Println("Running daily digest once and exiting...")
+		if err := sched.RunDailyOnce(); err != nil {
+			log.Fatalf("Daily digest failed: %v", err)
+		}
+		os.Exit(0)
+	}
+
+	if *onceMeeting {
+		log.Println("Sending meeting agenda once and exiting...")
+		if err := sched.RunMeetingOnce(); err != nil {
+			log.Fatalf("Meeting agenda failed: %v", err)
+		}
+		os.Exit(0)
+	}
+
+	if *onceAlerts {
+		log.Println("Running alert checks once and exiting...")
+		if err := sched.RunAlertsOnce(); err != nil {
+			log.Fatalf("Alert checks failed: %v", err)
+		}
+		os.Exit(0)
+	}
+
+	if dateRange {
+		start, end, err := processor.ParseDateRange(*from, *to, *weekOf, time.Now().In(cfg.Schedule.Location()))
+		if err != nil {
+			log.Fatalf("Invalid date range: %v", err)
+		}
+		if err := sched.RunRangeOnce(start, end); err != nil {
+			log.Fatalf("Wrap failed: %v", err)
+		}
 		os.Exit(0)
 	}
 
 	// Run weekly once for testing if requested
-	if *once || *dryRun {
+	if *once || *dryRun || *dryRunTo != 0 {
 		log.Println("Running once and exiting...")
-		sched.RunOnce()
+		if err := sched.RunOnce(); err != nil {
+			log.Fatalf("Weekly wrap failed: %v", err)
+		}
 		os.Exit(0)
 	}
 
@@ -70,6 +203,15 @@ func main() {
 		log.Fatalf("Failed to start scheduler: %v", err)
 	}
 
+	// Serve on-demand wraps alongside the schedule
+	if cfg.Trigger.Addr != "" {
+		go func() {
+			if err := trigger.NewServer(cfg.Trigger, sched).ListenAndServe(); err != nil {
+				log.Fatalf("Trigger endpoint failed: %v", err)
+			}
+		}()
+	}
+
 	// Keep the application running
 	select {}
 }
@@ -0,0 +1,52 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/sathyabhat/ynab-weekly-wrap/internal/config"
+	"github.com/sathyabhat/ynab-weekly-wrap/internal/scheduler"
+)
+
+// runNotify handles `notify "Rent is due tomorrow"`, sending a message from
+// another household script through the configured publishers, with their
+// credentials and delivery retries. Without a message it reads one from
+// stdin.
+func runNotify(args []string) error {
+	fs := flag.NewFlagSet("notify", flag.ExitOnError)
+	title := fs.String("title", "", "Bold title line to send above the message")
+	dryRun := fs.Bool("dry-run", false, "Print the message to stdout instead of sending it")
+	profile := fs.String("profile", "", "Configuration profile to load from the profiles file")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	message := strings.Join(fs.Args(), " ")
+	if fs.NArg() == 0 || message == "-" {
+		data, err := io.ReadAll(os.Stdin)
+		if err != nil {
+			return fmt.Errorf("failed to read message from stdin: %w", err)
+		}
+		message = string(data)
+	}
+
+	cfg, err := config.LoadProfile(*profile)
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+	// The message needs no budget, so only the publishers are checked
+	if !*dryRun {
+		if err := config.ValidateDelivery(cfg); err != nil {
+			return fmt.Errorf("invalid configuration: %w", err)
+		}
+	}
+
+	opts := []scheduler.SchedulerOption{scheduler.WithDryRun(*dryRun)}
+	if *dryRun {
+		opts = append(opts, scheduler.WithSkipTelegram(true))
+	}
+	return scheduler.NewScheduler(cfg, opts...).Notify(*title, message)
+}
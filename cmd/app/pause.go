@@ -0,0 +1,91 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"time"
+
+	"github.com/sathyabhat/ynab-weekly-wrap/internal/config"
+	"github.com/sathyabhat/ynab-weekly-wrap/internal/pause"
+)
+
+// runPause handles `pause -for 3w`, pausing the running scheduler's sends
+// until then. Without -for it shows whether the sends are paused.
+func runPause(args []string) error {
+	fs := flag.NewFlagSet("pause", flag.ExitOnError)
+	until := fs.String("for", "", `How long to pause, e.g. "3w", "10d", "2m", or the day to resume, e.g. "2026-11-01"`)
+	profile := fs.String("profile", "", "Configuration profile to load from the profiles file")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	store, err := pauseStore(*profile)
+	if err != nil {
+		return err
+	}
+	now := time.Now().In(store.Location())
+
+	if *until == "" {
+		state, err := store.Current()
+		if err != nil {
+			return err
+		}
+		if !state.Active(now) {
+			fmt.Println("Scheduled wraps are running")
+			return nil
+		}
+		fmt.Printf("Scheduled wraps are paused until %s\n", state.Until.Format(time.RFC1123))
+		return nil
+	}
+
+	when, err := pause.ParseUntil(*until, now)
+	if err != nil {
+		return err
+	}
+	state, err := store.Pause(now, when)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Scheduled wraps are paused until %s\n", state.Until.Format(time.RFC1123))
+	return nil
+}
+
+// runResume handles `resume`, ending the pause. The running scheduler then
+// resumes the sends and catches up on the days missed.
+func runResume(args []string) error {
+	fs := flag.NewFlagSet("resume", flag.ExitOnError)
+	profile := fs.String("profile", "", "Configuration profile to load from the profiles file")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	store, err := pauseStore(*profile)
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	state, err := store.Current()
+	if err != nil {
+		return err
+	}
+	if !state.Active(now) {
+		return fmt.Errorf("scheduled wraps aren't paused")
+	}
+	if _, err := store.Resume(now); err != nil {
+		return err
+	}
+	fmt.Println("Scheduled wraps resume within a minute, starting with a catch-up wrap of the days missed")
+	return nil
+}
+
+// pauseStore opens the pause file the scheduler of a profile watches
+func pauseStore(profile string) (*pause.Store, error) {
+	cfg, err := config.LoadProfile(profile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load configuration: %w", err)
+	}
+	if cfg.Schedule.PauseFile == "" {
+		return nil, fmt.Errorf("SCHEDULE_PAUSE_FILE is not set, so the scheduler won't see a pause")
+	}
+	return pause.NewStore(cfg.Schedule.PauseFile, cfg.Schedule.Location()), nil
+}
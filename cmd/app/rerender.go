@@ -0,0 +1,49 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+
+	"github.com/sathyabhat/ynab-weekly-wrap/internal/config"
+	"github.com/sathyabhat/ynab-weekly-wrap/internal/history"
+	"github.com/sathyabhat/ynab-weekly-wrap/internal/scheduler"
+)
+
+// runRerender handles `rerender -week 2024-W12`, printing a past weekly wrap
+// rendered from its stored snapshot with the current formatter
+func runRerender(args []string) error {
+	fs := flag.NewFlagSet("rerender", flag.ExitOnError)
+	week := fs.String("week", "", "ISO week of the stored wrap to re-render, e.g. 2024-W12")
+	profile := fs.String("profile", "", "Configuration profile to load from the profiles file")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *week == "" {
+		return fmt.Errorf("usage: rerender -week YYYY-Www")
+	}
+	if _, err := history.ParseWeek(*week); err != nil {
+		return err
+	}
+
+	cfg, err := config.LoadProfile(*profile)
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+	if cfg.History.Dir == "" {
+		return fmt.Errorf("HISTORY_DIR is not set, so no snapshots have been stored")
+	}
+
+	snapshot, err := history.NewStore(cfg.History.Dir).LoadWeek(*week)
+	if err != nil {
+		return err
+	}
+
+	// Dry-run keeps the scheduler from setting up publishers or storing history
+	run, err := scheduler.NewScheduler(cfg, scheduler.WithDryRun(true)).RerenderWeek(snapshot)
+	if err != nil {
+		return err
+	}
+
+	fmt.Println(run.Report.Message)
+	return nil
+}
@@ -0,0 +1,74 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"time"
+
+	"github.com/sathyabhat/ynab-weekly-wrap/internal/config"
+	"github.com/sathyabhat/ynab-weekly-wrap/internal/oneoff"
+)
+
+// runScheduleOnce handles `schedule-once -at "friday 6pm"`, queueing a wrap
+// for the running scheduler to send once at that time. -list shows the
+// queued wraps and -cancel removes one.
+func runScheduleOnce(args []string) error {
+	fs := flag.NewFlagSet("schedule-once", flag.ExitOnError)
+	at := fs.String("at", "", `When to send, e.g. "friday 6pm", "tomorrow 9am" or "2026-10-23 18:00"`)
+	period := fs.String("period", "weekly", "Wrap to send: weekly or monthly")
+	list := fs.Bool("list", false, "List the queued wraps")
+	cancel := fs.Int("cancel", 0, "Cancel the queued wrap with this number")
+	profile := fs.String("profile", "", "Configuration profile to load from the profiles file")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	cfg, err := config.LoadProfile(*profile)
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+	if cfg.Schedule.OnceFile == "" {
+		return fmt.Errorf("SCHEDULE_ONCE_FILE is not set, so the scheduler won't pick up queued wraps")
+	}
+	store := oneoff.NewStore(cfg.Schedule.OnceFile, cfg.Schedule.Location())
+
+	switch {
+	case *list:
+		pending, err := store.Pending()
+		if err != nil {
+			return err
+		}
+		if len(pending) == 0 {
+			fmt.Println("No wraps scheduled")
+		}
+		for _, send := range pending {
+			fmt.Printf("#%d  %-7s  %s\n", send.ID, send.Period, send.At.Format(time.RFC1123))
+		}
+		return nil
+
+	case *cancel != 0:
+		found, err := store.Cancel(*cancel)
+		if err != nil {
+			return err
+		}
+		if !found {
+			return fmt.Errorf("no scheduled wrap #%d", *cancel)
+		}
+		fmt.Printf("Cancelled scheduled wrap #%d\n", *cancel)
+		return nil
+
+	case *at == "":
+		return fmt.Errorf(`usage: schedule-once -at "friday 6pm" [-period monthly] | -list | -cancel N`)
+	}
+
+	when, err := oneoff.ParseWhen(*at, time.Now().In(store.Location()))
+	if err != nil {
+		return err
+	}
+	send, err := store.Add(when, *period)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Scheduled the %s wrap for %s (#%d)\n", send.Period, send.At.Format(time.RFC1123), send.ID)
+	return nil
+}
@@ -0,0 +1,13 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/sathyabhat/ynab-weekly-wrap/internal/archive"
+)
+
+// runSchema handles `schema`, printing the JSON schema of the weekly archive
+// files written to ARCHIVE_DIR
+func runSchema() {
+	fmt.Print(archive.JSONSchema)
+}
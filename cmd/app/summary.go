@@ -0,0 +1,43 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"time"
+
+	"github.com/sathyabhat/ynab-weekly-wrap/internal/config"
+	"github.com/sathyabhat/ynab-weekly-wrap/internal/processor"
+	"github.com/sathyabhat/ynab-weekly-wrap/internal/scheduler"
+)
+
+// runSummary handles `summary -period quarterly -of 2026-Q3`, sending the
+// summary of a quarter or year to the configured publishers
+func runSummary(args []string) error {
+	fs := flag.NewFlagSet("summary", flag.ExitOnError)
+	period := fs.String("period", processor.SummaryQuarterly, "quarterly or yearly")
+	of := fs.String("of", "", "Quarter or year to summarize, e.g. 2026-Q3 or 2025 (default: the last one to finish)")
+	dryRun := fs.Bool("dry-run", false, "Print the summary to stdout instead of sending it")
+	profile := fs.String("profile", "", "Configuration profile to load from the profiles file")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	cfg, err := config.LoadProfile(*profile)
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+	if err := config.ValidateConfig(cfg, *dryRun); err != nil {
+		return fmt.Errorf("invalid configuration: %w", err)
+	}
+
+	summaryPeriod, err := processor.ParseSummaryPeriod(*period, *of, time.Now().In(cfg.Schedule.Location()))
+	if err != nil {
+		return err
+	}
+
+	opts := []scheduler.SchedulerOption{scheduler.WithDryRun(*dryRun)}
+	if *dryRun {
+		opts = append(opts, scheduler.WithSkipTelegram(true))
+	}
+	return scheduler.NewScheduler(cfg, opts...).RunSummaryOnce(summaryPeriod)
+}
@@ -0,0 +1,230 @@
+// Package archive writes each weekly wrap's figures to JSON and CSV files
+// with a versioned schema, for spreadsheets and other tools to import.
+package archive
+
+import (
+	"bytes"
+	_ "embed"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/sathyabhat/ynab-weekly-wrap/internal/history"
+	"github.com/sathyabhat/ynab-weekly-wrap/internal/pipeline"
+	"github.com/sathyabhat/ynab-weekly-wrap/internal/ynab"
+)
+
+// SchemaVersion is the version of the archive files. Fields may be added
+// within a version; renaming, removing or changing the meaning of one bumps
+// it, so importers can tell which layout they are reading.
+const SchemaVersion = 1
+
+// JSONSchema describes the JSON archive files
+//
+//go:embed schema.json
+var JSONSchema string
+
+// Report is a week's figures as written to the archive. Amounts are in
+// currency units, with spending positive.
+type Report struct {
+	SchemaVersion int           `json:"schema_version"`
+	Week          string        `json:"week"`         // ISO week, e.g. 2024-W12
+	PeriodStart   string        `json:"period_start"` // YYYY-MM-DD
+	PeriodEnd     string        `json:"period_end"`   // YYYY-MM-DD
+	GeneratedAt   time.Time     `json:"generated_at"`
+	Currency      string        `json:"currency"` // ISO code, e.g. USD
+	TotalSpent    float64       `json:"total_spent"`
+	TotalBudgeted float64       `json:"total_budgeted"`
+	TotalBalance  float64       `json:"total_balance"`
+	HealthPercent float64       `json:"health_percent"` // Share of the month's budget used
+	Categories    []Category    `json:"categories"`
+	Transactions  []Transaction `json:"transactions"`
+}
+
+// Category is one budget category's figures for the week and month
+type Category struct {
+	Name       string  `json:"name"`
+	Group      string  `json:"group"`
+	Spent      float64 `json:"spent"`    // This week
+	Budgeted   float64 `json:"budgeted"` // This month
+	Balance    float64 `json:"balance"`  // Left this month
+	OverBudget bool    `json:"over_budget"`
+}
+
+// Transaction is one of the week's transactions, with outflows negative as
+// in YNAB
+type Transaction struct {
+	Date     string  `json:"date"` // YYYY-MM-DD
+	Payee    string  `json:"payee"`
+	Category string  `json:"category"`
+	Account  string  `json:"account"`
+	Amount   float64 `json:"amount"`
+	Memo     string  `json:"memo"`
+}
+
+// csvHeader is the header row of the CSV archive files, one row per category
+var csvHeader = []string{"schema_version", "week", "category", "group", "spent", "budgeted", "balance", "over_budget"}
+
+// Archive keeps the files under dir/weekly, as <week>.json and <week>.csv
+type Archive struct {
+	dir string
+}
+
+// New creates an archive rooted at dir
+func New(dir string) *Archive {
+	return &Archive{dir: dir}
+}
+
+// Export implements pipeline.Exporter, archiving every weekly run
+func (a *Archive) Export(run *pipeline.Run) error {
+	if run.Weekly == nil || run.Analysis == nil {
+		return nil
+	}
+	report := NewReport(run, time.Now().UTC())
+
+	data, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode archive: %w", err)
+	}
+	table, err := report.CSV()
+	if err != nil {
+		return err
+	}
+
+	base := filepath.Join(a.dir, "weekly", report.Week)
+	if err := os.MkdirAll(filepath.Dir(base), 0o755); err != nil {
+		return fmt.Errorf("failed to create archive directory: %w", err)
+	}
+	if err := os.WriteFile(base+".json", data, 0o644); err != nil {
+		return fmt.Errorf("failed to write archive: %w", err)
+	}
+	if err := os.WriteFile(base+".csv", table, 0o644); err != nil {
+		return fmt.Errorf("failed to write archive: %w", err)
+	}
+	log.Printf("Archived %s to %s.json and .csv", report.Week, base)
+	return nil
+}
+
+// NewReport collects a weekly run's figures for the archive
+func NewReport(run *pipeline.Run, generatedAt time.Time) *Report {
+	weekly, overview := run.Weekly, run.Analysis.Overview
+	report := &Report{
+		SchemaVersion: SchemaVersion,
+		Week:          history.WeekOf(run.PeriodEnd),
+		PeriodStart:   run.PeriodStart.Format("2006-01-02"),
+		PeriodEnd:     run.PeriodEnd.Format("2006-01-02"),
+		GeneratedAt:   generatedAt,
+		Currency:      run.Analysis.Currency.ISOCode,
+		Categories:    []Category{},
+		Transactions:  []Transaction{},
+	}
+	if overview != nil {
+		report.TotalSpent = units(overview.TotalSpent)
+		report.TotalBudgeted = units(overview.TotalBudgeted)
+		report.TotalBalance = units(overview.TotalBalance)
+		report.HealthPercent = overview.HealthPercentage
+	}
+
+	// Categories' spending is what the wrap counted, without transfers or
+	// excluded flags, so the rows add up to the total
+	spent := make(map[string]int64)
+	for _, tx := range run.Analysis.Spending {
+		if !tx.Deleted && tx.CategoryID != nil && tx.Amount < 0 {
+			spent[*tx.CategoryID] += -tx.Amount
+		}
+	}
+	for _, tx := range weekly.Transactions {
+		if !tx.Deleted {
+			report.Transactions = append(report.Transactions, newTransaction(tx))
+		}
+	}
+	sort.SliceStable(report.Transactions, func(i, j int) bool {
+		return report.Transactions[i].Date < report.Transactions[j].Date
+	})
+
+	// With the wrap restricted to focus categories, so is the archive
+	focus := make(map[string]bool, len(run.Analysis.Focus))
+	for _, name := range run.Analysis.Focus {
+		focus[name] = true
+	}
+	for _, cat := range weekly.Categories {
+		if cat.Hidden || cat.CategoryGroup.Hidden || cat.CategoryGroup.Deleted {
+			continue
+		}
+		if len(focus) > 0 && !focus[cat.Name] && !focus[cat.CategoryGroup.Name] {
+			continue
+		}
+		report.Categories = append(report.Categories, Category{
+			Name:       cat.Name,
+			Group:      cat.CategoryGroup.Name,
+			Spent:      units(spent[cat.ID]),
+			Budgeted:   units(cat.Budgeted),
+			Balance:    units(cat.Balance),
+			OverBudget: cat.Balance < 0,
+		})
+	}
+	sort.SliceStable(report.Categories, func(i, j int) bool {
+		if report.Categories[i].Spent != report.Categories[j].Spent {
+			return report.Categories[i].Spent > report.Categories[j].Spent
+		}
+		return report.Categories[i].Name < report.Categories[j].Name
+	})
+	return report
+}
+
+func newTransaction(tx ynab.Transaction) Transaction {
+	date := ""
+	if tx.Date != nil {
+		date = tx.Date.Format("2006-01-02")
+	}
+	return Transaction{
+		Date:     date,
+		Payee:    tx.PayeeName,
+		Category: tx.CategoryName,
+		Account:  tx.AccountName,
+		Amount:   units(tx.Amount),
+		Memo:     tx.Memo,
+	}
+}
+
+// CSV writes the report's categories as CSV, one row per category, each
+// carrying the schema version and week so files can be appended together
+func (r *Report) CSV() ([]byte, error) {
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	w.Write(csvHeader)
+	version := strconv.Itoa(r.SchemaVersion)
+	for _, cat := range r.Categories {
+		w.Write([]string{
+			version,
+			r.Week,
+			cat.Name,
+			cat.Group,
+			decimal(cat.Spent),
+			decimal(cat.Budgeted),
+			decimal(cat.Balance),
+			strconv.FormatBool(cat.OverBudget),
+		})
+	}
+	w.Flush()
+	if err := w.Error(); err != nil {
+		return nil, fmt.Errorf("failed to write archive CSV: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// units converts milliunits to currency units
+func units(milliunits int64) float64 {
+	return float64(milliunits) / 1000
+}
+
+// decimal writes an amount without trailing zeros or exponents
+func decimal(amount float64) string {
+	return strconv.FormatFloat(amount, 'f', -1, 64)
+}
@@ -0,0 +1,137 @@
+package archive
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sathyabhat/ynab-weekly-wrap/internal/money"
+	"github.com/sathyabhat/ynab-weekly-wrap/internal/pipeline"
+	"github.com/sathyabhat/ynab-weekly-wrap/internal/processor"
+	"github.com/sathyabhat/ynab-weekly-wrap/internal/ynab"
+)
+
+func testRun() *pipeline.Run {
+	groceries, dining, savings := "c1", "c2", "acct-2"
+	day := time.Date(2026, 1, 21, 0, 0, 0, 0, time.UTC)
+	spending := []ynab.Transaction{
+		{Date: &day, PayeeName: "Cafe", CategoryID: &dining, CategoryName: "Dining Out", AccountName: "Card", Amount: -112_500},
+		{Date: &day, PayeeName: "Market", CategoryID: &groceries, CategoryName: "Groceries", AccountName: "Card", Amount: -80_250},
+	}
+	transfer := ynab.Transaction{Date: &day, PayeeName: "Transfer : Brokerage", CategoryID: &groceries, CategoryName: "Groceries", AccountName: "Card", Amount: -50_000, TransferAccountID: &savings}
+	return &pipeline.Run{
+		PeriodStart: time.Date(2026, 1, 19, 0, 0, 0, 0, time.UTC),
+		PeriodEnd:   time.Date(2026, 1, 25, 0, 0, 0, 0, time.UTC),
+		Weekly: &ynab.WeeklyData{
+			Categories: []ynab.Category{
+				{ID: "c1", Name: "Groceries", CategoryGroup: ynab.CategoryGroup{Name: "Everyday"}, Budgeted: 500_000, Balance: 300_000},
+				{ID: "c2", Name: "Dining Out", CategoryGroup: ynab.CategoryGroup{Name: "Everyday"}, Budgeted: 100_000, Balance: -12_500},
+				{ID: "c3", Name: "Old", Hidden: true},
+			},
+			Transactions: append(spending, transfer),
+		},
+		Analysis: &processor.AnalysisResult{
+			Overview: &processor.Overview{TotalSpent: 192_750, TotalBudgeted: 600_000, TotalBalance: 287_500, HealthPercentage: 52.1},
+			Spending: spending,
+			Currency: money.USD,
+		},
+	}
+}
+
+func TestNewReport(t *testing.T) {
+	report := NewReport(testRun(), time.Date(2026, 1, 26, 9, 0, 0, 0, time.UTC))
+
+	if report.SchemaVersion != SchemaVersion || report.Week != "2026-W04" || report.Currency != "USD" || report.TotalSpent != 192.75 {
+		t.Errorf("unexpected report: %+v", report)
+	}
+	want := []Category{
+		{Name: "Dining Out", Group: "Everyday", Spent: 112.5, Budgeted: 100, Balance: -12.5, OverBudget: true},
+		{Name: "Groceries", Group: "Everyday", Spent: 80.25, Budgeted: 500, Balance: 300},
+	}
+	if !reflect.DeepEqual(report.Categories, want) {
+		t.Errorf("categories: got %+v, want %+v", report.Categories, want)
+	}
+	// The transfer is listed but, like in the wrap, not counted as spending
+	if len(report.Transactions) != 3 || report.Transactions[0].Amount != -112.5 || report.Transactions[0].Date != "2026-01-21" {
+		t.Errorf("unexpected transactions: %+v", report.Transactions)
+	}
+}
+
+func TestNewReport_FocusCategories(t *testing.T) {
+	run := testRun()
+	run.Analysis.Focus = []string{"Dining Out"}
+
+	report := NewReport(run, time.Date(2026, 1, 26, 9, 0, 0, 0, time.UTC))
+	if len(report.Categories) != 1 || report.Categories[0].Name != "Dining Out" {
+		t.Errorf("expected only the focus category, got %+v", report.Categories)
+	}
+}
+
+func TestExport_WritesJSONAndCSV(t *testing.T) {
+	dir := t.TempDir()
+	if err := New(dir).Export(testRun()); err != nil {
+		t.Fatalf("Export failed: %v", err)
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, "weekly", "2026-W04.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var decoded map[string]any
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if decoded["schema_version"] != float64(1) {
+		t.Errorf("expected schema_version 1, got %v", decoded["schema_version"])
+	}
+
+	table, err := os.ReadFile(filepath.Join(dir, "weekly", "2026-W04.csv"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantCSV := "schema_version,week,category,group,spent,budgeted,balance,over_budget\n" +
+		"1,2026-W04,Dining Out,Everyday,112.5,100,-12.5,true\n" +
+		"1,2026-W04,Groceries,Everyday,80.25,500,300,false\n"
+	if string(table) != wantCSV {
+		t.Errorf("CSV:\ngot  %q\nwant %q", table, wantCSV)
+	}
+}
+
+// TestJSONSchema_MatchesReport keeps the published schema in step with the
+// fields actually written
+func TestJSONSchema_MatchesReport(t *testing.T) {
+	type node struct {
+		Const      any             `json:"const"`
+		Required   []string        `json:"required"`
+		Properties map[string]node `json:"properties"`
+		Items      *node           `json:"items"`
+	}
+	var schema node
+	if err := json.Unmarshal([]byte(JSONSchema), &schema); err != nil {
+		t.Fatalf("schema is not valid JSON: %v", err)
+	}
+	if schema.Properties["schema_version"].Const != float64(SchemaVersion) {
+		t.Errorf("schema is for version %v, archive writes %d", schema.Properties["schema_version"].Const, SchemaVersion)
+	}
+
+	check := func(name string, typ reflect.Type, n node) {
+		var fields []string
+		for i := range typ.NumField() {
+			tag, _, _ := strings.Cut(typ.Field(i).Tag.Get("json"), ",")
+			fields = append(fields, tag)
+			if _, ok := n.Properties[tag]; !ok {
+				t.Errorf("%s field %q is missing from the schema", name, tag)
+			}
+		}
+		if len(n.Properties) != len(fields) || !reflect.DeepEqual(n.Required, fields) {
+			t.Errorf("%s schema lists %v, struct has %v", name, n.Required, fields)
+		}
+	}
+	check("report", reflect.TypeOf(Report{}), schema)
+	check("category", reflect.TypeOf(Category{}), *schema.Properties["categories"].Items)
+	check("transaction", reflect.TypeOf(Transaction{}), *schema.Properties["transactions"].Items)
+}
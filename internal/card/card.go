@@ -0,0 +1,114 @@
+package card
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+
+	"github.com/sathyabhat/ynab-weekly-wrap/internal/processor"
+)
+
+// Card dimensions and layout, in image pixels
+const (
+	width     = 800
+	height    = 460
+	margin    = 40
+	topCount  = 3
+	barHeight = 16
+)
+
+var (
+	background = color.RGBA{R: 0x1e, G: 0x22, B: 0x35, A: 0xff}
+	foreground = color.RGBA{R: 0xff, G: 0xff, B: 0xff, A: 0xff}
+	muted      = color.RGBA{R: 0x9a, G: 0xa0, B: 0xb8, A: 0xff}
+	track      = color.RGBA{R: 0x33, G: 0x39, B: 0x55, A: 0xff}
+	good       = color.RGBA{R: 0x2e, G: 0xcc, B: 0x71, A: 0xff}
+	warning    = color.RGBA{R: 0xf3, G: 0x9c, B: 0x12, A: 0xff}
+	danger     = color.RGBA{R: 0xe7, G: 0x4c, B: 0x3c, A: 0xff}
+)
+
+// Render draws the wrap's overview as a shareable PNG card: total spent,
+// how much of the month's budget is used, and the top categories. heading
+// names the wrap, e.g. "Weekly Financial Wrap".
+func Render(heading string, analysis *processor.AnalysisResult) ([]byte, error) {
+	if analysis == nil || analysis.Overview == nil {
+		return nil, fmt.Errorf("summary card needs an analysis with an overview")
+	}
+
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	draw.Draw(img, img.Bounds(), &image.Uniform{C: background}, image.Point{}, draw.Src)
+
+	health := analysis.Overview.HealthPercentage
+	accent := healthColor(health)
+	fillRect(img, image.Rect(0, 0, width, 12), accent)
+
+	drawText(img, margin, 40, fitText(heading, 3, width-2*margin), 3, foreground)
+	drawText(img, margin, 78, analysis.DateRange, 2, muted)
+
+	drawText(img, margin, 124, "Spent", 2, muted)
+	drawText(img, margin, 150, analysis.Currency.Whole(analysis.Overview.TotalSpent), 6, foreground)
+
+	if !analysis.NoBudget {
+		drawText(img, margin, 218, fmt.Sprintf("Budget used %.0f%%", health), 2, muted)
+		drawBar(img, image.Rect(margin, 242, width-margin, 242+barHeight), health/100, accent)
+	}
+
+	top := analysis.TopSpending
+	if len(top) > topCount {
+		top = top[:topCount]
+	}
+	var most int64
+	for _, category := range top {
+		most = max(most, category.Spent)
+	}
+	y := 290
+	for _, category := range top {
+		amount := analysis.Currency.Whole(category.Spent)
+		amountX := width - margin - textWidth(amount, 2)
+		drawText(img, margin, y, fitText(category.Category, 2, amountX-margin-24), 2, foreground)
+		drawText(img, amountX, y, amount, 2, foreground)
+		if most > 0 {
+			drawBar(img, image.Rect(margin, y+22, width-margin, y+28), float64(category.Spent)/float64(most), accent)
+		}
+		y += 52
+	}
+
+	return encode(img, "summary card")
+}
+
+// encode returns img as PNG bytes; what names the image in errors
+func encode(img image.Image, what string) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return nil, fmt.Errorf("failed to encode %s: %w", what, err)
+	}
+	return buf.Bytes(), nil
+}
+
+// healthColor picks the accent for how much of the budget is used
+func healthColor(percent float64) color.Color {
+	switch {
+	case percent > 100:
+		return danger
+	case percent > 90:
+		return warning
+	default:
+		return good
+	}
+}
+
+// drawBar draws a track filled to fraction (clamped to 0-1)
+func drawBar(img *image.RGBA, r image.Rectangle, fraction float64, c color.Color) {
+	fillRect(img, r, track)
+	fraction = min(max(fraction, 0), 1)
+	fill := r
+	fill.Max.X = r.Min.X + int(float64(r.Dx())*fraction)
+	fillRect(img, fill, c)
+}
+
+func fillRect(img *image.RGBA, r image.Rectangle, c color.Color) {
+	draw.Draw(img, r, &image.Uniform{C: c}, image.Point{}, draw.Src)
+}
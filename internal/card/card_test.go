@@ -0,0 +1,67 @@
+package card
+
+import (
+	"bytes"
+	"image/png"
+	"testing"
+
+	"github.com/sathyabhat/ynab-weekly-wrap/internal/processor"
+)
+
+func sampleAnalysis(health float64) *processor.AnalysisResult {
+	return &processor.AnalysisResult{
+		Overview:  &processor.Overview{TotalSpent: 1_234_560, HealthPercentage: health},
+		DateRange: "2026-06-01 to 2026-06-08",
+		TopSpending: []processor.TopSpendingCategory{
+			{Category: "🛒 Groceries", Spent: 412_000},
+			{Category: "Dining Out", Spent: 230_500},
+			{Category: "Kids' Activities", Spent: 99_000},
+			{Category: "Fuel", Spent: 60_000},
+		},
+	}
+}
+
+func TestRender(t *testing.T) {
+	data, err := Render("Weekly Financial Wrap", sampleAnalysis(68))
+	if err != nil {
+		t.Fatalf("Render failed: %v", err)
+	}
+
+	img, err := png.Decode(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("output is not a PNG: %v", err)
+	}
+	if b := img.Bounds(); b.Dx() != width || b.Dy() != height {
+		t.Errorf("size: got %dx%d, want %dx%d", b.Dx(), b.Dy(), width, height)
+	}
+	if r, g, b, _ := img.At(width/2, 4).RGBA(); r>>8 != uint32(good.R) || g>>8 != uint32(good.G) || b>>8 != uint32(good.B) {
+		t.Errorf("expected a green accent for a healthy budget")
+	}
+}
+
+func TestRender_OverBudgetAccent(t *testing.T) {
+	data, err := Render("Weekly Financial Wrap", sampleAnalysis(112))
+	if err != nil {
+		t.Fatalf("Render failed: %v", err)
+	}
+	img, _ := png.Decode(bytes.NewReader(data))
+	if r, _, _, _ := img.At(width/2, 4).RGBA(); r>>8 != uint32(danger.R) {
+		t.Errorf("expected a red accent when over budget")
+	}
+}
+
+func TestRender_NoOverview(t *testing.T) {
+	if _, err := Render("Weekly Financial Wrap", &processor.AnalysisResult{}); err == nil {
+		t.Error("expected an error without an overview")
+	}
+}
+
+func TestFitText(t *testing.T) {
+	if got := printable("🛒 Groceries"); got != "GROCERIES" {
+		t.Errorf("printable: got %q, want %q", got, "GROCERIES")
+	}
+	got := fitText("Kids' Activities and Sports", 2, textWidth("KIDS' ACTIV...", 2))
+	if got != "KIDS' ACTIV..." {
+		t.Errorf("fitText: got %q", got)
+	}
+}
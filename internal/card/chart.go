@@ -0,0 +1,84 @@
+package card
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+
+	"github.com/sathyabhat/ynab-weekly-wrap/internal/processor"
+)
+
+// Chart layout, in image pixels
+const (
+	chartCount    = 6
+	chartTop      = 150 // Tallest bar reaches here
+	chartBaseline = 390
+)
+
+var chartBar = color.RGBA{R: 0x4a, G: 0x90, B: 0xe2, A: 0xff}
+
+// RenderChart draws a bar chart of the period's top spending categories as
+// a PNG. When the analysis has the previous period, each bar carries a
+// marker at the previous period's spending so week-over-week changes stand
+// out.
+func RenderChart(analysis *processor.AnalysisResult) ([]byte, error) {
+	if analysis == nil || len(analysis.TopSpending) == 0 {
+		return nil, fmt.Errorf("chart needs an analysis with spending")
+	}
+
+	top := analysis.TopSpending
+	if len(top) > chartCount {
+		top = top[:chartCount]
+	}
+	var most int64
+	for _, category := range top {
+		most = max(most, category.Spent)
+		if analysis.HasPrevData {
+			most = max(most, category.PrevSpent)
+		}
+	}
+
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	draw.Draw(img, img.Bounds(), &image.Uniform{C: background}, image.Point{}, draw.Src)
+
+	drawText(img, margin, 40, "Top Spending", 3, foreground)
+	drawText(img, margin, 78, analysis.DateRange, 2, muted)
+	if analysis.HasPrevData {
+		legend := width - margin - textWidth("Previous period", 2)
+		fillRect(img, image.Rect(legend-28, 85, legend-8, 89), foreground)
+		drawText(img, legend, 80, "Previous period", 2, muted)
+	}
+
+	slot := (width - 2*margin) / len(top)
+	barWidth := min(slot*2/3, 120)
+	for i, category := range top {
+		center := margin + i*slot + slot/2
+		left, right := center-barWidth/2, center+barWidth/2
+
+		barTop := chartBaseline - scaled(category.Spent, most)
+		fillRect(img, image.Rect(left, barTop, right, chartBaseline), chartBar)
+
+		amount := analysis.Currency.Whole(category.Spent)
+		drawText(img, center-textWidth(amount, 2)/2, barTop-22, amount, 2, foreground)
+
+		if analysis.HasPrevData {
+			prev := chartBaseline - scaled(category.PrevSpent, most)
+			fillRect(img, image.Rect(left-6, prev-2, right+6, prev+2), foreground)
+		}
+
+		label := fitText(category.Category, 2, slot-8)
+		drawText(img, center-textWidth(label, 2)/2, chartBaseline+14, label, 2, foreground)
+	}
+	fillRect(img, image.Rect(margin, chartBaseline, width-margin, chartBaseline+2), track)
+
+	return encode(img, "chart")
+}
+
+// scaled returns the bar height for amount when most fills the chart
+func scaled(amount, most int64) int {
+	if most <= 0 || amount <= 0 {
+		return 0
+	}
+	return int(float64(chartBaseline-chartTop) * float64(amount) / float64(most))
+}
@@ -0,0 +1,65 @@
+package card
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/png"
+	"testing"
+
+	"github.com/sathyabhat/ynab-weekly-wrap/internal/processor"
+)
+
+func decodeChart(t *testing.T, analysis *processor.AnalysisResult) image.Image {
+	t.Helper()
+	data, err := RenderChart(analysis)
+	if err != nil {
+		t.Fatalf("RenderChart failed: %v", err)
+	}
+	img, err := png.Decode(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("output is not a PNG: %v", err)
+	}
+	return img
+}
+
+func sameColor(a, b color.Color) bool {
+	ar, ag, ab, _ := a.RGBA()
+	br, bg, bb, _ := b.RGBA()
+	return ar == br && ag == bg && ab == bb
+}
+
+func TestRenderChart(t *testing.T) {
+	img := decodeChart(t, sampleAnalysis(68))
+
+	if b := img.Bounds(); b.Dx() != width || b.Dy() != height {
+		t.Errorf("size: got %dx%d, want %dx%d", b.Dx(), b.Dy(), width, height)
+	}
+	// Four categories share the plot; the first bar is centred in the first quarter
+	center := margin + (width-2*margin)/8
+	if !sameColor(img.At(center, chartTop+2), chartBar) {
+		t.Errorf("expected the largest category to fill the chart height")
+	}
+	if !sameColor(img.At(center, chartBaseline-(chartBaseline-chartTop)/2), chartBar) {
+		t.Errorf("expected no previous period marker without previous data")
+	}
+}
+
+func TestRenderChart_PreviousPeriodMarker(t *testing.T) {
+	analysis := sampleAnalysis(68)
+	analysis.HasPrevData = true
+	analysis.TopSpending[0].PrevSpent = analysis.TopSpending[0].Spent / 2
+
+	img := decodeChart(t, analysis)
+
+	center := margin + (width-2*margin)/8
+	if !sameColor(img.At(center, chartBaseline-(chartBaseline-chartTop)/2), foreground) {
+		t.Errorf("expected a marker at half the bar's height for the previous period")
+	}
+}
+
+func TestRenderChart_NoSpending(t *testing.T) {
+	if _, err := RenderChart(&processor.AnalysisResult{}); err == nil {
+		t.Error("expected an error without spending")
+	}
+}
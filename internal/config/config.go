@@ -3,39 +3,287 @@ package config
 import (
 	"bufio"
 	"fmt"
+	"math"
 	"os"
+	"regexp"
+	"slices"
+	"sort"
 	"strconv"
 	"strings"
+	"time"
+
+	"github.com/sathyabhat/ynab-weekly-wrap/internal/i18n"
+	"github.com/sathyabhat/ynab-weekly-wrap/internal/theme"
 )
 
 type Config struct {
-	YNAB       YNABConfig     `yaml:"ynab"`
-	Telegram   TelegramConfig `yaml:"telegram"`
-	Discord    DiscordConfig  `yaml:"discord"`
-	Schedule   ScheduleConfig `yaml:"schedule"`
-	Logging    LoggingConfig  `yaml:"logging"`
+	Profile    string          `yaml:"profile"` // Name of the profile applied, if any
+	Locale     string          `yaml:"locale"`  // Language of the wrap text, e.g. de or es
+	YNAB       YNABConfig      `yaml:"ynab"`
+	Telegram   TelegramConfig  `yaml:"telegram"`
+	Discord    DiscordConfig   `yaml:"discord"`
+	Email      EmailConfig     `yaml:"email"`
+	Webhook    WebhookConfig   `yaml:"webhook"`
+	Matrix     MatrixConfig    `yaml:"matrix"`
+	Teams      TeamsConfig     `yaml:"teams"`
+	Gotify     GotifyConfig    `yaml:"gotify"`
+	WhatsApp   WhatsAppConfig  `yaml:"whatsapp"`
+	Delivery   DeliveryConfig  `yaml:"delivery"`
+	History    HistoryConfig   `yaml:"history"`
+	Archive    ArchiveConfig   `yaml:"archive"`
+	Journal    JournalConfig   `yaml:"journal"`
+	Vault      VaultConfig     `yaml:"vault"`
+	Templates  TemplateConfig  `yaml:"templates"`
+	Insights   InsightConfig   `yaml:"insights"`
+	Theme      ThemeConfig     `yaml:"theme"`
+	Notes      NotesConfig     `yaml:"notes"`
+	Trigger    TriggerConfig   `yaml:"trigger"`
+	Speech     SpeechConfig    `yaml:"speech"`
+	Schedule   ScheduleConfig  `yaml:"schedule"`
+	Logging    LoggingConfig   `yaml:"logging"`
 	Thresholds ThresholdConfig `yaml:"thresholds"`
+	Benchmarks BenchmarkConfig `yaml:"benchmarks"`
+	Alerts     AlertConfig     `yaml:"alerts"`
+	Meeting    MeetingConfig   `yaml:"meeting"`
+	Split      SplitConfig     `yaml:"split"`
+	Fiscal     FiscalConfig    `yaml:"fiscal"`
+	Categories CategoryConfig  `yaml:"categories"`
+	Accounts   AccountConfig   `yaml:"accounts"`
+	Flags      FlagConfig      `yaml:"flags"`
+	Utilities  UtilityConfig   `yaml:"utilities"`
+	Groceries  GroceryConfig   `yaml:"groceries"`
+	Actions    ActionConfig    `yaml:"actions"`
+	Savings    SavingsConfig   `yaml:"savings"`
+	Goals      GoalConfig      `yaml:"goals"`
 }
 
 type YNABConfig struct {
-	APIToken string `yaml:"api_token"`
-	BudgetID string `yaml:"budget_id"`
+	APIToken string              `yaml:"api_token"`
+	BudgetID string              `yaml:"budget_id"`
+	Partner  PartnerBudgetConfig `yaml:"partner"`
+	// Refuse every write to YNAB (approvals, categorization, budget moves),
+	// whatever else is configured
+	ReadOnly bool `yaml:"read_only"`
+}
+
+// PartnerBudgetConfig merges a second budget into the wrap, for households
+// where each partner keeps their own YNAB budget
+type PartnerBudgetConfig struct {
+	BudgetID    string            `yaml:"budget_id"`
+	APIToken    string            `yaml:"api_token"`    // Defaults to the primary token
+	CategoryMap map[string]string `yaml:"category_map"` // Partner category name -> primary category name
 }
 
 type TelegramConfig struct {
-	BotToken string `yaml:"bot_token"`
-	ChatID   int64  `yaml:"chat_id"`
-	TopicID  int    `yaml:"topic_id"` // Optional: Topic ID for topics in supergroups
+	BotToken  string         `yaml:"bot_token"`
+	ChatID    int64          `yaml:"chat_id"`
+	TopicID   int            `yaml:"topic_id"`   // Optional: Topic ID for topics in supergroups
+	Chats     []TelegramChat `yaml:"chats"`      // Optional: several chats to send to, instead of ChatID
+	ParseMode string         `yaml:"parse_mode"` // MarkdownV2 (default), HTML or the legacy Markdown
+	// Attempts per message before giving up, with exponential backoff from
+	// RetryDelay between them (rate limits wait as long as Telegram asks)
+	RetryAttempts int           `yaml:"retry_attempts"`
+	RetryDelay    time.Duration `yaml:"retry_delay"`
+	SummaryCard   bool          `yaml:"summary_card"`  // Also send the overview as an image card
+	Compact       bool          `yaml:"compact"`       // Send a short summary with buttons that expand the details
+	Chart         bool          `yaml:"chart"`         // Also send a bar chart of the top categories
+	VoiceSummary  bool          `yaml:"voice_summary"` // Also send a spoken summary as a voice message
+	AttachCSV     bool          `yaml:"attach_csv"`    // Also send the period's transactions as a CSV file
+	// Deliver without a notification sound, and pin the wrap in place of the
+	// previous one (the bot needs permission to pin in groups)
+	DisableNotification bool `yaml:"disable_notification"`
+	PinMessage          bool `yaml:"pin_message"`
+	// Receive button taps and commands as a webhook instead of polling.
+	// WebhookURL is the public HTTPS URL a reverse proxy forwards to
+	// WebhookAddr; Telegram sends WebhookSecret with every update.
+	WebhookURL    string `yaml:"webhook_url"`
+	WebhookAddr   string `yaml:"webhook_addr"`
+	WebhookSecret string `yaml:"webhook_secret"`
+	// Send Bot API requests through this proxy, e.g. socks5://host:1080,
+	// where Telegram is blocked
+	ProxyURL string `yaml:"proxy_url"`
+}
+
+// TelegramChat is one chat the wrap is sent to, optionally into a topic
+type TelegramChat struct {
+	ChatID  int64 `yaml:"chat_id"`
+	TopicID int   `yaml:"topic_id"`
+}
+
+// Targets returns every chat to send to: Chats when set, otherwise ChatID
+// and TopicID
+func (c TelegramConfig) Targets() []TelegramChat {
+	if len(c.Chats) > 0 {
+		return c.Chats
+	}
+	if c.ChatID == 0 {
+		return nil
+	}
+	return []TelegramChat{{ChatID: c.ChatID, TopicID: c.TopicID}}
 }
 
 type DiscordConfig struct {
 	WebhookURL string `yaml:"webhook_url"`
+	UseEmbeds  bool   `yaml:"use_embeds"` // Send rich embeds instead of plain text
+}
+
+type EmailConfig struct {
+	Host     string   `yaml:"host"`
+	Port     int      `yaml:"port"`
+	Username string   `yaml:"username"`
+	Password string   `yaml:"password"`
+	From     string   `yaml:"from"`
+	To       []string `yaml:"to"`
+}
+
+type WebhookConfig struct {
+	URL    string `yaml:"url"`
+	Secret string `yaml:"secret"` // Optional: signs the payload with HMAC-SHA256
+}
+
+type MatrixConfig struct {
+	HomeserverURL string `yaml:"homeserver_url"`
+	AccessToken   string `yaml:"access_token"`
+	RoomID        string `yaml:"room_id"`
+}
+
+type TeamsConfig struct {
+	WebhookURL string `yaml:"webhook_url"`
+}
+
+type GotifyConfig struct {
+	URL   string `yaml:"url"`
+	Token string `yaml:"token"` // Application token
+}
+
+type WhatsAppConfig struct {
+	PhoneNumberID string `yaml:"phone_number_id"` // Sending business phone number ID
+	Token         string `yaml:"token"`           // Cloud API access token
+	Recipient     string `yaml:"recipient"`       // Phone number to send to, in international format
+}
+
+// DeliveryConfig controls how reports are sent to the configured publishers
+type DeliveryConfig struct {
+	Timeout time.Duration `yaml:"timeout"` // Per-destination timeout
+	// Destination name, as in delivery summaries -> the currency its amounts
+	// are shown in instead of the budget's
+	Currencies map[string]DisplayCurrency `yaml:"currencies"`
+}
+
+// DisplayCurrency shows a destination's amounts in another currency,
+// converted at a fixed rate
+type DisplayCurrency struct {
+	Code string  `yaml:"code"` // ISO 4217 code, e.g. INR
+	Rate float64 `yaml:"rate"` // Units of Code per unit of the budget's currency; 0 converts nothing
+}
+
+// TemplateConfig points at text/template files that replace the built-in
+// message layouts
+type TemplateConfig struct {
+	WeeklyFile  string `yaml:"weekly_file"`
+	MonthlyFile string `yaml:"monthly_file"`
+}
+
+// InsightConfig points at the user's insight rules, which add lines of
+// commentary to the wrap when their conditions hold
+type InsightConfig struct {
+	RulesFile string `yaml:"rules_file"`
+}
+
+// ThemeConfig sets the emoji and titles of the wrap's sections
+type ThemeConfig struct {
+	Name   string            `yaml:"name"`   // default, or plain for no emoji
+	Emoji  map[string]string `yaml:"emoji"`  // Emoji name (e.g. trophy) -> emoji; empty leaves it out
+	Titles map[string]string `yaml:"titles"` // Message ID (e.g. over_budget) -> section title
+}
+
+// ArchiveConfig writes each weekly wrap's figures to versioned JSON and CSV
+// files for other tools to import
+type ArchiveConfig struct {
+	Dir string `yaml:"dir"` // Empty disables the archive
+}
+
+// VaultConfig writes each weekly and monthly wrap as a Markdown note with
+// YAML frontmatter, e.g. into an Obsidian vault
+type VaultConfig struct {
+	Dir string `yaml:"dir"` // Empty disables the vault notes
+}
+
+// JournalConfig commits each weekly and monthly wrap as a Markdown file to
+// a GitHub or Gitea repository, through the provider's contents API
+type JournalConfig struct {
+	Repo     string `yaml:"repo"` // owner/name; empty disables the journal
+	Token    string `yaml:"token"`
+	Provider string `yaml:"provider"` // github (default) or gitea
+	APIURL   string `yaml:"api_url"`  // Required for Gitea, e.g. https://git.example.com/api/v1
+	Branch   string `yaml:"branch"`   // Empty commits to the repository's default branch
+	Dir      string `yaml:"dir"`      // Folder in the repository the wraps go in
+}
+
+type HistoryConfig struct {
+	Dir string `yaml:"dir"` // Where weekly snapshots are stored; empty disables history
+
+	// List payees paid this week that don't appear in the snapshots of the
+	// previous NewPayeeMonths months
+	NewPayees      bool `yaml:"new_payees"`
+	NewPayeeMonths int  `yaml:"new_payee_months"`
+
+	// List outflows more than AnomalyStdDevs standard deviations above the
+	// mean of their category's outflows over the previous AnomalyMonths months
+	Anomalies      bool    `yaml:"anomalies"`
+	AnomalyStdDevs float64 `yaml:"anomaly_std_devs"`
+	AnomalyMonths  int     `yaml:"anomaly_months"`
+
+	// Show in the monthly wrap which days of the week the spending, and the
+	// spending in overspent categories, clusters on over PatternMonths months
+	Patterns      bool `yaml:"patterns"`
+	PatternMonths int  `yaml:"pattern_months"`
+}
+
+// NotesConfig holds notes shown under categories in every wrap until cleared
+type NotesConfig struct {
+	File       string            `yaml:"file"`       // Where notes set with /note are saved; empty disables /note
+	Categories map[string]string `yaml:"categories"` // Category name -> note
+}
+
+// TriggerConfig enables an HTTP endpoint that runs a wrap on demand, e.g.
+// from iOS Shortcuts or IFTTT
+type TriggerConfig struct {
+	Addr  string `yaml:"addr"`  // Address to listen on (e.g. ":8080"); empty disables the endpoint
+	Token string `yaml:"token"` // Secret callers must present
+}
+
+// SpeechConfig chooses how spoken summaries are generated
+type SpeechConfig struct {
+	Provider string `yaml:"provider"` // espeak (default) or command
+	Voice    string `yaml:"voice"`    // espeak voice, e.g. "en-us"
+	// Shell command for the command provider: it reads the text on stdin and
+	// writes OGG/Opus audio to stdout
+	Command string `yaml:"command"`
 }
 
 type ScheduleConfig struct {
-	Cron        string `yaml:"cron"`
-	MonthlyCron string `yaml:"monthly_cron"`
-	Timezone    string `yaml:"timezone"`
+	Cron            string        `yaml:"cron"`
+	MonthlyCron     string        `yaml:"monthly_cron"`
+	DailyCron       string        `yaml:"daily_cron"`       // Schedule for the daily digest; empty disables it
+	Timezone        string        `yaml:"timezone"`         // Timezone for all jobs; empty uses the local timezone
+	MonthlyTimezone string        `yaml:"monthly_timezone"` // Timezone for the monthly wrap (default: Timezone)
+	RetryAttempts   int           `yaml:"retry_attempts"`   // Attempts for a scheduled job before giving up and alerting
+	RetryDelay      time.Duration `yaml:"retry_delay"`      // Delay before the first retry, increasing with each attempt
+	OnceFile        string        `yaml:"once_file"`        // Where wraps queued with schedule-once are saved; empty disables them
+	PauseFile       string        `yaml:"pause_file"`       // Where a pause of the scheduled sends is saved; empty disables pausing
+}
+
+// Location returns the timezone jobs run in, the local one unless set
+func (c ScheduleConfig) Location() *time.Location {
+	if c.Timezone == "" {
+		return time.Local
+	}
+	location, err := time.LoadLocation(c.Timezone)
+	if err != nil {
+		return time.Local
+	}
+	return location
 }
 
 type LoggingConfig struct {
@@ -47,6 +295,254 @@ type ThresholdConfig struct {
 	AtRiskPercent      int `yaml:"at_risk_percent"`
 	OverBudgetPercent  int `yaml:"over_budget_percent"`
 	TopCategoriesCount int `yaml:"top_categories_count"`
+	WinsCount          int `yaml:"wins_count"`       // Categories with the most left to celebrate (0 = none)
+	VelocityDays       int `yaml:"velocity_days"`    // Early-month window in days for velocity alerts (0 = disabled)
+	VelocityPercent    int `yaml:"velocity_percent"` // Percent of monthly budget spent within the window that triggers an alert
+	// Amount over budget, in milliunits, that counts as severe whatever the
+	// budget (0 = tier by percentage alone)
+	SevereOverAmount int64 `yaml:"severe_over_amount"`
+}
+
+// AlertConfig controls critical alerts that are checked on their own schedule
+// and sent immediately instead of waiting for the weekly wrap
+type AlertConfig struct {
+	Cron                  string `yaml:"cron"`
+	Timezone              string `yaml:"timezone"` // Timezone for alert checks (default: the schedule's timezone)
+	OverdraftEnabled      bool   `yaml:"overdraft_enabled"`
+	OverdraftLookbackDays int    `yaml:"overdraft_lookback_days"` // Days of history used for the average daily spend
+	OverdraftHorizonDays  int    `yaml:"overdraft_horizon_days"`  // How far ahead to project when no income is scheduled
+	ImportEnabled         bool   `yaml:"import_enabled"`
+	ImportLookbackDays    int    `yaml:"import_lookback_days"` // Days of history used to tell which accounts are usually active
+	ImportSilentDays      int    `yaml:"import_silent_days"`   // Days without transactions before an active account is flagged
+	FraudEnabled          bool   `yaml:"fraud_enabled"`
+	FraudWindowDays       int    `yaml:"fraud_window_days"`   // Days of recent transactions checked for fraud patterns
+	FraudBaselineDays     int    `yaml:"fraud_baseline_days"` // Days before the window whose payees count as known
+	FraudMicroCharge      int64  `yaml:"fraud_micro_charge"`  // Largest charge, in milliunits, counted as a micro-charge
+}
+
+// Enabled reports whether any alert check is turned on
+func (c AlertConfig) Enabled() bool {
+	return c.OverdraftEnabled || c.ImportEnabled || c.FraudEnabled
+}
+
+// MeetingConfig sends an agenda of everything needing a decision ahead of a
+// weekly budget meeting
+type MeetingConfig struct {
+	Time      string        `yaml:"time"`       // Weekday and time of the meeting, e.g. "sunday 19:00"; empty disables the agenda
+	Lead      time.Duration `yaml:"lead"`       // How long before the meeting the agenda is sent
+	LargeBill int64         `yaml:"large_bill"` // Smallest upcoming bill, in milliunits, put on the agenda
+}
+
+// Cron returns the schedule the agenda is sent on, Lead before the meeting,
+// or "" when no meeting is set
+func (c MeetingConfig) Cron() string {
+	day, hour, minute, err := parseMeetingTime(c.Time)
+	if err != nil {
+		return ""
+	}
+	const week = 7 * 24 * 60
+	at := ((int(day)*24+hour)*60 + minute - int(c.Lead.Minutes())) % week
+	if at < 0 {
+		at += week
+	}
+	return fmt.Sprintf("%d %d * * %d", at%60, at/60%24, at/(24*60))
+}
+
+// Next returns the first meeting after now, in now's timezone
+func (c MeetingConfig) Next(now time.Time) time.Time {
+	day, hour, minute, err := parseMeetingTime(c.Time)
+	if err != nil {
+		return time.Time{}
+	}
+	ahead := (int(day) - int(now.Weekday()) + 7) % 7
+	next := time.Date(now.Year(), now.Month(), now.Day()+ahead, hour, minute, 0, 0, now.Location())
+	if !next.After(now) {
+		next = next.AddDate(0, 0, 7)
+	}
+	return next
+}
+
+// parseMeetingTime reads a weekday and 24-hour time such as "sunday 19:00"
+func parseMeetingTime(text string) (day time.Weekday, hour, minute int, err error) {
+	dayStr, clock, ok := strings.Cut(strings.TrimSpace(strings.ToLower(text)), " ")
+	if !ok {
+		return 0, 0, 0, fmt.Errorf("expected a weekday and time such as \"sunday 19:00\"")
+	}
+	found := false
+	for d := time.Sunday; d <= time.Saturday; d++ {
+		name := strings.ToLower(d.String())
+		if dayStr == name || dayStr == name[:3] {
+			day, found = d, true
+		}
+	}
+	if !found {
+		return 0, 0, 0, fmt.Errorf("unknown weekday %q", dayStr)
+	}
+	at, err := time.Parse("15:04", strings.TrimSpace(clock))
+	if err != nil {
+		return 0, 0, 0, fmt.Errorf("invalid time %q, expected e.g. 19:00", clock)
+	}
+	return day, at.Hour(), at.Minute(), nil
+}
+
+// SplitConfig tags categories (or whole category groups) as need, want or
+// savings so the wrap can summarize the week's split against a target
+type SplitConfig struct {
+	Tags          map[string]string `yaml:"tags"` // Category or group name -> need/want/savings
+	TargetNeeds   int               `yaml:"target_needs"`
+	TargetWants   int               `yaml:"target_wants"`
+	TargetSavings int               `yaml:"target_savings"`
+}
+
+// FiscalConfig aligns the budget month with a pay cycle, e.g. a month that
+// runs from the 25th to the 24th
+type FiscalConfig struct {
+	MonthStartDay int `yaml:"month_start_day"` // Day of the month the budget month starts on (1-28)
+}
+
+// CategoryConfig keeps comparisons continuous across category renames and
+// merges in YNAB
+type CategoryConfig struct {
+	Aliases map[string]string `yaml:"aliases"` // Old category name -> current name
+	Lumpy   []string          `yaml:"lumpy"`   // One-off/annual categories or groups, left out of percentages
+	Fixed   []string          `yaml:"fixed"`   // Fixed-cost categories or groups, left out of safe-to-spend
+	// YNAB goal types (e.g. MF, NEED) whose categories count as fixed costs
+	FixedGoalTypes []string `yaml:"fixed_goal_types"`
+	// The household's overspending playbook: overspent category -> the
+	// category planned to cover it, e.g. Car Repairs -> Vacation Fund.
+	// Offsets can have offsets of their own, drawn on in turn.
+	Offsets map[string]string `yaml:"offsets"`
+	// Add a tidy-up section to the monthly wrap: unused categories, hidden
+	// ones holding money and budgets that chronically miss
+	Hygiene bool `yaml:"hygiene"`
+	// Fetch only the categories named elsewhere in the configuration in
+	// quick paths such as the daily digest, for budgets with hundreds of
+	// categories. Wraps still fetch every category.
+	PartialFetch bool `yaml:"partial_fetch"`
+	// Ignore budgets and report spending only, for households that track
+	// in YNAB without budgeting categories
+	SpendingOnly bool `yaml:"spending_only"`
+	// Restrict the wraps to these categories or category groups, leaving
+	// the rest of the budget out of the figures
+	Focus []string `yaml:"focus"`
+	// Add a week-ahead section to the weekly wrap: categories close to
+	// their budget to watch and adjustments for the ones already over
+	WeekAhead bool `yaml:"week_ahead"`
+}
+
+// FlagColors are the colors a YNAB transaction can be flagged
+var FlagColors = []string{"red", "orange", "yellow", "green", "blue", "purple"}
+
+// FlagConfig uses the flags on YNAB transactions, e.g. purple for purchases
+// to be reimbursed
+type FlagConfig struct {
+	// Flag colors whose transactions are left out of the spending, such as
+	// purchases someone else pays back
+	Exclude []string `yaml:"exclude"`
+	// Flag colors whose transactions are listed in a section of their own
+	Highlight []string `yaml:"highlight"`
+}
+
+// AccountConfig controls what the weekly wrap reports about accounts
+type AccountConfig struct {
+	// List each on-budget account's cleared and uncleared balance
+	Balances bool `yaml:"balances"`
+	// Show the total of all accounts, tracking accounts included, with its
+	// change since last week and the start of the month from stored history
+	NetWorth bool `yaml:"net_worth"`
+	// Account name -> group, e.g. a joint account and each partner's own,
+	// to break the week's spending down by group
+	Groups map[string]string `yaml:"groups"`
+	// Sum up each credit card's week against its Credit Card Payments
+	// category, to see whether the card spending is covered
+	Cards bool `yaml:"cards"`
+	// Count the week's unapproved and uncleared transactions under Needs
+	// Attention, since they skew the category numbers until reconciled
+	Pending bool `yaml:"pending"`
+	// List each of them too, not just the counts
+	PendingList bool `yaml:"pending_list"`
+	// Count transfers to tracking accounts, which YNAB books against a
+	// category (e.g. a retirement contribution), as the week's spending
+	IncludeTransfers bool `yaml:"include_transfers"`
+}
+
+// UtilityConfig flags utility bills well above the payee's typical bill
+type UtilityConfig struct {
+	Bills        []string `yaml:"bills"`         // Utility categories or payees; empty disables the check
+	SpikePercent int      `yaml:"spike_percent"` // How far above the average of the last 12 bills counts as a spike
+}
+
+// GroceryConfig tracks the average grocery trip over time, to tell rising
+// prices from shopping more often
+type GroceryConfig struct {
+	Category string `yaml:"category"` // Empty disables the trip trend
+	Months   int    `yaml:"months"`   // How far back the average trip is compared with
+}
+
+// ActionConfig suggests what to do in YNAB each week, such as covering
+// overspending, and follows up on last week's suggestions from history
+type ActionConfig struct {
+	Enabled bool `yaml:"enabled"`
+}
+
+// SavingsConfig shows how much of the income is being kept, over the week
+// and the budget month so far
+type SavingsConfig struct {
+	Rate   bool `yaml:"rate"`
+	Target int  `yaml:"target"` // Percent of income to save, 0-100; 0 shows no target
+}
+
+// GoalConfig reports progress towards the categories' YNAB goals in the
+// weekly and monthly wraps
+type GoalConfig struct {
+	Progress   bool     `yaml:"progress"`
+	Categories []string `yaml:"categories"` // Categories or groups to report on; empty reports every goal
+}
+
+// ReferencedCategories returns the category and group names the
+// configuration refers to, sorted, for fetching only those categories
+func (c *Config) ReferencedCategories() []string {
+	seen := make(map[string]bool)
+	add := func(name string) {
+		if name != "" {
+			seen[name] = true
+		}
+	}
+	for name := range c.Benchmarks.Weekly {
+		add(name)
+	}
+	for name := range c.Split.Tags {
+		add(name)
+	}
+	for name := range c.Notes.Categories {
+		add(name)
+	}
+	for _, name := range c.Categories.Aliases {
+		add(name)
+	}
+	for _, name := range append(append([]string{}, c.Categories.Lumpy...), c.Categories.Fixed...) {
+		add(name)
+	}
+	for _, name := range append(append([]string{}, c.Goals.Categories...), c.Categories.Focus...) {
+		add(name)
+	}
+	for category, offset := range c.Categories.Offsets {
+		add(category)
+		add(offset)
+	}
+
+	names := make([]string, 0, len(seen))
+	for name := range seen {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// BenchmarkConfig holds personal spending targets that are independent of the
+// YNAB budget structure (e.g. "groceries $130/week").
+type BenchmarkConfig struct {
+	Weekly map[string]int64 `yaml:"weekly"` // Category name -> weekly target in milliunits
 }
 
 // loadEnvFile loads environment variables from a .env file
@@ -89,17 +585,62 @@ func loadEnvFile(filename string) error {
 }
 
 func LoadConfig() (*Config, error) {
+	return LoadProfile("")
+}
+
+// LoadProfile loads the configuration with a named profile from the profiles
+// file applied over the environment. An empty name uses CONFIG_PROFILE, and
+// loads no profile when that isn't set either.
+func LoadProfile(profile string) (*Config, error) {
 	// Load .env file if it exists (optional)
 	_ = loadEnvFile(".env")
 	_ = loadEnvFile("/app/.env")
 	config := &Config{}
 
+	if profile == "" {
+		profile = os.Getenv("CONFIG_PROFILE")
+	}
+	if profile != "" {
+		profilesPath := os.Getenv("PROFILES_FILE")
+		if profilesPath == "" {
+			profilesPath = defaultProfilesFile
+		}
+		if err := applyProfile(profilesPath, profile); err != nil {
+			return nil, err
+		}
+		config.Profile = profile
+	}
+
 	// Load from environment variables
 	config.YNAB.APIToken = os.Getenv("YNAB_API_TOKEN")
 	config.YNAB.BudgetID = os.Getenv("YNAB_BUDGET_ID")
+	config.YNAB.Partner.BudgetID = os.Getenv("YNAB_PARTNER_BUDGET_ID")
+	config.YNAB.Partner.APIToken = os.Getenv("YNAB_PARTNER_API_TOKEN")
+	if readOnlyStr := os.Getenv("READ_ONLY"); readOnlyStr != "" {
+		// A safety switch, so a value that doesn't parse is an error rather
+		// than leaving writes on
+		enabled, err := strconv.ParseBool(readOnlyStr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid READ_ONLY: %w", err)
+		}
+		config.YNAB.ReadOnly = enabled
+	}
+	if mapStr := os.Getenv("PARTNER_CATEGORY_MAP"); mapStr != "" {
+		categoryMap, err := parseStringMap(mapStr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid PARTNER_CATEGORY_MAP: %w", err)
+		}
+		config.YNAB.Partner.CategoryMap = categoryMap
+	}
 
 	config.Telegram.BotToken = os.Getenv("TELEGRAM_BOT_TOKEN")
-	if chatIDStr := os.Getenv("TELEGRAM_CHAT_ID"); chatIDStr != "" {
+	if chatIDStr := os.Getenv("TELEGRAM_CHAT_ID"); strings.ContainsAny(chatIDStr, ",:") {
+		chats, err := parseTelegramChats(chatIDStr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid TELEGRAM_CHAT_ID: %w", err)
+		}
+		config.Telegram.Chats = chats
+	} else if chatIDStr != "" {
 		if chatID, err := strconv.ParseInt(chatIDStr, 10, 64); err == nil {
 			config.Telegram.ChatID = chatID
 		}
@@ -109,18 +650,540 @@ func LoadConfig() (*Config, error) {
 			config.Telegram.TopicID = topicID
 		}
 	}
+	switch parseMode := os.Getenv("TELEGRAM_PARSE_MODE"); parseMode {
+	case "", "MarkdownV2":
+		config.Telegram.ParseMode = "MarkdownV2"
+	case "HTML", "Markdown":
+		config.Telegram.ParseMode = parseMode
+	default:
+		return nil, fmt.Errorf("invalid TELEGRAM_PARSE_MODE %q: must be MarkdownV2, HTML or Markdown", parseMode)
+	}
+	if attemptsStr := os.Getenv("TELEGRAM_RETRY_ATTEMPTS"); attemptsStr != "" {
+		if attempts, err := strconv.Atoi(attemptsStr); err == nil {
+			config.Telegram.RetryAttempts = attempts
+		}
+	}
+	if delayStr := os.Getenv("TELEGRAM_RETRY_DELAY"); delayStr != "" {
+		delay, err := time.ParseDuration(delayStr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid TELEGRAM_RETRY_DELAY: %w", err)
+		}
+		config.Telegram.RetryDelay = delay
+	}
+	if cardStr := os.Getenv("TELEGRAM_SUMMARY_CARD"); cardStr != "" {
+		if summaryCard, err := strconv.ParseBool(cardStr); err == nil {
+			config.Telegram.SummaryCard = summaryCard
+		}
+	}
+	if compactStr := os.Getenv("TELEGRAM_COMPACT"); compactStr != "" {
+		if compact, err := strconv.ParseBool(compactStr); err == nil {
+			config.Telegram.Compact = compact
+		}
+	}
+	if chartStr := os.Getenv("TELEGRAM_CHART"); chartStr != "" {
+		if chart, err := strconv.ParseBool(chartStr); err == nil {
+			config.Telegram.Chart = chart
+		}
+	}
+	if voiceStr := os.Getenv("TELEGRAM_VOICE_SUMMARY"); voiceStr != "" {
+		if voice, err := strconv.ParseBool(voiceStr); err == nil {
+			config.Telegram.VoiceSummary = voice
+		}
+	}
+	if csvStr := os.Getenv("TELEGRAM_ATTACH_CSV"); csvStr != "" {
+		if attachCSV, err := strconv.ParseBool(csvStr); err == nil {
+			config.Telegram.AttachCSV = attachCSV
+		}
+	}
+	if silentStr := os.Getenv("TELEGRAM_DISABLE_NOTIFICATION"); silentStr != "" {
+		if silent, err := strconv.ParseBool(silentStr); err == nil {
+			config.Telegram.DisableNotification = silent
+		}
+	}
+	if pinStr := os.Getenv("TELEGRAM_PIN_MESSAGE"); pinStr != "" {
+		if pin, err := strconv.ParseBool(pinStr); err == nil {
+			config.Telegram.PinMessage = pin
+		}
+	}
+	config.Telegram.WebhookURL = os.Getenv("TELEGRAM_WEBHOOK_URL")
+	config.Telegram.WebhookAddr = os.Getenv("TELEGRAM_WEBHOOK_ADDR")
+	config.Telegram.WebhookSecret = os.Getenv("TELEGRAM_WEBHOOK_SECRET")
+	config.Telegram.ProxyURL = os.Getenv("TELEGRAM_PROXY_URL")
 
 	config.Discord.WebhookURL = os.Getenv("DISCORD_WEBHOOK_URL")
+	if useEmbedsStr := os.Getenv("DISCORD_USE_EMBEDS"); useEmbedsStr != "" {
+		if useEmbeds, err := strconv.ParseBool(useEmbedsStr); err == nil {
+			config.Discord.UseEmbeds = useEmbeds
+		}
+	}
+
+	config.Email.Host = os.Getenv("SMTP_HOST")
+	if portStr := os.Getenv("SMTP_PORT"); portStr != "" {
+		if port, err := strconv.Atoi(portStr); err == nil {
+			config.Email.Port = port
+		}
+	}
+	config.Email.Username = os.Getenv("SMTP_USERNAME")
+	config.Email.Password = os.Getenv("SMTP_PASSWORD")
+	config.Email.From = os.Getenv("EMAIL_FROM")
+	config.Email.To = parseList(os.Getenv("EMAIL_TO"))
+
+	config.Webhook.URL = os.Getenv("WEBHOOK_URL")
+	config.Webhook.Secret = os.Getenv("WEBHOOK_SECRET")
+
+	config.Matrix.HomeserverURL = os.Getenv("MATRIX_HOMESERVER_URL")
+	config.Matrix.AccessToken = os.Getenv("MATRIX_ACCESS_TOKEN")
+	config.Matrix.RoomID = os.Getenv("MATRIX_ROOM_ID")
+
+	config.Teams.WebhookURL = os.Getenv("TEAMS_WEBHOOK_URL")
+
+	config.Gotify.URL = os.Getenv("GOTIFY_URL")
+	config.Gotify.Token = os.Getenv("GOTIFY_TOKEN")
+
+	config.WhatsApp.PhoneNumberID = os.Getenv("WHATSAPP_PHONE_NUMBER_ID")
+	config.WhatsApp.Token = os.Getenv("WHATSAPP_TOKEN")
+	config.WhatsApp.Recipient = os.Getenv("WHATSAPP_RECIPIENT")
+
+	if timeoutStr := os.Getenv("DELIVERY_TIMEOUT"); timeoutStr != "" {
+		timeout, err := time.ParseDuration(timeoutStr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid DELIVERY_TIMEOUT: %w", err)
+		}
+		config.Delivery.Timeout = timeout
+	}
+	if currencyStr := os.Getenv("DISPLAY_CURRENCY"); currencyStr != "" {
+		currencies, err := parseDisplayCurrencies(currencyStr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid DISPLAY_CURRENCY: %w", err)
+		}
+		config.Delivery.Currencies = currencies
+	}
+
+	config.History.Dir = os.Getenv("HISTORY_DIR")
+	config.Archive.Dir = os.Getenv("ARCHIVE_DIR")
+	config.Journal.Repo = os.Getenv("JOURNAL_REPO")
+	config.Journal.Token = os.Getenv("JOURNAL_TOKEN")
+	config.Journal.Provider = strings.ToLower(os.Getenv("JOURNAL_PROVIDER"))
+	config.Journal.APIURL = os.Getenv("JOURNAL_API_URL")
+	config.Journal.Branch = os.Getenv("JOURNAL_BRANCH")
+	config.Journal.Dir = os.Getenv("JOURNAL_DIR")
+	config.Vault.Dir = os.Getenv("VAULT_DIR")
+	config.Templates.WeeklyFile = os.Getenv("WEEKLY_TEMPLATE_FILE")
+	config.Templates.MonthlyFile = os.Getenv("MONTHLY_TEMPLATE_FILE")
+	config.Insights.RulesFile = os.Getenv("INSIGHT_RULES_FILE")
+	if excludeStr := os.Getenv("EXCLUDE_FLAGS"); excludeStr != "" {
+		colors, err := parseFlagColors(excludeStr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid EXCLUDE_FLAGS: %w", err)
+		}
+		config.Flags.Exclude = colors
+	}
+	if highlightStr := os.Getenv("HIGHLIGHT_FLAGS"); highlightStr != "" {
+		colors, err := parseFlagColors(highlightStr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid HIGHLIGHT_FLAGS: %w", err)
+		}
+		config.Flags.Highlight = colors
+	}
+	config.Theme.Name = os.Getenv("THEME")
+	if emojiStr := os.Getenv("THEME_EMOJI"); emojiStr != "" {
+		emoji, err := parseStringMap(emojiStr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid THEME_EMOJI: %w", err)
+		}
+		config.Theme.Emoji = emoji
+	}
+	if _, err := theme.New(config.Theme.Name, config.Theme.Emoji); err != nil {
+		return nil, fmt.Errorf("invalid THEME or THEME_EMOJI: %w", err)
+	}
+	if titlesStr := os.Getenv("SECTION_TITLES"); titlesStr != "" {
+		titles, err := parseStringMap(titlesStr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid SECTION_TITLES: %w", err)
+		}
+		for id := range titles {
+			if !i18n.HasMessage(id) {
+				return nil, fmt.Errorf("invalid SECTION_TITLES: unknown section %q", id)
+			}
+		}
+		config.Theme.Titles = titles
+	}
+	if newPayeesStr := os.Getenv("NEW_PAYEES"); newPayeesStr != "" {
+		if enabled, err := strconv.ParseBool(newPayeesStr); err == nil {
+			config.History.NewPayees = enabled
+		}
+	}
+	if monthsStr := os.Getenv("NEW_PAYEE_MONTHS"); monthsStr != "" {
+		if months, err := strconv.Atoi(monthsStr); err == nil {
+			config.History.NewPayeeMonths = months
+		}
+	}
+	if anomaliesStr := os.Getenv("ANOMALIES"); anomaliesStr != "" {
+		if enabled, err := strconv.ParseBool(anomaliesStr); err == nil {
+			config.History.Anomalies = enabled
+		}
+	}
+	if stdDevsStr := os.Getenv("ANOMALY_STD_DEVS"); stdDevsStr != "" {
+		stdDevs, err := strconv.ParseFloat(stdDevsStr, 64)
+		if err != nil || stdDevs <= 0 {
+			return nil, fmt.Errorf("invalid ANOMALY_STD_DEVS %q: must be a positive number", stdDevsStr)
+		}
+		config.History.AnomalyStdDevs = stdDevs
+	}
+	if monthsStr := os.Getenv("ANOMALY_MONTHS"); monthsStr != "" {
+		if months, err := strconv.Atoi(monthsStr); err == nil {
+			config.History.AnomalyMonths = months
+		}
+	}
+	if patternsStr := os.Getenv("SPENDING_PATTERNS"); patternsStr != "" {
+		if enabled, err := strconv.ParseBool(patternsStr); err == nil {
+			config.History.Patterns = enabled
+		}
+	}
+	if monthsStr := os.Getenv("SPENDING_PATTERN_MONTHS"); monthsStr != "" {
+		months, err := strconv.Atoi(monthsStr)
+		if err != nil || months < 1 || months > 24 {
+			return nil, fmt.Errorf("invalid SPENDING_PATTERN_MONTHS %q: must be between 1 and 24", monthsStr)
+		}
+		config.History.PatternMonths = months
+	}
+
+	config.Notes.File = os.Getenv("NOTES_FILE")
+	if notesStr := os.Getenv("CATEGORY_NOTES"); notesStr != "" {
+		categoryNotes, err := parseNotes(notesStr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid CATEGORY_NOTES: %w", err)
+		}
+		config.Notes.Categories = categoryNotes
+	}
+
+	config.Trigger.Addr = os.Getenv("TRIGGER_ADDR")
+	config.Trigger.Token = os.Getenv("TRIGGER_TOKEN")
+
+	switch provider := os.Getenv("SPEECH_PROVIDER"); provider {
+	case "", "espeak":
+		config.Speech.Provider = "espeak"
+	case "command":
+		config.Speech.Provider = provider
+	default:
+		return nil, fmt.Errorf("invalid SPEECH_PROVIDER %q: must be espeak or command", provider)
+	}
+	config.Speech.Voice = os.Getenv("SPEECH_VOICE")
+	config.Speech.Command = os.Getenv("SPEECH_COMMAND")
 
 	config.Schedule.Cron = os.Getenv("SCHEDULE_CRON")
 	config.Schedule.MonthlyCron = os.Getenv("MONTHLY_SCHEDULE_CRON")
+	config.Schedule.DailyCron = os.Getenv("DAILY_SCHEDULE_CRON")
+	config.Schedule.Timezone = os.Getenv("SCHEDULE_TIMEZONE")
+	config.Schedule.OnceFile = os.Getenv("SCHEDULE_ONCE_FILE")
+	config.Schedule.PauseFile = os.Getenv("SCHEDULE_PAUSE_FILE")
+	config.Schedule.MonthlyTimezone = os.Getenv("MONTHLY_SCHEDULE_TIMEZONE")
+	config.Alerts.Timezone = os.Getenv("ALERT_TIMEZONE")
+	for _, name := range []string{"SCHEDULE_TIMEZONE", "MONTHLY_SCHEDULE_TIMEZONE", "ALERT_TIMEZONE"} {
+		if tz := os.Getenv(name); tz != "" {
+			if _, err := time.LoadLocation(tz); err != nil {
+				return nil, fmt.Errorf("invalid %s %q: must be an IANA timezone such as Pacific/Auckland", name, tz)
+			}
+		}
+	}
+	if attemptsStr := os.Getenv("JOB_RETRY_ATTEMPTS"); attemptsStr != "" {
+		if attempts, err := strconv.Atoi(attemptsStr); err == nil {
+			config.Schedule.RetryAttempts = attempts
+		}
+	}
+	if delayStr := os.Getenv("JOB_RETRY_DELAY"); delayStr != "" {
+		delay, err := time.ParseDuration(delayStr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid JOB_RETRY_DELAY: %w", err)
+		}
+		config.Schedule.RetryDelay = delay
+	}
 	config.Logging.Level = os.Getenv("LOG_LEVEL")
+	if locale := os.Getenv("LOCALE"); locale != "" {
+		if _, err := i18n.New(locale); err != nil {
+			return nil, fmt.Errorf("invalid LOCALE: %w", err)
+		}
+		config.Locale = locale
+	}
 	if topCategoriesStr := os.Getenv("TOP_CATEGORIES_COUNT"); topCategoriesStr != "" {
 		if count, err := strconv.Atoi(topCategoriesStr); err == nil {
 			config.Thresholds.TopCategoriesCount = count
 		}
 	}
 
+	config.Thresholds.WinsCount = 3
+	if winsStr := os.Getenv("WINS_COUNT"); winsStr != "" {
+		if count, err := strconv.Atoi(winsStr); err == nil {
+			config.Thresholds.WinsCount = count
+		}
+	}
+	if atRiskStr := os.Getenv("AT_RISK_PERCENT"); atRiskStr != "" {
+		if percent, err := strconv.Atoi(atRiskStr); err == nil {
+			config.Thresholds.AtRiskPercent = percent
+		}
+	}
+	if overBudgetStr := os.Getenv("OVER_BUDGET_PERCENT"); overBudgetStr != "" {
+		if percent, err := strconv.Atoi(overBudgetStr); err == nil {
+			config.Thresholds.OverBudgetPercent = percent
+		}
+	}
+
+	config.Thresholds.VelocityDays = 10
+	if velocityDaysStr := os.Getenv("VELOCITY_DAYS"); velocityDaysStr != "" {
+		if days, err := strconv.Atoi(velocityDaysStr); err == nil {
+			config.Thresholds.VelocityDays = days
+		}
+	}
+	if velocityPercentStr := os.Getenv("VELOCITY_PERCENT"); velocityPercentStr != "" {
+		if percent, err := strconv.Atoi(velocityPercentStr); err == nil {
+			config.Thresholds.VelocityPercent = percent
+		}
+	}
+	if severeStr := os.Getenv("SEVERE_OVER_AMOUNT"); severeStr != "" {
+		amount, err := strconv.ParseFloat(severeStr, 64)
+		if err != nil || amount <= 0 {
+			return nil, fmt.Errorf("invalid SEVERE_OVER_AMOUNT %q: must be a positive amount", severeStr)
+		}
+		config.Thresholds.SevereOverAmount = int64(math.Round(amount * 1000))
+	}
+
+	config.Alerts.Cron = os.Getenv("ALERT_CRON")
+	if overdraftStr := os.Getenv("OVERDRAFT_ALERTS"); overdraftStr != "" {
+		if enabled, err := strconv.ParseBool(overdraftStr); err == nil {
+			config.Alerts.OverdraftEnabled = enabled
+		}
+	}
+	if lookbackStr := os.Getenv("OVERDRAFT_LOOKBACK_DAYS"); lookbackStr != "" {
+		if days, err := strconv.Atoi(lookbackStr); err == nil {
+			config.Alerts.OverdraftLookbackDays = days
+		}
+	}
+	if horizonStr := os.Getenv("OVERDRAFT_HORIZON_DAYS"); horizonStr != "" {
+		if days, err := strconv.Atoi(horizonStr); err == nil {
+			config.Alerts.OverdraftHorizonDays = days
+		}
+	}
+	if importStr := os.Getenv("IMPORT_ALERTS"); importStr != "" {
+		if enabled, err := strconv.ParseBool(importStr); err == nil {
+			config.Alerts.ImportEnabled = enabled
+		}
+	}
+	if lookbackStr := os.Getenv("IMPORT_LOOKBACK_DAYS"); lookbackStr != "" {
+		if days, err := strconv.Atoi(lookbackStr); err == nil {
+			config.Alerts.ImportLookbackDays = days
+		}
+	}
+	if silentStr := os.Getenv("IMPORT_SILENT_DAYS"); silentStr != "" {
+		if days, err := strconv.Atoi(silentStr); err == nil {
+			config.Alerts.ImportSilentDays = days
+		}
+	}
+	if fraudStr := os.Getenv("FRAUD_ALERTS"); fraudStr != "" {
+		if enabled, err := strconv.ParseBool(fraudStr); err == nil {
+			config.Alerts.FraudEnabled = enabled
+		}
+	}
+	if windowStr := os.Getenv("FRAUD_WINDOW_DAYS"); windowStr != "" {
+		if days, err := strconv.Atoi(windowStr); err == nil {
+			config.Alerts.FraudWindowDays = days
+		}
+	}
+	if baselineStr := os.Getenv("FRAUD_BASELINE_DAYS"); baselineStr != "" {
+		if days, err := strconv.Atoi(baselineStr); err == nil {
+			config.Alerts.FraudBaselineDays = days
+		}
+	}
+	if microStr := os.Getenv("FRAUD_MICRO_CHARGE"); microStr != "" {
+		amount, err := strconv.ParseFloat(microStr, 64)
+		if err != nil || amount <= 0 {
+			return nil, fmt.Errorf("invalid FRAUD_MICRO_CHARGE %q: must be a positive amount", microStr)
+		}
+		config.Alerts.FraudMicroCharge = int64(math.Round(amount * 1000))
+	}
+
+	if meetingStr := os.Getenv("MEETING_TIME"); meetingStr != "" {
+		if _, _, _, err := parseMeetingTime(meetingStr); err != nil {
+			return nil, fmt.Errorf("invalid MEETING_TIME %q: %w", meetingStr, err)
+		}
+		config.Meeting.Time = meetingStr
+	}
+	if leadStr := os.Getenv("MEETING_LEAD_TIME"); leadStr != "" {
+		lead, err := time.ParseDuration(leadStr)
+		if err != nil || lead < 0 {
+			return nil, fmt.Errorf("invalid MEETING_LEAD_TIME %q: must be a duration such as 2h", leadStr)
+		}
+		config.Meeting.Lead = lead
+	} else {
+		config.Meeting.Lead = 2 * time.Hour
+	}
+	if billStr := os.Getenv("MEETING_LARGE_BILL"); billStr != "" {
+		amount, err := strconv.ParseFloat(billStr, 64)
+		if err != nil || amount < 0 {
+			return nil, fmt.Errorf("invalid MEETING_LARGE_BILL %q: must be an amount", billStr)
+		}
+		config.Meeting.LargeBill = int64(math.Round(amount * 1000))
+	} else {
+		config.Meeting.LargeBill = 100_000
+	}
+
+	if benchmarksStr := os.Getenv("WEEKLY_BENCHMARKS"); benchmarksStr != "" {
+		benchmarks, err := parseAmountMap(benchmarksStr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid WEEKLY_BENCHMARKS: %w", err)
+		}
+		config.Benchmarks.Weekly = benchmarks
+	}
+
+	if tagsStr := os.Getenv("CATEGORY_TAGS"); tagsStr != "" {
+		tags, err := parseSplitTags(tagsStr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid CATEGORY_TAGS: %w", err)
+		}
+		config.Split.Tags = tags
+	}
+	if targetStr := os.Getenv("SPLIT_TARGET"); targetStr != "" {
+		needs, wants, savings, err := parseSplitTarget(targetStr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid SPLIT_TARGET: %w", err)
+		}
+		config.Split.TargetNeeds, config.Split.TargetWants, config.Split.TargetSavings = needs, wants, savings
+	}
+	if aliasesStr := os.Getenv("CATEGORY_ALIASES"); aliasesStr != "" {
+		aliases, err := parseStringMap(aliasesStr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid CATEGORY_ALIASES: %w", err)
+		}
+		config.Categories.Aliases = aliases
+	}
+	if lumpyStr := os.Getenv("LUMPY_CATEGORIES"); lumpyStr != "" {
+		config.Categories.Lumpy = parseList(lumpyStr)
+	}
+	if focusStr := os.Getenv("FOCUS_CATEGORIES"); focusStr != "" {
+		config.Categories.Focus = parseList(focusStr)
+	}
+	if fixedStr := os.Getenv("FIXED_CATEGORIES"); fixedStr != "" {
+		config.Categories.Fixed = parseList(fixedStr)
+	}
+	if goalTypesStr := os.Getenv("FIXED_GOAL_TYPES"); goalTypesStr != "" {
+		config.Categories.FixedGoalTypes = parseList(goalTypesStr)
+	}
+	if offsetsStr := os.Getenv("CATEGORY_OFFSETS"); offsetsStr != "" {
+		offsets, err := parseStringMap(offsetsStr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid CATEGORY_OFFSETS: %w", err)
+		}
+		config.Categories.Offsets = offsets
+	}
+	if hygieneStr := os.Getenv("CATEGORY_HYGIENE"); hygieneStr != "" {
+		if enabled, err := strconv.ParseBool(hygieneStr); err == nil {
+			config.Categories.Hygiene = enabled
+		}
+	}
+	if balancesStr := os.Getenv("ACCOUNT_BALANCES"); balancesStr != "" {
+		if enabled, err := strconv.ParseBool(balancesStr); err == nil {
+			config.Accounts.Balances = enabled
+		}
+	}
+	if billsStr := os.Getenv("UTILITY_BILLS"); billsStr != "" {
+		config.Utilities.Bills = parseList(billsStr)
+	}
+	if spikeStr := os.Getenv("UTILITY_SPIKE_PERCENT"); spikeStr != "" {
+		spike, err := strconv.Atoi(spikeStr)
+		if err != nil || spike <= 0 {
+			return nil, fmt.Errorf("invalid UTILITY_SPIKE_PERCENT %q: must be a positive whole number", spikeStr)
+		}
+		config.Utilities.SpikePercent = spike
+	}
+	if netWorthStr := os.Getenv("NET_WORTH"); netWorthStr != "" {
+		if enabled, err := strconv.ParseBool(netWorthStr); err == nil {
+			config.Accounts.NetWorth = enabled
+		}
+	}
+	if groupsStr := os.Getenv("ACCOUNT_GROUPS"); groupsStr != "" {
+		groups, err := parseStringMap(groupsStr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid ACCOUNT_GROUPS: %w", err)
+		}
+		config.Accounts.Groups = groups
+	}
+	if cardsStr := os.Getenv("CARD_PAYMENTS"); cardsStr != "" {
+		if enabled, err := strconv.ParseBool(cardsStr); err == nil {
+			config.Accounts.Cards = enabled
+		}
+	}
+	if pendingStr := os.Getenv("PENDING_TRANSACTIONS"); pendingStr != "" {
+		if enabled, err := strconv.ParseBool(pendingStr); err == nil {
+			config.Accounts.Pending = enabled
+		}
+	}
+	if listStr := os.Getenv("PENDING_TRANSACTIONS_LIST"); listStr != "" {
+		if enabled, err := strconv.ParseBool(listStr); err == nil {
+			config.Accounts.PendingList = enabled
+		}
+	}
+	if transfersStr := os.Getenv("INCLUDE_TRANSFERS"); transfersStr != "" {
+		if enabled, err := strconv.ParseBool(transfersStr); err == nil {
+			config.Accounts.IncludeTransfers = enabled
+		}
+	}
+	if actionsStr := os.Getenv("ACTION_ITEMS"); actionsStr != "" {
+		if enabled, err := strconv.ParseBool(actionsStr); err == nil {
+			config.Actions.Enabled = enabled
+		}
+	}
+	if rateStr := os.Getenv("SAVINGS_RATE"); rateStr != "" {
+		if enabled, err := strconv.ParseBool(rateStr); err == nil {
+			config.Savings.Rate = enabled
+		}
+	}
+	if targetStr := os.Getenv("SAVINGS_TARGET"); targetStr != "" {
+		target, err := strconv.Atoi(targetStr)
+		if err != nil || target < 0 || target > 100 {
+			return nil, fmt.Errorf("invalid SAVINGS_TARGET %q: must be a percentage between 0 and 100", targetStr)
+		}
+		config.Savings.Target = target
+	}
+	if progressStr := os.Getenv("GOAL_PROGRESS"); progressStr != "" {
+		if enabled, err := strconv.ParseBool(progressStr); err == nil {
+			config.Goals.Progress = enabled
+		}
+	}
+	if categoriesStr := os.Getenv("GOAL_CATEGORIES"); categoriesStr != "" {
+		config.Goals.Categories = parseList(categoriesStr)
+	}
+	config.Groceries.Category = os.Getenv("GROCERY_CATEGORY")
+	if monthsStr := os.Getenv("GROCERY_MONTHS"); monthsStr != "" {
+		months, err := strconv.Atoi(monthsStr)
+		if err != nil || months < 1 || months > 24 {
+			return nil, fmt.Errorf("invalid GROCERY_MONTHS %q: must be between 1 and 24", monthsStr)
+		}
+		config.Groceries.Months = months
+	}
+	if partialStr := os.Getenv("PARTIAL_CATEGORY_FETCH"); partialStr != "" {
+		if enabled, err := strconv.ParseBool(partialStr); err == nil {
+			config.Categories.PartialFetch = enabled
+		}
+	}
+	if weekAheadStr := os.Getenv("WEEK_AHEAD"); weekAheadStr != "" {
+		if enabled, err := strconv.ParseBool(weekAheadStr); err == nil {
+			config.Categories.WeekAhead = enabled
+		}
+	} else {
+		config.Categories.WeekAhead = true
+	}
+	if spendingOnlyStr := os.Getenv("SPENDING_ONLY"); spendingOnlyStr != "" {
+		if enabled, err := strconv.ParseBool(spendingOnlyStr); err == nil {
+			config.Categories.SpendingOnly = enabled
+		}
+	}
+	if startDayStr := os.Getenv("FISCAL_MONTH_START_DAY"); startDayStr != "" {
+		day, err := strconv.Atoi(startDayStr)
+		if err != nil || day < 1 || day > 28 {
+			return nil, fmt.Errorf("invalid FISCAL_MONTH_START_DAY %q: must be a day between 1 and 28", startDayStr)
+		}
+		config.Fiscal.MonthStartDay = day
+	}
+
 	// Set defaults
 	if config.Schedule.Cron == "" {
 		config.Schedule.Cron = "0 9 * * 1"
@@ -128,6 +1191,99 @@ func LoadConfig() (*Config, error) {
 	if config.Schedule.MonthlyCron == "" {
 		config.Schedule.MonthlyCron = "0 9 1 * *"
 	}
+	if config.Schedule.MonthlyTimezone == "" {
+		config.Schedule.MonthlyTimezone = config.Schedule.Timezone
+	}
+	if config.Alerts.Timezone == "" {
+		config.Alerts.Timezone = config.Schedule.Timezone
+	}
+	if config.Schedule.RetryAttempts == 0 {
+		config.Schedule.RetryAttempts = 3
+	}
+	if config.Schedule.RetryDelay == 0 {
+		config.Schedule.RetryDelay = time.Minute
+	}
+	if config.Telegram.RetryAttempts == 0 {
+		config.Telegram.RetryAttempts = 3
+	}
+	if config.Telegram.RetryDelay == 0 {
+		config.Telegram.RetryDelay = time.Second
+	}
+	if config.Telegram.WebhookAddr == "" {
+		config.Telegram.WebhookAddr = ":8443"
+	}
+	if config.Speech.Voice == "" {
+		config.Speech.Voice = "en"
+	}
+	if config.Delivery.Timeout == 0 {
+		config.Delivery.Timeout = 30 * time.Second
+	}
+	if config.Email.Port == 0 {
+		config.Email.Port = 587
+	}
+	if config.Email.From == "" {
+		config.Email.From = config.Email.Username
+	}
+	if config.Split.TargetNeeds == 0 && config.Split.TargetWants == 0 && config.Split.TargetSavings == 0 {
+		config.Split.TargetNeeds, config.Split.TargetWants, config.Split.TargetSavings = 50, 30, 20
+	}
+	if config.Fiscal.MonthStartDay == 0 {
+		config.Fiscal.MonthStartDay = 1
+	}
+	if config.Alerts.Cron == "" {
+		config.Alerts.Cron = "0 8 * * *"
+	}
+	if config.Alerts.OverdraftLookbackDays == 0 {
+		config.Alerts.OverdraftLookbackDays = 30
+	}
+	if config.Alerts.OverdraftHorizonDays == 0 {
+		config.Alerts.OverdraftHorizonDays = 14
+	}
+	if config.Journal.Provider == "" {
+		config.Journal.Provider = "github"
+	}
+	if config.Journal.APIURL == "" && config.Journal.Provider == "github" {
+		config.Journal.APIURL = "https://api.github.com"
+	}
+	if config.Journal.Dir == "" {
+		config.Journal.Dir = "journal"
+	}
+	if config.History.NewPayeeMonths == 0 {
+		config.History.NewPayeeMonths = 6
+	}
+	if config.Groceries.Months == 0 {
+		config.Groceries.Months = 6
+	}
+	if config.Utilities.SpikePercent == 0 {
+		config.Utilities.SpikePercent = 20
+	}
+	if config.History.AnomalyStdDevs == 0 {
+		config.History.AnomalyStdDevs = 2
+	}
+	if config.History.AnomalyMonths == 0 {
+		config.History.AnomalyMonths = 6
+	}
+	if config.History.PatternMonths == 0 {
+		config.History.PatternMonths = 3
+	}
+	if config.Alerts.FraudWindowDays == 0 {
+		config.Alerts.FraudWindowDays = 3
+	}
+	if config.Alerts.FraudBaselineDays == 0 {
+		config.Alerts.FraudBaselineDays = 90
+	}
+	if config.Alerts.FraudMicroCharge == 0 {
+		config.Alerts.FraudMicroCharge = 1000
+	}
+	if config.Alerts.ImportLookbackDays == 0 {
+		config.Alerts.ImportLookbackDays = 60
+	}
+	if config.Alerts.ImportSilentDays == 0 {
+		config.Alerts.ImportSilentDays = 4
+	}
+	if config.Locale == "" {
+		config.Locale = i18n.DefaultLocale
+	}
 	if config.Logging.Level == "" {
 		config.Logging.Level = "info"
 	}
@@ -140,10 +1296,196 @@ func LoadConfig() (*Config, error) {
 	if config.Thresholds.OverBudgetPercent == 0 {
 		config.Thresholds.OverBudgetPercent = 100
 	}
+	if config.Thresholds.VelocityPercent == 0 {
+		config.Thresholds.VelocityPercent = 50
+	}
 
 	return config, nil
 }
 
+// parseList parses a comma-separated list, trimming whitespace and dropping empty entries
+func parseList(value string) []string {
+	var result []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			result = append(result, item)
+		}
+	}
+	return result
+}
+
+// parseStringMap parses a comma-separated list of "key=value" pairs
+func parseStringMap(value string) (map[string]string, error) {
+	result := make(map[string]string)
+	for _, pair := range parseList(value) {
+		parts := strings.SplitN(pair, "=", 2)
+		if len(parts) != 2 {
+			return nil, fmt.Errorf("expected key=value, got %q", pair)
+		}
+		result[strings.TrimSpace(parts[0])] = strings.TrimSpace(parts[1])
+	}
+	return result, nil
+}
+
+// parseFlagColors parses a comma-separated list of flag colors, in any case
+func parseFlagColors(value string) ([]string, error) {
+	var colors []string
+	for _, color := range parseList(value) {
+		color = strings.ToLower(color)
+		if !slices.Contains(FlagColors, color) {
+			return nil, fmt.Errorf("unknown flag color %q, must be one of %s", color, strings.Join(FlagColors, ", "))
+		}
+		colors = append(colors, color)
+	}
+	return colors, nil
+}
+
+// parseNotes parses semicolon-separated "category=note" pairs. Notes are
+// free text, so unlike parseStringMap commas are allowed in them.
+func parseNotes(value string) (map[string]string, error) {
+	result := make(map[string]string)
+	for _, pair := range strings.Split(value, ";") {
+		if strings.TrimSpace(pair) == "" {
+			continue
+		}
+		category, note, ok := strings.Cut(pair, "=")
+		if !ok {
+			return nil, fmt.Errorf("expected category=note, got %q", pair)
+		}
+		result[strings.TrimSpace(category)] = strings.TrimSpace(note)
+	}
+	return result, nil
+}
+
+// parseTelegramChats parses a comma-separated list of chat IDs, each with an
+// optional topic ID, e.g. "123456789,-1001234567890:42"
+func parseTelegramChats(value string) ([]TelegramChat, error) {
+	var chats []TelegramChat
+	for _, entry := range parseList(value) {
+		idStr, topicStr, hasTopic := strings.Cut(entry, ":")
+		chatID, err := strconv.ParseInt(strings.TrimSpace(idStr), 10, 64)
+		if err != nil || chatID == 0 {
+			return nil, fmt.Errorf("invalid chat ID %q", idStr)
+		}
+		chat := TelegramChat{ChatID: chatID}
+		if hasTopic {
+			chat.TopicID, err = strconv.Atoi(strings.TrimSpace(topicStr))
+			if err != nil || chat.TopicID <= 0 {
+				return nil, fmt.Errorf("invalid topic ID %q for chat %d", topicStr, chatID)
+			}
+		}
+		chats = append(chats, chat)
+	}
+	if len(chats) == 0 {
+		return nil, fmt.Errorf("no chat IDs in %q", value)
+	}
+	return chats, nil
+}
+
+// parseDisplayCurrencies parses "destination=CODE@rate,..." such as
+// "telegram:-1001234=INR@83.2,email=USD". Without a rate amounts are shown
+// in the currency's format as they are.
+func parseDisplayCurrencies(value string) (map[string]DisplayCurrency, error) {
+	pairs, err := parseStringMap(value)
+	if err != nil {
+		return nil, err
+	}
+	currencies := make(map[string]DisplayCurrency, len(pairs))
+	for destination, spec := range pairs {
+		code, rateStr, hasRate := strings.Cut(spec, "@")
+		code = strings.ToUpper(strings.TrimSpace(code))
+		if !isCurrencyCode(code) {
+			return nil, fmt.Errorf("currency for %s must be an ISO code such as INR, got %q", destination, code)
+		}
+		currency := DisplayCurrency{Code: code}
+		if hasRate {
+			currency.Rate, err = strconv.ParseFloat(strings.TrimSpace(rateStr), 64)
+			if err != nil || currency.Rate <= 0 || math.IsInf(currency.Rate, 0) {
+				return nil, fmt.Errorf("invalid rate %q for %s", rateStr, destination)
+			}
+		}
+		currencies[destination] = currency
+	}
+	return currencies, nil
+}
+
+func isCurrencyCode(code string) bool {
+	if len(code) != 3 {
+		return false
+	}
+	for _, r := range code {
+		if r < 'A' || r > 'Z' {
+			return false
+		}
+	}
+	return true
+}
+
+// parseSplitTags parses "Name=need,Name=want,..." and validates the tags
+func parseSplitTags(value string) (map[string]string, error) {
+	tags, err := parseStringMap(value)
+	if err != nil {
+		return nil, err
+	}
+	for name, tag := range tags {
+		tag = strings.ToLower(tag)
+		if tag != "need" && tag != "want" && tag != "savings" {
+			return nil, fmt.Errorf("tag for %q must be need, want or savings, got %q", name, tag)
+		}
+		tags[name] = tag
+	}
+	return tags, nil
+}
+
+// parseSplitTarget parses a "needs/wants/savings" percentage target such as "50/30/20"
+func parseSplitTarget(value string) (needs, wants, savings int, err error) {
+	parts := strings.Split(value, "/")
+	if len(parts) != 3 {
+		return 0, 0, 0, fmt.Errorf("expected needs/wants/savings, got %q", value)
+	}
+
+	var values [3]int
+	for i, part := range parts {
+		values[i], err = strconv.Atoi(strings.TrimSpace(part))
+		if err != nil {
+			return 0, 0, 0, fmt.Errorf("invalid percentage %q: %w", part, err)
+		}
+	}
+	if values[0]+values[1]+values[2] != 100 {
+		return 0, 0, 0, fmt.Errorf("percentages must add up to 100, got %q", value)
+	}
+	return values[0], values[1], values[2], nil
+}
+
+// parseAmountMap parses a comma-separated list of "Name=amount" pairs where
+// amount is in currency units (e.g. "Groceries=130,Dining Out=80.50") and
+// returns the amounts in YNAB milliunits.
+func parseAmountMap(value string) (map[string]int64, error) {
+	result := make(map[string]int64)
+	for _, pair := range strings.Split(value, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+
+		parts := strings.SplitN(pair, "=", 2)
+		if len(parts) != 2 {
+			return nil, fmt.Errorf("expected Name=amount, got %q", pair)
+		}
+
+		name := strings.TrimSpace(parts[0])
+		amount, err := strconv.ParseFloat(strings.TrimSpace(parts[1]), 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid amount for %q: %w", name, err)
+		}
+		result[name] = int64(math.Round(amount * 1000))
+	}
+	return result, nil
+}
+
+// webhookSecretPattern is what Telegram accepts as a webhook secret token
+var webhookSecretPattern = regexp.MustCompile(`^[A-Za-z0-9_-]{1,256}$`)
+
 // ValidateConfig validates required configuration fields
 // testMode: if true, skip publisher validation (useful for dry-run testing)
 func ValidateConfig(config *Config, testMode bool) error {
@@ -160,12 +1502,60 @@ func ValidateConfig(config *Config, testMode bool) error {
 		return nil
 	}
 
-	// For production, require at least one publisher to be configured
-	hasTelegram := config.Telegram.BotToken != "" && config.Telegram.ChatID != 0
+	if journal := config.Journal; journal.Repo != "" {
+		if owner, name, ok := strings.Cut(journal.Repo, "/"); !ok || owner == "" || name == "" || strings.Contains(name, "/") {
+			return fmt.Errorf("the journal repository must be owner/name, got %q (JOURNAL_REPO)", journal.Repo)
+		}
+		if journal.Token == "" {
+			return fmt.Errorf("a token is required to commit to the journal repository (set JOURNAL_TOKEN)")
+		}
+		if journal.Provider != "github" && journal.Provider != "gitea" {
+			return fmt.Errorf("the journal provider must be github or gitea, got %q (JOURNAL_PROVIDER)", journal.Provider)
+		}
+		if journal.APIURL == "" {
+			return fmt.Errorf("the API URL of the Gitea server is required, e.g. https://git.example.com/api/v1 (set JOURNAL_API_URL)")
+		}
+	}
+
+	if config.Trigger.Addr != "" && config.Trigger.Token == "" {
+		return fmt.Errorf("a trigger token is required when the trigger endpoint is enabled (set TRIGGER_TOKEN)")
+	}
+
+	return ValidateDelivery(config)
+}
+
+// ValidateDelivery validates the publisher settings alone, for commands that
+// send messages without reading the budget
+func ValidateDelivery(config *Config) error {
+	// Require at least one publisher to be configured
+	hasTelegram := config.Telegram.BotToken != "" && len(config.Telegram.Targets()) > 0
 	hasDiscord := config.Discord.WebhookURL != ""
+	hasEmail := config.Email.Host != "" && len(config.Email.To) > 0
+	hasWebhook := config.Webhook.URL != ""
+	hasMatrix := config.Matrix.HomeserverURL != "" && config.Matrix.AccessToken != "" && config.Matrix.RoomID != ""
+	hasTeams := config.Teams.WebhookURL != ""
+	hasGotify := config.Gotify.URL != "" && config.Gotify.Token != ""
+	hasWhatsApp := config.WhatsApp.PhoneNumberID != "" && config.WhatsApp.Token != "" && config.WhatsApp.Recipient != ""
+
+	if config.Email.Host != "" && config.Email.From == "" {
+		return fmt.Errorf("email sender is required when SMTP is configured (set EMAIL_FROM)")
+	}
+
+	if config.Telegram.WebhookURL != "" {
+		if !strings.HasPrefix(config.Telegram.WebhookURL, "https://") {
+			return fmt.Errorf("the Telegram webhook URL must use https (TELEGRAM_WEBHOOK_URL)")
+		}
+		if !webhookSecretPattern.MatchString(config.Telegram.WebhookSecret) {
+			return fmt.Errorf("a Telegram webhook secret of 1-256 letters, digits, _ or - is required (set TELEGRAM_WEBHOOK_SECRET)")
+		}
+	}
+
+	if config.Telegram.VoiceSummary && config.Speech.Provider == "command" && config.Speech.Command == "" {
+		return fmt.Errorf("a speech command is required for the command provider (set SPEECH_COMMAND)")
+	}
 
-	if !hasTelegram && !hasDiscord {
-		return fmt.Errorf("at least one publisher must be configured (Telegram or Discord)")
+	if !hasTelegram && !hasDiscord && !hasEmail && !hasWebhook && !hasMatrix && !hasTeams && !hasGotify && !hasWhatsApp {
+		return fmt.Errorf("at least one publisher must be configured (Telegram, Discord, Email, Webhook, Matrix, Teams, Gotify or WhatsApp)")
 	}
 
 	return nil
@@ -0,0 +1,124 @@
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// defaultProfilesFile is read when PROFILES_FILE isn't set
+const defaultProfilesFile = "profiles.json"
+
+// profilesFile holds named sets of settings, using the same names as the
+// environment variables, that a profile applies on top of the environment:
+//
+//	{
+//	  "profiles": {
+//	    "test": {"TELEGRAM_CHAT_ID": "123456789", "YNAB_BUDGET_ID": "test-budget-id"},
+//	    "prod": {"TELEGRAM_CHAT_ID": "-1001234567890"}
+//	  }
+//	}
+//
+// A profile can build on others with EXTENDS, e.g. one instance per family
+// member sharing a base: {"EXTENDS": "base", "YNAB_BUDGET_ID": "kids"}.
+type profilesFile struct {
+	Profiles map[string]map[string]string `json:"profiles"`
+}
+
+// extendsKey lists, comma-separated, the profiles a profile builds on.
+// Their settings are applied first, in order, so the profile's own win.
+const extendsKey = "EXTENDS"
+
+// applyProfile sets the environment variables of the named profile, so they
+// take precedence over the environment and .env files
+func applyProfile(path, name string) error {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("profile %q selected but there is no profiles file at %s (set PROFILES_FILE)", name, path)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read profiles file: %w", err)
+	}
+	var file profilesFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return fmt.Errorf("failed to parse profiles file %s: %w", path, err)
+	}
+
+	if _, ok := file.Profiles[name]; !ok {
+		names := make([]string, 0, len(file.Profiles))
+		for known := range file.Profiles {
+			names = append(names, known)
+		}
+		sort.Strings(names)
+		return fmt.Errorf("unknown profile %q, %s has: %s", name, path, strings.Join(names, ", "))
+	}
+	settings, err := resolveProfile(file.Profiles, name, nil)
+	if err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+	for key, value := range settings {
+		os.Setenv(key, value)
+	}
+	return nil
+}
+
+// resolveProfile returns the settings of the named profile merged over
+// those of the profiles it extends. chain is the profiles being resolved
+// that lead to this one, to catch a profile extending itself.
+func resolveProfile(profiles map[string]map[string]string, name string, chain []string) (map[string]string, error) {
+	chain = append(chain, name)
+	if slices.Contains(chain[:len(chain)-1], name) {
+		return nil, fmt.Errorf("profile %q extends itself: %s", name, strings.Join(chain, " -> "))
+	}
+
+	own := profiles[name]
+	settings := make(map[string]string)
+	for _, base := range parseList(own[extendsKey]) {
+		if _, ok := profiles[base]; !ok {
+			return nil, fmt.Errorf("profile %q extends unknown profile %q", name, base)
+		}
+		inherited, err := resolveProfile(profiles, base, chain)
+		if err != nil {
+			return nil, err
+		}
+		maps.Copy(settings, inherited)
+	}
+	maps.Copy(settings, own)
+	delete(settings, extendsKey)
+	return settings, nil
+}
+
+// LoadProfiles loads each named profile over the same environment, e.g. to
+// compare two sets of thresholds. Unlike calling LoadProfile in turn, one
+// profile's settings don't carry over into the next. An empty name loads
+// the environment as LoadProfile does.
+func LoadProfiles(names ...string) ([]*Config, error) {
+	environ := os.Environ()
+	defer restoreEnv(environ)
+
+	configs := make([]*Config, 0, len(names))
+	for _, name := range names {
+		restoreEnv(environ)
+		cfg, err := LoadProfile(name)
+		if err != nil {
+			return nil, err
+		}
+		configs = append(configs, cfg)
+	}
+	return configs, nil
+}
+
+// restoreEnv replaces the environment with environ, as from os.Environ
+func restoreEnv(environ []string) {
+	os.Clearenv()
+	for _, entry := range environ {
+		if key, value, ok := strings.Cut(entry, "="); ok {
+			os.Setenv(key, value)
+		}
+	}
+}
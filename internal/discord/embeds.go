@@ -0,0 +1,221 @@
+package discord
+
+import (
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/sathyabhat/ynab-weekly-wrap/internal/notes"
+	"github.com/sathyabhat/ynab-weekly-wrap/internal/processor"
+	"github.com/sathyabhat/ynab-weekly-wrap/internal/publisher"
+)
+
+// Discord limits for a single webhook execution
+const (
+	maxEmbedsPerMessage = 10
+	maxFieldsPerEmbed   = 25
+)
+
+// Embed colors
+const (
+	colorInfo    = 0x3498db
+	colorSuccess = 0x2ecc71
+	colorWarning = 0xe74c3c
+	colorCaution = 0xe67e22 // Moderately over budget
+	colorNotice  = 0xf1c40f // Slightly over budget
+)
+
+// severityMarks sets each over budget severity tier apart with an emoji and
+// the color of the embed when it's the worst tier
+var severityMarks = map[processor.Severity]struct {
+	emoji string
+	color int
+}{
+	processor.SeveritySlight:   {"🟡 ", colorNotice},
+	processor.SeverityModerate: {"🟠 ", colorCaution},
+	processor.SeveritySevere:   {"🔴 ", colorWarning},
+}
+
+// Embed represents a Discord rich embed
+type Embed struct {
+	Title       string       `json:"title,omitempty"`
+	Description string       `json:"description,omitempty"`
+	Color       int          `json:"color,omitempty"`
+	Fields      []EmbedField `json:"fields,omitempty"`
+}
+
+// EmbedField represents a single name/value field within an embed
+type EmbedField struct {
+	Name   string `json:"name"`
+	Value  string `json:"value"`
+	Inline bool   `json:"inline,omitempty"`
+}
+
+// PublishReport sends the report as Discord embeds when embeds are enabled,
+// falling back to the plain text message otherwise.
+func (p *WebhookPublisher) PublishReport(report publisher.Report) error {
+	if !p.UseEmbeds || report.Analysis == nil {
+		return p.Publish(report.Message)
+	}
+
+	embeds := buildEmbeds(report)
+	batches := (len(embeds) + maxEmbedsPerMessage - 1) / maxEmbedsPerMessage
+	log.Printf("Sending %d embed(s) in %d message(s) to Discord Webhook", len(embeds), batches)
+
+	for i := 0; i < len(embeds); i += maxEmbedsPerMessage {
+		end := i + maxEmbedsPerMessage
+		if end > len(embeds) {
+			end = len(embeds)
+		}
+		if err := p.post(WebhookRequest{Embeds: embeds[i:end]}); err != nil {
+			return fmt.Errorf("failed to send discord embeds %d/%d: %w", i/maxEmbedsPerMessage+1, batches, err)
+		}
+	}
+
+	log.Println("Discord embed(s) sent successfully")
+	return nil
+}
+
+// buildEmbeds renders the overview, top-spending and over-budget sections as embeds
+func buildEmbeds(report publisher.Report) []Embed {
+	analysis := report.Analysis
+	currency := analysis.Currency
+
+	description := fmt.Sprintf("💰 **Total Spent**: %s", currency.Amount(analysis.Overview.TotalSpent))
+	if safe := analysis.SafeToSpend; safe != nil {
+		description = fmt.Sprintf("🛒 **Safe to spend through %s**: %s\n", safe.Through.Format("Monday"), currency.Amount(safe.Amount)) + description
+	}
+	if lumpy := analysis.Overview.LumpySpent; lumpy > 0 {
+		description += fmt.Sprintf("\n📦 Includes %s in one-off/annual categories", currency.Amount(lumpy))
+	}
+	if coverage := analysis.Coverage; coverage != nil {
+		description += fmt.Sprintf("\n🗓️ Partial period: covers %s to %s (%d of %d days)",
+			coverage.From.Format("2006-01-02"), coverage.To.Format("2006-01-02"), coverage.Days, coverage.PeriodDays)
+	}
+	if analysis.FirstReport {
+		description += "\nℹ️ First report, comparisons unavailable"
+	}
+
+	embeds := []Embed{{
+		Title:       "📊 " + report.Title,
+		Description: description,
+		Color:       colorInfo,
+	}}
+
+	var topFields []EmbedField
+	var listed []string
+	for _, category := range analysis.TopSpending {
+		value := fmt.Sprintf("Spent: %s\nBalance: %s", currency.Amount(category.Spent), currency.Amount(category.Balance))
+		if analysis.NoBudget {
+			value = "Spent: " + currency.Amount(category.Spent)
+		}
+		if note := notes.Lookup(analysis.Notes, category.Category); note != "" {
+			value += "\n📝 " + note
+		}
+		topFields = append(topFields, EmbedField{
+			Name:   category.Category,
+			Value:  value,
+			Inline: true,
+		})
+		listed = append(listed, category.Category)
+	}
+	embeds = append(embeds, fieldEmbeds("🏆 Top Spending Categories", colorInfo, topFields)...)
+
+	if len(analysis.Payees) > 0 {
+		var payeeFields []EmbedField
+		for _, payee := range analysis.Payees {
+			payeeFields = append(payeeFields, EmbedField{
+				Name:   payee.Payee,
+				Value:  fmt.Sprintf("%s across %d transactions", currency.Amount(payee.Spent), payee.Transactions),
+				Inline: true,
+			})
+		}
+		embeds = append(embeds, fieldEmbeds("🛒 Top Payees", colorInfo, payeeFields)...)
+	}
+
+	switch {
+	case analysis.NoBudget:
+		// Spending only, so nothing is over budget
+	case len(analysis.Concerns) == 0:
+		embeds = append(embeds, Embed{
+			Title:       "⚠️ Over Budget Categories",
+			Description: "No categories over budget - great job! 🎉",
+			Color:       colorSuccess,
+		})
+	default:
+		var concernFields []EmbedField
+		for _, concern := range analysis.Concerns {
+			concernFields = append(concernFields, EmbedField{
+				Name:  severityMarks[concern.Severity].emoji + concern.Category,
+				Value: fmt.Sprintf("Spent: %s\nBalance: %s\nOver by: %s", currency.Amount(concern.Spent), currency.Amount(concern.Balance), currency.Amount(concern.Over)),
+			})
+		}
+		// Concerns come most severe first
+		color := colorWarning
+		if mark, ok := severityMarks[analysis.Concerns[0].Severity]; ok {
+			color = mark.color
+		}
+		embeds = append(embeds, fieldEmbeds("⚠️ Over Budget Categories", color, concernFields)...)
+	}
+
+	if hygiene := analysis.Hygiene; !hygiene.Empty() {
+		var fields []EmbedField
+		if len(hygiene.Unused) > 0 {
+			fields = append(fields, EmbedField{
+				Name:  fmt.Sprintf("Unused for %d+ months", hygiene.UnusedMonths),
+				Value: strings.Join(hygiene.Unused, ", ") + "\nDelete or merge into a related category",
+			})
+		}
+		for _, hidden := range hygiene.HiddenBalances {
+			fields = append(fields, EmbedField{Name: hidden.Category, Value: "Hidden but holds " + currency.Amount(hidden.Balance)})
+		}
+		for _, over := range hygiene.ChronicOver {
+			fields = append(fields, EmbedField{Name: over.Category, Value: fmt.Sprintf("Overspent in %d of %d months: %s spent vs %s budgeted on average",
+				over.Months, over.OfMonths, currency.Amount(over.AvgSpent), currency.Amount(over.AvgBudgeted))})
+		}
+		for _, under := range hygiene.ChronicUnder {
+			fields = append(fields, EmbedField{Name: under.Category, Value: fmt.Sprintf("Under half spent in %d of %d months: %s spent vs %s budgeted on average",
+				under.Months, under.OfMonths, currency.Amount(under.AvgSpent), currency.Amount(under.AvgBudgeted))})
+		}
+		embeds = append(embeds, fieldEmbeds("🧹 Budget Tidy-Up", colorInfo, fields)...)
+	}
+
+	if len(analysis.NewPayees) > 0 {
+		embeds = append(embeds, Embed{
+			Title:       "🆕 New Payees",
+			Description: strings.Join(analysis.NewPayees, "\n"),
+			Color:       colorWarning,
+		})
+	}
+
+	var noteFields []EmbedField
+	for _, category := range notes.Unlisted(analysis.Notes, listed) {
+		noteFields = append(noteFields, EmbedField{Name: category, Value: analysis.Notes[category]})
+	}
+	if len(noteFields) > 0 {
+		embeds = append(embeds, fieldEmbeds("📝 Notes", colorInfo, noteFields)...)
+	}
+	return embeds
+}
+
+// fieldEmbeds splits fields across as many embeds as needed to respect
+// Discord's per-embed field limit.
+func fieldEmbeds(title string, color int, fields []EmbedField) []Embed {
+	if len(fields) == 0 {
+		return []Embed{{Title: title, Description: "None", Color: color}}
+	}
+
+	var embeds []Embed
+	for i := 0; i < len(fields); i += maxFieldsPerEmbed {
+		end := i + maxFieldsPerEmbed
+		if end > len(fields) {
+			end = len(fields)
+		}
+		embedTitle := title
+		if i > 0 {
+			embedTitle += " (cont.)"
+		}
+		embeds = append(embeds, Embed{Title: embedTitle, Color: color, Fields: fields[i:end]})
+	}
+	return embeds
+}
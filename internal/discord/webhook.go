@@ -13,20 +13,28 @@ import (
 // WebhookPublisher implements the publisher.Publisher interface for Discord Webhooks
 type WebhookPublisher struct {
 	WebhookURL string
+	UseEmbeds  bool // Render reports as rich embeds instead of plain text
 }
 
 // WebhookRequest represents the payload for Discord Webhook
 type WebhookRequest struct {
-	Content string `json:"content"`
+	Content string  `json:"content,omitempty"`
+	Embeds  []Embed `json:"embeds,omitempty"`
 }
 
 // NewWebhookPublisher creates a new Discord Webhook publisher
-func NewWebhookPublisher(webhookURL string) *WebhookPublisher {
+func NewWebhookPublisher(webhookURL string, useEmbeds bool) *WebhookPublisher {
 	return &WebhookPublisher{
 		WebhookURL: webhookURL,
+		UseEmbeds:  useEmbeds,
 	}
 }
 
+// Name identifies the destination in delivery summaries
+func (p *WebhookPublisher) Name() string {
+	return "discord"
+}
+
 // Publish sends the message to the configured Discord Webhook URL.
 // If the message exceeds Discord's 2000 character limit, it is split into
 // multiple messages at line boundaries (category boundaries).
@@ -46,10 +54,11 @@ func (p *WebhookPublisher) Publish(message string) error {
 
 // send delivers a single chunk to the Discord webhook.
 func (p *WebhookPublisher) send(content string) error {
-	reqBody := WebhookRequest{
-		Content: content,
-	}
+	return p.post(WebhookRequest{Content: content})
+}
 
+// post delivers a single webhook payload to Discord.
+func (p *WebhookPublisher) post(reqBody WebhookRequest) error {
 	jsonData, err := json.Marshal(reqBody)
 	if err != nil {
 		return fmt.Errorf("failed to marshal discord request: %w", err)
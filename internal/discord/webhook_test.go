@@ -8,6 +8,9 @@ import (
 	"net/http/httptest"
 	"strings"
 	"testing"
+
+	"github.com/sathyabhat/ynab-weekly-wrap/internal/processor"
+	"github.com/sathyabhat/ynab-weekly-wrap/internal/publisher"
 )
 
 func TestDiscordPublish(t *testing.T) {
@@ -137,3 +140,119 @@ func TestDiscordPublish_SplitAtLineBoundary(t *testing.T) {
 		t.Errorf("Expected multiple requests for split message, got %d", requestCount)
 	}
 }
+
+func TestDiscordPublishReport_Embeds(t *testing.T) {
+	var received []WebhookRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		var payload WebhookRequest
+		if err := json.Unmarshal(body, &payload); err != nil {
+			t.Fatalf("Failed to unmarshal request body: %v", err)
+		}
+		received = append(received, payload)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	report := publisher.Report{
+		Title:   "Weekly Financial Wrap - 2026-01-19 to 2026-01-26",
+		Message: "plain text fallback",
+		Analysis: &processor.AnalysisResult{
+			Overview: &processor.Overview{TotalSpent: 550_000},
+			TopSpending: []processor.TopSpendingCategory{
+				{Category: "Groceries", Spent: 200_000, Balance: 300_000},
+				{Category: "Dining", Spent: 350_000, Balance: -50_000},
+			},
+			Concerns: []processor.CategoryConcernWithTransactions{
+				{Category: "Dining", Spent: 350_000, Balance: -50_000, Over: 50_000},
+			},
+		},
+	}
+
+	p := NewWebhookPublisher(server.URL, true)
+	if err := p.PublishReport(report); err != nil {
+		t.Fatalf("PublishReport failed: %v", err)
+	}
+
+	if len(received) != 1 {
+		t.Fatalf("Expected 1 request, got %d", len(received))
+	}
+	payload := received[0]
+	if payload.Content != "" {
+		t.Errorf("Expected no plain content with embeds, got %q", payload.Content)
+	}
+	if len(payload.Embeds) != 3 {
+		t.Fatalf("Expected 3 embeds (overview, top spending, over budget), got %d", len(payload.Embeds))
+	}
+	if !strings.Contains(payload.Embeds[0].Description, "$550") {
+		t.Errorf("Overview embed should contain total spent, got %q", payload.Embeds[0].Description)
+	}
+	if len(payload.Embeds[1].Fields) != 2 {
+		t.Errorf("Top spending embed fields: got %d, want 2", len(payload.Embeds[1].Fields))
+	}
+	concern := payload.Embeds[2]
+	if concern.Color != colorWarning || len(concern.Fields) != 1 || concern.Fields[0].Name != "Dining" {
+		t.Errorf("Unexpected over budget embed: %+v", concern)
+	}
+}
+
+func TestDiscordPublishReport_EmbedsDisabled(t *testing.T) {
+	var payload map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		_ = json.Unmarshal(body, &payload)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	p := NewWebhookPublisher(server.URL, false)
+	err := p.PublishReport(publisher.Report{
+		Message:  "plain text",
+		Analysis: &processor.AnalysisResult{Overview: &processor.Overview{}},
+	})
+	if err != nil {
+		t.Fatalf("PublishReport failed: %v", err)
+	}
+	if payload["content"] != "plain text" {
+		t.Errorf("Expected plain text content, got %v", payload["content"])
+	}
+	if _, ok := payload["embeds"]; ok {
+		t.Error("Expected no embeds when embeds are disabled")
+	}
+}
+
+func TestBuildEmbeds_CategoryNotes(t *testing.T) {
+	embeds := buildEmbeds(publisher.Report{
+		Analysis: &processor.AnalysisResult{
+			Overview:    &processor.Overview{TotalSpent: 200_000},
+			TopSpending: []processor.TopSpendingCategory{{Category: "Groceries", Spent: 200_000, Balance: 300_000}},
+			Notes:       map[string]string{"Groceries": "weekly meal prep", "Gifts": "birthday season"},
+		},
+	})
+
+	if got := embeds[1].Fields[0].Value; !strings.HasSuffix(got, "\n📝 weekly meal prep") {
+		t.Errorf("Expected the note under its category, got %q", got)
+	}
+	last := embeds[len(embeds)-1]
+	if last.Title != "📝 Notes" || len(last.Fields) != 1 || last.Fields[0].Name != "Gifts" {
+		t.Errorf("Expected a notes embed for unlisted categories, got %+v", last)
+	}
+}
+
+func TestFieldEmbeds_SplitsAtFieldLimit(t *testing.T) {
+	var fields []EmbedField
+	for i := 0; i < 30; i++ {
+		fields = append(fields, EmbedField{Name: fmt.Sprintf("Category %d", i), Value: "$1"})
+	}
+
+	embeds := fieldEmbeds("Top", colorInfo, fields)
+	if len(embeds) != 2 {
+		t.Fatalf("Expected 2 embeds for 30 fields, got %d", len(embeds))
+	}
+	if len(embeds[0].Fields) != maxFieldsPerEmbed || len(embeds[1].Fields) != 5 {
+		t.Errorf("Unexpected field split: %d + %d", len(embeds[0].Fields), len(embeds[1].Fields))
+	}
+	if embeds[1].Title != "Top (cont.)" {
+		t.Errorf("Continuation title: got %q", embeds[1].Title)
+	}
+}
@@ -0,0 +1,103 @@
+package email
+
+import (
+	"bytes"
+	"html"
+	"html/template"
+	"strings"
+
+	"github.com/sathyabhat/ynab-weekly-wrap/internal/money"
+	"github.com/sathyabhat/ynab-weekly-wrap/internal/publisher"
+	"github.com/sathyabhat/ynab-weekly-wrap/internal/render"
+	"github.com/sathyabhat/ynab-weekly-wrap/internal/theme"
+)
+
+var reportTemplate = template.Must(template.New("report").Funcs(template.FuncMap{
+	"money":  money.USD.Amount,     // Replaced with the budget's currency on each render
+	"icon":   theme.Theme{}.Icon,   // Replaced with the configured theme on each render
+	"suffix": theme.Theme{}.Suffix, // Also replaced with the theme
+	"join":   strings.Join,
+}).Parse(`<!DOCTYPE html>
+<html>
+<body style="font-family: -apple-system, Segoe UI, Helvetica, Arial, sans-serif; color: #222; max-width: 640px;">
+<h2>{{icon "chart"}}{{.Title}}</h2>
+{{with .Analysis.Coverage}}<p style="color: #666;">{{icon "calendar"}}Partial period: covers {{.From.Format "2006-01-02"}} to {{.To.Format "2006-01-02"}} ({{.Days}} of {{.PeriodDays}} days)</p>
+{{end}}{{if .Analysis.FirstReport}}<p style="color: #666;">{{icon "info"}}First report, comparisons unavailable</p>
+{{end}}{{with .Analysis.SafeToSpend}}<p style="font-size: 1.4em;">{{icon "cart"}}<b>Safe to spend through {{.Through.Format "Monday"}}</b>: {{money .Amount}}</p>
+{{end}}<p style="font-size: 1.2em;">{{icon "money"}}<b>Total Spent</b>: {{money .Analysis.Overview.TotalSpent}}</p>
+{{if gt .Analysis.Overview.LumpySpent 0}}<p style="color: #666;">{{icon "package"}}Includes {{money .Analysis.Overview.LumpySpent}} in one-off/annual categories (not counted towards budget health)</p>
+{{end}}
+<h3>{{icon "trophy"}}Top Spending Categories</h3>
+{{if .Analysis.TopSpending}}
+<table cellpadding="6" style="border-collapse: collapse; width: 100%;">
+<tr style="background: #f0f0f0; text-align: left;"><th>Category</th><th>Spent</th>{{if not $.Analysis.NoBudget}}<th>Balance</th>{{end}}</tr>
+{{range .Analysis.TopSpending}}<tr style="border-bottom: 1px solid #eee;"><td>{{.Category}}</td><td>{{money .Spent}}</td>{{if not $.Analysis.NoBudget}}<td{{if lt .Balance 0}} style="color: #c0392b;"{{end}}>{{money .Balance}}</td>{{end}}</tr>
+{{end}}</table>
+{{else}}
+<p>No spending this period.</p>
+{{end}}
+{{if .Analysis.Payees}}
+<h3>{{icon "cart"}}Top Payees</h3>
+<ul>
+{{range .Analysis.Payees}}<li><b>{{.Payee}}</b>: {{money .Spent}} across {{.Transactions}} transactions</li>
+{{end}}</ul>
+{{end}}
+{{if not .Analysis.NoBudget}}
+<h3>{{icon "warning"}}Over Budget Categories</h3>
+{{if .Analysis.Concerns}}
+<table cellpadding="6" style="border-collapse: collapse; width: 100%;">
+<tr style="background: #fbeaea; text-align: left;"><th>Category</th><th>Spent</th><th>Balance</th></tr>
+{{range .Analysis.Concerns}}<tr style="border-bottom: 1px solid #eee;"><td><b>{{icon (print .Severity)}}{{.Category}}</b></td><td>{{money .Spent}}</td><td style="color: {{if eq (print .Severity) "slight"}}#e67e22{{else}}#c0392b{{end}};">{{money .Balance}}</td></tr>
+{{end}}</table>
+{{else}}
+<p>No categories over budget - great job!{{suffix "party"}}</p>
+{{end}}
+{{end}}
+{{with .Analysis.Hygiene}}{{if not .Empty}}
+<h3>{{icon "broom"}}Budget Tidy-Up</h3>
+<ul>
+{{with .Unused}}<li>Unused for {{$.Analysis.Hygiene.UnusedMonths}}+ months: <b>{{join . ", "}}</b>. Delete them or merge them into a related category</li>
+{{end}}{{range .HiddenBalances}}<li><b>{{.Category}}</b> is hidden but holds {{money .Balance}}</li>
+{{end}}{{range .ChronicOver}}<li><b>{{.Category}}</b> overspent in {{.Months}} of {{.OfMonths}} months: {{money .AvgSpent}} spent vs {{money .AvgBudgeted}} budgeted on average</li>
+{{end}}{{range .ChronicUnder}}<li><b>{{.Category}}</b> under half spent in {{.Months}} of {{.OfMonths}} months: {{money .AvgSpent}} spent vs {{money .AvgBudgeted}} budgeted on average</li>
+{{end}}</ul>
+{{end}}{{end}}{{with .Analysis.NewPayees}}
+<h3>{{icon "new"}}New Payees</h3>
+<ul>
+{{range .}}<li>{{.}}</li>
+{{end}}</ul>
+{{end}}{{with .Analysis.Notes}}
+<h3>{{icon "note"}}Notes</h3>
+<ul>
+{{range $category, $note := .}}<li><b>{{$category}}</b>: {{$note}}</li>
+{{end}}</ul>
+{{end}}
+</body>
+</html>
+`))
+
+// reportHTML renders the analysis as an HTML email body
+func reportHTML(report publisher.Report, th theme.Theme) (string, error) {
+	tmpl := template.Must(reportTemplate.Clone()).Funcs(template.FuncMap{
+		"money":  report.Analysis.Currency.Amount,
+		"icon":   th.Icon,
+		"suffix": th.Suffix,
+	})
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, report); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+// emailMarkup is simple HTML with <b> emphasis for mail clients
+var emailMarkup = render.Markup{
+	BoldOpen: "<b>", BoldClose: "</b>", ItalicOpen: "<i>", ItalicClose: "</i>",
+	CodeOpen: "<code>", CodeClose: "</code>", LineBreak: "<br>\n", Escape: html.EscapeString,
+}
+
+// documentHTML converts a rendered chat message into a simple HTML page
+func documentHTML(doc *render.Document) string {
+	return "<!DOCTYPE html>\n<html>\n<body style=\"font-family: -apple-system, Segoe UI, Helvetica, Arial, sans-serif;\">\n" +
+		emailMarkup.Format(doc) + "\n</body>\n</html>\n"
+}
@@ -0,0 +1,144 @@
+package email
+
+import (
+	"bytes"
+	"fmt"
+	"log"
+	"mime"
+	"mime/multipart"
+	"net/smtp"
+	"net/textproto"
+	"strings"
+
+	"github.com/sathyabhat/ynab-weekly-wrap/internal/config"
+	"github.com/sathyabhat/ynab-weekly-wrap/internal/publisher"
+	"github.com/sathyabhat/ynab-weekly-wrap/internal/render"
+	"github.com/sathyabhat/ynab-weekly-wrap/internal/theme"
+)
+
+const defaultSubject = "YNAB Weekly Wrap"
+
+// SMTPPublisher implements the publisher.Publisher interface by sending the
+// wrap as an HTML email with a plaintext fallback
+type SMTPPublisher struct {
+	config config.EmailConfig
+	theme  theme.Theme // Emoji in the HTML section headings
+
+	// sendMail is swapped out in tests
+	sendMail func(addr string, a smtp.Auth, from string, to []string, msg []byte) error
+}
+
+// SMTPOption is a functional option for configuring SMTPPublisher
+type SMTPOption func(*SMTPPublisher)
+
+// WithTheme sets the emoji used in the HTML email, e.g. the plain theme for
+// mail clients that render emoji poorly
+func WithTheme(th theme.Theme) SMTPOption {
+	return func(p *SMTPPublisher) {
+		p.theme = th
+	}
+}
+
+// NewSMTPPublisher creates a new SMTP email publisher
+func NewSMTPPublisher(emailConfig config.EmailConfig, opts ...SMTPOption) *SMTPPublisher {
+	p := &SMTPPublisher{
+		config:   emailConfig,
+		sendMail: smtp.SendMail,
+	}
+	for _, opt := range opts {
+		opt(p)
+	}
+	return p
+}
+
+// Publish sends a plain message, rendering a minimal HTML version of it
+func (p *SMTPPublisher) Publish(message string) error {
+	doc := render.Parse(message)
+	return p.send(defaultSubject, render.Plain.Format(doc), documentHTML(doc))
+}
+
+// Name identifies the destination in delivery summaries
+func (p *SMTPPublisher) Name() string {
+	return "email"
+}
+
+// PublishReport sends the report as a formatted HTML email
+func (p *SMTPPublisher) PublishReport(report publisher.Report) error {
+	subject := report.Title
+	if subject == "" {
+		subject = defaultSubject
+	}
+
+	doc := report.Doc()
+	htmlBody := documentHTML(doc)
+	if report.Analysis != nil {
+		rendered, err := reportHTML(report, p.theme)
+		if err != nil {
+			return fmt.Errorf("failed to render email: %w", err)
+		}
+		htmlBody = rendered
+	}
+
+	return p.send(subject, render.Plain.Format(doc), htmlBody)
+}
+
+func (p *SMTPPublisher) send(subject, textBody, htmlBody string) error {
+	log.Printf("Sending email to %s", strings.Join(p.config.To, ", "))
+
+	msg, err := buildMessage(p.config.From, p.config.To, subject, textBody, htmlBody)
+	if err != nil {
+		return fmt.Errorf("failed to build email: %w", err)
+	}
+
+	var auth smtp.Auth
+	if p.config.Username != "" {
+		auth = smtp.PlainAuth("", p.config.Username, p.config.Password, p.config.Host)
+	}
+
+	addr := fmt.Sprintf("%s:%d", p.config.Host, p.config.Port)
+	if err := p.sendMail(addr, auth, p.config.From, p.config.To, msg); err != nil {
+		return fmt.Errorf("failed to send email: %w", err)
+	}
+
+	log.Println("Email sent successfully")
+	return nil
+}
+
+// buildMessage assembles a multipart/alternative MIME message
+func buildMessage(from string, to []string, subject, textBody, htmlBody string) ([]byte, error) {
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+
+	for _, part := range []struct {
+		contentType string
+		content     string
+	}{
+		{"text/plain; charset=UTF-8", textBody},
+		{"text/html; charset=UTF-8", htmlBody},
+	} {
+		header := textproto.MIMEHeader{}
+		header.Set("Content-Type", part.contentType)
+		header.Set("Content-Transfer-Encoding", "8bit")
+		w, err := writer.CreatePart(header)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := w.Write([]byte(part.content)); err != nil {
+			return nil, err
+		}
+	}
+	if err := writer.Close(); err != nil {
+		return nil, err
+	}
+
+	var msg bytes.Buffer
+	fmt.Fprintf(&msg, "From: %s\r\n", from)
+	fmt.Fprintf(&msg, "To: %s\r\n", strings.Join(to, ", "))
+	fmt.Fprintf(&msg, "Subject: %s\r\n", mime.QEncoding.Encode("UTF-8", subject))
+	msg.WriteString("MIME-Version: 1.0\r\n")
+	fmt.Fprintf(&msg, "Content-Type: multipart/alternative; boundary=%q\r\n", writer.Boundary())
+	msg.WriteString("\r\n")
+	msg.Write(body.Bytes())
+
+	return msg.Bytes(), nil
+}
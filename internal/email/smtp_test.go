@@ -0,0 +1,117 @@
+package email
+
+import (
+	"net/smtp"
+	"strings"
+	"testing"
+
+	"github.com/sathyabhat/ynab-weekly-wrap/internal/config"
+	"github.com/sathyabhat/ynab-weekly-wrap/internal/processor"
+	"github.com/sathyabhat/ynab-weekly-wrap/internal/publisher"
+	"github.com/sathyabhat/ynab-weekly-wrap/internal/theme"
+)
+
+type capturedMail struct {
+	addr string
+	auth smtp.Auth
+	from string
+	to   []string
+	msg  string
+}
+
+func newTestPublisher(captured *capturedMail) *SMTPPublisher {
+	p := NewSMTPPublisher(config.EmailConfig{
+		Host:     "smtp.example.com",
+		Port:     587,
+		Username: "wrap@example.com",
+		Password: "secret",
+		From:     "wrap@example.com",
+		To:       []string{"alex@example.com", "sam@example.com"},
+	})
+	p.sendMail = func(addr string, a smtp.Auth, from string, to []string, msg []byte) error {
+		*captured = capturedMail{addr: addr, auth: a, from: from, to: to, msg: string(msg)}
+		return nil
+	}
+	return p
+}
+
+func TestPublishReport_SendsHTMLWithPlaintextFallback(t *testing.T) {
+	var captured capturedMail
+	p := newTestPublisher(&captured)
+
+	report := publisher.Report{
+		Title:   "Weekly Financial Wrap - 2026-01-19 to 2026-01-26",
+		Message: "💰 **Total Spent**: $550",
+		Analysis: &processor.AnalysisResult{
+			Overview: &processor.Overview{TotalSpent: 550_000},
+			TopSpending: []processor.TopSpendingCategory{
+				{Category: "Groceries", Spent: 200_000, Balance: 300_000},
+			},
+			Concerns: []processor.CategoryConcernWithTransactions{
+				{Category: "Dining <Out>", Spent: 350_000, Balance: -50_000},
+			},
+		},
+	}
+
+	if err := p.PublishReport(report); err != nil {
+		t.Fatalf("PublishReport failed: %v", err)
+	}
+
+	if captured.addr != "smtp.example.com:587" {
+		t.Errorf("addr: got %q, want smtp.example.com:587", captured.addr)
+	}
+	if captured.auth == nil {
+		t.Error("expected SMTP auth when username is configured")
+	}
+	if len(captured.to) != 2 {
+		t.Errorf("recipients: got %v, want 2", captured.to)
+	}
+
+	for _, want := range []string{
+		"Subject: Weekly Financial Wrap - 2026-01-19 to 2026-01-26",
+		"Content-Type: multipart/alternative",
+		"Content-Type: text/plain; charset=UTF-8",
+		"💰 Total Spent: $550",
+		"Content-Type: text/html; charset=UTF-8",
+		"<td>Groceries</td><td>$200</td>",
+		"Dining &lt;Out&gt;",
+	} {
+		if !strings.Contains(captured.msg, want) {
+			t.Errorf("email missing %q:\n%s", want, captured.msg)
+		}
+	}
+}
+
+func TestReportHTML_PlainTheme(t *testing.T) {
+	plain, _ := theme.New("plain", nil)
+	report := publisher.Report{
+		Title:    "Weekly Financial Wrap",
+		Analysis: &processor.AnalysisResult{Overview: &processor.Overview{TotalSpent: 550_000}},
+	}
+
+	html, err := reportHTML(report, plain)
+	if err != nil {
+		t.Fatalf("reportHTML failed: %v", err)
+	}
+	for _, want := range []string{"<h2>Weekly Financial Wrap</h2>", "<h3>Over Budget Categories</h3>", "great job!</p>"} {
+		if !strings.Contains(html, want) {
+			t.Errorf("expected %q without emoji, got:\n%s", want, html)
+		}
+	}
+}
+
+func TestPublish_ConvertsMarkdownToHTML(t *testing.T) {
+	var captured capturedMail
+	p := newTestPublisher(&captured)
+
+	if err := p.Publish("🚨 **Critical Budget Alert**\n<test>"); err != nil {
+		t.Fatalf("Publish failed: %v", err)
+	}
+
+	if !strings.Contains(captured.msg, "<b>Critical Budget Alert</b><br>") {
+		t.Errorf("expected bold converted to HTML, got:\n%s", captured.msg)
+	}
+	if !strings.Contains(captured.msg, "&lt;test&gt;") {
+		t.Errorf("expected HTML to be escaped, got:\n%s", captured.msg)
+	}
+}
@@ -0,0 +1,122 @@
+package fixtures
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"math"
+
+	"github.com/sathyabhat/ynab-weekly-wrap/internal/ynab"
+)
+
+// Anonymizer scrubs identifying details from a fixture. The same seed always
+// maps a value to the same replacement, so a payee or account stays
+// recognisable across transactions and across captures without revealing
+// the original. Category names are kept because the analysis depends on them.
+type Anonymizer struct {
+	seed  string
+	scale float64
+}
+
+// NewAnonymizer creates an anonymizer that hashes with seed and multiplies
+// every amount by scale
+func NewAnonymizer(seed string, scale float64) *Anonymizer {
+	return &Anonymizer{seed: seed, scale: scale}
+}
+
+// Anonymize rewrites the fixture in place
+func (a *Anonymizer) Anonymize(f *Fixture) {
+	f.Anonymized = true
+
+	if f.Weekly != nil {
+		if budget := f.Weekly.Budget; budget != nil {
+			budget.ID = a.id(budget.ID)
+			budget.Name = a.label("Budget", budget.Name)
+		}
+		for i := range f.Weekly.Categories {
+			a.category(&f.Weekly.Categories[i])
+		}
+		a.transactions(f.Weekly.Transactions)
+		a.transactions(f.Weekly.MonthToDate)
+	}
+	a.transactions(f.PrevWeek)
+
+	for i := range f.Accounts {
+		account := &f.Accounts[i]
+		account.ID = a.id(account.ID)
+		account.Name = a.label("Account", account.Name)
+		account.Balance = a.amount(account.Balance)
+		account.ClearedBalance = a.amount(account.ClearedBalance)
+		account.UnclearedBalance = a.amount(account.UnclearedBalance)
+	}
+
+	for i := range f.Scheduled {
+		st := &f.Scheduled[i]
+		st.ID = a.id(st.ID)
+		st.AccountID = a.id(st.AccountID)
+		st.AccountName = a.label("Account", st.AccountName)
+		st.PayeeName = a.label("Payee", st.PayeeName)
+		st.TransferAccountID = a.idPtr(st.TransferAccountID)
+		st.Amount = a.amount(st.Amount)
+	}
+}
+
+func (a *Anonymizer) category(c *ynab.Category) {
+	c.ID = a.id(c.ID)
+	c.CategoryGroupID = a.id(c.CategoryGroupID)
+	c.CategoryGroup.ID = a.id(c.CategoryGroup.ID)
+	c.Budgeted = a.amount(c.Budgeted)
+	c.Activity = a.amount(c.Activity)
+	c.Balance = a.amount(c.Balance)
+	c.GoalTarget = a.amount(c.GoalTarget)
+}
+
+func (a *Anonymizer) transactions(transactions []ynab.Transaction) {
+	for i := range transactions {
+		tx := &transactions[i]
+		tx.ID = a.id(tx.ID)
+		tx.AccountID = a.id(tx.AccountID)
+		tx.AccountName = a.label("Account", tx.AccountName)
+		tx.PayeeID = a.idPtr(tx.PayeeID)
+		tx.PayeeName = a.label("Payee", tx.PayeeName)
+		tx.CategoryID = a.idPtr(tx.CategoryID)
+		tx.TransferAccountID = a.idPtr(tx.TransferAccountID)
+		tx.Memo = a.label("Memo", tx.Memo)
+		tx.Amount = a.amount(tx.Amount)
+	}
+}
+
+func (a *Anonymizer) hash(value string) string {
+	sum := sha256.Sum256([]byte(a.seed + "\x00" + value))
+	return hex.EncodeToString(sum[:])
+}
+
+// id replaces an ID with a UUID-shaped hash so it still looks like a YNAB ID
+func (a *Anonymizer) id(value string) string {
+	if value == "" {
+		return ""
+	}
+	h := a.hash(value)
+	return fmt.Sprintf("%s-%s-%s-%s-%s", h[0:8], h[8:12], h[12:16], h[16:20], h[20:32])
+}
+
+func (a *Anonymizer) idPtr(value *string) *string {
+	if value == nil {
+		return nil
+	}
+	id := a.id(*value)
+	return &id
+}
+
+// label replaces free text with e.g. "Payee 3fa2c1", keeping blanks blank
+func (a *Anonymizer) label(prefix, value string) string {
+	if value == "" {
+		return ""
+	}
+	return prefix + " " + a.hash(value)[:6]
+}
+
+// amount scales a milliunit amount, rounded to whole cents
+func (a *Anonymizer) amount(milliunits int64) int64 {
+	return int64(math.Round(float64(milliunits)*a.scale/10)) * 10
+}
@@ -0,0 +1,106 @@
+package fixtures
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/sathyabhat/ynab-weekly-wrap/internal/ynab"
+)
+
+func strPtr(s string) *string { return &s }
+
+func sampleFixture() *Fixture {
+	date := time.Date(2026, 3, 5, 0, 0, 0, 0, time.UTC)
+	return &Fixture{
+		Weekly: &ynab.WeeklyData{
+			Budget: &ynab.Budget{ID: "budget-1", Name: "Family Budget"},
+			Categories: []ynab.Category{
+				{ID: "cat-1", Name: "Groceries", Budgeted: 500_000, Activity: -123_456, GoalTarget: 600_000},
+			},
+			Transactions: []ynab.Transaction{
+				{ID: "tx-1", Date: &date, Amount: -45_670, Memo: "birthday cake", PayeeID: strPtr("payee-1"), PayeeName: "Corner Bakery", CategoryID: strPtr("cat-1"), CategoryName: "Groceries"},
+				{ID: "tx-2", Date: &date, Amount: -12_000, PayeeID: strPtr("payee-1"), PayeeName: "Corner Bakery", CategoryID: strPtr("cat-1"), CategoryName: "Groceries"},
+				{ID: "tx-3", Date: &date, Amount: -100_000, AccountID: "acct-2", TransferAccountID: strPtr("acct-1")},
+			},
+			WeekStart: date.AddDate(0, 0, -4),
+			WeekEnd:   date.AddDate(0, 0, 3),
+		},
+		Accounts: []ynab.Account{{ID: "acct-1", Name: "Joint Checking", Balance: 2_500_000}},
+	}
+}
+
+func TestAnonymize_ScrubsDeterministically(t *testing.T) {
+	f := sampleFixture()
+	NewAnonymizer("seed", 0.5).Anonymize(f)
+
+	tx := f.Weekly.Transactions
+	if tx[0].PayeeName == "Corner Bakery" || tx[0].Memo == "birthday cake" || tx[0].ID == "tx-1" {
+		t.Errorf("expected payee, memo and ID to be scrubbed, got %+v", tx[0])
+	}
+	if tx[0].PayeeName != tx[1].PayeeName || *tx[0].PayeeID != *tx[1].PayeeID {
+		t.Errorf("expected the same payee to map to the same replacement, got %q and %q", tx[0].PayeeName, tx[1].PayeeName)
+	}
+	if tx[1].Memo != "" {
+		t.Errorf("expected blank memo to stay blank, got %q", tx[1].Memo)
+	}
+	if *tx[0].CategoryID != f.Weekly.Categories[0].ID {
+		t.Errorf("expected category IDs to stay linked, got %q and %q", *tx[0].CategoryID, f.Weekly.Categories[0].ID)
+	}
+	if f.Weekly.Categories[0].Name != "Groceries" {
+		t.Errorf("expected category names to be kept, got %q", f.Weekly.Categories[0].Name)
+	}
+	if f.Weekly.Budget.Name == "Family Budget" || f.Accounts[0].Name == "Joint Checking" {
+		t.Errorf("expected budget and account names to be scrubbed")
+	}
+
+	// -45.670 * 0.5 = -22.835, rounded to the cent
+	if tx[0].Amount != -22_840 {
+		t.Errorf("expected scaled amount -22840, got %d", tx[0].Amount)
+	}
+	if f.Accounts[0].Balance != 1_250_000 {
+		t.Errorf("expected scaled balance 1250000, got %d", f.Accounts[0].Balance)
+	}
+	if f.Weekly.Categories[0].GoalTarget != 300_000 {
+		t.Errorf("expected scaled goal target 300000, got %d", f.Weekly.Categories[0].GoalTarget)
+	}
+	if *tx[2].TransferAccountID != f.Accounts[0].ID {
+		t.Errorf("expected transfer account IDs to stay linked to the accounts, got %q and %q", *tx[2].TransferAccountID, f.Accounts[0].ID)
+	}
+
+	again := sampleFixture()
+	NewAnonymizer("seed", 0.5).Anonymize(again)
+	if again.Weekly.Transactions[0].PayeeName != tx[0].PayeeName {
+		t.Errorf("expected the same seed to give the same output")
+	}
+
+	other := sampleFixture()
+	NewAnonymizer("other", 0.5).Anonymize(other)
+	if other.Weekly.Transactions[0].PayeeName == tx[0].PayeeName {
+		t.Errorf("expected a different seed to give different output")
+	}
+}
+
+func TestSaveLoad_RoundTrip(t *testing.T) {
+	f := sampleFixture()
+	NewAnonymizer("seed", 1).Anonymize(f)
+
+	path, err := Save(f, t.TempDir())
+	if err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+	if filepath.Base(path) != "weekly-2026-03-08.json" {
+		t.Errorf("unexpected fixture file name %q", filepath.Base(path))
+	}
+
+	loaded, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if !loaded.Anonymized || len(loaded.Weekly.Transactions) != 3 {
+		t.Errorf("unexpected loaded fixture: %+v", loaded)
+	}
+	if loaded.Weekly.Transactions[0].Amount != f.Weekly.Transactions[0].Amount {
+		t.Errorf("amount did not round-trip: got %d, want %d", loaded.Weekly.Transactions[0].Amount, f.Weekly.Transactions[0].Amount)
+	}
+}
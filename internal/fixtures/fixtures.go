@@ -0,0 +1,104 @@
+package fixtures
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/sathyabhat/ynab-weekly-wrap/internal/ynab"
+)
+
+// Fixture is a captured week of budget data that can be replayed through the
+// analyzer without talking to YNAB
+type Fixture struct {
+	CapturedAt time.Time                   `json:"captured_at"`
+	Anonymized bool                        `json:"anonymized"`
+	Weekly     *ynab.WeeklyData            `json:"weekly"`
+	PrevWeek   []ynab.Transaction          `json:"prev_week"` // Transactions from the week before, for comparisons
+	Accounts   []ynab.Account              `json:"accounts"`
+	Scheduled  []ynab.ScheduledTransaction `json:"scheduled"`
+}
+
+// Source is the subset of the YNAB client needed to capture a fixture
+type Source interface {
+	GetWeeklyData(weekStart, weekEnd time.Time) (*ynab.WeeklyData, error)
+	GetTransactions(start, end time.Time) ([]ynab.Transaction, error)
+	GetAccounts() ([]ynab.Account, error)
+	GetScheduledTransactions() ([]ynab.ScheduledTransaction, error)
+}
+
+// Capture pulls the week ending at weekEnd, plus the week before it and the
+// current accounts and scheduled transactions
+func Capture(source Source, weekEnd time.Time) (*Fixture, error) {
+	weekStart := weekEnd.AddDate(0, 0, -7)
+
+	weekly, err := source.GetWeeklyData(weekStart, weekEnd)
+	if err != nil {
+		return nil, fmt.Errorf("failed to capture weekly data: %w", err)
+	}
+
+	prevWeek, err := source.GetTransactions(weekStart.AddDate(0, 0, -7), weekStart)
+	if err != nil {
+		return nil, fmt.Errorf("failed to capture previous week: %w", err)
+	}
+
+	accounts, err := source.GetAccounts()
+	if err != nil {
+		return nil, fmt.Errorf("failed to capture accounts: %w", err)
+	}
+
+	scheduled, err := source.GetScheduledTransactions()
+	if err != nil {
+		return nil, fmt.Errorf("failed to capture scheduled transactions: %w", err)
+	}
+
+	return &Fixture{
+		CapturedAt: time.Now().UTC(),
+		Weekly:     weekly,
+		PrevWeek:   prevWeek,
+		Accounts:   accounts,
+		Scheduled:  scheduled,
+	}, nil
+}
+
+// FileName is the name a fixture is saved under, e.g. weekly-2026-03-08.json
+func (f *Fixture) FileName() string {
+	return fmt.Sprintf("weekly-%s.json", f.Weekly.WeekEnd.Format("2006-01-02"))
+}
+
+// Save writes the fixture as indented JSON into dir and returns its path
+func Save(f *Fixture, dir string) (string, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", fmt.Errorf("failed to create fixture directory: %w", err)
+	}
+
+	data, err := json.MarshalIndent(f, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal fixture: %w", err)
+	}
+
+	path := filepath.Join(dir, f.FileName())
+	if err := os.WriteFile(path, append(data, '\n'), 0o644); err != nil {
+		return "", fmt.Errorf("failed to write fixture: %w", err)
+	}
+	return path, nil
+}
+
+// Load reads a fixture written by Save
+func Load(path string) (*Fixture, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read fixture: %w", err)
+	}
+
+	var f Fixture
+	if err := json.Unmarshal(data, &f); err != nil {
+		return nil, fmt.Errorf("failed to parse fixture %s: %w", path, err)
+	}
+	if f.Weekly == nil {
+		return nil, fmt.Errorf("fixture %s has no weekly data", path)
+	}
+	return &f, nil
+}
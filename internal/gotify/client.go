@@ -0,0 +1,115 @@
+package gotify
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/sathyabhat/ynab-weekly-wrap/internal/config"
+	"github.com/sathyabhat/ynab-weekly-wrap/internal/processor"
+	"github.com/sathyabhat/ynab-weekly-wrap/internal/publisher"
+)
+
+// Gotify priorities: clients typically stay silent below 4 and alert loudly from 8
+const (
+	PriorityLow    = 2
+	PriorityNormal = 5
+	PriorityHigh   = 8
+)
+
+// Publisher implements the publisher.Publisher interface for a self-hosted
+// Gotify server
+type Publisher struct {
+	config config.GotifyConfig
+}
+
+// Message is the body of a Gotify message
+type Message struct {
+	Title    string         `json:"title,omitempty"`
+	Message  string         `json:"message"`
+	Priority int            `json:"priority"`
+	Extras   map[string]any `json:"extras,omitempty"`
+}
+
+// NewPublisher creates a new Gotify publisher
+func NewPublisher(gotifyConfig config.GotifyConfig) *Publisher {
+	return &Publisher{config: gotifyConfig}
+}
+
+// Name identifies the destination in delivery summaries
+func (p *Publisher) Name() string {
+	return "gotify"
+}
+
+// Publish sends a plain message. Messages without an analysis are alerts and
+// failure notices, so they go out with high priority.
+func (p *Publisher) Publish(message string) error {
+	return p.send("", message, PriorityHigh)
+}
+
+// PublishReport sends the wrap with a priority based on how far over budget
+// the worst category is
+func (p *Publisher) PublishReport(report publisher.Report) error {
+	priority := PriorityHigh
+	if report.Analysis != nil {
+		priority = Priority(report.Analysis)
+	}
+	return p.send(report.Title, report.Message, priority)
+}
+
+// Priority maps over-budget severity onto a Gotify priority: nothing over
+// budget is low, anything over is normal, and a category the wrap rates
+// severe is high
+func Priority(analysis *processor.AnalysisResult) int {
+	if len(analysis.Concerns) == 0 {
+		return PriorityLow
+	}
+	for _, concern := range analysis.Concerns {
+		if concern.Severity == processor.SeveritySevere {
+			return PriorityHigh
+		}
+	}
+	return PriorityNormal
+}
+
+func (p *Publisher) send(title, message string, priority int) error {
+	log.Printf("Sending message to Gotify with priority %d", priority)
+
+	body, err := json.Marshal(Message{
+		Title:    title,
+		Message:  message,
+		Priority: priority,
+		// The chat formatters use markdown bold, which Gotify clients can render
+		Extras: map[string]any{
+			"client::display": map[string]string{"contentType": "text/markdown"},
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to marshal Gotify message: %w", err)
+	}
+
+	req, err := http.NewRequest(http.MethodPost, strings.TrimRight(p.config.URL, "/")+"/message", bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create Gotify request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Gotify-Key", p.config.Token)
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send Gotify message: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		respBody, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("gotify API error (status %d): %s", resp.StatusCode, string(respBody))
+	}
+
+	log.Println("Gotify message sent successfully")
+	return nil
+}
@@ -0,0 +1,85 @@
+package gotify
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/sathyabhat/ynab-weekly-wrap/internal/config"
+	"github.com/sathyabhat/ynab-weekly-wrap/internal/processor"
+	"github.com/sathyabhat/ynab-weekly-wrap/internal/publisher"
+)
+
+func TestPublishReport_SendsWithPriority(t *testing.T) {
+	var got Message
+	var path, token string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path = r.URL.Path
+		token = r.Header.Get("X-Gotify-Key")
+		if err := json.NewDecoder(r.Body).Decode(&got); err != nil {
+			t.Errorf("Failed to decode request body: %v", err)
+		}
+		w.Write([]byte(`{"id":1}`))
+	}))
+	defer server.Close()
+
+	p := NewPublisher(config.GotifyConfig{URL: server.URL + "/", Token: "app-token"})
+	err := p.PublishReport(publisher.Report{
+		Title:   "Weekly Financial Wrap",
+		Message: "📊 **Weekly Financial Wrap**",
+		Analysis: &processor.AnalysisResult{
+			Concerns: []processor.CategoryConcernWithTransactions{
+				{Category: "Dining", Budgeted: 300_000, Over: 20_000, Severity: processor.SeveritySlight},
+			},
+		},
+	})
+	if err != nil {
+		t.Fatalf("PublishReport failed: %v", err)
+	}
+
+	if path != "/message" || token != "app-token" {
+		t.Errorf("unexpected request: path %q token %q", path, token)
+	}
+	if got.Title != "Weekly Financial Wrap" || got.Priority != PriorityNormal {
+		t.Errorf("unexpected message: %+v", got)
+	}
+	if got.Extras["client::display"] == nil {
+		t.Errorf("expected markdown display extras, got %+v", got.Extras)
+	}
+}
+
+func TestPriority(t *testing.T) {
+	cases := []struct {
+		name     string
+		concerns []processor.CategoryConcernWithTransactions
+		want     int
+	}{
+		{"nothing over budget", nil, PriorityLow},
+		{"slightly over", []processor.CategoryConcernWithTransactions{{Severity: processor.SeveritySlight}}, PriorityNormal},
+		{"moderately over", []processor.CategoryConcernWithTransactions{{Severity: processor.SeverityModerate}}, PriorityNormal},
+		{"severely over", []processor.CategoryConcernWithTransactions{
+			{Severity: processor.SeverityModerate},
+			{Severity: processor.SeveritySevere},
+		}, PriorityHigh},
+	}
+
+	for _, tc := range cases {
+		got := Priority(&processor.AnalysisResult{Concerns: tc.concerns})
+		if got != tc.want {
+			t.Errorf("%s: got priority %d, want %d", tc.name, got, tc.want)
+		}
+	}
+}
+
+func TestPublish_Error(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+		w.Write([]byte(`{"error":"Unauthorized"}`))
+	}))
+	defer server.Close()
+
+	if err := NewPublisher(config.GotifyConfig{URL: server.URL, Token: "bad"}).Publish("alert"); err == nil {
+		t.Fatal("expected error, got nil")
+	}
+}
@@ -0,0 +1,79 @@
+package history
+
+import (
+	"sort"
+
+	"github.com/sathyabhat/ynab-weekly-wrap/internal/ynab"
+)
+
+// CategoryDiff is a category whose budgeted, activity or balance figure
+// differs between two snapshots. Before is nil for a category only in the
+// later snapshot, After for one only in the earlier.
+type CategoryDiff struct {
+	Before *ynab.Category
+	After  *ynab.Category
+}
+
+// Name returns the category's name in the later snapshot, or in the earlier
+// one when it's gone
+func (d CategoryDiff) Name() string {
+	if d.After != nil {
+		return d.After.Name
+	}
+	return d.Before.Name
+}
+
+// Renamed reports whether the category's name changed between the snapshots
+func (d CategoryDiff) Renamed() bool {
+	return d.Before != nil && d.After != nil && d.Before.Name != d.After.Name
+}
+
+// DiffSnapshots lists the categories whose figures differ between two
+// snapshots, to explain why a wrap's numbers differ from another week's or
+// from YNAB. Categories are matched by ID, so renames line up, and listed
+// by their later name.
+func DiffSnapshots(before, after *Snapshot) []CategoryDiff {
+	byID := make(map[string]*CategoryDiff)
+	var ids []string
+	add := func(snapshot *Snapshot, later bool) {
+		if snapshot.Weekly == nil {
+			return
+		}
+		for i := range snapshot.Weekly.Categories {
+			cat := &snapshot.Weekly.Categories[i]
+			id := cat.ID
+			if id == "" {
+				id = cat.Name
+			}
+			diff, ok := byID[id]
+			if !ok {
+				diff = &CategoryDiff{}
+				byID[id] = diff
+				ids = append(ids, id)
+			}
+			if later {
+				diff.After = cat
+			} else {
+				diff.Before = cat
+			}
+		}
+	}
+	add(before, false)
+	add(after, true)
+
+	var diffs []CategoryDiff
+	for _, id := range ids {
+		diff := *byID[id]
+		if diff.Before != nil && diff.After != nil && !diff.Renamed() &&
+			diff.Before.Budgeted == diff.After.Budgeted &&
+			diff.Before.Activity == diff.After.Activity &&
+			diff.Before.Balance == diff.After.Balance {
+			continue
+		}
+		diffs = append(diffs, diff)
+	}
+	sort.SliceStable(diffs, func(i, j int) bool {
+		return diffs[i].Name() < diffs[j].Name()
+	})
+	return diffs
+}
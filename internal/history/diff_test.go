@@ -0,0 +1,44 @@
+package history
+
+import (
+	"testing"
+
+	"github.com/sathyabhat/ynab-weekly-wrap/internal/ynab"
+)
+
+func TestDiffSnapshots(t *testing.T) {
+	snapshot := func(categories ...ynab.Category) *Snapshot {
+		return &Snapshot{Weekly: &ynab.WeeklyData{Categories: categories}}
+	}
+	before := snapshot(
+		ynab.Category{ID: "c1", Name: "Groceries", Budgeted: 500_000, Activity: -200_000, Balance: 300_000},
+		ynab.Category{ID: "c2", Name: "Eating Out", Budgeted: 200_000, Activity: -50_000, Balance: 150_000},
+		ynab.Category{ID: "c3", Name: "Rent", Budgeted: 2_000_000, Activity: -2_000_000},
+		ynab.Category{ID: "c4", Name: "Gym", Budgeted: 60_000, Balance: 60_000},
+	)
+	after := snapshot(
+		ynab.Category{ID: "c1", Name: "Groceries", Budgeted: 500_000, Activity: -320_000, Balance: 180_000},
+		ynab.Category{ID: "c2", Name: "Dining", Budgeted: 200_000, Activity: -50_000, Balance: 150_000},
+		ynab.Category{ID: "c3", Name: "Rent", Budgeted: 2_000_000, Activity: -2_000_000},
+		ynab.Category{ID: "c5", Name: "Vacation", Budgeted: 100_000, Balance: 100_000},
+	)
+
+	diffs := DiffSnapshots(before, after)
+	var names []string
+	for _, diff := range diffs {
+		names = append(names, diff.Name())
+	}
+	if want := []string{"Dining", "Groceries", "Gym", "Vacation"}; len(names) != len(want) ||
+		names[0] != want[0] || names[1] != want[1] || names[2] != want[2] || names[3] != want[3] {
+		t.Fatalf("got %v, want %v", names, want)
+	}
+	if !diffs[0].Renamed() || diffs[0].Before.Name != "Eating Out" {
+		t.Errorf("expected Dining renamed from Eating Out, got %+v", diffs[0])
+	}
+	if diffs[1].Before.Activity != -200_000 || diffs[1].After.Activity != -320_000 {
+		t.Errorf("expected Groceries' activity to differ, got %+v", diffs[1])
+	}
+	if diffs[2].After != nil || diffs[3].Before != nil {
+		t.Errorf("expected Gym only before and Vacation only after, got %+v and %+v", diffs[2], diffs[3])
+	}
+}
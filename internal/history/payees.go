@@ -0,0 +1,149 @@
+package history
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/sathyabhat/ynab-weekly-wrap/internal/processor"
+	"github.com/sathyabhat/ynab-weekly-wrap/internal/ynab"
+)
+
+// PayeeIndex is the set of payees paid in stored snapshots over a window
+type PayeeIndex struct {
+	Payees map[string]bool // Keyed by processor.PayeeKey
+	// Earliest is the start of the oldest snapshot read, clamped to the
+	// window, or zero when no snapshot overlaps it
+	Earliest time.Time
+}
+
+// Payees indexes the payees of every stored transaction dated from since up
+// to, but not including, until
+func (s *Store) Payees(since, until time.Time) (*PayeeIndex, error) {
+	index := &PayeeIndex{Payees: make(map[string]bool)}
+	earliest, err := s.scan(since, until, func(_ *Snapshot, tx ynab.Transaction) {
+		index.Payees[processor.PayeeKey(tx.PayeeName)] = true
+	})
+	if err != nil {
+		return nil, err
+	}
+	index.Earliest = earliest
+	return index, nil
+}
+
+// OutflowIndex is the amounts spent per category in stored snapshots over a
+// window
+type OutflowIndex struct {
+	Outflows map[string][]int64 // Category ID -> positive outflow amounts
+	// Earliest is as for PayeeIndex
+	Earliest time.Time
+}
+
+// Outflows collects the outflows of every stored, categorized transaction
+// dated from since up to, but not including, until. Keying by category ID
+// keeps a category's history across renames.
+func (s *Store) Outflows(since, until time.Time) (*OutflowIndex, error) {
+	index := &OutflowIndex{Outflows: make(map[string][]int64)}
+	earliest, err := s.scan(since, until, func(_ *Snapshot, tx ynab.Transaction) {
+		if tx.Amount >= 0 || tx.CategoryID == nil || tx.TransferAccountID != nil {
+			return
+		}
+		index.Outflows[*tx.CategoryID] = append(index.Outflows[*tx.CategoryID], -tx.Amount)
+	})
+	if err != nil {
+		return nil, err
+	}
+	index.Earliest = earliest
+	return index, nil
+}
+
+// WeekdayIndex is the amounts spent per day of the week in stored snapshots
+// over a window
+type WeekdayIndex struct {
+	Outflows [7]int64 // By time.Weekday
+	// Overspent is the part of Outflows in categories that were overspent
+	// when the week's snapshot was taken
+	Overspent [7]int64
+	// Earliest is as for PayeeIndex
+	Earliest time.Time
+}
+
+// Weekdays adds up the outflows of every stored, categorized transaction
+// dated from since up to, but not including, until by the day of the week
+// it was made. YNAB records only the date of a transaction, not the time.
+func (s *Store) Weekdays(since, until time.Time) (*WeekdayIndex, error) {
+	index := &WeekdayIndex{}
+	var current *Snapshot
+	var overspent map[string]bool // Category ID -> overspent, for current
+	earliest, err := s.scan(since, until, func(snapshot *Snapshot, tx ynab.Transaction) {
+		if tx.Amount >= 0 || tx.CategoryID == nil || tx.TransferAccountID != nil {
+			return
+		}
+		if snapshot != current {
+			current, overspent = snapshot, make(map[string]bool)
+			for _, cat := range snapshot.Weekly.Categories {
+				if cat.Balance < 0 {
+					overspent[cat.ID] = true
+				}
+			}
+		}
+		day := tx.Date.Weekday()
+		index.Outflows[day] += -tx.Amount
+		if overspent[*tx.CategoryID] {
+			index.Overspent[day] += -tx.Amount
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+	index.Earliest = earliest
+	return index, nil
+}
+
+// scan calls visit with every stored transaction dated from since up to, but
+// not including, until, and the snapshot holding it, and returns the start of
+// the oldest snapshot read, clamped to the window
+func (s *Store) scan(since, until time.Time, visit func(*Snapshot, ynab.Transaction)) (time.Time, error) {
+	var earliest time.Time
+
+	paths, err := filepath.Glob(filepath.Join(s.dir, "weekly", "*.json"))
+	if err != nil {
+		return time.Time{}, fmt.Errorf("failed to list snapshots: %w", err)
+	}
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return time.Time{}, fmt.Errorf("failed to read snapshot: %w", err)
+		}
+
+		var snapshot Snapshot
+		if err := json.Unmarshal(data, &snapshot); err != nil {
+			return time.Time{}, fmt.Errorf("failed to parse snapshot %s: %w", filepath.Base(path), err)
+		}
+		if snapshot.Weekly == nil || !snapshot.PeriodEnd.After(since) || !snapshot.PeriodStart.Before(until) {
+			continue
+		}
+
+		start := snapshot.PeriodStart
+		if start.Before(since) {
+			start = since
+		}
+		if earliest.IsZero() || start.Before(earliest) {
+			earliest = start
+		}
+
+		for _, tx := range snapshot.Weekly.Transactions {
+			if tx.Deleted || tx.Date == nil || tx.Date.Before(since) || !tx.Date.Before(until) {
+				continue
+			}
+			visit(&snapshot, tx)
+		}
+	}
+	return earliest, nil
+}
@@ -0,0 +1,138 @@
+package history
+
+import (
+	"testing"
+	"time"
+
+	"github.com/sathyabhat/ynab-weekly-wrap/internal/ynab"
+)
+
+func saveWeek(t *testing.T, store *Store, end time.Time, payees ...string) {
+	t.Helper()
+	var transactions []ynab.Transaction
+	for i, payee := range payees {
+		date := end.AddDate(0, 0, -i-1)
+		transactions = append(transactions, ynab.Transaction{Date: &date, PayeeName: payee, Amount: -10_000})
+	}
+	snapshot := &Snapshot{
+		Week:        WeekOf(end),
+		PeriodStart: end.AddDate(0, 0, -7),
+		PeriodEnd:   end,
+		Weekly:      &ynab.WeeklyData{Transactions: transactions},
+	}
+	if err := store.Save(snapshot); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+}
+
+func TestStore_Payees(t *testing.T) {
+	store := NewStore(t.TempDir())
+	end := time.Date(2024, 3, 24, 9, 0, 0, 0, time.UTC)
+	saveWeek(t, store, end.AddDate(0, -8, 0), "Old Gym")
+	saveWeek(t, store, end.AddDate(0, 0, -14), "Countdown", "Netflix")
+	saveWeek(t, store, end.AddDate(0, 0, -7), "COUNTDOWN")
+	saveWeek(t, store, end, "Bright Dental")
+
+	since, until := end.AddDate(0, -6, -7), end.AddDate(0, 0, -7)
+	index, err := store.Payees(since, until)
+	if err != nil {
+		t.Fatalf("Payees failed: %v", err)
+	}
+
+	for _, payee := range []string{"countdown", "netflix"} {
+		if !index.Payees[payee] {
+			t.Errorf("expected %q in the index, got %v", payee, index.Payees)
+		}
+	}
+	for _, payee := range []string{"old gym", "bright dental"} {
+		if index.Payees[payee] {
+			t.Errorf("expected %q outside the window, got %v", payee, index.Payees)
+		}
+	}
+	if want := end.AddDate(0, 0, -21); !index.Earliest.Equal(want) {
+		t.Errorf("Earliest: got %s, want %s", index.Earliest, want)
+	}
+}
+
+func TestStore_PayeesWithoutHistory(t *testing.T) {
+	index, err := NewStore(t.TempDir()).Payees(time.Now().AddDate(0, -6, 0), time.Now())
+	if err != nil {
+		t.Fatalf("Payees failed: %v", err)
+	}
+	if len(index.Payees) != 0 || !index.Earliest.IsZero() {
+		t.Errorf("expected an empty index, got %+v", index)
+	}
+}
+
+func TestStore_Outflows(t *testing.T) {
+	store := NewStore(t.TempDir())
+	end := time.Date(2024, 3, 24, 9, 0, 0, 0, time.UTC)
+	groceries, transferTo := "c1", "a2"
+	date := end.AddDate(0, 0, -10)
+	snapshot := &Snapshot{
+		Week:        WeekOf(end.AddDate(0, 0, -7)),
+		PeriodStart: end.AddDate(0, 0, -14),
+		PeriodEnd:   end.AddDate(0, 0, -7),
+		Weekly: &ynab.WeeklyData{Transactions: []ynab.Transaction{
+			{Date: &date, Amount: -80_000, CategoryID: &groceries},
+			{Date: &date, Amount: -60_000, CategoryID: &groceries},
+			{Date: &date, Amount: 20_000, CategoryID: &groceries},                                     // Refund
+			{Date: &date, Amount: -500_000, TransferAccountID: &transferTo},                           // Transfer
+			{Date: &date, Amount: -30_000, CategoryID: &groceries, Deleted: true},                     // Deleted
+			{Date: &date, Amount: -15_000, PayeeName: "Uncategorized", CategoryName: "Uncategorized"}, // No ID
+		}},
+	}
+	if err := store.Save(snapshot); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+
+	index, err := store.Outflows(end.AddDate(0, -6, 0), end)
+	if err != nil {
+		t.Fatalf("Outflows failed: %v", err)
+	}
+	if got := index.Outflows["c1"]; len(got) != 2 || got[0] != 80_000 || got[1] != 60_000 {
+		t.Errorf("Outflows[c1]: got %v, want [80000 60000]", got)
+	}
+	if len(index.Outflows) != 1 {
+		t.Errorf("expected only c1 indexed, got %v", index.Outflows)
+	}
+	if want := end.AddDate(0, 0, -14); !index.Earliest.Equal(want) {
+		t.Errorf("Earliest: got %s, want %s", index.Earliest, want)
+	}
+}
+
+func TestStore_Weekdays(t *testing.T) {
+	store := NewStore(t.TempDir())
+	end := time.Date(2024, 3, 24, 9, 0, 0, 0, time.UTC) // Sunday
+	dining, groceries := "c1", "c2"
+	friday, saturday, monday := end.AddDate(0, 0, -2), end.AddDate(0, 0, -1), end.AddDate(0, 0, -6)
+	snapshot := &Snapshot{
+		Week:        WeekOf(end),
+		PeriodStart: end.AddDate(0, 0, -7),
+		PeriodEnd:   end,
+		Weekly: &ynab.WeeklyData{
+			Categories: []ynab.Category{{ID: dining, Name: "Dining", Balance: -20_000}, {ID: groceries, Name: "Groceries", Balance: 50_000}},
+			Transactions: []ynab.Transaction{
+				{Date: &friday, Amount: -90_000, CategoryID: &dining},
+				{Date: &saturday, Amount: -40_000, CategoryID: &dining},
+				{Date: &saturday, Amount: -60_000, CategoryID: &groceries},
+				{Date: &monday, Amount: -30_000, CategoryID: &groceries},
+				{Date: &monday, Amount: 10_000, CategoryID: &groceries}, // Refund
+			},
+		},
+	}
+	if err := store.Save(snapshot); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+
+	index, err := store.Weekdays(end.AddDate(0, -3, 0), end.AddDate(0, 0, 1))
+	if err != nil {
+		t.Fatalf("Weekdays failed: %v", err)
+	}
+	if index.Outflows[time.Friday] != 90_000 || index.Outflows[time.Saturday] != 100_000 || index.Outflows[time.Monday] != 30_000 {
+		t.Errorf("Outflows: got %v", index.Outflows)
+	}
+	if index.Overspent[time.Friday] != 90_000 || index.Overspent[time.Saturday] != 40_000 || index.Overspent[time.Monday] != 0 {
+		t.Errorf("Overspent: got %v", index.Overspent)
+	}
+}
@@ -0,0 +1,126 @@
+package history
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/sathyabhat/ynab-weekly-wrap/internal/pipeline"
+	"github.com/sathyabhat/ynab-weekly-wrap/internal/processor"
+	"github.com/sathyabhat/ynab-weekly-wrap/internal/ynab"
+)
+
+// ErrNotFound is returned when no snapshot was stored for a period
+var ErrNotFound = errors.New("snapshot not found")
+
+// Snapshot is the data a weekly wrap was built from, stored so the report can
+// be re-rendered later without going back to YNAB
+type Snapshot struct {
+	Week              string           `json:"week"` // ISO week, e.g. 2024-W12
+	CapturedAt        time.Time        `json:"captured_at"`
+	PeriodStart       time.Time        `json:"period_start"`
+	PeriodEnd         time.Time        `json:"period_end"`
+	Weekly            *ynab.WeeklyData `json:"weekly"`
+	PrevCategorySpend map[string]int64 `json:"prev_category_spend,omitempty"`
+
+	// Actions are the action items the wrap suggested, so the next week's
+	// wrap can report which were done
+	Actions []processor.ActionItem `json:"actions,omitempty"`
+}
+
+// Store keeps snapshots as JSON files, one per ISO week, under dir/weekly
+type Store struct {
+	dir string
+}
+
+// NewStore creates a store rooted at dir
+func NewStore(dir string) *Store {
+	return &Store{dir: dir}
+}
+
+func (s *Store) weeklyPath(week string) string {
+	return filepath.Join(s.dir, "weekly", week+".json")
+}
+
+// Save writes the snapshot, replacing any earlier one for the same week
+func (s *Store) Save(snapshot *Snapshot) error {
+	path := s.weeklyPath(snapshot.Week)
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("failed to create history directory: %w", err)
+	}
+
+	data, err := json.MarshalIndent(snapshot, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal snapshot: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		return fmt.Errorf("failed to write snapshot: %w", err)
+	}
+	return nil
+}
+
+// LoadWeek reads the snapshot stored for an ISO week such as 2024-W12
+func (s *Store) LoadWeek(week string) (*Snapshot, error) {
+	data, err := os.ReadFile(s.weeklyPath(week))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("%s: %w", week, ErrNotFound)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read snapshot: %w", err)
+	}
+
+	var snapshot Snapshot
+	if err := json.Unmarshal(data, &snapshot); err != nil {
+		return nil, fmt.Errorf("failed to parse snapshot %s: %w", week, err)
+	}
+	return &snapshot, nil
+}
+
+// LatestBefore returns the stored snapshot of the last ISO week before week,
+// such as 2024-W12. Weeks are compared rather than period end times, so a
+// run that ended a moment later than the week before's still finds it.
+// Snapshots are named by ISO week, so they are read newest first and only
+// until one is found.
+func (s *Store) LatestBefore(week string) (*Snapshot, error) {
+	paths, err := filepath.Glob(filepath.Join(s.dir, "weekly", "*.json"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list snapshots: %w", err)
+	}
+	sort.Sort(sort.Reverse(sort.StringSlice(paths)))
+	for _, path := range paths {
+		if stored := strings.TrimSuffix(filepath.Base(path), ".json"); stored < week {
+			return s.LoadWeek(stored)
+		}
+	}
+	return nil, fmt.Errorf("before %s: %w", week, ErrNotFound)
+}
+
+// Export implements pipeline.Exporter, storing a snapshot of every weekly run
+func (s *Store) Export(run *pipeline.Run) error {
+	if run.Weekly == nil {
+		return nil
+	}
+
+	snapshot := &Snapshot{
+		Week:              WeekOf(run.PeriodEnd),
+		CapturedAt:        time.Now().UTC(),
+		PeriodStart:       run.PeriodStart,
+		PeriodEnd:         run.PeriodEnd,
+		Weekly:            run.Weekly,
+		PrevCategorySpend: run.PrevCategorySpend,
+	}
+	if run.Analysis != nil {
+		snapshot.Actions = run.Analysis.Actions
+	}
+	if err := s.Save(snapshot); err != nil {
+		return err
+	}
+	log.Printf("Stored snapshot for %s", snapshot.Week)
+	return nil
+}
@@ -0,0 +1,139 @@
+package history
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/sathyabhat/ynab-weekly-wrap/internal/pipeline"
+	"github.com/sathyabhat/ynab-weekly-wrap/internal/ynab"
+)
+
+func TestWeekOf(t *testing.T) {
+	cases := []struct {
+		end  time.Time
+		want string
+	}{
+		{time.Date(2024, 3, 24, 9, 0, 0, 0, time.UTC), "2024-W12"}, // Sunday
+		{time.Date(2024, 3, 25, 9, 0, 0, 0, time.UTC), "2024-W12"}, // Monday after
+		{time.Date(2024, 3, 28, 9, 0, 0, 0, time.UTC), "2024-W13"}, // Thursday
+		{time.Date(2021, 1, 3, 9, 0, 0, 0, time.UTC), "2020-W53"},
+	}
+	for _, tc := range cases {
+		if got := WeekOf(tc.end); got != tc.want {
+			t.Errorf("WeekOf(%s): got %s, want %s", tc.end.Format("2006-01-02"), got, tc.want)
+		}
+	}
+}
+
+func TestParseWeek(t *testing.T) {
+	monday, err := ParseWeek("2024-W12")
+	if err != nil {
+		t.Fatalf("ParseWeek failed: %v", err)
+	}
+	if want := time.Date(2024, 3, 18, 0, 0, 0, 0, time.UTC); !monday.Equal(want) {
+		t.Errorf("got %s, want %s", monday, want)
+	}
+
+	if monday, err := ParseWeek("2020-W53"); err != nil || monday.Format("2006-01-02") != "2020-12-28" {
+		t.Errorf("2020-W53: got %s, %v", monday, err)
+	}
+
+	for _, bad := range []string{"2024-12", "2024-W00", "2023-W53", "last week"} {
+		if _, err := ParseWeek(bad); err == nil {
+			t.Errorf("ParseWeek(%q): expected error", bad)
+		}
+	}
+}
+
+func TestStore_ExportAndLoad(t *testing.T) {
+	store := NewStore(t.TempDir())
+	end := time.Date(2024, 3, 24, 9, 0, 0, 0, time.UTC)
+	run := &pipeline.Run{
+		Name:        "Weekly wrap",
+		PeriodStart: end.AddDate(0, 0, -7),
+		PeriodEnd:   end,
+		Weekly: &ynab.WeeklyData{
+			Budget:       &ynab.Budget{ID: "b1", Name: "Budget"},
+			Transactions: []ynab.Transaction{{ID: "t1", Amount: -12_340, CategoryName: "Groceries"}},
+			WeekStart:    end.AddDate(0, 0, -7),
+			WeekEnd:      end,
+		},
+		PrevCategorySpend: map[string]int64{"Groceries": 50_000},
+	}
+
+	if err := store.Export(run); err != nil {
+		t.Fatalf("Export failed: %v", err)
+	}
+
+	snapshot, err := store.LoadWeek("2024-W12")
+	if err != nil {
+		t.Fatalf("LoadWeek failed: %v", err)
+	}
+	if len(snapshot.Weekly.Transactions) != 1 || snapshot.Weekly.Transactions[0].Amount != -12_340 {
+		t.Errorf("unexpected transactions: %+v", snapshot.Weekly.Transactions)
+	}
+	if snapshot.PrevCategorySpend["Groceries"] != 50_000 || !snapshot.PeriodEnd.Equal(end) {
+		t.Errorf("unexpected snapshot: %+v", snapshot)
+	}
+
+	if _, err := store.LoadWeek("2024-W11"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("expected ErrNotFound, got %v", err)
+	}
+}
+
+func TestStore_ExportSkipsMonthlyRuns(t *testing.T) {
+	store := NewStore(t.TempDir())
+	if err := store.Export(&pipeline.Run{Name: "Monthly wrap", Monthly: &ynab.MonthlyData{}}); err != nil {
+		t.Fatalf("Export failed: %v", err)
+	}
+	if _, err := store.LoadWeek(WeekOf(time.Time{})); !errors.Is(err, ErrNotFound) {
+		t.Errorf("expected no snapshot for a monthly run, got %v", err)
+	}
+}
+
+func TestStore_LatestBefore(t *testing.T) {
+	store := NewStore(t.TempDir())
+	end := time.Date(2024, 3, 24, 23, 59, 59, 0, time.UTC)
+	for _, weeks := range []int{0, 1, 3} {
+		saveWeek(t, store, end.AddDate(0, 0, -7*weeks))
+	}
+
+	snapshot, err := store.LatestBefore(WeekOf(end))
+	if err != nil {
+		t.Fatalf("LatestBefore failed: %v", err)
+	}
+	if want := WeekOf(end.AddDate(0, 0, -7)); snapshot.Week != want {
+		t.Errorf("got %s, want %s", snapshot.Week, want)
+	}
+
+	snapshot, err = store.LatestBefore(WeekOf(end.AddDate(0, 0, -7)))
+	if err != nil {
+		t.Fatalf("LatestBefore failed: %v", err)
+	}
+	if want := WeekOf(end.AddDate(0, 0, -21)); snapshot.Week != want {
+		t.Errorf("got %s, want %s, skipping the missing week", snapshot.Week, want)
+	}
+
+	if _, err := store.LatestBefore(WeekOf(end.AddDate(0, 0, -21))); !errors.Is(err, ErrNotFound) {
+		t.Errorf("expected ErrNotFound, got %v", err)
+	}
+}
+
+// A run that ends a moment earlier in the day than the week before's still
+// finds that week's snapshot
+func TestStore_LatestBeforeRunEndingEarlier(t *testing.T) {
+	store := NewStore(t.TempDir())
+	last := time.Date(2024, 3, 17, 9, 0, 0, 20_000_000, time.UTC)
+	saveWeek(t, store, last.AddDate(0, 0, -7))
+	saveWeek(t, store, last)
+
+	this := time.Date(2024, 3, 24, 9, 0, 0, 10_000_000, time.UTC)
+	snapshot, err := store.LatestBefore(WeekOf(this))
+	if err != nil {
+		t.Fatalf("LatestBefore failed: %v", err)
+	}
+	if want := WeekOf(last); snapshot.Week != want {
+		t.Errorf("got %s, want last week's %s", snapshot.Week, want)
+	}
+}
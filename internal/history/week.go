@@ -0,0 +1,31 @@
+package history
+
+import (
+	"fmt"
+	"time"
+)
+
+// WeekOf returns the ISO week (e.g. 2024-W12) that most of the seven days
+// ending at periodEnd fall in. A wrap run on Monday morning therefore files
+// under the week that just finished.
+func WeekOf(periodEnd time.Time) string {
+	year, week := periodEnd.AddDate(0, 0, -3).ISOWeek()
+	return fmt.Sprintf("%04d-W%02d", year, week)
+}
+
+// ParseWeek parses an ISO week such as 2024-W12 and returns its Monday
+func ParseWeek(week string) (time.Time, error) {
+	var year, num int
+	if _, err := fmt.Sscanf(week, "%4d-W%2d", &year, &num); err != nil {
+		return time.Time{}, fmt.Errorf("invalid week %q: expected e.g. 2024-W12", week)
+	}
+
+	// January 4th is always in week 1
+	jan4 := time.Date(year, time.January, 4, 0, 0, 0, 0, time.UTC)
+	monday := jan4.AddDate(0, 0, -((int(jan4.Weekday())+6)%7)+(num-1)*7)
+
+	if y, w := monday.ISOWeek(); num < 1 || y != year || w != num {
+		return time.Time{}, fmt.Errorf("invalid week %q: %d has no week %d", week, year, num)
+	}
+	return monday, nil
+}
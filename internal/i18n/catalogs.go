@@ -0,0 +1,382 @@
+package i18n
+
+// catalogs maps each locale to its messages, keyed by message ID. English is
+// the source every other catalog translates, and fills in for anything they
+// leave out.
+var catalogs = map[string]map[string]string{
+	"en": {
+		"weekly.title":             "Weekly Financial Wrap - %s",
+		"monthly.title":            "Monthly Financial Wrap - %s",
+		"total_spent":              "Total Spent",
+		"categories.none":          "No Spending Categories",
+		"categories.one":           "1 Spending Category",
+		"categories.many":          "%d Spending Categories",
+		"weekly.top":               "Top %s",
+		"weekly.category":          "Last Week Spend: %s  Balance: %s",
+		"monthly.category":         "Last Month Spend: %s  Balance: %s",
+		"weekly.vs_prev":           "%s vs prev week",
+		"weekly.avg":               "%d-wk avg %s",
+		"monthly.vs_prev":          "%s vs prev month",
+		"over_budget":              "Over Budget Categories",
+		"over_budget.none":         "No categories over budget - great job!",
+		"transactions.last_few":    "Last 3 transactions:",
+		"benchmarks":               "Weekly Benchmarks",
+		"needs_vs_wants":           "Needs vs Wants",
+		"early_alerts":             "Early Spending Alerts",
+		"projection":               "Projection",
+		"projection.line":          "on pace for %s of %s budgeted (%s over)",
+		"new_payees":               "New Payees",
+		"unusual":                  "Unusual this week",
+		"unusual.line":             "%s in %s, usually about %s",
+		"utilities":                "Utility Bills",
+		"utilities.spike":          "%s bill %.0f%% above typical (%s vs %s)",
+		"actions":                  "Action Items",
+		"actions.follow_up":        "Last week: %d of %d done",
+		"actions.cover":            "Cover %s's %s overspending from %s",
+		"actions.cover_uncovered":  "Cover %s's %s overspending",
+		"actions.approve":          "Approve %d transactions",
+		"actions.approve_one":      "Approve 1 transaction",
+		"actions.carried":          "still open from last week",
+		"actions.carried_some":     "%d still open from last week",
+		"actions.none":             "Nothing left to do",
+		"savings":                  "Savings Rate",
+		"savings.week":             "%.0f%% this week",
+		"savings.month":            "%.0f%% this month",
+		"savings.no_income":        "no income this week",
+		"savings.target":           "target %d%%",
+		"goals":                    "Goal Progress",
+		"goals.line":               "%d%% of %s target, %s to go",
+		"goals.by":                 "%s by %s",
+		"goals.funded":             "%s target fully funded",
+		"age_of_money":             "Age of Money",
+		"age_of_money.days":        "%d days",
+		"age_of_money.week":        "%+d vs last week",
+		"cards":                    "Credit Card Payments",
+		"cards.spent":              "%s spent this week",
+		"cards.paid":               "%s paid",
+		"cards.owed":               "%s owed",
+		"cards.available":          "%s set aside",
+		"cards.short":              "%s short",
+		"patterns":                 "Spending Patterns, Last %d Months",
+		"patterns.all":             "%.0f%% of spending happens %s (%.0f%% if spread evenly)",
+		"patterns.overspent":       "%.0f%% of spending in overspent categories happens %s (%.0f%% if spread evenly)",
+		"offsets":                  "Planned offset: %s",
+		"offsets.then":             ", then ",
+		"offsets.empty":            "%s (empty)",
+		"offsets.uncovered":        "%s still uncovered",
+		"attention":                "Needs Attention",
+		"attention.uncategorized":  "%d uncategorized transactions, %s in total: categorize them in YNAB",
+		"attention.unapproved":     "%d unapproved transactions, %s in total: approve them in YNAB",
+		"attention.uncleared":      "%d uncleared transactions, %s in total: they may still change",
+		"guardrails":               "To Finish the Month on Budget",
+		"guardrails.line":          "%s/day left over %d days",
+		"insights":                 "Insights",
+		"weekly.spent":             "Last Week Spend: %s",
+		"monthly.spent":            "Last Month Spend: %s",
+		"wins":                     "Wins",
+		"wins.left":                "%s left",
+		"week_ahead":               "Week Ahead",
+		"week_ahead.one_week":      "1 week left this month",
+		"week_ahead.weeks":         "%d weeks left this month",
+		"week_ahead.watch":         "Watch: %s",
+		"week_ahead.cover":         "Cover %s from %s, the planned offset",
+		"week_ahead.reduce":        "Consider reducing %s budget",
+		"flagged":                  "Flagged Transactions",
+		"groups":                   "By Account Group",
+		"groups.other":             "Other accounts",
+		"groceries":                "Grocery Trips",
+		"groceries.week":           "This week: %d trips, %s on average",
+		"groceries.one":            "This week: 1 trip, %s",
+		"groceries.up":             "Average trip up %.0f%% over %d months (%s vs %s)",
+		"groceries.down":           "Average trip down %.0f%% over %d months (%s vs %s)",
+		"accounts":                 "Account Balances",
+		"accounts.uncleared":       "%s cleared, %s uncleared",
+		"net_worth":                "Net Worth",
+		"net_worth.week":           "%s vs last week",
+		"net_worth.month":          "%s this month",
+		"cash_flow":                "Cash Flow",
+		"cash_flow.line":           "%s in, %s out, net %s",
+		"left_this_month":          "Left This Month",
+		"notes":                    "Notes",
+		"tidy_up":                  "Budget Tidy-Up",
+		"biggest_mover":            "Biggest Mover",
+		"daily.title":              "Daily Digest - %s",
+		"daily.spent":              "Spent Yesterday",
+		"daily.none":               "No spending yesterday",
+		"daily.more":               "...and %d more",
+		"daily.week_so_far":        "Week So Far",
+		"daily.newly_over":         "Newly Over Budget",
+		"daily.over":               "%s over (%s yesterday)",
+		"quarterly.title":          "Quarterly Summary - %s",
+		"yearly.title":             "Yearly Summary - %s",
+		"summary.total":            "%s of %s budgeted",
+		"summary.adherence":        "Budget Adherence",
+		"summary.adherence_detail": "%.0f%% of category-months within budget",
+		"summary.top_categories":   "Top Categories",
+		"summary.category":         "%s spent of %s budgeted",
+		"summary.months_over":      "over budget in %d of %d months",
+		"summary.top_payees":       "Top Payees",
+		"summary.payee":            "%s across %d transactions",
+		"meeting.title":            "Budget Meeting Agenda - %s",
+		"meeting.none":             "Nothing needs a decision this week",
+		"meeting.overspent":        "%s overspent, move %s from %s",
+		"meeting.uncovered":        "%s overspent, nothing left to cover it from",
+		"meeting.underfunded":      "goal %d%% funded (%s target)",
+		"meeting.unapproved":       "Unapproved Transactions",
+		"meeting.to_review":        "%d to review",
+		"meeting.uncategorized":    "Uncategorized Transactions",
+		"meeting.to_categorize":    "%d to categorize: %s",
+		"meeting.bill":             "%s due %s",
+		"confidence":               "Data: %d of %d accounts updated in the last 48h, %d unapproved",
+	},
+	"de": {
+		"weekly.title":             "Wöchentlicher Finanzrückblick - %s",
+		"monthly.title":            "Monatlicher Finanzrückblick - %s",
+		"total_spent":              "Gesamtausgaben",
+		"categories.none":          "Keine Ausgabenkategorien",
+		"categories.one":           "1 Ausgabenkategorie",
+		"categories.many":          "%d Ausgabenkategorien",
+		"weekly.top":               "Top %s",
+		"weekly.category":          "Ausgaben letzte Woche: %s  Saldo: %s",
+		"monthly.category":         "Ausgaben letzten Monat: %s  Saldo: %s",
+		"weekly.vs_prev":           "%s ggü. Vorwoche",
+		"weekly.avg":               "Ø %d Wo. %s",
+		"monthly.vs_prev":          "%s ggü. Vormonat",
+		"over_budget":              "Kategorien über Budget",
+		"over_budget.none":         "Keine Kategorie über Budget - gut gemacht!",
+		"transactions.last_few":    "Letzte 3 Buchungen:",
+		"benchmarks":               "Wöchentliche Richtwerte",
+		"needs_vs_wants":           "Bedarf vs. Wünsche",
+		"early_alerts":             "Frühe Ausgabenwarnungen",
+		"projection":               "Prognose",
+		"projection.line":          "auf Kurs zu %s von %s budgetiert (%s darüber)",
+		"new_payees":               "Neue Zahlungsempfänger",
+		"unusual":                  "Ungewöhnlich diese Woche",
+		"unusual.line":             "%s in %s, sonst etwa %s",
+		"utilities":                "Nebenkosten",
+		"utilities.spike":          "%s-Rechnung %.0f%% über üblich (%s statt %s)",
+		"actions":                  "Aufgaben",
+		"actions.follow_up":        "Letzte Woche: %d von %d erledigt",
+		"actions.cover":            "Überziehung von %s (%s) aus %s ausgleichen",
+		"actions.cover_uncovered":  "Überziehung von %s (%s) ausgleichen",
+		"actions.approve":          "%d Buchungen freigeben",
+		"actions.approve_one":      "1 Buchung freigeben",
+		"actions.carried":          "seit letzter Woche offen",
+		"actions.carried_some":     "%d seit letzter Woche offen",
+		"actions.none":             "Nichts mehr zu tun",
+		"savings":                  "Sparquote",
+		"savings.week":             "%.0f%% diese Woche",
+		"savings.month":            "%.0f%% diesen Monat",
+		"savings.no_income":        "keine Einnahmen diese Woche",
+		"savings.target":           "Ziel %d%%",
+		"goals":                    "Fortschritt der Ziele",
+		"goals.line":               "%d%% von %s Ziel, noch %s",
+		"goals.by":                 "%s bis %s",
+		"goals.funded":             "Ziel von %s voll finanziert",
+		"age_of_money":             "Alter des Geldes",
+		"age_of_money.days":        "%d Tage",
+		"age_of_money.week":        "%+d ggü. Vorwoche",
+		"cards":                    "Kreditkartenzahlungen",
+		"cards.spent":              "%s diese Woche ausgegeben",
+		"cards.paid":               "%s bezahlt",
+		"cards.owed":               "%s offen",
+		"cards.available":          "%s zurückgelegt",
+		"cards.short":              "%s fehlen",
+		"patterns":                 "Ausgabemuster der letzten %d Monate",
+		"patterns.all":             "%.0f%% der Ausgaben fallen auf %s (%.0f%% bei gleichmäßiger Verteilung)",
+		"patterns.overspent":       "%.0f%% der Ausgaben in überzogenen Kategorien fallen auf %s (%.0f%% bei gleichmäßiger Verteilung)",
+		"offsets":                  "Geplanter Ausgleich: %s",
+		"offsets.then":             ", danach ",
+		"offsets.empty":            "%s (leer)",
+		"offsets.uncovered":        "%s noch ungedeckt",
+		"attention":                "Zu erledigen",
+		"attention.uncategorized":  "%d nicht kategorisierte Buchungen, insgesamt %s: in YNAB kategorisieren",
+		"attention.unapproved":     "%d nicht freigegebene Buchungen, insgesamt %s: in YNAB freigeben",
+		"attention.uncleared":      "%d nicht abgeglichene Buchungen, insgesamt %s: sie können sich noch ändern",
+		"guardrails":               "Um den Monat im Budget abzuschließen",
+		"guardrails.line":          "%s/Tag übrig für %d Tage",
+		"insights":                 "Erkenntnisse",
+		"weekly.spent":             "Ausgaben letzte Woche: %s",
+		"monthly.spent":            "Ausgaben letzten Monat: %s",
+		"wins":                     "Erfolge",
+		"wins.left":                "%s übrig",
+		"week_ahead":               "Die Woche voraus",
+		"week_ahead.one_week":      "noch 1 Woche in diesem Monat",
+		"week_ahead.weeks":         "noch %d Wochen in diesem Monat",
+		"week_ahead.watch":         "Im Blick behalten: %s",
+		"week_ahead.cover":         "%s aus %s decken, dem geplanten Ausgleich",
+		"week_ahead.reduce":        "Budget für %s eventuell senken",
+		"flagged":                  "Markierte Buchungen",
+		"groups":                   "Nach Kontogruppe",
+		"groups.other":             "Andere Konten",
+		"groceries":                "Einkäufe",
+		"groceries.week":           "Diese Woche: %d Einkäufe, im Schnitt %s",
+		"groceries.one":            "Diese Woche: 1 Einkauf, %s",
+		"groceries.up":             "Durchschnittlicher Einkauf %.0f%% teurer als vor %d Monaten (%s statt %s)",
+		"groceries.down":           "Durchschnittlicher Einkauf %.0f%% günstiger als vor %d Monaten (%s statt %s)",
+		"accounts":                 "Kontostände",
+		"accounts.uncleared":       "%s gebucht, %s vorgemerkt",
+		"net_worth":                "Nettovermögen",
+		"net_worth.week":           "%s ggü. Vorwoche",
+		"net_worth.month":          "%s diesen Monat",
+		"cash_flow":                "Cashflow",
+		"cash_flow.line":           "%s Einnahmen, %s Ausgaben, netto %s",
+		"left_this_month":          "Übrig diesen Monat",
+		"notes":                    "Notizen",
+		"tidy_up":                  "Budget aufräumen",
+		"biggest_mover":            "Größte Veränderung",
+		"daily.title":              "Tagesüberblick - %s",
+		"daily.spent":              "Ausgaben gestern",
+		"daily.none":               "Gestern keine Ausgaben",
+		"daily.more":               "...und %d weitere",
+		"daily.week_so_far":        "Woche bisher",
+		"daily.newly_over":         "Neu über Budget",
+		"daily.over":               "%s drüber (%s gestern)",
+		"quarterly.title":          "Quartalsrückblick - %s",
+		"yearly.title":             "Jahresrückblick - %s",
+		"summary.total":            "%s von %s budgetiert",
+		"summary.adherence":        "Budgettreue",
+		"summary.adherence_detail": "%.0f%% der Kategorie-Monate im Budget",
+		"summary.top_categories":   "Top-Kategorien",
+		"summary.category":         "%s ausgegeben von %s budgetiert",
+		"summary.months_over":      "in %d von %d Monaten über Budget",
+		"summary.top_payees":       "Top-Zahlungsempfänger",
+		"summary.payee":            "%s in %d Buchungen",
+		"meeting.title":            "Agenda für das Budgetgespräch - %s",
+		"meeting.none":             "Diese Woche steht nichts zur Entscheidung an",
+		"meeting.overspent":        "%s überzogen, %s von %s umschichten",
+		"meeting.uncovered":        "%s überzogen, nichts mehr zum Ausgleichen übrig",
+		"meeting.underfunded":      "Ziel zu %d%% finanziert (Ziel %s)",
+		"meeting.unapproved":       "Nicht freigegebene Buchungen",
+		"meeting.to_review":        "%d zu prüfen",
+		"meeting.uncategorized":    "Nicht kategorisierte Buchungen",
+		"meeting.to_categorize":    "%d zu kategorisieren: %s",
+		"meeting.bill":             "%s fällig am %s",
+		"confidence":               "Daten: %d von %d Konten in den letzten 48 Std. aktualisiert, %d nicht freigegeben",
+	},
+	"es": {
+		"weekly.title":             "Resumen financiero semanal - %s",
+		"monthly.title":            "Resumen financiero mensual - %s",
+		"total_spent":              "Gasto total",
+		"categories.none":          "Sin categorías de gasto",
+		"categories.one":           "1 categoría de gasto",
+		"categories.many":          "%d categorías de gasto",
+		"weekly.top":               "Top %s",
+		"weekly.category":          "Gasto de la semana pasada: %s  Saldo: %s",
+		"monthly.category":         "Gasto del mes pasado: %s  Saldo: %s",
+		"weekly.vs_prev":           "%s vs. semana anterior",
+		"weekly.avg":               "prom. %d sem. %s",
+		"monthly.vs_prev":          "%s vs. mes anterior",
+		"over_budget":              "Categorías por encima del presupuesto",
+		"over_budget.none":         "Ninguna categoría por encima del presupuesto - ¡buen trabajo!",
+		"transactions.last_few":    "Últimas 3 transacciones:",
+		"benchmarks":               "Referencias semanales",
+		"needs_vs_wants":           "Necesidades vs. deseos",
+		"early_alerts":             "Alertas de gasto anticipado",
+		"projection":               "Proyección",
+		"projection.line":          "en camino a %s de %s presupuestado (%s de más)",
+		"new_payees":               "Nuevos beneficiarios",
+		"unusual":                  "Inusual esta semana",
+		"unusual.line":             "%s en %s, normalmente unos %s",
+		"utilities":                "Suministros",
+		"utilities.spike":          "Factura de %s %.0f%% por encima de lo habitual (%s frente a %s)",
+		"actions":                  "Tareas",
+		"actions.follow_up":        "La semana pasada: %d de %d hechas",
+		"actions.cover":            "Cubrir el exceso de %s (%s) con %s",
+		"actions.cover_uncovered":  "Cubrir el exceso de %s (%s)",
+		"actions.approve":          "Aprobar %d transacciones",
+		"actions.approve_one":      "Aprobar 1 transacción",
+		"actions.carried":          "pendiente desde la semana pasada",
+		"actions.carried_some":     "%d pendientes desde la semana pasada",
+		"actions.none":             "Nada pendiente",
+		"savings":                  "Tasa de ahorro",
+		"savings.week":             "%.0f%% esta semana",
+		"savings.month":            "%.0f%% este mes",
+		"savings.no_income":        "sin ingresos esta semana",
+		"savings.target":           "objetivo %d%%",
+		"goals":                    "Progreso de objetivos",
+		"goals.line":               "%d%% de un objetivo de %s, faltan %s",
+		"goals.by":                 "%s para %s",
+		"goals.funded":             "objetivo de %s totalmente financiado",
+		"age_of_money":             "Antigüedad del dinero",
+		"age_of_money.days":        "%d días",
+		"age_of_money.week":        "%+d frente a la semana pasada",
+		"cards":                    "Pagos de tarjetas de crédito",
+		"cards.spent":              "%s gastado esta semana",
+		"cards.paid":               "%s pagado",
+		"cards.owed":               "%s adeudado",
+		"cards.available":          "%s reservado",
+		"cards.short":              "faltan %s",
+		"patterns":                 "Patrones de gasto, últimos %d meses",
+		"patterns.all":             "El %.0f%% del gasto ocurre %s (%.0f%% si se repartiera por igual)",
+		"patterns.overspent":       "El %.0f%% del gasto en categorías excedidas ocurre %s (%.0f%% si se repartiera por igual)",
+		"offsets":                  "Compensación prevista: %s",
+		"offsets.then":             ", luego ",
+		"offsets.empty":            "%s (vacía)",
+		"offsets.uncovered":        "%s aún sin cubrir",
+		"attention":                "Requiere atención",
+		"attention.uncategorized":  "%d transacciones sin categoría, %s en total: categorízalas en YNAB",
+		"attention.unapproved":     "%d transacciones sin aprobar, %s en total: apruébalas en YNAB",
+		"attention.uncleared":      "%d transacciones sin liquidar, %s en total: aún pueden cambiar",
+		"guardrails":               "Para terminar el mes dentro del presupuesto",
+		"guardrails.line":          "%s/día disponibles durante %d días",
+		"insights":                 "Observaciones",
+		"weekly.spent":             "Gasto de la semana pasada: %s",
+		"monthly.spent":            "Gasto del mes pasado: %s",
+		"wins":                     "Logros",
+		"wins.left":                "Quedan %s",
+		"week_ahead":               "La semana que viene",
+		"week_ahead.one_week":      "queda 1 semana este mes",
+		"week_ahead.weeks":         "quedan %d semanas este mes",
+		"week_ahead.watch":         "Vigilar: %s",
+		"week_ahead.cover":         "Cubrir %s con %s, la compensación prevista",
+		"week_ahead.reduce":        "Considera reducir el presupuesto de %s",
+		"flagged":                  "Transacciones marcadas",
+		"groups":                   "Por grupo de cuentas",
+		"groups.other":             "Otras cuentas",
+		"groceries":                "Compras de supermercado",
+		"groceries.week":           "Esta semana: %d compras, %s de media",
+		"groceries.one":            "Esta semana: 1 compra, %s",
+		"groceries.up":             "Compra media %.0f%% más cara en %d meses (%s frente a %s)",
+		"groceries.down":           "Compra media %.0f%% más barata en %d meses (%s frente a %s)",
+		"accounts":                 "Saldos de cuentas",
+		"accounts.uncleared":       "%s conciliado, %s sin conciliar",
+		"net_worth":                "Patrimonio neto",
+		"net_worth.week":           "%s frente a la semana pasada",
+		"net_worth.month":          "%s este mes",
+		"cash_flow":                "Flujo de caja",
+		"cash_flow.line":           "%s de ingresos, %s de gastos, neto %s",
+		"left_this_month":          "Disponible este mes",
+		"notes":                    "Notas",
+		"tidy_up":                  "Limpieza del presupuesto",
+		"biggest_mover":            "Mayor cambio",
+		"daily.title":              "Resumen diario - %s",
+		"daily.spent":              "Gasto de ayer",
+		"daily.none":               "Sin gastos ayer",
+		"daily.more":               "...y %d más",
+		"daily.week_so_far":        "Semana hasta ahora",
+		"daily.newly_over":         "Nuevas categorías sobre el presupuesto",
+		"daily.over":               "%s por encima (%s ayer)",
+		"quarterly.title":          "Resumen trimestral - %s",
+		"yearly.title":             "Resumen anual - %s",
+		"summary.total":            "%s de %s presupuestado",
+		"summary.adherence":        "Cumplimiento del presupuesto",
+		"summary.adherence_detail": "%.0f%% de los meses por categoría dentro del presupuesto",
+		"summary.top_categories":   "Categorías principales",
+		"summary.category":         "%s gastado de %s presupuestado",
+		"summary.months_over":      "sobre el presupuesto en %d de %d meses",
+		"summary.top_payees":       "Beneficiarios principales",
+		"summary.payee":            "%s en %d transacciones",
+		"meeting.title":            "Agenda de la reunión de presupuesto - %s",
+		"meeting.none":             "Nada requiere una decisión esta semana",
+		"meeting.overspent":        "%s de sobregasto, mover %s de %s",
+		"meeting.uncovered":        "%s de sobregasto, no queda nada para cubrirlo",
+		"meeting.underfunded":      "meta financiada al %d%% (objetivo %s)",
+		"meeting.unapproved":       "Transacciones sin aprobar",
+		"meeting.to_review":        "%d por revisar",
+		"meeting.uncategorized":    "Transacciones sin categoría",
+		"meeting.to_categorize":    "%d por categorizar: %s",
+		"meeting.bill":             "%s con vencimiento el %s",
+		"confidence":               "Datos: %d de %d cuentas actualizadas en las últimas 48 h, %d sin aprobar",
+	},
+}
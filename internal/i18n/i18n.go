@@ -0,0 +1,96 @@
+// Package i18n holds message catalogs for the text of the wraps, so they can
+// be sent in languages other than English.
+package i18n
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// DefaultLocale is used when no locale is configured, and for messages a
+// catalog doesn't translate
+const DefaultLocale = "en"
+
+// Catalog translates message IDs for one locale
+type Catalog struct {
+	locale   string
+	messages map[string]string
+}
+
+// New returns the catalog for a locale such as "de" or "es-MX", falling back
+// from a regional locale to its language
+func New(locale string) (*Catalog, error) {
+	tag := strings.ToLower(strings.ReplaceAll(strings.TrimSpace(locale), "_", "-"))
+	if tag == "" {
+		tag = DefaultLocale
+	}
+	if messages, ok := catalogs[tag]; ok {
+		return &Catalog{locale: tag, messages: messages}, nil
+	}
+	language, _, _ := strings.Cut(tag, "-")
+	if messages, ok := catalogs[language]; ok {
+		return &Catalog{locale: language, messages: messages}, nil
+	}
+	return nil, fmt.Errorf("unsupported locale %q, available: %s", locale, strings.Join(Locales(), ", "))
+}
+
+// Locales lists the locales with a catalog
+func Locales() []string {
+	locales := make([]string, 0, len(catalogs))
+	for locale := range catalogs {
+		locales = append(locales, locale)
+	}
+	sort.Strings(locales)
+	return locales
+}
+
+// Locale returns the locale the catalog translates to
+func (c *Catalog) Locale() string {
+	if c == nil {
+		return DefaultLocale
+	}
+	return c.locale
+}
+
+// Override returns a copy of the catalog with some messages replaced, e.g. to
+// rename sections. Messages are keyed by ID, and unknown IDs are an error.
+func (c *Catalog) Override(messages map[string]string) (*Catalog, error) {
+	merged := make(map[string]string)
+	if c != nil {
+		for id, text := range c.messages {
+			merged[id] = text
+		}
+	}
+	for id, text := range messages {
+		if !HasMessage(id) {
+			return nil, fmt.Errorf("unknown message %q", id)
+		}
+		merged[id] = text
+	}
+	return &Catalog{locale: c.Locale(), messages: merged}, nil
+}
+
+// HasMessage reports whether id is a known message ID
+func HasMessage(id string) bool {
+	_, ok := catalogs[DefaultLocale][id]
+	return ok
+}
+
+// T formats the message with the given ID in the catalog's language. A nil
+// catalog, or one missing the message, uses the English text.
+func (c *Catalog) T(id string, args ...any) string {
+	format, ok := "", false
+	if c != nil {
+		format, ok = c.messages[id]
+	}
+	if !ok {
+		if format, ok = catalogs[DefaultLocale][id]; !ok {
+			return id
+		}
+	}
+	if len(args) == 0 {
+		return format
+	}
+	return fmt.Sprintf(format, args...)
+}
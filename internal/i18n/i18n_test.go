@@ -0,0 +1,60 @@
+package i18n
+
+import "testing"
+
+func TestNew_FallsBackToLanguage(t *testing.T) {
+	for locale, want := range map[string]string{"": "en", "de": "de", "es-MX": "es", "de_AT": "de", "EN-gb": "en"} {
+		catalog, err := New(locale)
+		if err != nil {
+			t.Fatalf("New(%q): unexpected error: %v", locale, err)
+		}
+		if catalog.Locale() != want {
+			t.Errorf("New(%q): got locale %q, want %q", locale, catalog.Locale(), want)
+		}
+	}
+	if _, err := New("xx"); err == nil {
+		t.Error("expected an error for an unsupported locale")
+	}
+}
+
+func TestT(t *testing.T) {
+	de, _ := New("de")
+	if got := de.T("monthly.title", "Januar 2026"); got != "Monatlicher Finanzrückblick - Januar 2026" {
+		t.Errorf("got %q", got)
+	}
+
+	var none *Catalog
+	if got := none.T("categories.many", 3); got != "3 Spending Categories" {
+		t.Errorf("nil catalog should use English, got %q", got)
+	}
+	if got := de.T("no.such.message"); got != "no.such.message" {
+		t.Errorf("unknown IDs should be returned as is, got %q", got)
+	}
+}
+
+func TestCatalogs_TranslateEveryMessage(t *testing.T) {
+	for locale, messages := range catalogs {
+		for id := range catalogs[DefaultLocale] {
+			if _, ok := messages[id]; !ok {
+				t.Errorf("%s catalog is missing %q", locale, id)
+			}
+		}
+	}
+}
+
+func TestOverride(t *testing.T) {
+	es, _ := New("es")
+	renamed, err := es.Override(map[string]string{"over_budget": "Gastos de más"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := renamed.T("over_budget"); got != "Gastos de más" {
+		t.Errorf("got %q", got)
+	}
+	if got := renamed.T("total_spent"); got != "Gasto total" || es.T("over_budget") == "Gastos de más" {
+		t.Errorf("override should only change the copy, got %q", got)
+	}
+	if _, err := es.Override(map[string]string{"no.such.message": "x"}); err == nil {
+		t.Error("expected an error for an unknown message ID")
+	}
+}
@@ -0,0 +1,182 @@
+// Package insights holds user-written rules that add lines of commentary to
+// a wrap when their conditions hold, e.g. "if Groceries came to more than
+// 1.3 times its 4-week average, say so".
+package insights
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Fields are what a rule's condition and message can refer to. Amounts are
+// in the budget's currency, percentage is the share of the budget spent.
+var Fields = []string{"spent", "budgeted", "balance", "over", "percentage", "prev_spent", "avg_spent"}
+
+// operators compare a field with the condition's right-hand side
+var operators = map[string]func(a, b float64) bool{
+	">":  func(a, b float64) bool { return a > b },
+	">=": func(a, b float64) bool { return a >= b },
+	"<":  func(a, b float64) bool { return a < b },
+	"<=": func(a, b float64) bool { return a <= b },
+	"==": func(a, b float64) bool { return a == b },
+	"!=": func(a, b float64) bool { return a != b },
+}
+
+// Rule adds Say to the wrap when When holds for Category, or for the budget
+// as a whole when Category is empty. When compares a field with a number,
+// another field or a multiple of one:
+//
+//	{"category": "Groceries", "when": "spent > 1.3 * avg_spent",
+//	 "say": "Groceries came to {spent}, well above the usual {avg_spent}"}
+//
+// Say can name fields in braces, replaced by their values, and {category}.
+type Rule struct {
+	Category string `json:"category,omitempty"`
+	When     string `json:"when"`
+	Say      string `json:"say"`
+
+	cond condition
+}
+
+// condition is a parsed When: field op factor * other, or field op factor
+// when other is empty
+type condition struct {
+	field  string
+	op     string
+	factor float64
+	other  string
+}
+
+// Values looks up a field's value for the rule's category, false when it
+// isn't known, e.g. the average before the budget has enough history
+type Values func(field string) (float64, bool)
+
+// rulesFile is the JSON a rules file holds:
+//
+//	{"rules": [{"category": "Dining Out", "when": "over > 0", "say": "..."}]}
+type rulesFile struct {
+	Rules []Rule `json:"rules"`
+}
+
+// Load reads and checks the rules in the file at path. An empty path
+// returns no rules.
+func Load(path string) ([]Rule, error) {
+	if path == "" {
+		return nil, nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read rules file: %w", err)
+	}
+	rules, err := Parse(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return rules, nil
+}
+
+// Parse reads and checks the rules in a rules file's contents
+func Parse(data []byte) ([]Rule, error) {
+	var file rulesFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("failed to parse rules: %w", err)
+	}
+	for i := range file.Rules {
+		rule := &file.Rules[i]
+		if strings.TrimSpace(rule.Say) == "" {
+			return nil, fmt.Errorf("rule %d: say is empty", i+1)
+		}
+		cond, err := parseCondition(rule.When)
+		if err != nil {
+			return nil, fmt.Errorf("rule %d: %w", i+1, err)
+		}
+		rule.cond = cond
+	}
+	return file.Rules, nil
+}
+
+// parseCondition parses "field op number", "field op field" or
+// "field op number * field", with × or x accepted for *
+func parseCondition(when string) (condition, error) {
+	tokens := strings.Fields(when)
+	if len(tokens) != 3 && len(tokens) != 5 {
+		return condition{}, fmt.Errorf("condition %q must be like \"spent > 1.3 * avg_spent\"", when)
+	}
+	cond := condition{field: tokens[0], op: tokens[1], factor: 1}
+	if err := checkField(cond.field); err != nil {
+		return condition{}, err
+	}
+	if _, ok := operators[cond.op]; !ok {
+		return condition{}, fmt.Errorf("unknown operator %q, must be one of >, >=, <, <=, == or !=", cond.op)
+	}
+
+	operand := tokens[2]
+	if len(tokens) == 5 {
+		if times := tokens[3]; times != "*" && times != "×" && times != "x" {
+			return condition{}, fmt.Errorf("condition %q must multiply with *", when)
+		}
+		cond.other = tokens[4]
+		if err := checkField(cond.other); err != nil {
+			return condition{}, err
+		}
+	}
+	factor, err := strconv.ParseFloat(operand, 64)
+	switch {
+	case err == nil:
+		cond.factor = factor
+	case len(tokens) == 3:
+		cond.other = operand
+		if err := checkField(cond.other); err != nil {
+			return condition{}, err
+		}
+	default:
+		return condition{}, fmt.Errorf("invalid factor %q in condition %q", operand, when)
+	}
+	return cond, nil
+}
+
+// checkField returns an error naming the known fields when field isn't one
+func checkField(field string) error {
+	if slices.Contains(Fields, field) {
+		return nil
+	}
+	return fmt.Errorf("unknown field %q, must be one of %s", field, strings.Join(Fields, ", "))
+}
+
+// Holds reports whether the rule's condition holds for values. A condition
+// on a field that isn't known doesn't hold.
+func (r Rule) Holds(values Values) bool {
+	c := r.cond
+	if c.op == "" {
+		return false // Not loaded through Parse
+	}
+	value, ok := values(c.field)
+	if !ok {
+		return false
+	}
+	against := c.factor
+	if c.other != "" {
+		other, ok := values(c.other)
+		if !ok {
+			return false
+		}
+		against *= other
+	}
+	return operators[c.op](value, against)
+}
+
+// Message returns Say with {category} and each {field} replaced, formatting
+// fields with format. Fields that aren't known are left as they are.
+func (r Rule) Message(category string, format func(field string) (string, bool)) string {
+	replacements := []string{"{category}", category}
+	for _, field := range Fields {
+		if value, ok := format(field); ok {
+			replacements = append(replacements, "{"+field+"}", value)
+		}
+	}
+	return strings.NewReplacer(replacements...).Replace(r.Say)
+}
@@ -0,0 +1,102 @@
+package insights
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	rules, err := Parse([]byte(`{"rules": [
+		{"category": "Groceries", "when": "spent > 1.3 * avg_spent", "say": "Groceries came to {spent}"},
+		{"when": "balance < 0", "say": "The budget is overspent"},
+		{"category": "Dining Out", "when": "spent >= prev_spent", "say": "Dining Out is up on last week"}
+	]}`))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(rules) != 3 {
+		t.Fatalf("expected 3 rules, got %d", len(rules))
+	}
+	want := []condition{
+		{field: "spent", op: ">", factor: 1.3, other: "avg_spent"},
+		{field: "balance", op: "<", factor: 0},
+		{field: "spent", op: ">=", factor: 1, other: "prev_spent"},
+	}
+	for i, rule := range rules {
+		if rule.cond != want[i] {
+			t.Errorf("rule %d: got %+v, want %+v", i+1, rule.cond, want[i])
+		}
+	}
+}
+
+func TestParse_Invalid(t *testing.T) {
+	for _, tc := range []struct {
+		rules string
+		want  string
+	}{
+		{`{"rules": [{"when": "spend > 100", "say": "x"}]}`, `rule 1: unknown field "spend"`},
+		{`{"rules": [{"when": "spent => 100", "say": "x"}]}`, `unknown operator "=>"`},
+		{`{"rules": [{"when": "spent > lots", "say": "x"}]}`, `unknown field "lots"`},
+		{`{"rules": [{"when": "spent > 1.3 avg_spent", "say": "x"}]}`, `must be like`},
+		{`{"rules": [{"when": "spent > 1.3 / avg_spent", "say": "x"}]}`, `must multiply with *`},
+		{`{"rules": [{"when": "spent > 100"}]}`, `rule 1: say is empty`},
+		{`{"rules": [`, `failed to parse rules`},
+	} {
+		_, err := Parse([]byte(tc.rules))
+		if err == nil || !strings.Contains(err.Error(), tc.want) {
+			t.Errorf("%s: got error %v, want %q", tc.rules, err, tc.want)
+		}
+	}
+}
+
+func TestRule_Holds(t *testing.T) {
+	rules, err := Parse([]byte(`{"rules": [
+		{"when": "spent > 1.3 × avg_spent", "say": "x"},
+		{"when": "percentage >= 90", "say": "x"}
+	]}`))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	values := func(figures map[string]float64) Values {
+		return func(field string) (float64, bool) {
+			value, ok := figures[field]
+			return value, ok
+		}
+	}
+
+	if !rules[0].Holds(values(map[string]float64{"spent": 180, "avg_spent": 120})) {
+		t.Error("expected 180 > 1.3 × 120 to hold")
+	}
+	if rules[0].Holds(values(map[string]float64{"spent": 150, "avg_spent": 120})) {
+		t.Error("expected 150 > 1.3 × 120 not to hold")
+	}
+	if rules[0].Holds(values(map[string]float64{"spent": 180})) {
+		t.Error("expected a condition on an unknown average not to hold")
+	}
+	if !rules[1].Holds(values(map[string]float64{"percentage": 90})) {
+		t.Error("expected 90 >= 90 to hold")
+	}
+	if (Rule{When: "spent > 0", Say: "x"}).Holds(values(map[string]float64{"spent": 1})) {
+		t.Error("expected a rule not loaded through Parse not to hold")
+	}
+}
+
+func TestRule_Message(t *testing.T) {
+	rule := Rule{Say: "{category} came to {spent}, {avg_spent} usually"}
+	msg := rule.Message("Groceries", func(field string) (string, bool) {
+		if field == "spent" {
+			return "$180", true
+		}
+		return "", false
+	})
+	if want := "Groceries came to $180, {avg_spent} usually"; msg != want {
+		t.Errorf("got %q, want %q", msg, want)
+	}
+}
+
+func TestLoad_NoPath(t *testing.T) {
+	rules, err := Load("")
+	if err != nil || rules != nil {
+		t.Errorf("expected no rules and no error, got %v, %v", rules, err)
+	}
+}
@@ -0,0 +1,159 @@
+// Package journal commits each wrap as a dated Markdown file to a GitHub or
+// Gitea repository, building a private, diffable financial journal.
+package journal
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/sathyabhat/ynab-weekly-wrap/internal/config"
+	"github.com/sathyabhat/ynab-weekly-wrap/internal/pipeline"
+	"github.com/sathyabhat/ynab-weekly-wrap/internal/render"
+)
+
+// errNotFound is returned for a file that isn't in the repository yet
+var errNotFound = errors.New("not found")
+
+// Journal implements pipeline.Exporter, committing every weekly and monthly
+// wrap to a repository through the contents API GitHub and Gitea share
+type Journal struct {
+	config config.JournalConfig
+	client *http.Client
+}
+
+// New creates a journal committing to the configured repository
+func New(journalConfig config.JournalConfig) *Journal {
+	return &Journal{
+		config: journalConfig,
+		client: &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+// Export commits the run's wrap, replacing the file when the period's wrap
+// was committed before
+func (j *Journal) Export(run *pipeline.Run) error {
+	kind := ""
+	switch {
+	case run.Weekly != nil:
+		kind = "weekly"
+	case run.Monthly != nil:
+		kind = "monthly"
+	}
+	if kind == "" || run.Report.Message == "" {
+		return nil
+	}
+
+	day := run.PeriodEnd.Format("2006-01-02")
+	file := path.Join(j.config.Dir, run.PeriodEnd.Format("2006"), day+"-"+kind+".md")
+	content := "# " + run.Report.Title + "\n\n" + render.MarkdownFile.Format(run.Report.Doc()) + "\n"
+
+	sha, err := j.sha(file)
+	if err != nil && !errors.Is(err, errNotFound) {
+		return err
+	}
+	message := fmt.Sprintf("Add %s wrap for %s", kind, day)
+	if sha != "" {
+		message = fmt.Sprintf("Update %s wrap for %s", kind, day)
+	}
+	if err := j.commit(file, content, sha, message); err != nil {
+		return err
+	}
+	log.Printf("Committed %s to %s", file, j.config.Repo)
+	return nil
+}
+
+// fileRequest is the body that creates or updates a file
+type fileRequest struct {
+	Message string `json:"message"`
+	Content string `json:"content"` // Base64
+	SHA     string `json:"sha,omitempty"`
+	Branch  string `json:"branch,omitempty"`
+}
+
+// sha returns the blob SHA of a file in the repository, which updating it
+// requires
+func (j *Journal) sha(file string) (string, error) {
+	endpoint := j.contentsURL(file)
+	if j.config.Branch != "" {
+		endpoint += "?ref=" + url.QueryEscape(j.config.Branch)
+	}
+	body, err := j.do(http.MethodGet, endpoint, nil)
+	if err != nil {
+		return "", err
+	}
+	var existing struct {
+		SHA string `json:"sha"`
+	}
+	if err := json.Unmarshal(body, &existing); err != nil {
+		return "", fmt.Errorf("failed to parse journal file %s: %w", file, err)
+	}
+	return existing.SHA, nil
+}
+
+// commit creates or updates the file. GitHub does both with PUT; Gitea
+// creates files with POST.
+func (j *Journal) commit(file, content, sha, message string) error {
+	body, err := json.Marshal(fileRequest{
+		Message: message,
+		Content: base64.StdEncoding.EncodeToString([]byte(content)),
+		SHA:     sha,
+		Branch:  j.config.Branch,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to encode journal commit: %w", err)
+	}
+	method := http.MethodPut
+	if sha == "" && j.config.Provider == "gitea" {
+		method = http.MethodPost
+	}
+	_, err = j.do(method, j.contentsURL(file), body)
+	return err
+}
+
+func (j *Journal) contentsURL(file string) string {
+	segments := strings.Split(file, "/")
+	for i, segment := range segments {
+		segments[i] = url.PathEscape(segment)
+	}
+	return strings.TrimRight(j.config.APIURL, "/") + "/repos/" + j.config.Repo + "/contents/" + strings.Join(segments, "/")
+}
+
+func (j *Journal) do(method, endpoint string, body []byte) ([]byte, error) {
+	req, err := http.NewRequest(method, endpoint, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create journal request: %w", err)
+	}
+	req.Header.Set("Authorization", "token "+j.config.Token)
+	req.Header.Set("Accept", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := j.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to reach the journal repository: %w", err)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read journal response: %w", err)
+	}
+	if resp.StatusCode == http.StatusNotFound && method == http.MethodGet {
+		return nil, errNotFound
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, fmt.Errorf("journal API error (status %d): %s", resp.StatusCode, string(respBody))
+	}
+	return respBody, nil
+}
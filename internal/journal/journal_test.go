@@ -0,0 +1,110 @@
+package journal
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sathyabhat/ynab-weekly-wrap/internal/config"
+	"github.com/sathyabhat/ynab-weekly-wrap/internal/pipeline"
+	"github.com/sathyabhat/ynab-weekly-wrap/internal/publisher"
+	"github.com/sathyabhat/ynab-weekly-wrap/internal/ynab"
+)
+
+type request struct {
+	method, path, auth string
+	body               fileRequest
+}
+
+// fakeRepo serves the contents API, holding files by path
+func fakeRepo(t *testing.T, files map[string]string) (*httptest.Server, *[]request) {
+	t.Helper()
+	var requests []request
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		req := request{method: r.Method, path: r.URL.Path, auth: r.Header.Get("Authorization")}
+		json.NewDecoder(r.Body).Decode(&req.body)
+		requests = append(requests, req)
+
+		sha, ok := files[r.URL.Path]
+		switch {
+		case r.Method == http.MethodGet && !ok:
+			http.NotFound(w, r)
+		case r.Method == http.MethodGet:
+			json.NewEncoder(w).Encode(map[string]string{"sha": sha})
+		default:
+			files[r.URL.Path] = "new-sha"
+			w.WriteHeader(http.StatusCreated)
+			w.Write([]byte(`{}`))
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server, &requests
+}
+
+func weeklyRun() *pipeline.Run {
+	return &pipeline.Run{
+		PeriodEnd: time.Date(2026, 1, 25, 23, 59, 59, 0, time.UTC),
+		Weekly:    &ynab.WeeklyData{},
+		Report: publisher.Report{
+			Title:   "Weekly Financial Wrap - 2026-01-19 to 2026-01-25",
+			Message: "💰 **Total Spent**: $120\n• Groceries",
+		},
+	}
+}
+
+func TestExport_CreatesThenUpdates(t *testing.T) {
+	server, requests := fakeRepo(t, map[string]string{})
+	j := New(config.JournalConfig{Repo: "me/money", Token: "secret", Provider: "github", APIURL: server.URL, Dir: "journal"})
+
+	if err := j.Export(weeklyRun()); err != nil {
+		t.Fatalf("Export failed: %v", err)
+	}
+	const path = "/repos/me/money/contents/journal/2026/2026-01-25-weekly.md"
+	if len(*requests) != 2 || (*requests)[1].method != http.MethodPut || (*requests)[1].path != path {
+		t.Fatalf("expected a lookup and a PUT to %s, got %+v", path, *requests)
+	}
+	create := (*requests)[1]
+	if create.auth != "token secret" || create.body.SHA != "" || create.body.Message != "Add weekly wrap for 2026-01-25" {
+		t.Errorf("unexpected create request %+v", create)
+	}
+	content, _ := base64.StdEncoding.DecodeString(create.body.Content)
+	want := "# Weekly Financial Wrap - 2026-01-19 to 2026-01-25\n\n💰 **Total Spent**: $120  \n• Groceries\n"
+	if string(content) != want {
+		t.Errorf("content: got %q, want %q", content, want)
+	}
+
+	if err := j.Export(weeklyRun()); err != nil {
+		t.Fatalf("second Export failed: %v", err)
+	}
+	if update := (*requests)[3]; update.body.SHA != "new-sha" || !strings.HasPrefix(update.body.Message, "Update") {
+		t.Errorf("expected the file to be updated with its SHA, got %+v", update)
+	}
+}
+
+func TestExport_GiteaCreatesWithPost(t *testing.T) {
+	server, requests := fakeRepo(t, map[string]string{})
+	j := New(config.JournalConfig{Repo: "me/money", Token: "secret", Provider: "gitea", APIURL: server.URL + "/", Branch: "wraps", Dir: "journal"})
+
+	if err := j.Export(weeklyRun()); err != nil {
+		t.Fatalf("Export failed: %v", err)
+	}
+	if create := (*requests)[1]; create.method != http.MethodPost || create.body.Branch != "wraps" {
+		t.Errorf("expected a POST to the wraps branch, got %+v", create)
+	}
+}
+
+func TestExport_SkipsOtherRuns(t *testing.T) {
+	server, requests := fakeRepo(t, map[string]string{})
+	j := New(config.JournalConfig{Repo: "me/money", Token: "secret", APIURL: server.URL})
+
+	if err := j.Export(&pipeline.Run{Report: publisher.Report{Message: "daily"}}); err != nil {
+		t.Fatalf("Export failed: %v", err)
+	}
+	if len(*requests) != 0 {
+		t.Errorf("expected nothing committed for a daily digest, got %+v", *requests)
+	}
+}
@@ -0,0 +1,96 @@
+package matrix
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/sathyabhat/ynab-weekly-wrap/internal/config"
+	"github.com/sathyabhat/ynab-weekly-wrap/internal/publisher"
+	"github.com/sathyabhat/ynab-weekly-wrap/internal/render"
+)
+
+// Publisher implements the publisher.Publisher interface by posting to a
+// Matrix room through the client-server API
+type Publisher struct {
+	config config.MatrixConfig
+}
+
+// Message is the m.room.message event content sent to the room
+type Message struct {
+	MsgType       string `json:"msgtype"`
+	Body          string `json:"body"`
+	Format        string `json:"format,omitempty"`
+	FormattedBody string `json:"formatted_body,omitempty"`
+}
+
+// NewPublisher creates a new Matrix room publisher
+func NewPublisher(matrixConfig config.MatrixConfig) *Publisher {
+	return &Publisher{config: matrixConfig}
+}
+
+// Name identifies the destination in delivery summaries
+func (p *Publisher) Name() string {
+	return "matrix"
+}
+
+// Publish sends the message to the room as formatted HTML, with a plain-text
+// fallback for clients that don't render HTML
+func (p *Publisher) Publish(message string) error {
+	return p.sendDocument(render.Parse(message))
+}
+
+// PublishReport sends the report's rendered document to the room
+func (p *Publisher) PublishReport(report publisher.Report) error {
+	return p.sendDocument(report.Doc())
+}
+
+func (p *Publisher) sendDocument(doc *render.Document) error {
+	return p.send(Message{
+		MsgType:       "m.text",
+		Body:          render.Plain.Format(doc),
+		Format:        "org.matrix.custom.html",
+		FormattedBody: render.HTML.Format(doc),
+	})
+}
+
+func (p *Publisher) send(message Message) error {
+	log.Printf("Sending message to Matrix room %s", p.config.RoomID)
+
+	body, err := json.Marshal(message)
+	if err != nil {
+		return fmt.Errorf("failed to marshal Matrix message: %w", err)
+	}
+
+	// The transaction ID makes retries of the same request idempotent
+	txnID := fmt.Sprintf("wrap-%d", time.Now().UnixNano())
+	endpoint := fmt.Sprintf("%s/_matrix/client/v3/rooms/%s/send/m.room.message/%s",
+		strings.TrimRight(p.config.HomeserverURL, "/"), url.PathEscape(p.config.RoomID), txnID)
+
+	req, err := http.NewRequest(http.MethodPut, endpoint, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create Matrix request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+p.config.AccessToken)
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send Matrix message: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		respBody, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("matrix API error (status %d): %s", resp.StatusCode, string(respBody))
+	}
+
+	log.Println("Matrix message sent successfully")
+	return nil
+}
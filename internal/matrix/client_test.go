@@ -0,0 +1,67 @@
+package matrix
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/sathyabhat/ynab-weekly-wrap/internal/config"
+)
+
+func TestPublish_SendsFormattedMessage(t *testing.T) {
+	var got Message
+	var path, auth string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPut {
+			t.Errorf("Expected PUT request, got %s", r.Method)
+		}
+		path = r.URL.EscapedPath()
+		auth = r.Header.Get("Authorization")
+		if err := json.NewDecoder(r.Body).Decode(&got); err != nil {
+			t.Errorf("Failed to decode request body: %v", err)
+		}
+		w.Write([]byte(`{"event_id":"$abc"}`))
+	}))
+	defer server.Close()
+
+	p := NewPublisher(config.MatrixConfig{
+		HomeserverURL: server.URL + "/",
+		AccessToken:   "token",
+		RoomID:        "!room:example.org",
+	})
+	if err := p.Publish("📊 **Weekly Wrap**\nSpent <$100>"); err != nil {
+		t.Fatalf("Publish failed: %v", err)
+	}
+
+	if !strings.HasPrefix(path, "/_matrix/client/v3/rooms/%21room:example.org/send/m.room.message/") {
+		t.Errorf("unexpected path: %s", path)
+	}
+	if auth != "Bearer token" {
+		t.Errorf("Authorization: got %q, want %q", auth, "Bearer token")
+	}
+	if got.MsgType != "m.text" || got.Format != "org.matrix.custom.html" {
+		t.Errorf("unexpected message type/format: %+v", got)
+	}
+	if got.Body != "📊 Weekly Wrap\nSpent <$100>" {
+		t.Errorf("Body: got %q", got.Body)
+	}
+	if got.FormattedBody != "📊 <strong>Weekly Wrap</strong><br>\nSpent &lt;$100&gt;" {
+		t.Errorf("FormattedBody: got %q", got.FormattedBody)
+	}
+}
+
+func TestPublish_APIError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+		w.Write([]byte(`{"errcode":"M_FORBIDDEN"}`))
+	}))
+	defer server.Close()
+
+	p := NewPublisher(config.MatrixConfig{HomeserverURL: server.URL, AccessToken: "token", RoomID: "!room:example.org"})
+	err := p.Publish("test")
+	if err == nil || !strings.Contains(err.Error(), "M_FORBIDDEN") {
+		t.Errorf("expected forbidden error, got %v", err)
+	}
+}
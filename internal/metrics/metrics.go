@@ -0,0 +1,186 @@
+// Package metrics keeps recent wrap timings and API call latencies in
+// memory, for the status endpoint to report on late or failing wraps.
+package metrics
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+const (
+	// maxCalls is how many recent calls are kept per API
+	maxCalls = 500
+	// callWindow is how far back calls are kept for error rates
+	callWindow = 7 * 24 * time.Hour
+	// recentWindow is the window the current error rate is taken over
+	recentWindow = 24 * time.Hour
+)
+
+// Stage is how long one stage of a wrap took
+type Stage struct {
+	Name     string        `json:"name"`
+	Duration time.Duration `json:"-"`
+	Millis   int64         `json:"duration_ms"`
+	Error    string        `json:"error,omitempty"`
+}
+
+// RunStatus is the last run of a wrap
+type RunStatus struct {
+	Name     string    `json:"name"`
+	Finished time.Time `json:"finished"`
+	Millis   int64     `json:"duration_ms"`
+	Error    string    `json:"error,omitempty"`
+	Stages   []Stage   `json:"stages"`
+}
+
+// APIStatus summarizes the recent calls to an API
+type APIStatus struct {
+	Calls int     `json:"calls"` // Calls in the last seven days, up to maxCalls
+	P50   float64 `json:"p50_ms"`
+	P90   float64 `json:"p90_ms"`
+	P99   float64 `json:"p99_ms"`
+	// Share of calls that failed in the last day and in the six days before,
+	// so a rising error rate stands out
+	ErrorRate     float64 `json:"error_rate_24h"`
+	PrevErrorRate float64 `json:"error_rate_prev_6d"`
+}
+
+// Status is what the status endpoint reports
+type Status struct {
+	Runs []RunStatus          `json:"runs"` // Last run of each wrap, most recent first
+	APIs map[string]APIStatus `json:"apis"` // Keyed by API, e.g. "ynab" or "telegram"
+}
+
+type call struct {
+	at       time.Time
+	duration time.Duration
+	failed   bool
+}
+
+// Recorder collects run timings and API calls. It is safe for concurrent
+// use, and a nil Recorder records nothing.
+type Recorder struct {
+	mu    sync.Mutex
+	runs  map[string]RunStatus
+	calls map[string][]call
+	now   func() time.Time
+}
+
+// NewRecorder creates an empty recorder
+func NewRecorder() *Recorder {
+	return &Recorder{
+		runs:  make(map[string]RunStatus),
+		calls: make(map[string][]call),
+		now:   time.Now,
+	}
+}
+
+// ObserveCall records one call to an API
+func (r *Recorder) ObserveCall(api string, duration time.Duration, err error) {
+	if r == nil {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := r.now()
+	// Keep calls inside the window, then the most recent up to the limit
+	var kept []call
+	for _, c := range r.calls[api] {
+		if now.Sub(c.at) <= callWindow {
+			kept = append(kept, c)
+		}
+	}
+	kept = append(kept, call{at: now, duration: duration, failed: err != nil})
+	if len(kept) > maxCalls {
+		kept = kept[len(kept)-maxCalls:]
+	}
+	r.calls[api] = kept
+}
+
+// ObserveRun records a finished run of a wrap, replacing its previous run
+func (r *Recorder) ObserveRun(name string, stages []Stage, err error) {
+	if r == nil {
+		return
+	}
+	run := RunStatus{Name: name, Finished: r.now(), Stages: stages}
+	for i := range run.Stages {
+		run.Stages[i].Millis = run.Stages[i].Duration.Milliseconds()
+		run.Millis += run.Stages[i].Millis
+	}
+	if err != nil {
+		run.Error = err.Error()
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.runs[name] = run
+}
+
+// Status summarizes what has been recorded
+func (r *Recorder) Status() Status {
+	status := Status{Runs: []RunStatus{}, APIs: map[string]APIStatus{}}
+	if r == nil {
+		return status
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, run := range r.runs {
+		status.Runs = append(status.Runs, run)
+	}
+	sort.Slice(status.Runs, func(i, j int) bool { return status.Runs[i].Finished.After(status.Runs[j].Finished) })
+
+	now := r.now()
+	for api, calls := range r.calls {
+		status.APIs[api] = summarize(calls, now)
+	}
+	return status
+}
+
+func summarize(calls []call, now time.Time) APIStatus {
+	durations := make([]time.Duration, len(calls))
+	var recent, recentFailed, prev, prevFailed int
+	for i, c := range calls {
+		durations[i] = c.duration
+		if now.Sub(c.at) <= recentWindow {
+			recent++
+			if c.failed {
+				recentFailed++
+			}
+		} else {
+			prev++
+			if c.failed {
+				prevFailed++
+			}
+		}
+	}
+	sort.Slice(durations, func(i, j int) bool { return durations[i] < durations[j] })
+
+	return APIStatus{
+		Calls:         len(calls),
+		P50:           percentile(durations, 50),
+		P90:           percentile(durations, 90),
+		P99:           percentile(durations, 99),
+		ErrorRate:     rate(recentFailed, recent),
+		PrevErrorRate: rate(prevFailed, prev),
+	}
+}
+
+// percentile returns the nearest-rank percentile of sorted durations, in
+// milliseconds
+func percentile(sorted []time.Duration, p int) float64 {
+	if len(sorted) == 0 {
+		return 0
+	}
+	rank := (p*len(sorted) + 99) / 100
+	return float64(sorted[max(rank, 1)-1].Microseconds()) / 1000
+}
+
+func rate(failed, total int) float64 {
+	if total == 0 {
+		return 0
+	}
+	return float64(failed) / float64(total)
+}
@@ -0,0 +1,82 @@
+package metrics
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestStatus_Percentiles(t *testing.T) {
+	r := NewRecorder()
+	for i := 1; i <= 100; i++ {
+		r.ObserveCall("ynab", time.Duration(i)*time.Millisecond, nil)
+	}
+
+	api := r.Status().APIs["ynab"]
+	if api.Calls != 100 {
+		t.Errorf("Calls = %d, want 100", api.Calls)
+	}
+	if api.P50 != 50 || api.P90 != 90 || api.P99 != 99 {
+		t.Errorf("percentiles = %v/%v/%v, want 50/90/99", api.P50, api.P90, api.P99)
+	}
+}
+
+func TestStatus_ErrorRates(t *testing.T) {
+	now := time.Date(2026, 10, 16, 9, 0, 0, 0, time.UTC)
+	r := NewRecorder()
+	r.now = func() time.Time { return now.AddDate(0, 0, -3) }
+	r.ObserveCall("telegram", time.Millisecond, nil)
+	r.ObserveCall("telegram", time.Millisecond, nil)
+	r.now = func() time.Time { return now.AddDate(0, 0, -10) }
+	r.ObserveCall("telegram", time.Millisecond, errors.New("old"))
+	r.now = func() time.Time { return now }
+	r.ObserveCall("telegram", time.Millisecond, errors.New("boom"))
+	r.ObserveCall("telegram", time.Millisecond, nil)
+
+	api := r.Status().APIs["telegram"]
+	if api.Calls != 4 {
+		t.Errorf("Calls = %d, want 4 (calls over a week old dropped)", api.Calls)
+	}
+	if api.ErrorRate != 0.5 || api.PrevErrorRate != 0 {
+		t.Errorf("error rates = %v/%v, want 0.5/0", api.ErrorRate, api.PrevErrorRate)
+	}
+}
+
+func TestObserveCall_KeepsRecentCalls(t *testing.T) {
+	r := NewRecorder()
+	for range maxCalls + 10 {
+		r.ObserveCall("ynab", time.Millisecond, nil)
+	}
+	if calls := r.Status().APIs["ynab"].Calls; calls != maxCalls {
+		t.Errorf("Calls = %d, want %d", calls, maxCalls)
+	}
+}
+
+func TestObserveRun(t *testing.T) {
+	now := time.Date(2026, 10, 16, 9, 0, 0, 0, time.UTC)
+	r := NewRecorder()
+	r.now = func() time.Time { return now }
+	r.ObserveRun("weekly", []Stage{{Name: "fetch", Duration: 2 * time.Second}}, nil)
+	r.now = func() time.Time { return now.Add(time.Hour) }
+	r.ObserveRun("monthly", []Stage{
+		{Name: "fetch", Duration: time.Second},
+		{Name: "deliver", Duration: 500 * time.Millisecond, Error: "telegram down"},
+	}, errors.New("telegram down"))
+
+	runs := r.Status().Runs
+	if len(runs) != 2 || runs[0].Name != "monthly" || runs[1].Name != "weekly" {
+		t.Fatalf("runs = %+v, want monthly then weekly", runs)
+	}
+	if runs[0].Millis != 1500 || runs[0].Error != "telegram down" {
+		t.Errorf("monthly run = %+v, want 1500ms and the error", runs[0])
+	}
+}
+
+func TestNilRecorder(t *testing.T) {
+	var r *Recorder
+	r.ObserveCall("ynab", time.Second, nil)
+	r.ObserveRun("weekly", nil, nil)
+	if status := r.Status(); len(status.Runs) != 0 || len(status.APIs) != 0 {
+		t.Errorf("Status() = %+v, want empty", status)
+	}
+}
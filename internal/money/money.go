@@ -0,0 +1,158 @@
+package money
+
+import (
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Format is how a budget writes amounts: its currency symbol, where the
+// symbol goes, decimal digits and separators, as set in YNAB's budget
+// settings
+type Format struct {
+	ISOCode          string `json:"iso_code"`
+	Symbol           string `json:"symbol"`
+	SymbolFirst      bool   `json:"symbol_first"`
+	DisplaySymbol    bool   `json:"display_symbol"`
+	DecimalDigits    int    `json:"decimal_digits"`
+	DecimalSeparator string `json:"decimal_separator"`
+	GroupSeparator   string `json:"group_separator"`
+
+	// Rate converts budget amounts into this currency, in units of it per
+	// unit of the budget's; zero shows amounts as they are
+	Rate float64 `json:"rate,omitempty"`
+}
+
+// USD is used when a budget's format isn't known, e.g. for snapshots stored
+// before formats were
+var USD = Format{
+	ISOCode:          "USD",
+	Symbol:           "$",
+	SymbolFirst:      true,
+	DisplaySymbol:    true,
+	DecimalDigits:    2,
+	DecimalSeparator: ".",
+	GroupSeparator:   ",",
+}
+
+// formats are the usual ways of writing currencies a destination can be
+// switched to. Other currencies get their ISO code as the symbol.
+var formats = map[string]Format{
+	"USD": USD,
+	"EUR": {ISOCode: "EUR", Symbol: "€", DisplaySymbol: true, DecimalDigits: 2, DecimalSeparator: ",", GroupSeparator: "."},
+	"GBP": {ISOCode: "GBP", Symbol: "£", SymbolFirst: true, DisplaySymbol: true, DecimalDigits: 2, DecimalSeparator: ".", GroupSeparator: ","},
+	"INR": {ISOCode: "INR", Symbol: "₹", SymbolFirst: true, DisplaySymbol: true, DecimalDigits: 2, DecimalSeparator: ".", GroupSeparator: ","},
+	"JPY": {ISOCode: "JPY", Symbol: "¥", SymbolFirst: true, DisplaySymbol: true, DecimalSeparator: ".", GroupSeparator: ","},
+	"CAD": {ISOCode: "CAD", Symbol: "CA$", SymbolFirst: true, DisplaySymbol: true, DecimalDigits: 2, DecimalSeparator: ".", GroupSeparator: ","},
+	"AUD": {ISOCode: "AUD", Symbol: "A$", SymbolFirst: true, DisplaySymbol: true, DecimalDigits: 2, DecimalSeparator: ".", GroupSeparator: ","},
+}
+
+// ForCode returns the format for an ISO 4217 currency code, such as "INR",
+// converting budget amounts at rate
+func ForCode(code string, rate float64) Format {
+	code = strings.ToUpper(code)
+	f, ok := formats[code]
+	if !ok {
+		f = Format{ISOCode: code, Symbol: code + " ", SymbolFirst: true, DisplaySymbol: true, DecimalDigits: 2, DecimalSeparator: ".", GroupSeparator: ","}
+	}
+	f.Rate = rate
+	return f
+}
+
+// orDefault returns f, or USD when f is the zero Format
+func (f Format) orDefault() Format {
+	if f.ISOCode == "" && f.DecimalSeparator == "" {
+		return USD
+	}
+	return f
+}
+
+// Amount formats milliunits with the currency symbol, dropping the decimals
+// of whole amounts: "$1,234.50", "-$12", "1.234,50€"
+func (f Format) Amount(milliunits int64) string {
+	f = f.orDefault()
+	milliunits = f.convert(milliunits)
+	if units, cents := split(milliunits, f.DecimalDigits); cents == 0 {
+		return f.withSymbol(milliunits < 0 && units != 0, f.group(units))
+	}
+	return f.withSymbol(milliunits < 0, f.number(milliunits, f.DecimalDigits))
+}
+
+// Whole formats milliunits rounded to whole units, for places with little
+// room such as images: "$1,235"
+func (f Format) Whole(milliunits int64) string {
+	f = f.orDefault()
+	milliunits = f.convert(milliunits)
+	units, _ := split(milliunits, 0)
+	return f.withSymbol(milliunits < 0 && units != 0, f.group(units))
+}
+
+// Delta formats a change with an explicit sign: "+$5", "-$12.50"
+func (f Format) Delta(milliunits int64) string {
+	if milliunits >= 0 {
+		return "+" + f.Amount(milliunits)
+	}
+	return f.Amount(milliunits)
+}
+
+// convert turns budget milliunits into milliunits of f's currency
+func (f Format) convert(milliunits int64) int64 {
+	if f.Rate == 0 || f.Rate == 1 {
+		return milliunits
+	}
+	return int64(math.Round(float64(milliunits) * f.Rate))
+}
+
+// number formats the absolute value of milliunits with digits decimals
+func (f Format) number(milliunits int64, digits int) string {
+	units, fraction := split(milliunits, digits)
+	if digits == 0 {
+		return f.group(units)
+	}
+	decimals := strconv.FormatInt(fraction, 10)
+	decimals = strings.Repeat("0", digits-len(decimals)) + decimals
+	return f.group(units) + f.DecimalSeparator + decimals
+}
+
+// split rounds the absolute value of milliunits to digits decimals (at most
+// 3, the precision of milliunits) and returns the whole units and fraction
+func split(milliunits int64, digits int) (units, fraction int64) {
+	digits = min(max(digits, 0), 3)
+	scale := int64(1)
+	for range 3 - digits {
+		scale *= 10
+	}
+	rounded := (max(milliunits, -milliunits) + scale/2) / scale
+
+	perUnit := int64(1)
+	for range digits {
+		perUnit *= 10
+	}
+	return rounded / perUnit, rounded % perUnit
+}
+
+// group writes units with the group separator every three digits
+func (f Format) group(units int64) string {
+	digits := strconv.FormatInt(units, 10)
+	var groups []string
+	for len(digits) > 3 {
+		groups = append([]string{digits[len(digits)-3:]}, groups...)
+		digits = digits[:len(digits)-3]
+	}
+	return strings.Join(append([]string{digits}, groups...), f.GroupSeparator)
+}
+
+func (f Format) withSymbol(negative bool, number string) string {
+	sign := ""
+	if negative {
+		sign = "-"
+	}
+	switch {
+	case !f.DisplaySymbol || f.Symbol == "":
+		return sign + number
+	case f.SymbolFirst:
+		return sign + f.Symbol + number
+	default:
+		return sign + number + f.Symbol
+	}
+}
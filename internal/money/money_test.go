@@ -0,0 +1,103 @@
+package money
+
+import "testing"
+
+var euro = Format{
+	ISOCode:          "EUR",
+	Symbol:           "€",
+	DisplaySymbol:    true,
+	DecimalDigits:    2,
+	DecimalSeparator: ",",
+	GroupSeparator:   ".",
+}
+
+var yen = Format{
+	ISOCode:          "JPY",
+	Symbol:           "¥",
+	SymbolFirst:      true,
+	DisplaySymbol:    true,
+	DecimalSeparator: ".",
+	GroupSeparator:   ",",
+}
+
+func TestAmount(t *testing.T) {
+	cases := []struct {
+		format     Format
+		milliunits int64
+		want       string
+	}{
+		{USD, 0, "$0"},
+		{USD, 100_000, "$100"},
+		{USD, 1_234_000, "$1,234"},
+		{USD, -50_000, "-$50"},
+		{USD, 1_500, "$1.50"},
+		{USD, 1_050, "$1.05"},
+		{USD, -1_234_567_890, "-$1,234,567.89"},
+		{USD, 4, "$0"}, // rounds to zero cents
+		{USD, -4, "$0"},
+		{Format{}, 12_340, "$12.34"}, // unknown format
+		{euro, 1_234_500, "1.234,50€"},
+		{euro, -7_000, "-7€"},
+		{yen, 1_234_600, "¥1,235"},
+		{Format{ISOCode: "USD", DecimalDigits: 2, DecimalSeparator: ".", GroupSeparator: ","}, 12_000, "12"}, // symbol hidden
+	}
+	for _, c := range cases {
+		if got := c.format.Amount(c.milliunits); got != c.want {
+			t.Errorf("%s Amount(%d): got %q, want %q", c.format.ISOCode, c.milliunits, got, c.want)
+		}
+	}
+}
+
+func TestWhole(t *testing.T) {
+	cases := map[int64]string{
+		0:             "$0",
+		99_000:        "$99",
+		1_234_560:     "$1,235",
+		-2_500_000:    "-$2,500",
+		1_000_000_000: "$1,000,000",
+	}
+	for milliunits, want := range cases {
+		if got := USD.Whole(milliunits); got != want {
+			t.Errorf("Whole(%d): got %q, want %q", milliunits, got, want)
+		}
+	}
+	if got := euro.Whole(-1_234_500); got != "-1.235€" {
+		t.Errorf("euro Whole: got %q", got)
+	}
+}
+
+func TestDelta(t *testing.T) {
+	cases := map[int64]string{
+		0:       "+$0",
+		5_000:   "+$5",
+		-12_500: "-$12.50",
+	}
+	for milliunits, want := range cases {
+		if got := USD.Delta(milliunits); got != want {
+			t.Errorf("Delta(%d): got %q, want %q", milliunits, got, want)
+		}
+	}
+}
+
+func TestForCode(t *testing.T) {
+	cases := []struct {
+		code       string
+		rate       float64
+		milliunits int64
+		want       string
+	}{
+		{"INR", 83.2, 100_000, "₹8,320"},
+		{"inr", 83.2, -12_345, "-₹1,027.10"},
+		{"GBP", 0, 1_500, "£1.50"}, // no rate: shown as they are
+		{"EUR", 0.92, 10_000, "9,20€"},
+		{"SEK", 10.5, 2_000, "SEK 21"},
+	}
+	for _, c := range cases {
+		if got := ForCode(c.code, c.rate).Amount(c.milliunits); got != c.want {
+			t.Errorf("ForCode(%q, %v).Amount(%d): got %q, want %q", c.code, c.rate, c.milliunits, got, c.want)
+		}
+	}
+	if got := ForCode("INR", 83.2).Whole(1_234_500); got != "₹102,710" {
+		t.Errorf("Whole: got %q, want ₹102,710", got)
+	}
+}
@@ -0,0 +1,160 @@
+package notes
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"unicode"
+)
+
+// Store keeps per-category notes that are shown in every wrap until they
+// are cleared. Notes from the config are defaults; notes set from chat are
+// saved to a JSON file and take precedence, and clearing one from chat
+// hides the default too.
+type Store struct {
+	mu       sync.Mutex
+	path     string
+	defaults map[string]string
+}
+
+// NewStore creates a store saving to path, which may be empty to only use
+// the defaults
+func NewStore(path string, defaults map[string]string) *Store {
+	return &Store{path: path, defaults: defaults}
+}
+
+// Writable reports whether notes can be set, i.e. a file is configured
+func (s *Store) Writable() bool {
+	return s.path != ""
+}
+
+// All returns the current notes keyed by category name
+func (s *Store) All() (map[string]string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	saved, err := s.load()
+	if err != nil {
+		return nil, err
+	}
+	notes := make(map[string]string, len(s.defaults)+len(saved))
+	for category, note := range s.defaults {
+		if _, overridden := find(saved, category); !overridden {
+			notes[category] = note
+		}
+	}
+	for category, note := range saved {
+		if note != "" {
+			notes[category] = note
+		}
+	}
+	return notes, nil
+}
+
+// Set saves a note for a category, replacing any earlier one. An empty note
+// clears it.
+func (s *Store) Set(category, note string) error {
+	if !s.Writable() {
+		return fmt.Errorf("notes can't be changed without a notes file")
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	saved, err := s.load()
+	if err != nil {
+		return err
+	}
+	if existing, ok := find(saved, category); ok {
+		delete(saved, existing)
+	}
+	category, note = strings.TrimSpace(category), strings.TrimSpace(note)
+	if _, isDefault := find(s.defaults, category); note != "" || isDefault {
+		saved[category] = note
+	}
+
+	data, err := json.MarshalIndent(saved, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode notes: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(s.path), 0o755); err != nil {
+		return fmt.Errorf("failed to create notes directory: %w", err)
+	}
+	if err := os.WriteFile(s.path, data, 0o644); err != nil {
+		return fmt.Errorf("failed to save notes: %w", err)
+	}
+	return nil
+}
+
+func (s *Store) load() (map[string]string, error) {
+	saved := make(map[string]string)
+	if s.path == "" {
+		return saved, nil
+	}
+	data, err := os.ReadFile(s.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return saved, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read notes: %w", err)
+	}
+	if err := json.Unmarshal(data, &saved); err != nil {
+		return nil, fmt.Errorf("failed to parse notes %s: %w", s.path, err)
+	}
+	return saved, nil
+}
+
+// Lookup returns the note for a category. Names match as in SameCategory,
+// so "groceries" finds the note for "🛒 Groceries".
+func Lookup(notes map[string]string, category string) string {
+	if name, ok := find(notes, category); ok {
+		return notes[name]
+	}
+	return ""
+}
+
+// Unlisted returns, sorted, the categories with notes that aren't among
+// listed, so a wrap can show them apart from its category lists
+func Unlisted(notes map[string]string, listed []string) []string {
+	var categories []string
+	for category := range notes {
+		shown := slices.ContainsFunc(listed, func(name string) bool {
+			return SameCategory(name, category)
+		})
+		if !shown {
+			categories = append(categories, category)
+		}
+	}
+	sort.Strings(categories)
+	return categories
+}
+
+// SameCategory reports whether two names refer to the same category,
+// ignoring case, emoji and punctuation
+func SameCategory(a, b string) bool {
+	return normalize(a) == normalize(b)
+}
+
+// find returns the key in notes that names the same category
+func find(notes map[string]string, category string) (string, bool) {
+	for name := range notes {
+		if SameCategory(name, category) {
+			return name, true
+		}
+	}
+	return "", false
+}
+
+func normalize(name string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return unicode.ToLower(r)
+		}
+		return -1
+	}, name)
+}
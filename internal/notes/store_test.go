@@ -0,0 +1,73 @@
+package notes
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestStore_SetAndClear(t *testing.T) {
+	store := NewStore(filepath.Join(t.TempDir(), "notes.json"), nil)
+
+	if err := store.Set("Groceries", "switching to weekly meal prep"); err != nil {
+		t.Fatalf("Set failed: %v", err)
+	}
+	// A later note for the same category replaces it, whatever the casing
+	if err := store.Set("groceries", "meal prep on Sundays"); err != nil {
+		t.Fatalf("Set failed: %v", err)
+	}
+
+	reopened := NewStore(store.path, nil)
+	notes, err := reopened.All()
+	if err != nil {
+		t.Fatalf("All failed: %v", err)
+	}
+	if len(notes) != 1 || notes["groceries"] != "meal prep on Sundays" {
+		t.Errorf("expected the latest note to be kept, got %v", notes)
+	}
+
+	if err := reopened.Set("Groceries", ""); err != nil {
+		t.Fatalf("Set failed: %v", err)
+	}
+	if notes, _ := reopened.All(); len(notes) != 0 {
+		t.Errorf("expected the note to be cleared, got %v", notes)
+	}
+}
+
+func TestStore_Defaults(t *testing.T) {
+	defaults := map[string]string{"Car Maintenance": "saving for new tyres", "Gifts": "birthday season"}
+	store := NewStore(filepath.Join(t.TempDir(), "notes.json"), defaults)
+
+	store.Set("Car Maintenance", "tyres bought, back to normal")
+	store.Set("gifts", "")
+
+	notes, err := store.All()
+	if err != nil {
+		t.Fatalf("All failed: %v", err)
+	}
+	if len(notes) != 1 || notes["Car Maintenance"] != "tyres bought, back to normal" {
+		t.Errorf("expected chat notes to override and clear defaults, got %v", notes)
+	}
+}
+
+func TestStore_ReadOnly(t *testing.T) {
+	store := NewStore("", map[string]string{"Gifts": "birthday season"})
+	if store.Writable() {
+		t.Error("expected a store without a file to be read-only")
+	}
+	if err := store.Set("Gifts", ""); err == nil {
+		t.Error("expected an error setting a note without a file")
+	}
+	if notes, _ := store.All(); notes["Gifts"] != "birthday season" {
+		t.Errorf("expected the default note, got %v", notes)
+	}
+}
+
+func TestLookup(t *testing.T) {
+	notes := map[string]string{"groceries": "meal prep"}
+	if got := Lookup(notes, "🛒 Groceries"); got != "meal prep" {
+		t.Errorf("Lookup: got %q", got)
+	}
+	if got := Lookup(notes, "Dining Out"); got != "" {
+		t.Errorf("Lookup: expected no note, got %q", got)
+	}
+}
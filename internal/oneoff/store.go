@@ -0,0 +1,200 @@
+package oneoff
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// Send is a wrap queued to go out once at a set time, e.g. before a budget
+// meeting
+type Send struct {
+	ID     int       `json:"id"`
+	At     time.Time `json:"at"`
+	Period string    `json:"period"` // "weekly" or "monthly"
+}
+
+// Store keeps queued sends in a JSON file, so they survive restarts and can
+// be added by the schedule-once command while the scheduler is running.
+// Changes are made under a lock on a file next to it, which the two
+// processes share.
+type Store struct {
+	mu       sync.Mutex
+	path     string
+	location *time.Location
+}
+
+// NewStore creates a store saving to path. Times given without a timezone
+// are read in location.
+func NewStore(path string, location *time.Location) *Store {
+	return &Store{path: path, location: location}
+}
+
+// Location returns the timezone sends are scheduled in
+func (s *Store) Location() *time.Location {
+	return s.location
+}
+
+// Add queues a send of the given period's wrap at a time
+func (s *Store) Add(at time.Time, period string) (Send, error) {
+	if period != "weekly" && period != "monthly" {
+		return Send{}, fmt.Errorf("period must be weekly or monthly, got %q", period)
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	unlock, err := s.lock()
+	if err != nil {
+		return Send{}, err
+	}
+	defer unlock()
+
+	sends, err := s.load()
+	if err != nil {
+		return Send{}, err
+	}
+	send := Send{ID: 1, At: at.In(s.location), Period: period}
+	for _, existing := range sends {
+		send.ID = max(send.ID, existing.ID+1)
+	}
+	if err := s.save(append(sends, send)); err != nil {
+		return Send{}, err
+	}
+	return send, nil
+}
+
+// Pending returns the queued sends, soonest first
+func (s *Store) Pending() ([]Send, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.load()
+}
+
+// Cancel removes a queued send, reporting whether it was found
+func (s *Store) Cancel(id int) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	unlock, err := s.lock()
+	if err != nil {
+		return false, err
+	}
+	defer unlock()
+
+	sends, err := s.load()
+	if err != nil {
+		return false, err
+	}
+	kept := sends[:0]
+	for _, send := range sends {
+		if send.ID != id {
+			kept = append(kept, send)
+		}
+	}
+	if len(kept) == len(sends) {
+		return false, nil
+	}
+	return true, s.save(kept)
+}
+
+// TakeDue removes and returns the sends due by now. They are removed before
+// they run, so a send that fails is reported once rather than repeated
+// every minute.
+func (s *Store) TakeDue(now time.Time) ([]Send, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	unlock, err := s.lock()
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	sends, err := s.load()
+	if err != nil {
+		return nil, err
+	}
+	var due, kept []Send
+	for _, send := range sends {
+		if send.At.After(now) {
+			kept = append(kept, send)
+		} else {
+			due = append(due, send)
+		}
+	}
+	if len(due) == 0 {
+		return nil, nil
+	}
+	return due, s.save(kept)
+}
+
+// lock takes an exclusive lock on the store's lock file, waiting for the
+// other process to finish its change first, and returns the function that
+// releases it
+func (s *Store) lock() (func(), error) {
+	if err := os.MkdirAll(filepath.Dir(s.path), 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create scheduled sends directory: %w", err)
+	}
+	f, err := os.OpenFile(s.path+".lock", os.O_RDWR|os.O_CREATE, 0o644)
+	if err != nil {
+		return nil, fmt.Errorf("failed to lock scheduled sends: %w", err)
+	}
+	if err := syscall.Flock(int(f.Fd()), syscall.LOCK_EX); err != nil {
+		f.Close()
+		return nil, fmt.Errorf("failed to lock scheduled sends: %w", err)
+	}
+	return func() {
+		syscall.Flock(int(f.Fd()), syscall.LOCK_UN)
+		f.Close()
+	}, nil
+}
+
+func (s *Store) load() ([]Send, error) {
+	data, err := os.ReadFile(s.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read scheduled sends: %w", err)
+	}
+	var sends []Send
+	if err := json.Unmarshal(data, &sends); err != nil {
+		return nil, fmt.Errorf("failed to parse scheduled sends %s: %w", s.path, err)
+	}
+	sort.Slice(sends, func(i, j int) bool { return sends[i].At.Before(sends[j].At) })
+	return sends, nil
+}
+
+// save writes the file through a temporary file of its own, so the scheduler
+// never reads one that schedule-once has only half written
+func (s *Store) save(sends []Send) error {
+	if sends == nil {
+		sends = []Send{}
+	}
+	data, err := json.MarshalIndent(sends, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode scheduled sends: %w", err)
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(s.path), filepath.Base(s.path)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to save scheduled sends: %w", err)
+	}
+	defer os.Remove(tmp.Name()) // Fails harmlessly once renamed
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to save scheduled sends: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to save scheduled sends: %w", err)
+	}
+	if err := os.Chmod(tmp.Name(), 0o644); err != nil {
+		return fmt.Errorf("failed to save scheduled sends: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), s.path); err != nil {
+		return fmt.Errorf("failed to save scheduled sends: %w", err)
+	}
+	return nil
+}
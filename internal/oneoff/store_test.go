@@ -0,0 +1,91 @@
+package oneoff
+
+import (
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestStore_AddTakeDueAndCancel(t *testing.T) {
+	store := NewStore(filepath.Join(t.TempDir(), "sends", "once.json"), time.UTC)
+	now := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+
+	if pending, err := store.Pending(); err != nil || len(pending) != 0 {
+		t.Fatalf("expected no sends before the file exists, got %v, %v", pending, err)
+	}
+
+	later, err := store.Add(now.Add(2*time.Hour), "monthly")
+	if err != nil {
+		t.Fatalf("Add failed: %v", err)
+	}
+	soon, _ := store.Add(now.Add(time.Hour), "weekly")
+	cancelled, _ := store.Add(now.Add(3*time.Hour), "weekly")
+	if later.ID != 1 || soon.ID != 2 || cancelled.ID != 3 {
+		t.Errorf("expected increasing IDs, got %d, %d, %d", later.ID, soon.ID, cancelled.ID)
+	}
+	if _, err := store.Add(now, "daily"); err == nil {
+		t.Error("expected an error for an unknown period")
+	}
+
+	if found, err := store.Cancel(cancelled.ID); err != nil || !found {
+		t.Errorf("expected #%d to be cancelled, got %v, %v", cancelled.ID, found, err)
+	}
+	if found, _ := store.Cancel(cancelled.ID); found {
+		t.Error("expected a second cancel to find nothing")
+	}
+
+	// A second store reads what the first saved, as after a restart
+	store = NewStore(store.path, time.UTC)
+	pending, err := store.Pending()
+	if err != nil {
+		t.Fatalf("Pending failed: %v", err)
+	}
+	if len(pending) != 2 || pending[0].ID != soon.ID || pending[1].ID != later.ID {
+		t.Fatalf("expected the sends soonest first, got %+v", pending)
+	}
+
+	due, err := store.TakeDue(now.Add(90 * time.Minute))
+	if err != nil {
+		t.Fatalf("TakeDue failed: %v", err)
+	}
+	if len(due) != 1 || due[0].ID != soon.ID || due[0].Period != "weekly" {
+		t.Errorf("expected only #%d to be due, got %+v", soon.ID, due)
+	}
+	if pending, _ := store.Pending(); len(pending) != 1 || pending[0].ID != later.ID {
+		t.Errorf("expected the due send to be removed, got %+v", pending)
+	}
+}
+
+// The scheduler and the schedule-once command each have their own store on
+// the same file; neither may drop the other's changes
+func TestStore_SharedFileKeepsEveryChange(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "once.json")
+	scheduler, command := NewStore(path, time.UTC), NewStore(path, time.UTC)
+	now := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+
+	var wg sync.WaitGroup
+	for i := range 20 {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			if _, err := command.Add(now.Add(time.Duration(i+1)*time.Hour), "weekly"); err != nil {
+				t.Errorf("Add failed: %v", err)
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			if _, err := scheduler.Add(now.Add(-time.Minute), "monthly"); err != nil {
+				t.Errorf("Add failed: %v", err)
+			}
+			if _, err := scheduler.TakeDue(now); err != nil {
+				t.Errorf("TakeDue failed: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if pending, err := scheduler.Pending(); err != nil || len(pending) != 20 {
+		t.Errorf("expected all 20 sends kept, got %d, %v", len(pending), err)
+	}
+}
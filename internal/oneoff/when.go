@@ -0,0 +1,121 @@
+package oneoff
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// defaultHour is used when a day is given without a time, matching the
+// default schedule
+const defaultHour = 9
+
+var weekdays = map[string]time.Weekday{
+	"sun": time.Sunday, "sunday": time.Sunday,
+	"mon": time.Monday, "monday": time.Monday,
+	"tue": time.Tuesday, "tues": time.Tuesday, "tuesday": time.Tuesday,
+	"wed": time.Wednesday, "wednesday": time.Wednesday,
+	"thu": time.Thursday, "thur": time.Thursday, "thurs": time.Thursday, "thursday": time.Thursday,
+	"fri": time.Friday, "friday": time.Friday,
+	"sat": time.Saturday, "saturday": time.Saturday,
+}
+
+// ParseWhen reads a time to send at, relative to now and in its timezone:
+//
+//	friday 6pm
+//	this fri at 18:30
+//	tomorrow 9am
+//	2026-10-23 18:00
+//	18:00            (today, or tomorrow once it has passed)
+//
+// A weekday means its next occurrence, so "friday" on a Friday evening is a
+// week away. The time must be in the future.
+func ParseWhen(text string, now time.Time) (time.Time, error) {
+	var (
+		date         time.Time
+		dateSet      bool
+		weekday      bool
+		hour, minute = defaultHour, 0
+		clockGiven   bool
+	)
+	for _, word := range strings.Fields(strings.ToLower(text)) {
+		switch word {
+		case "at", "on", "this", "next":
+			continue
+		case "today":
+			date, dateSet = now, true
+			continue
+		case "tomorrow":
+			date, dateSet = now.AddDate(0, 0, 1), true
+			continue
+		}
+
+		if day, ok := weekdays[word]; ok {
+			ahead := (int(day) - int(now.Weekday()) + 7) % 7
+			date, dateSet, weekday = now.AddDate(0, 0, ahead), true, true
+			continue
+		}
+		if d, err := time.ParseInLocation("2006-01-02", word, now.Location()); err == nil {
+			date, dateSet = d, true
+			continue
+		}
+		h, m, err := parseClock(word)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("can't read %q as a time, try e.g. \"friday 6pm\" or \"2026-10-23 18:00\"", text)
+		}
+		hour, minute, clockGiven = h, m, true
+	}
+	if !dateSet && !clockGiven {
+		return time.Time{}, fmt.Errorf("no time given, try e.g. \"friday 6pm\"")
+	}
+	if !dateSet {
+		date = now
+	}
+
+	at := time.Date(date.Year(), date.Month(), date.Day(), hour, minute, 0, 0, now.Location())
+	if !at.After(now) {
+		switch {
+		case weekday:
+			at = at.AddDate(0, 0, 7)
+		case !dateSet:
+			at = at.AddDate(0, 0, 1)
+		default:
+			return time.Time{}, fmt.Errorf("%s is in the past", at.Format("Mon 2 Jan 15:04"))
+		}
+	}
+	return at, nil
+}
+
+// parseClock reads "18:00", "6pm" or "6:30pm" as an hour and minute
+func parseClock(word string) (hour, minute int, err error) {
+	pm := strings.HasSuffix(word, "pm")
+	twelveHour := pm || strings.HasSuffix(word, "am")
+	word = strings.TrimSuffix(strings.TrimSuffix(word, "pm"), "am")
+
+	hourStr, minStr, hasMin := strings.Cut(word, ":")
+	if !hasMin && !twelveHour {
+		return 0, 0, fmt.Errorf("ambiguous time %q", word)
+	}
+	if hour, err = strconv.Atoi(hourStr); err != nil {
+		return 0, 0, err
+	}
+	if hasMin {
+		if minute, err = strconv.Atoi(minStr); err != nil || len(minStr) != 2 || minute > 59 {
+			return 0, 0, fmt.Errorf("invalid minutes in %q", word)
+		}
+	}
+
+	if twelveHour {
+		if hour < 1 || hour > 12 {
+			return 0, 0, fmt.Errorf("invalid hour in %q", word)
+		}
+		hour %= 12
+		if pm {
+			hour += 12
+		}
+	} else if hour < 0 || hour > 23 {
+		return 0, 0, fmt.Errorf("invalid hour in %q", word)
+	}
+	return hour, minute, nil
+}
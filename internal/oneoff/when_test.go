@@ -0,0 +1,46 @@
+package oneoff
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseWhen(t *testing.T) {
+	auckland, err := time.LoadLocation("Pacific/Auckland")
+	if err != nil {
+		t.Fatalf("LoadLocation failed: %v", err)
+	}
+	now := time.Date(2026, 10, 16, 19, 0, 0, 0, auckland) // Friday evening
+
+	cases := []struct {
+		text string
+		want time.Time
+	}{
+		{"friday 6pm", time.Date(2026, 10, 23, 18, 0, 0, 0, auckland)},
+		{"this Friday at 9:30pm", time.Date(2026, 10, 16, 21, 30, 0, 0, auckland)},
+		{"mon", time.Date(2026, 10, 19, 9, 0, 0, 0, auckland)},
+		{"tomorrow 12am", time.Date(2026, 10, 17, 0, 0, 0, 0, auckland)},
+		{"2026-10-30 18:00", time.Date(2026, 10, 30, 18, 0, 0, 0, auckland)},
+		{"20:15", time.Date(2026, 10, 16, 20, 15, 0, 0, auckland)},
+		{"7am", time.Date(2026, 10, 17, 7, 0, 0, 0, auckland)},
+	}
+	for _, c := range cases {
+		got, err := ParseWhen(c.text, now)
+		if err != nil {
+			t.Errorf("ParseWhen(%q) failed: %v", c.text, err)
+			continue
+		}
+		if !got.Equal(c.want) {
+			t.Errorf("ParseWhen(%q): got %s, want %s", c.text, got, c.want)
+		}
+	}
+}
+
+func TestParseWhen_Invalid(t *testing.T) {
+	now := time.Date(2026, 10, 16, 19, 0, 0, 0, time.UTC)
+	for _, text := range []string{"", "at", "soon", "friday 6", "13pm", "18:7", "today 6pm", "2026-10-01 9am"} {
+		if got, err := ParseWhen(text, now); err == nil {
+			t.Errorf("ParseWhen(%q): expected an error, got %s", text, got)
+		}
+	}
+}
@@ -0,0 +1,137 @@
+package pause
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// State is a pause of the scheduled sends, e.g. over a holiday
+type State struct {
+	Since time.Time `json:"since"`
+	Until time.Time `json:"until"` // When sends resume
+}
+
+// Active reports whether sends are still paused at now
+func (s *State) Active(now time.Time) bool {
+	return s != nil && now.Before(s.Until)
+}
+
+// Store keeps the pause in a JSON file, so it survives restarts and can be
+// set by the pause command while the scheduler is running
+type Store struct {
+	mu       sync.Mutex
+	path     string
+	location *time.Location
+}
+
+// NewStore creates a store saving to path. Dates a pause ends on are read
+// in location.
+func NewStore(path string, location *time.Location) *Store {
+	return &Store{path: path, location: location}
+}
+
+// Location returns the timezone pauses are set in
+func (s *Store) Location() *time.Location {
+	return s.location
+}
+
+// Pause suspends the scheduled sends from now until a time. Pausing again
+// while paused moves the end of the pause but keeps its start, so the
+// catch-up wrap still covers all of it.
+func (s *Store) Pause(now, until time.Time) (State, error) {
+	if !until.After(now) {
+		return State{}, fmt.Errorf("%s is in the past", until.Format("Mon 2 Jan 15:04"))
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	current, err := s.load()
+	if err != nil {
+		return State{}, err
+	}
+	state := State{Since: now, Until: until}
+	if current != nil {
+		state.Since = current.Since
+	}
+	return state, s.save(&state)
+}
+
+// Current returns the pause, or nil when sends aren't paused. A pause that
+// has ended is returned until TakeEnded removes it.
+func (s *Store) Current() (*State, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.load()
+}
+
+// Resume ends the pause at now, reporting whether sends were paused. The
+// scheduler sees the pause has ended and resumes within a minute.
+func (s *Store) Resume(now time.Time) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	current, err := s.load()
+	if err != nil || current == nil {
+		return false, err
+	}
+	if current.Until.After(now) {
+		current.Until = now
+	}
+	return true, s.save(current)
+}
+
+// TakeEnded removes and returns the pause once it has ended by now, or
+// returns nil while sends are paused or when they aren't
+func (s *Store) TakeEnded(now time.Time) (*State, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	current, err := s.load()
+	if err != nil || current == nil || current.Active(now) {
+		return nil, err
+	}
+	if err := os.Remove(s.path); err != nil {
+		return nil, fmt.Errorf("failed to clear the pause: %w", err)
+	}
+	return current, nil
+}
+
+func (s *Store) load() (*State, error) {
+	data, err := os.ReadFile(s.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the pause: %w", err)
+	}
+	var state State
+	if err := json.Unmarshal(data, &state); err != nil {
+		return nil, fmt.Errorf("failed to parse the pause %s: %w", s.path, err)
+	}
+	return &state, nil
+}
+
+// save writes the file through a temporary file, so the scheduler never
+// reads one that the pause command has only half written
+func (s *Store) save(state *State) error {
+	data, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode the pause: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(s.path), 0o755); err != nil {
+		return fmt.Errorf("failed to create the pause directory: %w", err)
+	}
+	tmp := s.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return fmt.Errorf("failed to save the pause: %w", err)
+	}
+	if err := os.Rename(tmp, s.path); err != nil {
+		return fmt.Errorf("failed to save the pause: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,58 @@
+package pause
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestStore_PauseResumeAndTakeEnded(t *testing.T) {
+	store := NewStore(filepath.Join(t.TempDir(), "state", "pause.json"), time.UTC)
+	now := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+
+	if state, err := store.Current(); err != nil || state != nil {
+		t.Fatalf("expected no pause before the file exists, got %v, %v", state, err)
+	}
+	if _, err := store.Pause(now, now.Add(-time.Hour)); err == nil {
+		t.Error("expected an error for a pause ending in the past")
+	}
+
+	if _, err := store.Pause(now, now.AddDate(0, 0, 7)); err != nil {
+		t.Fatalf("Pause failed: %v", err)
+	}
+	// Extending the pause keeps when it started
+	state, err := store.Pause(now.AddDate(0, 0, 2), now.AddDate(0, 0, 21))
+	if err != nil {
+		t.Fatalf("Pause failed: %v", err)
+	}
+	if !state.Since.Equal(now) || !state.Until.Equal(now.AddDate(0, 0, 21)) {
+		t.Errorf("expected the pause from now for three weeks, got %+v", state)
+	}
+
+	// A second store reads what the first saved, as after a restart
+	store = NewStore(store.path, time.UTC)
+	if current, _ := store.Current(); !current.Active(now.AddDate(0, 0, 20)) {
+		t.Errorf("expected sends to be paused, got %+v", current)
+	}
+	if ended, err := store.TakeEnded(now.AddDate(0, 0, 3)); err != nil || ended != nil {
+		t.Errorf("expected nothing to take while paused, got %v, %v", ended, err)
+	}
+
+	resumed := now.AddDate(0, 0, 5)
+	if found, err := store.Resume(resumed); err != nil || !found {
+		t.Fatalf("expected the pause to be resumed, got %v, %v", found, err)
+	}
+	ended, err := store.TakeEnded(resumed)
+	if err != nil {
+		t.Fatalf("TakeEnded failed: %v", err)
+	}
+	if ended == nil || !ended.Since.Equal(now) || !ended.Until.Equal(resumed) {
+		t.Errorf("expected the pause to end on resuming, got %+v", ended)
+	}
+	if current, _ := store.Current(); current != nil {
+		t.Errorf("expected the pause to be removed, got %+v", current)
+	}
+	if found, _ := store.Resume(resumed); found {
+		t.Error("expected resuming without a pause to find nothing")
+	}
+}
@@ -0,0 +1,43 @@
+package pause
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ParseUntil reads when a pause ends, relative to now and in its timezone:
+//
+//	3w           (three weeks from now)
+//	10d          (ten days)
+//	2m           (two months)
+//	2026-11-01   (the start of that day)
+func ParseUntil(text string, now time.Time) (time.Time, error) {
+	text = strings.ToLower(strings.TrimSpace(text))
+	if day, err := time.ParseInLocation("2006-01-02", text, now.Location()); err == nil {
+		if !day.After(now) {
+			return time.Time{}, fmt.Errorf("%s is in the past", text)
+		}
+		return day, nil
+	}
+
+	usage := fmt.Errorf("can't read %q as a pause, try e.g. \"3w\", \"10d\", \"2m\" or \"2026-11-01\"", text)
+	if len(text) < 2 {
+		return time.Time{}, usage
+	}
+	count, err := strconv.Atoi(text[:len(text)-1])
+	if err != nil || count < 1 {
+		return time.Time{}, usage
+	}
+	switch text[len(text)-1] {
+	case 'd':
+		return now.AddDate(0, 0, count), nil
+	case 'w':
+		return now.AddDate(0, 0, 7*count), nil
+	case 'm':
+		return now.AddDate(0, count, 0), nil
+	default:
+		return time.Time{}, usage
+	}
+}
@@ -0,0 +1,40 @@
+package pause
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseUntil(t *testing.T) {
+	auckland, err := time.LoadLocation("Pacific/Auckland")
+	if err != nil {
+		t.Fatalf("LoadLocation failed: %v", err)
+	}
+	now := time.Date(2026, 10, 16, 19, 0, 0, 0, auckland)
+
+	cases := []struct {
+		text string
+		want time.Time
+	}{
+		{"3w", time.Date(2026, 11, 6, 19, 0, 0, 0, auckland)},
+		{"10D", time.Date(2026, 10, 26, 19, 0, 0, 0, auckland)},
+		{"2m", time.Date(2026, 12, 16, 19, 0, 0, 0, auckland)},
+		{"2026-11-01", time.Date(2026, 11, 1, 0, 0, 0, 0, auckland)},
+	}
+	for _, c := range cases {
+		got, err := ParseUntil(c.text, now)
+		if err != nil {
+			t.Errorf("ParseUntil(%q) failed: %v", c.text, err)
+			continue
+		}
+		if !got.Equal(c.want) {
+			t.Errorf("ParseUntil(%q): got %s, want %s", c.text, got, c.want)
+		}
+	}
+
+	for _, text := range []string{"", "w", "0w", "3y", "three weeks", "2026-10-01"} {
+		if _, err := ParseUntil(text, now); err == nil {
+			t.Errorf("ParseUntil(%q): expected an error", text)
+		}
+	}
+}
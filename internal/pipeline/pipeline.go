@@ -0,0 +1,178 @@
+package pipeline
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/sathyabhat/ynab-weekly-wrap/internal/processor"
+	"github.com/sathyabhat/ynab-weekly-wrap/internal/publisher"
+	"github.com/sathyabhat/ynab-weekly-wrap/internal/ynab"
+)
+
+// Standard stage names, in the order a wrap runs them
+const (
+	StageFetch   = "fetch"
+	StageEnrich  = "enrich"
+	StageAnalyze = "analyze"
+	StageRender  = "render"
+	StageDeliver = "deliver"
+	StageExport  = "export"
+)
+
+// Run carries the state of a single wrap through the pipeline. Each stage
+// reads what earlier stages produced and fills in its own part.
+type Run struct {
+	Name        string // e.g. "Weekly wrap"
+	PeriodStart time.Time
+	PeriodEnd   time.Time
+
+	// Fetch / Enrich
+	Weekly            *ynab.WeeklyData
+	Monthly           *ynab.MonthlyData
+	Daily             *ynab.DailyData
+	Summary           *ynab.PeriodData // Quarterly and yearly summaries
+	Meeting           *ynab.MeetingData
+	PrevCategorySpend map[string]int64 // nil when the previous period is unavailable
+
+	// Analyze / Render
+	Analysis *processor.AnalysisResult
+	Digest   *processor.DailyDigest   // Daily digests only; Analysis is nil for them
+	Totals   *processor.PeriodSummary // Quarterly and yearly summaries only
+	Agenda   *processor.Agenda        // Meeting agendas only
+	Report   publisher.Report
+
+	// Deliver
+	Delivery *publisher.DeliveryResult // nil in dry-run mode or without publishers
+
+	Timings []StageTiming
+}
+
+// StageTiming records how long a stage took and whether it failed
+type StageTiming struct {
+	Stage    string        `json:"stage"`
+	Duration time.Duration `json:"duration"`
+	Err      error         `json:"-"`
+}
+
+// Stage is a single step of the pipeline
+type Stage interface {
+	Name() string
+	Run(ctx context.Context, run *Run) error
+}
+
+type stageFunc struct {
+	name string
+	fn   func(ctx context.Context, run *Run) error
+}
+
+func (s stageFunc) Name() string                            { return s.name }
+func (s stageFunc) Run(ctx context.Context, run *Run) error { return s.fn(ctx, run) }
+
+// NewStage creates a stage from a function
+func NewStage(name string, fn func(ctx context.Context, run *Run) error) Stage {
+	return stageFunc{name: name, fn: fn}
+}
+
+// Hook observes the pipeline; either function may be nil
+type Hook struct {
+	BeforeStage func(stage string, run *Run)
+	AfterStage  func(stage string, run *Run, timing StageTiming)
+}
+
+// Pipeline runs stages in order, stopping at the first error
+type Pipeline struct {
+	stages []Stage
+	hooks  []Hook
+}
+
+// New creates a pipeline from the given stages
+func New(stages ...Stage) *Pipeline {
+	return &Pipeline{stages: stages}
+}
+
+// Use registers a hook that is called around every stage
+func (p *Pipeline) Use(hook Hook) *Pipeline {
+	p.hooks = append(p.hooks, hook)
+	return p
+}
+
+// Stages returns the names of the pipeline's stages in order
+func (p *Pipeline) Stages() []string {
+	names := make([]string, len(p.stages))
+	for i, stage := range p.stages {
+		names[i] = stage.Name()
+	}
+	return names
+}
+
+// Run executes every stage in order. The error from a failing stage is
+// returned wrapped with the stage name; later stages are skipped.
+func (p *Pipeline) Run(ctx context.Context, run *Run) error {
+	for _, stage := range p.stages {
+		if err := ctx.Err(); err != nil {
+			return fmt.Errorf("%s: %w", stage.Name(), err)
+		}
+
+		for _, hook := range p.hooks {
+			if hook.BeforeStage != nil {
+				hook.BeforeStage(stage.Name(), run)
+			}
+		}
+
+		start := time.Now()
+		err := stage.Run(ctx, run)
+		timing := StageTiming{Stage: stage.Name(), Duration: time.Since(start), Err: err}
+		run.Timings = append(run.Timings, timing)
+
+		for _, hook := range p.hooks {
+			if hook.AfterStage != nil {
+				hook.AfterStage(stage.Name(), run, timing)
+			}
+		}
+
+		if err != nil {
+			return fmt.Errorf("%s: %w", stage.Name(), err)
+		}
+	}
+	return nil
+}
+
+// LogTimings is a hook that logs a summary of the stage timings once the
+// last stage has run or a stage fails
+func LogTimings(p *Pipeline) Hook {
+	stages := p.Stages()
+	return Hook{
+		AfterStage: func(stage string, run *Run, timing StageTiming) {
+			if timing.Err == nil && stage != stages[len(stages)-1] {
+				return
+			}
+			parts := make([]string, len(run.Timings))
+			for i, t := range run.Timings {
+				parts[i] = fmt.Sprintf("%s=%s", t.Stage, t.Duration.Round(time.Millisecond))
+			}
+			log.Printf("%s stage timings: %s", run.Name, strings.Join(parts, " "))
+		},
+	}
+}
+
+// Exporter writes a finished run somewhere other than a chat publisher,
+// e.g. a file archive
+type Exporter interface {
+	Export(run *Run) error
+}
+
+// ExportStage runs every exporter. A failing exporter is logged and does not
+// stop the others or fail the run, since the report was already delivered.
+func ExportStage(exporters ...Exporter) Stage {
+	return NewStage(StageExport, func(ctx context.Context, run *Run) error {
+		for _, exporter := range exporters {
+			if err := exporter.Export(run); err != nil {
+				log.Printf("Failed to export %s: %v", run.Name, err)
+			}
+		}
+		return nil
+	})
+}
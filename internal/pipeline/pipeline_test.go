@@ -0,0 +1,87 @@
+package pipeline
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestPipelineRun_StagesInOrderWithTimings(t *testing.T) {
+	var order []string
+	stage := func(name string) Stage {
+		return NewStage(name, func(ctx context.Context, run *Run) error {
+			order = append(order, name)
+			return nil
+		})
+	}
+
+	run := &Run{Name: "Weekly wrap"}
+	p := New(stage(StageFetch), stage(StageAnalyze), stage(StageDeliver))
+	if err := p.Run(context.Background(), run); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if strings.Join(order, ",") != "fetch,analyze,deliver" {
+		t.Errorf("order: got %v", order)
+	}
+	if len(run.Timings) != 3 || run.Timings[1].Stage != StageAnalyze {
+		t.Errorf("unexpected timings: %+v", run.Timings)
+	}
+}
+
+func TestPipelineRun_StopsAtFailingStage(t *testing.T) {
+	errFetch := errors.New("ynab unreachable")
+	delivered := false
+
+	p := New(
+		NewStage(StageFetch, func(ctx context.Context, run *Run) error { return errFetch }),
+		NewStage(StageDeliver, func(ctx context.Context, run *Run) error { delivered = true; return nil }),
+	)
+	err := p.Run(context.Background(), &Run{})
+
+	if !errors.Is(err, errFetch) {
+		t.Fatalf("expected wrapped fetch error, got %v", err)
+	}
+	if !strings.HasPrefix(err.Error(), "fetch: ") {
+		t.Errorf("expected error prefixed with stage name, got %q", err)
+	}
+	if delivered {
+		t.Error("deliver stage should not run after fetch fails")
+	}
+}
+
+func TestPipelineRun_Hooks(t *testing.T) {
+	var events []string
+	p := New(NewStage(StageRender, func(ctx context.Context, run *Run) error { return nil }))
+	p.Use(Hook{
+		BeforeStage: func(stage string, run *Run) { events = append(events, "before:"+stage) },
+		AfterStage:  func(stage string, run *Run, timing StageTiming) { events = append(events, "after:"+timing.Stage) },
+	})
+
+	if err := p.Run(context.Background(), &Run{}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if strings.Join(events, ",") != "before:render,after:render" {
+		t.Errorf("events: got %v", events)
+	}
+}
+
+type failingExporter struct{ calls *int }
+
+func (e failingExporter) Export(run *Run) error {
+	*e.calls++
+	return errors.New("disk full")
+}
+
+func TestExportStage_FailuresDoNotFailRun(t *testing.T) {
+	calls := 0
+	p := New(ExportStage(failingExporter{&calls}, failingExporter{&calls}))
+
+	if err := p.Run(context.Background(), &Run{Name: "Weekly wrap"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if calls != 2 {
+		t.Errorf("exporter calls: got %d, want 2", calls)
+	}
+}
@@ -0,0 +1,53 @@
+package processor
+
+import "github.com/sathyabhat/ynab-weekly-wrap/internal/ynab"
+
+// AccountBalance is an on-budget account's balance, split into what has
+// cleared the bank and what hasn't yet
+type AccountBalance struct {
+	Account   string `json:"account"`
+	Type      string `json:"type"` // checking, savings, creditCard, ...
+	Balance   int64  `json:"balance"`
+	Cleared   int64  `json:"cleared"`
+	Uncleared int64  `json:"uncleared"`
+}
+
+// AccountBalances lists the balances of the open on-budget accounts, in the
+// order YNAB returns them. Tracking accounts aren't part of the budget, so
+// they are left out.
+func AccountBalances(accounts []ynab.Account) []AccountBalance {
+	var balances []AccountBalance
+	for _, account := range accounts {
+		if account.Closed || !account.OnBudget {
+			continue
+		}
+		balances = append(balances, AccountBalance{
+			Account:   account.Name,
+			Type:      account.Type,
+			Balance:   account.Balance,
+			Cleared:   account.ClearedBalance,
+			Uncleared: account.UnclearedBalance,
+		})
+	}
+	return balances
+}
+
+// NetWorth is the total balance of every open account, budget and tracking,
+// with what it was at earlier points when known
+type NetWorth struct {
+	Total      int64  `json:"total"`
+	LastWeek   *int64 `json:"last_week,omitempty"`   // A week earlier; nil when not stored
+	MonthStart *int64 `json:"month_start,omitempty"` // At the start of the budget month; nil when not stored
+}
+
+// TotalBalance adds up the balances of the open accounts, including tracking
+// accounts such as investments and loans
+func TotalBalance(accounts []ynab.Account) int64 {
+	var total int64
+	for _, account := range accounts {
+		if !account.Closed {
+			total += account.Balance
+		}
+	}
+	return total
+}
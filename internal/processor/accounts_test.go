@@ -0,0 +1,42 @@
+package processor
+
+import (
+	"testing"
+
+	"github.com/sathyabhat/ynab-weekly-wrap/internal/ynab"
+)
+
+func TestAccountBalances(t *testing.T) {
+	accounts := []ynab.Account{
+		{Name: "Checking", Type: "checking", OnBudget: true, Balance: 1_234_500, ClearedBalance: 1_000_000, UnclearedBalance: 234_500},
+		{Name: "Mortgage", Type: "mortgage", Balance: -300_000_000, ClearedBalance: -300_000_000},
+		{Name: "Old Savings", Type: "savings", OnBudget: true, Closed: true},
+		{Name: "Visa", Type: "creditCard", OnBudget: true, Balance: -80_000, ClearedBalance: -50_000, UnclearedBalance: -30_000},
+	}
+
+	balances := AccountBalances(accounts)
+	want := []AccountBalance{
+		{Account: "Checking", Type: "checking", Balance: 1_234_500, Cleared: 1_000_000, Uncleared: 234_500},
+		{Account: "Visa", Type: "creditCard", Balance: -80_000, Cleared: -50_000, Uncleared: -30_000},
+	}
+	if len(balances) != len(want) {
+		t.Fatalf("got %+v, want %+v", balances, want)
+	}
+	for i := range want {
+		if balances[i] != want[i] {
+			t.Errorf("balances[%d]: got %+v, want %+v", i, balances[i], want[i])
+		}
+	}
+}
+
+func TestTotalBalance(t *testing.T) {
+	accounts := []ynab.Account{
+		{Name: "Checking", OnBudget: true, Balance: 1_234_500},
+		{Name: "KiwiSaver", Balance: 40_000_000},
+		{Name: "Mortgage", Balance: -300_000_000},
+		{Name: "Old Savings", OnBudget: true, Closed: true, Balance: 5_000},
+	}
+	if got := TotalBalance(accounts); got != -258_765_500 {
+		t.Errorf("got %d, want -258,765,500", got)
+	}
+}
@@ -0,0 +1,131 @@
+package processor
+
+import (
+	"time"
+
+	"github.com/sathyabhat/ynab-weekly-wrap/internal/ynab"
+)
+
+// What an action item asks for
+const (
+	ActionCover   = "cover"   // Cover an overspent category
+	ActionApprove = "approve" // Approve an imported transaction
+)
+
+// approvalLookbackDays is how far before the week unapproved transactions
+// are still suggested for approval
+const approvalLookbackDays = 28
+
+// ActionItem is something the weekly wrap suggests doing in YNAB. Each wrap's
+// items are stored with its snapshot, so the next wrap can tell which were
+// done.
+type ActionItem struct {
+	Kind        string `json:"kind"`                  // ActionCover or ActionApprove
+	Category    string `json:"category,omitempty"`    // Overspent category to cover
+	From        string `json:"from,omitempty"`        // Category to cover it from, if any has money to spare
+	Amount      int64  `json:"amount"`                // How much is overspent, or the transaction's amount
+	Transaction string `json:"transaction,omitempty"` // ID of the transaction to approve
+	Payee       string `json:"payee,omitempty"`
+	CarriedOver bool   `json:"carried_over,omitempty"` // Suggested last week too and still not done
+}
+
+// ActionReview is how last week's action items turned out
+type ActionReview struct {
+	Done    []ActionItem `json:"done,omitempty"`
+	Pending []ActionItem `json:"pending,omitempty"`
+}
+
+// Total returns how many items last week suggested
+func (r *ActionReview) Total() int {
+	return len(r.Done) + len(r.Pending)
+}
+
+// ApprovalWindow returns the first day of unapproved transactions a weekly
+// wrap needs: a week more than it suggests approving, so that last week's
+// suggestions can all still be checked
+func ApprovalWindow(weekStart time.Time) time.Time {
+	return weekStart.AddDate(0, 0, -approvalLookbackDays-7)
+}
+
+// UnapprovedTransactions returns the transactions still to be approved
+func UnapprovedTransactions(transactions []ynab.Transaction) []ynab.Transaction {
+	var unapproved []ynab.Transaction
+	for _, tx := range transactions {
+		if tx.Unapproved && !tx.Deleted {
+			unapproved = append(unapproved, tx)
+		}
+	}
+	return unapproved
+}
+
+// SuggestActions lists what to do this week: cover each overspent category,
+// from the flexible category holding the most money where there is one, and
+// approve the transactions imported in the four weeks before weekStart or
+// since
+func (a *Analyzer) SuggestActions(categories []ynab.Category, unapproved []ynab.Transaction, weekStart time.Time) []ActionItem {
+	var actions []ActionItem
+	for _, over := range a.suggestCovers(categories) {
+		actions = append(actions, ActionItem{
+			Kind:     ActionCover,
+			Category: over.Category,
+			From:     over.CoverFrom,
+			Amount:   over.Overspent,
+		})
+	}
+
+	since := truncateToDay(weekStart).AddDate(0, 0, -approvalLookbackDays)
+	for _, tx := range unapproved {
+		if tx.Date != nil && tx.Date.Before(since) {
+			continue
+		}
+		actions = append(actions, ActionItem{
+			Kind:        ActionApprove,
+			Amount:      tx.Amount,
+			Transaction: tx.ID,
+			Payee:       tx.PayeeName,
+		})
+	}
+	return actions
+}
+
+// ReviewActions sorts last week's action items into done and pending. A
+// category counts as covered once it is no longer overspent, or is gone, and
+// a transaction as approved once it is no longer unapproved. This week's
+// items that were pending are marked as carried over.
+func ReviewActions(previous, actions []ActionItem, categories []ynab.Category, unapproved []ynab.Transaction) *ActionReview {
+	overspent := make(map[string]bool)
+	for _, cat := range categories {
+		if cat.Balance < 0 {
+			overspent[cat.Name] = true
+		}
+	}
+	waiting := make(map[string]bool, len(unapproved))
+	for _, tx := range unapproved {
+		waiting[tx.ID] = true
+	}
+
+	review := &ActionReview{}
+	pending := make(map[ActionItem]bool)
+	for _, item := range previous {
+		item.CarriedOver = false
+		done := !overspent[item.Category]
+		if item.Kind == ActionApprove {
+			done = !waiting[item.Transaction]
+		}
+		if done {
+			review.Done = append(review.Done, item)
+			continue
+		}
+		review.Pending = append(review.Pending, item)
+		pending[actionKey(item)] = true
+	}
+	for i := range actions {
+		actions[i].CarriedOver = pending[actionKey(actions[i])]
+	}
+	return review
+}
+
+// actionKey identifies an action item across weeks, whatever its amount
+func actionKey(item ActionItem) ActionItem {
+	return ActionItem{Kind: item.Kind, Category: item.Category, Transaction: item.Transaction}
+}
@@ -0,0 +1,61 @@
+package processor
+
+import (
+	"testing"
+
+	"github.com/sathyabhat/ynab-weekly-wrap/internal/ynab"
+)
+
+func TestSuggestActions(t *testing.T) {
+	weekStart := makeDate(2026, 3, 16)
+	recent := makeTx("t1", makeDate(2026, 3, 17), -45_000, "Groceries")
+	old := makeTx("t2", makeDate(2026, 2, 10), -12_000, "Dining")
+	categories := []ynab.Category{
+		makeCategory("c1", "Fun Money", 100_000, 60_000),
+		makeCategory("c2", "Dining", 300_000, -50_000),
+	}
+
+	actions := NewAnalyzer().SuggestActions(categories, []ynab.Transaction{recent, old}, *weekStart)
+	want := []ActionItem{
+		{Kind: ActionCover, Category: "Dining", From: "Fun Money", Amount: 50_000},
+		{Kind: ActionApprove, Amount: -45_000, Transaction: "t1", Payee: recent.PayeeName},
+	}
+	if len(actions) != len(want) {
+		t.Fatalf("got %+v, want %+v", actions, want)
+	}
+	for i := range want {
+		if actions[i] != want[i] {
+			t.Errorf("actions[%d]: got %+v, want %+v", i, actions[i], want[i])
+		}
+	}
+}
+
+func TestReviewActions(t *testing.T) {
+	previous := []ActionItem{
+		{Kind: ActionCover, Category: "Dining", From: "Fun Money", Amount: 50_000},
+		{Kind: ActionCover, Category: "Car Repair", Amount: 320_000},
+		{Kind: ActionApprove, Transaction: "t1", Amount: -45_000},
+		{Kind: ActionApprove, Transaction: "t2", Amount: -12_000},
+	}
+	categories := []ynab.Category{
+		makeCategory("c1", "Dining", 300_000, 10_000),
+		makeCategory("c2", "Car Repair", 0, -100_000),
+	}
+	unapproved := []ynab.Transaction{makeTx("t2", makeDate(2026, 3, 12), -12_000, "Dining")}
+	actions := []ActionItem{
+		{Kind: ActionCover, Category: "Car Repair", Amount: 100_000},
+		{Kind: ActionApprove, Transaction: "t2", Amount: -12_000},
+		{Kind: ActionApprove, Transaction: "t3", Amount: -8_000},
+	}
+
+	review := ReviewActions(previous, actions, categories, unapproved)
+	if len(review.Done) != 2 || review.Done[0].Category != "Dining" || review.Done[1].Transaction != "t1" {
+		t.Errorf("Done: got %+v, want Dining covered and t1 approved", review.Done)
+	}
+	if len(review.Pending) != 2 || review.Pending[0].Category != "Car Repair" || review.Pending[1].Transaction != "t2" {
+		t.Errorf("Pending: got %+v, want Car Repair and t2", review.Pending)
+	}
+	if !actions[0].CarriedOver || !actions[1].CarriedOver || actions[2].CarriedOver {
+		t.Errorf("expected only Car Repair and t2 carried over, got %+v", actions)
+	}
+}
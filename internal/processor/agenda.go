@@ -0,0 +1,173 @@
+package processor
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/sathyabhat/ynab-weekly-wrap/internal/money"
+	"github.com/sathyabhat/ynab-weekly-wrap/internal/ynab"
+)
+
+// Agenda is everything that needs a decision at the weekly budget meeting
+type Agenda struct {
+	Meeting       time.Time           `json:"meeting"`
+	Overspent     []OverspentCategory `json:"overspent,omitempty"`
+	Underfunded   []UnderfundedGoal   `json:"underfunded,omitempty"`
+	Unapproved    []ynab.Transaction  `json:"unapproved,omitempty"`
+	Uncategorized []ynab.Transaction  `json:"uncategorized,omitempty"`
+	Bills         []UpcomingBill      `json:"bills,omitempty"` // Large bills due before the next meeting
+	Currency      money.Format        `json:"currency"`
+}
+
+// Empty reports whether nothing needs a decision
+func (a *Agenda) Empty() bool {
+	return len(a.Overspent) == 0 && len(a.Underfunded) == 0 && len(a.Unapproved) == 0 &&
+		len(a.Uncategorized) == 0 && len(a.Bills) == 0
+}
+
+// OverspentCategory is an overspent category with a suggested category to
+// cover it from, if any has money to spare
+type OverspentCategory struct {
+	Category  string `json:"category"`
+	Overspent int64  `json:"overspent"`
+	CoverFrom string `json:"cover_from,omitempty"`
+	Cover     int64  `json:"cover,omitempty"` // How much to move from CoverFrom, at most Overspent
+}
+
+// UnderfundedGoal is a category whose YNAB goal isn't fully funded
+type UnderfundedGoal struct {
+	Category string `json:"category"`
+	Target   int64  `json:"target"`
+	Percent  int    `json:"percent"`
+}
+
+// UpcomingBill is a scheduled outflow due before the next meeting
+type UpcomingBill struct {
+	Payee    string    `json:"payee"`
+	Category string    `json:"category,omitempty"`
+	Amount   int64     `json:"amount"` // Positive
+	Due      time.Time `json:"due"`
+}
+
+// BuildAgenda compiles the agenda for a meeting at the given time. Overspent
+// categories are matched with the flexible categories holding the most money
+// without a goal, largest overspending first. Bills of at least largeBill
+// that are due within a week of the meeting are listed; transfers are not.
+func (a *Analyzer) BuildAgenda(data *ynab.MeetingData, meeting time.Time, largeBill int64) (*Agenda, error) {
+	if data == nil {
+		return nil, fmt.Errorf("meeting data is nil: %w", ynab.ErrNoData)
+	}
+
+	agenda := &Agenda{
+		Meeting:   meeting,
+		Overspent: a.suggestCovers(data.Categories),
+		Currency:  budgetCurrency(data.Budget),
+	}
+
+	for _, cat := range data.Categories {
+		if cat.Hidden || !isSpendable(cat) || cat.GoalTarget <= 0 || cat.GoalPercent >= 100 {
+			continue
+		}
+		agenda.Underfunded = append(agenda.Underfunded, UnderfundedGoal{
+			Category: cat.Name,
+			Target:   cat.GoalTarget,
+			Percent:  cat.GoalPercent,
+		})
+	}
+	sort.SliceStable(agenda.Underfunded, func(i, j int) bool {
+		return agenda.Underfunded[i].Percent < agenda.Underfunded[j].Percent
+	})
+
+	for _, tx := range data.Transactions {
+		if tx.Deleted {
+			continue
+		}
+		if tx.Unapproved {
+			agenda.Unapproved = append(agenda.Unapproved, tx)
+		}
+		if isUncategorized(tx) {
+			agenda.Uncategorized = append(agenda.Uncategorized, tx)
+		}
+	}
+
+	until := truncateToDay(meeting).AddDate(0, 0, 7)
+	for _, st := range data.Scheduled {
+		if st.DateNext == nil || st.TransferAccountID != nil || -st.Amount < largeBill || st.DateNext.After(until) {
+			continue
+		}
+		agenda.Bills = append(agenda.Bills, UpcomingBill{
+			Payee:    st.PayeeName,
+			Category: st.CategoryName,
+			Amount:   -st.Amount,
+			Due:      *st.DateNext,
+		})
+	}
+	sort.SliceStable(agenda.Bills, func(i, j int) bool {
+		return agenda.Bills[i].Due.Before(agenda.Bills[j].Due)
+	})
+	return agenda, nil
+}
+
+// suggestCovers lists the overspent categories, each with its planned
+// offset to cover it from or, without one holding money, the flexible
+// category holding the most money left
+func (a *Analyzer) suggestCovers(categories []ynab.Category) []OverspentCategory {
+	var overspent []OverspentCategory
+	spare := make(map[string]int64)
+	for _, cat := range categories {
+		if cat.Hidden || !isSpendable(cat) {
+			continue
+		}
+		if cat.Balance < 0 {
+			overspent = append(overspent, OverspentCategory{Category: cat.Name, Overspent: -cat.Balance})
+		} else if cat.Balance > 0 && cat.GoalType == "" && a.isFlexible(cat) {
+			spare[cat.Name] = cat.Balance
+		}
+	}
+	sort.SliceStable(overspent, func(i, j int) bool {
+		return overspent[i].Overspent > overspent[j].Overspent
+	})
+
+	available := categoryBalances(categories)
+	for i := range overspent {
+		// The household's planned offset comes before whatever has most to spare
+		if from := a.plannedOffset(overspent[i].Category, available); from != "" {
+			overspent[i].CoverFrom = from
+			overspent[i].Cover = min(overspent[i].Overspent, available[from])
+			available[from] -= overspent[i].Cover
+			if _, ok := spare[from]; ok {
+				if spare[from] = available[from]; spare[from] == 0 {
+					delete(spare, from)
+				}
+			}
+			continue
+		}
+
+		var from string
+		for name, balance := range spare {
+			if balance > spare[from] || (balance == spare[from] && name < from) {
+				from = name
+			}
+		}
+		if from == "" {
+			break
+		}
+		overspent[i].CoverFrom = from
+		overspent[i].Cover = min(overspent[i].Overspent, spare[from])
+		available[from] -= overspent[i].Cover
+		if spare[from] -= overspent[i].Cover; spare[from] == 0 {
+			delete(spare, from)
+		}
+	}
+	return overspent
+}
+
+// isUncategorized reports whether a transaction still needs a category.
+// Transfers between budget accounts have none and don't need one.
+func isUncategorized(tx ynab.Transaction) bool {
+	if tx.CategoryName == "Uncategorized" {
+		return true
+	}
+	return tx.CategoryID == nil && tx.TransferAccountID == nil
+}
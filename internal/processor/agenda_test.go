@@ -0,0 +1,120 @@
+package processor
+
+import (
+	"testing"
+	"time"
+
+	"github.com/sathyabhat/ynab-weekly-wrap/internal/ynab"
+)
+
+func baseMeetingData() *ynab.MeetingData {
+	emergency := makeCategory("c5", "Emergency Fund", 100_000, 2_000_000)
+	emergency.GoalType, emergency.GoalTarget, emergency.GoalPercent = "TB", 5_000_000, 40
+	rent := makeCategory("c6", "Rent", 2_000_000, 2_000_000)
+	rent.GoalType, rent.GoalTarget, rent.GoalPercent = "NEED", 2_000_000, 100
+
+	uncategorized := makeTx("t2", makeDate(2026, 1, 21), -30_000, "Uncategorized")
+	transfer := makeTx("t3", makeDate(2026, 1, 22), -500_000, "")
+	transfer.CategoryID, transfer.TransferAccountID = nil, new(string)
+	noCategory := makeTx("t4", makeDate(2026, 1, 23), -12_000, "")
+	noCategory.CategoryID = nil
+	unapproved := makeTx("t1", makeDate(2026, 1, 20), -45_000, "Groceries")
+	unapproved.Unapproved = true
+
+	return &ynab.MeetingData{
+		Budget: &ynab.Budget{ID: "b1", Name: "Test Budget"},
+		Categories: []ynab.Category{
+			makeCategory("c1", "Groceries", 500_000, 300_000),
+			makeCategory("c2", "Fun Money", 100_000, 40_000),
+			makeCategory("c3", "Dining", 300_000, -50_000),
+			makeCategory("c4", "Car Repair", 0, -320_000),
+			emergency,
+			rent,
+		},
+		Transactions: []ynab.Transaction{unapproved, uncategorized, transfer, noCategory},
+		Scheduled: []ynab.ScheduledTransaction{
+			{PayeeName: "Landlord", CategoryName: "Rent", Amount: -2_000_000, DateNext: makeDate(2026, 2, 1)},
+			{PayeeName: "Netflix", CategoryName: "Subscriptions", Amount: -15_000, DateNext: makeDate(2026, 1, 27)},
+			{PayeeName: "Insurer", CategoryName: "Insurance", Amount: -900_000, DateNext: makeDate(2026, 3, 1)},
+			{PayeeName: "Savings", Amount: -1_000_000, DateNext: makeDate(2026, 1, 28), TransferAccountID: new(string)},
+		},
+	}
+}
+
+func TestBuildAgenda(t *testing.T) {
+	meeting := time.Date(2026, 1, 25, 19, 0, 0, 0, time.UTC)
+	agenda, err := NewAnalyzer().BuildAgenda(baseMeetingData(), meeting, 100_000)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Car Repair is covered first, from the category holding the most
+	want := []OverspentCategory{
+		{Category: "Car Repair", Overspent: 320_000, CoverFrom: "Groceries", Cover: 300_000},
+		{Category: "Dining", Overspent: 50_000, CoverFrom: "Fun Money", Cover: 40_000},
+	}
+	if len(agenda.Overspent) != len(want) {
+		t.Fatalf("Overspent: got %+v, want %+v", agenda.Overspent, want)
+	}
+	for i := range want {
+		if agenda.Overspent[i] != want[i] {
+			t.Errorf("Overspent[%d]: got %+v, want %+v", i, agenda.Overspent[i], want[i])
+		}
+	}
+
+	if len(agenda.Underfunded) != 1 || agenda.Underfunded[0].Category != "Emergency Fund" || agenda.Underfunded[0].Percent != 40 {
+		t.Errorf("Underfunded: got %+v, want only Emergency Fund at 40%%", agenda.Underfunded)
+	}
+	if len(agenda.Unapproved) != 1 || agenda.Unapproved[0].ID != "t1" {
+		t.Errorf("Unapproved: got %+v, want t1", agenda.Unapproved)
+	}
+	if len(agenda.Uncategorized) != 2 || agenda.Uncategorized[0].ID != "t2" || agenda.Uncategorized[1].ID != "t4" {
+		t.Errorf("Uncategorized: got %+v, want t2 and t4 but not the transfer", agenda.Uncategorized)
+	}
+	if len(agenda.Bills) != 1 || agenda.Bills[0].Payee != "Landlord" || agenda.Bills[0].Amount != 2_000_000 {
+		t.Errorf("Bills: got %+v, want only the rent", agenda.Bills)
+	}
+	if agenda.Empty() {
+		t.Error("agenda should not be empty")
+	}
+}
+
+func TestBuildAgenda_NothingToCoverFrom(t *testing.T) {
+	data := &ynab.MeetingData{Categories: []ynab.Category{makeCategory("c1", "Dining", 100_000, -20_000)}}
+	agenda, err := NewAnalyzer().BuildAgenda(data, time.Now(), 100_000)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(agenda.Overspent) != 1 || agenda.Overspent[0].CoverFrom != "" || agenda.Overspent[0].Cover != 0 {
+		t.Errorf("Overspent: got %+v, want Dining with nothing to cover it from", agenda.Overspent)
+	}
+}
+
+func TestBuildAgenda_PlannedOffset(t *testing.T) {
+	data := baseMeetingData()
+	a := NewAnalyzer(WithOffsetRules(map[string]string{"Car Repair": "Emergency Fund"}))
+	agenda, err := a.BuildAgenda(data, time.Date(2026, 1, 25, 19, 0, 0, 0, time.UTC), 100_000)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// The goal-backed Emergency Fund is only drawn on because the rule says so
+	want := []OverspentCategory{
+		{Category: "Car Repair", Overspent: 320_000, CoverFrom: "Emergency Fund", Cover: 320_000},
+		{Category: "Dining", Overspent: 50_000, CoverFrom: "Groceries", Cover: 50_000},
+	}
+	if len(agenda.Overspent) != len(want) {
+		t.Fatalf("Overspent: got %+v, want %+v", agenda.Overspent, want)
+	}
+	for i := range want {
+		if agenda.Overspent[i] != want[i] {
+			t.Errorf("Overspent[%d]: got %+v, want %+v", i, agenda.Overspent[i], want[i])
+		}
+	}
+}
+
+func TestBuildAgenda_NilInput(t *testing.T) {
+	if _, err := NewAnalyzer().BuildAgenda(nil, time.Now(), 0); err == nil {
+		t.Fatal("expected error for nil input, got nil")
+	}
+}
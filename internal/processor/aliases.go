@@ -0,0 +1,32 @@
+package processor
+
+// CategoryAliases maps an old or merged category name to the name it is
+// reported under now, e.g. "Eating Out" -> "Dining" after a rename
+type CategoryAliases map[string]string
+
+// Resolve follows the aliases for name and returns the current name. Chains
+// (A -> B -> C) are followed; a cycle stops at the last name before repeating.
+func (c CategoryAliases) Resolve(name string) string {
+	seen := map[string]bool{name: true}
+	for {
+		next, ok := c[name]
+		if !ok || seen[next] {
+			return name
+		}
+		seen[next] = true
+		name = next
+	}
+}
+
+// Apply re-keys per-category amounts by their current names, adding together
+// categories that were merged. A nil map stays nil.
+func (c CategoryAliases) Apply(amounts map[string]int64) map[string]int64 {
+	if amounts == nil || len(c) == 0 {
+		return amounts
+	}
+	resolved := make(map[string]int64, len(amounts))
+	for name, amount := range amounts {
+		resolved[c.Resolve(name)] += amount
+	}
+	return resolved
+}
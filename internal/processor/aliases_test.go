@@ -0,0 +1,56 @@
+package processor
+
+import (
+	"testing"
+
+	"github.com/sathyabhat/ynab-weekly-wrap/internal/ynab"
+)
+
+func TestCategoryAliases_Resolve(t *testing.T) {
+	aliases := CategoryAliases{
+		"Eating Out":  "Restaurants",
+		"Restaurants": "Dining",
+		"Ping":        "Pong",
+		"Pong":        "Ping",
+	}
+	cases := map[string]string{
+		"Eating Out":  "Dining",
+		"Restaurants": "Dining",
+		"Groceries":   "Groceries",
+		"Ping":        "Pong",
+	}
+	for in, want := range cases {
+		if got := aliases.Resolve(in); got != want {
+			t.Errorf("Resolve(%q): got %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestCategoryAliases_ApplyMergesCategories(t *testing.T) {
+	aliases := CategoryAliases{"Takeout": "Dining", "Restaurants": "Dining"}
+	got := aliases.Apply(map[string]int64{"Takeout": 20_000, "Restaurants": 30_000, "Groceries": 50_000})
+
+	if got["Dining"] != 50_000 || got["Groceries"] != 50_000 || len(got) != 2 {
+		t.Errorf("unexpected merged spend: %v", got)
+	}
+	if aliases.Apply(nil) != nil {
+		t.Errorf("expected nil to stay nil")
+	}
+}
+
+func TestAnalyzeWeeklyData_AliasesKeepComparisons(t *testing.T) {
+	a := NewAnalyzer(WithCategoryAliases(map[string]string{"Eating Out": "Dining"}))
+	catID := "cat-1"
+	data := &ynab.WeeklyData{
+		Categories:   []ynab.Category{{ID: catID, Name: "Dining", Budgeted: 300_000, Activity: -60_000}},
+		Transactions: []ynab.Transaction{{Amount: -60_000, CategoryID: &catID, CategoryName: "Dining"}},
+	}
+
+	result, err := a.AnalyzeWeeklyData(data, map[string]int64{"Eating Out": 40_000}, 0)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(result.TopSpending) != 1 || result.TopSpending[0].PrevSpent != 40_000 || result.TopSpending[0].SpendDelta != 20_000 {
+		t.Errorf("expected previous spend under the old name to carry over, got %+v", result.TopSpending)
+	}
+}
@@ -2,26 +2,174 @@ package processor
 
 import (
 	"fmt"
-	"math"
 	"sort"
 	"time"
 
+	"github.com/sathyabhat/ynab-weekly-wrap/internal/insights"
+	"github.com/sathyabhat/ynab-weekly-wrap/internal/money"
 	"github.com/sathyabhat/ynab-weekly-wrap/internal/ynab"
 )
 
-type Analyzer struct{}
+type Analyzer struct {
+	benchmarks      map[string]int64
+	velocityDays    int
+	velocityPercent float64
+	atRiskPercent   float64
+	winsCount       int
+	overPercent     float64
+	splitTags       map[string]string
+	splitTarget     SplitTarget
+	fiscalStartDay  int
+	aliases         CategoryAliases
+	lumpy           map[string]bool
+	fixed           map[string]bool
+	fixedGoals      map[string]bool
+	offsets         map[string]string
+	severeOver      int64
+	transfers       bool
+	rules           []insights.Rule
+	spendingOnly    bool
+	focus           map[string]bool
+	focusNames      []string
+	excludeFlags    map[string]bool
+	highlightFlags  map[string]bool
+}
+
+// AnalyzerOption is a functional option for configuring Analyzer
+type AnalyzerOption func(*Analyzer)
+
+// WithBenchmarks sets personal weekly spending targets (category name -> milliunits)
+// that are compared against actual spending independently of YNAB budgets
+func WithBenchmarks(benchmarks map[string]int64) AnalyzerOption {
+	return func(a *Analyzer) {
+		a.benchmarks = benchmarks
+	}
+}
+
+// WithVelocity enables early-month velocity alerts: a category is flagged when
+// its month-to-date spend reaches percent of its budget within the first days
+// of the month
+func WithVelocity(days, percent int) AnalyzerOption {
+	return func(a *Analyzer) {
+		a.velocityDays = days
+		a.velocityPercent = float64(percent)
+	}
+}
+
+// WithThresholds sets the percentages of its budget at which a category is
+// watched as at risk and counted as over budget in the week ahead (defaults:
+// 75 and 100)
+func WithThresholds(atRiskPercent, overBudgetPercent int) AnalyzerOption {
+	return func(a *Analyzer) {
+		a.atRiskPercent = float64(atRiskPercent)
+		a.overPercent = float64(overBudgetPercent)
+	}
+}
+
+// WithWinsCount sets how many categories with the most left to list as wins
+// (default: 3, 0 = none)
+func WithWinsCount(count int) AnalyzerOption {
+	return func(a *Analyzer) {
+		a.winsCount = count
+	}
+}
+
+// WithSpendingSplit tags categories or category groups as need/want/savings
+// and sets the target split the week is compared against
+func WithSpendingSplit(tags map[string]string, target SplitTarget) AnalyzerOption {
+	return func(a *Analyzer) {
+		a.splitTags = tags
+		a.splitTarget = target
+	}
+}
 
-func NewAnalyzer() *Analyzer {
-	return &Analyzer{}
+// WithFiscalMonthStart sets the day of the month the budget month starts on,
+// used for pace and "weeks left" math (1 = calendar months)
+func WithFiscalMonthStart(day int) AnalyzerOption {
+	return func(a *Analyzer) {
+		a.fiscalStartDay = day
+	}
 }
 
-func (a *Analyzer) AnalyzeWeeklyData(data *ynab.WeeklyData, topCategoriesLimit int) (*AnalysisResult, error) {
+// WithCategoryAliases maps renamed or merged category names (old -> current)
+// so comparisons against earlier periods stay continuous
+func WithCategoryAliases(aliases map[string]string) AnalyzerOption {
+	return func(a *Analyzer) {
+		a.aliases = aliases
+	}
+}
+
+// WithLumpyCategories marks categories or category groups that are spent in
+// large one-off amounts (annual insurance, registrations). They are reported
+// as absolute amounts only and left out of percentages and at-risk checks.
+func WithLumpyCategories(names []string) AnalyzerOption {
+	return func(a *Analyzer) {
+		a.lumpy = make(map[string]bool, len(names))
+		for _, name := range names {
+			a.lumpy[name] = true
+		}
+	}
+}
+
+// WithTransfers counts transfers to tracking accounts, which YNAB books
+// against a category (e.g. a retirement contribution), as spending in the
+// weekly wrap. They are left out by default.
+func WithTransfers(include bool) AnalyzerOption {
+	return func(a *Analyzer) {
+		a.transfers = include
+	}
+}
+
+// spendingTransactions returns the transactions counted as the week's
+// spending: transfers are left out unless configured otherwise, as are
+// transactions flagged an excluded color and, with focus categories set,
+// transactions outside them
+func (a *Analyzer) spendingTransactions(transactions []ynab.Transaction, categories []ynab.Category) []ynab.Transaction {
+	if (a.transfers && len(a.focus) == 0 && len(a.excludeFlags) == 0) || transactions == nil {
+		return transactions
+	}
+	names := make(map[string]bool, len(categories))
+	for _, cat := range categories {
+		names[cat.Name] = true
+	}
+	spending := make([]ynab.Transaction, 0, len(transactions))
+	for _, tx := range transactions {
+		if tx.TransferAccountID != nil && !a.transfers {
+			continue
+		}
+		if len(a.focus) > 0 && !names[tx.CategoryName] {
+			continue
+		}
+		if a.excluded(tx) {
+			continue
+		}
+		spending = append(spending, tx)
+	}
+	return spending
+}
+
+// isLumpy reports whether the category or its group is marked lumpy
+func (a *Analyzer) isLumpy(cat ynab.Category) bool {
+	return a.lumpy[cat.Name] || (cat.CategoryGroup.Name != "" && a.lumpy[cat.CategoryGroup.Name])
+}
+
+func NewAnalyzer(opts ...AnalyzerOption) *Analyzer {
+	a := &Analyzer{winsCount: 3}
+	for _, opt := range opts {
+		opt(a)
+	}
+	return a
+}
+
+func (a *Analyzer) AnalyzeWeeklyData(data *ynab.WeeklyData, prevCategorySpend map[string]int64, topCategoriesLimit int) (*AnalysisResult, error) {
 	if data == nil {
-		return nil, fmt.Errorf("weekly data is nil")
+		return nil, fmt.Errorf("weekly data is nil: %w", ynab.ErrNoData)
 	}
 
 	// Calculate spending by category
-	categorySpending := a.calculateCategorySpending(data.Categories, data.Transactions)
+	categories := a.focusCategories(data.Categories)
+	spending := a.spendingTransactions(data.Transactions, categories)
+	categorySpending := a.calculateCategorySpending(categories, spending)
 
 	// Calculate budget health
 	overview := a.calculateOverview(categorySpending)
@@ -34,32 +182,85 @@ func (a *Analyzer) AnalyzeWeeklyData(data *ynab.WeeklyData, topCategoriesLimit i
 
 	// Identify areas for attention (with transaction details)
 	concerns := a.identifyConcernsWithTransactions(categorySpending)
+	a.applyOffsets(concerns, data.Categories)
 
 	// Calculate ahead focus
 	aheadFocus := a.calculateAheadFocus(categorySpending, data.WeekEnd)
 
+	// Compare against personal benchmarks
+	benchmarks := a.compareBenchmarks(spending)
+
+	// Flag categories burning through their budget early in the month
+	velocityAlerts := a.identifyVelocityAlerts(categories, data.MonthToDate, data.WeekEnd)
+
+	// Project where spending ends up by the end of the month
+	projections := a.projectMonthEnd(categories, data.MonthToDate, data.WeekEnd)
+
+	// Summarize needs vs wants vs savings
+	split := a.calculateSpendingSplit(categories, spending)
+
+	// Work out what can still be spent this week
+	safeToSpend := a.calculateSafeToSpend(categories, data.Scheduled, data.WeekEnd)
+
+	// Group what is left for the month into flexible and fixed
+	envelopes := a.calculateEnvelopes(categories)
+
 	result := &AnalysisResult{
 		Overview:    overview,
 		TopSpending: topSpending,
 		Wins:        wins,
 		Concerns:    concerns,
 		AheadFocus:  aheadFocus,
+		Benchmarks:  benchmarks,
+		Velocity:    velocityAlerts,
+		Projections: projections,
+		Split:       split,
+		SafeToSpend: safeToSpend,
+		CashFlow:    calculateCashFlow(a.WithoutExcluded(data.Transactions)),
+		Attention:   findNeedsAttention(a.WithoutExcluded(data.Transactions)),
+		Envelopes:   envelopes,
+		Coverage:    periodCoverage(data.Budget, data.WeekStart, data.WeekEnd),
+		Confidence:  assessConfidence(data.Accounts, data.Transactions, data.WeekEnd),
+		Validation:  data.Validation,
+		Focus:       a.focusNames,
+		Flagged:     a.flaggedTransactions(data.Transactions),
+		Spending:    spending,
+		Currency:    budgetCurrency(data.Budget),
 		DateRange:   data.WeekStart.Format("2006-01-02") + " to " + data.WeekEnd.Format("2006-01-02"),
 	}
 
+	// Compare with the previous week unless the budget is too new for one
+	prevStart := data.WeekStart.Add(-data.WeekEnd.Sub(data.WeekStart))
+	a.applyPrevSpend(result, data.Budget, prevStart, prevCategorySpend)
+	if result.HasPrevData {
+		result.Mover = a.findBiggestMover(categorySpending, prevCategorySpend)
+	}
+	a.applyBaseline(result, data)
+
+	var prev, baseline map[string]int64
+	if result.HasPrevData {
+		prev = prevCategorySpend
+	}
+	if result.HasBaseline {
+		baseline = data.Baseline
+	}
+	a.applyInsights(result, categorySpending, prev, baseline)
+	a.applySpendingOnly(result, spending, topCategoriesLimit)
+
 	return result, nil
 }
 
 func (a *Analyzer) AnalyzeMonthlyData(data *ynab.MonthlyData, prevCategorySpend map[string]int64, topCategoriesLimit int) (*AnalysisResult, error) {
 	if data == nil {
-		return nil, fmt.Errorf("monthly data is nil")
+		return nil, fmt.Errorf("monthly data is nil: %w", ynab.ErrNoData)
 	}
 
-	categorySpending := a.calculateCategorySpending(data.Categories, data.Transactions)
+	categorySpending := a.calculateCategorySpending(a.focusCategories(data.Categories), data.Transactions)
 	overview := a.calculateOverview(categorySpending)
 	topSpending := a.getTopSpendingCategories(categorySpending, topCategoriesLimit)
 	wins := a.identifyWins(categorySpending)
 	concerns := a.identifyConcernsWithTransactions(categorySpending)
+	a.applyOffsets(concerns, data.Categories)
 
 	result := &AnalysisResult{
 		Overview:    overview,
@@ -67,24 +268,140 @@ func (a *Analyzer) AnalyzeMonthlyData(data *ynab.MonthlyData, prevCategorySpend
 		Wins:        wins,
 		Concerns:    concerns,
 		AheadFocus:  nil,
+		Validation:  data.Validation,
+		Focus:       a.focusNames,
+		Currency:    budgetCurrency(data.Budget),
 		DateRange:   data.MonthStart.Format("January 2006"),
 	}
 
-	if prevCategorySpend != nil {
-		for i := range result.TopSpending {
-			prevSpent := prevCategorySpend[result.TopSpending[i].Category]
-			result.TopSpending[i].PrevSpent = prevSpent
-			result.TopSpending[i].SpendDelta = result.TopSpending[i].Spent - prevSpent
+	a.applyPrevSpend(result, data.Budget, data.MonthStart.AddDate(0, -1, 0), prevCategorySpend)
+
+	var prev map[string]int64
+	if result.HasPrevData {
+		prev = prevCategorySpend
+	}
+	a.applyInsights(result, categorySpending, prev, nil)
+	a.applySpendingOnly(result, data.Transactions, topCategoriesLimit)
+
+	return result, nil
+}
+
+// applyPrevSpend fills in the spending deltas against the previous period.
+// When the budget's data starts after prevStart the previous period is empty
+// or partial, so the result is marked as a first report rather than being
+// compared against a zero baseline.
+func (a *Analyzer) applyPrevSpend(result *AnalysisResult, budget *ynab.Budget, prevStart time.Time, prevCategorySpend map[string]int64) {
+	if budget != nil && budget.DataStart != nil && truncateToDay(*budget.DataStart).After(truncateToDay(prevStart)) {
+		result.FirstReport = true
+		return
+	}
+	if prevCategorySpend == nil {
+		return
+	}
+	prevCategorySpend = a.aliases.Apply(prevCategorySpend)
+
+	for i := range result.TopSpending {
+		prevSpent := prevCategorySpend[a.aliases.Resolve(result.TopSpending[i].Category)]
+		result.TopSpending[i].PrevSpent = prevSpent
+		result.TopSpending[i].SpendDelta = result.TopSpending[i].Spent - prevSpent
+	}
+	for i := range result.Concerns {
+		prevSpent := prevCategorySpend[a.aliases.Resolve(result.Concerns[i].Category)]
+		result.Concerns[i].PrevSpent = prevSpent
+		result.Concerns[i].SpendDelta = result.Concerns[i].Spent - prevSpent
+	}
+	result.HasPrevData = true
+}
+
+// BaselineWeeks is how many periods before the week its average spending
+// covers. The periods are as long as the week's, so a custom range is
+// averaged over ranges like it.
+const BaselineWeeks = 4
+
+// applyBaseline fills in each top category's average spending over the
+// BaselineWeeks before the week, so a big number can be told from an
+// unusual one. Budgets that don't reach back that far get no average.
+func (a *Analyzer) applyBaseline(result *AnalysisResult, data *ynab.WeeklyData) {
+	if data.Baseline == nil {
+		return
+	}
+	baselineStart := data.WeekStart.Add(-BaselineWeeks * data.WeekEnd.Sub(data.WeekStart))
+	if budget := data.Budget; budget != nil && budget.DataStart != nil && truncateToDay(*budget.DataStart).After(truncateToDay(baselineStart)) {
+		return
+	}
+
+	baseline := a.aliases.Apply(data.Baseline)
+	for i := range result.TopSpending {
+		result.TopSpending[i].AvgSpent = baseline[a.aliases.Resolve(result.TopSpending[i].Category)] / BaselineWeeks
+	}
+	result.HasBaseline = true
+}
+
+// findBiggestMover returns the category whose spending changed most from the
+// previous period, either way, with its largest transaction as the likely
+// reason when spending went up. Lumpy categories are left out, since their
+// swings are expected.
+func (a *Analyzer) findBiggestMover(spending []CategorySpending, prevCategorySpend map[string]int64) *BiggestMover {
+	prevCategorySpend = a.aliases.Apply(prevCategorySpend)
+
+	var mover *BiggestMover
+	for _, cat := range spending {
+		if cat.Lumpy {
+			continue
+		}
+		prevSpent := prevCategorySpend[a.aliases.Resolve(cat.Category.Name)]
+		delta := cat.Spent - prevSpent
+		if delta == 0 || (mover != nil && max(delta, -delta) <= max(mover.Delta, -mover.Delta)) {
+			continue
+		}
+		mover = &BiggestMover{
+			Category:  cat.Category.Name,
+			Spent:     cat.Spent,
+			PrevSpent: prevSpent,
+			Delta:     delta,
 		}
-		for i := range result.Concerns {
-			prevSpent := prevCategorySpend[result.Concerns[i].Category]
-			result.Concerns[i].PrevSpent = prevSpent
-			result.Concerns[i].SpendDelta = result.Concerns[i].Spent - prevSpent
+		if delta > 0 {
+			for _, tx := range cat.Transactions {
+				if mover.Transaction == nil || tx.Amount < mover.Transaction.Amount {
+					mover.Transaction = &tx
+				}
+			}
 		}
-		result.HasPrevData = true
 	}
+	return mover
+}
 
-	return result, nil
+// budgetCurrency returns how the budget formats amounts, or the zero Format,
+// which formats as US dollars, when the budget isn't known
+func budgetCurrency(budget *ynab.Budget) money.Format {
+	if budget == nil {
+		return money.Format{}
+	}
+	return budget.Currency
+}
+
+// periodCoverage reports how much of the period is covered by budget data,
+// or nil when the data reaches back to the start of the period. Periods run
+// from the day after start through end, matching the transaction fetch.
+func periodCoverage(budget *ynab.Budget, start, end time.Time) *PeriodCoverage {
+	if budget == nil || budget.DataStart == nil {
+		return nil
+	}
+
+	from := truncateToDay(*budget.DataStart)
+	to := truncateToDay(end)
+	periodDays := int(to.Sub(truncateToDay(start)).Hours() / 24)
+	days := int(to.Sub(from).Hours()/24) + 1
+	if days >= periodDays || days <= 0 {
+		return nil
+	}
+
+	return &PeriodCoverage{
+		From:       from,
+		To:         to,
+		Days:       days,
+		PeriodDays: periodDays,
+	}
 }
 
 func (a *Analyzer) calculateCategorySpending(categories []ynab.Category, transactions []ynab.Transaction) []CategorySpending {
@@ -104,7 +421,7 @@ func (a *Analyzer) calculateCategorySpending(categories []ynab.Category, transac
 	// Create category spending list
 	var categorySpendingList []CategorySpending
 	for _, cat := range categories {
-		if cat.Budgeted == 0 {
+		if cat.Budgeted == 0 && !a.spendingOnly {
 			continue
 		}
 
@@ -120,7 +437,12 @@ func (a *Analyzer) calculateCategorySpending(categories []ynab.Category, transac
 			categoryTxns = txByCategory[cat.Name]
 		}
 
-		percentage := float64(spend) / float64(cat.Budgeted) * 100
+		// Lumpy categories are shown as amounts only, so they get no percentage
+		lumpy := a.isLumpy(cat)
+		percentage := float64(0)
+		if !lumpy && cat.Budgeted != 0 {
+			percentage = float64(spend) / float64(cat.Budgeted) * 100
+		}
 
 		categorySpendingList = append(categorySpendingList, CategorySpending{
 			Category:     cat,
@@ -128,6 +450,7 @@ func (a *Analyzer) calculateCategorySpending(categories []ynab.Category, transac
 			Budgeted:     cat.Budgeted,
 			Balance:      cat.Balance, // Use YNAB's balance (remaining for the month)
 			Percentage:   percentage,
+			Lumpy:        lumpy,
 			Transactions: categoryTxns,
 		})
 	}
@@ -139,16 +462,24 @@ func (a *Analyzer) calculateOverview(spending []CategorySpending) *Overview {
 	totalSpent := int64(0)
 	totalBudgeted := int64(0)
 	totalBalance := int64(0)
+	lumpySpent := int64(0)
+	lumpyBudgeted := int64(0)
 
 	for _, cat := range spending {
 		totalSpent += cat.Spent
 		totalBudgeted += cat.Budgeted
 		totalBalance += cat.Balance
+		if cat.Lumpy {
+			lumpySpent += cat.Spent
+			lumpyBudgeted += cat.Budgeted
+		}
 	}
 
+	// Budget health leaves out lumpy categories, whose one-off payments would
+	// swamp the percentage
 	healthPercentage := float64(0)
-	if totalBudgeted > 0 {
-		healthPercentage = float64(totalSpent) / float64(totalBudgeted) * 100
+	if totalBudgeted-lumpyBudgeted > 0 {
+		healthPercentage = float64(totalSpent-lumpySpent) / float64(totalBudgeted-lumpyBudgeted) * 100
 	}
 
 	return &Overview{
@@ -156,6 +487,7 @@ func (a *Analyzer) calculateOverview(spending []CategorySpending) *Overview {
 		TotalBudgeted:    totalBudgeted,
 		TotalBalance:     totalBalance,
 		HealthPercentage: healthPercentage,
+		LumpySpent:       lumpySpent,
 	}
 }
 
@@ -167,8 +499,8 @@ func (a *Analyzer) identifyWins(spending []CategorySpending) []CategoryWin {
 		return spending[i].Balance > spending[j].Balance
 	})
 
-	// Take top 3 wins (categories with highest remaining balance)
-	for i := 0; i < 3 && i < len(spending); i++ {
+	// Take the top wins (categories with highest remaining balance)
+	for i := 0; i < a.winsCount && i < len(spending); i++ {
 		cat := spending[i]
 		if cat.Balance > 0 { // Only include categories with positive balance
 			wins = append(wins, CategoryWin{
@@ -213,6 +545,7 @@ func (a *Analyzer) getTopSpendingCategories(spending []CategorySpending, limit i
 			Budgeted:   cat.Budgeted,
 			Balance:    cat.Balance,
 			Percentage: cat.Percentage,
+			Lumpy:      cat.Lumpy,
 		})
 	}
 
@@ -238,31 +571,188 @@ func (a *Analyzer) identifyConcernsWithTransactions(spending []CategorySpending)
 				Spent:        cat.Spent,
 				Balance:      cat.Category.Balance,
 				Over:         overage,
+				Severity:     a.severity(overage, cat.Budgeted),
 				Percentage:   cat.Percentage,
 				Transactions: cat.Transactions,
 			})
 		}
 	}
 
+	// Most severe first, most overspent first within a tier
+	sort.SliceStable(concerns, func(i, j int) bool {
+		return concerns[i].Severity.rank() < concerns[j].Severity.rank()
+	})
 	return concerns
 }
 
 func (a *Analyzer) calculateAheadFocus(spending []CategorySpending, weekEnd time.Time) *AheadFocus {
 	var highestRiskCategories []string
-	var adjustments []string
+	var adjustments []Adjustment
 
+	atRisk, over := a.atRiskPercent, a.overPercent
+	if atRisk == 0 {
+		atRisk = 75
+	}
+	if over == 0 {
+		over = 100
+	}
 	for _, cat := range spending {
-		if cat.Percentage >= 75 && cat.Percentage < 100 {
+		if cat.Lumpy {
+			continue
+		}
+		if cat.Percentage >= atRisk && cat.Percentage < over {
 			highestRiskCategories = append(highestRiskCategories, cat.Category.Name)
 		}
-		if cat.Percentage >= 100 {
-			adjustments = append(adjustments, fmt.Sprintf("Consider reducing %s budget", cat.Category.Name))
+		if cat.Percentage >= over {
+			adjustments = append(adjustments, Adjustment{Category: cat.Category.Name, Offset: a.offsets[cat.Category.Name]})
 		}
 	}
 
 	return &AheadFocus{
 		Watch:       highestRiskCategories,
 		Adjustments: adjustments,
-		WeeksLeft:   int(math.Ceil(time.Until(weekEnd).Hours() / 24 / 7)),
+		WeeksLeft:   weeksLeftInMonth(weekEnd, a.fiscalStartDay),
+	}
+}
+
+// compareBenchmarks compares spending in the period against the configured
+// benchmarks. Spending is summed straight from the transactions so that
+// categories without a YNAB budget can still be benchmarked.
+func (a *Analyzer) compareBenchmarks(transactions []ynab.Transaction) []BenchmarkComparison {
+	if len(a.benchmarks) == 0 {
+		return nil
+	}
+
+	spent := spendingByCategory(transactions)
+
+	var comparisons []BenchmarkComparison
+	for category, target := range a.benchmarks {
+		actual := spent[category]
+		percentage := float64(0)
+		if target > 0 {
+			percentage = float64(actual) / float64(target) * 100
+		}
+		comparisons = append(comparisons, BenchmarkComparison{
+			Category:   category,
+			Target:     target,
+			Actual:     actual,
+			Delta:      actual - target,
+			Percentage: percentage,
+		})
+	}
+
+	// Most over benchmark first, then by name for a stable order
+	sort.Slice(comparisons, func(i, j int) bool {
+		if comparisons[i].Delta != comparisons[j].Delta {
+			return comparisons[i].Delta > comparisons[j].Delta
+		}
+		return comparisons[i].Category < comparisons[j].Category
+	})
+
+	return comparisons
+}
+
+// identifyVelocityAlerts flags categories whose month-to-date spend has
+// already reached the velocity threshold within the early-month window.
+// Unlike the at-risk threshold this takes the day of the month into account.
+// For calendar months YNAB's category activity is used; non-calendar months
+// sum the month-to-date transactions instead.
+func (a *Analyzer) identifyVelocityAlerts(categories []ynab.Category, monthToDate []ynab.Transaction, asOf time.Time) []VelocityAlert {
+	day := fiscalDay(asOf, a.fiscalStartDay)
+	if a.velocityDays <= 0 || day > a.velocityDays {
+		return nil
+	}
+
+	spent := a.monthToDateSpent(monthToDate)
+	var alerts []VelocityAlert
+	for _, cat := range categories {
+		monthSpent := spent(cat)
+		if cat.Budgeted <= 0 || monthSpent <= 0 || a.isLumpy(cat) {
+			continue
+		}
+
+		percentage := float64(monthSpent) / float64(cat.Budgeted) * 100
+		if percentage < a.velocityPercent {
+			continue
+		}
+
+		alerts = append(alerts, VelocityAlert{
+			Category:   cat.Name,
+			MonthSpent: monthSpent,
+			Budgeted:   cat.Budgeted,
+			Percentage: percentage,
+			Day:        day,
+		})
+	}
+
+	sort.Slice(alerts, func(i, j int) bool {
+		return alerts[i].Percentage > alerts[j].Percentage
+	})
+
+	return alerts
+}
+
+// monthToDateSpent returns how much a category has spent so far this budget
+// month: YNAB's category activity for calendar months, or the sum of the
+// month-to-date transactions otherwise
+func (a *Analyzer) monthToDateSpent(monthToDate []ynab.Transaction) func(ynab.Category) int64 {
+	if a.fiscalStartDay <= 1 {
+		return func(cat ynab.Category) int64 { return -cat.Activity }
+	}
+	fiscalSpent := spendingByCategory(a.WithoutExcluded(monthToDate))
+	return func(cat ynab.Category) int64 { return fiscalSpent[cat.Name] }
+}
+
+// calculateSpendingSplit buckets the period's spending into needs, wants and
+// savings using the configured tags. A category tag takes precedence over the
+// tag of its group; spending in untagged categories is reported separately.
+func (a *Analyzer) calculateSpendingSplit(categories []ynab.Category, transactions []ynab.Transaction) *SpendingSplit {
+	if len(a.splitTags) == 0 {
+		return nil
+	}
+
+	groupByCategory := make(map[string]string)
+	for _, cat := range categories {
+		groupByCategory[cat.Name] = cat.CategoryGroup.Name
+	}
+
+	split := &SpendingSplit{Target: a.splitTarget}
+	for category, spent := range spendingByCategory(transactions) {
+		tag, ok := a.splitTags[category]
+		if !ok {
+			tag = a.splitTags[groupByCategory[category]]
+		}
+
+		switch tag {
+		case "need":
+			split.Needs += spent
+		case "want":
+			split.Wants += spent
+		case "savings":
+			split.Savings += spent
+		default:
+			split.Untagged += spent
+		}
+	}
+
+	if tagged := split.Needs + split.Wants + split.Savings; tagged > 0 {
+		split.NeedsPercent = float64(split.Needs) / float64(tagged) * 100
+		split.WantsPercent = float64(split.Wants) / float64(tagged) * 100
+		split.SavingsPercent = float64(split.Savings) / float64(tagged) * 100
+	}
+
+	return split
+}
+
+// spendingByCategory sums outflows per category name straight from the
+// transactions, regardless of whether the category has a budget
+func spendingByCategory(transactions []ynab.Transaction) map[string]int64 {
+	spent := make(map[string]int64)
+	for _, tx := range transactions {
+		if tx.Deleted || tx.CategoryID == nil || tx.Amount >= 0 {
+			continue
+		}
+		spent[tx.CategoryName] += -tx.Amount
 	}
+	return spent
 }
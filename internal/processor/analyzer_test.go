@@ -1,6 +1,7 @@
 package processor
 
 import (
+	"reflect"
 	"testing"
 	"time"
 
@@ -237,7 +238,7 @@ func TestAnalyzeMonthlyData_NoBudgetedCategoriesSkipped(t *testing.T) {
 
 func TestAnalyzeWeeklyData_NilInput(t *testing.T) {
 	a := NewAnalyzer()
-	_, err := a.AnalyzeWeeklyData(nil, 5)
+	_, err := a.AnalyzeWeeklyData(nil, nil, 5)
 	if err == nil {
 		t.Fatal("expected error for nil input, got nil")
 	}
@@ -245,7 +246,7 @@ func TestAnalyzeWeeklyData_NilInput(t *testing.T) {
 
 func TestAnalyzeWeeklyData_DateRangeFormat(t *testing.T) {
 	a := NewAnalyzer()
-	result, err := a.AnalyzeWeeklyData(baseWeeklyData(), 5)
+	result, err := a.AnalyzeWeeklyData(baseWeeklyData(), nil, 5)
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -258,7 +259,7 @@ func TestAnalyzeWeeklyData_DateRangeFormat(t *testing.T) {
 
 func TestAnalyzeWeeklyData_AheadFocusNotNil(t *testing.T) {
 	a := NewAnalyzer()
-	result, err := a.AnalyzeWeeklyData(baseWeeklyData(), 5)
+	result, err := a.AnalyzeWeeklyData(baseWeeklyData(), nil, 5)
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -267,9 +268,35 @@ func TestAnalyzeWeeklyData_AheadFocusNotNil(t *testing.T) {
 	}
 }
 
+func TestAnalyzeWeeklyData_AheadFocusThresholds(t *testing.T) {
+	// Groceries 40%, Transport 75%, Dining 117% of budget
+	result, err := NewAnalyzer().AnalyzeWeeklyData(baseWeeklyData(), nil, 5)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := result.AheadFocus.Watch; !reflect.DeepEqual(got, []string{"Transport"}) {
+		t.Errorf("default Watch: got %v, want [Transport]", got)
+	}
+	if got := result.AheadFocus.Adjustments; len(got) != 1 || got[0].Category != "Dining" {
+		t.Errorf("default Adjustments: got %v, want one for Dining", got)
+	}
+
+	a := NewAnalyzer(WithThresholds(30, 120))
+	result, err = a.AnalyzeWeeklyData(baseWeeklyData(), nil, 5)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := result.AheadFocus.Watch; !reflect.DeepEqual(got, []string{"Dining", "Transport", "Groceries"}) {
+		t.Errorf("Watch: got %v, want [Dining Transport Groceries]", got)
+	}
+	if got := result.AheadFocus.Adjustments; len(got) != 0 {
+		t.Errorf("Adjustments: got %v, want none below 120%%", got)
+	}
+}
+
 func TestAnalyzeWeeklyData_OverBudgetConcern(t *testing.T) {
 	a := NewAnalyzer()
-	result, err := a.AnalyzeWeeklyData(baseWeeklyData(), 0)
+	result, err := a.AnalyzeWeeklyData(baseWeeklyData(), nil, 0)
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -281,6 +308,30 @@ func TestAnalyzeWeeklyData_OverBudgetConcern(t *testing.T) {
 	}
 }
 
+func TestAnalyzeWeeklyData_TransfersExcludedByDefault(t *testing.T) {
+	data := baseWeeklyData()
+	account := "tracking"
+	transfer := makeTx("t4", makeDate(2026, 1, 21), -400_000, "Groceries")
+	transfer.TransferAccountID = &account
+	data.Transactions = append(data.Transactions, transfer)
+
+	result, err := NewAnalyzer().AnalyzeWeeklyData(data, nil, 0)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Overview.TotalSpent != 700_000 {
+		t.Errorf("TotalSpent: got %d, want 700000 without the transfer", result.Overview.TotalSpent)
+	}
+
+	result, err = NewAnalyzer(WithTransfers(true)).AnalyzeWeeklyData(data, nil, 0)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Overview.TotalSpent != 1_100_000 {
+		t.Errorf("TotalSpent: got %d, want 1100000 with the transfer", result.Overview.TotalSpent)
+	}
+}
+
 // ── HealthPercentage ─────────────────────────────────────────────────────────
 
 func TestAnalyzeMonthlyData_HealthPercentage(t *testing.T) {
@@ -325,6 +376,32 @@ func TestAnalyzeMonthlyData_WinsAreCategoriesWithPositiveBalance(t *testing.T) {
 	}
 }
 
+func TestAnalyzeWeeklyData_WinsCount(t *testing.T) {
+	result, err := NewAnalyzer().AnalyzeWeeklyData(baseWeeklyData(), nil, 5)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(result.Wins) != 2 || result.Wins[0].Category != "Groceries" {
+		t.Errorf("default Wins: got %+v, want Groceries and Transport", result.Wins)
+	}
+
+	result, err = NewAnalyzer(WithWinsCount(1)).AnalyzeWeeklyData(baseWeeklyData(), nil, 5)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(result.Wins) != 1 || result.Wins[0].Category != "Groceries" {
+		t.Errorf("Wins: got %+v, want only Groceries", result.Wins)
+	}
+
+	result, err = NewAnalyzer(WithWinsCount(0)).AnalyzeWeeklyData(baseWeeklyData(), nil, 5)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(result.Wins) != 0 {
+		t.Errorf("expected no wins when disabled, got %+v", result.Wins)
+	}
+}
+
 func TestAnalyzeMonthlyData_PrevDataDelta(t *testing.T) {
 	data := baseMonthlyData()
 	prevCategorySpend := map[string]int64{
@@ -390,3 +467,367 @@ func TestAnalyzeMonthlyData_NilPrevDataHasPrevDataFalse(t *testing.T) {
 		t.Error("HasPrevData should be false when prevData is nil")
 	}
 }
+
+// ── Previous period / first report ────────────────────────────────────────────
+
+func TestAnalyzeWeeklyData_PrevWeekDelta(t *testing.T) {
+	a := NewAnalyzer()
+	result, err := a.AnalyzeWeeklyData(baseWeeklyData(), map[string]int64{"Dining": 300_000}, 0)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !result.HasPrevData || result.FirstReport {
+		t.Fatalf("HasPrevData/FirstReport: got %v/%v, want true/false", result.HasPrevData, result.FirstReport)
+	}
+	for _, top := range result.TopSpending {
+		if top.Category == "Dining" && top.SpendDelta != 50_000 {
+			t.Errorf("Dining.SpendDelta: got %d, want 50000", top.SpendDelta)
+		}
+	}
+}
+
+func TestAnalyzeWeeklyData_BiggestMover(t *testing.T) {
+	prev := map[string]int64{"Dining": 300_000, "Groceries": 50_000, "Transport": 250_000}
+	result, err := NewAnalyzer().AnalyzeWeeklyData(baseWeeklyData(), prev, 0)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	mover := result.Mover
+	if mover == nil {
+		t.Fatal("Mover should be set when there is previous data")
+	}
+	// Groceries moved +150, Transport -100 and Dining +50
+	if mover.Category != "Groceries" || mover.Delta != 150_000 {
+		t.Errorf("Mover: got %s %d, want Groceries 150000", mover.Category, mover.Delta)
+	}
+	if mover.Transaction == nil || mover.Transaction.ID != "t1" {
+		t.Errorf("Mover.Transaction: got %+v, want t1", mover.Transaction)
+	}
+}
+
+func TestAnalyzeWeeklyData_BiggestMoverDrop(t *testing.T) {
+	prev := map[string]int64{"Dining": 350_000, "Groceries": 200_000, "Transport": 500_000}
+	result, err := NewAnalyzer().AnalyzeWeeklyData(baseWeeklyData(), prev, 0)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Mover == nil || result.Mover.Category != "Transport" || result.Mover.Delta != -350_000 {
+		t.Fatalf("Mover: got %+v, want Transport -350000", result.Mover)
+	}
+	if result.Mover.Transaction != nil {
+		t.Error("a drop in spending should not name a transaction")
+	}
+}
+
+func TestAnalyzeWeeklyData_NoMoverWithoutPrevData(t *testing.T) {
+	result, err := NewAnalyzer().AnalyzeWeeklyData(baseWeeklyData(), nil, 0)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Mover != nil {
+		t.Errorf("Mover should be nil without previous data, got %+v", result.Mover)
+	}
+}
+
+func TestAnalyzeWeeklyData_Baseline(t *testing.T) {
+	data := baseWeeklyData()
+	data.Baseline = map[string]int64{"Groceries": 800_000, "Dining": 400_000}
+
+	result, err := NewAnalyzer().AnalyzeWeeklyData(data, nil, 0)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !result.HasBaseline {
+		t.Fatal("HasBaseline should be set with baseline spending")
+	}
+	want := map[string]int64{"Groceries": 200_000, "Dining": 100_000, "Transport": 0}
+	for _, category := range result.TopSpending {
+		if avg, ok := want[category.Category]; ok && category.AvgSpent != avg {
+			t.Errorf("%s AvgSpent: got %d, want %d", category.Category, category.AvgSpent, avg)
+		}
+	}
+
+	// Data from Jan 1 doesn't cover the four weeks before Jan 19
+	data.Budget.DataStart = makeDate(2026, 1, 1)
+	if result, _ := NewAnalyzer().AnalyzeWeeklyData(data, nil, 0); result.HasBaseline {
+		t.Error("HasBaseline should be false when the budget doesn't reach back four weeks")
+	}
+}
+
+func TestAnalyzeWeeklyData_FirstReportSkipsComparison(t *testing.T) {
+	data := baseWeeklyData()
+	data.Budget.DataStart = makeDate(2026, 1, 15) // after the previous week started on Jan 12
+
+	result, err := NewAnalyzer().AnalyzeWeeklyData(data, map[string]int64{}, 0)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !result.FirstReport {
+		t.Error("FirstReport should be true when budget data starts after the previous week")
+	}
+	if result.HasPrevData {
+		t.Error("HasPrevData should be false on the first report")
+	}
+	if result.Coverage != nil {
+		t.Errorf("Coverage should be nil when the week is fully covered, got %+v", result.Coverage)
+	}
+}
+
+func TestAnalyzeWeeklyData_PartialWeekCoverage(t *testing.T) {
+	data := baseWeeklyData()
+	data.Budget.DataStart = makeDate(2026, 1, 22)
+
+	result, err := NewAnalyzer().AnalyzeWeeklyData(data, nil, 0)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Coverage == nil {
+		t.Fatal("expected Coverage for a partial week")
+	}
+	if result.Coverage.Days != 5 || result.Coverage.PeriodDays != 7 {
+		t.Errorf("Coverage: got %d of %d days, want 5 of 7", result.Coverage.Days, result.Coverage.PeriodDays)
+	}
+	if !result.FirstReport {
+		t.Error("FirstReport should be true for a partial first week")
+	}
+}
+
+func TestAnalyzeMonthlyData_FirstReportSkipsComparison(t *testing.T) {
+	data := baseMonthlyData()
+	data.Budget.DataStart = makeDate(2026, 1, 3)
+
+	result, err := NewAnalyzer().AnalyzeMonthlyData(data, map[string]int64{"Dining": 0}, 0)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !result.FirstReport || result.HasPrevData {
+		t.Errorf("FirstReport/HasPrevData: got %v/%v, want true/false", result.FirstReport, result.HasPrevData)
+	}
+}
+
+// ── Benchmarks ────────────────────────────────────────────────────────────────
+
+func TestAnalyzeWeeklyData_Benchmarks(t *testing.T) {
+	a := NewAnalyzer(WithBenchmarks(map[string]int64{
+		"Groceries": 130_000,
+		"Dining":    400_000,
+		"Coffee":    20_000, // no YNAB category — still reported
+	}))
+	result, err := a.AnalyzeWeeklyData(baseWeeklyData(), nil, 0)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(result.Benchmarks) != 3 {
+		t.Fatalf("Benchmarks count: got %d, want 3", len(result.Benchmarks))
+	}
+
+	// Groceries: 200_000 spent vs 130_000 target → +70_000, sorted first
+	first := result.Benchmarks[0]
+	if first.Category != "Groceries" || first.Delta != 70_000 {
+		t.Errorf("Benchmarks[0]: got %s delta %d, want Groceries delta 70000", first.Category, first.Delta)
+	}
+
+	for _, b := range result.Benchmarks {
+		if b.Category == "Coffee" && b.Actual != 0 {
+			t.Errorf("Coffee actual: got %d, want 0", b.Actual)
+		}
+		if b.Category == "Dining" && b.Delta != -50_000 {
+			t.Errorf("Dining delta: got %d, want -50000", b.Delta)
+		}
+	}
+}
+
+func TestAnalyzeWeeklyData_NoBenchmarksConfigured(t *testing.T) {
+	a := NewAnalyzer()
+	result, err := a.AnalyzeWeeklyData(baseWeeklyData(), nil, 0)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Benchmarks != nil {
+		t.Errorf("Benchmarks should be nil when none configured, got %+v", result.Benchmarks)
+	}
+}
+
+// ── Velocity alerts ───────────────────────────────────────────────────────────
+
+func velocityWeeklyData(weekEnd time.Time) *ynab.WeeklyData {
+	data := baseWeeklyData()
+	data.Categories = []ynab.Category{
+		{ID: "c1", Name: "Dining Out", Budgeted: 300_000, Activity: -180_000, Balance: 120_000}, // 60%
+		{ID: "c2", Name: "Groceries", Budgeted: 500_000, Activity: -100_000, Balance: 400_000},  // 20%
+	}
+	data.WeekEnd = weekEnd
+	return data
+}
+
+func TestAnalyzeWeeklyData_VelocityAlertEarlyInMonth(t *testing.T) {
+	a := NewAnalyzer(WithVelocity(10, 50))
+	result, err := a.AnalyzeWeeklyData(velocityWeeklyData(time.Date(2026, 2, 8, 0, 0, 0, 0, time.UTC)), nil, 0)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(result.Velocity) != 1 {
+		t.Fatalf("Velocity count: got %d, want 1", len(result.Velocity))
+	}
+	alert := result.Velocity[0]
+	if alert.Category != "Dining Out" || alert.Day != 8 || alert.Percentage != 60 {
+		t.Errorf("unexpected alert: %+v", alert)
+	}
+}
+
+func TestAnalyzeWeeklyData_VelocityOutsideWindow(t *testing.T) {
+	a := NewAnalyzer(WithVelocity(10, 50))
+	result, err := a.AnalyzeWeeklyData(velocityWeeklyData(time.Date(2026, 2, 20, 0, 0, 0, 0, time.UTC)), nil, 0)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(result.Velocity) != 0 {
+		t.Errorf("expected no velocity alerts after day 10, got %+v", result.Velocity)
+	}
+}
+
+func TestAnalyzeWeeklyData_VelocityDisabled(t *testing.T) {
+	a := NewAnalyzer()
+	result, err := a.AnalyzeWeeklyData(velocityWeeklyData(time.Date(2026, 2, 3, 0, 0, 0, 0, time.UTC)), nil, 0)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(result.Velocity) != 0 {
+		t.Errorf("expected no velocity alerts when disabled, got %+v", result.Velocity)
+	}
+}
+
+func TestAnalyzeWeeklyData_VelocityFiscalMonth(t *testing.T) {
+	// Month runs from the 25th; Feb 1 is day 8 of the budget month
+	data := velocityWeeklyData(time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC))
+	data.MonthToDate = []ynab.Transaction{
+		makeTx("t1", makeDate(2026, 1, 26), -120_000, "Groceries"),
+		makeTx("t2", makeDate(2026, 1, 30), -160_000, "Groceries"),
+	}
+
+	a := NewAnalyzer(WithVelocity(10, 50), WithFiscalMonthStart(25))
+	result, err := a.AnalyzeWeeklyData(data, nil, 0)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(result.Velocity) != 1 {
+		t.Fatalf("Velocity count: got %d, want 1: %+v", len(result.Velocity), result.Velocity)
+	}
+	alert := result.Velocity[0]
+	if alert.Category != "Groceries" || alert.Day != 8 || alert.MonthSpent != 280_000 {
+		t.Errorf("unexpected alert: %+v", alert)
+	}
+}
+
+func TestAnalyzeWeeklyData_WeeksLeftFiscalMonth(t *testing.T) {
+	data := baseWeeklyData()
+	data.WeekEnd = time.Date(2026, 2, 10, 0, 0, 0, 0, time.UTC)
+
+	result, err := NewAnalyzer(WithFiscalMonthStart(25)).AnalyzeWeeklyData(data, nil, 0)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// Budget month ends Feb 24: 14 days left
+	if result.AheadFocus.WeeksLeft != 2 {
+		t.Errorf("WeeksLeft: got %d, want 2", result.AheadFocus.WeeksLeft)
+	}
+}
+
+// ── Needs vs wants split ──────────────────────────────────────────────────────
+
+func TestAnalyzeWeeklyData_SpendingSplit(t *testing.T) {
+	data := baseWeeklyData()
+	data.Categories[1].CategoryGroup = ynab.CategoryGroup{Name: "Bills"}
+
+	a := NewAnalyzer(WithSpendingSplit(map[string]string{
+		"Groceries": "need",
+		"Bills":     "need", // group tag covers Transport
+		"Dining":    "want",
+	}, SplitTarget{Needs: 50, Wants: 30, Savings: 20}))
+	result, err := a.AnalyzeWeeklyData(data, nil, 0)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	split := result.Split
+	if split == nil {
+		t.Fatal("expected Split to be set")
+	}
+	if split.Needs != 350_000 || split.Wants != 350_000 {
+		t.Errorf("split: got needs %d wants %d, want 350000 each", split.Needs, split.Wants)
+	}
+	if split.NeedsPercent != 50 || split.WantsPercent != 50 || split.SavingsPercent != 0 {
+		t.Errorf("split percentages: got %.0f/%.0f/%.0f, want 50/50/0", split.NeedsPercent, split.WantsPercent, split.SavingsPercent)
+	}
+	if split.Untagged != 0 {
+		t.Errorf("Untagged: got %d, want 0", split.Untagged)
+	}
+}
+
+func TestAnalyzeWeeklyData_SpendingSplitUntagged(t *testing.T) {
+	a := NewAnalyzer(WithSpendingSplit(map[string]string{"Groceries": "need"}, SplitTarget{}))
+	result, err := a.AnalyzeWeeklyData(baseWeeklyData(), nil, 0)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if result.Split.Untagged != 500_000 {
+		t.Errorf("Untagged: got %d, want 500000", result.Split.Untagged)
+	}
+	if result.Split.NeedsPercent != 100 {
+		t.Errorf("NeedsPercent: got %.0f, want 100", result.Split.NeedsPercent)
+	}
+}
+
+func TestAnalyzeWeeklyData_NoSplitWithoutTags(t *testing.T) {
+	result, err := NewAnalyzer().AnalyzeWeeklyData(baseWeeklyData(), nil, 0)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Split != nil {
+		t.Errorf("expected nil Split without tags, got %+v", result.Split)
+	}
+}
+
+func TestAnalyzeWeeklyData_LumpyCategories(t *testing.T) {
+	a := NewAnalyzer(WithLumpyCategories([]string{"Transport"}))
+	result, err := a.AnalyzeWeeklyData(baseWeeklyData(), nil, 0)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if result.Overview.TotalSpent != 700_000 || result.Overview.LumpySpent != 150_000 {
+		t.Errorf("expected lumpy spend to stay in the total, got %+v", result.Overview)
+	}
+	// (200 + 350) / (500 + 300) without Transport
+	if result.Overview.HealthPercentage != 68.75 {
+		t.Errorf("HealthPercentage: got %.2f, want 68.75", result.Overview.HealthPercentage)
+	}
+
+	for _, cat := range result.TopSpending {
+		if cat.Category == "Transport" && (!cat.Lumpy || cat.Percentage != 0) {
+			t.Errorf("expected Transport to be lumpy without a percentage, got %+v", cat)
+		}
+	}
+	for _, name := range result.AheadFocus.Watch {
+		if name == "Transport" {
+			t.Errorf("expected lumpy category to be left out of the watch list, got %v", result.AheadFocus.Watch)
+		}
+	}
+}
+
+func TestAnalyzeWeeklyData_LumpyCategoryGroup(t *testing.T) {
+	data := baseWeeklyData()
+	data.Categories[1].CategoryGroup = ynab.CategoryGroup{Name: "Annual"}
+
+	result, err := NewAnalyzer(WithLumpyCategories([]string{"Annual"})).AnalyzeWeeklyData(data, nil, 0)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Overview.LumpySpent != 150_000 {
+		t.Errorf("expected categories in a lumpy group to be lumpy, got LumpySpent %d", result.Overview.LumpySpent)
+	}
+}
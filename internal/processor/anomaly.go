@@ -0,0 +1,65 @@
+package processor
+
+import (
+	"math"
+	"sort"
+
+	"github.com/sathyabhat/ynab-weekly-wrap/internal/ynab"
+)
+
+// minAnomalySamples is how many past outflows a category needs before its
+// transactions are judged against them
+const minAnomalySamples = 5
+
+// UnusualTransaction is an outflow far larger than the category's past ones
+type UnusualTransaction struct {
+	Transaction ynab.Transaction `json:"transaction"`
+	Mean        int64            `json:"mean"`     // Average past outflow in the category
+	StdDevs     float64          `json:"std_devs"` // How far above Mean, in standard deviations
+}
+
+// FindUnusual returns the outflows in transactions more than threshold
+// standard deviations above the mean of the category's past outflows, which
+// are keyed by category ID, largest first. The deviation is taken as at
+// least a tenth of the mean, so a category paid the same amount every time
+// isn't flagged over a small price change.
+func FindUnusual(transactions []ynab.Transaction, past map[string][]int64, threshold float64) []UnusualTransaction {
+	var unusual []UnusualTransaction
+	for _, tx := range transactions {
+		if tx.Deleted || tx.Amount >= 0 || tx.CategoryID == nil || tx.TransferAccountID != nil {
+			continue
+		}
+		amounts := past[*tx.CategoryID]
+		if len(amounts) < minAnomalySamples {
+			continue
+		}
+		mean, stdDev := meanStdDev(amounts)
+		stdDev = max(stdDev, mean/10)
+		if stdDev == 0 {
+			continue
+		}
+		if deviations := (float64(-tx.Amount) - mean) / stdDev; deviations > threshold {
+			unusual = append(unusual, UnusualTransaction{
+				Transaction: tx,
+				Mean:        int64(math.Round(mean)),
+				StdDevs:     deviations,
+			})
+		}
+	}
+	sort.SliceStable(unusual, func(i, j int) bool {
+		return unusual[i].Transaction.Amount < unusual[j].Transaction.Amount
+	})
+	return unusual
+}
+
+// meanStdDev returns the mean and population standard deviation of amounts
+func meanStdDev(amounts []int64) (mean, stdDev float64) {
+	for _, amount := range amounts {
+		mean += float64(amount)
+	}
+	mean /= float64(len(amounts))
+	for _, amount := range amounts {
+		stdDev += (float64(amount) - mean) * (float64(amount) - mean)
+	}
+	return mean, math.Sqrt(stdDev / float64(len(amounts)))
+}
@@ -0,0 +1,42 @@
+package processor
+
+import (
+	"testing"
+
+	"github.com/sathyabhat/ynab-weekly-wrap/internal/ynab"
+)
+
+func TestFindUnusual(t *testing.T) {
+	date := makeDate(2026, 1, 20)
+	past := map[string][]int64{
+		"Groceries": {80_000, 90_000, 100_000, 110_000, 120_000}, // Mean 100, deviation ~14
+		"Rent":      {2_000_000, 2_000_000, 2_000_000, 2_000_000, 2_000_000},
+		"Dining":    {20_000, 25_000, 30_000}, // Too few to judge
+	}
+	transactions := []ynab.Transaction{
+		makeTx("t1", date, -150_000, "Groceries"),
+		makeTx("t2", date, -120_000, "Groceries"),
+		makeTx("t3", date, -2_050_000, "Rent"), // Within a tenth of the mean
+		makeTx("t4", date, -3_000_000, "Rent"),
+		makeTx("t5", date, -500_000, "Dining"),
+		makeTx("t6", date, 400_000, "Groceries"), // Refund
+	}
+
+	unusual := FindUnusual(transactions, past, 2)
+	if len(unusual) != 2 {
+		t.Fatalf("expected rent and the large grocery shop, got %+v", unusual)
+	}
+	if unusual[0].Transaction.ID != "t4" || unusual[0].Mean != 2_000_000 || unusual[0].StdDevs != 5 {
+		t.Errorf("unusual[0]: got %+v, want t4 five deviations above 2,000,000", unusual[0])
+	}
+	if unusual[1].Transaction.ID != "t1" || unusual[1].Mean != 100_000 || unusual[1].StdDevs < 3.5 || unusual[1].StdDevs > 3.6 {
+		t.Errorf("unusual[1]: got %+v, want t1 about 3.5 deviations above 100,000", unusual[1])
+	}
+}
+
+func TestFindUnusual_NoHistory(t *testing.T) {
+	transactions := []ynab.Transaction{makeTx("t1", makeDate(2026, 1, 20), -150_000, "Groceries")}
+	if unusual := FindUnusual(transactions, nil, 2); len(unusual) != 0 {
+		t.Errorf("expected nothing flagged without history, got %+v", unusual)
+	}
+}
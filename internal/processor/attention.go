@@ -0,0 +1,63 @@
+package processor
+
+import "github.com/sathyabhat/ynab-weekly-wrap/internal/ynab"
+
+// NeedsAttention is what still needs sorting out in YNAB before the week's
+// numbers are complete
+type NeedsAttention struct {
+	Uncategorized []ynab.Transaction `json:"uncategorized,omitempty"`
+	Unapproved    []ynab.Transaction `json:"unapproved,omitempty"`   // Only with the pending report on
+	Uncleared     []ynab.Transaction `json:"uncleared,omitempty"`    // Only with the pending report on
+	ListPending   bool               `json:"list_pending,omitempty"` // List the unapproved and uncleared transactions, not just count them
+}
+
+// findNeedsAttention collects the transactions with no category, which the
+// spending figures leave out. It returns nil when there are none.
+func findNeedsAttention(transactions []ynab.Transaction) *NeedsAttention {
+	attention := &NeedsAttention{}
+	for _, tx := range transactions {
+		if !tx.Deleted && isUncategorized(tx) {
+			attention.Uncategorized = append(attention.Uncategorized, tx)
+		}
+	}
+	if len(attention.Uncategorized) == 0 {
+		return nil
+	}
+	return attention
+}
+
+// AddPending adds the unapproved and uncleared transactions, which skew the
+// category numbers until they are reconciled, to what needs attention, to
+// be listed in full when list is set. It returns nil when nothing needs
+// attention.
+func AddPending(attention *NeedsAttention, transactions []ynab.Transaction, list bool) *NeedsAttention {
+	if attention == nil {
+		attention = &NeedsAttention{}
+	}
+	attention.Unapproved, attention.Uncleared, attention.ListPending = nil, nil, list
+	for _, tx := range transactions {
+		if tx.Deleted {
+			continue
+		}
+		if tx.Unapproved {
+			attention.Unapproved = append(attention.Unapproved, tx)
+		}
+		if tx.Uncleared {
+			attention.Uncleared = append(attention.Uncleared, tx)
+		}
+	}
+	if len(attention.Uncategorized) == 0 && len(attention.Unapproved) == 0 && len(attention.Uncleared) == 0 {
+		return nil
+	}
+	return attention
+}
+
+// Outflow returns the net outflow of transactions, negative when more came
+// in than went out
+func Outflow(transactions []ynab.Transaction) int64 {
+	var outflow int64
+	for _, tx := range transactions {
+		outflow -= tx.Amount
+	}
+	return outflow
+}
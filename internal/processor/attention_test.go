@@ -0,0 +1,56 @@
+package processor
+
+import (
+	"testing"
+
+	"github.com/sathyabhat/ynab-weekly-wrap/internal/ynab"
+)
+
+func TestFindNeedsAttention(t *testing.T) {
+	uncategorized := makeTx("t1", makeDate(2026, 1, 20), -45_000, "Uncategorized")
+	noCategory := makeTx("t2", makeDate(2026, 1, 21), -80_000, "")
+	noCategory.CategoryID = nil
+	refund := makeTx("t3", makeDate(2026, 1, 22), 5_000, "Uncategorized")
+	deleted := makeTx("t4", makeDate(2026, 1, 22), -10_000, "Uncategorized")
+	deleted.Deleted = true
+	transfer := makeTx("t5", makeDate(2026, 1, 23), -500_000, "")
+	transfer.CategoryID, transfer.TransferAccountID = nil, new(string)
+
+	transactions := []ynab.Transaction{
+		uncategorized, noCategory, refund, deleted, transfer,
+		makeTx("t6", makeDate(2026, 1, 23), -20_000, "Groceries"),
+	}
+	attention := findNeedsAttention(transactions)
+	if attention == nil || len(attention.Uncategorized) != 3 || Outflow(attention.Uncategorized) != 120_000 {
+		t.Fatalf("expected three uncategorized transactions, $120 out, got %+v", attention)
+	}
+
+	if attention := findNeedsAttention(transactions[5:]); attention != nil {
+		t.Errorf("expected nothing needing attention, got %+v", attention)
+	}
+}
+
+func TestAddPending(t *testing.T) {
+	unapproved := makeTx("t1", makeDate(2026, 1, 20), -45_000, "Groceries")
+	unapproved.Unapproved = true
+	uncleared := makeTx("t2", makeDate(2026, 1, 21), -80_000, "Dining")
+	uncleared.Uncleared = true
+	both := makeTx("t3", makeDate(2026, 1, 22), -20_000, "Dining")
+	both.Unapproved, both.Uncleared = true, true
+	deleted := makeTx("t4", makeDate(2026, 1, 22), -10_000, "Dining")
+	deleted.Unapproved, deleted.Deleted = true, true
+	transactions := []ynab.Transaction{unapproved, uncleared, both, deleted, makeTx("t5", makeDate(2026, 1, 23), -5_000, "Dining")}
+
+	uncategorized := &NeedsAttention{Uncategorized: []ynab.Transaction{makeTx("t6", makeDate(2026, 1, 23), -5_000, "Uncategorized")}}
+	attention := AddPending(uncategorized, transactions, false)
+	if len(attention.Uncategorized) != 1 || len(attention.Unapproved) != 2 || len(attention.Uncleared) != 2 {
+		t.Fatalf("expected 1 uncategorized, 2 unapproved and 2 uncleared, got %+v", attention)
+	}
+	if Outflow(attention.Unapproved) != 65_000 || Outflow(attention.Uncleared) != 100_000 {
+		t.Errorf("expected $65 unapproved and $100 uncleared, got %+v", attention)
+	}
+
+	if attention := AddPending(nil, transactions[4:], true); attention != nil {
+		t.Errorf("expected nothing needing attention, got %+v", attention)
+	}
+}
@@ -0,0 +1,60 @@
+package processor
+
+import "github.com/sathyabhat/ynab-weekly-wrap/internal/ynab"
+
+// cardPaymentGroup is the YNAB category group holding a payment category
+// for each credit card, named after the card's account
+const cardPaymentGroup = "Credit Card Payments"
+
+// CardPayment is a credit card's week: what was charged to it and paid off,
+// and whether its payment category holds enough to pay what is owed
+type CardPayment struct {
+	Account   string `json:"account"`
+	Spent     int64  `json:"spent"`     // Charged this week, less refunds
+	Paid      int64  `json:"paid"`      // Payments to the card this week
+	Owed      int64  `json:"owed"`      // Card balance, positive when money is owed
+	Available int64  `json:"available"` // Balance of the card's payment category
+}
+
+// Shortfall returns how much more the payment category needs to pay off the
+// card, or 0 when it's covered
+func (c CardPayment) Shortfall() int64 {
+	return max(c.Owed-c.Available, 0)
+}
+
+// CardPayments summarizes the open on-budget credit cards and lines of
+// credit, in the order YNAB returns them. Cards with nothing owed and no
+// activity this week are left out.
+func CardPayments(accounts []ynab.Account, categories []ynab.Category, transactions []ynab.Transaction) []CardPayment {
+	available := make(map[string]int64)
+	for _, cat := range categories {
+		if cat.CategoryGroup.Name == cardPaymentGroup {
+			available[cat.Name] = cat.Balance
+		}
+	}
+
+	var cards []CardPayment
+	for _, account := range accounts {
+		if account.Closed || !account.OnBudget || (account.Type != "creditCard" && account.Type != "lineOfCredit") {
+			continue
+		}
+		card := CardPayment{Account: account.Name, Owed: -account.Balance, Available: available[account.Name]}
+		for _, tx := range transactions {
+			if tx.Deleted || tx.AccountID != account.ID {
+				continue
+			}
+			if tx.TransferAccountID != nil {
+				if tx.Amount > 0 {
+					card.Paid += tx.Amount
+				}
+				continue
+			}
+			card.Spent -= tx.Amount
+		}
+		if card.Owed == 0 && card.Spent == 0 && card.Paid == 0 {
+			continue
+		}
+		cards = append(cards, card)
+	}
+	return cards
+}
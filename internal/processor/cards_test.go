@@ -0,0 +1,49 @@
+package processor
+
+import (
+	"testing"
+
+	"github.com/sathyabhat/ynab-weekly-wrap/internal/ynab"
+)
+
+func TestCardPayments(t *testing.T) {
+	payments := ynab.CategoryGroup{Name: "Credit Card Payments"}
+	visaPayment := makeCategory("p1", "Visa", 0, 420_000)
+	visaPayment.CategoryGroup = payments
+	amexPayment := makeCategory("p2", "Amex", 0, 600_000)
+	amexPayment.CategoryGroup = payments
+
+	accounts := []ynab.Account{
+		{ID: "a1", Name: "Checking", Type: "checking", OnBudget: true, Balance: 3_000_000},
+		{ID: "a2", Name: "Visa", Type: "creditCard", OnBudget: true, Balance: -420_000},
+		{ID: "a3", Name: "Amex", Type: "creditCard", OnBudget: true, Balance: -900_000},
+		{ID: "a4", Name: "Store Card", Type: "creditCard", OnBudget: true},
+	}
+
+	onCard := func(id, account string, amount int64) ynab.Transaction {
+		tx := makeTx(id, makeDate(2026, 1, 20), amount, "Groceries")
+		tx.AccountID = account
+		return tx
+	}
+	payment := onCard("t4", "a2", 500_000)
+	payment.CategoryID, payment.TransferAccountID = nil, new(string)
+	transactions := []ynab.Transaction{
+		onCard("t1", "a2", -300_000),
+		onCard("t2", "a2", 20_000), // Refund
+		onCard("t3", "a3", -150_000),
+		payment,
+		onCard("t5", "a1", -80_000),
+	}
+
+	cards := CardPayments(accounts, []ynab.Category{visaPayment, amexPayment}, transactions)
+	if len(cards) != 2 {
+		t.Fatalf("expected the two cards with activity, got %+v", cards)
+	}
+	visa, amex := cards[0], cards[1]
+	if visa.Account != "Visa" || visa.Spent != 280_000 || visa.Paid != 500_000 || visa.Owed != 420_000 || visa.Shortfall() != 0 {
+		t.Errorf("expected Visa fully covered, got %+v", visa)
+	}
+	if amex.Account != "Amex" || amex.Spent != 150_000 || amex.Shortfall() != 300_000 {
+		t.Errorf("expected Amex $300 short, got %+v", amex)
+	}
+}
@@ -0,0 +1,70 @@
+package processor
+
+import (
+	"strings"
+
+	"github.com/sathyabhat/ynab-weekly-wrap/internal/ynab"
+)
+
+// inflowCategoryPrefix starts the name of YNAB's built-in category for
+// income, "Inflow: Ready to Assign" (formerly "Inflow: To be Budgeted")
+const inflowCategoryPrefix = "Inflow"
+
+// CashFlow is the money that came in and went out over a period
+type CashFlow struct {
+	Income   int64 `json:"income"`   // Inflows to Ready to Assign
+	Spending int64 `json:"spending"` // Outflows less refunds, positive when more went out
+	Net      int64 `json:"net"`      // Income - Spending
+}
+
+// isIncome reports whether a transaction is money received into the budget,
+// as opposed to a refund to a spending category
+func isIncome(tx ynab.Transaction) bool {
+	return tx.Amount > 0 && strings.HasPrefix(tx.CategoryName, inflowCategoryPrefix)
+}
+
+// calculateCashFlow totals income and spending. Refunds count against
+// spending rather than as income, and transfers between budget accounts
+// are neither, as the money stays in the budget.
+func calculateCashFlow(transactions []ynab.Transaction) *CashFlow {
+	flow := &CashFlow{}
+	for _, tx := range transactions {
+		if tx.Deleted || tx.TransferAccountID != nil {
+			continue
+		}
+		if isIncome(tx) {
+			flow.Income += tx.Amount
+		} else if tx.CategoryID != nil {
+			flow.Spending -= tx.Amount
+		}
+	}
+	flow.Net = flow.Income - flow.Spending
+	return flow
+}
+
+// SavingsRate returns the share of income not spent, in percent, or false
+// when nothing came in
+func (f *CashFlow) SavingsRate() (float64, bool) {
+	if f == nil || f.Income <= 0 {
+		return 0, false
+	}
+	return float64(f.Net) / float64(f.Income) * 100, true
+}
+
+// Savings is how much of the income was kept over the week and the budget
+// month so far
+type Savings struct {
+	Week        *CashFlow `json:"week"`
+	MonthToDate *CashFlow `json:"month_to_date,omitempty"` // Nil when the month's transactions aren't known
+	Target      int       `json:"target,omitempty"`        // Percent of income to save; 0 for no target
+}
+
+// CalculateSavings works out the savings rates from the week's and the
+// month-to-date transactions
+func CalculateSavings(week, monthToDate []ynab.Transaction, target int) *Savings {
+	savings := &Savings{Week: calculateCashFlow(week), Target: target}
+	if monthToDate != nil {
+		savings.MonthToDate = calculateCashFlow(monthToDate)
+	}
+	return savings
+}
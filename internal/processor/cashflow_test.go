@@ -0,0 +1,46 @@
+package processor
+
+import (
+	"testing"
+
+	"github.com/sathyabhat/ynab-weekly-wrap/internal/ynab"
+)
+
+func TestCalculateCashFlow(t *testing.T) {
+	date := makeDate(2026, 1, 20)
+	transfer := makeTx("t5", date, -500_000, "")
+	transfer.CategoryID, transfer.TransferAccountID = nil, new(string)
+	deleted := makeTx("t6", date, -90_000, "Groceries")
+	deleted.Deleted = true
+
+	flow := calculateCashFlow([]ynab.Transaction{
+		makeTx("t1", date, 2_000_000, "Inflow: Ready to Assign"),
+		makeTx("t2", date, -150_000, "Groceries"),
+		makeTx("t3", date, -60_000, "Dining"),
+		makeTx("t4", date, 10_000, "Groceries"), // Refund
+		transfer,
+		deleted,
+	})
+
+	want := CashFlow{Income: 2_000_000, Spending: 200_000, Net: 1_800_000}
+	if *flow != want {
+		t.Errorf("got %+v, want %+v", *flow, want)
+	}
+}
+
+func TestCalculateSavings(t *testing.T) {
+	income := makeTx("t1", makeDate(2026, 1, 2), 4_000_000, "Inflow: Ready to Assign")
+	week := []ynab.Transaction{makeTx("t2", makeDate(2026, 1, 20), -600_000, "Groceries")}
+	monthToDate := append([]ynab.Transaction{income, makeTx("t3", makeDate(2026, 1, 9), -2_400_000, "Rent")}, week...)
+
+	savings := CalculateSavings(week, monthToDate, 20)
+	if _, ok := savings.Week.SavingsRate(); ok {
+		t.Error("expected no weekly rate without income")
+	}
+	if rate, ok := savings.MonthToDate.SavingsRate(); !ok || rate != 25 {
+		t.Errorf("month-to-date rate: got %.1f (%t), want 25", rate, ok)
+	}
+	if savings := CalculateSavings(week, nil, 0); savings.MonthToDate != nil {
+		t.Errorf("expected no month-to-date figures without its transactions, got %+v", savings.MonthToDate)
+	}
+}
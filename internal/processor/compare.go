@@ -0,0 +1,66 @@
+package processor
+
+import "sort"
+
+// What a weekly wrap can flag a category for
+const (
+	FlagTopSpending = "top spending"
+	FlagOverBudget  = "over budget"
+	FlagEarlyAlert  = "early alert"
+	FlagProjected   = "projected over budget"
+	FlagUnusual     = "unusual transaction"
+)
+
+// FlagChange is a category that one of two wraps of the same week flags and
+// the other doesn't, e.g. under current and proposed thresholds
+type FlagChange struct {
+	Category string `json:"category"`
+	Flag     string `json:"flag"`
+	InA      bool   `json:"in_a"` // Flagged by the first wrap only; otherwise by the second only
+}
+
+// CompareFlags lists what the two wraps flag differently, by category and
+// then flag
+func CompareFlags(a, b *AnalysisResult) []FlagChange {
+	flagsA, flagsB := categoryFlags(a), categoryFlags(b)
+	var changes []FlagChange
+	for flag := range flagsA {
+		if !flagsB[flag] {
+			changes = append(changes, FlagChange{Category: flag[0], Flag: flag[1], InA: true})
+		}
+	}
+	for flag := range flagsB {
+		if !flagsA[flag] {
+			changes = append(changes, FlagChange{Category: flag[0], Flag: flag[1]})
+		}
+	}
+	sort.Slice(changes, func(i, j int) bool {
+		x, y := changes[i], changes[j]
+		if x.Category != y.Category {
+			return x.Category < y.Category
+		}
+		return x.Flag < y.Flag
+	})
+	return changes
+}
+
+// categoryFlags returns the category and flag pairs a wrap raises
+func categoryFlags(result *AnalysisResult) map[[2]string]bool {
+	flags := make(map[[2]string]bool)
+	for _, top := range result.TopSpending {
+		flags[[2]string{top.Category, FlagTopSpending}] = true
+	}
+	for _, concern := range result.Concerns {
+		flags[[2]string{concern.Category, FlagOverBudget}] = true
+	}
+	for _, alert := range result.Velocity {
+		flags[[2]string{alert.Category, FlagEarlyAlert}] = true
+	}
+	for _, projection := range result.Projections {
+		flags[[2]string{projection.Category, FlagProjected}] = true
+	}
+	for _, unusual := range result.Unusual {
+		flags[[2]string{unusual.Transaction.CategoryName, FlagUnusual}] = true
+	}
+	return flags
+}
@@ -0,0 +1,39 @@
+package processor
+
+import (
+	"testing"
+
+	"github.com/sathyabhat/ynab-weekly-wrap/internal/ynab"
+)
+
+func TestCompareFlags(t *testing.T) {
+	current := &AnalysisResult{
+		TopSpending: []TopSpendingCategory{{Category: "Groceries"}, {Category: "Dining"}},
+		Concerns:    []CategoryConcernWithTransactions{{Category: "Dining"}},
+		Velocity:    []VelocityAlert{{Category: "Fun Money"}},
+	}
+	proposed := &AnalysisResult{
+		TopSpending: []TopSpendingCategory{{Category: "Groceries"}, {Category: "Dining"}, {Category: "Fuel"}},
+		Concerns:    []CategoryConcernWithTransactions{{Category: "Dining"}},
+		Unusual:     []UnusualTransaction{{Transaction: ynab.Transaction{CategoryName: "Health"}}},
+	}
+
+	changes := CompareFlags(current, proposed)
+	want := []FlagChange{
+		{Category: "Fuel", Flag: FlagTopSpending},
+		{Category: "Fun Money", Flag: FlagEarlyAlert, InA: true},
+		{Category: "Health", Flag: FlagUnusual},
+	}
+	if len(changes) != len(want) {
+		t.Fatalf("expected %d changes, got %+v", len(want), changes)
+	}
+	for i := range want {
+		if changes[i] != want[i] {
+			t.Errorf("change %d: got %+v, want %+v", i, changes[i], want[i])
+		}
+	}
+
+	if changes := CompareFlags(current, current); len(changes) != 0 {
+		t.Errorf("expected no changes between identical wraps, got %+v", changes)
+	}
+}
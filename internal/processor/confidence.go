@@ -0,0 +1,79 @@
+package processor
+
+import (
+	"sort"
+	"time"
+
+	"github.com/sathyabhat/ynab-weekly-wrap/internal/ynab"
+)
+
+// freshDays is how many days before the end of the period an account's
+// latest transaction can be for the account to count as up to date, i.e.
+// updated in the last 48 hours
+const freshDays = 2
+
+// DataConfidence tells readers how far to trust the period's numbers: how
+// many accounts have been updated recently, and how many imported
+// transactions are still waiting for approval
+type DataConfidence struct {
+	ActiveAccounts  int                `json:"active_accounts"`  // On-budget accounts with transactions in the period
+	UpdatedAccounts int                `json:"updated_accounts"` // Active accounts with a transaction in the last 48 hours
+	Stale           []StaleAccount     `json:"stale,omitempty"`  // Active accounts without one, least recent first
+	Unapproved      []ynab.Transaction `json:"unapproved,omitempty"`
+}
+
+// StaleAccount is an active account that hasn't been updated recently
+type StaleAccount struct {
+	Account         string    `json:"account"`
+	LastTransaction time.Time `json:"last_transaction"`
+}
+
+// UpdatedPercent returns the share of active accounts updated recently, or
+// 100 when no account was active
+func (c *DataConfidence) UpdatedPercent() float64 {
+	if c.ActiveAccounts == 0 {
+		return 100
+	}
+	return float64(c.UpdatedAccounts) / float64(c.ActiveAccounts) * 100
+}
+
+// assessConfidence checks how up to date the period's data is, or returns
+// nil when the accounts aren't known. Transactions only carry a date, so an
+// account counts as updated when its latest one is within freshDays of end.
+func assessConfidence(accounts []ynab.Account, transactions []ynab.Transaction, end time.Time) *DataConfidence {
+	if accounts == nil {
+		return nil
+	}
+
+	latest := make(map[string]time.Time)
+	confidence := &DataConfidence{}
+	for _, tx := range transactions {
+		if tx.Deleted || tx.Date == nil {
+			continue
+		}
+		if day := truncateToDay(*tx.Date); day.After(latest[tx.AccountID]) {
+			latest[tx.AccountID] = day
+		}
+		if tx.Unapproved {
+			confidence.Unapproved = append(confidence.Unapproved, tx)
+		}
+	}
+
+	freshFrom := truncateToDay(end).AddDate(0, 0, -freshDays)
+	for _, account := range accounts {
+		last, ok := latest[account.ID]
+		if !ok || account.Closed || !account.OnBudget {
+			continue
+		}
+		confidence.ActiveAccounts++
+		if !last.Before(freshFrom) {
+			confidence.UpdatedAccounts++
+			continue
+		}
+		confidence.Stale = append(confidence.Stale, StaleAccount{Account: account.Name, LastTransaction: last})
+	}
+	sort.SliceStable(confidence.Stale, func(i, j int) bool {
+		return confidence.Stale[i].LastTransaction.Before(confidence.Stale[j].LastTransaction)
+	})
+	return confidence
+}
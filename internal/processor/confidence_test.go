@@ -0,0 +1,53 @@
+package processor
+
+import (
+	"testing"
+	"time"
+
+	"github.com/sathyabhat/ynab-weekly-wrap/internal/ynab"
+)
+
+func TestAssessConfidence(t *testing.T) {
+	end := time.Date(2026, 1, 25, 23, 59, 59, 0, time.UTC)
+	accounts := []ynab.Account{
+		{ID: "a1", Name: "Checking", OnBudget: true},
+		{ID: "a2", Name: "Credit Card", OnBudget: true},
+		{ID: "a3", Name: "Savings", OnBudget: true},
+		{ID: "a4", Name: "Mortgage"},
+		{ID: "a5", Name: "Cash", OnBudget: true},
+	}
+	tx := func(id, account string, day int, unapproved bool) ynab.Transaction {
+		t := makeTx(id, makeDate(2026, 1, day), -10_000, "Groceries")
+		t.AccountID, t.Unapproved = account, unapproved
+		return t
+	}
+	transactions := []ynab.Transaction{
+		tx("t1", "a1", 24, true),
+		tx("t2", "a2", 19, false),
+		tx("t3", "a2", 23, false), // two days before the end still counts
+		tx("t4", "a3", 20, true),
+		tx("t5", "a4", 21, false), // off budget
+	}
+	deleted := tx("t6", "a3", 25, true)
+	deleted.Deleted = true
+
+	confidence := assessConfidence(accounts, append(transactions, deleted), end)
+	if confidence.ActiveAccounts != 3 || confidence.UpdatedAccounts != 2 {
+		t.Errorf("got %d of %d accounts updated, want 2 of 3", confidence.UpdatedAccounts, confidence.ActiveAccounts)
+	}
+	if len(confidence.Stale) != 1 || confidence.Stale[0].Account != "Savings" || !confidence.Stale[0].LastTransaction.Equal(*makeDate(2026, 1, 20)) {
+		t.Errorf("Stale: got %+v, want Savings last updated 2026-01-20", confidence.Stale)
+	}
+	if len(confidence.Unapproved) != 2 || confidence.Unapproved[0].ID != "t1" || confidence.Unapproved[1].ID != "t4" {
+		t.Errorf("Unapproved: got %+v, want t1 and t4", confidence.Unapproved)
+	}
+	if got := confidence.UpdatedPercent(); got < 66 || got > 67 {
+		t.Errorf("UpdatedPercent: got %.1f, want 66.7", got)
+	}
+}
+
+func TestAssessConfidence_AccountsUnknown(t *testing.T) {
+	if confidence := assessConfidence(nil, nil, time.Now()); confidence != nil {
+		t.Errorf("expected nil without accounts, got %+v", confidence)
+	}
+}
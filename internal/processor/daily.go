@@ -0,0 +1,78 @@
+package processor
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/sathyabhat/ynab-weekly-wrap/internal/money"
+	"github.com/sathyabhat/ynab-weekly-wrap/internal/ynab"
+)
+
+// DailyDigest is a short look at one day's spending, for people who want
+// feedback sooner than the weekly wrap
+type DailyDigest struct {
+	Day          time.Time          `json:"day"`
+	Transactions []ynab.Transaction `json:"transactions"` // The day's spending, largest first
+	DaySpent     int64              `json:"day_spent"`
+	WeekStart    time.Time          `json:"week_start"`
+	WeekSpent    int64              `json:"week_spent"` // Spending from WeekStart through Day
+	// Categories the day's spending took over budget
+	NewlyOverBudget []OverBudgetCategory `json:"newly_over_budget,omitempty"`
+	Currency        money.Format         `json:"currency"`
+}
+
+// OverBudgetCategory is a category that went over budget
+type OverBudgetCategory struct {
+	Category string `json:"category"`
+	Over     int64  `json:"over"`      // How far over budget it is now
+	DaySpent int64  `json:"day_spent"` // What was spent in it on the day
+}
+
+// AnalyzeDailyData summarizes the spending on data.Day. A category counts as
+// newly over budget when it is overspent now but wasn't before the day's
+// spending in it.
+func (a *Analyzer) AnalyzeDailyData(data *ynab.DailyData) (*DailyDigest, error) {
+	if data == nil {
+		return nil, fmt.Errorf("daily data is nil: %w", ynab.ErrNoData)
+	}
+
+	digest := &DailyDigest{
+		Day:       data.Day,
+		WeekStart: data.WeekStart,
+		Currency:  budgetCurrency(data.Budget),
+	}
+	day := truncateToDay(data.Day)
+	daySpend := make(map[string]int64)
+	for _, tx := range data.Transactions {
+		if tx.Deleted || tx.CategoryID == nil || tx.Amount >= 0 {
+			continue
+		}
+		digest.WeekSpent += -tx.Amount
+		if tx.Date == nil || !truncateToDay(*tx.Date).Equal(day) {
+			continue
+		}
+		digest.DaySpent += -tx.Amount
+		daySpend[tx.CategoryName] += -tx.Amount
+		digest.Transactions = append(digest.Transactions, tx)
+	}
+	sort.SliceStable(digest.Transactions, func(i, j int) bool {
+		return digest.Transactions[i].Amount < digest.Transactions[j].Amount
+	})
+
+	for _, cat := range data.Categories {
+		spent := daySpend[cat.Name]
+		if spent == 0 || cat.Balance >= 0 || cat.Balance+spent < 0 {
+			continue
+		}
+		digest.NewlyOverBudget = append(digest.NewlyOverBudget, OverBudgetCategory{
+			Category: cat.Name,
+			Over:     -cat.Balance,
+			DaySpent: spent,
+		})
+	}
+	sort.SliceStable(digest.NewlyOverBudget, func(i, j int) bool {
+		return digest.NewlyOverBudget[i].Over > digest.NewlyOverBudget[j].Over
+	})
+	return digest, nil
+}
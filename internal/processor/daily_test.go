@@ -0,0 +1,48 @@
+package processor
+
+import (
+	"testing"
+	"time"
+
+	"github.com/sathyabhat/ynab-weekly-wrap/internal/ynab"
+)
+
+func TestAnalyzeDailyData(t *testing.T) {
+	data := &ynab.DailyData{
+		Categories: []ynab.Category{
+			makeCategory("1", "Groceries", 400_000, -30_000),  // Pushed over by today's 50
+			makeCategory("2", "Dining Out", 200_000, -10_000), // Already over before today
+			makeCategory("3", "Fuel", 150_000, 90_000),
+		},
+		Transactions: []ynab.Transaction{
+			makeTx("t1", makeDate(2026, 2, 16), -80_000, "Groceries"),
+			makeTx("t2", makeDate(2026, 2, 18), -50_000, "Groceries"),
+			makeTx("t3", makeDate(2026, 2, 18), -5_000, "Dining Out"),
+			makeTx("t4", makeDate(2026, 2, 18), -60_000, "Fuel"),
+			makeTx("t5", makeDate(2026, 2, 18), 1_000_000, "Inflow"),
+		},
+		WeekStart: time.Date(2026, 2, 16, 0, 0, 0, 0, time.UTC),
+		Day:       time.Date(2026, 2, 18, 0, 0, 0, 0, time.UTC),
+	}
+
+	digest, err := NewAnalyzer().AnalyzeDailyData(data)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if digest.DaySpent != 115_000 || digest.WeekSpent != 195_000 {
+		t.Errorf("spent: got %d today and %d this week, want 115000 and 195000", digest.DaySpent, digest.WeekSpent)
+	}
+	if len(digest.Transactions) != 3 || digest.Transactions[0].ID != "t4" {
+		t.Errorf("expected the day's 3 outflows largest first, got %+v", digest.Transactions)
+	}
+	if len(digest.NewlyOverBudget) != 1 || digest.NewlyOverBudget[0].Category != "Groceries" || digest.NewlyOverBudget[0].Over != 30_000 {
+		t.Errorf("expected only Groceries newly over budget, got %+v", digest.NewlyOverBudget)
+	}
+}
+
+func TestAnalyzeDailyData_Nil(t *testing.T) {
+	if _, err := NewAnalyzer().AnalyzeDailyData(nil); err == nil {
+		t.Error("expected an error for nil data")
+	}
+}
@@ -0,0 +1,64 @@
+package processor
+
+import (
+	"fmt"
+	"time"
+)
+
+// ParseDateRange reads the days a one-off wrap covers, both inclusive: from
+// and to as dates such as 2026-01-19, or weekOf, a date whose Monday-to-Sunday
+// week to cover. An empty to means today, in now's timezone. Days are
+// returned at midnight UTC, like YNAB transaction dates.
+func ParseDateRange(from, to, weekOf string, now time.Time) (start, end time.Time, err error) {
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+
+	switch {
+	case weekOf != "":
+		if from != "" || to != "" {
+			return time.Time{}, time.Time{}, fmt.Errorf("-week-of can't be combined with -from or -to")
+		}
+		day, err := time.Parse("2006-01-02", weekOf)
+		if err != nil {
+			return time.Time{}, time.Time{}, fmt.Errorf("invalid -week-of %q: expected e.g. 2026-01-19", weekOf)
+		}
+		start, end = CalendarWeek(day)
+	case from != "":
+		if start, err = time.Parse("2006-01-02", from); err != nil {
+			return time.Time{}, time.Time{}, fmt.Errorf("invalid -from %q: expected e.g. 2026-01-19", from)
+		}
+		end = today
+		if to != "" {
+			if end, err = time.Parse("2006-01-02", to); err != nil {
+				return time.Time{}, time.Time{}, fmt.Errorf("invalid -to %q: expected e.g. 2026-01-25", to)
+			}
+		}
+	default:
+		return time.Time{}, time.Time{}, fmt.Errorf("-to needs a -from date")
+	}
+
+	if end.Before(start) {
+		return time.Time{}, time.Time{}, fmt.Errorf("%s is before %s", end.Format("2006-01-02"), start.Format("2006-01-02"))
+	}
+	if start.After(today) {
+		return time.Time{}, time.Time{}, fmt.Errorf("%s is in the future", start.Format("2006-01-02"))
+	}
+	return start, end, nil
+}
+
+// CalendarWeek returns the Monday and Sunday of the week holding day
+func CalendarWeek(day time.Time) (monday, sunday time.Time) {
+	monday = time.Date(day.Year(), day.Month(), day.Day(), 0, 0, 0, 0, time.UTC)
+	monday = monday.AddDate(0, 0, -((int(monday.Weekday()) + 6) % 7))
+	return monday, monday.AddDate(0, 0, 6)
+}
+
+// LastWeeks returns the Mondays of the n Monday-to-Sunday weeks that have
+// finished by now, oldest first
+func LastWeeks(n int, now time.Time) []time.Time {
+	thisMonday, _ := CalendarWeek(now)
+	mondays := make([]time.Time, 0, max(n, 0))
+	for i := n; i >= 1; i-- {
+		mondays = append(mondays, thisMonday.AddDate(0, 0, -7*i))
+	}
+	return mondays
+}
@@ -0,0 +1,63 @@
+package processor
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseDateRange(t *testing.T) {
+	now := time.Date(2026, 2, 10, 9, 0, 0, 0, time.UTC)
+	cases := []struct {
+		from, to, weekOf string
+		start, end       string
+	}{
+		{"2026-01-01", "2026-01-31", "", "2026-01-01", "2026-01-31"},
+		{"2026-02-01", "", "", "2026-02-01", "2026-02-10"},
+		{"", "", "2026-01-21", "2026-01-19", "2026-01-25"}, // Wednesday
+		{"", "", "2026-01-25", "2026-01-19", "2026-01-25"}, // Sunday
+	}
+	for _, tc := range cases {
+		start, end, err := ParseDateRange(tc.from, tc.to, tc.weekOf, now)
+		if err != nil {
+			t.Errorf("%q %q %q: unexpected error: %v", tc.from, tc.to, tc.weekOf, err)
+			continue
+		}
+		if start.Format("2006-01-02") != tc.start || end.Format("2006-01-02") != tc.end {
+			t.Errorf("%q %q %q: got %s to %s, want %s to %s", tc.from, tc.to, tc.weekOf,
+				start.Format("2006-01-02"), end.Format("2006-01-02"), tc.start, tc.end)
+		}
+	}
+
+	for _, bad := range [][3]string{
+		{"", "2026-01-31", ""},
+		{"2026-01-31", "2026-01-01", ""},
+		{"2026-03-01", "", ""},
+		{"2026-01-01", "", "2026-01-21"},
+		{"Jan 1", "", ""},
+		{"", "", "last week"},
+	} {
+		if _, _, err := ParseDateRange(bad[0], bad[1], bad[2], now); err == nil {
+			t.Errorf("%q %q %q: expected an error", bad[0], bad[1], bad[2])
+		}
+	}
+}
+
+func TestLastWeeks(t *testing.T) {
+	for _, now := range []time.Time{
+		time.Date(2026, 2, 9, 8, 0, 0, 0, time.UTC),   // Monday
+		time.Date(2026, 2, 15, 22, 0, 0, 0, time.UTC), // Sunday, the week hasn't finished
+	} {
+		mondays := LastWeeks(3, now)
+		var got []string
+		for _, monday := range mondays {
+			got = append(got, monday.Format("2006-01-02"))
+		}
+		if want := "2026-01-19 2026-01-26 2026-02-02"; strings.Join(got, " ") != want {
+			t.Errorf("LastWeeks(3, %s): got %v, want %s", now.Format("Mon 2006-01-02"), got, want)
+		}
+	}
+	if weeks := LastWeeks(0, time.Now()); len(weeks) != 0 {
+		t.Errorf("LastWeeks(0): got %v, want none", weeks)
+	}
+}
@@ -0,0 +1,34 @@
+package processor
+
+import "time"
+
+// FiscalMonth returns the first and last day of the budget month containing t
+// when months start on startDay (1 = calendar months). startDay is expected to
+// be between 1 and 28 so every month has that day.
+func FiscalMonth(t time.Time, startDay int) (start, end time.Time) {
+	if startDay < 1 {
+		startDay = 1
+	}
+
+	day := truncateToDay(t)
+	start = time.Date(day.Year(), day.Month(), startDay, 0, 0, 0, 0, time.UTC)
+	if day.Day() < startDay {
+		start = start.AddDate(0, -1, 0)
+	}
+	end = start.AddDate(0, 1, -1)
+	return start, end
+}
+
+// fiscalDay returns the 1-based day of the budget month for t
+func fiscalDay(t time.Time, startDay int) int {
+	start, _ := FiscalMonth(t, startDay)
+	return int(truncateToDay(t).Sub(start).Hours()/24) + 1
+}
+
+// weeksLeftInMonth returns the number of weeks, rounded up, between t and the
+// end of its budget month
+func weeksLeftInMonth(t time.Time, startDay int) int {
+	_, end := FiscalMonth(t, startDay)
+	daysLeft := int(end.Sub(truncateToDay(t)).Hours() / 24)
+	return (daysLeft + 6) / 7
+}
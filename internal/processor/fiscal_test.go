@@ -0,0 +1,65 @@
+package processor
+
+import (
+	"testing"
+	"time"
+)
+
+func TestFiscalMonth(t *testing.T) {
+	tests := []struct {
+		name      string
+		date      time.Time
+		startDay  int
+		wantStart time.Time
+		wantEnd   time.Time
+	}{
+		{
+			name:      "calendar month",
+			date:      time.Date(2026, 2, 10, 15, 0, 0, 0, time.UTC),
+			startDay:  1,
+			wantStart: time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC),
+			wantEnd:   time.Date(2026, 2, 28, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:      "before start day falls in previous month",
+			date:      time.Date(2026, 2, 10, 0, 0, 0, 0, time.UTC),
+			startDay:  25,
+			wantStart: time.Date(2026, 1, 25, 0, 0, 0, 0, time.UTC),
+			wantEnd:   time.Date(2026, 2, 24, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:      "on start day",
+			date:      time.Date(2026, 12, 25, 0, 0, 0, 0, time.UTC),
+			startDay:  25,
+			wantStart: time.Date(2026, 12, 25, 0, 0, 0, 0, time.UTC),
+			wantEnd:   time.Date(2027, 1, 24, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:      "zero start day treated as calendar",
+			date:      time.Date(2026, 3, 31, 0, 0, 0, 0, time.UTC),
+			startDay:  0,
+			wantStart: time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC),
+			wantEnd:   time.Date(2026, 3, 31, 0, 0, 0, 0, time.UTC),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			start, end := FiscalMonth(tt.date, tt.startDay)
+			if !start.Equal(tt.wantStart) || !end.Equal(tt.wantEnd) {
+				t.Errorf("FiscalMonth: got %s to %s, want %s to %s",
+					start.Format("2006-01-02"), end.Format("2006-01-02"),
+					tt.wantStart.Format("2006-01-02"), tt.wantEnd.Format("2006-01-02"))
+			}
+		})
+	}
+}
+
+func TestFiscalDay(t *testing.T) {
+	if day := fiscalDay(time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC), 25); day != 8 {
+		t.Errorf("fiscalDay: got %d, want 8", day)
+	}
+	if day := fiscalDay(time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC), 1); day != 1 {
+		t.Errorf("fiscalDay: got %d, want 1", day)
+	}
+}
@@ -0,0 +1,71 @@
+package processor
+
+import (
+	"sort"
+
+	"github.com/sathyabhat/ynab-weekly-wrap/internal/ynab"
+)
+
+// WithFlags uses the flags on YNAB transactions: those flagged one of the
+// exclude colors, e.g. purple for purchases to be reimbursed, are left out
+// of the weekly spending, cash flow, needs attention and fiscal month-to-date
+// spending, and those flagged one of the highlight colors are listed in a
+// section of their own. The monthly wrap and calendar month pace go by
+// YNAB's category activity, which excluded transactions stay part of.
+func WithFlags(exclude, highlight []string) AnalyzerOption {
+	return func(a *Analyzer) {
+		a.excludeFlags = flagSet(exclude)
+		a.highlightFlags = flagSet(highlight)
+	}
+}
+
+func flagSet(colors []string) map[string]bool {
+	if len(colors) == 0 {
+		return nil
+	}
+	set := make(map[string]bool, len(colors))
+	for _, color := range colors {
+		set[color] = true
+	}
+	return set
+}
+
+// excluded reports whether the transaction is flagged to be left out of the
+// spending
+func (a *Analyzer) excluded(tx ynab.Transaction) bool {
+	return tx.FlagColor != "" && a.excludeFlags[tx.FlagColor]
+}
+
+// WithoutExcluded returns the transactions not flagged an excluded color.
+// Nil stays nil, for transactions that weren't fetched.
+func (a *Analyzer) WithoutExcluded(transactions []ynab.Transaction) []ynab.Transaction {
+	if len(a.excludeFlags) == 0 || transactions == nil {
+		return transactions
+	}
+	kept := make([]ynab.Transaction, 0, len(transactions))
+	for _, tx := range transactions {
+		if !a.excluded(tx) {
+			kept = append(kept, tx)
+		}
+	}
+	return kept
+}
+
+// flaggedTransactions returns the period's transactions flagged one of the
+// highlight colors, oldest first. They are listed even when their color is
+// also excluded from the spending.
+func (a *Analyzer) flaggedTransactions(transactions []ynab.Transaction) []ynab.Transaction {
+	if len(a.highlightFlags) == 0 {
+		return nil
+	}
+	var flagged []ynab.Transaction
+	for _, tx := range transactions {
+		if !tx.Deleted && tx.FlagColor != "" && a.highlightFlags[tx.FlagColor] {
+			flagged = append(flagged, tx)
+		}
+	}
+	sort.SliceStable(flagged, func(i, j int) bool {
+		return flagged[i].Date != nil && flagged[j].Date != nil && flagged[i].Date.Before(*flagged[j].Date)
+	})
+	return flagged
+}
@@ -0,0 +1,90 @@
+package processor
+
+import (
+	"testing"
+
+	"github.com/sathyabhat/ynab-weekly-wrap/internal/ynab"
+)
+
+func TestAnalyzeWeeklyData_ExcludeFlags(t *testing.T) {
+	data := baseWeeklyData()
+	data.Transactions[2].FlagColor = "purple" // Dining, to be reimbursed
+
+	a := NewAnalyzer(WithFlags([]string{"purple"}, nil))
+	result, err := a.AnalyzeWeeklyData(data, nil, 0)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Overview.TotalSpent != 350_000 {
+		t.Errorf("TotalSpent: got %d, want 350000 without the purple-flagged Dining", result.Overview.TotalSpent)
+	}
+	if result.Flagged != nil {
+		t.Errorf("expected nothing listed without highlight colors, got %+v", result.Flagged)
+	}
+	if result.CashFlow.Spending != 350_000 {
+		t.Errorf("CashFlow.Spending: got %d, want 350000 without the purple-flagged Dining", result.CashFlow.Spending)
+	}
+}
+
+func TestAnalyzeWeeklyData_ExcludeFlagsLeavesOutAttention(t *testing.T) {
+	data := baseWeeklyData()
+	data.Transactions = append(data.Transactions, ynab.Transaction{ID: "t4", Amount: -40_000, FlagColor: "purple"})
+
+	a := NewAnalyzer(WithFlags([]string{"purple"}, nil))
+	result, err := a.AnalyzeWeeklyData(data, nil, 0)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Attention != nil {
+		t.Errorf("expected the purple-flagged uncategorized transaction left out, got %+v", result.Attention)
+	}
+}
+
+// The monthly wrap goes by YNAB's category activity, so excluded flags only
+// apply to the weekly wrap
+func TestAnalyzeMonthlyData_ExcludeFlagsWeeklyOnly(t *testing.T) {
+	data := baseMonthlyData()
+	data.Transactions = nil
+	for i := range data.Categories {
+		data.Categories[i].Activity = -100_000
+	}
+
+	a := NewAnalyzer(WithFlags([]string{"purple"}, nil))
+	result, err := a.AnalyzeMonthlyData(data, nil, 0)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Overview.TotalSpent != 300_000 {
+		t.Errorf("TotalSpent: got %d, want YNAB's activity of 300000", result.Overview.TotalSpent)
+	}
+}
+
+func TestAnalyzeWeeklyData_HighlightFlags(t *testing.T) {
+	data := baseWeeklyData()
+	data.Transactions[0].FlagColor = "red"
+	data.Transactions[1].FlagColor = "blue"
+	data.Transactions[2].FlagColor = "red"
+	data.Transactions[2].Deleted = true
+
+	a := NewAnalyzer(WithFlags(nil, []string{"red", "green"}))
+	result, err := a.AnalyzeWeeklyData(data, nil, 0)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(result.Flagged) != 1 || result.Flagged[0].ID != "t1" {
+		t.Errorf("Flagged: got %+v, want only t1", result.Flagged)
+	}
+	if result.Overview.TotalSpent != 350_000 {
+		t.Errorf("TotalSpent: got %d, want highlighted spending still counted, less the deleted Dining", result.Overview.TotalSpent)
+	}
+}
+
+func TestMonthToDateSpent_ExcludeFlags(t *testing.T) {
+	a := NewAnalyzer(WithFiscalMonthStart(15), WithFlags([]string{"purple"}, nil))
+	flagged := makeTx("t2", makeDate(2026, 1, 17), -60_000, "Dining")
+	flagged.FlagColor = "purple"
+	spent := a.monthToDateSpent([]ynab.Transaction{makeTx("t1", makeDate(2026, 1, 16), -40_000, "Dining"), flagged})
+	if got := spent(ynab.Category{Name: "Dining"}); got != 40_000 {
+		t.Errorf("got %d, want 40000 without the purple-flagged transaction", got)
+	}
+}
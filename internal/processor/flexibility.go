@@ -0,0 +1,100 @@
+package processor
+
+import (
+	"sort"
+
+	"github.com/sathyabhat/ynab-weekly-wrap/internal/ynab"
+)
+
+// internalGroups are YNAB's own category groups, which hold money that isn't
+// available for day-to-day spending
+var internalGroups = map[string]bool{
+	"Internal Master Category": true,
+	"Credit Card Payments":     true,
+}
+
+// EnvelopeBalance is what is left in one category for the rest of the month
+type EnvelopeBalance struct {
+	Category string `json:"category"`
+	Balance  int64  `json:"balance"`
+}
+
+// Envelopes groups remaining balances by flexibility. Flexible categories
+// are listed one by one since that is where spending can still change; fixed
+// costs (and lumpy categories) are only totalled.
+type Envelopes struct {
+	Flexible      []EnvelopeBalance `json:"flexible"`
+	FlexibleTotal int64             `json:"flexible_total"`
+	FixedTotal    int64             `json:"fixed_total"`
+}
+
+// WithFixedCategories marks categories or category groups holding fixed
+// costs (rent, utilities). Everything else that isn't lumpy is flexible.
+func WithFixedCategories(names []string) AnalyzerOption {
+	return func(a *Analyzer) {
+		a.fixed = make(map[string]bool, len(names))
+		for _, name := range names {
+			a.fixed[name] = true
+		}
+	}
+}
+
+// WithFixedGoalTypes treats categories whose YNAB goal is one of the given
+// types (e.g. MF for a fixed monthly amount, NEED for bills) as fixed costs
+func WithFixedGoalTypes(goalTypes []string) AnalyzerOption {
+	return func(a *Analyzer) {
+		a.fixedGoals = make(map[string]bool, len(goalTypes))
+		for _, goalType := range goalTypes {
+			a.fixedGoals[goalType] = true
+		}
+	}
+}
+
+// isFixed reports whether the category, its group or its goal type is
+// configured as a fixed cost
+func (a *Analyzer) isFixed(cat ynab.Category) bool {
+	if a.fixed[cat.Name] || (cat.CategoryGroup.Name != "" && a.fixed[cat.CategoryGroup.Name]) {
+		return true
+	}
+	return cat.GoalType != "" && a.fixedGoals[cat.GoalType]
+}
+
+// isSpendable reports whether the category is a regular budget category,
+// rather than one of YNAB's internal or hidden ones
+func isSpendable(cat ynab.Category) bool {
+	group := cat.CategoryGroup
+	return !group.Hidden && !group.Deleted && !internalGroups[group.Name]
+}
+
+// isFlexible reports whether the category holds day-to-day spending money
+func (a *Analyzer) isFlexible(cat ynab.Category) bool {
+	return isSpendable(cat) && !a.isFixed(cat) && !a.isLumpy(cat)
+}
+
+// calculateEnvelopes groups the month's remaining balances into flexible and
+// fixed. It returns nil until some category is classed as fixed, since the
+// split adds nothing when every category is flexible.
+func (a *Analyzer) calculateEnvelopes(categories []ynab.Category) *Envelopes {
+	envelopes := &Envelopes{}
+	var hasFixed bool
+	for _, cat := range categories {
+		if !isSpendable(cat) || (cat.Budgeted == 0 && cat.Balance == 0) {
+			continue
+		}
+		if !a.isFlexible(cat) {
+			hasFixed = hasFixed || a.isFixed(cat)
+			envelopes.FixedTotal += cat.Balance
+			continue
+		}
+		envelopes.Flexible = append(envelopes.Flexible, EnvelopeBalance{Category: cat.Name, Balance: cat.Balance})
+		envelopes.FlexibleTotal += cat.Balance
+	}
+	if !hasFixed {
+		return nil
+	}
+
+	sort.SliceStable(envelopes.Flexible, func(i, j int) bool {
+		return envelopes.Flexible[i].Balance > envelopes.Flexible[j].Balance
+	})
+	return envelopes
+}
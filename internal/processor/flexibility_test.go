@@ -0,0 +1,54 @@
+package processor
+
+import (
+	"testing"
+
+	"github.com/sathyabhat/ynab-weekly-wrap/internal/ynab"
+)
+
+func TestCalculateEnvelopes(t *testing.T) {
+	a := NewAnalyzer(WithFixedCategories([]string{"Bills"}), WithLumpyCategories([]string{"Car Insurance"}))
+
+	envelopes := a.calculateEnvelopes(safeToSpendCategories())
+	if envelopes == nil {
+		t.Fatal("expected envelopes")
+	}
+
+	want := []EnvelopeBalance{{"Groceries", 400_000}, {"Fun", 200_000}, {"Dining Out", -50_000}}
+	if len(envelopes.Flexible) != len(want) {
+		t.Fatalf("flexible: got %+v, want %+v", envelopes.Flexible, want)
+	}
+	for i := range want {
+		if envelopes.Flexible[i] != want[i] {
+			t.Errorf("flexible[%d]: got %+v, want %+v", i, envelopes.Flexible[i], want[i])
+		}
+	}
+	if envelopes.FlexibleTotal != 550_000 {
+		t.Errorf("flexible total: got %d, want 550000", envelopes.FlexibleTotal)
+	}
+	// Rent and the lumpy insurance; Ready to Assign is left out
+	if envelopes.FixedTotal != 2_100_000 {
+		t.Errorf("fixed total: got %d, want 2100000", envelopes.FixedTotal)
+	}
+}
+
+func TestCalculateEnvelopes_FixedGoalType(t *testing.T) {
+	a := NewAnalyzer(WithFixedGoalTypes([]string{"MF"}))
+	internet := makeCategory("net", "Internet", 80_000, 80_000)
+	internet.GoalType = "MF"
+	categories := []ynab.Category{makeCategory("groc", "Groceries", 600_000, 400_000), internet}
+
+	envelopes := a.calculateEnvelopes(categories)
+	if envelopes == nil || len(envelopes.Flexible) != 1 || envelopes.FixedTotal != 80_000 {
+		t.Errorf("expected Internet to be fixed by its goal type, got %+v", envelopes)
+	}
+}
+
+func TestCalculateEnvelopes_NothingFixed(t *testing.T) {
+	a := NewAnalyzer()
+	categories := []ynab.Category{makeCategory("groc", "Groceries", 600_000, 400_000)}
+
+	if envelopes := a.calculateEnvelopes(categories); envelopes != nil {
+		t.Errorf("expected nil without fixed categories, got %+v", envelopes)
+	}
+}
@@ -0,0 +1,36 @@
+package processor
+
+import "github.com/sathyabhat/ynab-weekly-wrap/internal/ynab"
+
+// WithFocusCategories restricts the wraps to the named categories or
+// category groups, e.g. the ones a household is working on, leaving the
+// rest of the budget out of the figures. No names means every category.
+func WithFocusCategories(names []string) AnalyzerOption {
+	return func(a *Analyzer) {
+		a.focusNames = names
+		a.focus = make(map[string]bool, len(names))
+		for _, name := range names {
+			a.focus[name] = true
+		}
+	}
+}
+
+// inFocus reports whether the category or its group is one the wraps are
+// restricted to, or true when they aren't restricted
+func (a *Analyzer) inFocus(cat ynab.Category) bool {
+	return len(a.focus) == 0 || a.focus[cat.Name] || (cat.CategoryGroup.Name != "" && a.focus[cat.CategoryGroup.Name])
+}
+
+// focusCategories returns the categories in focus
+func (a *Analyzer) focusCategories(categories []ynab.Category) []ynab.Category {
+	if len(a.focus) == 0 {
+		return categories
+	}
+	var focused []ynab.Category
+	for _, cat := range categories {
+		if a.inFocus(cat) {
+			focused = append(focused, cat)
+		}
+	}
+	return focused
+}
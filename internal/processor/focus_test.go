@@ -0,0 +1,41 @@
+package processor
+
+import (
+	"testing"
+
+	"github.com/sathyabhat/ynab-weekly-wrap/internal/ynab"
+)
+
+func TestAnalyzeWeeklyData_FocusCategories(t *testing.T) {
+	data := baseWeeklyData()
+	data.Categories[1].CategoryGroup = ynab.CategoryGroup{Name: "Getting Around"}
+
+	a := NewAnalyzer(WithFocusCategories([]string{"Groceries", "Getting Around"}))
+	result, err := a.AnalyzeWeeklyData(data, nil, 0)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if result.Overview.TotalSpent != 350_000 {
+		t.Errorf("TotalSpent: got %d, want 350000 from Groceries and Transport", result.Overview.TotalSpent)
+	}
+	if len(result.TopSpending) != 2 {
+		t.Errorf("TopSpending: got %+v, want Groceries and Transport", result.TopSpending)
+	}
+	if len(result.Concerns) != 0 {
+		t.Errorf("expected Dining, outside the focus, not to be a concern, got %+v", result.Concerns)
+	}
+	if len(result.Focus) != 2 {
+		t.Errorf("Focus: got %v, want the configured names", result.Focus)
+	}
+}
+
+func TestAnalyzeWeeklyData_NoFocusCategories(t *testing.T) {
+	result, err := NewAnalyzer(WithFocusCategories(nil)).AnalyzeWeeklyData(baseWeeklyData(), nil, 0)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Overview.TotalSpent != 700_000 || result.Focus != nil {
+		t.Errorf("expected every category without focus categories, got %d spent, focus %v", result.Overview.TotalSpent, result.Focus)
+	}
+}
@@ -0,0 +1,149 @@
+package processor
+
+import (
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/sathyabhat/ynab-weekly-wrap/internal/ynab"
+)
+
+// FraudPattern names a pattern of charges that card fraud commonly leaves
+type FraudPattern string
+
+const (
+	// FraudMicroCharges is several tiny charges from new payees, typical of
+	// a stolen card being tested before it is used
+	FraudMicroCharges FraudPattern = "micro_charges"
+	// FraudRepeatedAmount is the same odd amount charged by several new
+	// payees
+	FraudRepeatedAmount FraudPattern = "repeated_amount"
+	// FraudForeignBurst is a run of new payees whose names look foreign
+	FraudForeignBurst FraudPattern = "foreign_burst"
+)
+
+// How many suspicious charges it takes to raise each pattern
+const (
+	minMicroCharges     = 2
+	minRepeatedPayees   = 2
+	minForeignBurstSize = 3
+)
+
+// foreignMarkers are words card networks and banks add to payee names for
+// charges made abroad
+var foreignMarkers = []string{"intl", "international", "foreign", "fx", "overseas", "cross border"}
+
+// FraudSignal is one suspicious pattern found in recent transactions
+type FraudSignal struct {
+	Pattern      FraudPattern       `json:"pattern"`
+	Amount       int64              `json:"amount,omitempty"` // The shared outflow, for FraudRepeatedAmount
+	Transactions []ynab.Transaction `json:"transactions"`
+}
+
+// DetectFraud looks for classic card fraud patterns among outflows in the
+// last windowDays days to payees that don't appear earlier in transactions:
+// repeated charges of microCharge or less, the same odd amount from several
+// payees, and a burst of foreign-looking payees. transactions should reach
+// back well before the window, since the earlier ones are what tell known
+// payees from new ones.
+func (a *Analyzer) DetectFraud(transactions []ynab.Transaction, now time.Time, windowDays int, microCharge int64) []FraudSignal {
+	if windowDays <= 0 {
+		return nil
+	}
+	windowStart := truncateToDay(now).AddDate(0, 0, -windowDays+1)
+
+	known := make(map[string]bool)
+	var recent []ynab.Transaction
+	for _, tx := range transactions {
+		if tx.Deleted || tx.Date == nil {
+			continue
+		}
+		if tx.Date.Before(windowStart) {
+			known[PayeeKey(tx.PayeeName)] = true
+		} else if tx.Amount < 0 && !strings.HasPrefix(tx.PayeeName, transferPayeePrefix) {
+			recent = append(recent, tx)
+		}
+	}
+
+	var unknown []ynab.Transaction
+	for _, tx := range recent {
+		if !known[PayeeKey(tx.PayeeName)] {
+			unknown = append(unknown, tx)
+		}
+	}
+	sort.SliceStable(unknown, func(i, j int) bool {
+		return unknown[i].Date.Before(*unknown[j].Date)
+	})
+
+	var signals []FraudSignal
+
+	var micro []ynab.Transaction
+	for _, tx := range unknown {
+		if -tx.Amount <= microCharge {
+			micro = append(micro, tx)
+		}
+	}
+	if microCharge > 0 && len(micro) >= minMicroCharges {
+		signals = append(signals, FraudSignal{Pattern: FraudMicroCharges, Transactions: micro})
+	}
+
+	byAmount := make(map[int64][]ynab.Transaction)
+	for _, tx := range unknown {
+		if tx.Amount%1000 != 0 && -tx.Amount > microCharge {
+			byAmount[-tx.Amount] = append(byAmount[-tx.Amount], tx)
+		}
+	}
+	var amounts []int64
+	for amount, txs := range byAmount {
+		if distinctPayees(txs) >= minRepeatedPayees {
+			amounts = append(amounts, amount)
+		}
+	}
+	sort.Slice(amounts, func(i, j int) bool { return amounts[i] > amounts[j] })
+	for _, amount := range amounts {
+		signals = append(signals, FraudSignal{Pattern: FraudRepeatedAmount, Amount: amount, Transactions: byAmount[amount]})
+	}
+
+	var foreign []ynab.Transaction
+	for _, tx := range unknown {
+		if looksForeign(tx.PayeeName) {
+			foreign = append(foreign, tx)
+		}
+	}
+	if distinctPayees(foreign) >= minForeignBurstSize {
+		signals = append(signals, FraudSignal{Pattern: FraudForeignBurst, Transactions: foreign})
+	}
+
+	return signals
+}
+
+// distinctPayees counts the different payees among transactions
+func distinctPayees(transactions []ynab.Transaction) int {
+	payees := make(map[string]bool)
+	for _, tx := range transactions {
+		payees[PayeeKey(tx.PayeeName)] = true
+	}
+	return len(payees)
+}
+
+// looksForeign reports whether a payee name is written in a non-Latin script
+// or carries a marker banks add to charges made abroad
+func looksForeign(payee string) bool {
+	for _, r := range payee {
+		if unicode.IsLetter(r) && !unicode.Is(unicode.Latin, r) {
+			return true
+		}
+	}
+
+	words := strings.FieldsFunc(strings.ToLower(payee), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	name := " " + strings.Join(words, " ") + " "
+	for _, marker := range foreignMarkers {
+		if strings.Contains(name, " "+marker+" ") {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,90 @@
+package processor
+
+import (
+	"testing"
+	"time"
+
+	"github.com/sathyabhat/ynab-weekly-wrap/internal/ynab"
+)
+
+func payeeTx(payee string, day int, amount int64) ynab.Transaction {
+	tx := makeTx(payee, makeDate(2026, 3, day), amount, "Shopping")
+	tx.PayeeName = payee
+	return tx
+}
+
+func TestDetectFraud(t *testing.T) {
+	now := time.Date(2026, 3, 20, 8, 0, 0, 0, time.UTC)
+	transactions := []ynab.Transaction{
+		// Known payees from before the window
+		payeeTx("Parking Meter", 2, -1_000),
+		payeeTx("Countdown", 5, -84_230),
+
+		// Window: Mar 18-20
+		payeeTx("Parking Meter", 18, -1_000),
+		payeeTx("Countdown", 19, -84_230),
+		payeeTx("VERIFY*CARD", 18, -1_000),
+		payeeTx("Acme Digital", 19, -500),
+		payeeTx("Shop One", 19, -37_420),
+		payeeTx("Shop Two", 20, -37_420),
+		payeeTx("Shop Three", 20, -50_000),
+		payeeTx("淘宝网", 19, -12_000),
+		payeeTx("Bazaar INTL", 19, -8_000),
+		payeeTx("FX Payments Ltd", 20, -9_000),
+		payeeTx("Refund Co", 20, 37_420),
+		payeeTx("Transfer : Savings", 20, -37_420),
+	}
+
+	signals := NewAnalyzer().DetectFraud(transactions, now, 3, 1_000)
+	if len(signals) != 3 {
+		t.Fatalf("expected 3 signals, got %+v", signals)
+	}
+
+	micro := signals[0]
+	if micro.Pattern != FraudMicroCharges || len(micro.Transactions) != 2 ||
+		micro.Transactions[0].PayeeName != "VERIFY*CARD" || micro.Transactions[1].PayeeName != "Acme Digital" {
+		t.Errorf("expected micro-charges from the two new payees only, got %+v", micro)
+	}
+
+	repeated := signals[1]
+	if repeated.Pattern != FraudRepeatedAmount || repeated.Amount != 37_420 || len(repeated.Transactions) != 2 {
+		t.Errorf("expected $37.42 from two new payees, got %+v", repeated)
+	}
+
+	foreign := signals[2]
+	if foreign.Pattern != FraudForeignBurst || len(foreign.Transactions) != 3 {
+		t.Errorf("expected three foreign-looking payees, got %+v", foreign)
+	}
+}
+
+func TestDetectFraud_NothingSuspicious(t *testing.T) {
+	now := time.Date(2026, 3, 20, 8, 0, 0, 0, time.UTC)
+	transactions := []ynab.Transaction{
+		payeeTx("Countdown", 5, -84_230),
+		payeeTx("Countdown", 19, -91_100),
+		payeeTx("New Cafe", 19, -6_500),
+		payeeTx("Shop One", 19, -40_000),
+		payeeTx("Shop Two", 20, -40_000), // Same amount, but whole dollars
+	}
+
+	if signals := NewAnalyzer().DetectFraud(transactions, now, 3, 1_000); len(signals) != 0 {
+		t.Errorf("expected no signals, got %+v", signals)
+	}
+}
+
+func TestLooksForeign(t *testing.T) {
+	cases := map[string]bool{
+		"淘宝网":              true,
+		"Яндекс Такси":     true,
+		"Bazaar INTL":      true,
+		"FX Payments Ltd":  true,
+		"Café Crème":       false,
+		"Fixings Hardware": false,
+		"Countdown":        false,
+	}
+	for payee, want := range cases {
+		if got := looksForeign(payee); got != want {
+			t.Errorf("looksForeign(%q): got %v, want %v", payee, got, want)
+		}
+	}
+}
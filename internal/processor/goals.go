@@ -0,0 +1,58 @@
+package processor
+
+import (
+	"sort"
+	"time"
+
+	"github.com/sathyabhat/ynab-weekly-wrap/internal/ynab"
+)
+
+// GoalProgress is how far a category is towards its YNAB goal
+type GoalProgress struct {
+	Category  string     `json:"category"`
+	Type      string     `json:"type"`   // YNAB goal type, e.g. TB or NEED
+	Target    int64      `json:"target"` // Goal amount
+	Percent   int        `json:"percent"`
+	Remaining int64      `json:"remaining"`     // Still to fund, worked out from Percent
+	Due       *time.Time `json:"due,omitempty"` // Month the goal is due, for goals with a target date
+}
+
+// Complete reports whether the goal is fully funded
+func (g GoalProgress) Complete() bool {
+	return g.Percent >= 100
+}
+
+// TrackGoals lists the visible categories with a goal, least funded first.
+// When only is given, just those categories are listed. The API doesn't
+// report how much a goal is underfunded, so what remains is worked out from
+// the percentage funded.
+func TrackGoals(categories []ynab.Category, only []string) []GoalProgress {
+	wanted := make(map[string]bool, len(only))
+	for _, name := range only {
+		wanted[name] = true
+	}
+
+	var goals []GoalProgress
+	for _, cat := range categories {
+		if cat.Hidden || !isSpendable(cat) || cat.GoalType == "" || cat.GoalTarget <= 0 {
+			continue
+		}
+		if len(wanted) > 0 && !wanted[cat.Name] && !wanted[cat.CategoryGroup.Name] {
+			continue
+		}
+		percent := min(max(cat.GoalPercent, 0), 100)
+		goals = append(goals, GoalProgress{
+			Category:  cat.Name,
+			Type:      cat.GoalType,
+			Target:    cat.GoalTarget,
+			Percent:   percent,
+			Remaining: cat.GoalTarget * int64(100-percent) / 100,
+			Due:       cat.GoalMonth,
+		})
+	}
+	sort.SliceStable(goals, func(i, j int) bool {
+		a, b := goals[i], goals[j]
+		return a.Percent < b.Percent || (a.Percent == b.Percent && a.Category < b.Category)
+	})
+	return goals
+}
@@ -0,0 +1,45 @@
+package processor
+
+import (
+	"testing"
+	"time"
+
+	"github.com/sathyabhat/ynab-weekly-wrap/internal/ynab"
+)
+
+func TestTrackGoals(t *testing.T) {
+	due := time.Date(2026, 12, 1, 0, 0, 0, 0, time.UTC)
+	vacation := makeCategory("c1", "Vacation", 200_000, 1_860_000)
+	vacation.GoalType, vacation.GoalTarget, vacation.GoalPercent, vacation.GoalMonth = "TBD", 3_000_000, 62, &due
+	emergency := makeCategory("c2", "Emergency Fund", 0, 1_000_000)
+	emergency.GoalType, emergency.GoalTarget, emergency.GoalPercent = "TB", 5_000_000, 20
+	rent := makeCategory("c3", "Rent", 2_000_000, 2_000_000)
+	rent.GoalType, rent.GoalTarget, rent.GoalPercent = "NEED", 2_000_000, 100
+	hidden := makeCategory("c4", "Old Goal", 0, 0)
+	hidden.GoalType, hidden.GoalTarget, hidden.Hidden = "TB", 1_000_000, true
+	internal := makeCategory("c5", "Inflow: Ready to Assign", 0, 0)
+	internal.GoalType, internal.GoalTarget = "TB", 1_000_000
+	internal.CategoryGroup.Name = "Internal Master Category"
+	categories := []ynab.Category{
+		makeCategory("c0", "Groceries", 500_000, 300_000), vacation, emergency, rent, hidden, internal,
+	}
+
+	goals := TrackGoals(categories, nil)
+	if len(goals) != 3 {
+		t.Fatalf("expected three goals, got %+v", goals)
+	}
+	if goals[0].Category != "Emergency Fund" || goals[1].Category != "Vacation" || goals[2].Category != "Rent" {
+		t.Errorf("expected the least funded goal first, got %+v", goals)
+	}
+	if goals[1].Remaining != 1_140_000 || goals[1].Due == nil || !goals[1].Due.Equal(due) {
+		t.Errorf("expected $1,140 to go by December, got %+v", goals[1])
+	}
+	if !goals[2].Complete() || goals[2].Remaining != 0 {
+		t.Errorf("expected rent fully funded, got %+v", goals[2])
+	}
+
+	goals = TrackGoals(categories, []string{"Vacation"})
+	if len(goals) != 1 || goals[0].Category != "Vacation" {
+		t.Errorf("expected only the vacation goal, got %+v", goals)
+	}
+}
@@ -0,0 +1,111 @@
+package processor
+
+import (
+	"time"
+
+	"github.com/sathyabhat/ynab-weekly-wrap/internal/ynab"
+)
+
+const (
+	// groceryWindowDays is how many days of trips make up a rolling average
+	groceryWindowDays = 28
+	// minGroceryTrips is how many trips each window needs for the trend
+	minGroceryTrips = 3
+)
+
+// GroceryTrend tracks the average grocery trip, so a rising bill can be
+// told apart from shopping more often: prices drifting up show as a larger
+// average trip rather than more trips
+type GroceryTrend struct {
+	Category     string `json:"category"`
+	WeekTrips    int    `json:"week_trips"`
+	WeekAverage  int64  `json:"week_average"` // Average trip this week
+	Recent       int64  `json:"recent"`       // Average trip over the last four weeks; zero when too few trips
+	Earlier      int64  `json:"earlier"`      // Average trip over the same four weeks, Months months earlier; zero when too few trips
+	Months       int    `json:"months"`
+	RecentTrips  int    `json:"recent_trips"`
+	EarlierTrips int    `json:"earlier_trips"`
+}
+
+// HasTrend reports whether both windows had enough trips to compare
+func (g *GroceryTrend) HasTrend() bool {
+	return g.Recent > 0 && g.Earlier > 0
+}
+
+// Percent returns how much the average trip changed from Earlier to Recent
+func (g *GroceryTrend) Percent() float64 {
+	if g.Earlier == 0 {
+		return 0
+	}
+	return float64(g.Recent-g.Earlier) / float64(g.Earlier) * 100
+}
+
+// GroceryWindow returns the first day of transactions TrackGroceries needs
+// for a trend over months ending at end
+func GroceryWindow(end time.Time, months int) time.Time {
+	_, earlierStart, _ := groceryWindows(end, months)
+	return earlierStart
+}
+
+// groceryWindows returns the first day of the four weeks ending at end, and
+// the first day and the day after the last day of the same four weeks the
+// given number of months earlier
+func groceryWindows(end time.Time, months int) (recentStart, earlierStart, earlierEnd time.Time) {
+	recentStart = truncateToDay(end).AddDate(0, 0, -groceryWindowDays+1)
+	earlierEnd = recentStart.AddDate(0, -months, 0)
+	return recentStart, earlierEnd.AddDate(0, 0, -groceryWindowDays), earlierEnd
+}
+
+// GroceryTrips returns the outflows in the named category, matched ignoring
+// case, each of which counts as a trip
+func GroceryTrips(transactions []ynab.Transaction, category string) []ynab.Transaction {
+	var trips []ynab.Transaction
+	for _, tx := range transactions {
+		if !tx.Deleted && tx.Amount < 0 && tx.Date != nil && PayeeKey(tx.CategoryName) == PayeeKey(category) {
+			trips = append(trips, tx)
+		}
+	}
+	return trips
+}
+
+// TrackGroceries works out the average trip, one outflow each, in the named
+// category this week (from weekStart through end), over the four weeks to
+// end, and over the four weeks the given number of months before that.
+// Returns nil when the category saw no trips at all.
+func TrackGroceries(transactions []ynab.Transaction, category string, weekStart, end time.Time, months int) *GroceryTrend {
+	recentStart, earlierStart, earlierEnd := groceryWindows(end, months)
+	weekStart = truncateToDay(weekStart)
+
+	trend := &GroceryTrend{Category: category, Months: months}
+	var week, recent, earlier int64
+	for _, tx := range GroceryTrips(transactions, category) {
+		date := truncateToDay(*tx.Date)
+		if date.After(end) {
+			continue
+		}
+		if !date.Before(weekStart) {
+			trend.WeekTrips++
+			week += -tx.Amount
+		}
+		switch {
+		case !date.Before(recentStart):
+			trend.RecentTrips++
+			recent += -tx.Amount
+		case !date.Before(earlierStart) && date.Before(earlierEnd):
+			trend.EarlierTrips++
+			earlier += -tx.Amount
+		}
+	}
+	if trend.WeekTrips == 0 && trend.RecentTrips == 0 && trend.EarlierTrips == 0 {
+		return nil
+	}
+
+	if trend.WeekTrips > 0 {
+		trend.WeekAverage = week / int64(trend.WeekTrips)
+	}
+	if trend.RecentTrips >= minGroceryTrips && trend.EarlierTrips >= minGroceryTrips {
+		trend.Recent = recent / int64(trend.RecentTrips)
+		trend.Earlier = earlier / int64(trend.EarlierTrips)
+	}
+	return trend
+}
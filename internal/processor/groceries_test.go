@@ -0,0 +1,59 @@
+package processor
+
+import (
+	"testing"
+	"time"
+
+	"github.com/sathyabhat/ynab-weekly-wrap/internal/ynab"
+)
+
+func TestTrackGroceries(t *testing.T) {
+	weekStart := time.Date(2026, 7, 20, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2026, 7, 26, 23, 59, 59, 0, time.UTC)
+
+	var transactions []ynab.Transaction
+	// Six months before: four trips averaging $75
+	for i, amount := range []int64{70_000, 75_000, 75_000, 80_000} {
+		transactions = append(transactions, makeTx("e", makeDate(2025, 12, 5+i*5), -amount, "Groceries"))
+	}
+	// The last four weeks: four trips averaging about $82, two this week
+	for i, day := range []int{2, 10, 21, 25} {
+		amount := []int64{80_000, 82_000, 80_000, 85_000}[i]
+		transactions = append(transactions, makeTx("r", makeDate(2026, 7, day), -amount, "Groceries"))
+	}
+	transactions = append(transactions,
+		makeTx("x", makeDate(2026, 7, 22), -300_000, "Dining"),    // Another category
+		makeTx("y", makeDate(2026, 7, 22), 20_000, "Groceries"),   // Refund
+		makeTx("z", makeDate(2026, 4, 10), -500_000, "Groceries"), // Between the windows
+	)
+
+	trend := TrackGroceries(transactions, "groceries", weekStart, end, 6)
+	if trend == nil {
+		t.Fatal("expected a trend")
+	}
+	if trend.WeekTrips != 2 || trend.WeekAverage != 82_500 {
+		t.Errorf("week: got %d trips averaging %d, want 2 averaging 82,500", trend.WeekTrips, trend.WeekAverage)
+	}
+	if !trend.HasTrend() || trend.Recent != 81_750 || trend.Earlier != 75_000 {
+		t.Errorf("trend: got %+v, want 81,750 against 75,000", trend)
+	}
+	if percent := trend.Percent(); percent < 8.9 || percent > 9.1 {
+		t.Errorf("Percent: got %.2f, want 9", percent)
+	}
+	if start := GroceryWindow(end, 6); !start.Equal(time.Date(2025, 12, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("GroceryWindow: got %s, want 2025-12-01", start)
+	}
+}
+
+func TestTrackGroceries_TooFewTrips(t *testing.T) {
+	end := time.Date(2026, 7, 26, 23, 59, 59, 0, time.UTC)
+	transactions := []ynab.Transaction{makeTx("t1", makeDate(2026, 7, 22), -90_000, "Groceries")}
+
+	trend := TrackGroceries(transactions, "Groceries", end.AddDate(0, 0, -6), end, 6)
+	if trend == nil || trend.WeekTrips != 1 || trend.HasTrend() {
+		t.Errorf("expected this week's trip without a trend, got %+v", trend)
+	}
+	if trend := TrackGroceries(nil, "Groceries", end.AddDate(0, 0, -6), end, 6); trend != nil {
+		t.Errorf("expected nil without trips, got %+v", trend)
+	}
+}
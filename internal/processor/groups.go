@@ -0,0 +1,70 @@
+package processor
+
+import (
+	"sort"
+
+	"github.com/sathyabhat/ynab-weekly-wrap/internal/ynab"
+)
+
+// groupTopCategories is how many categories each account group lists
+const groupTopCategories = 3
+
+// AccountGroup is the spending from one group of accounts, such as a joint
+// account and each partner's own, so everyone sees their own picture
+type AccountGroup struct {
+	Group         string           `json:"group"` // Empty for accounts in no group
+	Spent         int64            `json:"spent"`
+	Transactions  int              `json:"transactions"`
+	TopCategories []GroupSpendLine `json:"top_categories"` // Largest first, at most three
+}
+
+// GroupSpendLine is a category's spending within an account group
+type GroupSpendLine struct {
+	Category string `json:"category"`
+	Spent    int64  `json:"spent"`
+}
+
+// GroupSpending totals the outflows from each group of accounts, given as
+// account name -> group name. Spending from accounts in no group is
+// gathered in a group with no name, listed last; transfers are left out.
+// Other groups are listed by spending, largest first.
+func GroupSpending(transactions []ynab.Transaction, groups map[string]string) []AccountGroup {
+	byGroup := make(map[string]*AccountGroup)
+	byCategory := make(map[string]map[string]int64)
+	for _, tx := range transactions {
+		if tx.Deleted || tx.Amount >= 0 || tx.CategoryID == nil || tx.TransferAccountID != nil {
+			continue
+		}
+		name := groups[tx.AccountName]
+		group, ok := byGroup[name]
+		if !ok {
+			group = &AccountGroup{Group: name}
+			byGroup[name] = group
+			byCategory[name] = make(map[string]int64)
+		}
+		group.Spent += -tx.Amount
+		group.Transactions++
+		byCategory[name][tx.CategoryName] += -tx.Amount
+	}
+
+	result := make([]AccountGroup, 0, len(byGroup))
+	for name, group := range byGroup {
+		for category, spent := range byCategory[name] {
+			group.TopCategories = append(group.TopCategories, GroupSpendLine{Category: category, Spent: spent})
+		}
+		sort.Slice(group.TopCategories, func(i, j int) bool {
+			a, b := group.TopCategories[i], group.TopCategories[j]
+			return a.Spent > b.Spent || (a.Spent == b.Spent && a.Category < b.Category)
+		})
+		group.TopCategories = group.TopCategories[:min(len(group.TopCategories), groupTopCategories)]
+		result = append(result, *group)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		a, b := result[i], result[j]
+		if (a.Group == "") != (b.Group == "") {
+			return b.Group == ""
+		}
+		return a.Spent > b.Spent || (a.Spent == b.Spent && a.Group < b.Group)
+	})
+	return result
+}
@@ -0,0 +1,51 @@
+package processor
+
+import (
+	"testing"
+
+	"github.com/sathyabhat/ynab-weekly-wrap/internal/ynab"
+)
+
+func TestGroupSpending(t *testing.T) {
+	date := makeDate(2026, 1, 20)
+	tx := func(account, category string, amount int64) ynab.Transaction {
+		tx := makeTx(account+category, date, amount, category)
+		tx.AccountName = account
+		return tx
+	}
+	transfer := tx("Joint Checking", "", -500_000)
+	transfer.TransferAccountID = new(string)
+
+	groups := map[string]string{"Joint Checking": "Joint", "Joint Visa": "Joint", "Alex Amex": "Alex"}
+	result := GroupSpending([]ynab.Transaction{
+		tx("Joint Checking", "Groceries", -150_000),
+		tx("Joint Visa", "Groceries", -50_000),
+		tx("Joint Visa", "Dining", -80_000),
+		tx("Joint Visa", "Fuel", -60_000),
+		tx("Joint Visa", "Pets", -10_000),
+		tx("Joint Visa", "Groceries", 20_000), // Refund
+		tx("Alex Amex", "Hobbies", -90_000),
+		tx("Old Card", "Gifts", -400_000),
+		transfer,
+	}, groups)
+
+	if len(result) != 3 || result[0].Group != "Joint" || result[1].Group != "Alex" || result[2].Group != "" {
+		t.Fatalf("expected Joint, Alex then ungrouped, got %+v", result)
+	}
+	joint := result[0]
+	if joint.Spent != 350_000 || joint.Transactions != 5 {
+		t.Errorf("Joint: got %d over %d transactions, want 350,000 over 5", joint.Spent, joint.Transactions)
+	}
+	want := []GroupSpendLine{{"Groceries", 200_000}, {"Dining", 80_000}, {"Fuel", 60_000}}
+	if len(joint.TopCategories) != len(want) {
+		t.Fatalf("Joint top categories: got %+v, want %+v", joint.TopCategories, want)
+	}
+	for i := range want {
+		if joint.TopCategories[i] != want[i] {
+			t.Errorf("TopCategories[%d]: got %+v, want %+v", i, joint.TopCategories[i], want[i])
+		}
+	}
+	if result[2].Spent != 400_000 {
+		t.Errorf("ungrouped: got %d, want 400,000", result[2].Spent)
+	}
+}
@@ -0,0 +1,146 @@
+package processor
+
+import (
+	"sort"
+
+	"github.com/sathyabhat/ynab-weekly-wrap/internal/ynab"
+)
+
+// Thresholds for the category tidy-up check
+const (
+	unusedMonths     = 3         // Months without activity or budgeting before a category counts as unused
+	chronicShare     = 2.0 / 3.0 // Share of months a pattern must hold in to count as chronic
+	minChronicMonths = 3         // Months of history needed before judging a pattern chronic
+	underSpentShare  = 0.5       // Spending below this share of the budget counts as under-spent
+)
+
+// CategoryHygiene lists categories a long-lived budget could do without or
+// should budget differently
+type CategoryHygiene struct {
+	Months         int               `json:"months"`                    // Months of history checked
+	UnusedMonths   int               `json:"unused_months"`             // Months a category must be idle to count as unused
+	Unused         []string          `json:"unused,omitempty"`          // No activity or budgeting for UnusedMonths or more
+	HiddenBalances []EnvelopeBalance `json:"hidden_balances,omitempty"` // Hidden categories still holding money
+	ChronicOver    []BudgetPattern   `json:"chronic_over,omitempty"`    // Overspent in most months
+	ChronicUnder   []BudgetPattern   `json:"chronic_under,omitempty"`   // Spent well under budget in most months
+}
+
+// Empty reports whether there is nothing to tidy up
+func (h *CategoryHygiene) Empty() bool {
+	return h == nil || len(h.Unused)+len(h.HiddenBalances)+len(h.ChronicOver)+len(h.ChronicUnder) == 0
+}
+
+// BudgetPattern describes a category whose budget keeps missing its spending
+type BudgetPattern struct {
+	Category    string `json:"category"`
+	Months      int    `json:"months"` // Months the pattern held in
+	OfMonths    int    `json:"of_months"`
+	AvgBudgeted int64  `json:"avg_budgeted"`
+	AvgSpent    int64  `json:"avg_spent"`
+}
+
+// AssessCategoryHygiene looks through the months of category figures, most
+// recent first, for categories that have gone unused, hidden categories
+// still holding a balance, and budgets that are chronically too small or
+// too large. current supplies the categories' groups and hidden state, and
+// is matched to the monthly figures by ID. Categories with a goal or marked
+// lumpy are left out of the under-spent check, since they are meant to
+// build up a balance.
+func (a *Analyzer) AssessCategoryHygiene(current []ynab.Category, months [][]ynab.Category) *CategoryHygiene {
+	hygiene := &CategoryHygiene{Months: len(months), UnusedMonths: unusedMonths}
+
+	byMonth := make([]map[string]ynab.Category, len(months))
+	for i, categories := range months {
+		byMonth[i] = make(map[string]ynab.Category, len(categories))
+		for _, cat := range categories {
+			byMonth[i][cat.ID] = cat
+		}
+	}
+
+	for _, cat := range current {
+		group := cat.CategoryGroup
+		if group.Deleted || internalGroups[group.Name] {
+			continue
+		}
+		if cat.Hidden || group.Hidden {
+			if cat.Balance != 0 {
+				hygiene.HiddenBalances = append(hygiene.HiddenBalances, EnvelopeBalance{Category: cat.Name, Balance: cat.Balance})
+			}
+			continue
+		}
+
+		var history []ynab.Category
+		for _, month := range byMonth {
+			if figures, ok := month[cat.ID]; ok {
+				history = append(history, figures)
+			}
+		}
+
+		if isUnused(history) {
+			hygiene.Unused = append(hygiene.Unused, cat.Name)
+			continue
+		}
+		if pattern, ok := chronicPattern(cat.Name, history, func(m ynab.Category) bool {
+			return -m.Activity > m.Budgeted
+		}); ok {
+			hygiene.ChronicOver = append(hygiene.ChronicOver, pattern)
+		}
+		if cat.GoalType == "" && !a.isLumpy(cat) {
+			if pattern, ok := chronicPattern(cat.Name, history, func(m ynab.Category) bool {
+				return m.Budgeted > 0 && float64(-m.Activity) < underSpentShare*float64(m.Budgeted)
+			}); ok {
+				hygiene.ChronicUnder = append(hygiene.ChronicUnder, pattern)
+			}
+		}
+	}
+
+	sort.Strings(hygiene.Unused)
+	sort.Slice(hygiene.HiddenBalances, func(i, j int) bool {
+		return abs(hygiene.HiddenBalances[i].Balance) > abs(hygiene.HiddenBalances[j].Balance)
+	})
+	return hygiene
+}
+
+// isUnused reports whether a category saw no activity and no budgeting in
+// each of the most recent unusedMonths months
+func isUnused(history []ynab.Category) bool {
+	if len(history) < unusedMonths {
+		return false
+	}
+	for _, month := range history[:unusedMonths] {
+		if month.Activity != 0 || month.Budgeted != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// chronicPattern reports whether match holds in at least chronicShare of
+// the months, with the average budget and spending over all of them
+func chronicPattern(category string, history []ynab.Category, match func(ynab.Category) bool) (BudgetPattern, bool) {
+	if len(history) < minChronicMonths {
+		return BudgetPattern{}, false
+	}
+
+	pattern := BudgetPattern{Category: category, OfMonths: len(history)}
+	for _, month := range history {
+		if match(month) {
+			pattern.Months++
+		}
+		pattern.AvgBudgeted += month.Budgeted
+		pattern.AvgSpent += -month.Activity
+	}
+	if float64(pattern.Months) < chronicShare*float64(len(history)) {
+		return BudgetPattern{}, false
+	}
+	pattern.AvgBudgeted /= int64(len(history))
+	pattern.AvgSpent /= int64(len(history))
+	return pattern, true
+}
+
+func abs(n int64) int64 {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
@@ -0,0 +1,87 @@
+package processor
+
+import (
+	"testing"
+
+	"github.com/sathyabhat/ynab-weekly-wrap/internal/ynab"
+)
+
+// monthFigures returns one month of figures for the category IDs given as
+// id -> {budgeted, activity}
+func monthFigures(figures map[string][2]int64) []ynab.Category {
+	var categories []ynab.Category
+	for id, f := range figures {
+		categories = append(categories, ynab.Category{ID: id, Budgeted: f[0], Activity: f[1]})
+	}
+	return categories
+}
+
+func TestAssessCategoryHygiene(t *testing.T) {
+	everyday := ynab.CategoryGroup{Name: "Everyday"}
+	current := []ynab.Category{
+		{ID: "boat", Name: "Boat", CategoryGroup: everyday},
+		{ID: "dining", Name: "Dining Out", CategoryGroup: everyday},
+		{ID: "clothing", Name: "Clothing", CategoryGroup: everyday},
+		{ID: "insurance", Name: "Insurance", CategoryGroup: everyday, GoalType: "NEED"},
+		{ID: "groceries", Name: "Groceries", CategoryGroup: everyday},
+		{ID: "car", Name: "Old Car", CategoryGroup: everyday, Hidden: true, Balance: 120_000},
+		{ID: "gym", Name: "Gym", CategoryGroup: ynab.CategoryGroup{Name: "Retired", Hidden: true}},
+		{ID: "cc", Name: "Visa", CategoryGroup: ynab.CategoryGroup{Name: "Credit Card Payments"}, Balance: 500_000},
+	}
+
+	var months [][]ynab.Category
+	for i := 0; i < 6; i++ {
+		dining := [2]int64{300_000, -420_000}
+		if i == 4 {
+			dining = [2]int64{300_000, -250_000}
+		}
+		clothing := [2]int64{150_000, -20_000}
+		if i%3 == 0 {
+			clothing = [2]int64{150_000, -140_000}
+		}
+		boat := [2]int64{0, 0}
+		if i == 5 {
+			boat = [2]int64{0, -80_000}
+		}
+		months = append(months, monthFigures(map[string][2]int64{
+			"boat":      boat,
+			"dining":    dining,
+			"clothing":  clothing,
+			"insurance": {100_000, 0},
+			"groceries": {600_000, -580_000},
+		}))
+	}
+
+	hygiene := NewAnalyzer().AssessCategoryHygiene(current, months)
+
+	if hygiene.Months != 6 || hygiene.UnusedMonths != 3 {
+		t.Errorf("unexpected window: %+v", hygiene)
+	}
+	if len(hygiene.Unused) != 1 || hygiene.Unused[0] != "Boat" {
+		t.Errorf("expected Boat to be unused, got %v", hygiene.Unused)
+	}
+	if len(hygiene.HiddenBalances) != 1 || hygiene.HiddenBalances[0] != (EnvelopeBalance{Category: "Old Car", Balance: 120_000}) {
+		t.Errorf("expected Old Car's hidden balance only, got %+v", hygiene.HiddenBalances)
+	}
+	if len(hygiene.ChronicOver) != 1 {
+		t.Fatalf("expected Dining Out to be chronically overspent, got %+v", hygiene.ChronicOver)
+	}
+	if over := hygiene.ChronicOver[0]; over.Category != "Dining Out" || over.Months != 5 || over.OfMonths != 6 || over.AvgBudgeted != 300_000 {
+		t.Errorf("unexpected over-budget pattern: %+v", over)
+	}
+	if len(hygiene.ChronicUnder) != 1 {
+		t.Fatalf("expected Clothing to be chronically under-spent, got %+v", hygiene.ChronicUnder)
+	}
+	if under := hygiene.ChronicUnder[0]; under.Category != "Clothing" || under.Months != 4 || under.AvgSpent != 60_000 {
+		t.Errorf("unexpected under-spent pattern: %+v", under)
+	}
+}
+
+func TestAssessCategoryHygiene_ShortHistory(t *testing.T) {
+	current := []ynab.Category{{ID: "boat", Name: "Boat", CategoryGroup: ynab.CategoryGroup{Name: "Everyday"}}}
+	months := [][]ynab.Category{monthFigures(map[string][2]int64{"boat": {0, 0}})}
+
+	if hygiene := NewAnalyzer().AssessCategoryHygiene(current, months); !hygiene.Empty() {
+		t.Errorf("expected nothing to tidy with one month of history, got %+v", hygiene)
+	}
+}
@@ -0,0 +1,90 @@
+package processor
+
+import (
+	"sort"
+	"time"
+
+	"github.com/sathyabhat/ynab-weekly-wrap/internal/ynab"
+)
+
+// An account counts as historically active when it had transactions on at
+// least this share of days, over at least minImportHistoryDays
+const (
+	activeDayShare       = 0.5
+	minImportHistoryDays = 14
+)
+
+// StaleImport describes an account that usually sees activity most days but
+// has gone quiet, which usually means its bank import has stopped working
+type StaleImport struct {
+	AccountName     string    `json:"account_name"`
+	LastTransaction time.Time `json:"last_transaction"`
+	DaysSilent      int       `json:"days_silent"`  // Full days since the last transaction
+	ActiveDays      int       `json:"active_days"`  // Days with transactions before the silence
+	HistoryDays     int       `json:"history_days"` // Days of history ActiveDays was counted over
+}
+
+// DetectStaleImports flags open accounts with transactions on most days of
+// the lookback window that have had none for silentDays or more. An import
+// that fails silently leaves spending out of every report, so it's worth
+// catching before the next wrap. Accounts are returned longest silent first.
+func (a *Analyzer) DetectStaleImports(accounts []ynab.Account, recent []ynab.Transaction, now time.Time, lookbackDays, silentDays int) []StaleImport {
+	if lookbackDays <= 0 || silentDays <= 0 {
+		return nil
+	}
+
+	today := truncateToDay(now)
+	windowStart := today.AddDate(0, 0, -lookbackDays)
+
+	activeDays := make(map[string]map[time.Time]bool)
+	for _, tx := range recent {
+		if tx.Deleted || tx.Date == nil {
+			continue
+		}
+		day := truncateToDay(*tx.Date)
+		if day.Before(windowStart) || day.After(today) {
+			continue
+		}
+		if activeDays[tx.AccountID] == nil {
+			activeDays[tx.AccountID] = make(map[time.Time]bool)
+		}
+		activeDays[tx.AccountID][day] = true
+	}
+
+	var stale []StaleImport
+	for _, account := range accounts {
+		days := activeDays[account.ID]
+		if account.Closed || len(days) == 0 {
+			continue
+		}
+
+		var last time.Time
+		for day := range days {
+			if day.After(last) {
+				last = day
+			}
+		}
+		silent := int(today.Sub(last).Hours() / 24)
+		if silent < silentDays {
+			continue
+		}
+
+		history := int(last.Sub(windowStart).Hours()/24) + 1
+		if history < minImportHistoryDays || float64(len(days)) < activeDayShare*float64(history) {
+			continue
+		}
+
+		stale = append(stale, StaleImport{
+			AccountName:     account.Name,
+			LastTransaction: last,
+			DaysSilent:      silent,
+			ActiveDays:      len(days),
+			HistoryDays:     history,
+		})
+	}
+
+	sort.Slice(stale, func(i, j int) bool {
+		return stale[i].DaysSilent > stale[j].DaysSilent
+	})
+	return stale
+}
@@ -0,0 +1,70 @@
+package processor
+
+import (
+	"testing"
+	"time"
+
+	"github.com/sathyabhat/ynab-weekly-wrap/internal/ynab"
+)
+
+// dailyActivity returns one transaction a day on the account from Jan 1 to
+// lastDay of January, skipping every skip-th day (0 skips none)
+func dailyActivity(accountID string, lastDay, skip int) []ynab.Transaction {
+	var txs []ynab.Transaction
+	for day := 1; day <= lastDay; day++ {
+		if skip > 0 && day%skip == 0 {
+			continue
+		}
+		tx := makeTx("t", makeDate(2026, 1, day), -20_000, "Groceries")
+		tx.AccountID = accountID
+		txs = append(txs, tx)
+	}
+	return txs
+}
+
+func TestDetectStaleImports(t *testing.T) {
+	now := time.Date(2026, 1, 31, 8, 0, 0, 0, time.UTC)
+	accounts := []ynab.Account{
+		{ID: "chk", Name: "Joint Checking"},
+		{ID: "cc", Name: "Visa"},
+		{ID: "sav", Name: "Savings"},
+	}
+	// Checking stopped on the 25th; the card is current; savings only
+	// sees a transfer now and then, so a quiet spell is normal
+	recent := append(dailyActivity("chk", 25, 3), dailyActivity("cc", 30, 0)...)
+	for _, day := range []int{2, 16} {
+		tx := makeTx("s", makeDate(2026, 1, day), 100_000, "")
+		tx.AccountID = "sav"
+		recent = append(recent, tx)
+	}
+
+	stale := NewAnalyzer().DetectStaleImports(accounts, recent, now, 30, 4)
+
+	if len(stale) != 1 {
+		t.Fatalf("stale count: got %d, want 1: %+v", len(stale), stale)
+	}
+	got := stale[0]
+	if got.AccountName != "Joint Checking" || got.DaysSilent != 6 {
+		t.Errorf("got %s silent %d days, want Joint Checking silent 6 days", got.AccountName, got.DaysSilent)
+	}
+	// Window starts Jan 1; 25 days to the last transaction, 8 of them skipped
+	if got.ActiveDays != 17 || got.HistoryDays != 25 {
+		t.Errorf("activity: got %d of %d days, want 17 of 25", got.ActiveDays, got.HistoryDays)
+	}
+}
+
+func TestDetectStaleImports_ShortHistory(t *testing.T) {
+	now := time.Date(2026, 1, 31, 8, 0, 0, 0, time.UTC)
+	accounts := []ynab.Account{{ID: "chk", Name: "New Checking"}}
+	var recent []ynab.Transaction
+	for day := 20; day <= 24; day++ {
+		tx := makeTx("t", makeDate(2026, 1, day), -20_000, "Groceries")
+		tx.AccountID = "chk"
+		recent = append(recent, tx)
+	}
+
+	// Window is 10 days, so only the last few days of activity are visible
+	if stale := NewAnalyzer().DetectStaleImports(accounts, recent, now, 10, 4); len(stale) != 0 {
+		t.Errorf("expected too little history to judge, got %+v", stale)
+	}
+}
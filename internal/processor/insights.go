@@ -0,0 +1,103 @@
+package processor
+
+import (
+	"fmt"
+
+	"github.com/sathyabhat/ynab-weekly-wrap/internal/insights"
+)
+
+// WithInsightRules adds the user's insight rules, whose messages go in the
+// wrap when their conditions hold
+func WithInsightRules(rules []insights.Rule) AnalyzerOption {
+	return func(a *Analyzer) {
+		a.rules = rules
+	}
+}
+
+// applyInsights adds the message of each insight rule that holds. prev and
+// baseline are the spending per category in the previous period and the
+// BaselineWeeks before, nil when not known.
+func (a *Analyzer) applyInsights(result *AnalysisResult, categorySpending []CategorySpending, prev, baseline map[string]int64) {
+	if len(a.rules) == 0 {
+		return
+	}
+	prev, baseline = a.aliases.Apply(prev), a.aliases.Apply(baseline)
+
+	for _, rule := range a.rules {
+		figures, ok := a.insightFigures(rule.Category, result.Overview, categorySpending, prev, baseline)
+		if !ok {
+			continue
+		}
+		values := func(field string) (float64, bool) {
+			value, ok := figures[field]
+			if field != "percentage" {
+				value /= 1000 // Milliunits to the budget's currency
+			}
+			return value, ok
+		}
+		if !rule.Holds(values) {
+			continue
+		}
+		category := rule.Category
+		result.Insights = append(result.Insights, rule.Message(category, func(field string) (string, bool) {
+			value, ok := figures[field]
+			if !ok {
+				return "", false
+			}
+			if field == "percentage" {
+				return fmt.Sprintf("%.0f%%", value), true
+			}
+			return result.Currency.Amount(int64(value)), true
+		}))
+	}
+}
+
+// insightFigures returns the fields a rule can refer to for category, or for
+// the whole budget when category is empty, with amounts in milliunits. It
+// returns false when the category isn't budgeted this month.
+func (a *Analyzer) insightFigures(category string, overview *Overview, categorySpending []CategorySpending, prev, baseline map[string]int64) (map[string]float64, bool) {
+	figures := make(map[string]float64)
+	if category == "" {
+		figures["spent"] = float64(overview.TotalSpent)
+		figures["budgeted"] = float64(overview.TotalBudgeted)
+		figures["balance"] = float64(overview.TotalBalance)
+		figures["over"] = float64(max(-overview.TotalBalance, 0))
+		figures["percentage"] = overview.HealthPercentage
+		if prev != nil {
+			figures["prev_spent"] = float64(sumAmounts(prev))
+		}
+		if baseline != nil {
+			figures["avg_spent"] = float64(sumAmounts(baseline) / BaselineWeeks)
+		}
+		return figures, true
+	}
+
+	for _, cs := range categorySpending {
+		if cs.Category.Name != category {
+			continue
+		}
+		name := a.aliases.Resolve(category)
+		figures["spent"] = float64(cs.Spent)
+		figures["budgeted"] = float64(cs.Budgeted)
+		figures["balance"] = float64(cs.Balance)
+		figures["over"] = float64(max(-cs.Balance, 0))
+		figures["percentage"] = cs.Percentage
+		if prev != nil {
+			figures["prev_spent"] = float64(prev[name])
+		}
+		if baseline != nil {
+			figures["avg_spent"] = float64(baseline[name] / BaselineWeeks)
+		}
+		return figures, true
+	}
+	return nil, false
+}
+
+// sumAmounts totals per-category amounts
+func sumAmounts(amounts map[string]int64) int64 {
+	var total int64
+	for _, amount := range amounts {
+		total += amount
+	}
+	return total
+}
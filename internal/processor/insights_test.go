@@ -0,0 +1,59 @@
+package processor
+
+import (
+	"testing"
+
+	"github.com/sathyabhat/ynab-weekly-wrap/internal/insights"
+)
+
+func TestAnalyzeWeeklyData_Insights(t *testing.T) {
+	rules, err := insights.Parse([]byte(`{"rules": [
+		{"category": "Groceries", "when": "spent > 1.3 * avg_spent", "say": "{category} came to {spent}, well above the usual {avg_spent}"},
+		{"category": "Transport", "when": "spent > 1.3 * avg_spent", "say": "Transport is up"},
+		{"category": "Dining", "when": "over > 0", "say": "Dining is {over} over, {percentage} of its budget"},
+		{"when": "spent >= 2 * prev_spent", "say": "Spending doubled on last week"},
+		{"category": "Holidays", "when": "spent > 0", "say": "Not budgeted"}
+	]}`))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	data := baseWeeklyData()
+	data.Baseline = map[string]int64{"Groceries": 400_000, "Transport": 800_000}
+
+	result, err := NewAnalyzer(WithInsightRules(rules)).AnalyzeWeeklyData(data, map[string]int64{"Groceries": 100_000, "Dining": 200_000}, 0)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := []string{
+		"Groceries came to $200, well above the usual $100",
+		"Dining is $50 over, 117% of its budget",
+		"Spending doubled on last week",
+	}
+	if len(result.Insights) != len(want) {
+		t.Fatalf("Insights: got %q, want %q", result.Insights, want)
+	}
+	for i := range want {
+		if result.Insights[i] != want[i] {
+			t.Errorf("Insights[%d]: got %q, want %q", i, result.Insights[i], want[i])
+		}
+	}
+}
+
+func TestAnalyzeWeeklyData_InsightsWithoutHistory(t *testing.T) {
+	rules, err := insights.Parse([]byte(`{"rules": [
+		{"category": "Groceries", "when": "spent > avg_spent", "say": "Groceries are up"},
+		{"category": "Groceries", "when": "spent > prev_spent", "say": "Groceries are up on last week"}
+	]}`))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	result, err := NewAnalyzer(WithInsightRules(rules)).AnalyzeWeeklyData(baseWeeklyData(), nil, 0)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(result.Insights) != 0 {
+		t.Errorf("expected no insights without a baseline or last week, got %q", result.Insights)
+	}
+}
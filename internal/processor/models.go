@@ -1,6 +1,10 @@
 package processor
 
 import (
+	"fmt"
+	"time"
+
+	"github.com/sathyabhat/ynab-weekly-wrap/internal/money"
 	"github.com/sathyabhat/ynab-weekly-wrap/internal/ynab"
 )
 
@@ -9,57 +13,189 @@ type CategorySpending struct {
 	Spent        int64   // Spending for this category in the period
 	Budgeted     int64   // Monthly budgeted amount
 	Balance      int64   // Remaining balance for the month (from YNAB)
-	Percentage   float64 // Percentage of budget spent in the period
+	Percentage   float64 // Percentage of budget spent in the period (0 for lumpy categories)
+	Lumpy        bool    // One-off or annual category, reported as an amount only
 	Transactions []ynab.Transaction
 }
 
 type AnalysisResult struct {
-	Overview       *Overview
-	TopSpending    []TopSpendingCategory
-	Wins           []CategoryWin
-	Concerns       []CategoryConcernWithTransactions
-	AheadFocus     *AheadFocus
-	DateRange      string
-	HasPrevData    bool
+	Overview    *Overview                         `json:"overview"`
+	TopSpending []TopSpendingCategory             `json:"top_spending"`
+	Wins        []CategoryWin                     `json:"wins"`
+	Concerns    []CategoryConcernWithTransactions `json:"concerns"`
+	AheadFocus  *AheadFocus                       `json:"ahead_focus,omitempty"`
+	Benchmarks  []BenchmarkComparison             `json:"benchmarks,omitempty"`
+	Velocity    []VelocityAlert                   `json:"velocity,omitempty"`
+	Projections []CategoryProjection              `json:"projections,omitempty"`
+	Split       *SpendingSplit                    `json:"split,omitempty"`
+	SafeToSpend *SafeToSpend                      `json:"safe_to_spend,omitempty"`
+	CashFlow    *CashFlow                         `json:"cash_flow,omitempty"` // Income against spending, in weekly wraps
+	Savings     *Savings                          `json:"savings,omitempty"`   // Savings rates, in weekly wraps when enabled
+	Envelopes   *Envelopes                        `json:"envelopes,omitempty"`
+	Coverage    *PeriodCoverage                   `json:"coverage,omitempty"`
+	Confidence  *DataConfidence                   `json:"confidence,omitempty"` // How up to date the accounts are, in weekly wraps
+	Validation  *ynab.Validation                  `json:"validation,omitempty"` // Inconsistent records repaired or left out before the analysis
+	Focus       []string                          `json:"focus,omitempty"`      // Categories or groups the wrap is restricted to
+	Flagged     []ynab.Transaction                `json:"flagged,omitempty"`    // Transactions flagged a highlighted color, in weekly wraps
+	Accounts    []AccountBalance                  `json:"accounts,omitempty"`   // On-budget account balances, in weekly wraps when enabled
+	NetWorth    *NetWorth                         `json:"net_worth,omitempty"`  // Total of all accounts, in weekly wraps when enabled
+	Groups      []AccountGroup                    `json:"groups,omitempty"`     // Spending by account group, in weekly wraps when groups are set
+	Cards       []CardPayment                     `json:"cards,omitempty"`      // Credit card activity against the payment categories, when enabled
+	Actions     []ActionItem                      `json:"actions,omitempty"`    // What to do in YNAB this week, in weekly wraps when enabled
+	FollowUp    *ActionReview                     `json:"follow_up,omitempty"`  // How last week's action items turned out
+	Goals       []GoalProgress                    `json:"goals,omitempty"`      // Progress towards YNAB goals, when enabled
+	Mover       *BiggestMover                     `json:"mover,omitempty"`      // Category whose spending changed most from the previous period
+	Notes       map[string]string                 `json:"notes,omitempty"`      // Category name -> note shown under it
+	NewPayees   []string                          `json:"new_payees,omitempty"` // Payees paid this period but not in the lookback window
+	Patterns    *SpendingPattern                  `json:"patterns,omitempty"`   // Days of the week spending clusters on, in monthly wraps when enabled
+	Unusual     []UnusualTransaction              `json:"unusual,omitempty"`    // Outflows far above their category's stored history
+	Utilities   []UtilitySpike                    `json:"utilities,omitempty"`  // Utility bills well above the payee's typical bill
+	Groceries   *GroceryTrend                     `json:"groceries,omitempty"`  // Average grocery trip and how it has drifted
+	Hygiene     *CategoryHygiene                  `json:"hygiene,omitempty"`    // Categories worth tidying up, in monthly wraps
+	Attention   *NeedsAttention                   `json:"attention,omitempty"`  // Transactions still to categorize, in weekly wraps
+	Insights    []string                          `json:"insights,omitempty"`   // Messages of the user's insight rules that hold
+	Payees      []SummaryPayee                    `json:"payees,omitempty"`     // Payees spent with most, in spending-only mode
+	Spending    []ynab.Transaction                `json:"-"`                    // Transactions counted as spending, in weekly wraps, for exporters to total by category
+	Currency    money.Format                      `json:"currency"`             // How the budget formats amounts
+	DateRange   string                            `json:"date_range"`
+	HasPrevData bool                              `json:"has_prev_data"`
+	HasBaseline bool                              `json:"has_baseline,omitempty"` // Top categories carry their average spending
+	FirstReport bool                              `json:"first_report,omitempty"` // Budget data doesn't reach back to the previous period
+	NoBudget    bool                              `json:"no_budget,omitempty"`    // Spending-only mode: budgets are ignored, so there are no balances or overspending
+}
+
+// BiggestMover is the category with the largest change in spending from the
+// previous period
+type BiggestMover struct {
+	Category  string `json:"category"`
+	Spent     int64  `json:"spent"`
+	PrevSpent int64  `json:"prev_spent"`
+	Delta     int64  `json:"delta"` // Spent - PrevSpent (positive = spent more)
+	// Largest transaction of the period in the category, explaining a rise;
+	// nil when spending fell
+	Transaction *ynab.Transaction `json:"transaction,omitempty"`
+}
+
+// PeriodCoverage describes a period only partly covered by budget data, e.g.
+// the first week after a budget was created
+type PeriodCoverage struct {
+	From       time.Time `json:"from"` // First day with data
+	To         time.Time `json:"to"`
+	Days       int       `json:"days"`        // Days covered by data
+	PeriodDays int       `json:"period_days"` // Days in the full period
 }
 
 type Overview struct {
-	TotalSpent       int64   // Total spending across all categories in the period
-	TotalBudgeted    int64   // Total monthly budget across all categories
-	TotalBalance     int64   // Total remaining balance for the month across all categories
-	HealthPercentage float64 // Percentage of monthly budget used
+	TotalSpent       int64       `json:"total_spent"`            // Total spending across all categories in the period
+	TotalBudgeted    int64       `json:"total_budgeted"`         // Total monthly budget across all categories
+	TotalBalance     int64       `json:"total_balance"`          // Total remaining balance for the month across all categories
+	HealthPercentage float64     `json:"health_percentage"`      // Percentage of monthly budget used, excluding lumpy categories
+	LumpySpent       int64       `json:"lumpy_spent"`            // Spending in lumpy categories, included in TotalSpent
+	AgeOfMoney       *AgeOfMoney `json:"age_of_money,omitempty"` // In weekly wraps, when YNAB has one
+}
+
+// AgeOfMoney is YNAB's Age of Money, with what it was a week earlier when
+// history has it
+type AgeOfMoney struct {
+	Days     int  `json:"days"`
+	LastWeek *int `json:"last_week,omitempty"` // nil when not stored
 }
 
 type CategoryWin struct {
-	Category   string
-	Balance    int64   // Remaining balance for the month
-	Percentage float64 // Percentage of monthly budget used
+	Category   string  `json:"category"`
+	Balance    int64   `json:"balance"`    // Remaining balance for the month
+	Percentage float64 `json:"percentage"` // Percentage of monthly budget used
 }
 
 type AheadFocus struct {
-	Watch       []string
-	Adjustments []string
-	WeeksLeft   int
+	Watch       []string     `json:"watch"`
+	Adjustments []Adjustment `json:"adjustments"`
+	WeeksLeft   int          `json:"weeks_left"`
+}
+
+// Adjustment is a suggestion for a category already over budget
+type Adjustment struct {
+	Category string `json:"category"`
+	Offset   string `json:"offset,omitempty"` // Planned offset to cover it from; empty to suggest reducing its budget
+}
+
+// Text is the suggestion in English, as adjustments were written before they
+// became structured, for custom templates. The built-in format translates it.
+func (a Adjustment) Text() string {
+	if a.Offset != "" {
+		return fmt.Sprintf("Cover %s from %s, the planned offset", a.Category, a.Offset)
+	}
+	return fmt.Sprintf("Consider reducing %s budget", a.Category)
+}
+
+// String lets a template that prints an adjustment as a whole keep reading it
+// as text
+func (a Adjustment) String() string {
+	return a.Text()
 }
 
 type TopSpendingCategory struct {
-	Category   string
-	Spent      int64   // Spending for this category in the period
-	Budgeted   int64   // Monthly budgeted amount
-	Balance    int64   // Remaining balance for the month
-	Percentage float64 // Percentage of budget spent in the period
-	PrevSpent  int64   // Spending in the previous period (valid only when HasPrevData=true)
-	SpendDelta int64   // Spent - PrevSpent (positive = spent more)
+	Category   string  `json:"category"`
+	Spent      int64   `json:"spent"`       // Spending for this category in the period
+	Budgeted   int64   `json:"budgeted"`    // Monthly budgeted amount
+	Balance    int64   `json:"balance"`     // Remaining balance for the month
+	Percentage float64 `json:"percentage"`  // Percentage of budget spent in the period
+	PrevSpent  int64   `json:"prev_spent"`  // Spending in the previous period (valid only when HasPrevData=true)
+	SpendDelta int64   `json:"spend_delta"` // Spent - PrevSpent (positive = spent more)
+	AvgSpent   int64   `json:"avg_spent"`   // Average over the BaselineWeeks before (valid only when HasBaseline=true)
+	Lumpy      bool    `json:"lumpy,omitempty"`
 }
 
 type CategoryConcernWithTransactions struct {
-	Category     string
-	Budgeted     int64
-	Spent        int64
-	Balance      int64
-	Over         int64
-	Percentage   float64
-	Transactions []ynab.Transaction
-	PrevSpent    int64 // Spending in the previous period (valid only when HasPrevData=true)
-	SpendDelta   int64 // Spent - PrevSpent (positive = spent more)
+	Category     string             `json:"category"`
+	Budgeted     int64              `json:"budgeted"`
+	Spent        int64              `json:"spent"`
+	Balance      int64              `json:"balance"`
+	Over         int64              `json:"over"`
+	Severity     Severity           `json:"severity"`
+	Percentage   float64            `json:"percentage"`
+	Transactions []ynab.Transaction `json:"transactions"`
+	PrevSpent    int64              `json:"prev_spent"`          // Spending in the previous period (valid only when HasPrevData=true)
+	SpendDelta   int64              `json:"spend_delta"`         // Spent - PrevSpent (positive = spent more)
+	Offsets      []OffsetStep       `json:"offsets,omitempty"`   // Planned offsets drawn on to cover Over, in order
+	Uncovered    int64              `json:"uncovered,omitempty"` // Over left once the planned offsets run out
+}
+
+// BenchmarkComparison compares actual spending against a personal benchmark
+// configured independently of the YNAB budget
+type BenchmarkComparison struct {
+	Category   string  `json:"category"`
+	Target     int64   `json:"target"`     // Benchmark for the period
+	Actual     int64   `json:"actual"`     // Spending for this category in the period
+	Delta      int64   `json:"delta"`      // Actual - Target (positive = over benchmark)
+	Percentage float64 `json:"percentage"` // Percentage of benchmark used
+}
+
+// VelocityAlert flags a category that has spent a large share of its monthly
+// budget early in the month
+type VelocityAlert struct {
+	Category   string  `json:"category"`
+	MonthSpent int64   `json:"month_spent"` // Month-to-date spending
+	Budgeted   int64   `json:"budgeted"`    // Monthly budgeted amount
+	Percentage float64 `json:"percentage"`  // Percentage of monthly budget spent so far
+	Day        int     `json:"day"`         // Day of the month the threshold was observed
+}
+
+// SplitTarget is the desired needs/wants/savings split in percent (e.g. 50/30/20)
+type SplitTarget struct {
+	Needs   int `json:"needs"`
+	Wants   int `json:"wants"`
+	Savings int `json:"savings"`
+}
+
+// SpendingSplit summarizes the period's spending by need/want/savings tag
+type SpendingSplit struct {
+	Needs          int64       `json:"needs"`
+	Wants          int64       `json:"wants"`
+	Savings        int64       `json:"savings"`
+	Untagged       int64       `json:"untagged"` // Spending in categories without a tag
+	NeedsPercent   float64     `json:"needs_percent"`
+	WantsPercent   float64     `json:"wants_percent"`
+	SavingsPercent float64     `json:"savings_percent"`
+	Target         SplitTarget `json:"target"`
 }
@@ -0,0 +1,74 @@
+package processor
+
+import "github.com/sathyabhat/ynab-weekly-wrap/internal/ynab"
+
+// OffsetStep is money drawn from a planned offset towards an overspent
+// category
+type OffsetStep struct {
+	Category string `json:"category"`
+	Amount   int64  `json:"amount"` // At most what the offset holds, 0 when it's empty
+}
+
+// WithOffsetRules sets the household's overspending playbook: overspent
+// category -> the category planned to cover it. An offset can have an
+// offset of its own, drawn on once it runs out.
+func WithOffsetRules(rules map[string]string) AnalyzerOption {
+	return func(a *Analyzer) {
+		a.offsets = rules
+	}
+}
+
+// applyOffsets plans how each concern is covered by the offset rules,
+// largest overspending first. Offsets shared by several concerns are drawn
+// down as they go, so the same money isn't counted twice.
+func (a *Analyzer) applyOffsets(concerns []CategoryConcernWithTransactions, categories []ynab.Category) {
+	if len(a.offsets) == 0 {
+		return
+	}
+	available := categoryBalances(categories)
+	for i := range concerns {
+		steps, uncovered := a.planOffset(concerns[i].Category, concerns[i].Over, available)
+		if len(steps) > 0 {
+			concerns[i].Offsets, concerns[i].Uncovered = steps, uncovered
+		}
+	}
+}
+
+// planOffset follows the offset rules from an overspent category, drawing
+// on each planned offset in turn until over is covered or the chain ends,
+// and returns the steps along with what is left uncovered. available is
+// drawn down by what each step takes.
+func (a *Analyzer) planOffset(category string, over int64, available map[string]int64) ([]OffsetStep, int64) {
+	var steps []OffsetStep
+	seen := map[string]bool{category: true}
+	for offset := a.offsets[category]; offset != "" && over > 0 && !seen[offset]; offset = a.offsets[offset] {
+		seen[offset] = true
+		amount := min(over, max(available[offset], 0))
+		available[offset] -= amount
+		over -= amount
+		steps = append(steps, OffsetStep{Category: offset, Amount: amount})
+	}
+	return steps, over
+}
+
+// plannedOffset returns the first category along the offset rules from
+// category that still holds money, or "" when none does
+func (a *Analyzer) plannedOffset(category string, available map[string]int64) string {
+	seen := map[string]bool{category: true}
+	for offset := a.offsets[category]; offset != "" && !seen[offset]; offset = a.offsets[offset] {
+		if available[offset] > 0 {
+			return offset
+		}
+		seen[offset] = true
+	}
+	return ""
+}
+
+// categoryBalances maps category names to their balances
+func categoryBalances(categories []ynab.Category) map[string]int64 {
+	balances := make(map[string]int64, len(categories))
+	for _, cat := range categories {
+		balances[cat.Name] = cat.Balance
+	}
+	return balances
+}
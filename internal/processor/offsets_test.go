@@ -0,0 +1,49 @@
+package processor
+
+import (
+	"testing"
+
+	"github.com/sathyabhat/ynab-weekly-wrap/internal/ynab"
+)
+
+func TestApplyOffsets(t *testing.T) {
+	a := NewAnalyzer(WithOffsetRules(map[string]string{
+		"Car Repairs":    "Vacation Fund",
+		"Vet":            "Vacation Fund",
+		"Vacation Fund":  "Emergency Fund",
+		"Emergency Fund": "Car Repairs", // A loop must not be followed round
+		"Dining":         "Fun Money",
+	}))
+	categories := []ynab.Category{
+		makeCategory("c1", "Car Repairs", 0, -600_000),
+		makeCategory("c2", "Vet", 0, -100_000),
+		makeCategory("c3", "Vacation Fund", 0, 300_000),
+		makeCategory("c4", "Emergency Fund", 0, 200_000),
+		makeCategory("c5", "Dining", 0, -50_000),
+		makeCategory("c6", "Fun Money", 0, 0),
+		makeCategory("c7", "Clothing", 0, -20_000),
+	}
+	concerns := []CategoryConcernWithTransactions{
+		{Category: "Car Repairs", Over: 600_000},
+		{Category: "Vet", Over: 100_000},
+		{Category: "Dining", Over: 50_000},
+		{Category: "Clothing", Over: 20_000},
+	}
+	a.applyOffsets(concerns, categories)
+
+	car := concerns[0]
+	if len(car.Offsets) != 2 || car.Offsets[0] != (OffsetStep{"Vacation Fund", 300_000}) ||
+		car.Offsets[1] != (OffsetStep{"Emergency Fund", 200_000}) || car.Uncovered != 100_000 {
+		t.Errorf("expected Car Repairs to drain Vacation Fund then Emergency Fund, $100 short, got %+v", car)
+	}
+	// Car Repairs has already drawn the whole chain down, back round to itself
+	if vet := concerns[1]; len(vet.Offsets) != 3 || vet.Offsets[0].Amount != 0 || vet.Uncovered != 100_000 {
+		t.Errorf("expected nothing left for Vet, got %+v", vet)
+	}
+	if dining := concerns[2]; len(dining.Offsets) != 1 || dining.Offsets[0] != (OffsetStep{"Fun Money", 0}) || dining.Uncovered != 50_000 {
+		t.Errorf("expected Dining's empty offset reported, got %+v", dining)
+	}
+	if clothing := concerns[3]; clothing.Offsets != nil || clothing.Uncovered != 0 {
+		t.Errorf("expected no plan without a rule, got %+v", clothing)
+	}
+}
@@ -0,0 +1,173 @@
+package processor
+
+import (
+	"sort"
+	"time"
+
+	"github.com/sathyabhat/ynab-weekly-wrap/internal/ynab"
+)
+
+// OverdraftRisk describes a checking account projected to go negative before
+// its next expected income
+type OverdraftRisk struct {
+	AccountName      string    `json:"account_name"`
+	Balance          int64     `json:"balance"`           // Current balance
+	ProjectedLow     int64     `json:"projected_low"`     // Lowest projected balance before the next income
+	NegativeOn       time.Time `json:"negative_on"`       // First day the balance is projected to go negative
+	NextIncome       time.Time `json:"next_income"`       // Next expected income date (zero if none within the horizon)
+	AvgDailySpend    int64     `json:"avg_daily_spend"`   // Average daily outflow over the lookback window
+	ScheduledOutflow int64     `json:"scheduled_outflow"` // Scheduled outflows due before the next income
+}
+
+// AssessOverdraftRisk projects each open checking account forward day by day,
+// subtracting the average daily spend and any scheduled outflows, until the
+// next scheduled income (or the horizon if no income is scheduled). Accounts
+// whose projected balance drops below zero are returned, soonest first.
+func (a *Analyzer) AssessOverdraftRisk(accounts []ynab.Account, scheduled []ynab.ScheduledTransaction, recent []ynab.Transaction, now time.Time, lookbackDays, horizonDays int) []OverdraftRisk {
+	if lookbackDays <= 0 || horizonDays <= 0 {
+		return nil
+	}
+
+	today := truncateToDay(now)
+	horizon := today.AddDate(0, 0, horizonDays)
+
+	var risks []OverdraftRisk
+	for _, account := range accounts {
+		if account.Type != "checking" || account.Closed {
+			continue
+		}
+
+		avgDailySpend := averageDailyOutflow(account.ID, recent, lookbackDays)
+
+		// Expand scheduled transactions for this account within the horizon
+		outflows := make(map[time.Time]int64)
+		var nextIncome time.Time
+		for _, st := range scheduled {
+			if st.AccountID != account.ID || st.DateNext == nil {
+				continue
+			}
+			for _, date := range occurrences(*st.DateNext, st.Frequency, today, horizon) {
+				if st.Amount > 0 {
+					if nextIncome.IsZero() || date.Before(nextIncome) {
+						nextIncome = date
+					}
+				} else {
+					outflows[date] += -st.Amount
+				}
+			}
+		}
+
+		end := horizon
+		if !nextIncome.IsZero() {
+			end = nextIncome
+		}
+
+		balance := account.Balance
+		low := balance
+		var negativeOn time.Time
+		var scheduledOutflow int64
+		for day := today.AddDate(0, 0, 1); !day.After(end); day = day.AddDate(0, 0, 1) {
+			// Income lands on the income date itself, so only spending before it counts
+			if !nextIncome.IsZero() && day.Equal(nextIncome) {
+				break
+			}
+			balance -= avgDailySpend + outflows[day]
+			scheduledOutflow += outflows[day]
+			if balance < low {
+				low = balance
+			}
+			if balance < 0 && negativeOn.IsZero() {
+				negativeOn = day
+			}
+		}
+
+		if negativeOn.IsZero() {
+			continue
+		}
+
+		risks = append(risks, OverdraftRisk{
+			AccountName:      account.Name,
+			Balance:          account.Balance,
+			ProjectedLow:     low,
+			NegativeOn:       negativeOn,
+			NextIncome:       nextIncome,
+			AvgDailySpend:    avgDailySpend,
+			ScheduledOutflow: scheduledOutflow,
+		})
+	}
+
+	sort.Slice(risks, func(i, j int) bool {
+		return risks[i].NegativeOn.Before(risks[j].NegativeOn)
+	})
+
+	return risks
+}
+
+// averageDailyOutflow averages categorized outflows from the account over the
+// lookback window (transfers carry no category and are left out)
+func averageDailyOutflow(accountID string, transactions []ynab.Transaction, lookbackDays int) int64 {
+	var total int64
+	for _, tx := range transactions {
+		if tx.Deleted || tx.AccountID != accountID || tx.Amount >= 0 || tx.CategoryID == nil {
+			continue
+		}
+		total += -tx.Amount
+	}
+	return total / int64(lookbackDays)
+}
+
+// occurrences expands a scheduled transaction into the dates it falls on within (from, to]
+func occurrences(next time.Time, frequency string, from, to time.Time) []time.Time {
+	var dates []time.Time
+	date := truncateToDay(next)
+	for i := 0; i < 366 && !date.After(to); i++ {
+		if date.After(from) {
+			dates = append(dates, date)
+		}
+
+		following, ok := advance(date, frequency)
+		if !ok {
+			break
+		}
+		date = following
+	}
+	return dates
+}
+
+// advance returns the next date for a YNAB scheduled frequency
+func advance(date time.Time, frequency string) (time.Time, bool) {
+	switch frequency {
+	case "daily":
+		return date.AddDate(0, 0, 1), true
+	case "weekly":
+		return date.AddDate(0, 0, 7), true
+	case "everyOtherWeek":
+		return date.AddDate(0, 0, 14), true
+	case "twiceAMonth":
+		return date.AddDate(0, 0, 15), true
+	case "every4Weeks":
+		return date.AddDate(0, 0, 28), true
+	case "monthly":
+		return date.AddDate(0, 1, 0), true
+	case "everyOtherMonth":
+		return date.AddDate(0, 2, 0), true
+	case "every3Months":
+		return date.AddDate(0, 3, 0), true
+	case "every4Months":
+		return date.AddDate(0, 4, 0), true
+	case "twiceAYear":
+		return date.AddDate(0, 6, 0), true
+	case "yearly":
+		return date.AddDate(1, 0, 0), true
+	case "everyOtherYear":
+		return date.AddDate(2, 0, 0), true
+	default:
+		return time.Time{}, false
+	}
+}
+
+// truncateToDay returns midnight UTC of t's calendar date, so dates from YNAB
+// (UTC) and the local clock can be compared and used as map keys
+func truncateToDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
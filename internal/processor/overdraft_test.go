@@ -0,0 +1,97 @@
+package processor
+
+import (
+	"testing"
+	"time"
+
+	"github.com/sathyabhat/ynab-weekly-wrap/internal/ynab"
+)
+
+func overdraftAccounts() []ynab.Account {
+	return []ynab.Account{
+		{ID: "chk", Name: "Joint Checking", Type: "checking", OnBudget: true, Balance: 500_000},
+		{ID: "sav", Name: "Savings", Type: "savings", OnBudget: true, Balance: 100_000},
+	}
+}
+
+// recentSpend returns 30 days of $50/day outflows from the given account
+func recentSpend(accountID string) []ynab.Transaction {
+	var txs []ynab.Transaction
+	for i := 1; i <= 30; i++ {
+		tx := makeTx("t", makeDate(2026, 1, i), -50_000, "Groceries")
+		tx.AccountID = accountID
+		txs = append(txs, tx)
+	}
+	return txs
+}
+
+func TestAssessOverdraftRisk_GoesNegativeBeforeIncome(t *testing.T) {
+	now := time.Date(2026, 2, 1, 9, 0, 0, 0, time.UTC)
+	scheduled := []ynab.ScheduledTransaction{
+		{ID: "rent", DateNext: makeDate(2026, 2, 3), Frequency: "monthly", Amount: -400_000, AccountID: "chk"},
+		{ID: "pay", DateNext: makeDate(2026, 2, 14), Frequency: "everyOtherWeek", Amount: 3_000_000, AccountID: "chk"},
+	}
+
+	a := NewAnalyzer()
+	risks := a.AssessOverdraftRisk(overdraftAccounts(), scheduled, recentSpend("chk"), now, 30, 14)
+
+	if len(risks) != 1 {
+		t.Fatalf("risks count: got %d, want 1", len(risks))
+	}
+	risk := risks[0]
+	if risk.AccountName != "Joint Checking" {
+		t.Errorf("AccountName: got %s, want Joint Checking", risk.AccountName)
+	}
+	// 500 - 50 (02-02) - 50 - 400 (02-03) = 0, then -50 on 02-04
+	if got := risk.NegativeOn.Format("2006-01-02"); got != "2026-02-04" {
+		t.Errorf("NegativeOn: got %s, want 2026-02-04", got)
+	}
+	if got := risk.NextIncome.Format("2006-01-02"); got != "2026-02-14" {
+		t.Errorf("NextIncome: got %s, want 2026-02-14", got)
+	}
+	if risk.AvgDailySpend != 50_000 {
+		t.Errorf("AvgDailySpend: got %d, want 50000", risk.AvgDailySpend)
+	}
+	if risk.ScheduledOutflow != 400_000 {
+		t.Errorf("ScheduledOutflow: got %d, want 400000", risk.ScheduledOutflow)
+	}
+}
+
+func TestAssessOverdraftRisk_IncomeArrivesFirst(t *testing.T) {
+	now := time.Date(2026, 2, 1, 9, 0, 0, 0, time.UTC)
+	scheduled := []ynab.ScheduledTransaction{
+		{ID: "pay", DateNext: makeDate(2026, 2, 3), Frequency: "everyOtherWeek", Amount: 3_000_000, AccountID: "chk"},
+		{ID: "rent", DateNext: makeDate(2026, 2, 5), Frequency: "monthly", Amount: -400_000, AccountID: "chk"},
+	}
+
+	a := NewAnalyzer()
+	risks := a.AssessOverdraftRisk(overdraftAccounts(), scheduled, recentSpend("chk"), now, 30, 14)
+	if len(risks) != 0 {
+		t.Errorf("expected no risk when income lands first, got %+v", risks)
+	}
+}
+
+func TestAssessOverdraftRisk_OnlyCheckingAccounts(t *testing.T) {
+	now := time.Date(2026, 2, 1, 9, 0, 0, 0, time.UTC)
+
+	a := NewAnalyzer()
+	risks := a.AssessOverdraftRisk(overdraftAccounts(), nil, recentSpend("sav"), now, 30, 14)
+	if len(risks) != 0 {
+		t.Errorf("expected savings account to be ignored, got %+v", risks)
+	}
+}
+
+func TestOccurrences_ExpandsRecurring(t *testing.T) {
+	from := time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2026, 2, 28, 0, 0, 0, 0, time.UTC)
+
+	dates := occurrences(*makeDate(2026, 2, 2), "weekly", from, to)
+	if len(dates) != 4 {
+		t.Errorf("weekly occurrences: got %d, want 4", len(dates))
+	}
+
+	dates = occurrences(*makeDate(2026, 2, 2), "never", from, to)
+	if len(dates) != 1 {
+		t.Errorf("one-off occurrences: got %d, want 1", len(dates))
+	}
+}
@@ -0,0 +1,74 @@
+package processor
+
+import "time"
+
+// patternDays is how many days in a row a spending peak spans, e.g. Friday
+// and Saturday
+const patternDays = 2
+
+// patternShare is the percentage of the spending the peak days must hold to
+// be pointed out. Spread evenly, two days would hold about 29%.
+const patternShare = 40
+
+// SpendingPattern is when in the week the spending clusters, over the
+// stored history. YNAB records only the date of a transaction, so the days
+// of the week are as close as it gets to the time of day.
+type SpendingPattern struct {
+	Months        int          `json:"months"`                   // How much history the pattern is drawn from
+	Even          float64      `json:"even"`                     // Percentage the peak days would hold if spending were spread evenly
+	Peak          *WeekdayPeak `json:"peak,omitempty"`           // Where all spending clusters; nil when it's spread out
+	OverspentPeak *WeekdayPeak `json:"overspent_peak,omitempty"` // Where the spending in overspent categories clusters
+}
+
+// WeekdayPeak is the days in a row holding the most spending
+type WeekdayPeak struct {
+	From  time.Weekday `json:"from"`
+	To    time.Weekday `json:"to"`
+	Share float64      `json:"share"` // Percentage of the spending on those days
+}
+
+// FindSpendingPattern looks for the days of the week that all spending, and
+// the spending in categories that ended up overspent, clusters on, given the
+// outflows by time.Weekday. It returns nil when neither clusters.
+func FindSpendingPattern(outflows, overspent [7]int64, months int) *SpendingPattern {
+	pattern := &SpendingPattern{
+		Months:        months,
+		Even:          float64(patternDays) / 7 * 100,
+		Peak:          weekdayPeak(outflows),
+		OverspentPeak: weekdayPeak(overspent),
+	}
+	if pattern.Peak == nil && pattern.OverspentPeak == nil {
+		return nil
+	}
+	return pattern
+}
+
+// weekdayPeak returns the patternDays days in a row, Monday first on ties,
+// holding the largest share of amounts, or nil when that share is below
+// patternShare
+func weekdayPeak(amounts [7]int64) *WeekdayPeak {
+	var total int64
+	for _, amount := range amounts {
+		total += amount
+	}
+	if total <= 0 {
+		return nil
+	}
+
+	var peak *WeekdayPeak
+	for i := range 7 {
+		from := time.Weekday((i + 1) % 7) // Monday first
+		var sum int64
+		for day := range patternDays {
+			sum += amounts[(int(from)+day)%7]
+		}
+		share := float64(sum) / float64(total) * 100
+		if peak == nil || share > peak.Share {
+			peak = &WeekdayPeak{From: from, To: time.Weekday((int(from) + patternDays - 1) % 7), Share: share}
+		}
+	}
+	if peak.Share < patternShare {
+		return nil
+	}
+	return peak
+}
@@ -0,0 +1,34 @@
+package processor
+
+import (
+	"testing"
+	"time"
+)
+
+func TestFindSpendingPattern(t *testing.T) {
+	var outflows, overspent [7]int64
+	for day := range outflows {
+		outflows[day] = 100_000
+	}
+	outflows[time.Friday], outflows[time.Saturday] = 400_000, 300_000
+	overspent[time.Saturday], overspent[time.Sunday] = 150_000, 100_000
+	overspent[time.Wednesday] = 50_000
+
+	pattern := FindSpendingPattern(outflows, overspent, 3)
+	if pattern == nil || pattern.Peak == nil || pattern.OverspentPeak == nil {
+		t.Fatalf("expected both peaks, got %+v", pattern)
+	}
+	if peak := pattern.Peak; peak.From != time.Friday || peak.To != time.Saturday || int(peak.Share) != 58 {
+		t.Errorf("expected 58%% on Friday and Saturday, got %+v", peak)
+	}
+	if peak := pattern.OverspentPeak; peak.From != time.Saturday || peak.To != time.Sunday || int(peak.Share) != 83 {
+		t.Errorf("expected 83%% on Saturday and Sunday, got %+v", peak)
+	}
+
+	for day := range outflows {
+		outflows[day] = 100_000
+	}
+	if pattern := FindSpendingPattern(outflows, [7]int64{}, 3); pattern != nil {
+		t.Errorf("expected no pattern in evenly spread spending, got %+v", pattern)
+	}
+}
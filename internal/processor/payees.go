@@ -0,0 +1,42 @@
+package processor
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/sathyabhat/ynab-weekly-wrap/internal/ynab"
+)
+
+// transferPayeePrefix starts the payee name YNAB gives transfers between
+// accounts
+const transferPayeePrefix = "Transfer : "
+
+// PayeeKey is how payee names are matched across periods, ignoring case and
+// surrounding space
+func PayeeKey(name string) string {
+	return strings.ToLower(strings.TrimSpace(name))
+}
+
+// NewPayees returns the payees paid in transactions that are not in known,
+// which is keyed by PayeeKey. A payee never paid before is either a new
+// subscription or a charge that isn't yours, so both are worth a look.
+// Inflows, transfers and deleted transactions are skipped, and names are
+// returned sorted.
+func NewPayees(transactions []ynab.Transaction, known map[string]bool) []string {
+	seen := make(map[string]bool)
+	var payees []string
+	for _, tx := range transactions {
+		name := strings.TrimSpace(tx.PayeeName)
+		if tx.Deleted || tx.Amount >= 0 || name == "" || strings.HasPrefix(name, transferPayeePrefix) {
+			continue
+		}
+		key := PayeeKey(name)
+		if known[key] || seen[key] {
+			continue
+		}
+		seen[key] = true
+		payees = append(payees, name)
+	}
+	sort.Strings(payees)
+	return payees
+}
@@ -0,0 +1,35 @@
+package processor
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/sathyabhat/ynab-weekly-wrap/internal/ynab"
+)
+
+func TestNewPayees(t *testing.T) {
+	known := map[string]bool{PayeeKey("Countdown"): true, PayeeKey("Netflix"): true}
+	transactions := []ynab.Transaction{
+		{PayeeName: "SteamPowered", Amount: -29_990},
+		{PayeeName: "countdown ", Amount: -84_200},
+		{PayeeName: "Bright Dental", Amount: -180_000},
+		{PayeeName: "Bright Dental", Amount: -20_000},
+		{PayeeName: "Employer Ltd", Amount: 2_400_000},
+		{PayeeName: "Transfer : Savings", Amount: -500_000},
+		{PayeeName: "Deleted Shop", Amount: -10_000, Deleted: true},
+		{PayeeName: "", Amount: -5_000},
+	}
+
+	got := NewPayees(transactions, known)
+	want := []string{"Bright Dental", "SteamPowered"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
+
+func TestNewPayees_NoneNew(t *testing.T) {
+	known := map[string]bool{PayeeKey("Netflix"): true}
+	if got := NewPayees([]ynab.Transaction{{PayeeName: "NETFLIX", Amount: -15_990}}, known); len(got) != 0 {
+		t.Errorf("expected no new payees, got %v", got)
+	}
+}
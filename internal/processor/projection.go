@@ -0,0 +1,70 @@
+package processor
+
+import (
+	"sort"
+	"time"
+
+	"github.com/sathyabhat/ynab-weekly-wrap/internal/ynab"
+)
+
+// minProjectionDays is how far into the budget month spending has to be
+// before it is projected, since a few days say little about the month
+const minProjectionDays = 7
+
+// CategoryProjection is a category on pace to spend more than its budget by
+// the end of the month
+type CategoryProjection struct {
+	Category   string `json:"category"`
+	MonthSpent int64  `json:"month_spent"` // Month-to-date spending
+	Projected  int64  `json:"projected"`   // End-of-month spending at the month-to-date rate
+	Budgeted   int64  `json:"budgeted"`
+	Over       int64  `json:"over"` // Projected - Budgeted
+	// Most it can spend per day for the rest of the month to finish within
+	// budget, over DaysLeft days; 0 on the month's last day
+	PerDay   int64 `json:"per_day"`
+	DaysLeft int   `json:"days_left"`
+}
+
+// projectMonthEnd projects each flexible category's end-of-month spending
+// from its month-to-date run rate and returns those projected to go over
+// budget, furthest over first. Categories already fully spent are left out,
+// as are fixed and lumpy ones: a bill paid once or a big purchase doesn't
+// repeat at the same rate.
+func (a *Analyzer) projectMonthEnd(categories []ynab.Category, monthToDate []ynab.Transaction, asOf time.Time) []CategoryProjection {
+	day := fiscalDay(asOf, a.fiscalStartDay)
+	if day < minProjectionDays {
+		return nil
+	}
+	start, end := FiscalMonth(asOf, a.fiscalStartDay)
+	monthDays := int(end.Sub(start).Hours()/24) + 1
+
+	spent := a.monthToDateSpent(monthToDate)
+	var projections []CategoryProjection
+	for _, cat := range categories {
+		monthSpent := spent(cat)
+		if cat.Budgeted <= 0 || monthSpent <= 0 || monthSpent >= cat.Budgeted || !a.isFlexible(cat) {
+			continue
+		}
+		projected := monthSpent * int64(monthDays) / int64(day)
+		if projected <= cat.Budgeted {
+			continue
+		}
+		projection := CategoryProjection{
+			Category:   cat.Name,
+			MonthSpent: monthSpent,
+			Projected:  projected,
+			Budgeted:   cat.Budgeted,
+			Over:       projected - cat.Budgeted,
+			DaysLeft:   monthDays - day,
+		}
+		if projection.DaysLeft > 0 {
+			projection.PerDay = (cat.Budgeted - monthSpent) / int64(projection.DaysLeft)
+		}
+		projections = append(projections, projection)
+	}
+
+	sort.SliceStable(projections, func(i, j int) bool {
+		return projections[i].Over > projections[j].Over
+	})
+	return projections
+}
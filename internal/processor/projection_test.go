@@ -0,0 +1,49 @@
+package processor
+
+import (
+	"testing"
+	"time"
+
+	"github.com/sathyabhat/ynab-weekly-wrap/internal/ynab"
+)
+
+func TestProjectMonthEnd(t *testing.T) {
+	spent := func(name string, budgeted, activity int64) ynab.Category {
+		cat := makeCategory(name, name, budgeted, budgeted+activity)
+		cat.Activity = activity
+		return cat
+	}
+	categories := []ynab.Category{
+		spent("Dining", 300_000, -150_000),    // on pace for 465
+		spent("Groceries", 600_000, -200_000), // on pace for 620
+		spent("Fun", 100_000, -20_000),        // on pace for 62
+		spent("Rent", 2_000_000, -2_000_000),  // paid in full
+		spent("Gifts", 100_000, -90_000),      // lumpy
+	}
+	asOf := time.Date(2026, 1, 10, 23, 59, 59, 0, time.UTC) // day 10 of 31
+
+	projections := NewAnalyzer(WithLumpyCategories([]string{"Gifts"})).projectMonthEnd(categories, nil, asOf)
+
+	want := []CategoryProjection{
+		{Category: "Dining", MonthSpent: 150_000, Projected: 465_000, Budgeted: 300_000, Over: 165_000, PerDay: 7_142, DaysLeft: 21},
+		{Category: "Groceries", MonthSpent: 200_000, Projected: 620_000, Budgeted: 600_000, Over: 20_000, PerDay: 19_047, DaysLeft: 21},
+	}
+	if len(projections) != len(want) {
+		t.Fatalf("got %+v, want %+v", projections, want)
+	}
+	for i := range want {
+		if projections[i] != want[i] {
+			t.Errorf("projections[%d]: got %+v, want %+v", i, projections[i], want[i])
+		}
+	}
+}
+
+func TestProjectMonthEnd_TooEarly(t *testing.T) {
+	categories := []ynab.Category{makeCategory("c1", "Dining", 300_000, 200_000)}
+	categories[0].Activity = -100_000
+	asOf := time.Date(2026, 1, 3, 0, 0, 0, 0, time.UTC)
+
+	if projections := NewAnalyzer().projectMonthEnd(categories, nil, asOf); projections != nil {
+		t.Errorf("expected no projections on day 3, got %+v", projections)
+	}
+}
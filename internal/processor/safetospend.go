@@ -0,0 +1,71 @@
+package processor
+
+import (
+	"time"
+
+	"github.com/sathyabhat/ynab-weekly-wrap/internal/ynab"
+)
+
+// SafeToSpend is how much can be spent from flexible categories through the
+// end of the week while leaving enough for the rest of the budget month
+type SafeToSpend struct {
+	Amount        int64     `json:"amount"`
+	Through       time.Time `json:"through"`        // Last day the amount covers: the coming Sunday, or the month end if sooner
+	Available     int64     `json:"available"`      // Remaining balance across flexible categories
+	UpcomingBills int64     `json:"upcoming_bills"` // Scheduled outflows from flexible categories due before the month ends
+	DaysLeft      int       `json:"days_left"`      // Days left in the budget month, including today
+}
+
+// calculateSafeToSpend spreads what is left in flexible categories, less the
+// scheduled bills still to come out of them, evenly over the days left in the
+// budget month and returns the share up to Sunday. It returns nil when no
+// category is flexible.
+func (a *Analyzer) calculateSafeToSpend(categories []ynab.Category, scheduled []ynab.ScheduledTransaction, now time.Time) *SafeToSpend {
+	flexible := make(map[string]bool)
+	var available int64
+	for _, cat := range categories {
+		if !a.isFlexible(cat) {
+			continue
+		}
+		flexible[cat.Name] = true
+		if cat.Balance > 0 {
+			available += cat.Balance
+		}
+	}
+	if len(flexible) == 0 {
+		return nil
+	}
+
+	today := truncateToDay(now)
+	_, monthEnd := FiscalMonth(today, a.fiscalStartDay)
+
+	// Bills due today haven't been entered yet, so they count too
+	var bills int64
+	for _, st := range scheduled {
+		if st.Amount >= 0 || st.DateNext == nil || !flexible[st.CategoryName] {
+			continue
+		}
+		due := occurrences(*st.DateNext, st.Frequency, today.AddDate(0, 0, -1), monthEnd)
+		bills += -st.Amount * int64(len(due))
+	}
+
+	through := today.AddDate(0, 0, (7-int(today.Weekday()))%7)
+	if through.After(monthEnd) {
+		through = monthEnd
+	}
+	daysLeft := int(monthEnd.Sub(today).Hours()/24) + 1
+	days := int(through.Sub(today).Hours()/24) + 1
+
+	var amount int64
+	if spendable := available - bills; spendable > 0 {
+		amount = spendable * int64(days) / int64(daysLeft)
+	}
+
+	return &SafeToSpend{
+		Amount:        amount,
+		Through:       through,
+		Available:     available,
+		UpcomingBills: bills,
+		DaysLeft:      daysLeft,
+	}
+}
@@ -0,0 +1,80 @@
+package processor
+
+import (
+	"testing"
+	"time"
+
+	"github.com/sathyabhat/ynab-weekly-wrap/internal/ynab"
+)
+
+func safeToSpendCategories() []ynab.Category {
+	rent := makeCategory("rent", "Rent", 1_500_000, 1_500_000)
+	rent.CategoryGroup.Name = "Bills"
+	insurance := makeCategory("ins", "Car Insurance", 600_000, 600_000)
+	rta := makeCategory("rta", "Inflow: Ready to Assign", 0, 900_000)
+	rta.CategoryGroup.Name = "Internal Master Category"
+
+	return []ynab.Category{
+		makeCategory("groc", "Groceries", 600_000, 400_000),
+		makeCategory("fun", "Fun", 300_000, 200_000),
+		makeCategory("dine", "Dining Out", 150_000, -50_000),
+		rent, insurance, rta,
+	}
+}
+
+func TestCalculateSafeToSpend(t *testing.T) {
+	a := NewAnalyzer(WithFixedCategories([]string{"Bills"}), WithLumpyCategories([]string{"Car Insurance"}))
+	scheduled := []ynab.ScheduledTransaction{
+		{ID: "stream", DateNext: makeDate(2026, 6, 3), Frequency: "weekly", Amount: -10_000, CategoryName: "Fun"},
+		{ID: "rent", DateNext: makeDate(2026, 6, 5), Frequency: "monthly", Amount: -1_500_000, CategoryName: "Rent"},
+	}
+	now := time.Date(2026, 6, 1, 9, 0, 0, 0, time.UTC) // Monday
+
+	safe := a.calculateSafeToSpend(safeToSpendCategories(), scheduled, now)
+	if safe == nil {
+		t.Fatal("expected a safe-to-spend figure")
+	}
+	// Groceries + Fun, less four weekly streaming bills, over 7 of 30 days
+	if safe.Available != 600_000 || safe.UpcomingBills != 40_000 {
+		t.Errorf("available/bills: got %d/%d, want 600000/40000", safe.Available, safe.UpcomingBills)
+	}
+	if safe.DaysLeft != 30 || !safe.Through.Equal(*makeDate(2026, 6, 7)) {
+		t.Errorf("days left %d through %s, want 30 through 2026-06-07", safe.DaysLeft, safe.Through.Format("2006-01-02"))
+	}
+	if safe.Amount != 560_000*7/30 {
+		t.Errorf("amount: got %d, want %d", safe.Amount, 560_000*7/30)
+	}
+}
+
+func TestCalculateSafeToSpend_MonthEndsBeforeSunday(t *testing.T) {
+	a := NewAnalyzer()
+	categories := []ynab.Category{makeCategory("groc", "Groceries", 600_000, 100_000)}
+	now := time.Date(2026, 6, 29, 9, 0, 0, 0, time.UTC) // Monday, month ends Tuesday
+
+	safe := a.calculateSafeToSpend(categories, nil, now)
+	if !safe.Through.Equal(*makeDate(2026, 6, 30)) || safe.Amount != 100_000 {
+		t.Errorf("got %d through %s, want the whole 100000 through 2026-06-30", safe.Amount, safe.Through.Format("2006-01-02"))
+	}
+}
+
+func TestCalculateSafeToSpend_BillsExceedBalance(t *testing.T) {
+	a := NewAnalyzer()
+	categories := []ynab.Category{makeCategory("groc", "Groceries", 600_000, 100_000)}
+	scheduled := []ynab.ScheduledTransaction{
+		{ID: "box", DateNext: makeDate(2026, 6, 10), Frequency: "never", Amount: -150_000, CategoryName: "Groceries"},
+	}
+
+	safe := a.calculateSafeToSpend(categories, scheduled, time.Date(2026, 6, 1, 9, 0, 0, 0, time.UTC))
+	if safe.Amount != 0 {
+		t.Errorf("amount: got %d, want 0", safe.Amount)
+	}
+}
+
+func TestCalculateSafeToSpend_NoFlexibleCategories(t *testing.T) {
+	a := NewAnalyzer(WithFixedCategories([]string{"Rent"}))
+	categories := []ynab.Category{makeCategory("rent", "Rent", 1_500_000, 1_500_000)}
+
+	if safe := a.calculateSafeToSpend(categories, nil, time.Now()); safe != nil {
+		t.Errorf("expected nil, got %+v", safe)
+	}
+}
@@ -0,0 +1,57 @@
+package processor
+
+// Severity tiers how far over budget a category is, so a few dollars over
+// doesn't read the same as a blowout
+type Severity string
+
+const (
+	SeveritySlight   Severity = "slight"   // Less than 10% over budget
+	SeverityModerate Severity = "moderate" // 10-50% over
+	SeveritySevere   Severity = "severe"   // More than 50% over, or over the severe amount
+)
+
+// Percentages of the budget marking the moderate and severe tiers
+const (
+	moderateOverPercent = 10
+	severeOverPercent   = 50
+)
+
+// WithSevereOverAmount sets an amount over budget, in milliunits, that is
+// severe whatever the budget, e.g. $200 over a $2,000 rent budget. 0 tiers
+// by percentage alone.
+func WithSevereOverAmount(amount int64) AnalyzerOption {
+	return func(a *Analyzer) {
+		a.severeOver = amount
+	}
+}
+
+// severity tiers an amount over a category's budget. Without a budget
+// there is no percentage, so it's moderate unless over the severe amount.
+func (a *Analyzer) severity(over, budgeted int64) Severity {
+	if a.severeOver > 0 && over >= a.severeOver {
+		return SeveritySevere
+	}
+	if budgeted <= 0 {
+		return SeverityModerate
+	}
+	switch percent := float64(over) / float64(budgeted) * 100; {
+	case percent > severeOverPercent:
+		return SeveritySevere
+	case percent >= moderateOverPercent:
+		return SeverityModerate
+	default:
+		return SeveritySlight
+	}
+}
+
+// rank orders the tiers, most severe first
+func (s Severity) rank() int {
+	switch s {
+	case SeveritySevere:
+		return 0
+	case SeverityModerate:
+		return 1
+	default:
+		return 2
+	}
+}
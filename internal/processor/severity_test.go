@@ -0,0 +1,43 @@
+package processor
+
+import "testing"
+
+func TestIdentifyConcerns_Severity(t *testing.T) {
+	over := func(name string, budgeted, balance int64) CategorySpending {
+		return CategorySpending{Category: makeCategory(name, name, budgeted, balance), Budgeted: budgeted}
+	}
+	spending := []CategorySpending{
+		over("Coffee", 100_000, -3_000),        // 3% over
+		over("Dining", 300_000, -60_000),       // 20% over
+		over("Car Repairs", 200_000, -400_000), // 200% over
+		over("Rent", 2_000_000, -250_000),      // 12.5% over, but past the severe amount
+		over("Gifts", 0, -20_000),              // No budget
+		over("Groceries", 600_000, 100_000),
+	}
+
+	concerns := NewAnalyzer(WithSevereOverAmount(200_000)).identifyConcernsWithTransactions(spending)
+	want := []struct {
+		category string
+		severity Severity
+	}{
+		{"Car Repairs", SeveritySevere},
+		{"Rent", SeveritySevere},
+		{"Dining", SeverityModerate},
+		{"Gifts", SeverityModerate},
+		{"Coffee", SeveritySlight},
+	}
+	if len(concerns) != len(want) {
+		t.Fatalf("got %+v, want %+v", concerns, want)
+	}
+	for i, w := range want {
+		if concerns[i].Category != w.category || concerns[i].Severity != w.severity {
+			t.Errorf("concerns[%d]: got %s %s, want %s %s", i, concerns[i].Category, concerns[i].Severity, w.category, w.severity)
+		}
+	}
+
+	// Without a severe amount, Rent is tiered by percentage alone
+	concerns = NewAnalyzer().identifyConcernsWithTransactions(spending)
+	if concerns[1].Category != "Rent" || concerns[1].Severity != SeverityModerate {
+		t.Errorf("expected Rent moderately over, got %+v", concerns[1])
+	}
+}
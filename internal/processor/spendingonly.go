@@ -0,0 +1,33 @@
+package processor
+
+import "github.com/sathyabhat/ynab-weekly-wrap/internal/ynab"
+
+// WithSpendingOnly ignores budgets, for households that track spending in
+// YNAB without budgeting categories. Every category with spending is
+// reported, whatever is budgeted, along with the payees spent with most,
+// and everything comparing spending with the budget is left out.
+func WithSpendingOnly(enabled bool) AnalyzerOption {
+	return func(a *Analyzer) {
+		a.spendingOnly = enabled
+	}
+}
+
+// applySpendingOnly leaves the budget figures out of result and adds the
+// payees spent with most, at most limit of them (0 = all)
+func (a *Analyzer) applySpendingOnly(result *AnalysisResult, transactions []ynab.Transaction, limit int) {
+	if !a.spendingOnly {
+		return
+	}
+	result.NoBudget = true
+	result.Overview.TotalBudgeted, result.Overview.TotalBalance, result.Overview.HealthPercentage = 0, 0, 0
+	for i := range result.TopSpending {
+		result.TopSpending[i].Budgeted, result.TopSpending[i].Balance, result.TopSpending[i].Percentage = 0, 0, 0
+	}
+	result.Wins, result.Concerns, result.AheadFocus = nil, nil, nil
+	result.Velocity, result.Projections, result.SafeToSpend, result.Envelopes = nil, nil, nil, nil
+
+	result.Payees = topPayees(transactions)
+	if limit > 0 {
+		result.Payees = result.Payees[:min(limit, len(result.Payees))]
+	}
+}
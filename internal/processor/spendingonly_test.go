@@ -0,0 +1,53 @@
+package processor
+
+import "testing"
+
+func TestAnalyzeWeeklyData_SpendingOnly(t *testing.T) {
+	data := baseWeeklyData()
+	data.Categories = append(data.Categories, makeCategory("c4", "Hobbies", 0, -80_000))
+	hobbies := makeTx("t4", makeDate(2026, 1, 22), -80_000, "Hobbies")
+	hobbies.PayeeName = "Craft Shop"
+	data.Transactions = append(data.Transactions, hobbies)
+	data.Transactions[0].PayeeName = "Countdown"
+	data.Transactions[2].PayeeName = "Countdown"
+
+	result, err := NewAnalyzer(WithSpendingOnly(true)).AnalyzeWeeklyData(data, nil, 0)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if !result.NoBudget {
+		t.Error("expected NoBudget to be set")
+	}
+	if result.Overview.TotalSpent != 780_000 {
+		t.Errorf("TotalSpent: got %d, want 780000 with the unbudgeted category", result.Overview.TotalSpent)
+	}
+	if len(result.TopSpending) != 4 {
+		t.Fatalf("TopSpending: got %d categories, want 4", len(result.TopSpending))
+	}
+	for _, category := range result.TopSpending {
+		if category.Balance != 0 || category.Percentage != 0 {
+			t.Errorf("%s: expected no budget figures, got balance %d, %.0f%%", category.Category, category.Balance, category.Percentage)
+		}
+	}
+	if len(result.Concerns) != 0 || len(result.Wins) != 0 || result.AheadFocus != nil || result.SafeToSpend != nil {
+		t.Errorf("expected no budget sections, got concerns %v, wins %v", result.Concerns, result.Wins)
+	}
+	if len(result.Payees) != 2 || result.Payees[0].Payee != "Countdown" || result.Payees[0].Spent != 550_000 || result.Payees[0].Transactions != 2 {
+		t.Errorf("Payees: got %+v, want Countdown first with $550 across 2", result.Payees)
+	}
+}
+
+func TestAnalyzeWeeklyData_UnbudgetedSkippedByDefault(t *testing.T) {
+	data := baseWeeklyData()
+	data.Categories = append(data.Categories, makeCategory("c4", "Hobbies", 0, -80_000))
+	data.Transactions = append(data.Transactions, makeTx("t4", makeDate(2026, 1, 22), -80_000, "Hobbies"))
+
+	result, err := NewAnalyzer().AnalyzeWeeklyData(data, nil, 0)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.NoBudget || result.Payees != nil || len(result.TopSpending) != 3 {
+		t.Errorf("expected the budgeted categories only, got %+v", result.TopSpending)
+	}
+}
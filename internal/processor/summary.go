@@ -0,0 +1,205 @@
+package processor
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/sathyabhat/ynab-weekly-wrap/internal/money"
+	"github.com/sathyabhat/ynab-weekly-wrap/internal/ynab"
+)
+
+// Summary periods
+const (
+	SummaryQuarterly = "quarterly"
+	SummaryYearly    = "yearly"
+)
+
+// SummaryPeriod is a quarter or year to summarize
+type SummaryPeriod struct {
+	Kind  string    // SummaryQuarterly or SummaryYearly
+	Label string    // e.g. "2026-Q3" or "2025"
+	Start time.Time // First day
+	End   time.Time // Last day
+}
+
+// ParseSummaryPeriod reads a quarter ("2026-Q3") or year ("2025") of the
+// given kind. An empty spec means the last one to have finished before now.
+func ParseSummaryPeriod(kind, spec string, now time.Time) (SummaryPeriod, error) {
+	switch kind {
+	case SummaryQuarterly:
+		year, quarter := now.Year(), (int(now.Month())-1)/3 // Previous quarter, 0 meaning Q4 of last year
+		if quarter == 0 {
+			year, quarter = year-1, 4
+		}
+		if spec != "" {
+			yearStr, quarterStr, ok := strings.Cut(strings.ToUpper(spec), "-Q")
+			y, yErr := strconv.Atoi(yearStr)
+			q, qErr := strconv.Atoi(quarterStr)
+			if !ok || yErr != nil || qErr != nil || q < 1 || q > 4 {
+				return SummaryPeriod{}, fmt.Errorf("invalid quarter %q: expected e.g. 2026-Q3", spec)
+			}
+			year, quarter = y, q
+		}
+		start := time.Date(year, time.Month((quarter-1)*3+1), 1, 0, 0, 0, 0, time.UTC)
+		return SummaryPeriod{
+			Kind:  kind,
+			Label: fmt.Sprintf("%04d-Q%d", year, quarter),
+			Start: start,
+			End:   start.AddDate(0, 3, -1),
+		}, nil
+	case SummaryYearly:
+		year := now.Year() - 1
+		if spec != "" {
+			y, err := strconv.Atoi(spec)
+			if err != nil || len(spec) != 4 {
+				return SummaryPeriod{}, fmt.Errorf("invalid year %q: expected e.g. 2025", spec)
+			}
+			year = y
+		}
+		start := time.Date(year, time.January, 1, 0, 0, 0, 0, time.UTC)
+		return SummaryPeriod{
+			Kind:  kind,
+			Label: strconv.Itoa(year),
+			Start: start,
+			End:   start.AddDate(1, 0, -1),
+		}, nil
+	default:
+		return SummaryPeriod{}, fmt.Errorf("period must be %s or %s, got %q", SummaryQuarterly, SummaryYearly, kind)
+	}
+}
+
+// PeriodSummary aggregates spending and budget adherence over a quarter or
+// year
+type PeriodSummary struct {
+	Period        SummaryPeriod     `json:"-"`
+	Label         string            `json:"label"`
+	Months        int               `json:"months"`
+	TotalSpent    int64             `json:"total_spent"`
+	TotalBudgeted int64             `json:"total_budgeted"`
+	Categories    []SummaryCategory `json:"categories"` // Top categories by spending
+	Payees        []SummaryPayee    `json:"payees"`     // Top payees by spending
+	// Share of budgeted category-months that stayed within budget, leaving
+	// out lumpy categories
+	AdherencePercent float64      `json:"adherence_percent"`
+	Currency         money.Format `json:"currency"`
+}
+
+// SummaryCategory is a category's spending over a summary period
+type SummaryCategory struct {
+	Category   string `json:"category"`
+	Spent      int64  `json:"spent"`
+	Budgeted   int64  `json:"budgeted"`
+	MonthsOver int    `json:"months_over"` // Months it was overspent
+}
+
+// SummaryPayee is a payee's spending over a summary period
+type SummaryPayee struct {
+	Payee        string `json:"payee"`
+	Spent        int64  `json:"spent"`
+	Transactions int    `json:"transactions"`
+}
+
+// SummarizePeriod adds up each month's category figures and the period's
+// transactions, listing up to limit categories and payees (0 = all)
+func (a *Analyzer) SummarizePeriod(data *ynab.PeriodData, period SummaryPeriod, limit int) (*PeriodSummary, error) {
+	if data == nil || len(data.Months) == 0 {
+		return nil, fmt.Errorf("period data is empty: %w", ynab.ErrNoData)
+	}
+
+	summary := &PeriodSummary{
+		Period:   period,
+		Label:    period.Label,
+		Months:   len(data.Months),
+		Currency: budgetCurrency(data.Budget),
+	}
+
+	// Categories are matched by ID, so a category renamed during the period
+	// is counted once under its latest name
+	byID := make(map[string]*SummaryCategory)
+	var onBudget, budgetedMonths int
+	for _, month := range data.Months {
+		for _, cat := range month {
+			spent := max(-cat.Activity, 0)
+			entry, ok := byID[cat.ID]
+			if !ok {
+				entry = &SummaryCategory{}
+				byID[cat.ID] = entry
+			}
+			entry.Category = cat.Name
+			entry.Spent += spent
+			entry.Budgeted += cat.Budgeted
+			summary.TotalSpent += spent
+			summary.TotalBudgeted += cat.Budgeted
+
+			if cat.Budgeted <= 0 {
+				continue
+			}
+			if spent > cat.Budgeted {
+				entry.MonthsOver++
+			}
+			if !a.isLumpy(cat) {
+				budgetedMonths++
+				if spent <= cat.Budgeted {
+					onBudget++
+				}
+			}
+		}
+	}
+	if budgetedMonths > 0 {
+		summary.AdherencePercent = float64(onBudget) / float64(budgetedMonths) * 100
+	}
+
+	for _, entry := range byID {
+		if entry.Spent > 0 {
+			summary.Categories = append(summary.Categories, *entry)
+		}
+	}
+	sort.Slice(summary.Categories, func(i, j int) bool {
+		if summary.Categories[i].Spent != summary.Categories[j].Spent {
+			return summary.Categories[i].Spent > summary.Categories[j].Spent
+		}
+		return summary.Categories[i].Category < summary.Categories[j].Category
+	})
+
+	summary.Payees = topPayees(data.Transactions)
+	if limit > 0 {
+		summary.Categories = summary.Categories[:min(limit, len(summary.Categories))]
+		summary.Payees = summary.Payees[:min(limit, len(summary.Payees))]
+	}
+	return summary, nil
+}
+
+// topPayees totals spending per payee, largest first. Inflows, transfers and
+// uncategorized transactions are left out, as in the wraps.
+func topPayees(transactions []ynab.Transaction) []SummaryPayee {
+	byKey := make(map[string]*SummaryPayee)
+	for _, tx := range transactions {
+		name := strings.TrimSpace(tx.PayeeName)
+		if tx.Deleted || tx.CategoryID == nil || tx.Amount >= 0 || name == "" || strings.HasPrefix(name, transferPayeePrefix) {
+			continue
+		}
+		key := PayeeKey(name)
+		entry, ok := byKey[key]
+		if !ok {
+			entry = &SummaryPayee{Payee: name}
+			byKey[key] = entry
+		}
+		entry.Spent += -tx.Amount
+		entry.Transactions++
+	}
+
+	payees := make([]SummaryPayee, 0, len(byKey))
+	for _, entry := range byKey {
+		payees = append(payees, *entry)
+	}
+	sort.Slice(payees, func(i, j int) bool {
+		if payees[i].Spent != payees[j].Spent {
+			return payees[i].Spent > payees[j].Spent
+		}
+		return payees[i].Payee < payees[j].Payee
+	})
+	return payees
+}
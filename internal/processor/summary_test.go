@@ -0,0 +1,89 @@
+package processor
+
+import (
+	"testing"
+	"time"
+
+	"github.com/sathyabhat/ynab-weekly-wrap/internal/ynab"
+)
+
+func TestParseSummaryPeriod(t *testing.T) {
+	now := time.Date(2026, 2, 10, 9, 0, 0, 0, time.UTC)
+	cases := []struct {
+		kind, spec string
+		label      string
+		start, end string
+	}{
+		{SummaryQuarterly, "", "2025-Q4", "2025-10-01", "2025-12-31"},
+		{SummaryQuarterly, "2026-q1", "2026-Q1", "2026-01-01", "2026-03-31"},
+		{SummaryQuarterly, "2025-Q3", "2025-Q3", "2025-07-01", "2025-09-30"},
+		{SummaryYearly, "", "2025", "2025-01-01", "2025-12-31"},
+		{SummaryYearly, "2024", "2024", "2024-01-01", "2024-12-31"},
+	}
+	for _, tc := range cases {
+		period, err := ParseSummaryPeriod(tc.kind, tc.spec, now)
+		if err != nil {
+			t.Errorf("%s %q: unexpected error: %v", tc.kind, tc.spec, err)
+			continue
+		}
+		if period.Label != tc.label || period.Start.Format("2006-01-02") != tc.start || period.End.Format("2006-01-02") != tc.end {
+			t.Errorf("%s %q: got %s %s to %s, want %s %s to %s", tc.kind, tc.spec,
+				period.Label, period.Start.Format("2006-01-02"), period.End.Format("2006-01-02"), tc.label, tc.start, tc.end)
+		}
+	}
+
+	for _, bad := range [][2]string{{SummaryQuarterly, "2026-Q5"}, {SummaryQuarterly, "2026"}, {SummaryYearly, "25"}, {"weekly", ""}} {
+		if _, err := ParseSummaryPeriod(bad[0], bad[1], now); err == nil {
+			t.Errorf("%s %q: expected an error", bad[0], bad[1])
+		}
+	}
+}
+
+func TestSummarizePeriod(t *testing.T) {
+	month := func(groceries, dining int64) []ynab.Category {
+		return []ynab.Category{
+			{ID: "g", Name: "Groceries", Budgeted: 400_000, Activity: -groceries},
+			{ID: "d", Name: "Dining Out", Budgeted: 100_000, Activity: -dining},
+		}
+	}
+	payee := func(name string, amount int64) ynab.Transaction {
+		tx := makeTx(name, makeDate(2026, 1, 5), amount, "Groceries")
+		tx.PayeeName = name
+		return tx
+	}
+	data := &ynab.PeriodData{
+		Months: [][]ynab.Category{month(380_000, 150_000), month(420_000, 90_000), month(390_000, 80_000)},
+		Transactions: []ynab.Transaction{
+			payee("Countdown", -200_000),
+			payee("countdown ", -150_000),
+			payee("Pak'nSave", -300_000),
+			payee("Transfer : Savings", -500_000),
+			payee("Employer", 2_000_000),
+		},
+	}
+
+	summary, err := NewAnalyzer().SummarizePeriod(data, SummaryPeriod{Label: "2026-Q1"}, 0)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if summary.TotalSpent != 1_510_000 || summary.TotalBudgeted != 1_500_000 {
+		t.Errorf("totals: got %d spent of %d budgeted", summary.TotalSpent, summary.TotalBudgeted)
+	}
+	if len(summary.Categories) != 2 || summary.Categories[0].Category != "Groceries" || summary.Categories[0].MonthsOver != 1 || summary.Categories[1].MonthsOver != 1 {
+		t.Errorf("categories: got %+v", summary.Categories)
+	}
+	// 4 of the 6 budgeted category-months stayed within budget
+	if summary.AdherencePercent < 66.6 || summary.AdherencePercent > 66.7 {
+		t.Errorf("adherence: got %.1f%%, want 66.7%%", summary.AdherencePercent)
+	}
+	if len(summary.Payees) != 2 || summary.Payees[0].Payee != "Countdown" || summary.Payees[0].Spent != 350_000 || summary.Payees[0].Transactions != 2 {
+		t.Errorf("payees: got %+v", summary.Payees)
+	}
+}
+
+func TestSummarizePeriod_Empty(t *testing.T) {
+	if _, err := NewAnalyzer().SummarizePeriod(&ynab.PeriodData{}, SummaryPeriod{}, 0); err == nil {
+		t.Error("expected an error without any months")
+	}
+}
@@ -0,0 +1,91 @@
+package processor
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/sathyabhat/ynab-weekly-wrap/internal/ynab"
+)
+
+const (
+	// utilityBillCount is how many of a payee's previous bills make up its
+	// typical bill
+	utilityBillCount = 12
+	// minUtilityBills is how many previous bills a payee needs before a
+	// bill is compared against them
+	minUtilityBills = 3
+)
+
+// UtilitySpike is a utility bill well above the payee's typical bill
+type UtilitySpike struct {
+	Payee   string  `json:"payee"`
+	Amount  int64   `json:"amount"`  // Positive
+	Typical int64   `json:"typical"` // Average of the previous bills, up to 12
+	Percent float64 `json:"percent"` // How far Amount is above Typical
+	Bills   int     `json:"bills"`   // How many previous bills Typical averages
+}
+
+// UtilityBills returns the outflows to the given utility categories or
+// payees, matched by name ignoring case
+func UtilityBills(transactions []ynab.Transaction, names []string) []ynab.Transaction {
+	match := make(map[string]bool, len(names))
+	for _, name := range names {
+		match[PayeeKey(name)] = true
+	}
+	var bills []ynab.Transaction
+	for _, tx := range transactions {
+		if tx.Deleted || tx.Amount >= 0 || tx.TransferAccountID != nil {
+			continue
+		}
+		if match[PayeeKey(tx.CategoryName)] || match[PayeeKey(tx.PayeeName)] {
+			bills = append(bills, tx)
+		}
+	}
+	return bills
+}
+
+// FindUtilitySpikes compares each of the week's bills to the given utility
+// categories or payees against the payee's previous bills in past, and
+// returns those more than threshold percent above the average of the last
+// twelve, largest rise first. Bills are grouped by payee, so an electricity
+// bill isn't compared with water bills in a shared Utilities category.
+func FindUtilitySpikes(week, past []ynab.Transaction, names []string, threshold int) []UtilitySpike {
+	previous := make(map[string][]ynab.Transaction)
+	for _, tx := range UtilityBills(past, names) {
+		if tx.Date != nil {
+			previous[PayeeKey(tx.PayeeName)] = append(previous[PayeeKey(tx.PayeeName)], tx)
+		}
+	}
+
+	var spikes []UtilitySpike
+	for _, bill := range UtilityBills(week, names) {
+		bills := previous[PayeeKey(bill.PayeeName)]
+		if len(bills) < minUtilityBills {
+			continue
+		}
+		sort.SliceStable(bills, func(i, j int) bool { return bills[i].Date.After(*bills[j].Date) })
+		bills = bills[:min(len(bills), utilityBillCount)]
+
+		var total int64
+		for _, tx := range bills {
+			total += -tx.Amount
+		}
+		typical := total / int64(len(bills))
+		if typical <= 0 {
+			continue
+		}
+		percent := float64(-bill.Amount-typical) / float64(typical) * 100
+		if percent <= float64(threshold) {
+			continue
+		}
+		spikes = append(spikes, UtilitySpike{
+			Payee:   strings.TrimSpace(bill.PayeeName),
+			Amount:  -bill.Amount,
+			Typical: typical,
+			Percent: percent,
+			Bills:   len(bills),
+		})
+	}
+	sort.SliceStable(spikes, func(i, j int) bool { return spikes[i].Percent > spikes[j].Percent })
+	return spikes
+}
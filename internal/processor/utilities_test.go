@@ -0,0 +1,43 @@
+package processor
+
+import (
+	"testing"
+
+	"github.com/sathyabhat/ynab-weekly-wrap/internal/ynab"
+)
+
+func utilityBill(payee, category string, month int, amount int64) ynab.Transaction {
+	tx := makeTx(payee, makeDate(2025, month, 15), -amount, category)
+	tx.PayeeName = payee
+	return tx
+}
+
+func TestFindUtilitySpikes(t *testing.T) {
+	var past []ynab.Transaction
+	for month := 1; month <= 12; month++ {
+		past = append(past,
+			utilityBill("Genesis Energy", "Utilities", month, 150_000),
+			utilityBill("Watercare", "Utilities", month, 60_000),
+			utilityBill("Countdown", "Groceries", month, 200_000),
+		)
+	}
+	past = append(past, utilityBill("Spark", "Internet", 11, 80_000), utilityBill("Spark", "Internet", 12, 80_000))
+	// Bills more than a year back don't count towards the typical bill
+	past = append(past, utilityBill("Genesis Energy", "Utilities", 0, 900_000))
+
+	week := []ynab.Transaction{
+		utilityBill("Genesis Energy", "Utilities", 13, 213_000), // 42% above
+		utilityBill("Watercare", "Utilities", 13, 66_000),       // 10% above
+		utilityBill("Spark", "Internet", 13, 160_000),           // Too few bills to judge
+		utilityBill("Countdown", "Groceries", 13, 400_000),      // Not a utility
+	}
+
+	spikes := FindUtilitySpikes(week, past, []string{"utilities", "Spark"}, 20)
+	if len(spikes) != 1 {
+		t.Fatalf("expected only the electricity bill, got %+v", spikes)
+	}
+	spike := spikes[0]
+	if spike.Payee != "Genesis Energy" || spike.Amount != 213_000 || spike.Typical != 150_000 || spike.Bills != 12 || int(spike.Percent) != 42 {
+		t.Errorf("got %+v, want Genesis Energy 42%% above 150,000 over 12 bills", spike)
+	}
+}
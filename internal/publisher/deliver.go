@@ -0,0 +1,217 @@
+package publisher
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrTimeout is returned for a destination that didn't finish within the
+// delivery timeout. The send may still complete in the background.
+var ErrTimeout = errors.New("delivery timed out")
+
+// defaultBackoff is used when a temporary error doesn't suggest a delay
+const defaultBackoff = time.Second
+
+// Named is implemented by publishers that report a destination name for logs
+// and delivery summaries
+type Named interface {
+	Name() string
+}
+
+// TemporaryError is implemented by publisher errors that are worth retrying,
+// such as rate limits. Backoff returns the suggested delay (zero = default).
+type TemporaryError interface {
+	error
+	Temporary() bool
+	Backoff() time.Duration
+}
+
+// Name returns the publisher's destination name, falling back to its type
+func Name(pub Publisher) string {
+	if named, ok := pub.(Named); ok {
+		return named.Name()
+	}
+	t := reflect.TypeOf(pub)
+	if t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	return strings.ToLower(t.String())
+}
+
+// DeliveryFailure records a destination that could not be delivered to.
+// Error is safe to show elsewhere; Err, for the local log, may hold
+// credentials.
+type DeliveryFailure struct {
+	Destination string `json:"destination"`
+	Err         error  `json:"-"`
+	Error       string `json:"error"`
+}
+
+// DeliveryResult aggregates the outcome of delivering one report to every
+// destination
+type DeliveryResult struct {
+	Successes []string          `json:"successes"`
+	Failures  []DeliveryFailure `json:"failures"`
+	Retried   []string          `json:"retried"` // Destinations that needed a retry, whatever the outcome
+	Duration  time.Duration     `json:"duration"`
+}
+
+// Err returns the failures joined into one error, or nil if every
+// destination succeeded
+func (r *DeliveryResult) Err() error {
+	var errs []error
+	for _, failure := range r.Failures {
+		errs = append(errs, fmt.Errorf("%s: %w", failure.Destination, failure.Err))
+	}
+	return errors.Join(errs...)
+}
+
+// SafeError returns err's text with the URL of every request error in it left
+// out, since bot and webhook tokens are part of the URLs. It is what may be
+// shown beyond the local log, e.g. to other destinations.
+func SafeError(err error) string {
+	if err == nil {
+		return ""
+	}
+	text := err.Error()
+	for _, urlErr := range urlErrors(err) {
+		text = strings.ReplaceAll(text, urlErr.Error(), urlErr.Err.Error())
+	}
+	return text
+}
+
+// urlErrors returns the request errors in err's tree
+func urlErrors(err error) []*url.Error {
+	var found []*url.Error
+	if urlErr, ok := err.(*url.Error); ok && urlErr.Err != nil {
+		found = append(found, urlErr)
+	}
+	switch e := err.(type) {
+	case interface{ Unwrap() error }:
+		if inner := e.Unwrap(); inner != nil {
+			found = append(found, urlErrors(inner)...)
+		}
+	case interface{ Unwrap() []error }:
+		for _, inner := range e.Unwrap() {
+			found = append(found, urlErrors(inner)...)
+		}
+	}
+	return found
+}
+
+// Summary describes the result in one line, e.g.
+// "2/3 destinations succeeded (1 retried); failed: discord"
+func (r *DeliveryResult) Summary() string {
+	total := len(r.Successes) + len(r.Failures)
+	summary := fmt.Sprintf("%d/%d destinations succeeded", len(r.Successes), total)
+	if len(r.Retried) > 0 {
+		summary += fmt.Sprintf(" (%d retried)", len(r.Retried))
+	}
+	if len(r.Failures) > 0 {
+		failed := make([]string, len(r.Failures))
+		for i, failure := range r.Failures {
+			failed[i] = failure.Destination
+		}
+		summary += "; failed: " + strings.Join(failed, ", ")
+	}
+	return summary
+}
+
+// Deliver sends the report to every publisher concurrently. Each destination
+// gets its own timeout (zero = none), and a destination failing with a
+// temporary error is retried once after its suggested backoff. Results keep
+// the publishers' order. The message is rendered into a document once up
+// front and every channel formats its own markup from it; destinations with
+// a variant of the report get that instead.
+func Deliver(ctx context.Context, publishers []Publisher, report Report, timeout time.Duration) *DeliveryResult {
+	start := time.Now()
+	report.Document = report.Doc()
+
+	type outcome struct {
+		name    string
+		err     error
+		retried bool
+	}
+	outcomes := make([]outcome, len(publishers))
+
+	var wg sync.WaitGroup
+	for i, pub := range publishers {
+		wg.Add(1)
+		go func(i int, pub Publisher) {
+			defer wg.Done()
+			name := Name(pub)
+			report := report.For(name)
+			err := publishWithTimeout(ctx, pub, report, timeout)
+
+			var temp TemporaryError
+			retried := false
+			if errors.As(err, &temp) && temp.Temporary() {
+				backoff := temp.Backoff()
+				if backoff <= 0 {
+					backoff = defaultBackoff
+				}
+				if timeout > 0 && backoff > timeout {
+					backoff = timeout
+				}
+				retried = true
+				select {
+				case <-time.After(backoff):
+					err = publishWithTimeout(ctx, pub, report, timeout)
+				case <-ctx.Done():
+					err = ctx.Err()
+				}
+			}
+			outcomes[i] = outcome{name: name, err: err, retried: retried}
+		}(i, pub)
+	}
+	wg.Wait()
+
+	result := &DeliveryResult{}
+	for _, o := range outcomes {
+		if o.retried {
+			result.Retried = append(result.Retried, o.name)
+		}
+		if o.err != nil {
+			result.Failures = append(result.Failures, DeliveryFailure{Destination: o.name, Err: o.err, Error: SafeError(o.err)})
+		} else {
+			result.Successes = append(result.Successes, o.name)
+		}
+	}
+	result.Duration = time.Since(start)
+	return result
+}
+
+// publishWithTimeout sends the report, giving up after timeout. Publishers
+// don't take a context, so a timed out send is abandoned rather than cancelled.
+func publishWithTimeout(ctx context.Context, pub Publisher, report Report, timeout time.Duration) error {
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		if cp, ok := pub.(ContextPublisher); ok {
+			done <- cp.PublishReportContext(ctx, report)
+			return
+		}
+		done <- PublishTo(pub, report)
+	}()
+
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			return fmt.Errorf("%w after %s", ErrTimeout, timeout)
+		}
+		return ctx.Err()
+	}
+}
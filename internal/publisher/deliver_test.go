@@ -0,0 +1,206 @@
+package publisher
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+type fakePublisher struct {
+	name  string
+	delay time.Duration
+	errs  []error // Returned in order, one per call; nil once exhausted
+	calls atomic.Int32
+}
+
+func (p *fakePublisher) Name() string { return p.name }
+
+func (p *fakePublisher) Publish(message string) error {
+	call := int(p.calls.Add(1)) - 1
+	time.Sleep(p.delay)
+	if call < len(p.errs) {
+		return p.errs[call]
+	}
+	return nil
+}
+
+type rateLimitError struct{}
+
+func (rateLimitError) Error() string          { return "rate limited" }
+func (rateLimitError) Temporary() bool        { return true }
+func (rateLimitError) Backoff() time.Duration { return time.Millisecond }
+
+func TestDeliver_AggregatesResults(t *testing.T) {
+	ok := &fakePublisher{name: "discord"}
+	failing := &fakePublisher{name: "email", errs: []error{errors.New("smtp down")}}
+	limited := &fakePublisher{name: "telegram", errs: []error{rateLimitError{}}}
+
+	result := Deliver(context.Background(), []Publisher{ok, failing, limited}, Report{Message: "wrap"}, time.Second)
+
+	if strings.Join(result.Successes, ",") != "discord,telegram" {
+		t.Errorf("Successes: got %v", result.Successes)
+	}
+	if len(result.Failures) != 1 || result.Failures[0].Destination != "email" {
+		t.Errorf("Failures: got %+v", result.Failures)
+	}
+	if strings.Join(result.Retried, ",") != "telegram" || limited.calls.Load() != 2 {
+		t.Errorf("Retried: got %v after %d calls", result.Retried, limited.calls.Load())
+	}
+	if failing.calls.Load() != 1 {
+		t.Errorf("permanent errors should not be retried, got %d calls", failing.calls.Load())
+	}
+	if got := result.Summary(); got != "2/3 destinations succeeded (1 retried); failed: email" {
+		t.Errorf("Summary: got %q", got)
+	}
+	if err := result.Err(); err == nil || !strings.Contains(err.Error(), "email: smtp down") {
+		t.Errorf("Err: got %v", err)
+	}
+}
+
+func TestDeliver_RunsConcurrentlyWithTimeout(t *testing.T) {
+	slow := &fakePublisher{name: "slow", delay: 200 * time.Millisecond}
+	fast := &fakePublisher{name: "fast", delay: 10 * time.Millisecond}
+	fast2 := &fakePublisher{name: "fast2", delay: 10 * time.Millisecond}
+
+	start := time.Now()
+	result := Deliver(context.Background(), []Publisher{slow, fast, fast2}, Report{Message: "wrap"}, 50*time.Millisecond)
+	elapsed := time.Since(start)
+
+	if elapsed > 150*time.Millisecond {
+		t.Errorf("expected delivery to finish around the timeout, took %s", elapsed)
+	}
+	if len(result.Failures) != 1 || !errors.Is(result.Failures[0].Err, ErrTimeout) {
+		t.Errorf("expected slow destination to time out, got %+v", result.Failures)
+	}
+	if len(result.Successes) != 2 {
+		t.Errorf("Successes: got %v", result.Successes)
+	}
+}
+
+type unnamedPublisher struct{}
+
+func (unnamedPublisher) Publish(message string) error { return nil }
+
+func TestSafeError_LeavesOutRequestURLs(t *testing.T) {
+	urlErr := &url.Error{Op: "Post", URL: "https://api.telegram.org/bot123:SECRET/sendMessage", Err: errors.New("connection reset")}
+	err := errors.Join(fmt.Errorf("telegram: failed to send message: %w", urlErr), errors.New("discord: 500"))
+
+	got := SafeError(err)
+	if strings.Contains(got, "SECRET") || strings.Contains(got, "api.telegram.org") {
+		t.Errorf("expected the URL to be left out, got %q", got)
+	}
+	if want := "telegram: failed to send message: connection reset\ndiscord: 500"; got != want {
+		t.Errorf("SafeError: got %q, want %q", got, want)
+	}
+
+	result := Deliver(context.Background(), []Publisher{&fakePublisher{name: "telegram", errs: []error{urlErr}}}, Report{Message: "wrap"}, time.Second)
+	if len(result.Failures) != 1 || result.Failures[0].Error != "connection reset" {
+		t.Errorf("failures: got %+v, want the cause without the URL", result.Failures)
+	}
+}
+
+func TestName_FallsBackToType(t *testing.T) {
+	if got := Name(&unnamedPublisher{}); got != "publisher.unnamedpublisher" {
+		t.Errorf("Name: got %q", got)
+	}
+}
+
+type documentPublisher struct {
+	fakePublisher
+	got atomic.Pointer[Report]
+}
+
+func (p *documentPublisher) PublishReport(report Report) error {
+	p.got.Store(&report)
+	return nil
+}
+
+func TestDeliver_RendersDocumentOnce(t *testing.T) {
+	first := &documentPublisher{fakePublisher: fakePublisher{name: "matrix"}}
+	second := &documentPublisher{fakePublisher: fakePublisher{name: "whatsapp"}}
+
+	result := Deliver(context.Background(), []Publisher{first, second}, Report{Message: "📊 **Wrap**"}, time.Second)
+	if err := result.Err(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	a, b := first.got.Load(), second.got.Load()
+	if a == nil || b == nil || a.Document == nil {
+		t.Fatalf("expected both publishers to get a rendered document")
+	}
+	if a.Document != b.Document {
+		t.Errorf("expected every channel to format the same document")
+	}
+	if span := a.Document.Lines[0][1]; span.Text != "Wrap" || !span.Bold {
+		t.Errorf("unexpected document: %+v", a.Document.Lines)
+	}
+}
+
+type contextPublisher struct {
+	documentPublisher
+	deadline atomic.Bool
+}
+
+func (p *contextPublisher) PublishReportContext(ctx context.Context, report Report) error {
+	_, ok := ctx.Deadline()
+	p.deadline.Store(ok)
+	return nil
+}
+
+func TestDeliver_PassesDeadlineToContextPublishers(t *testing.T) {
+	pub := &contextPublisher{documentPublisher: documentPublisher{fakePublisher: fakePublisher{name: "telegram"}}}
+
+	result := Deliver(context.Background(), []Publisher{pub}, Report{Message: "hello"}, time.Second)
+	if err := result.Err(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !pub.deadline.Load() {
+		t.Error("expected the delivery timeout as the context's deadline")
+	}
+	if pub.got.Load() != nil {
+		t.Error("expected PublishReportContext to be used instead of PublishReport")
+	}
+}
+
+func TestDeliver_SendsVariants(t *testing.T) {
+	family := &documentPublisher{fakePublisher: fakePublisher{name: "telegram:-100"}}
+	personal := &documentPublisher{fakePublisher: fakePublisher{name: "email"}}
+	report := Report{
+		Message:  "Spent $100",
+		Variants: map[string]Report{"telegram:-100": {Message: "Spent ₹8,320"}},
+	}
+
+	if err := Deliver(context.Background(), []Publisher{family, personal}, report, time.Second).Err(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := family.got.Load(); got == nil || got.Message != "Spent ₹8,320" || got.Document == nil {
+		t.Errorf("expected the variant with its document, got %+v", got)
+	}
+	if got := personal.got.Load(); got == nil || got.Message != "Spent $100" {
+		t.Errorf("expected the report itself, got %+v", got)
+	}
+}
+
+func TestDeliver_SendsChatVariantToTopics(t *testing.T) {
+	topic := &documentPublisher{fakePublisher: fakePublisher{name: "telegram:-100/7"}}
+	other := &documentPublisher{fakePublisher: fakePublisher{name: "telegram:-200/7"}}
+	report := Report{
+		Message:  "Spent $100",
+		Variants: map[string]Report{"telegram:-100": {Message: "Spent ₹8,320"}},
+	}
+
+	if err := Deliver(context.Background(), []Publisher{topic, other}, report, time.Second).Err(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := topic.got.Load(); got == nil || got.Message != "Spent ₹8,320" {
+		t.Errorf("expected the chat's variant for its topic, got %+v", got)
+	}
+	if got := other.got.Load(); got == nil || got.Message != "Spent $100" {
+		t.Errorf("expected the report itself, got %+v", got)
+	}
+}
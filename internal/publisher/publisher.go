@@ -1,6 +1,84 @@
 package publisher
 
+import (
+	"context"
+	"strings"
+
+	"github.com/sathyabhat/ynab-weekly-wrap/internal/processor"
+	"github.com/sathyabhat/ynab-weekly-wrap/internal/render"
+	"github.com/sathyabhat/ynab-weekly-wrap/internal/ynab"
+)
+
 // Publisher defines the interface for sending messages to various platforms
 type Publisher interface {
 	Publish(message string) error
 }
+
+// Report is a formatted wrap together with the analysis it was rendered from,
+// so publishers can build platform-native layouts from the underlying data
+type Report struct {
+	Title    string
+	Message  string
+	Analysis *processor.AnalysisResult
+	Document *render.Document // Message rendered once for per-channel formatting
+
+	// The period's transactions, for publishers that attach them
+	Transactions []ynab.Transaction
+
+	// Destination name -> the report rendered for it instead, e.g. with
+	// amounts in another currency
+	Variants map[string]Report
+}
+
+// For returns the report to send to a destination: its variant if it has
+// one, otherwise the report itself
+func (r Report) For(destination string) Report {
+	for _, key := range SettingKeys(destination) {
+		if variant, ok := r.Variants[key]; ok {
+			variant.Document = variant.Doc()
+			return variant
+		}
+	}
+	return r
+}
+
+// SettingKeys returns the names a destination's own settings may be given
+// under, most specific first. A Telegram topic, named telegram:<chat>/<topic>,
+// also takes the settings given for its chat.
+func SettingKeys(destination string) []string {
+	if chat, _, ok := strings.Cut(destination, "/"); ok {
+		return []string{destination, chat}
+	}
+	return []string{destination}
+}
+
+// Doc returns the report's document, parsing the message if it hasn't been
+// rendered yet
+func (r Report) Doc() *render.Document {
+	if r.Document != nil {
+		return r.Document
+	}
+	return render.Parse(r.Message)
+}
+
+// ReportPublisher is implemented by publishers that render the analysis
+// themselves instead of sending the pre-formatted message
+type ReportPublisher interface {
+	Publisher
+	PublishReport(report Report) error
+}
+
+// ContextPublisher is implemented by publishers that retry on their own, so
+// their waits between attempts can stop at the delivery timeout
+type ContextPublisher interface {
+	ReportPublisher
+	PublishReportContext(ctx context.Context, report Report) error
+}
+
+// PublishTo sends the report using the richest method the publisher supports
+func PublishTo(pub Publisher, report Report) error {
+	if rp, ok := pub.(ReportPublisher); ok {
+		return rp.PublishReport(report)
+	}
+	return pub.Publish(report.Message)
+}
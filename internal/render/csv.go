@@ -0,0 +1,57 @@
+package render
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/sathyabhat/ynab-weekly-wrap/internal/ynab"
+)
+
+// TransactionsCSV writes transactions as a spreadsheet-friendly CSV with a
+// header row, oldest first. Amounts are in currency units with outflows
+// negative, as in YNAB.
+func TransactionsCSV(transactions []ynab.Transaction) ([]byte, error) {
+	sorted := slices.Clone(transactions)
+	slices.SortStableFunc(sorted, func(a, b ynab.Transaction) int {
+		if a.Date == nil || b.Date == nil {
+			return 0
+		}
+		return a.Date.Compare(*b.Date)
+	})
+
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	w.Write([]string{"Date", "Payee", "Category", "Amount", "Memo"})
+	for _, t := range sorted {
+		if t.Deleted {
+			continue
+		}
+		date := ""
+		if t.Date != nil {
+			date = t.Date.Format("2006-01-02")
+		}
+		w.Write([]string{
+			date,
+			cell(t.PayeeName),
+			cell(t.CategoryName),
+			fmt.Sprintf("%.2f", float64(t.Amount)/1000),
+			cell(t.Memo),
+		})
+	}
+	w.Flush()
+	if err := w.Error(); err != nil {
+		return nil, fmt.Errorf("failed to write transactions CSV: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// cell stops spreadsheets treating text from payees and memos as a formula
+func cell(text string) string {
+	if text != "" && strings.ContainsRune("=+-@", rune(text[0])) {
+		return "'" + text
+	}
+	return text
+}
@@ -0,0 +1,33 @@
+package render
+
+import (
+	"testing"
+	"time"
+
+	"github.com/sathyabhat/ynab-weekly-wrap/internal/ynab"
+)
+
+func TestTransactionsCSV(t *testing.T) {
+	day := func(d int) *time.Time {
+		date := time.Date(2026, 6, d, 0, 0, 0, 0, time.UTC)
+		return &date
+	}
+	transactions := []ynab.Transaction{
+		{Date: day(3), PayeeName: "Corner Cafe", CategoryName: "Dining Out", Amount: -12_500, Memo: "lunch, with Sam"},
+		{Date: day(1), PayeeName: "Employer", CategoryName: "Inflow: Ready to Assign", Amount: 2_000_000},
+		{Date: day(2), PayeeName: "Refunded", Amount: -5_000, Deleted: true},
+		{Date: day(2), PayeeName: "=HYPERLINK(\"x\")", CategoryName: "Groceries", Amount: -40_120},
+	}
+
+	got, err := TransactionsCSV(transactions)
+	if err != nil {
+		t.Fatalf("TransactionsCSV failed: %v", err)
+	}
+	want := "Date,Payee,Category,Amount,Memo\n" +
+		"2026-06-01,Employer,Inflow: Ready to Assign,2000.00,\n" +
+		"2026-06-02,\"'=HYPERLINK(\"\"x\"\")\",Groceries,-40.12,\n" +
+		"2026-06-03,Corner Cafe,Dining Out,-12.50,\"lunch, with Sam\"\n"
+	if string(got) != want {
+		t.Errorf("TransactionsCSV:\n got %q\nwant %q", got, want)
+	}
+}
@@ -0,0 +1,150 @@
+package render
+
+import (
+	"html"
+	"regexp"
+	"strings"
+)
+
+var boldPattern = regexp.MustCompile(`\*\*(.+?)\*\*`)
+
+// Span is a run of text with the same emphasis. Parse only produces Bold;
+// documents built directly can also use Italic and Code.
+type Span struct {
+	Text   string
+	Bold   bool
+	Italic bool
+	Code   bool // Monospace, e.g. for amounts that should line up
+}
+
+// Line is a single line of a document
+type Line []Span
+
+// Document is a report rendered once in a channel-neutral form. Each channel
+// turns it into its own markup with a Markup.
+type Document struct {
+	Lines []Line
+}
+
+// Parse builds a document from the **bold** markup the scheduler's formatters
+// write. Stray markers that don't close on the same line are dropped.
+func Parse(message string) *Document {
+	doc := &Document{}
+	for _, text := range strings.Split(message, "\n") {
+		var line Line
+		last := 0
+		for _, m := range boldPattern.FindAllStringSubmatchIndex(text, -1) {
+			if m[0] > last {
+				line = append(line, Span{Text: stripMarkers(text[last:m[0]])})
+			}
+			line = append(line, Span{Text: text[m[2]:m[3]], Bold: true})
+			last = m[1]
+		}
+		if last < len(text) {
+			line = append(line, Span{Text: stripMarkers(text[last:])})
+		}
+		doc.Lines = append(doc.Lines, line)
+	}
+	return doc
+}
+
+func stripMarkers(text string) string {
+	return strings.ReplaceAll(text, "**", "")
+}
+
+// Markup describes how a channel writes emphasis and line breaks. Emphasis
+// a channel has no markup for is left out.
+type Markup struct {
+	BoldOpen    string
+	BoldClose   string
+	ItalicOpen  string
+	ItalicClose string
+	CodeOpen    string
+	CodeClose   string
+	LineBreak   string
+	Escape      func(string) string // Applied to text before markup is added; nil leaves text as-is
+}
+
+var (
+	// Markdown is the **bold** markup used by chat platforms such as Discord
+	Markdown = Markup{BoldOpen: "**", BoldClose: "**", ItalicOpen: "_", ItalicClose: "_", CodeOpen: "`", CodeClose: "`", LineBreak: "\n"}
+
+	// MarkdownFile is Markdown for files such as journal entries and notes,
+	// ending lines with a hard break so they aren't joined into paragraphs
+	MarkdownFile = Markup{BoldOpen: "**", BoldClose: "**", ItalicOpen: "_", ItalicClose: "_", CodeOpen: "`", CodeClose: "`", LineBreak: "  \n"}
+
+	// Plain drops all emphasis
+	Plain = Markup{LineBreak: "\n"}
+
+	// WhatsApp only knows single-asterisk bold
+	WhatsApp = Markup{BoldOpen: "*", BoldClose: "*", ItalicOpen: "_", ItalicClose: "_", CodeOpen: "```", CodeClose: "```", LineBreak: "\n"}
+
+	// HTML escapes text and uses <strong> and <br>
+	HTML = Markup{
+		BoldOpen: "<strong>", BoldClose: "</strong>", ItalicOpen: "<em>", ItalicClose: "</em>",
+		CodeOpen: "<code>", CodeClose: "</code>", LineBreak: "<br>\n", Escape: html.EscapeString,
+	}
+
+	// MarkdownV2 is Telegram's strict markdown, where every reserved
+	// character in the text has to be escaped
+	MarkdownV2 = Markup{
+		BoldOpen: "*", BoldClose: "*", ItalicOpen: "_", ItalicClose: "_",
+		CodeOpen: "`", CodeClose: "`", LineBreak: "\n", Escape: EscapeMarkdownV2,
+	}
+
+	// TelegramHTML is Telegram's HTML parse mode. It only needs <, > and &
+	// escaped, which makes it the safest mode for arbitrary payee names,
+	// and takes plain newlines rather than <br>.
+	TelegramHTML = Markup{
+		BoldOpen: "<b>", BoldClose: "</b>", ItalicOpen: "<i>", ItalicClose: "</i>",
+		CodeOpen: "<code>", CodeClose: "</code>", LineBreak: "\n", Escape: EscapeTelegramHTML,
+	}
+)
+
+var markdownV2Escaper = strings.NewReplacer(
+	`\`, `\\`, "_", `\_`, "*", `\*`, "[", `\[`, "]", `\]`, "(", `\(`, ")", `\)`,
+	"~", `\~`, "`", "\\`", ">", `\>`, "#", `\#`, "+", `\+`, "-", `\-`, "=", `\=`,
+	"|", `\|`, "{", `\{`, "}", `\}`, ".", `\.`, "!", `\!`,
+)
+
+// EscapeMarkdownV2 backslash-escapes the characters Telegram reserves in
+// MarkdownV2, so payee and category names like "7-Eleven *Store" are sent
+// as literal text
+func EscapeMarkdownV2(text string) string {
+	return markdownV2Escaper.Replace(text)
+}
+
+var telegramHTMLEscaper = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;")
+
+// EscapeTelegramHTML escapes the characters Telegram's HTML parse mode
+// reserves, using only the entities it understands
+func EscapeTelegramHTML(text string) string {
+	return telegramHTMLEscaper.Replace(text)
+}
+
+// Format writes the document in this markup
+func (m Markup) Format(doc *Document) string {
+	var b strings.Builder
+	for i, line := range doc.Lines {
+		if i > 0 {
+			b.WriteString(m.LineBreak)
+		}
+		for _, span := range line {
+			text := span.Text
+			if m.Escape != nil {
+				text = m.Escape(text)
+			}
+			if span.Code {
+				text = m.CodeOpen + text + m.CodeClose
+			}
+			if span.Italic {
+				text = m.ItalicOpen + text + m.ItalicClose
+			}
+			if span.Bold {
+				text = m.BoldOpen + text + m.BoldClose
+			}
+			b.WriteString(text)
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,78 @@
+package render
+
+import (
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	doc := Parse("📊 **Weekly Wrap**\n• **Dining**: $40 **\n\nplain")
+
+	if len(doc.Lines) != 4 {
+		t.Fatalf("lines: got %d, want 4", len(doc.Lines))
+	}
+	want := Line{{Text: "📊 "}, {Text: "Weekly Wrap", Bold: true}}
+	if len(doc.Lines[0]) != 2 || doc.Lines[0][0] != want[0] || doc.Lines[0][1] != want[1] {
+		t.Errorf("line 0: got %+v", doc.Lines[0])
+	}
+	if last := doc.Lines[1][len(doc.Lines[1])-1]; last.Text != ": $40 " || last.Bold {
+		t.Errorf("expected stray marker to be dropped, got %+v", last)
+	}
+	if len(doc.Lines[2]) != 0 {
+		t.Errorf("expected blank line, got %+v", doc.Lines[2])
+	}
+}
+
+func TestMarkup_Format(t *testing.T) {
+	doc := Parse("📊 **Weekly Wrap**\nSpent <$100> & **more**")
+
+	cases := []struct {
+		name   string
+		markup Markup
+		want   string
+	}{
+		{"markdown", Markdown, "📊 **Weekly Wrap**\nSpent <$100> & **more**"},
+		{"plain", Plain, "📊 Weekly Wrap\nSpent <$100> & more"},
+		{"whatsapp", WhatsApp, "📊 *Weekly Wrap*\nSpent <$100> & *more*"},
+		{"html", HTML, "📊 <strong>Weekly Wrap</strong><br>\nSpent &lt;$100&gt; &amp; <strong>more</strong>"},
+		{"markdownv2", MarkdownV2, "📊 *Weekly Wrap*\nSpent <$100\\> & *more*"},
+		{"telegram html", TelegramHTML, "📊 <b>Weekly Wrap</b>\nSpent &lt;$100&gt; &amp; <b>more</b>"},
+	}
+	for _, tc := range cases {
+		if got := tc.markup.Format(doc); got != tc.want {
+			t.Errorf("%s: got %q, want %q", tc.name, got, tc.want)
+		}
+	}
+}
+
+func TestMarkup_FormatItalicAndCode(t *testing.T) {
+	doc := &Document{Lines: []Line{{
+		{Text: "Dining <Out>", Bold: true},
+		{Text: " "},
+		{Text: "$12.50", Code: true},
+		{Text: " "},
+		{Text: "as of Monday", Italic: true},
+	}}}
+
+	cases := []struct {
+		name   string
+		markup Markup
+		want   string
+	}{
+		{"telegram html", TelegramHTML, "<b>Dining &lt;Out&gt;</b> <code>$12.50</code> <i>as of Monday</i>"},
+		{"markdownv2", MarkdownV2, "*Dining <Out\\>* `$12\\.50` _as of Monday_"},
+		{"plain", Plain, "Dining <Out> $12.50 as of Monday"},
+	}
+	for _, tc := range cases {
+		if got := tc.markup.Format(doc); got != tc.want {
+			t.Errorf("%s: got %q, want %q", tc.name, got, tc.want)
+		}
+	}
+}
+
+func TestEscapeMarkdownV2(t *testing.T) {
+	got := MarkdownV2.Format(Parse("**7-Eleven *Store**: $12.50 (snacks_and_drinks)!"))
+	want := `*7\-Eleven \*Store*: $12\.50 \(snacks\_and\_drinks\)\!`
+	if got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
@@ -1,28 +1,70 @@
 package scheduler
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"log"
 	"strings"
+	"text/template"
 	"time"
 
 	"github.com/robfig/cron/v3"
+	"github.com/sathyabhat/ynab-weekly-wrap/internal/archive"
 	"github.com/sathyabhat/ynab-weekly-wrap/internal/config"
 	"github.com/sathyabhat/ynab-weekly-wrap/internal/discord"
+	"github.com/sathyabhat/ynab-weekly-wrap/internal/email"
+	"github.com/sathyabhat/ynab-weekly-wrap/internal/gotify"
+	"github.com/sathyabhat/ynab-weekly-wrap/internal/history"
+	"github.com/sathyabhat/ynab-weekly-wrap/internal/i18n"
+	"github.com/sathyabhat/ynab-weekly-wrap/internal/insights"
+	"github.com/sathyabhat/ynab-weekly-wrap/internal/journal"
+	"github.com/sathyabhat/ynab-weekly-wrap/internal/matrix"
+	"github.com/sathyabhat/ynab-weekly-wrap/internal/metrics"
+	"github.com/sathyabhat/ynab-weekly-wrap/internal/money"
+	"github.com/sathyabhat/ynab-weekly-wrap/internal/notes"
+	"github.com/sathyabhat/ynab-weekly-wrap/internal/oneoff"
+	"github.com/sathyabhat/ynab-weekly-wrap/internal/pause"
+	"github.com/sathyabhat/ynab-weekly-wrap/internal/pipeline"
 	"github.com/sathyabhat/ynab-weekly-wrap/internal/processor"
 	"github.com/sathyabhat/ynab-weekly-wrap/internal/publisher"
+	"github.com/sathyabhat/ynab-weekly-wrap/internal/speech"
+	"github.com/sathyabhat/ynab-weekly-wrap/internal/teams"
 	"github.com/sathyabhat/ynab-weekly-wrap/internal/telegram"
+	"github.com/sathyabhat/ynab-weekly-wrap/internal/theme"
+	"github.com/sathyabhat/ynab-weekly-wrap/internal/vault"
+	"github.com/sathyabhat/ynab-weekly-wrap/internal/webhook"
+	"github.com/sathyabhat/ynab-weekly-wrap/internal/whatsapp"
 	"github.com/sathyabhat/ynab-weekly-wrap/internal/ynab"
 )
 
+// ErrDelivery is returned when one or more publishers failed to send a report
+var ErrDelivery = errors.New("delivery failed")
+
 type Scheduler struct {
 	cron         *cron.Cron
 	config       *config.Config
 	ynabClient   *ynab.Client
 	publishers   []publisher.Publisher
 	analyzer     *processor.Analyzer
+	hooks        []pipeline.Hook
+	exporters    []pipeline.Exporter
 	dryRun       bool
+	dryRunChatID int64
 	skipTelegram bool
+	poller       *telegram.Poller  // Handles compact summary buttons and /note; nil unless enabled
+	notes        *notes.Store      // Category notes shown in each wrap
+	history      *history.Store    // Stored weekly snapshots; nil unless HISTORY_DIR is set
+	sends        *oneoff.Store     // Wraps queued with schedule-once; nil unless SCHEDULE_ONCE_FILE is set
+	pauses       *pause.Store      // Pause of the scheduled sends; nil unless SCHEDULE_PAUSE_FILE is set
+	messages     *i18n.Catalog     // Text of the wraps in the configured locale; nil uses English
+	theme        theme.Theme       // Emoji marking each section
+	metrics      *metrics.Recorder // Stage timings and API latencies for the status endpoint
+	latest       *latestRun        // Last weekly or monthly wrap, for the overview endpoint
+
+	// User-supplied message layouts; nil uses the built-in formatters
+	weeklyTemplate  *template.Template
+	monthlyTemplate *template.Template
 }
 
 // SchedulerOption is a functional option for configuring Scheduler
@@ -35,6 +77,15 @@ func WithDryRun(dryRun bool) SchedulerOption {
 	}
 }
 
+// WithDryRunTo enables dry-run mode but sends the formatted message to the
+// given Telegram chat instead of stdout, so a test chat can preview it
+func WithDryRunTo(chatID int64) SchedulerOption {
+	return func(s *Scheduler) {
+		s.dryRun = true
+		s.dryRunChatID = chatID
+	}
+}
+
 // WithSkipTelegram disables Telegram bot creation (for testing without credentials)
 func WithSkipTelegram(skip bool) SchedulerOption {
 	return func(s *Scheduler) {
@@ -42,266 +93,823 @@ func WithSkipTelegram(skip bool) SchedulerOption {
 	}
 }
 
+// WithHooks registers hooks that observe every stage of the wrap pipelines
+func WithHooks(hooks ...pipeline.Hook) SchedulerOption {
+	return func(s *Scheduler) {
+		s.hooks = append(s.hooks, hooks...)
+	}
+}
+
+// WithExporters adds exporters that run after a wrap has been delivered
+func WithExporters(exporters ...pipeline.Exporter) SchedulerOption {
+	return func(s *Scheduler) {
+		s.exporters = append(s.exporters, exporters...)
+	}
+}
+
+// cronParser accepts standard five-field expressions, an optional leading
+// seconds field, descriptors such as @daily or @every 30m, and a CRON_TZ=
+// prefix to run one job in its own timezone
+var cronParser = cron.NewParser(cron.SecondOptional | cron.Minute | cron.Hour | cron.Dom | cron.Month | cron.Dow | cron.Descriptor)
+
 func NewScheduler(cfg *config.Config, opts ...SchedulerOption) *Scheduler {
-	cronScheduler := cron.New()
+	cronScheduler := cron.New(cron.WithParser(cronParser))
 
+	rules, err := insights.Load(cfg.Insights.RulesFile)
+	if err != nil {
+		log.Fatalf("Failed to load insight rules: %v", err)
+	}
+	analyzer := processor.NewAnalyzer(
+		processor.WithBenchmarks(cfg.Benchmarks.Weekly),
+		processor.WithVelocity(cfg.Thresholds.VelocityDays, cfg.Thresholds.VelocityPercent),
+		processor.WithThresholds(cfg.Thresholds.AtRiskPercent, cfg.Thresholds.OverBudgetPercent),
+		processor.WithWinsCount(cfg.Thresholds.WinsCount),
+		processor.WithSpendingSplit(cfg.Split.Tags, processor.SplitTarget{
+			Needs:   cfg.Split.TargetNeeds,
+			Wants:   cfg.Split.TargetWants,
+			Savings: cfg.Split.TargetSavings,
+		}),
+		processor.WithFiscalMonthStart(cfg.Fiscal.MonthStartDay),
+		processor.WithCategoryAliases(cfg.Categories.Aliases),
+		processor.WithLumpyCategories(cfg.Categories.Lumpy),
+		processor.WithFixedCategories(cfg.Categories.Fixed),
+		processor.WithFixedGoalTypes(cfg.Categories.FixedGoalTypes),
+		processor.WithOffsetRules(cfg.Categories.Offsets),
+		processor.WithSevereOverAmount(cfg.Thresholds.SevereOverAmount),
+		processor.WithTransfers(cfg.Accounts.IncludeTransfers),
+		processor.WithInsightRules(rules),
+		processor.WithSpendingOnly(cfg.Categories.SpendingOnly),
+		processor.WithFocusCategories(cfg.Categories.Focus),
+		processor.WithFlags(cfg.Flags.Exclude, cfg.Flags.Highlight),
+	)
+
+	recorder := metrics.NewRecorder()
+	ynabOpts := []ynab.ClientOption{ynab.WithMetrics(recorder)}
+	if cfg.Categories.PartialFetch {
+		ynabOpts = append(ynabOpts, ynab.WithCategoryScope(cfg.ReferencedCategories()))
+	}
 	sched := &Scheduler{
 		cron:         cronScheduler,
 		config:       cfg,
-		ynabClient:   ynab.NewClient(cfg.YNAB),
-		analyzer:     processor.NewAnalyzer(),
+		ynabClient:   ynab.NewClient(cfg.YNAB, ynabOpts...),
+		analyzer:     analyzer,
+		metrics:      recorder,
+		notes:        notes.NewStore(cfg.Notes.File, cfg.Notes.Categories),
 		dryRun:       false,
 		skipTelegram: false,
 	}
 
+	if cfg.Schedule.OnceFile != "" {
+		sched.sends = oneoff.NewStore(cfg.Schedule.OnceFile, cfg.Schedule.Location())
+	}
+	if cfg.Schedule.PauseFile != "" {
+		sched.pauses = pause.NewStore(cfg.Schedule.PauseFile, cfg.Schedule.Location())
+	}
+
 	// Apply options (which may set dryRun or skipTelegram)
 	for _, opt := range opts {
 		opt(sched)
 	}
 
+	if sched.messages, err = i18n.New(cfg.Locale); err != nil {
+		log.Fatalf("Failed to load messages: %v", err)
+	}
+	if sched.messages, err = sched.messages.Override(cfg.Theme.Titles); err != nil {
+		log.Fatalf("Failed to set section titles: %v", err)
+	}
+	if sched.theme, err = theme.New(cfg.Theme.Name, cfg.Theme.Emoji); err != nil {
+		log.Fatalf("Failed to load theme: %v", err)
+	}
+	if sched.weeklyTemplate, err = sched.loadTemplate(cfg.Templates.WeeklyFile); err != nil {
+		log.Fatalf("Failed to load weekly template %s: %v", cfg.Templates.WeeklyFile, err)
+	}
+	if sched.monthlyTemplate, err = sched.loadTemplate(cfg.Templates.MonthlyFile); err != nil {
+		log.Fatalf("Failed to load monthly template %s: %v", cfg.Templates.MonthlyFile, err)
+	}
+
 	// Only initialize publishers if not in dry-run mode
 	if !sched.dryRun {
 		// Initialize Telegram if configured and not skipped
-		if !sched.skipTelegram && cfg.Telegram.BotToken != "" && cfg.Telegram.ChatID != 0 {
-			telegramBot, err := telegram.NewBot(cfg.Telegram)
-			if err != nil {
-				log.Fatalf("Failed to create Telegram bot: %v", err)
+		if !sched.skipTelegram && cfg.Telegram.BotToken != "" {
+			// One publisher per chat, so a failing chat doesn't block the others
+			var bots []*telegram.Bot
+			botOpts := []telegram.BotOption{telegram.WithMetrics(sched.metrics)}
+			if cfg.Telegram.VoiceSummary {
+				synth, err := speech.New(cfg.Speech)
+				if err != nil {
+					log.Fatalf("Failed to set up voice summaries: %v", err)
+				}
+				botOpts = append(botOpts, telegram.WithSynthesizer(synth))
+			}
+			if sched.notes.Writable() {
+				botOpts = append(botOpts, telegram.WithNotes(sched.notes))
+			}
+			if sched.sends != nil {
+				botOpts = append(botOpts, telegram.WithSends(sched.sends))
+			}
+			if sched.pauses != nil {
+				botOpts = append(botOpts, telegram.WithPauses(sched.pauses))
+			}
+			for _, chat := range cfg.Telegram.Targets() {
+				telegramConfig := cfg.Telegram
+				telegramConfig.ChatID, telegramConfig.TopicID = chat.ChatID, chat.TopicID
+				telegramBot, err := telegram.NewBot(telegramConfig, botOpts...)
+				if err != nil {
+					log.Fatalf("Failed to create Telegram bot: %v", err)
+				}
+				sched.publishers = append(sched.publishers, telegramBot)
+				bots = append(bots, telegramBot)
+				log.Printf("Telegram publisher initialized for chat %d", chat.ChatID)
+			}
+			if cfg.Telegram.Compact || sched.notes.Writable() || sched.sends != nil || sched.pauses != nil {
+				sched.poller = telegram.NewPoller(bots...)
 			}
-			sched.publishers = append(sched.publishers, telegramBot)
-			log.Println("Telegram publisher initialized")
 		}
 
 		// Initialize Discord if configured
 		if cfg.Discord.WebhookURL != "" {
-			discordPublisher := discord.NewWebhookPublisher(cfg.Discord.WebhookURL)
+			discordPublisher := discord.NewWebhookPublisher(cfg.Discord.WebhookURL, cfg.Discord.UseEmbeds)
 			sched.publishers = append(sched.publishers, discordPublisher)
 			log.Println("Discord publisher initialized")
 		}
+
+		// Initialize email if configured
+		if cfg.Email.Host != "" && len(cfg.Email.To) > 0 {
+			sched.publishers = append(sched.publishers, email.NewSMTPPublisher(cfg.Email, email.WithTheme(sched.theme)))
+			log.Println("Email publisher initialized")
+		}
+
+		// Initialize generic webhook if configured
+		if cfg.Webhook.URL != "" {
+			sched.publishers = append(sched.publishers, webhook.NewPublisher(cfg.Webhook.URL, cfg.Webhook.Secret))
+			log.Println("Webhook publisher initialized")
+		}
+
+		// Initialize Matrix if configured
+		if cfg.Matrix.HomeserverURL != "" && cfg.Matrix.AccessToken != "" && cfg.Matrix.RoomID != "" {
+			sched.publishers = append(sched.publishers, matrix.NewPublisher(cfg.Matrix))
+			log.Println("Matrix publisher initialized")
+		}
+
+		// Initialize Teams if configured
+		if cfg.Teams.WebhookURL != "" {
+			sched.publishers = append(sched.publishers, teams.NewWebhookPublisher(cfg.Teams.WebhookURL))
+			log.Println("Teams publisher initialized")
+		}
+
+		// Initialize Gotify if configured
+		if cfg.Gotify.URL != "" && cfg.Gotify.Token != "" {
+			sched.publishers = append(sched.publishers, gotify.NewPublisher(cfg.Gotify))
+			log.Println("Gotify publisher initialized")
+		}
+
+		// Initialize WhatsApp if configured
+		if cfg.WhatsApp.PhoneNumberID != "" && cfg.WhatsApp.Token != "" && cfg.WhatsApp.Recipient != "" {
+			sched.publishers = append(sched.publishers, whatsapp.NewPublisher(cfg.WhatsApp))
+			log.Println("WhatsApp publisher initialized")
+		}
+		warnUnmatchedCurrencies(cfg.Delivery.Currencies, sched.publishers)
 	}
 
+	// Keep a snapshot of every real weekly run so it can be re-rendered later
+	if cfg.History.Dir != "" {
+		sched.history = history.NewStore(cfg.History.Dir)
+		if !sched.dryRun {
+			sched.exporters = append(sched.exporters, sched.history)
+			log.Printf("Storing weekly snapshots in %s", cfg.History.Dir)
+		}
+	}
+	if cfg.Archive.Dir != "" && !sched.dryRun {
+		sched.exporters = append(sched.exporters, archive.New(cfg.Archive.Dir))
+		log.Printf("Archiving weekly figures in %s", cfg.Archive.Dir)
+	}
+	if cfg.Journal.Repo != "" && !sched.dryRun {
+		sched.exporters = append(sched.exporters, journal.New(cfg.Journal))
+		log.Printf("Committing wraps to the journal in %s", cfg.Journal.Repo)
+	}
+	if cfg.Vault.Dir != "" && !sched.dryRun {
+		sched.exporters = append(sched.exporters, vault.New(cfg.Vault.Dir))
+		log.Printf("Writing wrap notes to %s", cfg.Vault.Dir)
+	}
+	sched.latest = &latestRun{}
+	sched.exporters = append(sched.exporters, sched.latest)
+
 	return sched
 }
 
-func (s *Scheduler) Start() error {
-	log.Printf("Starting scheduler with cron expression: %s", s.config.Schedule.Cron)
+// warnUnmatchedCurrencies logs the display currencies given for a
+// destination none of the publishers is named, which would otherwise be
+// ignored without a word
+func warnUnmatchedCurrencies(currencies map[string]config.DisplayCurrency, publishers []publisher.Publisher) {
+	names := make(map[string]bool)
+	for _, pub := range publishers {
+		for _, key := range publisher.SettingKeys(publisher.Name(pub)) {
+			names[key] = true
+		}
+	}
+	for destination := range currencies {
+		if !names[destination] {
+			log.Printf("Warning: DISPLAY_CURRENCY is set for %s, which matches no destination", destination)
+		}
+	}
+}
 
+func (s *Scheduler) Start() error {
 	// Add weekly wrap job
-	_, err := s.cron.AddFunc(s.config.Schedule.Cron, s.runWeeklyWrap)
-	if err != nil {
+	if err := s.schedule("Weekly wrap", s.config.Schedule.Cron, s.config.Schedule.Timezone, s.runWeeklyWrap); err != nil {
 		return err
 	}
 
 	// Add monthly wrap job
-	log.Printf("Registering monthly wrap with cron expression: %s", s.config.Schedule.MonthlyCron)
-	_, err = s.cron.AddFunc(s.config.Schedule.MonthlyCron, s.runMonthlyWrap)
-	if err != nil {
+	if err := s.schedule("Monthly wrap", s.config.Schedule.MonthlyCron, s.config.Schedule.MonthlyTimezone, s.runMonthlyWrap); err != nil {
 		return err
 	}
 
+	// Add the daily digest if it has a schedule
+	if s.config.Schedule.DailyCron != "" {
+		if err := s.schedule("Daily digest", s.config.Schedule.DailyCron, s.config.Schedule.Timezone, s.runDailyDigest); err != nil {
+			return err
+		}
+	}
+
+	// Send the agenda ahead of the budget meeting if one is set
+	if spec := s.config.Meeting.Cron(); spec != "" {
+		if err := s.schedule("Meeting agenda", spec, s.config.Schedule.Timezone, s.runMeetingAgenda); err != nil {
+			return err
+		}
+	}
+
+	// Add critical alert checks if any are enabled
+	if s.config.Alerts.Enabled() {
+		if err := s.schedule("Alert check", s.config.Alerts.Cron, s.config.Alerts.Timezone, s.runAlertChecks); err != nil {
+			return err
+		}
+	}
+
+	// Send wraps queued with schedule-once, checking for due ones each minute
+	if s.sends != nil {
+		s.cron.AddFunc("@every 1m", s.runDueSends)
+		log.Printf("Checking %s for scheduled sends", s.config.Schedule.OnceFile)
+	}
+
+	// Resume from a pause once it ends, checking each minute
+	if s.pauses != nil {
+		s.cron.AddFunc("@every 1m", s.resumeEnded)
+		log.Printf("Checking %s for a pause of scheduled sends", s.config.Schedule.PauseFile)
+	}
+
 	// Start the cron scheduler
 	s.cron.Start()
 
+	// Answer taps on the compact summary's buttons
+	if s.poller != nil {
+		go s.poller.Run(context.Background())
+	}
+
 	log.Println("Scheduler started successfully")
 	return nil
 }
 
 // RunOnce runs the weekly wrap job once (useful for testing/dry-run)
-func (s *Scheduler) RunOnce() {
-	s.runWeeklyWrap()
+func (s *Scheduler) RunOnce() error {
+	return s.runWeeklyWrap()
 }
 
 // RunMonthlyOnce runs the monthly wrap job once (useful for testing/dry-run)
-func (s *Scheduler) RunMonthlyOnce() {
-	s.runMonthlyWrap()
+func (s *Scheduler) RunMonthlyOnce() error {
+	return s.runMonthlyWrap()
 }
 
-func (s *Scheduler) runWeeklyWrap() {
-	log.Println("Running weekly wrap...")
+// RunDailyOnce runs the daily digest once (useful for testing/dry-run)
+func (s *Scheduler) RunDailyOnce() error {
+	return s.runDailyDigest()
+}
 
-	// Get current date and calculate week range
-	now := time.Now()
-	weekEnd := now
-	weekStart := now.AddDate(0, 0, -7)
+// RunMeetingOnce sends the budget meeting agenda once (useful for testing/dry-run)
+func (s *Scheduler) RunMeetingOnce() error {
+	return s.runMeetingAgenda()
+}
 
-	log.Printf("Processing week from %s to %s", weekStart.Format("2006-01-02"), weekEnd.Format("2006-01-02"))
+// RunAlertsOnce runs the critical alert checks once (useful for testing/dry-run)
+func (s *Scheduler) RunAlertsOnce() error {
+	return s.runAlertChecks()
+}
 
-	// Get weekly data from YNAB
-	data, err := s.ynabClient.GetWeeklyData(weekStart, weekEnd)
+// Notify sends an ad-hoc message from another script through the configured
+// publishers, with a bold title line when title isn't empty. Each channel
+// escapes the text for its own markup; **bold** in it stays bold.
+func (s *Scheduler) Notify(title, message string) error {
+	message = strings.TrimSpace(message)
+	if message == "" {
+		return fmt.Errorf("notification is empty")
+	}
+	if title != "" {
+		message = fmt.Sprintf("**%s**\n\n%s", title, message)
+	} else {
+		title = "Notification"
+	}
+	return s.deliver("Notification", publisher.Report{
+		Title:   title,
+		Message: message + "\n",
+	})
+}
+
+// schedule registers a job to run on spec in timezone, or in the local
+// timezone when it is empty. A CRON_TZ= prefix in spec takes precedence.
+func (s *Scheduler) schedule(name, spec, timezone string, job func() error) error {
+	if timezone != "" && !strings.HasPrefix(spec, "CRON_TZ=") && !strings.HasPrefix(spec, "TZ=") {
+		spec = "CRON_TZ=" + timezone + " " + spec
+	}
+	schedule, err := cronParser.Parse(spec)
 	if err != nil {
-		log.Printf("Failed to get weekly data: %v", err)
+		return fmt.Errorf("invalid cron expression for %s %q: %w", strings.ToLower(name), spec, err)
+	}
+
+	s.cron.Schedule(schedule, cron.FuncJob(s.runJob(name, s.unlessPaused(name, job))))
+	log.Printf("Registered %s with cron expression %q, next run at %s", strings.ToLower(name), spec, schedule.Next(time.Now()).Format(time.RFC1123))
+	return nil
+}
+
+// runJob wraps a scheduled job so transient failures are retried and a
+// failure that persists is reported to the configured publishers
+func (s *Scheduler) runJob(label string, job func() error) func() {
+	return func() {
+		err := s.retry(label, job)
+		if err == nil {
+			return
+		}
+		log.Printf("%s failed: %v", label, err)
+		s.alertFailure(label, err)
+	}
+}
+
+// retry runs job up to the configured number of attempts, backing off
+// linearly between them. Errors that won't go away by themselves (bad
+// credentials, missing data) and delivery errors are returned straight away;
+// retrying after a partial delivery would send duplicates.
+func (s *Scheduler) retry(label string, job func() error) error {
+	attempts := max(s.config.Schedule.RetryAttempts, 1)
+
+	var err error
+	for attempt := 1; attempt <= attempts; attempt++ {
+		err = job()
+		if err == nil || !isRetryable(err) {
+			return err
+		}
+		if attempt < attempts {
+			delay := s.config.Schedule.RetryDelay * time.Duration(attempt)
+			log.Printf("%s attempt %d/%d failed, retrying in %s: %v", label, attempt, attempts, delay, err)
+			time.Sleep(delay)
+		}
+	}
+	return err
+}
+
+func isRetryable(err error) bool {
+	return !errors.Is(err, ynab.ErrYNABAuth) &&
+		!errors.Is(err, ynab.ErrNoData) &&
+		!errors.Is(err, ErrDelivery)
+}
+
+// alertFailure tells the publishers that a job failed, unless the publishers
+// themselves are what failed
+func (s *Scheduler) alertFailure(label string, err error) {
+	if errors.Is(err, ErrDelivery) {
 		return
 	}
 
-	// Analyze the data
-	topCategoriesLimit := s.config.Thresholds.TopCategoriesCount
-	analysis, err := s.analyzer.AnalyzeWeeklyData(data, topCategoriesLimit)
+	message := fmt.Sprintf("%s**%s failed**\n\n%s\n", s.theme.Icon("warning"), label, failureReason(err))
+	if deliverErr := s.deliver(label+" failure alert", publisher.Report{
+		Title:   label + " failed",
+		Message: message,
+	}); deliverErr != nil {
+		log.Printf("Failed to send failure alert: %v", deliverErr)
+	}
+}
+
+// failureReason explains a job failure in terms the reader can act on
+func failureReason(err error) string {
+	switch {
+	case errors.Is(err, ynab.ErrYNABAuth):
+		return "YNAB rejected the API token. Check that YNAB_API_TOKEN is valid and hasn't been revoked."
+	case errors.Is(err, ynab.ErrNoData):
+		return "YNAB returned no data. Check that YNAB_BUDGET_ID points at the right budget."
+	default:
+		return err.Error()
+	}
+}
+
+// runAlertChecks evaluates the enabled critical alerts and sends any that fire
+// straight away rather than waiting for the next wrap. A failing check does
+// not stop the others; its error is returned after the rest are delivered.
+func (s *Scheduler) runAlertChecks() error {
+	log.Println("Running alert checks...")
+
+	now := time.Now()
+	var sections []string
+	var errs []error
+
+	// Alerts don't fetch the budget, so ask for its currency separately
+	currency, err := s.ynabClient.GetCurrency()
 	if err != nil {
-		log.Printf("Failed to analyze data: %v", err)
-		return
+		log.Printf("Warning: could not fetch the budget's currency, showing dollars: %v", err)
 	}
 
-	// Format the message
-	message := s.formatMessage(analysis)
+	if s.config.Alerts.OverdraftEnabled {
+		section, err := s.checkOverdraftRisk(now, currency)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("failed to check overdraft risk: %w", err))
+		} else if section != "" {
+			sections = append(sections, section)
+		}
+	}
 
-	if s.dryRun {
-		separator := strings.Repeat("=", 80)
-		log.Println("\n" + separator)
-		log.Println("DRY RUN MODE - Output that would be sent to publishers:")
-		log.Println(separator)
-		fmt.Println(message)
-		log.Println(separator)
-		log.Println("Weekly wrap dry-run completed successfully (not sent to publishers)")
-	} else if len(s.publishers) > 0 {
-		// Send to all configured publishers
-		for _, pub := range s.publishers {
-			err = pub.Publish(message)
-			if err != nil {
-				log.Printf("Failed to send message via publisher: %v", err)
-				// Continue to next publisher
-			}
+	if s.config.Alerts.ImportEnabled {
+		section, err := s.checkStaleImports(now, currency)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("failed to check bank imports: %w", err))
+		} else if section != "" {
+			sections = append(sections, section)
 		}
+	}
 
-		log.Println("Weekly wrap completed successfully")
-	} else {
-		log.Println("No publishers are configured, skipping message send")
+	if s.config.Alerts.FraudEnabled {
+		section, err := s.checkFraud(now, currency)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("failed to check for fraud: %w", err))
+		} else if section != "" {
+			sections = append(sections, section)
+		}
+	}
+
+	if len(sections) == 0 {
+		log.Println("No alerts triggered")
+		return errors.Join(errs...)
 	}
+
+	message := s.theme.Icon("alert") + "**Critical Budget Alert**\n\n" + strings.Join(sections, "\n")
+	err = s.deliver("Alert check", publisher.Report{
+		Title:   "Critical Budget Alert",
+		Message: message,
+	})
+	return errors.Join(append(errs, err)...)
 }
 
-func (s *Scheduler) runMonthlyWrap() {
-	log.Println("Running monthly wrap...")
+// checkOverdraftRisk returns a formatted alert section for checking accounts
+// projected to go negative before the next income, or "" if none are at risk
+func (s *Scheduler) checkOverdraftRisk(now time.Time, currency money.Format) (string, error) {
+	accounts, err := s.ynabClient.GetAccounts()
+	if err != nil {
+		return "", err
+	}
 
-	now := time.Now()
-	prev := now.AddDate(0, -1, 0)
+	scheduled, err := s.ynabClient.GetScheduledTransactions()
+	if err != nil {
+		return "", err
+	}
+
+	lookbackDays := s.config.Alerts.OverdraftLookbackDays
+	recent, err := s.ynabClient.GetTransactions(now.AddDate(0, 0, -lookbackDays), now)
+	if err != nil {
+		return "", err
+	}
+
+	risks := s.analyzer.AssessOverdraftRisk(accounts, scheduled, recent, now, lookbackDays, s.config.Alerts.OverdraftHorizonDays)
+	return s.formatOverdraftRisks(risks, currency), nil
+}
 
-	log.Printf("Processing month: %s", prev.Format("January 2006"))
+func (s *Scheduler) formatOverdraftRisks(risks []processor.OverdraftRisk, currency money.Format) string {
+	if len(risks) == 0 {
+		return ""
+	}
 
-	data, err := s.ynabClient.GetMonthlyData(prev.Year(), int(prev.Month()))
+	message := s.theme.Icon("cash") + "**Overdraft Risk**\n"
+	for _, risk := range risks {
+		nextIncome := "no income scheduled"
+		if !risk.NextIncome.IsZero() {
+			nextIncome = "next income " + risk.NextIncome.Format("01-02")
+		}
+		message += fmt.Sprintf("• **%s**: %s now, projected to go negative on %s (low %s, %s)\n",
+			risk.AccountName,
+			currency.Amount(risk.Balance),
+			risk.NegativeOn.Format("01-02"),
+			currency.Amount(risk.ProjectedLow),
+			nextIncome)
+	}
+	return message
+}
+
+// checkStaleImports returns a formatted alert section for accounts whose
+// bank import looks to have stopped, or "" if all look current
+func (s *Scheduler) checkStaleImports(now time.Time, currency money.Format) (string, error) {
+	accounts, err := s.ynabClient.GetAccounts()
 	if err != nil {
-		log.Printf("Failed to get monthly data: %v", err)
-		return
+		return "", err
 	}
 
-	prevMonthTime := now.AddDate(0, -2, 0)
-	prevCategorySpend, err := s.ynabClient.GetPrevMonthCategorySpend(prevMonthTime.Year(), int(prevMonthTime.Month()))
+	lookbackDays := s.config.Alerts.ImportLookbackDays
+	recent, err := s.ynabClient.GetTransactions(now.AddDate(0, 0, -lookbackDays), now)
 	if err != nil {
-		log.Printf("Warning: could not fetch previous month data for comparison: %v", err)
-		prevCategorySpend = nil
+		return "", err
 	}
 
-	topCategoriesLimit := s.config.Thresholds.TopCategoriesCount
-	analysis, err := s.analyzer.AnalyzeMonthlyData(data, prevCategorySpend, topCategoriesLimit)
+	stale := s.analyzer.DetectStaleImports(accounts, recent, now, lookbackDays, s.config.Alerts.ImportSilentDays)
+	return s.formatStaleImports(stale), nil
+}
+
+func (s *Scheduler) formatStaleImports(stale []processor.StaleImport) string {
+	if len(stale) == 0 {
+		return ""
+	}
+
+	message := s.theme.Icon("plug") + "**Bank Import May Be Broken**\n"
+	for _, account := range stale {
+		message += fmt.Sprintf("• **%s**: no transactions for %d days, last on %s (usually active %d of %d days)\n",
+			account.AccountName, account.DaysSilent, account.LastTransaction.Format("01-02"),
+			account.ActiveDays, account.HistoryDays)
+	}
+	message += "Reports will leave out this spending until the connection is fixed in YNAB\n"
+	return message
+}
+
+// checkFraud returns a formatted alert section for recent charges that look
+// like card fraud, or "" if nothing looks suspicious
+func (s *Scheduler) checkFraud(now time.Time, currency money.Format) (string, error) {
+	alerts := s.config.Alerts
+	since := now.AddDate(0, 0, -(alerts.FraudWindowDays + alerts.FraudBaselineDays))
+	transactions, err := s.ynabClient.GetTransactions(since, now)
 	if err != nil {
-		log.Printf("Failed to analyze monthly data: %v", err)
-		return
+		return "", err
 	}
 
-	message := s.formatMonthlyMessage(analysis)
+	signals := s.analyzer.DetectFraud(transactions, now, alerts.FraudWindowDays, alerts.FraudMicroCharge)
+	return s.formatFraudSignals(signals, currency), nil
+}
+
+func (s *Scheduler) formatFraudSignals(signals []processor.FraudSignal, currency money.Format) string {
+	if len(signals) == 0 {
+		return ""
+	}
 
+	message := s.theme.Icon("detective") + "**Possible Card Fraud**\n"
+	for _, signal := range signals {
+		charges := s.formatCharges(signal.Transactions, currency)
+		switch signal.Pattern {
+		case processor.FraudMicroCharges:
+			message += fmt.Sprintf("• **%d tiny charges** from new payees: %s\n", len(signal.Transactions), charges)
+		case processor.FraudRepeatedAmount:
+			message += fmt.Sprintf("• **%s charged %d times** by different new payees: %s\n",
+				currency.Amount(signal.Amount), len(signal.Transactions), charges)
+		case processor.FraudForeignBurst:
+			message += fmt.Sprintf("• **%d charges from new foreign-looking payees**: %s\n", len(signal.Transactions), charges)
+		}
+	}
+	message += "Check these with your bank, and freeze the card if you don't recognise them\n"
+	return message
+}
+
+// formatCharges lists transactions as "Payee $amount (MM-DD)"
+func (s *Scheduler) formatCharges(transactions []ynab.Transaction, currency money.Format) string {
+	charges := make([]string, len(transactions))
+	for i, tx := range transactions {
+		charges[i] = fmt.Sprintf("%s %s", tx.PayeeName, currency.Amount(-tx.Amount))
+		if tx.Date != nil {
+			charges[i] += " (" + tx.Date.Format("01-02") + ")"
+		}
+	}
+	return strings.Join(charges, ", ")
+}
+
+// deliver sends the report to every configured publisher, or prints it to
+// stdout in dry-run mode. A failing publisher does not stop the others; their
+// errors are returned together wrapped in ErrDelivery.
+func (s *Scheduler) deliver(label string, report publisher.Report) error {
+	_, err := s.deliverWithResult(label, report)
+	return err
+}
+
+// deliverWithResult delivers the report to all publishers concurrently and
+// returns the aggregated result (nil in dry-run mode or without publishers).
+// When only some destinations fail, the ones that worked are told which
+// didn't so the failure doesn't go unnoticed.
+func (s *Scheduler) deliverWithResult(label string, report publisher.Report) (*publisher.DeliveryResult, error) {
+	if s.dryRun && s.dryRunChatID != 0 {
+		return nil, s.sendDryRunToChat(label, report)
+	}
 	if s.dryRun {
 		separator := strings.Repeat("=", 80)
 		log.Println("\n" + separator)
 		log.Println("DRY RUN MODE - Output that would be sent to publishers:")
 		log.Println(separator)
-		fmt.Println(message)
+		fmt.Println(report.Message)
 		log.Println(separator)
-		log.Println("Monthly wrap dry-run completed successfully (not sent to publishers)")
-	} else if len(s.publishers) > 0 {
-		for _, pub := range s.publishers {
-			err = pub.Publish(message)
-			if err != nil {
-				log.Printf("Failed to send message via publisher: %v", err)
-			}
-		}
-		log.Println("Monthly wrap completed successfully")
-	} else {
+		log.Printf("%s dry-run completed successfully (not sent to publishers)", label)
+		return nil, nil
+	}
+
+	if len(s.publishers) == 0 {
 		log.Println("No publishers are configured, skipping message send")
+		return nil, nil
+	}
+
+	result := publisher.Deliver(context.Background(), s.publishers, report, s.config.Delivery.Timeout)
+	log.Printf("%s delivery: %s in %s", label, result.Summary(), result.Duration.Round(time.Millisecond))
+	for _, failure := range result.Failures {
+		log.Printf("Failed to send message via %s: %v", failure.Destination, failure.Err)
+	}
+
+	if len(result.Failures) > 0 {
+		if len(result.Successes) > 0 {
+			s.notifyDeliveryFailures(label, result)
+		}
+		return result, fmt.Errorf("%s: %w: %w", label, ErrDelivery, result.Err())
 	}
+
+	log.Printf("%s completed successfully", label)
+	return result, nil
 }
 
-// formatAmount formats a float amount, removing unnecessary decimals
-func (s *Scheduler) formatAmount(amount float64) string {
-	// Check if the amount is a whole number
-	if amount == float64(int64(amount)) {
-		return fmt.Sprintf("%.0f", amount)
+// sendDryRunToChat sends the message to the dry-run test chat without
+// truncating it, so Telegram validates both its markdown and its length
+func (s *Scheduler) sendDryRunToChat(label string, report publisher.Report) error {
+	telegramConfig := s.config.Telegram
+	telegramConfig.ChatID = s.dryRunChatID
+	telegramConfig.TopicID = 0
+	telegramConfig.Chats = nil
+
+	bot, err := telegram.NewBot(telegramConfig)
+	if err != nil {
+		return fmt.Errorf("%s dry-run: %w", label, err)
+	}
+	if err := bot.PublishUntruncated(report.Message); err != nil {
+		return fmt.Errorf("%s dry-run to chat %d: %w: %w", label, s.dryRunChatID, ErrDelivery, err)
+	}
+
+	log.Printf("%s dry-run sent to test chat %d (%d characters)", label, s.dryRunChatID, len(report.Message))
+	return nil
+}
+
+// notifyDeliveryFailures sends a short delivery report to the destinations
+// that succeeded
+func (s *Scheduler) notifyDeliveryFailures(label string, result *publisher.DeliveryResult) {
+	succeeded := make(map[string]bool)
+	for _, name := range result.Successes {
+		succeeded[name] = true
+	}
+	var working []publisher.Publisher
+	for _, pub := range s.publishers {
+		if succeeded[publisher.Name(pub)] {
+			working = append(working, pub)
+		}
+	}
+
+	message := fmt.Sprintf("%s**%s delivery incomplete**\n\n%s\n", s.theme.Icon("warning"), label, result.Summary())
+	for _, failure := range result.Failures {
+		message += fmt.Sprintf("• **%s**: %s\n", failure.Destination, failure.Error)
+	}
+
+	notice := publisher.Deliver(context.Background(), working, publisher.Report{
+		Title:   label + " delivery incomplete",
+		Message: message,
+	}, s.config.Delivery.Timeout)
+	if err := notice.Err(); err != nil {
+		log.Printf("Failed to send delivery failure notice: %v", err)
 	}
-	// Otherwise show up to 2 decimals, but trim trailing zeros
-	formatted := fmt.Sprintf("%.2f", amount)
-	// Remove trailing zeros after decimal point
-	formatted = strings.TrimRight(formatted, "0")
-	formatted = strings.TrimRight(formatted, ".")
-	return formatted
 }
 
-func (s *Scheduler) formatDelta(delta int64) string {
-	amount := float64(delta) / 1000
-	if delta >= 0 {
-		return fmt.Sprintf("+$%s", s.formatAmount(amount))
+// formatReportNotes explains partial data and missing comparisons so a brand
+// new budget doesn't read as a quiet period or a huge jump
+func (s *Scheduler) formatReportNotes(analysis *processor.AnalysisResult, period string) string {
+	notes := ""
+	if coverage := analysis.Coverage; coverage != nil {
+		notes += fmt.Sprintf("%sPartial %s: covers %s to %s (%d of %d days)\n",
+			s.theme.Icon("calendar"), period, coverage.From.Format("2006-01-02"), coverage.To.Format("2006-01-02"),
+			coverage.Days, coverage.PeriodDays)
 	}
-	return fmt.Sprintf("-$%s", s.formatAmount(-amount))
+	if analysis.FirstReport {
+		notes += s.theme.Icon("info") + "First report, comparisons unavailable\n"
+	}
+	if len(analysis.Focus) > 0 {
+		notes += fmt.Sprintf("%sFocused on %s, other categories left out\n", s.theme.Icon("target"), strings.Join(analysis.Focus, ", "))
+	}
+	if validation := analysis.Validation; !validation.Empty() {
+		var repairs []string
+		if n := validation.UnknownCategory; n > 0 {
+			repairs = append(repairs, fmt.Sprintf("%d transactions with a category no longer in the budget counted as uncategorized", n))
+		}
+		if n := validation.NegativeBudgeted; n > 0 {
+			repairs = append(repairs, fmt.Sprintf("%d negative budgets counted as zero", n))
+		}
+		if n := validation.FutureDated; n > 0 {
+			repairs = append(repairs, fmt.Sprintf("%d future-dated transactions left out", n))
+		}
+		notes += s.theme.Icon("info") + "Data checks: " + strings.Join(repairs, ", ") + "\n"
+	}
+	if notes != "" {
+		notes += "\n"
+	}
+	return notes
 }
 
 func (s *Scheduler) formatMessage(analysis *processor.AnalysisResult) string {
-	// Format currency amounts (YNAB stores amounts in millicents)
-	spent := float64(analysis.Overview.TotalSpent) / 1000
-	spentStr := s.formatAmount(spent)
-
-	// Create header with category count
-	categoryCountText := "Spending Categories"
-	if len(analysis.TopSpending) == 0 {
-		categoryCountText = "No Spending Categories"
-	} else if len(analysis.TopSpending) == 1 {
-		categoryCountText = "1 Spending Category"
-	} else {
-		categoryCountText = fmt.Sprintf("%d Spending Categories", len(analysis.TopSpending))
-	}
+	currency := analysis.Currency
+	t := s.messages.T
 
-	message := fmt.Sprintf(
-		"📊 **Weekly Financial Wrap - %s**\n\n"+
-			"💰 **Total Spent**: $%s\n\n"+
-			"🏆 **Top %s**\n",
-		analysis.DateRange,
-		spentStr,
-		categoryCountText,
-	)
+	message := fmt.Sprintf("%s**%s**\n\n", s.theme.Icon("chart"), t("weekly.title", analysis.DateRange))
+	message += s.formatReportNotes(analysis, "week")
+	message += s.formatSafeToSpend(analysis)
+	message += fmt.Sprintf("%s**%s**: %s\n", s.theme.Icon("money"), t("total_spent"), currency.Amount(analysis.Overview.TotalSpent))
+	message += s.formatAgeOfMoney(analysis)
+	message += s.formatLumpyNote(analysis)
+	message += s.formatCashFlow(analysis)
+	message += s.formatSavings(analysis)
+	message += s.formatNetWorth(analysis)
+	message += s.formatBiggestMover(analysis)
+	message += fmt.Sprintf("\n%s**%s**\n", s.theme.Icon("trophy"), t("weekly.top", s.categoryCount(len(analysis.TopSpending))))
 
 	// Add top spending categories
 	for _, category := range analysis.TopSpending {
 		// Weekly spending and remaining balance for the month
-		weeklySpent := float64(category.Spent) / 1000
-		monthlyBalance := float64(category.Balance) / 1000
+		var comparisons []string
+		if analysis.HasPrevData {
+			comparisons = append(comparisons, t("weekly.vs_prev", currency.Delta(category.SpendDelta)))
+		}
+		if analysis.HasBaseline {
+			comparisons = append(comparisons, t("weekly.avg", processor.BaselineWeeks, currency.Amount(category.AvgSpent)))
+		}
+		spendField := currency.Amount(category.Spent)
+		if len(comparisons) > 0 {
+			spendField += " (" + strings.Join(comparisons, ", ") + ")"
+		}
+
+		line := t("weekly.category", spendField, currency.Amount(category.Balance))
+		if analysis.NoBudget {
+			line = t("weekly.spent", spendField)
+		}
+		message += fmt.Sprintf("• **%s**%s: %s\n", category.Category, s.lumpyMarker(category.Lumpy), line)
+		message += s.formatNote(analysis, category.Category)
+	}
+	message += s.formatPayees(analysis)
 
-		// Format amounts, removing unnecessary decimals
-		spentStr := s.formatAmount(weeklySpent)
-		balanceStr := s.formatAmount(monthlyBalance)
+	message += s.formatEnvelopes(analysis.Envelopes, currency)
 
-		message += fmt.Sprintf("• **%s**: Last Week Spend: $%s  Balance: $%s\n",
-			category.Category, spentStr, balanceStr)
+	// Add personal benchmark comparisons
+	if len(analysis.Benchmarks) > 0 {
+		message += fmt.Sprintf("\n%s**%s**\n", s.theme.Icon("target"), t("benchmarks"))
+		for _, benchmark := range analysis.Benchmarks {
+			message += fmt.Sprintf("• **%s**: %s of %s target (%s)\n",
+				benchmark.Category, currency.Amount(benchmark.Actual), currency.Amount(benchmark.Target), currency.Delta(benchmark.Delta))
+		}
 	}
 
-	message += "\n⚠️ **Over Budget Categories**\n"
+	// Add needs vs wants split
+	if split := analysis.Split; split != nil {
+		message += fmt.Sprintf("\n%s**%s**\n", s.theme.Icon("scales"), t("needs_vs_wants"))
+		message += fmt.Sprintf("%.0f%% needs / %.0f%% wants / %.0f%% savings (target %d/%d/%d)\n",
+			split.NeedsPercent, split.WantsPercent, split.SavingsPercent,
+			split.Target.Needs, split.Target.Wants, split.Target.Savings)
+		if split.Untagged > 0 {
+			message += fmt.Sprintf("Untagged spending: %s\n", currency.Amount(split.Untagged))
+		}
+	}
+
+	// Add early-month velocity alerts
+	if len(analysis.Velocity) > 0 {
+		message += fmt.Sprintf("\n%s**%s**\n", s.theme.Icon("rocket"), t("early_alerts"))
+		for _, alert := range analysis.Velocity {
+			message += fmt.Sprintf("• **%s** hit %.0f%% of budget by day %d\n",
+				alert.Category, alert.Percentage, alert.Day)
+		}
+	}
+
+	// Add categories on pace to go over budget by the end of the month
+	if len(analysis.Projections) > 0 {
+		message += fmt.Sprintf("\n%s**%s**\n", s.theme.Icon("up"), t("projection"))
+		for _, projection := range analysis.Projections {
+			message += fmt.Sprintf("• **%s**: %s\n", projection.Category, t("projection.line",
+				currency.Amount(projection.Projected), currency.Amount(projection.Budgeted), currency.Amount(projection.Over)))
+		}
+	}
+
+	if !analysis.NoBudget {
+		message += fmt.Sprintf("\n%s**%s**\n", s.theme.Icon("warning"), t("over_budget"))
+	}
 
 	// Add concerns with transaction details
 	if len(analysis.Concerns) > 0 {
 		for _, concern := range analysis.Concerns {
-			weeklySpent := float64(concern.Spent) / 1000
-			monthlyBalance := float64(concern.Balance) / 1000
-
-			spentStr := s.formatAmount(weeklySpent)
-			balanceStr := s.formatAmount(monthlyBalance)
-
-			message += fmt.Sprintf("\n**%s**: Last Week Spend: $%s  Balance: $%s\n",
-				concern.Category, spentStr, balanceStr)
+			message += fmt.Sprintf("\n%s**%s**: %s\n", s.theme.Icon(string(concern.Severity)),
+				concern.Category, t("weekly.category", currency.Amount(concern.Spent), currency.Amount(concern.Balance)))
+			message += s.formatOffsets(concern, currency)
 
 			// Add transaction details
 			if len(concern.Transactions) > 0 {
-				message += "Last 3 transactions:\n"
+				message += t("transactions.last_few") + "\n"
 				for count, tx := range concern.Transactions {
 					// YNAB stores spending as negative, convert to positive for display
 					if count == 3 {
 						break
 					}
-					txAmount := -float64(tx.Amount) / 1000
-					txAmountStr := s.formatAmount(txAmount)
 					date := ""
 					if tx.Date != nil {
 						date = tx.Date.Format("01-02")
@@ -310,81 +918,812 @@ func (s *Scheduler) formatMessage(analysis *processor.AnalysisResult) string {
 					if memo == "" {
 						memo = tx.PayeeName
 					}
-					message += fmt.Sprintf("  • %s: $%s - %s\n", date, txAmountStr, memo)
+					message += fmt.Sprintf("  • %s: %s - %s\n", date, currency.Amount(-tx.Amount), memo)
 				}
 			}
 		}
-	} else {
-		message += "• No categories over budget - great job! 🎉\n"
+	} else if !analysis.NoBudget {
+		message += "• " + t("over_budget.none") + s.theme.Suffix("party") + "\n"
 	}
+	message += s.formatWins(analysis)
 
+	message += s.formatGroups(analysis)
+	message += s.formatCards(analysis)
+	message += s.formatGoals(analysis)
+	message += s.formatUtilities(analysis)
+	message += s.formatGroceries(analysis)
+	message += s.formatUnusual(analysis)
+	message += s.formatNewPayees(analysis)
+	message += s.formatFlagged(analysis)
+	message += s.formatWeekAhead(analysis.AheadFocus)
+	message += s.formatInsights(analysis)
+	message += s.formatOtherNotes(analysis)
+	message += s.formatAttention(analysis)
+	message += s.formatActions(analysis)
+	message += s.formatAccounts(analysis)
+	message += s.formatGuardrails(analysis)
+	message += s.formatConfidence(analysis.Confidence)
 	return message
 }
 
-func (s *Scheduler) formatMonthlyMessage(analysis *processor.AnalysisResult) string {
-	spent := float64(analysis.Overview.TotalSpent) / 1000
-	spentStr := s.formatAmount(spent)
-
-	categoryCountText := "Spending Categories"
-	if len(analysis.TopSpending) == 0 {
-		categoryCountText = "No Spending Categories"
-	} else if len(analysis.TopSpending) == 1 {
-		categoryCountText = "1 Spending Category"
-	} else {
-		categoryCountText = fmt.Sprintf("%d Spending Categories", len(analysis.TopSpending))
+// categoryCount names how many top spending categories are listed, e.g.
+// "3 Spending Categories"
+func (s *Scheduler) categoryCount(count int) string {
+	switch count {
+	case 0:
+		return s.messages.T("categories.none")
+	case 1:
+		return s.messages.T("categories.one")
+	default:
+		return s.messages.T("categories.many", count)
 	}
+}
 
-	message := fmt.Sprintf(
-		"📊 **Monthly Financial Wrap - %s**\n\n"+
-			"💰 **Total Spent**: $%s\n\n"+
-			"🏆 **%s**\n",
-		analysis.DateRange,
-		spentStr,
-		categoryCountText,
-	)
+// formatBiggestMover headlines the category whose spending changed most from
+// last week, with its largest transaction as the likely reason
+func (s *Scheduler) formatBiggestMover(analysis *processor.AnalysisResult) string {
+	mover := analysis.Mover
+	if mover == nil {
+		return ""
+	}
+	line := fmt.Sprintf("%s**%s**: %s, %s", s.theme.Icon("pin"), s.messages.T("biggest_mover"),
+		mover.Category, s.messages.T("weekly.vs_prev", analysis.Currency.Delta(mover.Delta)))
+	if tx := mover.Transaction; tx != nil {
+		reason := tx.Memo
+		if reason == "" {
+			reason = tx.PayeeName
+		}
+		if reason != "" {
+			line += " — " + reason
+		}
+	}
+	return line + "\n"
+}
 
+// formatGroups breaks the week's spending down by account group, with each
+// group's top categories
+func (s *Scheduler) formatGroups(analysis *processor.AnalysisResult) string {
+	if len(analysis.Groups) == 0 {
+		return ""
+	}
+	currency := analysis.Currency
+	message := fmt.Sprintf("\n%s**%s**\n", s.theme.Icon("people"), s.messages.T("groups"))
+	for _, group := range analysis.Groups {
+		name := group.Group
+		if name == "" {
+			name = s.messages.T("groups.other")
+		}
+		categories := make([]string, len(group.TopCategories))
+		for i, line := range group.TopCategories {
+			categories[i] = fmt.Sprintf("%s %s", line.Category, currency.Amount(line.Spent))
+		}
+		message += fmt.Sprintf("• %s: %s", name, currency.Amount(group.Spent))
+		if len(categories) > 0 {
+			message += " — " + strings.Join(categories, ", ")
+		}
+		message += "\n"
+	}
+	return message
+}
+
+// formatCards shows each credit card's week and whether its payment
+// category covers what is owed
+func (s *Scheduler) formatCards(analysis *processor.AnalysisResult) string {
+	if len(analysis.Cards) == 0 {
+		return ""
+	}
+	currency := analysis.Currency
+	t := s.messages.T
+	message := fmt.Sprintf("\n%s**%s**\n", s.theme.Icon("card"), t("cards"))
+	for _, card := range analysis.Cards {
+		parts := []string{t("cards.spent", currency.Amount(card.Spent))}
+		if card.Paid > 0 {
+			parts = append(parts, t("cards.paid", currency.Amount(card.Paid)))
+		}
+		parts = append(parts, t("cards.owed", currency.Amount(card.Owed)), t("cards.available", currency.Amount(card.Available)))
+		line := fmt.Sprintf("• **%s**: %s", card.Account, strings.Join(parts, ", "))
+		if shortfall := card.Shortfall(); shortfall > 0 {
+			line += ", " + t("cards.short", currency.Amount(shortfall)) + s.theme.Suffix("warning")
+		} else {
+			line += s.theme.Suffix("check")
+		}
+		message += line + "\n"
+	}
+	return message
+}
+
+// formatGuardrails closes the wrap with the most each category on pace to
+// go over can spend per day for the rest of the month, e.g. "Dining Out:
+// $6.50/day left over 12 days"
+func (s *Scheduler) formatGuardrails(analysis *processor.AnalysisResult) string {
+	var lines string
+	for _, projection := range analysis.Projections {
+		if projection.DaysLeft == 0 {
+			continue
+		}
+		lines += fmt.Sprintf("• %s: %s\n", projection.Category,
+			s.messages.T("guardrails.line", analysis.Currency.Amount(projection.PerDay), projection.DaysLeft))
+	}
+	if lines == "" {
+		return ""
+	}
+	return fmt.Sprintf("\n%s**%s**\n", s.theme.Icon("traffic"), s.messages.T("guardrails")) + lines
+}
+
+// formatAttention prompts to sort out the week's transactions that have no
+// category and, with the pending report on, those still to be approved or
+// cleared
+func (s *Scheduler) formatAttention(analysis *processor.AnalysisResult) string {
+	attention := analysis.Attention
+	if attention == nil {
+		return ""
+	}
+	currency := analysis.Currency
+	t := s.messages.T
+	list := func(transactions []ynab.Transaction, limit int) string {
+		var lines string
+		for i, tx := range transactions {
+			if i == limit {
+				break
+			}
+			date := ""
+			if tx.Date != nil {
+				date = tx.Date.Format("01-02")
+			}
+			payee := tx.PayeeName
+			if payee == "" {
+				payee = tx.Memo
+			}
+			lines += fmt.Sprintf("  • %s: %s - %s\n", date, currency.Amount(-tx.Amount), payee)
+		}
+		return lines
+	}
+
+	message := fmt.Sprintf("\n%s**%s**\n", s.theme.Icon("inbox"), t("attention"))
+	if txs := attention.Uncategorized; len(txs) > 0 {
+		message += "• " + t("attention.uncategorized", len(txs), currency.Amount(processor.Outflow(txs))) + "\n"
+		message += list(txs, 3)
+	}
+	limit := 0
+	if attention.ListPending {
+		limit = -1
+	}
+	if txs := attention.Unapproved; len(txs) > 0 {
+		message += "• " + t("attention.unapproved", len(txs), currency.Amount(processor.Outflow(txs))) + "\n"
+		message += list(txs, limit)
+	}
+	if txs := attention.Uncleared; len(txs) > 0 {
+		message += "• " + t("attention.uncleared", len(txs), currency.Amount(processor.Outflow(txs))) + "\n"
+		message += list(txs, limit)
+	}
+	return message
+}
+
+// formatOffsets walks through the planned offsets covering an overspent
+// category, e.g. "Vacation Fund $300, then Emergency Fund $200", warning
+// when they don't cover all of it
+func (s *Scheduler) formatOffsets(concern processor.CategoryConcernWithTransactions, currency money.Format) string {
+	if len(concern.Offsets) == 0 {
+		return ""
+	}
+	t := s.messages.T
+	steps := make([]string, 0, len(concern.Offsets))
+	for _, step := range concern.Offsets {
+		if step.Amount == 0 {
+			steps = append(steps, t("offsets.empty", step.Category))
+			continue
+		}
+		steps = append(steps, step.Category+" "+currency.Amount(step.Amount))
+	}
+	line := "  • " + t("offsets", strings.Join(steps, t("offsets.then")))
+	if concern.Uncovered > 0 {
+		line += ", " + t("offsets.uncovered", currency.Amount(concern.Uncovered)) + s.theme.Suffix("warning")
+	}
+	return line + "\n"
+}
+
+// formatPatterns points out the days of the week the spending clusters on
+func (s *Scheduler) formatPatterns(pattern *processor.SpendingPattern) string {
+	if pattern == nil {
+		return ""
+	}
+	t := s.messages.T
+	days := func(peak *processor.WeekdayPeak) string {
+		return peak.From.String()[:3] + "–" + peak.To.String()[:3]
+	}
+	message := fmt.Sprintf("\n%s**%s**\n", s.theme.Icon("calendar"), t("patterns", pattern.Months))
+	if peak := pattern.Peak; peak != nil {
+		message += "• " + t("patterns.all", peak.Share, days(peak), pattern.Even) + "\n"
+	}
+	if peak := pattern.OverspentPeak; peak != nil {
+		message += "• " + t("patterns.overspent", peak.Share, days(peak), pattern.Even) + "\n"
+	}
+	return message
+}
+
+// formatGoals shows how far each category is towards its YNAB goal, with
+// what is left to fund and, for goals with a target date, by when
+func (s *Scheduler) formatGoals(analysis *processor.AnalysisResult) string {
+	if len(analysis.Goals) == 0 {
+		return ""
+	}
+	currency := analysis.Currency
+	t := s.messages.T
+	message := fmt.Sprintf("\n%s**%s**\n", s.theme.Icon("flag"), t("goals"))
+	for _, goal := range analysis.Goals {
+		if goal.Complete() {
+			message += fmt.Sprintf("• **%s**: %s%s\n", goal.Category, t("goals.funded", currency.Amount(goal.Target)), s.theme.Suffix("check"))
+			continue
+		}
+		line := t("goals.line", goal.Percent, currency.Amount(goal.Target), currency.Amount(goal.Remaining))
+		if goal.Due != nil {
+			line = t("goals.by", line, goal.Due.Format("Jan 2006"))
+		}
+		message += fmt.Sprintf("• **%s**: %s\n", goal.Category, line)
+	}
+	return message
+}
+
+// formatUtilities lists utility bills well above what the payee usually
+// charges
+func (s *Scheduler) formatUtilities(analysis *processor.AnalysisResult) string {
+	if len(analysis.Utilities) == 0 {
+		return ""
+	}
+	currency := analysis.Currency
+	message := fmt.Sprintf("\n%s**%s**\n", s.theme.Icon("plug"), s.messages.T("utilities"))
+	for _, spike := range analysis.Utilities {
+		message += "• " + s.messages.T("utilities.spike", spike.Payee, spike.Percent,
+			currency.Amount(spike.Amount), currency.Amount(spike.Typical)) + "\n"
+	}
+	return message
+}
+
+// formatGroceries shows the week's average grocery trip and how the average
+// trip has moved over the months
+func (s *Scheduler) formatGroceries(analysis *processor.AnalysisResult) string {
+	groceries := analysis.Groceries
+	if groceries == nil || (groceries.WeekTrips == 0 && !groceries.HasTrend()) {
+		return ""
+	}
+	currency := analysis.Currency
+	message := fmt.Sprintf("\n%s**%s**\n", s.theme.Icon("cart"), s.messages.T("groceries"))
+	switch groceries.WeekTrips {
+	case 0:
+	case 1:
+		message += "• " + s.messages.T("groceries.one", currency.Amount(groceries.WeekAverage)) + "\n"
+	default:
+		message += "• " + s.messages.T("groceries.week", groceries.WeekTrips, currency.Amount(groceries.WeekAverage)) + "\n"
+	}
+	if groceries.HasTrend() {
+		id, percent := "groceries.up", groceries.Percent()
+		if percent < 0 {
+			id, percent = "groceries.down", -percent
+		}
+		message += "• " + s.messages.T(id, percent, groceries.Months,
+			currency.Amount(groceries.Recent), currency.Amount(groceries.Earlier)) + "\n"
+	}
+	return message
+}
+
+// formatUnusual lists outflows far above what their category usually sees
+func (s *Scheduler) formatUnusual(analysis *processor.AnalysisResult) string {
+	if len(analysis.Unusual) == 0 {
+		return ""
+	}
+	message := fmt.Sprintf("\n%s**%s**\n", s.theme.Icon("detective"), s.messages.T("unusual"))
+	for _, unusual := range analysis.Unusual {
+		tx := unusual.Transaction
+		date := ""
+		if tx.Date != nil {
+			date = tx.Date.Format("01-02") + " "
+		}
+		message += fmt.Sprintf("• %s%s: %s\n", date, tx.PayeeName, s.messages.T("unusual.line",
+			analysis.Currency.Amount(-tx.Amount), tx.CategoryName, analysis.Currency.Amount(unusual.Mean)))
+	}
+	return message
+}
+
+// formatActions lists this week's action items, with how many of last week's
+// were done. Approvals are counted rather than listed one by one.
+func (s *Scheduler) formatActions(analysis *processor.AnalysisResult) string {
+	if len(analysis.Actions) == 0 && analysis.FollowUp == nil {
+		return ""
+	}
+	t := s.messages.T
+	currency := analysis.Currency
+	message := fmt.Sprintf("\n%s**%s**\n", s.theme.Icon("target"), t("actions"))
+	if followUp := analysis.FollowUp; followUp != nil {
+		message += s.theme.Icon("check") + t("actions.follow_up", len(followUp.Done), followUp.Total()) + "\n"
+	}
+
+	var approve, approveCarried int
+	for _, action := range analysis.Actions {
+		if action.Kind == processor.ActionApprove {
+			approve++
+			if action.CarriedOver {
+				approveCarried++
+			}
+			continue
+		}
+		line := t("actions.cover_uncovered", action.Category, currency.Amount(action.Amount))
+		if action.From != "" {
+			line = t("actions.cover", action.Category, currency.Amount(action.Amount), action.From)
+		}
+		if action.CarriedOver {
+			line += " (" + t("actions.carried") + ")"
+		}
+		message += "• " + line + "\n"
+	}
+	if approve > 0 {
+		line := t("actions.approve", approve)
+		if approve == 1 {
+			line = t("actions.approve_one")
+		}
+		switch approveCarried {
+		case 0:
+		case approve:
+			line += " (" + t("actions.carried") + ")"
+		default:
+			line += " (" + t("actions.carried_some", approveCarried) + ")"
+		}
+		message += "• " + line + "\n"
+	}
+	if len(analysis.Actions) == 0 {
+		message += "• " + t("actions.none") + s.theme.Suffix("party") + "\n"
+	}
+	return message
+}
+
+// formatAccounts lists each on-budget account's balance, with what hasn't
+// cleared yet when there is any
+func (s *Scheduler) formatAccounts(analysis *processor.AnalysisResult) string {
+	if len(analysis.Accounts) == 0 {
+		return ""
+	}
+	currency := analysis.Currency
+	message := fmt.Sprintf("\n%s**%s**\n", s.theme.Icon("bank"), s.messages.T("accounts"))
+	for _, account := range analysis.Accounts {
+		message += fmt.Sprintf("• %s: %s", account.Account, currency.Amount(account.Balance))
+		if account.Uncleared != 0 {
+			message += " (" + s.messages.T("accounts.uncleared", currency.Amount(account.Cleared), currency.Amount(account.Uncleared)) + ")"
+		}
+		message += "\n"
+	}
+	return message
+}
+
+// formatNewPayees lists payees paid for the first time in the lookback window
+func (s *Scheduler) formatNewPayees(analysis *processor.AnalysisResult) string {
+	if len(analysis.NewPayees) == 0 {
+		return ""
+	}
+	quoted := make([]string, len(analysis.NewPayees))
+	for i, payee := range analysis.NewPayees {
+		quoted[i] = "'" + payee + "'"
+	}
+	return fmt.Sprintf("\n%s**%s**: %s\n", s.theme.Icon("new"), s.messages.T("new_payees"), strings.Join(quoted, ", "))
+}
+
+// formatCashFlow shows the week's income against its spending
+func (s *Scheduler) formatCashFlow(analysis *processor.AnalysisResult) string {
+	flow := analysis.CashFlow
+	if flow == nil || (flow.Income == 0 && flow.Spending == 0) {
+		return ""
+	}
+	currency := analysis.Currency
+	return fmt.Sprintf("%s**%s**: %s\n", s.theme.Icon("cash"), s.messages.T("cash_flow"),
+		s.messages.T("cash_flow.line", currency.Amount(flow.Income), currency.Amount(flow.Spending), currency.Delta(flow.Net)))
+}
+
+// formatSavings shows the share of income kept this week and this month,
+// marked against the target by the month's rate
+func (s *Scheduler) formatSavings(analysis *processor.AnalysisResult) string {
+	savings := analysis.Savings
+	if savings == nil {
+		return ""
+	}
+	t := s.messages.T
+	weekRate, weekOK := savings.Week.SavingsRate()
+	monthRate, monthOK := savings.MonthToDate.SavingsRate()
+	if !weekOK && !monthOK {
+		return ""
+	}
+
+	rates := []string{t("savings.no_income")}
+	if weekOK {
+		rates[0] = t("savings.week", weekRate)
+	}
+	if monthOK {
+		rates = append(rates, t("savings.month", monthRate))
+	}
+	line := fmt.Sprintf("%s**%s**: %s", s.theme.Icon("seedling"), t("savings"), strings.Join(rates, ", "))
+	if savings.Target > 0 {
+		line += " (" + t("savings.target", savings.Target) + ")"
+		rate, ok := monthRate, monthOK
+		if !ok {
+			rate = weekRate
+		}
+		if rate >= float64(savings.Target) {
+			line += s.theme.Suffix("check")
+		} else {
+			line += s.theme.Suffix("warning")
+		}
+	}
+	return line + "\n"
+}
+
+// formatNetWorth shows the total of all accounts, with how it changed since
+// last week and the start of the month when history has the earlier totals
+func (s *Scheduler) formatNetWorth(analysis *processor.AnalysisResult) string {
+	netWorth := analysis.NetWorth
+	if netWorth == nil {
+		return ""
+	}
+	currency := analysis.Currency
+	var changes []string
+	if netWorth.LastWeek != nil {
+		changes = append(changes, s.messages.T("net_worth.week", currency.Delta(netWorth.Total-*netWorth.LastWeek)))
+	}
+	if netWorth.MonthStart != nil {
+		changes = append(changes, s.messages.T("net_worth.month", currency.Delta(netWorth.Total-*netWorth.MonthStart)))
+	}
+	line := fmt.Sprintf("%s**%s**: %s", s.theme.Icon("bank"), s.messages.T("net_worth"), currency.Amount(netWorth.Total))
+	if len(changes) > 0 {
+		line += " (" + strings.Join(changes, ", ") + ")"
+	}
+	return line + "\n"
+}
+
+// formatAgeOfMoney shows YNAB's Age of Money, with how it changed since last
+// week when history has last week's
+func (s *Scheduler) formatAgeOfMoney(analysis *processor.AnalysisResult) string {
+	ageOfMoney := analysis.Overview.AgeOfMoney
+	if ageOfMoney == nil {
+		return ""
+	}
+	line := fmt.Sprintf("%s**%s**: %s", s.theme.Icon("hourglass"), s.messages.T("age_of_money"), s.messages.T("age_of_money.days", ageOfMoney.Days))
+	if ageOfMoney.LastWeek != nil {
+		line += " (" + s.messages.T("age_of_money.week", ageOfMoney.Days-*ageOfMoney.LastWeek) + ")"
+	}
+	return line + "\n"
+}
+
+// formatLumpyNote notes how much of the total went to lumpy categories,
+// which are left out of the budget health percentage
+func (s *Scheduler) formatLumpyNote(analysis *processor.AnalysisResult) string {
+	if analysis.Overview.LumpySpent <= 0 {
+		return ""
+	}
+	return fmt.Sprintf("%sIncludes %s in one-off/annual categories (not counted towards budget health)\n",
+		s.theme.Icon("package"), analysis.Currency.Amount(analysis.Overview.LumpySpent))
+}
+
+// formatSafeToSpend leads the weekly wrap with what can still be spent from
+// flexible categories this week
+func (s *Scheduler) formatSafeToSpend(analysis *processor.AnalysisResult) string {
+	safe := analysis.SafeToSpend
+	if safe == nil {
+		return ""
+	}
+	return fmt.Sprintf("%s**Safe to spend through %s: %s**\n\n",
+		s.theme.Icon("cart"), safe.Through.Format("Monday"), analysis.Currency.Amount(safe.Amount))
+}
+
+// formatEnvelopes lists what is left in each flexible category and sums up
+// the fixed ones, which need no attention
+func (s *Scheduler) formatEnvelopes(envelopes *processor.Envelopes, currency money.Format) string {
+	if envelopes == nil {
+		return ""
+	}
+
+	message := fmt.Sprintf("\n%s**%s**\n", s.theme.Icon("envelope"), s.messages.T("left_this_month"))
+	message += fmt.Sprintf("Flexible: %s\n", currency.Amount(envelopes.FlexibleTotal))
+	for _, envelope := range envelopes.Flexible {
+		message += fmt.Sprintf("• **%s**: %s\n", envelope.Category, currency.Amount(envelope.Balance))
+	}
+	message += fmt.Sprintf("Fixed: %s set aside\n", currency.Amount(envelopes.FixedTotal))
+	return message
+}
+
+// formatNote shows a category's note under it in the spending list
+func (s *Scheduler) formatNote(analysis *processor.AnalysisResult, category string) string {
+	if note := notes.Lookup(analysis.Notes, category); note != "" {
+		return fmt.Sprintf("  %s%s\n", s.theme.Icon("note"), note)
+	}
+	return ""
+}
+
+// formatOtherNotes lists the notes for categories that didn't make the
+// spending list, so context isn't lost in a quiet week
+func (s *Scheduler) formatOtherNotes(analysis *processor.AnalysisResult) string {
+	var listed []string
 	for _, category := range analysis.TopSpending {
-		monthlySpent := float64(category.Spent) / 1000
-		monthlyBalance := float64(category.Balance) / 1000
+		listed = append(listed, category.Category)
+	}
+	categories := notes.Unlisted(analysis.Notes, listed)
+	if len(categories) == 0 {
+		return ""
+	}
+
+	message := fmt.Sprintf("\n%s**%s**\n", s.theme.Icon("note"), s.messages.T("notes"))
+	for _, category := range categories {
+		message += fmt.Sprintf("• **%s**: %s\n", category, analysis.Notes[category])
+	}
+	return message
+}
+
+// formatPayees lists the payees spent with most, in spending-only mode
+func (s *Scheduler) formatPayees(analysis *processor.AnalysisResult) string {
+	if len(analysis.Payees) == 0 {
+		return ""
+	}
+	message := fmt.Sprintf("\n%s**%s**\n", s.theme.Icon("cart"), s.messages.T("summary.top_payees"))
+	for _, payee := range analysis.Payees {
+		message += fmt.Sprintf("• **%s**: %s\n", payee.Payee, s.messages.T("summary.payee", analysis.Currency.Amount(payee.Spent), payee.Transactions))
+	}
+	return message
+}
+
+// formatWins celebrates the categories with the most left
+func (s *Scheduler) formatWins(analysis *processor.AnalysisResult) string {
+	if len(analysis.Wins) == 0 {
+		return ""
+	}
+	message := fmt.Sprintf("\n%s**%s**\n", s.theme.Icon("party"), s.messages.T("wins"))
+	for _, win := range analysis.Wins {
+		message += fmt.Sprintf("• **%s**: %s\n", win.Category, s.messages.T("wins.left", analysis.Currency.Amount(win.Balance)))
+	}
+	return message
+}
+
+// formatFlagged lists the transactions flagged a highlighted color
+func (s *Scheduler) formatFlagged(analysis *processor.AnalysisResult) string {
+	if len(analysis.Flagged) == 0 {
+		return ""
+	}
+	message := fmt.Sprintf("\n%s**%s**\n", s.theme.Icon("flagged"), s.messages.T("flagged"))
+	for _, tx := range analysis.Flagged {
+		date := ""
+		if tx.Date != nil {
+			date = tx.Date.Format("01-02") + " "
+		}
+		detail := tx.FlagColor
+		if tx.CategoryName != "" {
+			detail = tx.CategoryName + ", " + detail
+		}
+		message += fmt.Sprintf("• %s**%s**: %s (%s)\n", date, tx.PayeeName, analysis.Currency.Amount(-tx.Amount), detail)
+	}
+	return message
+}
+
+// formatWeekAhead lists the categories close to their budget to watch for
+// the rest of the month and the adjustments suggested for those already over
+func (s *Scheduler) formatWeekAhead(focus *processor.AheadFocus) string {
+	if focus == nil || (len(focus.Watch) == 0 && len(focus.Adjustments) == 0) {
+		return ""
+	}
+	t := s.messages.T
+
+	message := fmt.Sprintf("\n%s**%s**", s.theme.Icon("calendar"), t("week_ahead"))
+	switch {
+	case focus.WeeksLeft == 1:
+		message += " (" + t("week_ahead.one_week") + ")"
+	case focus.WeeksLeft > 1:
+		message += " (" + t("week_ahead.weeks", focus.WeeksLeft) + ")"
+	}
+	message += "\n"
+	if len(focus.Watch) > 0 {
+		message += "• " + t("week_ahead.watch", "**"+strings.Join(focus.Watch, "**, **")+"**") + "\n"
+	}
+	for _, adjustment := range focus.Adjustments {
+		if adjustment.Offset != "" {
+			message += "• " + t("week_ahead.cover", adjustment.Category, adjustment.Offset) + "\n"
+		} else {
+			message += "• " + t("week_ahead.reduce", adjustment.Category) + "\n"
+		}
+	}
+	return message
+}
+
+// formatInsights lists the messages of the user's insight rules that hold
+func (s *Scheduler) formatInsights(analysis *processor.AnalysisResult) string {
+	if len(analysis.Insights) == 0 {
+		return ""
+	}
+	message := fmt.Sprintf("\n%s**%s**\n", s.theme.Icon("bulb"), s.messages.T("insights"))
+	for _, insight := range analysis.Insights {
+		message += "• " + insight + "\n"
+	}
+	return message
+}
+
+// formatConfidence is a footer saying how up to date the data is, with the
+// details behind the bot's /coverage command
+func (s *Scheduler) formatConfidence(confidence *processor.DataConfidence) string {
+	if confidence == nil {
+		return ""
+	}
+	return fmt.Sprintf("\n%s%s\n", s.theme.Icon("info"), s.messages.T("confidence",
+		confidence.UpdatedAccounts, confidence.ActiveAccounts, len(confidence.Unapproved)))
+}
+
+// lumpyMarker flags a lumpy category in the spending lists
+func (s *Scheduler) lumpyMarker(lumpy bool) string {
+	if lumpy {
+		return s.theme.Suffix("package")
+	}
+	return ""
+}
 
-		spentStr := s.formatAmount(monthlySpent)
-		balanceStr := s.formatAmount(monthlyBalance)
+// dailyTransactionLimit is how many of the day's transactions the daily
+// digest lists before summing up the rest
+const dailyTransactionLimit = 5
 
-		spendField := "$" + spentStr
+// formatDailyDigest is the built-in layout of the daily digest: the day's
+// spending, the running total for the week and anything newly over budget
+func (s *Scheduler) formatDailyDigest(digest *processor.DailyDigest) string {
+	currency := digest.Currency
+	t := s.messages.T
+
+	message := fmt.Sprintf("%s**%s**\n\n", s.theme.Icon("calendar"), t("daily.title", digest.Day.Format("2006-01-02")))
+	if len(digest.Transactions) == 0 {
+		message += t("daily.none") + "\n"
+	} else {
+		message += fmt.Sprintf("%s**%s**: %s\n", s.theme.Icon("money"), t("daily.spent"), currency.Amount(digest.DaySpent))
+		for i, tx := range digest.Transactions {
+			if i == dailyTransactionLimit {
+				message += "• " + t("daily.more", len(digest.Transactions)-i) + "\n"
+				break
+			}
+			payee := tx.PayeeName
+			if payee == "" {
+				payee = tx.Memo
+			}
+			message += fmt.Sprintf("• %s: %s (%s)\n", payee, currency.Amount(-tx.Amount), tx.CategoryName)
+		}
+	}
+	message += fmt.Sprintf("%s**%s**: %s\n", s.theme.Icon("chart"), t("daily.week_so_far"), currency.Amount(digest.WeekSpent))
+
+	if len(digest.NewlyOverBudget) > 0 {
+		message += fmt.Sprintf("\n%s**%s**\n", s.theme.Icon("warning"), t("daily.newly_over"))
+		for _, over := range digest.NewlyOverBudget {
+			message += fmt.Sprintf("• **%s**: %s\n", over.Category, t("daily.over", currency.Amount(over.Over), currency.Amount(over.DaySpent)))
+		}
+	}
+	return message
+}
+
+// agendaExamples is how many uncategorized transactions the agenda names
+const agendaExamples = 3
+
+// formatAgenda numbers everything needing a decision at the budget meeting
+func (s *Scheduler) formatAgenda(agenda *processor.Agenda) string {
+	currency := agenda.Currency
+	t := s.messages.T
+
+	message := fmt.Sprintf("%s**%s**\n\n", s.theme.Icon("calendar"), t("meeting.title", agenda.Meeting.Format("Mon 2 Jan 15:04")))
+	if agenda.Empty() {
+		return message + t("meeting.none") + s.theme.Suffix("party") + "\n"
+	}
+
+	var items []string
+	for _, over := range agenda.Overspent {
+		if over.CoverFrom == "" {
+			items = append(items, fmt.Sprintf("**%s**: %s", over.Category, t("meeting.uncovered", currency.Amount(over.Overspent))))
+			continue
+		}
+		items = append(items, fmt.Sprintf("**%s**: %s", over.Category,
+			t("meeting.overspent", currency.Amount(over.Overspent), currency.Amount(over.Cover), over.CoverFrom)))
+	}
+	for _, goal := range agenda.Underfunded {
+		items = append(items, fmt.Sprintf("**%s**: %s", goal.Category, t("meeting.underfunded", goal.Percent, currency.Amount(goal.Target))))
+	}
+	if len(agenda.Unapproved) > 0 {
+		items = append(items, fmt.Sprintf("**%s**: %s", t("meeting.unapproved"), t("meeting.to_review", len(agenda.Unapproved))))
+	}
+	if len(agenda.Uncategorized) > 0 {
+		var examples []string
+		for i, tx := range agenda.Uncategorized {
+			if i == agendaExamples {
+				examples = append(examples, "...")
+				break
+			}
+			payee := tx.PayeeName
+			if payee == "" {
+				payee = tx.Memo
+			}
+			examples = append(examples, payee+" "+currency.Amount(-tx.Amount))
+		}
+		items = append(items, fmt.Sprintf("**%s**: %s", t("meeting.uncategorized"),
+			t("meeting.to_categorize", len(agenda.Uncategorized), strings.Join(examples, ", "))))
+	}
+	for _, bill := range agenda.Bills {
+		items = append(items, fmt.Sprintf("**%s**: %s", bill.Payee, t("meeting.bill", currency.Amount(bill.Amount), bill.Due.Format("Mon 2 Jan"))))
+	}
+
+	for i, item := range items {
+		message += fmt.Sprintf("%d. %s\n", i+1, item)
+	}
+	return message
+}
+
+// formatSummary lays out a quarterly or yearly summary
+func (s *Scheduler) formatSummary(summary *processor.PeriodSummary) string {
+	currency := summary.Currency
+	t := s.messages.T
+
+	message := fmt.Sprintf("%s**%s**\n\n", s.theme.Icon("chart"), t(summary.Period.Kind+".title", summary.Label))
+	message += fmt.Sprintf("%s**%s**: %s\n", s.theme.Icon("money"), t("total_spent"),
+		t("summary.total", currency.Amount(summary.TotalSpent), currency.Amount(summary.TotalBudgeted)))
+	message += fmt.Sprintf("%s**%s**: %s\n", s.theme.Icon("target"), t("summary.adherence"), t("summary.adherence_detail", summary.AdherencePercent))
+
+	if len(summary.Categories) > 0 {
+		message += fmt.Sprintf("\n%s**%s**\n", s.theme.Icon("trophy"), t("summary.top_categories"))
+		for _, category := range summary.Categories {
+			message += fmt.Sprintf("• **%s**: %s", category.Category, t("summary.category", currency.Amount(category.Spent), currency.Amount(category.Budgeted)))
+			if category.MonthsOver > 0 {
+				message += " (" + t("summary.months_over", category.MonthsOver, summary.Months) + ")"
+			}
+			message += "\n"
+		}
+	}
+
+	if len(summary.Payees) > 0 {
+		message += fmt.Sprintf("\n%s**%s**\n", s.theme.Icon("cart"), t("summary.top_payees"))
+		for _, payee := range summary.Payees {
+			message += fmt.Sprintf("• **%s**: %s\n", payee.Payee, t("summary.payee", currency.Amount(payee.Spent), payee.Transactions))
+		}
+	}
+	return message
+}
+
+func (s *Scheduler) formatMonthlyMessage(analysis *processor.AnalysisResult) string {
+	currency := analysis.Currency
+	t := s.messages.T
+
+	message := fmt.Sprintf("%s**%s**\n\n", s.theme.Icon("chart"), t("monthly.title", analysis.DateRange))
+	message += s.formatReportNotes(analysis, "month")
+	message += fmt.Sprintf("%s**%s**: %s\n", s.theme.Icon("money"), t("total_spent"), currency.Amount(analysis.Overview.TotalSpent))
+	message += s.formatLumpyNote(analysis)
+	message += fmt.Sprintf("\n%s**%s**\n", s.theme.Icon("trophy"), s.categoryCount(len(analysis.TopSpending)))
+
+	for _, category := range analysis.TopSpending {
+		spendField := currency.Amount(category.Spent)
 		if analysis.HasPrevData {
-			spendField += fmt.Sprintf(" (%s vs prev month)", s.formatDelta(category.SpendDelta))
+			spendField += " (" + t("monthly.vs_prev", currency.Delta(category.SpendDelta)) + ")"
 		}
 
-		message += fmt.Sprintf("• **%s**: Last Month Spend: %s  Balance: $%s\n",
-			category.Category, spendField, balanceStr)
+		line := t("monthly.category", spendField, currency.Amount(category.Balance))
+		if analysis.NoBudget {
+			line = t("monthly.spent", spendField)
+		}
+		message += fmt.Sprintf("• **%s**%s: %s\n", category.Category, s.lumpyMarker(category.Lumpy), line)
+		message += s.formatNote(analysis, category.Category)
 	}
+	message += s.formatPayees(analysis)
 
-	message += "\n⚠️ **Over Budget Categories**\n"
+	if !analysis.NoBudget {
+		message += fmt.Sprintf("\n%s**%s**\n", s.theme.Icon("warning"), t("over_budget"))
+	}
 
 	if len(analysis.Concerns) > 0 {
 		for _, concern := range analysis.Concerns {
-			monthlySpent := float64(concern.Spent) / 1000
-			monthlyBalance := float64(concern.Balance) / 1000
-
-			spentStr := s.formatAmount(monthlySpent)
-			balanceStr := s.formatAmount(monthlyBalance)
-
-			spendField := "$" + spentStr
+			spendField := currency.Amount(concern.Spent)
 			if analysis.HasPrevData {
-				spendField += fmt.Sprintf(" (%s vs prev month)", s.formatDelta(concern.SpendDelta))
+				spendField += " (" + t("monthly.vs_prev", currency.Delta(concern.SpendDelta)) + ")"
 			}
 
-			message += fmt.Sprintf("\n**%s**: Last Month Spend: %s  Balance: $%s\n",
-				concern.Category, spendField, balanceStr)
+			message += fmt.Sprintf("\n%s**%s**: %s\n", s.theme.Icon(string(concern.Severity)),
+				concern.Category, t("monthly.category", spendField, currency.Amount(concern.Balance)))
+			message += s.formatOffsets(concern, currency)
 
 			if len(concern.Transactions) > 0 {
-				message += "Last 3 transactions:\n"
+				message += t("transactions.last_few") + "\n"
 				for count, tx := range concern.Transactions {
 					if count == 3 {
 						break
 					}
-					txAmount := -float64(tx.Amount) / 1000
-					txAmountStr := s.formatAmount(txAmount)
 					date := ""
 					if tx.Date != nil {
 						date = tx.Date.Format("01-02")
@@ -393,13 +1732,45 @@ func (s *Scheduler) formatMonthlyMessage(analysis *processor.AnalysisResult) str
 					if memo == "" {
 						memo = tx.PayeeName
 					}
-					message += fmt.Sprintf("  • %s: $%s - %s\n", date, txAmountStr, memo)
+					message += fmt.Sprintf("  • %s: %s - %s\n", date, currency.Amount(-tx.Amount), memo)
 				}
 			}
 		}
-	} else {
-		message += "• No categories over budget - great job! 🎉\n"
+	} else if !analysis.NoBudget {
+		message += "• " + t("over_budget.none") + s.theme.Suffix("party") + "\n"
+	}
+	message += s.formatWins(analysis)
+
+	message += s.formatGoals(analysis)
+	message += s.formatPatterns(analysis.Patterns)
+	message += s.formatHygiene(analysis.Hygiene, currency)
+	message += s.formatInsights(analysis)
+	message += s.formatOtherNotes(analysis)
+	return message
+}
+
+// formatHygiene suggests categories to delete, merge or budget differently
+func (s *Scheduler) formatHygiene(hygiene *processor.CategoryHygiene, currency money.Format) string {
+	if hygiene.Empty() {
+		return ""
 	}
 
+	message := fmt.Sprintf("\n%s**%s**\n", s.theme.Icon("broom"), s.messages.T("tidy_up"))
+	if len(hygiene.Unused) > 0 {
+		message += fmt.Sprintf("• Unused for %d+ months: **%s**. Delete them or merge them into a related category\n",
+			hygiene.UnusedMonths, strings.Join(hygiene.Unused, "**, **"))
+	}
+	for _, hidden := range hygiene.HiddenBalances {
+		message += fmt.Sprintf("• **%s** is hidden but holds %s. Move it to an active category\n",
+			hidden.Category, currency.Amount(hidden.Balance))
+	}
+	for _, over := range hygiene.ChronicOver {
+		message += fmt.Sprintf("• **%s** overspent in %d of %d months: %s spent vs %s budgeted on average. Consider budgeting more\n",
+			over.Category, over.Months, over.OfMonths, currency.Amount(over.AvgSpent), currency.Amount(over.AvgBudgeted))
+	}
+	for _, under := range hygiene.ChronicUnder {
+		message += fmt.Sprintf("• **%s** under half spent in %d of %d months: %s spent vs %s budgeted on average. Consider budgeting less or merging it\n",
+			under.Category, under.Months, under.OfMonths, currency.Amount(under.AvgSpent), currency.Amount(under.AvgBudgeted))
+	}
 	return message
 }
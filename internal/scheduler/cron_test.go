@@ -1,52 +1,149 @@
 package scheduler
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 
+	"github.com/robfig/cron/v3"
+	"github.com/sathyabhat/ynab-weekly-wrap/internal/config"
+	"github.com/sathyabhat/ynab-weekly-wrap/internal/history"
+	"github.com/sathyabhat/ynab-weekly-wrap/internal/i18n"
+	"github.com/sathyabhat/ynab-weekly-wrap/internal/money"
+	"github.com/sathyabhat/ynab-weekly-wrap/internal/pause"
+	"github.com/sathyabhat/ynab-weekly-wrap/internal/pipeline"
 	"github.com/sathyabhat/ynab-weekly-wrap/internal/processor"
+	"github.com/sathyabhat/ynab-weekly-wrap/internal/publisher"
+	"github.com/sathyabhat/ynab-weekly-wrap/internal/theme"
+	"github.com/sathyabhat/ynab-weekly-wrap/internal/ynab"
 )
 
 func newTestScheduler() *Scheduler {
 	return &Scheduler{dryRun: true}
 }
 
-// ── formatAmount ─────────────────────────────────────────────────────────────
+// ── formatDailyDigest ────────────────────────────────────────────────────────
 
-func TestFormatAmount_WholeNumber(t *testing.T) {
+func TestFormatDailyDigest(t *testing.T) {
 	s := newTestScheduler()
-	cases := []struct {
-		in   float64
-		want string
-	}{
-		{0, "0"},
-		{100, "100"},
-		{1234, "1234"},
-		{-50, "-50"},
+	digest := &processor.DailyDigest{
+		Day:       time.Date(2026, 2, 18, 0, 0, 0, 0, time.UTC),
+		DaySpent:  115_000,
+		WeekSpent: 195_000,
+		Transactions: []ynab.Transaction{
+			{PayeeName: "Shell", Amount: -60_000, CategoryName: "Fuel"},
+			{PayeeName: "Countdown", Amount: -55_000, CategoryName: "Groceries"},
+		},
+		NewlyOverBudget: []processor.OverBudgetCategory{{Category: "Groceries", Over: 30_000, DaySpent: 55_000}},
+	}
+
+	msg := s.formatDailyDigest(digest)
+
+	for _, want := range []string{
+		"**Daily Digest - 2026-02-18**",
+		"**Spent Yesterday**: $115",
+		"• Shell: $60 (Fuel)",
+		"**Week So Far**: $195",
+		"**Newly Over Budget**",
+		"• **Groceries**: $30 over ($55 yesterday)",
+	} {
+		if !strings.Contains(msg, want) {
+			t.Errorf("daily digest missing %q:\n%s", want, msg)
+		}
+	}
+}
+
+func TestFormatDailyDigest_NoSpending(t *testing.T) {
+	s := newTestScheduler()
+	digest := &processor.DailyDigest{Day: time.Date(2026, 2, 18, 0, 0, 0, 0, time.UTC), WeekSpent: 80_000}
+
+	msg := s.formatDailyDigest(digest)
+
+	if !strings.Contains(msg, "No spending yesterday") || strings.Contains(msg, "Newly Over Budget") {
+		t.Errorf("unexpected quiet-day digest:\n%s", msg)
+	}
+	if !strings.Contains(msg, "**Week So Far**: $80") {
+		t.Errorf("expected the week's running total:\n%s", msg)
+	}
+}
+
+// ── formatAgenda ─────────────────────────────────────────────────────────────
+
+func TestFormatAgenda(t *testing.T) {
+	s := newTestScheduler()
+	due := time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC)
+	agenda := &processor.Agenda{
+		Meeting: time.Date(2026, 1, 25, 19, 0, 0, 0, time.UTC),
+		Overspent: []processor.OverspentCategory{
+			{Category: "Car Repair", Overspent: 320_000, CoverFrom: "Groceries", Cover: 300_000},
+			{Category: "Dining", Overspent: 50_000},
+		},
+		Underfunded: []processor.UnderfundedGoal{{Category: "Emergency Fund", Target: 5_000_000, Percent: 40}},
+		Unapproved:  []ynab.Transaction{{PayeeName: "Countdown", Amount: -45_000}},
+		Uncategorized: []ynab.Transaction{
+			{PayeeName: "Amazon", Amount: -30_000}, {Memo: "cash", Amount: -12_000},
+			{PayeeName: "Shell", Amount: -60_000}, {PayeeName: "Kmart", Amount: -8_000},
+		},
+		Bills: []processor.UpcomingBill{{Payee: "Landlord", Amount: 2_000_000, Due: due}},
 	}
-	for _, tc := range cases {
-		got := s.formatAmount(tc.in)
-		if got != tc.want {
-			t.Errorf("formatAmount(%v): got %q, want %q", tc.in, got, tc.want)
+
+	msg := s.formatAgenda(agenda)
+
+	for _, want := range []string{
+		"**Budget Meeting Agenda - Sun 25 Jan 19:00**",
+		"1. **Car Repair**: $320 overspent, move $300 from Groceries",
+		"2. **Dining**: $50 overspent, nothing left to cover it from",
+		"3. **Emergency Fund**: goal 40% funded ($5,000 target)",
+		"4. **Unapproved Transactions**: 1 to review",
+		"5. **Uncategorized Transactions**: 4 to categorize: Amazon $30, cash $12, Shell $60, ...",
+		"6. **Landlord**: $2,000 due Sun 1 Feb",
+	} {
+		if !strings.Contains(msg, want) {
+			t.Errorf("agenda missing %q:\n%s", want, msg)
 		}
 	}
 }
 
-func TestFormatAmount_Decimal(t *testing.T) {
+func TestFormatAgenda_Empty(t *testing.T) {
+	s := newTestScheduler()
+	msg := s.formatAgenda(&processor.Agenda{Meeting: time.Date(2026, 1, 25, 19, 0, 0, 0, time.UTC)})
+
+	if !strings.Contains(msg, "Nothing needs a decision this week") || strings.Contains(msg, "1.") {
+		t.Errorf("unexpected empty agenda:\n%s", msg)
+	}
+}
+
+// ── formatSummary ────────────────────────────────────────────────────────────
+
+func TestFormatSummary(t *testing.T) {
 	s := newTestScheduler()
-	cases := []struct {
-		in   float64
-		want string
-	}{
-		{1.5, "1.5"},
-		{1.05, "1.05"},
-		{1.50, "1.5"},  // trailing zero trimmed
-		{0.10, "0.1"},
+	summary := &processor.PeriodSummary{
+		Period:           processor.SummaryPeriod{Kind: processor.SummaryQuarterly},
+		Label:            "2026-Q1",
+		Months:           3,
+		TotalSpent:       1_510_000,
+		TotalBudgeted:    1_500_000,
+		AdherencePercent: 66.7,
+		Categories:       []processor.SummaryCategory{{Category: "Groceries", Spent: 1_190_000, Budgeted: 1_200_000, MonthsOver: 1}},
+		Payees:           []processor.SummaryPayee{{Payee: "Countdown", Spent: 350_000, Transactions: 2}},
 	}
-	for _, tc := range cases {
-		got := s.formatAmount(tc.in)
-		if got != tc.want {
-			t.Errorf("formatAmount(%v): got %q, want %q", tc.in, got, tc.want)
+
+	msg := s.formatSummary(summary)
+
+	for _, want := range []string{
+		"**Quarterly Summary - 2026-Q1**",
+		"**Total Spent**: $1,510 of $1,500 budgeted",
+		"**Budget Adherence**: 67% of category-months within budget",
+		"• **Groceries**: $1,190 spent of $1,200 budgeted (over budget in 1 of 3 months)",
+		"• **Countdown**: $350 across 2 transactions",
+	} {
+		if !strings.Contains(msg, want) {
+			t.Errorf("summary missing %q:\n%s", want, msg)
 		}
 	}
 }
@@ -211,67 +308,1618 @@ func TestFormatMessage_CategoryLabelIsWeekly(t *testing.T) {
 	}
 }
 
-func TestFormatMessage_NoConcerns(t *testing.T) {
+func TestFormatMessage_LumpyCategories(t *testing.T) {
+	s := newTestScheduler()
+	analysis := makeAnalysis("2026-01-19 to 2026-01-26", 1_400_000, []processor.TopSpendingCategory{
+		{Category: "Annual Insurance", Spent: 1_200_000, Budgeted: 100_000, Balance: 0, Lumpy: true},
+		{Category: "Groceries", Spent: 200_000, Budgeted: 500_000, Balance: 300_000},
+	}, nil)
+	analysis.Overview.LumpySpent = 1_200_000
+
+	msg := s.formatMessage(analysis)
+
+	if !strings.Contains(msg, "📦 Includes $1,200 in one-off/annual categories") {
+		t.Errorf("expected lumpy note, got:\n%s", msg)
+	}
+	if !strings.Contains(msg, "• **Annual Insurance** 📦: Last Week Spend") || strings.Contains(msg, "**Groceries** 📦") {
+		t.Errorf("expected only the lumpy category to be marked, got:\n%s", msg)
+	}
+}
+
+func TestFormatMessage_BiggestMover(t *testing.T) {
+	s := newTestScheduler()
+	analysis := makeAnalysisWithPrev("2026-01-19 to 2026-01-26", 200_000, nil, nil)
+	analysis.Mover = &processor.BiggestMover{
+		Category: "Dining", Spent: 340_000, PrevSpent: 200_000, Delta: 140_000,
+		Transaction: &ynab.Transaction{PayeeName: "Bistro", Memo: "Anniversary dinner", Amount: -120_000},
+	}
+
+	msg := s.formatMessage(analysis)
+
+	if !strings.Contains(msg, "📌 **Biggest Mover**: Dining, +$140 vs prev week — Anniversary dinner") {
+		t.Errorf("expected biggest mover line, got:\n%s", msg)
+	}
+
+	analysis.Mover = nil
+	if msg := s.formatMessage(analysis); strings.Contains(msg, "Biggest Mover") {
+		t.Errorf("no mover line expected without a mover, got:\n%s", msg)
+	}
+}
+
+func TestFormatMessage_Baseline(t *testing.T) {
+	s := newTestScheduler()
+	analysis := makeAnalysisWithPrev("2026-01-19 to 2026-01-26", 200_000, []processor.TopSpendingCategory{
+		{Category: "Groceries", Spent: 200_000, Balance: 300_000, SpendDelta: 20_000, AvgSpent: 120_000},
+	}, nil)
+	analysis.HasBaseline = true
+
+	msg := s.formatMessage(analysis)
+
+	if !strings.Contains(msg, "Last Week Spend: $200 (+$20 vs prev week, 4-wk avg $120)") {
+		t.Errorf("expected the 4-week average next to the spend, got:\n%s", msg)
+	}
+}
+
+func TestFormatMessage_Projection(t *testing.T) {
 	s := newTestScheduler()
 	analysis := makeAnalysis("2026-01-19 to 2026-01-26", 200_000, nil, nil)
+	analysis.Projections = []processor.CategoryProjection{
+		{Category: "Dining", MonthSpent: 150_000, Projected: 465_000, Budgeted: 300_000, Over: 165_000},
+	}
+
 	msg := s.formatMessage(analysis)
-	if !strings.Contains(msg, "No categories over budget") {
-		t.Errorf("expected 'No categories over budget', got:\n%s", msg)
+
+	if !strings.Contains(msg, "📈 **Projection**\n• **Dining**: on pace for $465 of $300 budgeted ($165 over)") {
+		t.Errorf("expected projection section, got:\n%s", msg)
+	}
+
+	analysis.Projections = nil
+	if msg := s.formatMessage(analysis); strings.Contains(msg, "Projection") {
+		t.Errorf("no projection section expected, got:\n%s", msg)
 	}
 }
 
-// ── formatDelta / delta display ───────────────────────────────────────────────
+func TestFormatMessage_Confidence(t *testing.T) {
+	s := newTestScheduler()
+	analysis := makeAnalysis("2026-01-19 to 2026-01-26", 200_000, nil, nil)
+	analysis.Confidence = &processor.DataConfidence{
+		ActiveAccounts:  4,
+		UpdatedAccounts: 3,
+		Stale:           []processor.StaleAccount{{Account: "Savings"}},
+		Unapproved:      []ynab.Transaction{{ID: "t1"}, {ID: "t2"}},
+	}
 
-func TestFormatMonthlyMessage_ShowsDeltaWhenHasPrevData(t *testing.T) {
+	msg := s.formatMessage(analysis)
+
+	if !strings.HasSuffix(msg, "ℹ️ Data: 3 of 4 accounts updated in the last 48h, 2 unapproved\n") {
+		t.Errorf("expected confidence footer, got:\n%s", msg)
+	}
+
+	analysis.Confidence = nil
+	if msg := s.formatMessage(analysis); strings.Contains(msg, "accounts updated") {
+		t.Errorf("no footer expected without confidence data, got:\n%s", msg)
+	}
+}
+
+func TestFormatMessage_BudgetCurrency(t *testing.T) {
 	s := newTestScheduler()
-	analysis := makeAnalysisWithPrev("January 2026", 350_000, []processor.TopSpendingCategory{
-		{Category: "Dining", Spent: 350_000, Budgeted: 300_000, Balance: -50_000, PrevSpent: 300_000, SpendDelta: 50_000},
+	analysis := makeAnalysis("2026-01-19 to 2026-01-26", 1_234_500, nil, nil)
+	analysis.Currency = money.Format{ISOCode: "EUR", Symbol: "€", DisplaySymbol: true, DecimalDigits: 2, DecimalSeparator: ",", GroupSeparator: "."}
+
+	msg := s.formatMessage(analysis)
+
+	if !strings.Contains(msg, "💰 **Total Spent**: 1.234,50€") {
+		t.Errorf("expected amounts in the budget's currency, got:\n%s", msg)
+	}
+}
+
+func TestRenderWeekly_DisplayCurrency(t *testing.T) {
+	s := newTestScheduler()
+	s.config = &config.Config{Delivery: config.DeliveryConfig{
+		Currencies: map[string]config.DisplayCurrency{"telegram:-100": {Code: "INR", Rate: 83.2}},
+	}}
+	run := &pipeline.Run{
+		Weekly:   &ynab.WeeklyData{},
+		Analysis: makeAnalysis("2026-01-19 to 2026-01-26", 100_000, nil, nil),
+	}
+
+	if err := s.renderWeekly(context.Background(), run); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(run.Report.Message, "**Total Spent**: $100") {
+		t.Errorf("expected the budget's currency by default, got:\n%s", run.Report.Message)
+	}
+	variant := run.Report.For("telegram:-100")
+	if !strings.Contains(variant.Message, "**Total Spent**: ₹8,320") || variant.Analysis.Currency.ISOCode != "INR" {
+		t.Errorf("expected rupees for the family chat, got:\n%s", variant.Message)
+	}
+	if run.Analysis.Currency.ISOCode == "INR" {
+		t.Error("the variant should not change the analysis that is stored")
+	}
+}
+
+func TestFormatMessage_Locale(t *testing.T) {
+	s := newTestScheduler()
+	s.messages, _ = i18n.New("de")
+	analysis := makeAnalysisWithPrev("2026-01-19 to 2026-01-26", 200_000, []processor.TopSpendingCategory{
+		{Category: "Groceries", Spent: 200_000, Balance: 300_000, SpendDelta: 20_000},
 	}, nil)
 
-	msg := s.formatMonthlyMessage(analysis)
+	msg := s.formatMessage(analysis)
 
-	if !strings.Contains(msg, "vs prev month") {
-		t.Errorf("expected 'vs prev month' when HasPrevData=true, got:\n%s", msg)
+	for _, want := range []string{
+		"📊 **Wöchentlicher Finanzrückblick - 2026-01-19 to 2026-01-26**",
+		"💰 **Gesamtausgaben**: $200",
+		"🏆 **Top 1 Ausgabenkategorie**",
+		"• **Groceries**: Ausgaben letzte Woche: $200 (+$20 ggü. Vorwoche)  Saldo: $300",
+		"• Keine Kategorie über Budget - gut gemacht! 🎉",
+	} {
+		if !strings.Contains(msg, want) {
+			t.Errorf("expected %q, got:\n%s", want, msg)
+		}
 	}
 }
 
-func TestFormatMonthlyMessage_NoDeltaWhenNoPrevData(t *testing.T) {
+func TestFormatMessage_Theme(t *testing.T) {
 	s := newTestScheduler()
-	analysis := makeAnalysis("January 2026", 350_000, []processor.TopSpendingCategory{
-		{Category: "Dining", Spent: 350_000, Budgeted: 300_000, Balance: -50_000},
+	s.theme, _ = theme.New("plain", map[string]string{"warning": "‼️"})
+	s.messages, _ = s.messages.Override(map[string]string{"over_budget": "Overspent"})
+	analysis := makeAnalysis("2026-01-19 to 2026-01-26", 200_000, []processor.TopSpendingCategory{
+		{Category: "Annual Insurance", Spent: 200_000, Lumpy: true},
 	}, nil)
 
-	msg := s.formatMonthlyMessage(analysis)
+	msg := s.formatMessage(analysis)
 
-	if strings.Contains(msg, "vs prev month") {
-		t.Errorf("expected no 'vs prev month' when HasPrevData=false, got:\n%s", msg)
+	for _, want := range []string{
+		"**Weekly Financial Wrap - 2026-01-19 to 2026-01-26**\n",
+		"\n**Total Spent**: $200\n",
+		"• **Annual Insurance**: Last Week Spend",
+		"\n‼️ **Overspent**\n",
+		"great job!\n",
+	} {
+		if !strings.Contains(msg, want) {
+			t.Errorf("expected %q, got:\n%s", want, msg)
+		}
+	}
+	if strings.ContainsAny(msg, "📊💰🏆📦🎉") {
+		t.Errorf("plain theme should leave the emoji out, got:\n%s", msg)
 	}
 }
 
-func TestFormatMonthlyMessage_PositiveDelta(t *testing.T) {
+func TestFormatMessage_SafeToSpend(t *testing.T) {
 	s := newTestScheduler()
-	// SpendDelta=50_000 millicents → +$50
-	analysis := makeAnalysisWithPrev("January 2026", 350_000, []processor.TopSpendingCategory{
-		{Category: "Dining", Spent: 350_000, Budgeted: 300_000, Balance: -50_000, PrevSpent: 300_000, SpendDelta: 50_000},
+	analysis := makeAnalysis("2026-01-19 to 2026-01-26", 200_000, nil, nil)
+	analysis.SafeToSpend = &processor.SafeToSpend{Amount: 186_000, Through: time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC)}
+
+	msg := s.formatMessage(analysis)
+
+	want := "🛒 **Safe to spend through Sunday: $186**\n\n💰 **Total Spent**"
+	if !strings.Contains(msg, want) {
+		t.Errorf("expected safe-to-spend ahead of the total, got:\n%s", msg)
+	}
+}
+
+func TestFormatMessage_Envelopes(t *testing.T) {
+	s := newTestScheduler()
+	analysis := makeAnalysis("2026-01-19 to 2026-01-26", 200_000, nil, nil)
+	analysis.Envelopes = &processor.Envelopes{
+		Flexible:      []processor.EnvelopeBalance{{Category: "Groceries", Balance: 400_000}, {Category: "Fun", Balance: 150_000}},
+		FlexibleTotal: 550_000,
+		FixedTotal:    1_580_000,
+	}
+
+	msg := s.formatMessage(analysis)
+
+	want := "✉️ **Left This Month**\nFlexible: $550\n• **Groceries**: $400\n• **Fun**: $150\nFixed: $1,580 set aside\n"
+	if !strings.Contains(msg, want) {
+		t.Errorf("expected envelope balances grouped by flexibility, got:\n%s", msg)
+	}
+}
+
+func TestFormatMessage_CategoryNotes(t *testing.T) {
+	s := newTestScheduler()
+	analysis := makeAnalysis("2026-01-19 to 2026-01-26", 200_000, []processor.TopSpendingCategory{
+		{Category: "🛒 Groceries", Spent: 200_000, Budgeted: 500_000, Balance: 300_000},
 	}, nil)
+	analysis.Notes = map[string]string{
+		"groceries":       "switching to weekly meal prep",
+		"Car Maintenance": "saving for new tyres",
+	}
 
-	msg := s.formatMonthlyMessage(analysis)
+	msg := s.formatMessage(analysis)
 
-	if !strings.Contains(msg, "+$50") {
-		t.Errorf("expected '+$50' for positive delta, got:\n%s", msg)
+	if !strings.Contains(msg, "Balance: $300\n  📝 switching to weekly meal prep\n") {
+		t.Errorf("expected the note under its category, got:\n%s", msg)
+	}
+	if !strings.Contains(msg, "📝 **Notes**\n• **Car Maintenance**: saving for new tyres\n") || strings.Contains(msg, "• **groceries**") {
+		t.Errorf("expected only notes for unlisted categories in the notes section, got:\n%s", msg)
 	}
 }
 
-func TestFormatMonthlyMessage_NegativeDelta(t *testing.T) {
+func TestFormatMessage_NewPayees(t *testing.T) {
 	s := newTestScheduler()
-	// SpendDelta=-30_000 millicents → -$30
-	analysis := makeAnalysisWithPrev("January 2026", 150_000, []processor.TopSpendingCategory{
-		{Category: "Transport", Spent: 150_000, Budgeted: 200_000, Balance: 50_000, PrevSpent: 180_000, SpendDelta: -30_000},
+	analysis := makeAnalysis("2026-01-19 to 2026-01-26", 200_000, nil, nil)
+	analysis.NewPayees = []string{"Bright Dental", "SteamPowered"}
+
+	msg := s.formatMessage(analysis)
+
+	if !strings.Contains(msg, "🆕 **New Payees**: 'Bright Dental', 'SteamPowered'\n") {
+		t.Errorf("expected the new payees to be listed, got:\n%s", msg)
+	}
+	if msg := s.formatMessage(makeAnalysis("2026-01-19 to 2026-01-26", 200_000, nil, nil)); strings.Contains(msg, "New Payees") {
+		t.Errorf("expected no new payees section, got:\n%s", msg)
+	}
+}
+
+func TestFormatMessage_Unusual(t *testing.T) {
+	s := newTestScheduler()
+	analysis := makeAnalysis("2026-01-19 to 2026-01-26", 200_000, nil, nil)
+	date := time.Date(2026, 1, 22, 0, 0, 0, 0, time.UTC)
+	analysis.Unusual = []processor.UnusualTransaction{{
+		Transaction: ynab.Transaction{Date: &date, Amount: -450_000, PayeeName: "Bright Dental", CategoryName: "Health"},
+		Mean:        80_000,
+		StdDevs:     4.2,
+	}}
+
+	msg := s.formatMessage(analysis)
+
+	if !strings.Contains(msg, "🕵️ **Unusual this week**\n• 01-22 Bright Dental: $450 in Health, usually about $80\n") {
+		t.Errorf("expected the unusual transaction to be listed, got:\n%s", msg)
+	}
+	if msg := s.formatMessage(makeAnalysis("2026-01-19 to 2026-01-26", 200_000, nil, nil)); strings.Contains(msg, "Unusual") {
+		t.Errorf("expected no unusual section, got:\n%s", msg)
+	}
+}
+
+func TestFormatMessage_Accounts(t *testing.T) {
+	s := newTestScheduler()
+	analysis := makeAnalysis("2026-01-19 to 2026-01-26", 200_000, nil, nil)
+	analysis.Accounts = []processor.AccountBalance{
+		{Account: "Checking", Balance: 1_234_500, Cleared: 1_000_000, Uncleared: 234_500},
+		{Account: "Savings", Balance: 5_000_000, Cleared: 5_000_000},
+	}
+
+	msg := s.formatMessage(analysis)
+
+	want := "🏦 **Account Balances**\n• Checking: $1,234.50 ($1,000 cleared, $234.50 uncleared)\n• Savings: $5,000\n"
+	if !strings.Contains(msg, want) {
+		t.Errorf("expected the account balances, got:\n%s", msg)
+	}
+}
+
+func TestFormatMessage_Utilities(t *testing.T) {
+	s := newTestScheduler()
+	analysis := makeAnalysis("2026-01-19 to 2026-01-26", 200_000, nil, nil)
+	analysis.Utilities = []processor.UtilitySpike{{Payee: "Genesis Energy", Amount: 213_000, Typical: 150_000, Percent: 42, Bills: 12}}
+
+	msg := s.formatMessage(analysis)
+
+	if !strings.Contains(msg, "🔌 **Utility Bills**\n• Genesis Energy bill 42% above typical ($213 vs $150)\n") {
+		t.Errorf("expected the utility spike, got:\n%s", msg)
+	}
+}
+
+func TestFormatMessage_Groceries(t *testing.T) {
+	s := newTestScheduler()
+	analysis := makeAnalysis("2026-01-19 to 2026-01-26", 200_000, nil, nil)
+	analysis.Groceries = &processor.GroceryTrend{
+		Category: "Groceries", WeekTrips: 2, WeekAverage: 82_500,
+		Recent: 81_750, Earlier: 75_000, Months: 6, RecentTrips: 4, EarlierTrips: 4,
+	}
+
+	msg := s.formatMessage(analysis)
+
+	want := "🛒 **Grocery Trips**\n• This week: 2 trips, $82.50 on average\n• Average trip up 9% over 6 months ($81.75 vs $75)\n"
+	if !strings.Contains(msg, want) {
+		t.Errorf("expected the grocery trend, got:\n%s", msg)
+	}
+}
+
+func TestFormatMessage_CashFlow(t *testing.T) {
+	s := newTestScheduler()
+	analysis := makeAnalysis("2026-01-19 to 2026-01-26", 200_000, nil, nil)
+	analysis.CashFlow = &processor.CashFlow{Income: 2_000_000, Spending: 200_000, Net: 1_800_000}
+
+	msg := s.formatMessage(analysis)
+
+	if !strings.Contains(msg, "💸 **Cash Flow**: $2,000 in, $200 out, net +$1,800\n") {
+		t.Errorf("expected the cash flow line, got:\n%s", msg)
+	}
+	if msg := s.formatMessage(makeAnalysis("2026-01-19 to 2026-01-26", 200_000, nil, nil)); strings.Contains(msg, "Cash Flow") {
+		t.Errorf("expected no cash flow line without transactions, got:\n%s", msg)
+	}
+}
+
+func TestFormatMessage_Savings(t *testing.T) {
+	s := newTestScheduler()
+	analysis := makeAnalysis("2026-01-19 to 2026-01-26", 200_000, nil, nil)
+	analysis.Savings = &processor.Savings{
+		Week:        &processor.CashFlow{Income: 2_000_000, Spending: 1_300_000, Net: 700_000},
+		MonthToDate: &processor.CashFlow{Income: 4_000_000, Spending: 3_400_000, Net: 600_000},
+		Target:      20,
+	}
+
+	msg := s.formatMessage(analysis)
+	if want := "🌱 **Savings Rate**: 35% this week, 15% this month (target 20%) ⚠️\n"; !strings.Contains(msg, want) {
+		t.Errorf("expected %q in the message, got:\n%s", want, msg)
+	}
+
+	analysis.Savings.Week = &processor.CashFlow{Spending: 200_000, Net: -200_000}
+	analysis.Savings.Target = 0
+	msg = s.formatMessage(analysis)
+	if want := "🌱 **Savings Rate**: no income this week, 15% this month\n"; !strings.Contains(msg, want) {
+		t.Errorf("expected %q in the message, got:\n%s", want, msg)
+	}
+}
+
+func TestFormatMessage_Guardrails(t *testing.T) {
+	s := newTestScheduler()
+	analysis := makeAnalysis("2026-01-19 to 2026-01-26", 200_000, nil, nil)
+	analysis.Projections = []processor.CategoryProjection{
+		{Category: "Dining Out", MonthSpent: 150_000, Projected: 465_000, Budgeted: 300_000, Over: 165_000, PerDay: 6_500, DaysLeft: 12},
+		{Category: "Groceries", MonthSpent: 590_000, Projected: 610_000, Budgeted: 600_000, Over: 10_000},
+	}
+
+	msg := s.formatMessage(analysis)
+	if want := "🚦 **To Finish the Month on Budget**\n• Dining Out: $6.50/day left over 12 days\n"; !strings.Contains(msg, want) {
+		t.Errorf("expected %q in the message, got:\n%s", want, msg)
+	}
+	if strings.Contains(msg, "Groceries: ") {
+		t.Errorf("no guardrail expected on the month's last day, got:\n%s", msg)
+	}
+}
+
+func TestFormatMessage_NoConcerns(t *testing.T) {
+	s := newTestScheduler()
+	analysis := makeAnalysis("2026-01-19 to 2026-01-26", 200_000, nil, nil)
+	msg := s.formatMessage(analysis)
+	if !strings.Contains(msg, "No categories over budget") {
+		t.Errorf("expected 'No categories over budget', got:\n%s", msg)
+	}
+}
+
+func TestFormatMessage_SeverityTiers(t *testing.T) {
+	s := newTestScheduler()
+	concerns := []processor.CategoryConcernWithTransactions{
+		{Category: "Car Repairs", Spent: 600_000, Balance: -400_000, Over: 400_000, Severity: processor.SeveritySevere},
+		{Category: "Coffee", Spent: 103_000, Balance: -3_000, Over: 3_000, Severity: processor.SeveritySlight},
+	}
+	msg := s.formatMessage(makeAnalysis("2026-01-19 to 2026-01-26", 703_000, nil, concerns))
+	for _, want := range []string{"\n🔴 **Car Repairs**: ", "\n🟡 **Coffee**: "} {
+		if !strings.Contains(msg, want) {
+			t.Errorf("expected %q in the message, got:\n%s", want, msg)
+		}
+	}
+}
+
+func TestFormatMessage_PlannedOffsets(t *testing.T) {
+	s := newTestScheduler()
+	concerns := []processor.CategoryConcernWithTransactions{
+		{Category: "Car Repairs", Spent: 600_000, Balance: -600_000, Over: 600_000, Uncovered: 100_000, Offsets: []processor.OffsetStep{
+			{Category: "Vacation Fund", Amount: 300_000}, {Category: "Emergency Fund", Amount: 200_000},
+		}},
+		{Category: "Dining", Spent: 50_000, Balance: -50_000, Over: 50_000, Uncovered: 50_000, Offsets: []processor.OffsetStep{
+			{Category: "Fun Money"},
+		}},
+	}
+	msg := s.formatMessage(makeAnalysis("2026-01-19 to 2026-01-26", 650_000, nil, concerns))
+	for _, want := range []string{
+		"  • Planned offset: Vacation Fund $300, then Emergency Fund $200, $100 still uncovered ⚠️\n",
+		"  • Planned offset: Fun Money (empty), $50 still uncovered ⚠️\n",
+	} {
+		if !strings.Contains(msg, want) {
+			t.Errorf("expected %q in the message, got:\n%s", want, msg)
+		}
+	}
+}
+
+func TestFormatMessage_Insights(t *testing.T) {
+	s := newTestScheduler()
+	analysis := makeAnalysis("2026-01-19 to 2026-01-26", 200_000, nil, nil)
+	analysis.Insights = []string{"Groceries came to $180, well above the usual $120"}
+
+	msg := s.formatMessage(analysis)
+	want := "\n💡 **Insights**\n• Groceries came to $180, well above the usual $120\n"
+	if !strings.Contains(msg, want) {
+		t.Errorf("expected %q in the message, got:\n%s", want, msg)
+	}
+	if msg := s.formatMessage(makeAnalysis("2026-01-19 to 2026-01-26", 200_000, nil, nil)); strings.Contains(msg, "Insights") {
+		t.Errorf("expected no insights section without insights, got:\n%s", msg)
+	}
+}
+
+func TestFormatMessage_SpendingOnly(t *testing.T) {
+	s := newTestScheduler()
+	analysis := makeAnalysis("2026-01-19 to 2026-01-26", 200_000, []processor.TopSpendingCategory{
+		{Category: "Groceries", Spent: 150_000},
 	}, nil)
+	analysis.NoBudget = true
+	analysis.Payees = []processor.SummaryPayee{{Payee: "Countdown", Spent: 120_000, Transactions: 3}}
 
-	msg := s.formatMonthlyMessage(analysis)
+	msg := s.formatMessage(analysis)
+	for _, want := range []string{
+		"• **Groceries**: Last Week Spend: $150\n",
+		"🛒 **Top Payees**\n• **Countdown**: $120 across 3 transactions\n",
+	} {
+		if !strings.Contains(msg, want) {
+			t.Errorf("expected %q in the message, got:\n%s", want, msg)
+		}
+	}
+	for _, unwanted := range []string{"Balance", "Over Budget"} {
+		if strings.Contains(msg, unwanted) {
+			t.Errorf("expected no %q in spending-only mode, got:\n%s", unwanted, msg)
+		}
+	}
+}
 
-	if !strings.Contains(msg, "-$30") {
-		t.Errorf("expected '-$30' for negative delta, got:\n%s", msg)
+func TestFormatMessage_Wins(t *testing.T) {
+	s := newTestScheduler()
+	analysis := makeAnalysis("Jan 19 - Jan 25, 2026", 700_000, nil, nil)
+
+	if msg := s.formatMessage(analysis); strings.Contains(msg, "**Wins**") {
+		t.Errorf("expected no wins section without wins, got:\n%s", msg)
+	}
+
+	analysis.Wins = []processor.CategoryWin{
+		{Category: "Groceries", Balance: 300_000},
+		{Category: "Transport", Balance: 50_000},
+	}
+	msg := s.formatMessage(analysis)
+	for _, want := range []string{"🎉 **Wins**", "• **Groceries**: $300 left", "• **Transport**: $50 left"} {
+		if !strings.Contains(msg, want) {
+			t.Errorf("expected %q in message, got:\n%s", want, msg)
+		}
+	}
+}
+
+func TestFormatMessage_WeekAhead(t *testing.T) {
+	s := newTestScheduler()
+	analysis := makeAnalysis("Jan 19 - Jan 25, 2026", 700_000, nil, nil)
+
+	analysis.AheadFocus = &processor.AheadFocus{WeeksLeft: 1}
+	if msg := s.formatMessage(analysis); strings.Contains(msg, "Week Ahead") {
+		t.Errorf("expected no week ahead with nothing to watch, got:\n%s", msg)
+	}
+
+	analysis.AheadFocus = &processor.AheadFocus{
+		Watch:       []string{"Transport", "Groceries"},
+		Adjustments: []processor.Adjustment{{Category: "Dining"}, {Category: "Car Repairs", Offset: "Vacation Fund"}},
+		WeeksLeft:   2,
+	}
+	msg := s.formatMessage(analysis)
+	for _, want := range []string{
+		"🗓️ **Week Ahead** (2 weeks left this month)",
+		"• Watch: **Transport**, **Groceries**",
+		"• Consider reducing Dining budget",
+		"• Cover Car Repairs from Vacation Fund, the planned offset",
+	} {
+		if !strings.Contains(msg, want) {
+			t.Errorf("expected %q in message, got:\n%s", want, msg)
+		}
+	}
+}
+
+func TestFormatMessage_Flagged(t *testing.T) {
+	s := newTestScheduler()
+	analysis := makeAnalysis("Jan 19 - Jan 25, 2026", 700_000, nil, nil)
+	date := time.Date(2026, 1, 20, 0, 0, 0, 0, time.UTC)
+	analysis.Flagged = []ynab.Transaction{
+		{Date: &date, PayeeName: "Bunnings", Amount: -45_000, CategoryName: "Home", FlagColor: "red"},
+		{PayeeName: "Cash", Amount: -20_000, FlagColor: "orange"},
+	}
+
+	msg := s.formatMessage(analysis)
+	for _, want := range []string{
+		"🚩 **Flagged Transactions**",
+		"• 01-20 **Bunnings**: $45 (Home, red)",
+		"• **Cash**: $20 (orange)",
+	} {
+		if !strings.Contains(msg, want) {
+			t.Errorf("expected %q in message, got:\n%s", want, msg)
+		}
+	}
+}
+
+func TestFormatMessage_NeedsAttention(t *testing.T) {
+	s := newTestScheduler()
+	date := time.Date(2026, 1, 20, 0, 0, 0, 0, time.UTC)
+	analysis := makeAnalysis("2026-01-19 to 2026-01-26", 200_000, nil, nil)
+	analysis.Attention = &processor.NeedsAttention{Uncategorized: []ynab.Transaction{
+		{Date: &date, PayeeName: "Countdown", Amount: -45_000},
+		{Date: &date, Memo: "Cash", Amount: -80_000},
+	}}
+
+	msg := s.formatMessage(analysis)
+	for _, want := range []string{
+		"📥 **Needs Attention**\n• 2 uncategorized transactions, $125 in total: categorize them in YNAB\n",
+		"  • 01-20: $45 - Countdown\n",
+		"  • 01-20: $80 - Cash\n",
+	} {
+		if !strings.Contains(msg, want) {
+			t.Errorf("expected %q in the message, got:\n%s", want, msg)
+		}
+	}
+}
+
+func TestFormatMessage_PendingTransactions(t *testing.T) {
+	s := newTestScheduler()
+	date := time.Date(2026, 1, 20, 0, 0, 0, 0, time.UTC)
+	analysis := makeAnalysis("2026-01-19 to 2026-01-26", 200_000, nil, nil)
+	analysis.Attention = &processor.NeedsAttention{
+		Unapproved: []ynab.Transaction{{Date: &date, PayeeName: "Countdown", Amount: -45_000}},
+		Uncleared:  []ynab.Transaction{{Date: &date, PayeeName: "Z Energy", Amount: -80_000}, {Date: &date, PayeeName: "Cafe", Amount: -12_000}},
+	}
+
+	msg := s.formatMessage(analysis)
+	for _, want := range []string{
+		"• 1 unapproved transactions, $45 in total: approve them in YNAB\n• 2 uncleared transactions, $92 in total: they may still change\n",
+	} {
+		if !strings.Contains(msg, want) {
+			t.Errorf("expected %q in the message, got:\n%s", want, msg)
+		}
+	}
+
+	analysis.Attention.ListPending = true
+	msg = s.formatMessage(analysis)
+	if want := "• 2 uncleared transactions, $92 in total: they may still change\n  • 01-20: $80 - Z Energy\n  • 01-20: $12 - Cafe\n"; !strings.Contains(msg, want) {
+		t.Errorf("expected %q in the message, got:\n%s", want, msg)
+	}
+}
+
+func TestFormatMessage_AccountGroups(t *testing.T) {
+	s := newTestScheduler()
+	analysis := makeAnalysis("2026-01-19 to 2026-01-26", 390_000, nil, nil)
+	analysis.Groups = []processor.AccountGroup{
+		{Group: "Joint", Spent: 350_000, Transactions: 5, TopCategories: []processor.GroupSpendLine{
+			{Category: "Groceries", Spent: 200_000}, {Category: "Dining", Spent: 80_000},
+		}},
+		{Spent: 40_000, Transactions: 1, TopCategories: []processor.GroupSpendLine{{Category: "Gifts", Spent: 40_000}}},
+	}
+
+	msg := s.formatMessage(analysis)
+	for _, want := range []string{
+		"👥 **By Account Group**\n",
+		"• Joint: $350 — Groceries $200, Dining $80\n",
+		"• Other accounts: $40 — Gifts $40\n",
+	} {
+		if !strings.Contains(msg, want) {
+			t.Errorf("expected %q in the message, got:\n%s", want, msg)
+		}
+	}
+}
+
+func TestFormatMessage_CardPayments(t *testing.T) {
+	s := newTestScheduler()
+	analysis := makeAnalysis("2026-01-19 to 2026-01-26", 430_000, nil, nil)
+	analysis.Cards = []processor.CardPayment{
+		{Account: "Visa", Spent: 280_000, Paid: 500_000, Owed: 420_000, Available: 420_000},
+		{Account: "Amex", Spent: 150_000, Owed: 900_000, Available: 600_000},
+	}
+
+	msg := s.formatMessage(analysis)
+	for _, want := range []string{
+		"💳 **Credit Card Payments**\n",
+		"• **Visa**: $280 spent this week, $500 paid, $420 owed, $420 set aside ✅\n",
+		"• **Amex**: $150 spent this week, $900 owed, $600 set aside, $300 short ⚠️\n",
+	} {
+		if !strings.Contains(msg, want) {
+			t.Errorf("expected %q in the message, got:\n%s", want, msg)
+		}
+	}
+}
+
+func TestFormatMessage_ActionItems(t *testing.T) {
+	s := newTestScheduler()
+	analysis := makeAnalysis("2026-01-19 to 2026-01-26", 200_000, nil, nil)
+	analysis.Actions = []processor.ActionItem{
+		{Kind: processor.ActionCover, Category: "Dining", From: "Fun Money", Amount: 50_000, CarriedOver: true},
+		{Kind: processor.ActionCover, Category: "Car Repair", Amount: 320_000},
+		{Kind: processor.ActionApprove, Transaction: "t1", CarriedOver: true},
+		{Kind: processor.ActionApprove, Transaction: "t2"},
+	}
+
+	msg := s.formatMessage(analysis)
+	for _, want := range []string{
+		"• Cover Dining's $50 overspending from Fun Money (still open from last week)\n",
+		"• Cover Car Repair's $320 overspending\n",
+		"• Approve 2 transactions (1 still open from last week)\n",
+	} {
+		if !strings.Contains(msg, want) {
+			t.Errorf("expected %q in the message, got:\n%s", want, msg)
+		}
+	}
+}
+
+func TestFormatMessage_Benchmarks(t *testing.T) {
+	s := newTestScheduler()
+	analysis := makeAnalysis("2026-01-19 to 2026-01-26", 150_000, nil, nil)
+	analysis.Benchmarks = []processor.BenchmarkComparison{
+		{Category: "Groceries", Target: 130_000, Actual: 150_000, Delta: 20_000},
+	}
+
+	msg := s.formatMessage(analysis)
+
+	if !strings.Contains(msg, "Weekly Benchmarks") {
+		t.Errorf("expected 'Weekly Benchmarks' section, got:\n%s", msg)
+	}
+	if !strings.Contains(msg, "$150 of $130 target (+$20)") {
+		t.Errorf("expected benchmark line, got:\n%s", msg)
+	}
+}
+
+func TestFormatMessage_NoBenchmarksSection(t *testing.T) {
+	s := newTestScheduler()
+	analysis := makeAnalysis("2026-01-19 to 2026-01-26", 150_000, nil, nil)
+	msg := s.formatMessage(analysis)
+	if strings.Contains(msg, "Weekly Benchmarks") {
+		t.Errorf("expected no benchmarks section when none configured, got:\n%s", msg)
+	}
+}
+
+func TestFormatMessage_SpendingSplit(t *testing.T) {
+	s := newTestScheduler()
+	analysis := makeAnalysis("2026-01-19 to 2026-01-26", 150_000, nil, nil)
+	analysis.Split = &processor.SpendingSplit{
+		NeedsPercent:   62,
+		WantsPercent:   28,
+		SavingsPercent: 10,
+		Untagged:       25_000,
+		Target:         processor.SplitTarget{Needs: 50, Wants: 30, Savings: 20},
+	}
+
+	msg := s.formatMessage(analysis)
+
+	if !strings.Contains(msg, "62% needs / 28% wants / 10% savings (target 50/30/20)") {
+		t.Errorf("expected split line, got:\n%s", msg)
+	}
+	if !strings.Contains(msg, "Untagged spending: $25") {
+		t.Errorf("expected untagged line, got:\n%s", msg)
+	}
+}
+
+func TestFormatMessage_ValidationNote(t *testing.T) {
+	s := newTestScheduler()
+	analysis := makeAnalysis("2026-01-19 to 2026-01-26", 150_000, nil, nil)
+	analysis.Validation = &ynab.Validation{UnknownCategory: 2, FutureDated: 1}
+
+	msg := s.formatMessage(analysis)
+	want := "ℹ️ Data checks: 2 transactions with a category no longer in the budget counted as uncategorized, 1 future-dated transactions left out\n"
+	if !strings.Contains(msg, want) {
+		t.Errorf("expected %q in the message, got:\n%s", want, msg)
+	}
+}
+
+func TestFormatMessage_FocusNote(t *testing.T) {
+	s := newTestScheduler()
+	analysis := makeAnalysis("2026-01-19 to 2026-01-26", 150_000, nil, nil)
+	analysis.Focus = []string{"Groceries", "Dining Out"}
+
+	msg := s.formatMessage(analysis)
+	if want := "🎯 Focused on Groceries, Dining Out, other categories left out\n"; !strings.Contains(msg, want) {
+		t.Errorf("expected %q in the message, got:\n%s", want, msg)
+	}
+}
+
+func TestFormatMessage_FirstReportNotes(t *testing.T) {
+	s := newTestScheduler()
+	analysis := makeAnalysis("2026-01-19 to 2026-01-26", 150_000, []processor.TopSpendingCategory{
+		{Category: "Dining", Spent: 150_000, Budgeted: 300_000, Balance: 150_000},
+	}, nil)
+	analysis.FirstReport = true
+	analysis.Coverage = &processor.PeriodCoverage{
+		From:       time.Date(2026, 1, 22, 0, 0, 0, 0, time.UTC),
+		To:         time.Date(2026, 1, 26, 0, 0, 0, 0, time.UTC),
+		Days:       5,
+		PeriodDays: 7,
+	}
+
+	msg := s.formatMessage(analysis)
+
+	if !strings.Contains(msg, "Partial week: covers 2026-01-22 to 2026-01-26 (5 of 7 days)") {
+		t.Errorf("expected partial week label, got:\n%s", msg)
+	}
+	if !strings.Contains(msg, "First report, comparisons unavailable") {
+		t.Errorf("expected first report note, got:\n%s", msg)
+	}
+	if strings.Contains(msg, "vs prev week") {
+		t.Errorf("expected no week-over-week delta on the first report, got:\n%s", msg)
+	}
+}
+
+func TestFormatMessage_ShowsDeltaWhenHasPrevData(t *testing.T) {
+	s := newTestScheduler()
+	analysis := makeAnalysisWithPrev("2026-01-19 to 2026-01-26", 150_000, []processor.TopSpendingCategory{
+		{Category: "Dining", Spent: 150_000, Budgeted: 300_000, Balance: 150_000, PrevSpent: 100_000, SpendDelta: 50_000},
+	}, nil)
+
+	msg := s.formatMessage(analysis)
+
+	if !strings.Contains(msg, "$150 (+$50 vs prev week)") {
+		t.Errorf("expected week-over-week delta, got:\n%s", msg)
+	}
+	if strings.Contains(msg, "First report") {
+		t.Errorf("expected no first report note, got:\n%s", msg)
+	}
+}
+
+// ── formatDelta / delta display ───────────────────────────────────────────────
+
+func TestFormatMonthlyMessage_Hygiene(t *testing.T) {
+	s := newTestScheduler()
+	analysis := makeAnalysis("January 2026", 200_000, nil, nil)
+	analysis.Hygiene = &processor.CategoryHygiene{
+		Months:         6,
+		UnusedMonths:   3,
+		Unused:         []string{"Boat", "Piano Lessons"},
+		HiddenBalances: []processor.EnvelopeBalance{{Category: "Old Car", Balance: 120_000}},
+		ChronicOver:    []processor.BudgetPattern{{Category: "Dining Out", Months: 5, OfMonths: 6, AvgBudgeted: 300_000, AvgSpent: 420_000}},
+		ChronicUnder:   []processor.BudgetPattern{{Category: "Clothing", Months: 4, OfMonths: 6, AvgBudgeted: 150_000, AvgSpent: 60_000}},
+	}
+
+	msg := s.formatMonthlyMessage(analysis)
+
+	for _, want := range []string{
+		"🧹 **Budget Tidy-Up**\n",
+		"• Unused for 3+ months: **Boat**, **Piano Lessons**. Delete them or merge them into a related category\n",
+		"• **Old Car** is hidden but holds $120. Move it to an active category\n",
+		"• **Dining Out** overspent in 5 of 6 months: $420 spent vs $300 budgeted on average. Consider budgeting more\n",
+		"• **Clothing** under half spent in 4 of 6 months: $60 spent vs $150 budgeted on average.",
+	} {
+		if !strings.Contains(msg, want) {
+			t.Errorf("expected %q in the monthly wrap, got:\n%s", want, msg)
+		}
+	}
+
+	analysis.Hygiene = &processor.CategoryHygiene{Months: 6, UnusedMonths: 3}
+	if msg := s.formatMonthlyMessage(analysis); strings.Contains(msg, "Tidy-Up") {
+		t.Errorf("expected no tidy-up section when nothing needs tidying, got:\n%s", msg)
+	}
+}
+
+func TestFormatMonthlyMessage_GoalProgress(t *testing.T) {
+	s := newTestScheduler()
+	due := time.Date(2026, 12, 1, 0, 0, 0, 0, time.UTC)
+	analysis := makeAnalysis("January 2026", 200_000, nil, nil)
+	analysis.Goals = []processor.GoalProgress{
+		{Category: "Vacation fund", Type: "TBD", Target: 3_000_000, Percent: 62, Remaining: 1_140_000, Due: &due},
+		{Category: "Emergency Fund", Type: "TB", Target: 5_000_000, Percent: 80, Remaining: 1_000_000},
+		{Category: "Rent", Type: "NEED", Target: 2_000_000, Percent: 100},
+	}
+
+	msg := s.formatMonthlyMessage(analysis)
+	for _, want := range []string{
+		"🏁 **Goal Progress**\n",
+		"• **Vacation fund**: 62% of $3,000 target, $1,140 to go by Dec 2026\n",
+		"• **Emergency Fund**: 80% of $5,000 target, $1,000 to go\n",
+		"• **Rent**: $2,000 target fully funded ✅\n",
+	} {
+		if !strings.Contains(msg, want) {
+			t.Errorf("expected %q in the monthly wrap, got:\n%s", want, msg)
+		}
+	}
+	if msg := s.formatMessage(analysis); !strings.Contains(msg, "**Goal Progress**") {
+		t.Errorf("expected goal progress in the weekly wrap too, got:\n%s", msg)
+	}
+}
+
+func TestFormatMonthlyMessage_SpendingPatterns(t *testing.T) {
+	s := newTestScheduler()
+	analysis := makeAnalysis("January 2026", 200_000, nil, nil)
+	analysis.Patterns = &processor.SpendingPattern{
+		Months:        3,
+		Even:          28.6,
+		Peak:          &processor.WeekdayPeak{From: time.Friday, To: time.Saturday, Share: 45},
+		OverspentPeak: &processor.WeekdayPeak{From: time.Saturday, To: time.Sunday, Share: 62},
+	}
+
+	msg := s.formatMonthlyMessage(analysis)
+	for _, want := range []string{
+		"🗓️ **Spending Patterns, Last 3 Months**\n",
+		"• 45% of spending happens Fri–Sat (29% if spread evenly)\n",
+		"• 62% of spending in overspent categories happens Sat–Sun (29% if spread evenly)\n",
+	} {
+		if !strings.Contains(msg, want) {
+			t.Errorf("expected %q in the monthly wrap, got:\n%s", want, msg)
+		}
+	}
+}
+
+func TestFormatMonthlyMessage_ShowsDeltaWhenHasPrevData(t *testing.T) {
+	s := newTestScheduler()
+	analysis := makeAnalysisWithPrev("January 2026", 350_000, []processor.TopSpendingCategory{
+		{Category: "Dining", Spent: 350_000, Budgeted: 300_000, Balance: -50_000, PrevSpent: 300_000, SpendDelta: 50_000},
+	}, nil)
+
+	msg := s.formatMonthlyMessage(analysis)
+
+	if !strings.Contains(msg, "vs prev month") {
+		t.Errorf("expected 'vs prev month' when HasPrevData=true, got:\n%s", msg)
+	}
+}
+
+func TestFormatMonthlyMessage_NoDeltaWhenNoPrevData(t *testing.T) {
+	s := newTestScheduler()
+	analysis := makeAnalysis("January 2026", 350_000, []processor.TopSpendingCategory{
+		{Category: "Dining", Spent: 350_000, Budgeted: 300_000, Balance: -50_000},
+	}, nil)
+
+	msg := s.formatMonthlyMessage(analysis)
+
+	if strings.Contains(msg, "vs prev month") {
+		t.Errorf("expected no 'vs prev month' when HasPrevData=false, got:\n%s", msg)
+	}
+}
+
+func TestFormatMonthlyMessage_PositiveDelta(t *testing.T) {
+	s := newTestScheduler()
+	// SpendDelta=50_000 millicents → +$50
+	analysis := makeAnalysisWithPrev("January 2026", 350_000, []processor.TopSpendingCategory{
+		{Category: "Dining", Spent: 350_000, Budgeted: 300_000, Balance: -50_000, PrevSpent: 300_000, SpendDelta: 50_000},
+	}, nil)
+
+	msg := s.formatMonthlyMessage(analysis)
+
+	if !strings.Contains(msg, "+$50") {
+		t.Errorf("expected '+$50' for positive delta, got:\n%s", msg)
+	}
+}
+
+func TestFormatMonthlyMessage_NegativeDelta(t *testing.T) {
+	s := newTestScheduler()
+	// SpendDelta=-30_000 millicents → -$30
+	analysis := makeAnalysisWithPrev("January 2026", 150_000, []processor.TopSpendingCategory{
+		{Category: "Transport", Spent: 150_000, Budgeted: 200_000, Balance: 50_000, PrevSpent: 180_000, SpendDelta: -30_000},
+	}, nil)
+
+	msg := s.formatMonthlyMessage(analysis)
+
+	if !strings.Contains(msg, "-$30") {
+		t.Errorf("expected '-$30' for negative delta, got:\n%s", msg)
+	}
+}
+
+// ── formatOverdraftRisks ──────────────────────────────────────────────────────
+
+func TestFormatOverdraftRisks(t *testing.T) {
+	s := newTestScheduler()
+	msg := s.formatOverdraftRisks([]processor.OverdraftRisk{
+		{
+			AccountName:  "Joint Checking",
+			Balance:      500_000,
+			ProjectedLow: -150_000,
+			NegativeOn:   time.Date(2026, 2, 4, 0, 0, 0, 0, time.UTC),
+			NextIncome:   time.Date(2026, 2, 14, 0, 0, 0, 0, time.UTC),
+		},
+	}, money.USD)
+
+	if !strings.Contains(msg, "Overdraft Risk") {
+		t.Errorf("expected 'Overdraft Risk' header, got:\n%s", msg)
+	}
+	if !strings.Contains(msg, "**Joint Checking**: $500 now, projected to go negative on 02-04 (low -$150, next income 02-14)") {
+		t.Errorf("unexpected overdraft line, got:\n%s", msg)
+	}
+}
+
+func TestFormatOverdraftRisks_None(t *testing.T) {
+	s := newTestScheduler()
+	if msg := s.formatOverdraftRisks(nil, money.USD); msg != "" {
+		t.Errorf("expected empty section when no risks, got:\n%s", msg)
+	}
+}
+
+// ── formatStaleImports ────────────────────────────────────────────────────────
+
+func TestFormatFraudSignals(t *testing.T) {
+	s := newTestScheduler()
+	day := time.Date(2026, 3, 19, 0, 0, 0, 0, time.UTC)
+	msg := s.formatFraudSignals([]processor.FraudSignal{
+		{Pattern: processor.FraudMicroCharges, Transactions: []ynab.Transaction{
+			{PayeeName: "VERIFY*CARD", Amount: -1_000, Date: &day},
+			{PayeeName: "Acme Digital", Amount: -750, Date: &day},
+		}},
+		{Pattern: processor.FraudRepeatedAmount, Amount: 37_420, Transactions: []ynab.Transaction{
+			{PayeeName: "Shop One", Amount: -37_420, Date: &day},
+			{PayeeName: "Shop Two", Amount: -37_420, Date: &day},
+		}},
+	}, money.USD)
+
+	for _, want := range []string{
+		"🕵️ **Possible Card Fraud**\n",
+		"• **2 tiny charges** from new payees: VERIFY*CARD $1 (03-19), Acme Digital $0.75 (03-19)\n",
+		"• **$37.42 charged 2 times** by different new payees: Shop One $37.42 (03-19), Shop Two $37.42 (03-19)\n",
+	} {
+		if !strings.Contains(msg, want) {
+			t.Errorf("expected %q in fraud section, got:\n%s", want, msg)
+		}
+	}
+	if msg := s.formatFraudSignals(nil, money.USD); msg != "" {
+		t.Errorf("expected empty section without signals, got:\n%s", msg)
+	}
+}
+
+func TestFormatStaleImports(t *testing.T) {
+	s := newTestScheduler()
+	msg := s.formatStaleImports([]processor.StaleImport{{
+		AccountName:     "Joint Checking",
+		LastTransaction: time.Date(2026, 1, 25, 0, 0, 0, 0, time.UTC),
+		DaysSilent:      6,
+		ActiveDays:      17,
+		HistoryDays:     25,
+	}})
+
+	want := "🔌 **Bank Import May Be Broken**\n• **Joint Checking**: no transactions for 6 days, last on 01-25 (usually active 17 of 25 days)\n"
+	if !strings.HasPrefix(msg, want) {
+		t.Errorf("unexpected stale import section, got:\n%s", msg)
+	}
+	if msg := s.formatStaleImports(nil); msg != "" {
+		t.Errorf("expected empty section when imports are current, got:\n%s", msg)
+	}
+}
+
+// ── retry / failure alerts ────────────────────────────────────────────────────
+
+// recordingPublisher captures published messages and optionally fails
+type recordingPublisher struct {
+	name     string
+	messages []string
+	err      error
+}
+
+func (p *recordingPublisher) Name() string {
+	if p.name == "" {
+		return "recorder"
+	}
+	return p.name
+}
+
+func (p *recordingPublisher) Publish(message string) error {
+	p.messages = append(p.messages, message)
+	return p.err
+}
+
+func newRetryScheduler(pub publisher.Publisher) *Scheduler {
+	cfg := &config.Config{}
+	cfg.Schedule.RetryAttempts = 3
+	return &Scheduler{config: cfg, publishers: []publisher.Publisher{pub}}
+}
+
+func TestRunJob_RetriesTransientErrors(t *testing.T) {
+	pub := &recordingPublisher{}
+	s := newRetryScheduler(pub)
+
+	calls := 0
+	s.runJob("Weekly wrap", func() error {
+		calls++
+		if calls < 3 {
+			return fmt.Errorf("failed to get weekly data: connection reset")
+		}
+		return nil
+	})()
+
+	if calls != 3 {
+		t.Errorf("calls: got %d, want 3", calls)
+	}
+	if len(pub.messages) != 0 {
+		t.Errorf("expected no failure alert after a successful retry, got %v", pub.messages)
+	}
+}
+
+func TestRunJob_AuthErrorAlertsWithoutRetry(t *testing.T) {
+	pub := &recordingPublisher{}
+	s := newRetryScheduler(pub)
+
+	calls := 0
+	s.runJob("Weekly wrap", func() error {
+		calls++
+		return fmt.Errorf("failed to get weekly data: %w", ynab.ErrYNABAuth)
+	})()
+
+	if calls != 1 {
+		t.Errorf("calls: got %d, want 1 (auth errors are not retried)", calls)
+	}
+	if len(pub.messages) != 1 || !strings.Contains(pub.messages[0], "YNAB_API_TOKEN") {
+		t.Errorf("expected a failure alert mentioning YNAB_API_TOKEN, got %v", pub.messages)
+	}
+}
+
+func TestRunJob_DeliveryErrorNotRetriedOrAlerted(t *testing.T) {
+	pub := &recordingPublisher{err: errors.New("chat not found")}
+	s := newRetryScheduler(pub)
+
+	calls := 0
+	s.runJob("Weekly wrap", func() error {
+		calls++
+		return s.deliver("Weekly wrap", publisher.Report{Message: "wrap"})
+	})()
+
+	if calls != 1 {
+		t.Errorf("calls: got %d, want 1 (delivery errors are not retried)", calls)
+	}
+	if len(pub.messages) != 1 {
+		t.Errorf("expected only the original send, got %v", pub.messages)
+	}
+}
+
+func TestDeliver_ReturnsDeliveryError(t *testing.T) {
+	s := newRetryScheduler(&recordingPublisher{err: errors.New("chat not found")})
+
+	err := s.deliver("Weekly wrap", publisher.Report{Message: "wrap"})
+	if !errors.Is(err, ErrDelivery) {
+		t.Fatalf("expected ErrDelivery, got %v", err)
+	}
+	if !strings.Contains(err.Error(), "chat not found") {
+		t.Errorf("expected publisher error in %q", err)
+	}
+}
+
+func TestNotify_SendsMessageWithTitle(t *testing.T) {
+	pub := &recordingPublisher{}
+	s := newRetryScheduler(pub)
+
+	if err := s.Notify("Backup", "  Nightly backup finished  \n"); err != nil {
+		t.Fatalf("Notify: %v", err)
+	}
+	want := "**Backup**\n\nNightly backup finished\n"
+	if len(pub.messages) != 1 || pub.messages[0] != want {
+		t.Errorf("messages: got %q, want [%q]", pub.messages, want)
+	}
+
+	if err := s.Notify("", " \n"); err == nil {
+		t.Error("expected an error for an empty notification")
+	}
+	if len(pub.messages) != 1 {
+		t.Errorf("expected an empty notification not to be sent, got %q", pub.messages)
+	}
+}
+
+func TestDeliver_NotifiesWorkingDestinationsOfFailures(t *testing.T) {
+	working := &recordingPublisher{name: "discord"}
+	broken := &recordingPublisher{name: "email", err: errors.New("smtp down")}
+	s := newRetryScheduler(working)
+	s.publishers = append(s.publishers, broken)
+
+	result, err := s.deliverWithResult("Weekly wrap", publisher.Report{Message: "wrap"})
+	if !errors.Is(err, ErrDelivery) {
+		t.Fatalf("expected ErrDelivery, got %v", err)
+	}
+	if len(result.Successes) != 1 || len(result.Failures) != 1 {
+		t.Errorf("unexpected result: %+v", result)
+	}
+
+	if len(working.messages) != 2 {
+		t.Fatalf("expected the wrap and a delivery notice on discord, got %v", working.messages)
+	}
+	notice := working.messages[1]
+	if !strings.Contains(notice, "1/2 destinations succeeded; failed: email") || !strings.Contains(notice, "smtp down") {
+		t.Errorf("unexpected notice: %s", notice)
+	}
+	if len(broken.messages) != 1 {
+		t.Errorf("expected no notice on the failing destination, got %v", broken.messages)
+	}
+}
+
+// ── pipelines ─────────────────────────────────────────────────────────────────
+
+type recordingExporter struct{ runs []string }
+
+func (e *recordingExporter) Export(run *pipeline.Run) error {
+	e.runs = append(e.runs, run.Name)
+	return nil
+}
+
+func TestNewPipeline_AddsExportStageAndHooks(t *testing.T) {
+	exporter := &recordingExporter{}
+	var stages []string
+	s := newTestScheduler()
+	WithExporters(exporter)(s)
+	WithHooks(pipeline.Hook{
+		AfterStage: func(stage string, run *pipeline.Run, timing pipeline.StageTiming) {
+			stages = append(stages, stage)
+		},
+	})(s)
+
+	p := s.newPipeline(
+		pipeline.NewStage(pipeline.StageRender, func(ctx context.Context, run *pipeline.Run) error {
+			run.Report = publisher.Report{Message: "wrap"}
+			return nil
+		}),
+		pipeline.NewStage(pipeline.StageDeliver, s.deliverRun),
+	)
+	if err := p.Run(context.Background(), &pipeline.Run{Name: "Weekly wrap"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if strings.Join(stages, ",") != "render,deliver,export" {
+		t.Errorf("stages: got %v", stages)
+	}
+	if len(exporter.runs) != 1 || exporter.runs[0] != "Weekly wrap" {
+		t.Errorf("exporter runs: got %v", exporter.runs)
+	}
+}
+
+func TestLatest_KeepsMostRecentPeriod(t *testing.T) {
+	s := newTestScheduler()
+	if run, _ := s.Latest(); run != nil {
+		t.Fatalf("expected no latest run, got %+v", run)
+	}
+	s.latest = &latestRun{}
+
+	end := time.Date(2026, 1, 25, 23, 59, 59, 0, time.UTC)
+	weekly := func(end time.Time) *pipeline.Run {
+		return &pipeline.Run{PeriodEnd: end, Weekly: &ynab.WeeklyData{}, Analysis: &processor.AnalysisResult{}}
+	}
+	current, past := weekly(end), weekly(end.AddDate(0, 0, -14))
+	for _, run := range []*pipeline.Run{current, past, {Daily: &ynab.DailyData{}, Analysis: &processor.AnalysisResult{}}} {
+		if err := s.latest.Export(run); err != nil {
+			t.Fatalf("Export failed: %v", err)
+		}
+	}
+	if run, at := s.Latest(); run != current || at.IsZero() {
+		t.Errorf("expected the current week to be kept, got %+v at %s", run, at)
+	}
+}
+
+// ── RerenderWeek ─────────────────────────────────────────────────────────────
+
+func TestRerenderWeek_RendersSnapshotWithoutDelivering(t *testing.T) {
+	pub := &recordingPublisher{}
+	cfg := &config.Config{}
+	cfg.Thresholds.TopCategoriesCount = 3
+	s := &Scheduler{config: cfg, analyzer: processor.NewAnalyzer(), publishers: []publisher.Publisher{pub}}
+
+	end := time.Date(2024, 3, 24, 9, 0, 0, 0, time.UTC)
+	date := end.AddDate(0, 0, -2)
+	catID := "cat-1"
+	snapshot := &history.Snapshot{
+		Week:        "2024-W12",
+		PeriodStart: end.AddDate(0, 0, -7),
+		PeriodEnd:   end,
+		Weekly: &ynab.WeeklyData{
+			Budget: &ynab.Budget{ID: "b1", Name: "Budget"},
+			Categories: []ynab.Category{
+				{ID: catID, Name: "Groceries", Budgeted: 400_000, Activity: -150_000, CategoryGroup: ynab.CategoryGroup{Name: "Everyday"}},
+			},
+			Transactions: []ynab.Transaction{
+				{ID: "t1", Date: &date, Amount: -150_000, CategoryID: &catID, CategoryName: "Groceries"},
+			},
+			WeekStart: end.AddDate(0, 0, -7),
+			WeekEnd:   end,
+		},
+	}
+
+	run, err := s.RerenderWeek(snapshot)
+	if err != nil {
+		t.Fatalf("RerenderWeek failed: %v", err)
+	}
+	if !strings.Contains(run.Report.Message, "Groceries") {
+		t.Errorf("expected rendered message to include Groceries, got:\n%s", run.Report.Message)
+	}
+	if len(pub.messages) != 0 {
+		t.Errorf("expected nothing to be delivered, got %v", pub.messages)
+	}
+	if len(run.Timings) != 2 {
+		t.Errorf("expected only analyze and render stages, got %+v", run.Timings)
+	}
+}
+
+func TestRerenderWeek_ExcludeFlagsLeavesOutSections(t *testing.T) {
+	cfg := &config.Config{}
+	cfg.Accounts.Pending = true
+	cfg.Accounts.Groups = map[string]string{"Card": "Everyday"}
+	cfg.Savings.Rate = true
+	s := &Scheduler{config: cfg, analyzer: processor.NewAnalyzer(processor.WithFlags([]string{"purple"}, nil))}
+
+	end := time.Date(2024, 3, 24, 9, 0, 0, 0, time.UTC)
+	catID := "cat-1"
+	run, err := s.RerenderWeek(&history.Snapshot{
+		Week:        history.WeekOf(end),
+		PeriodStart: end.AddDate(0, 0, -7),
+		PeriodEnd:   end,
+		Weekly: &ynab.WeeklyData{
+			Budget:     &ynab.Budget{ID: "b1", Name: "Budget"},
+			Categories: []ynab.Category{{ID: catID, Name: "Home", Budgeted: 500_000}},
+			Transactions: []ynab.Transaction{
+				{ID: "t1", Amount: -30_000, CategoryID: &catID, CategoryName: "Home", AccountName: "Card"},
+				{ID: "t2", Amount: -90_000, CategoryID: &catID, CategoryName: "Home", AccountName: "Card", FlagColor: "purple", Uncleared: true},
+			},
+			WeekStart: end.AddDate(0, 0, -7),
+			WeekEnd:   end,
+		},
+	})
+	if err != nil {
+		t.Fatalf("RerenderWeek failed: %v", err)
+	}
+	analysis := run.Analysis
+	if analysis.Attention != nil {
+		t.Errorf("expected the purple-flagged transaction left out of pending items, got %+v", analysis.Attention)
+	}
+	if len(analysis.Groups) != 1 || analysis.Groups[0].Spent != 30_000 {
+		t.Errorf("expected only t1 in the group totals, got %+v", analysis.Groups)
+	}
+	if analysis.Savings == nil || analysis.Savings.Week.Spending != 30_000 {
+		t.Errorf("expected only t1 in the savings, got %+v", analysis.Savings)
+	}
+}
+
+func TestRerenderWeek_ListsNewPayees(t *testing.T) {
+	cfg := &config.Config{}
+	cfg.Thresholds.TopCategoriesCount = 3
+	cfg.History.NewPayees = true
+	cfg.History.NewPayeeMonths = 6
+	store := history.NewStore(t.TempDir())
+	s := &Scheduler{config: cfg, analyzer: processor.NewAnalyzer(), history: store}
+
+	end := time.Date(2024, 3, 24, 9, 0, 0, 0, time.UTC)
+	snapshotFor := func(end time.Time, payees ...string) *history.Snapshot {
+		var transactions []ynab.Transaction
+		for i, payee := range payees {
+			date := end.AddDate(0, 0, -i-1)
+			transactions = append(transactions, ynab.Transaction{ID: payee, Date: &date, Amount: -50_000, CategoryName: "Groceries", PayeeName: payee})
+		}
+		return &history.Snapshot{
+			Week:        history.WeekOf(end),
+			PeriodStart: end.AddDate(0, 0, -7),
+			PeriodEnd:   end,
+			Weekly: &ynab.WeeklyData{
+				Budget:       &ynab.Budget{ID: "b1", Name: "Budget"},
+				Transactions: transactions,
+				WeekStart:    end.AddDate(0, 0, -7),
+				WeekEnd:      end,
+			},
+		}
+	}
+
+	// Too little history to tell what is new
+	if err := store.Save(snapshotFor(end.AddDate(0, 0, -7), "Countdown")); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+	run, err := s.RerenderWeek(snapshotFor(end, "Countdown", "Bright Dental"))
+	if err != nil {
+		t.Fatalf("RerenderWeek failed: %v", err)
+	}
+	if len(run.Analysis.NewPayees) != 0 {
+		t.Errorf("expected no new payees with a week of history, got %v", run.Analysis.NewPayees)
+	}
+
+	if err := store.Save(snapshotFor(end.AddDate(0, 0, -42), "Netflix")); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+	run, err = s.RerenderWeek(snapshotFor(end, "Countdown", "Bright Dental", "Netflix"))
+	if err != nil {
+		t.Fatalf("RerenderWeek failed: %v", err)
+	}
+	if got := run.Analysis.NewPayees; len(got) != 1 || got[0] != "Bright Dental" {
+		t.Errorf("expected only Bright Dental to be new, got %v", got)
+	}
+	if !strings.Contains(run.Report.Message, "'Bright Dental'") {
+		t.Errorf("expected the new payee in the message, got:\n%s", run.Report.Message)
+	}
+}
+
+func TestRerenderWeek_NetWorth(t *testing.T) {
+	cfg := &config.Config{}
+	cfg.Thresholds.TopCategoriesCount = 3
+	cfg.Accounts.NetWorth = true
+	store := history.NewStore(t.TempDir())
+	s := &Scheduler{config: cfg, analyzer: processor.NewAnalyzer(), history: store}
+
+	snapshotFor := func(end time.Time, checking, mortgage int64) *history.Snapshot {
+		start := end.AddDate(0, 0, -6).Truncate(24 * time.Hour)
+		return &history.Snapshot{
+			Week:        history.WeekOf(end),
+			PeriodStart: start,
+			PeriodEnd:   end,
+			Weekly: &ynab.WeeklyData{
+				Budget: &ynab.Budget{ID: "b1", Name: "Budget"},
+				Accounts: []ynab.Account{
+					{Name: "Checking", OnBudget: true, Balance: checking},
+					{Name: "Mortgage", Balance: mortgage},
+				},
+				WeekStart: start,
+				WeekEnd:   end,
+			},
+		}
+	}
+	end := time.Date(2024, 3, 24, 23, 59, 59, 0, time.UTC)
+	for _, snapshot := range []*history.Snapshot{
+		snapshotFor(end.AddDate(0, 0, -28), 2_000_000, -1_000_000), // Last week of February
+		snapshotFor(end.AddDate(0, 0, -7), 2_500_000, -1_000_000),
+	} {
+		if err := store.Save(snapshot); err != nil {
+			t.Fatalf("Save failed: %v", err)
+		}
+	}
+
+	run, err := s.RerenderWeek(snapshotFor(end, 2_800_000, -1_000_000))
+	if err != nil {
+		t.Fatalf("RerenderWeek failed: %v", err)
+	}
+	want := "🏦 **Net Worth**: $1,800 (+$300 vs last week, +$800 this month)\n"
+	if !strings.Contains(run.Report.Message, want) {
+		t.Errorf("expected %q in the message, got:\n%s", want, run.Report.Message)
+	}
+}
+
+func TestRerenderWeek_AgeOfMoney(t *testing.T) {
+	cfg := &config.Config{}
+	cfg.Thresholds.TopCategoriesCount = 3
+	store := history.NewStore(t.TempDir())
+	s := &Scheduler{config: cfg, analyzer: processor.NewAnalyzer(), history: store}
+
+	snapshotFor := func(end time.Time, days int) *history.Snapshot {
+		start := end.AddDate(0, 0, -6).Truncate(24 * time.Hour)
+		return &history.Snapshot{
+			Week:        history.WeekOf(end),
+			PeriodStart: start,
+			PeriodEnd:   end,
+			Weekly: &ynab.WeeklyData{
+				Budget:     &ynab.Budget{ID: "b1", Name: "Budget"},
+				AgeOfMoney: &days,
+				WeekStart:  start,
+				WeekEnd:    end,
+			},
+		}
+	}
+	end := time.Date(2024, 3, 24, 23, 59, 59, 0, time.UTC)
+	if err := store.Save(snapshotFor(end.AddDate(0, 0, -7), 45)); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+
+	run, err := s.RerenderWeek(snapshotFor(end, 42))
+	if err != nil {
+		t.Fatalf("RerenderWeek failed: %v", err)
+	}
+	want := "⏳ **Age of Money**: 42 days (-3 vs last week)\n"
+	if !strings.Contains(run.Report.Message, want) {
+		t.Errorf("expected %q in the message, got:\n%s", want, run.Report.Message)
+	}
+}
+
+func TestRerenderWeek_ActionItems(t *testing.T) {
+	cfg := &config.Config{}
+	cfg.Thresholds.TopCategoriesCount = 3
+	cfg.Actions.Enabled = true
+	store := history.NewStore(t.TempDir())
+	s := &Scheduler{config: cfg, analyzer: processor.NewAnalyzer(), history: store}
+
+	end := time.Date(2024, 3, 24, 23, 59, 59, 0, time.UTC)
+	start := time.Date(2024, 3, 18, 0, 0, 0, 0, time.UTC)
+	if err := store.Save(&history.Snapshot{
+		Week:        history.WeekOf(end.AddDate(0, 0, -7)),
+		PeriodStart: start.AddDate(0, 0, -7),
+		PeriodEnd:   end.AddDate(0, 0, -7),
+		Actions: []processor.ActionItem{
+			{Kind: processor.ActionCover, Category: "Dining", From: "Fun Money", Amount: 50_000},
+			{Kind: processor.ActionApprove, Transaction: "t1", Amount: -45_000},
+		},
+	}); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+
+	txDate := start.AddDate(0, 0, -3)
+	run, err := s.RerenderWeek(&history.Snapshot{
+		Week:        history.WeekOf(end),
+		PeriodStart: start,
+		PeriodEnd:   end,
+		Weekly: &ynab.WeeklyData{
+			Budget: &ynab.Budget{ID: "b1", Name: "Budget"},
+			Categories: []ynab.Category{
+				{ID: "c1", Name: "Dining", Budgeted: 300_000, Balance: 20_000},
+				{ID: "c2", Name: "Fun Money", Budgeted: 100_000, Balance: 30_000},
+			},
+			Unapproved: []ynab.Transaction{{ID: "t1", Date: &txDate, Amount: -45_000, Unapproved: true}},
+			WeekStart:  start,
+			WeekEnd:    end,
+		},
+	})
+	if err != nil {
+		t.Fatalf("RerenderWeek failed: %v", err)
+	}
+	for _, want := range []string{
+		"🎯 **Action Items**\n✅ Last week: 1 of 2 done\n",
+		"• Approve 1 transaction (still open from last week)\n",
+	} {
+		if !strings.Contains(run.Report.Message, want) {
+			t.Errorf("expected %q in the message, got:\n%s", want, run.Report.Message)
+		}
+	}
+}
+
+// ── templates ────────────────────────────────────────────────────────────────
+
+func writeTemplate(t *testing.T, text string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "weekly.tmpl")
+	if err := os.WriteFile(path, []byte(text), 0o644); err != nil {
+		t.Fatalf("WriteFile failed: %v", err)
+	}
+	return path
+}
+
+func TestFormatWithTemplate(t *testing.T) {
+	s := newTestScheduler()
+	tmpl, err := s.loadTemplate(writeTemplate(t, `{{emoji "chart"}} **{{.DateRange}}**
+Spent {{currency .Overview.TotalSpent}} ({{percent .Overview.HealthPercentage}} of budget)
+{{range .Wins}}{{emoji "trophy"}} {{.Category}}: {{currency .Balance}} left
+{{end}}`))
+	if err != nil {
+		t.Fatalf("loadTemplate failed: %v", err)
+	}
+
+	analysis := makeAnalysis("2026-01-19 to 2026-01-26", 212_340, nil, nil)
+	analysis.Overview.HealthPercentage = 64.4
+	analysis.Wins = []processor.CategoryWin{{Category: "Dining", Balance: -5_000}}
+
+	msg := s.formatWithTemplate(tmpl, analysis, s.formatMessage)
+
+	want := "📊 **2026-01-19 to 2026-01-26**\nSpent $212.34 (64% of budget)\n🏆 Dining: -$5 left\n"
+	if msg != want {
+		t.Errorf("got:\n%q\nwant:\n%q", msg, want)
+	}
+}
+
+func TestFormatWithTemplate_Adjustments(t *testing.T) {
+	s := newTestScheduler()
+	tmpl, err := s.loadTemplate(writeTemplate(t, `{{range .AheadFocus.Adjustments}}• {{.}}
+{{end}}{{range .AheadFocus.Adjustments}}{{.Category}}: {{.Text}}
+{{end}}`))
+	if err != nil {
+		t.Fatalf("loadTemplate failed: %v", err)
+	}
+	analysis := makeAnalysis("2026-01-19 to 2026-01-26", 200_000, nil, nil)
+	analysis.AheadFocus = &processor.AheadFocus{Adjustments: []processor.Adjustment{
+		{Category: "Dining", Offset: "Fun Money"},
+		{Category: "Shopping"},
+	}}
+
+	want := "• Cover Dining from Fun Money, the planned offset\n• Consider reducing Shopping budget\n" +
+		"Dining: Cover Dining from Fun Money, the planned offset\nShopping: Consider reducing Shopping budget\n"
+	if got := s.formatWithTemplate(tmpl, analysis, s.formatMessage); got != want {
+		t.Errorf("got:\n%q\nwant:\n%q", got, want)
+	}
+}
+
+func TestFormatWithTemplate_FallsBackOnError(t *testing.T) {
+	s := newTestScheduler()
+	tmpl, err := s.loadTemplate(writeTemplate(t, `{{emoji "unicorn"}} {{.DateRange}}`))
+	if err != nil {
+		t.Fatalf("loadTemplate failed: %v", err)
+	}
+	analysis := makeAnalysis("2026-01-19 to 2026-01-26", 200_000, nil, nil)
+
+	if got, want := s.formatWithTemplate(tmpl, analysis, s.formatMessage), s.formatMessage(analysis); got != want {
+		t.Errorf("expected the built-in layout, got:\n%s", got)
+	}
+	if got, want := s.formatWithTemplate(nil, analysis, s.formatMessage), s.formatMessage(analysis); got != want {
+		t.Errorf("expected the built-in layout without a template, got:\n%s", got)
+	}
+}
+
+func TestLoadTemplate_Errors(t *testing.T) {
+	s := newTestScheduler()
+	if tmpl, err := s.loadTemplate(""); tmpl != nil || err != nil {
+		t.Errorf("expected no template for an empty path, got %v, %v", tmpl, err)
+	}
+	if _, err := s.loadTemplate(filepath.Join(t.TempDir(), "missing.tmpl")); err == nil {
+		t.Error("expected an error for a missing file")
+	}
+	if _, err := s.loadTemplate(writeTemplate(t, `{{.DateRange`)); err == nil {
+		t.Error("expected an error for a malformed template")
+	}
+}
+
+// ── schedule ─────────────────────────────────────────────────────────────────
+
+func TestSchedule_SecondsAndTimezones(t *testing.T) {
+	s := &Scheduler{cron: cron.New(cron.WithParser(cronParser))}
+	noop := func() error { return nil }
+
+	if err := s.schedule("Weekly wrap", "0 9 * * 1", "Pacific/Auckland", noop); err != nil {
+		t.Fatalf("schedule failed: %v", err)
+	}
+	if err := s.schedule("Alert check", "0 */30 * * * *", "UTC", noop); err != nil {
+		t.Fatalf("schedule failed: %v", err)
+	}
+	if err := s.schedule("Monthly wrap", "CRON_TZ=America/New_York 0 9 1 * *", "UTC", noop); err != nil {
+		t.Fatalf("schedule failed: %v", err)
+	}
+
+	from := time.Date(2026, 1, 4, 0, 10, 0, 0, time.UTC) // Sunday afternoon in Auckland
+	want := []time.Time{
+		time.Date(2026, 1, 4, 20, 0, 0, 0, time.UTC), // Monday 9am NZDT
+		time.Date(2026, 1, 4, 0, 30, 0, 0, time.UTC),
+		time.Date(2026, 2, 1, 14, 0, 0, 0, time.UTC), // 9am EST, ignoring the UTC default
+	}
+	for i, entry := range s.cron.Entries() {
+		if got := entry.Schedule.Next(from); !got.Equal(want[i]) {
+			t.Errorf("job %d: next run at %s, want %s", i, got.UTC(), want[i])
+		}
+	}
+}
+
+func TestSchedule_InvalidExpression(t *testing.T) {
+	s := &Scheduler{cron: cron.New(cron.WithParser(cronParser))}
+	err := s.schedule("Weekly wrap", "0 9 * *", "", func() error { return nil })
+	if err == nil || !strings.Contains(err.Error(), "weekly wrap") {
+		t.Errorf("expected an error naming the job, got %v", err)
+	}
+}
+
+func TestUnlessPaused(t *testing.T) {
+	store := pause.NewStore(filepath.Join(t.TempDir(), "pause.json"), time.UTC)
+	s := &Scheduler{pauses: store}
+	calls := 0
+	job := s.unlessPaused("Weekly wrap", func() error {
+		calls++
+		return nil
+	})
+
+	now := time.Now()
+	if _, err := store.Pause(now, now.AddDate(0, 0, 21)); err != nil {
+		t.Fatalf("Pause failed: %v", err)
+	}
+	if err := job(); err != nil || calls != 0 {
+		t.Errorf("expected the job to be skipped while paused, got %d calls, %v", calls, err)
+	}
+	if _, err := store.Resume(now); err != nil {
+		t.Fatalf("Resume failed: %v", err)
+	}
+	if err := job(); err != nil || calls != 1 {
+		t.Errorf("expected the job to run once resumed, got %d calls, %v", calls, err)
+	}
+}
+
+func TestCatchUpRange(t *testing.T) {
+	auckland, err := time.LoadLocation("Pacific/Auckland")
+	if err != nil {
+		t.Fatalf("LoadLocation failed: %v", err)
+	}
+	start, end := catchUpRange(pause.State{
+		Since: time.Date(2026, 10, 16, 19, 0, 0, 0, auckland),
+		Until: time.Date(2026, 11, 6, 8, 0, 0, 0, auckland),
+	})
+	if want := time.Date(2026, 10, 16, 0, 0, 0, 0, time.UTC); !start.Equal(want) {
+		t.Errorf("start: got %s, want %s", start, want)
+	}
+	if want := time.Date(2026, 11, 5, 0, 0, 0, 0, time.UTC); !end.Equal(want) {
+		t.Errorf("end: got %s, want %s", end, want)
+	}
+
+	start, end = catchUpRange(pause.State{
+		Since: time.Date(2026, 10, 16, 9, 0, 0, 0, time.UTC),
+		Until: time.Date(2026, 10, 16, 17, 0, 0, 0, time.UTC),
+	})
+	if !end.Before(start) {
+		t.Errorf("expected no days to catch up on after a pause within a day, got %s to %s", start, end)
+	}
+}
+
+func TestRangeExporters(t *testing.T) {
+	store := history.NewStore(t.TempDir())
+	archived := &recordingExporter{}
+	s := &Scheduler{history: store, latest: &latestRun{}}
+	s.exporters = []pipeline.Exporter{store, archived, s.latest}
+
+	// A week across the end of daylight saving in New Zealand is 7 calendar
+	// days but not 6*24 hours
+	auckland, err := time.LoadLocation("Pacific/Auckland")
+	if err != nil {
+		t.Fatalf("LoadLocation failed: %v", err)
+	}
+	start := time.Date(2026, 4, 1, 0, 0, 0, 0, auckland)
+	end := time.Date(2026, 4, 7, 0, 0, 0, 0, auckland)
+	if got := s.rangeExporters(start, end); len(got) != 2 || got[0] != pipeline.Exporter(store) || got[1] != pipeline.Exporter(archived) {
+		t.Errorf("expected history and the archive without a stored snapshot, got %v", got)
+	}
+
+	if err := store.Save(&history.Snapshot{Week: history.WeekOf(endOfDay(end))}); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+	if got := s.rangeExporters(start, end); len(got) != 1 || got[0] != pipeline.Exporter(archived) {
+		t.Errorf("expected the stored snapshot to be kept, got %v", got)
+	}
+
+	if got := s.rangeExporters(start, end.AddDate(0, 0, 1)); got != nil {
+		t.Errorf("expected no exporters for a range that isn't a week, got %v", got)
 	}
 }
@@ -0,0 +1,35 @@
+package scheduler
+
+import (
+	"log"
+	"time"
+)
+
+// maxSendDelay is how late a queued send may still go out, e.g. after the
+// scheduler was down at its time. A wrap meant for a meeting that is long
+// over is dropped instead.
+const maxSendDelay = 12 * time.Hour
+
+// runDueSends sends the wraps queued with schedule-once whose time has come
+func (s *Scheduler) runDueSends() {
+	now := time.Now()
+	due, err := s.sends.TakeDue(now)
+	if err != nil {
+		log.Printf("Warning: could not check scheduled sends: %v", err)
+		return
+	}
+
+	for _, send := range due {
+		if late := now.Sub(send.At); late > maxSendDelay {
+			log.Printf("Warning: dropping the %s wrap scheduled for %s, missed by %s", send.Period, send.At.Format(time.RFC1123), late.Round(time.Minute))
+			continue
+		}
+
+		log.Printf("Sending the %s wrap scheduled for %s", send.Period, send.At.Format(time.RFC1123))
+		if send.Period == "monthly" {
+			s.runJob("Scheduled monthly wrap", s.runMonthlyWrap)()
+		} else {
+			s.runJob("Scheduled weekly wrap", s.runWeeklyWrap)()
+		}
+	}
+}
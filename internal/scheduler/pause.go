@@ -0,0 +1,58 @@
+package scheduler
+
+import (
+	"log"
+	"strings"
+	"time"
+
+	"github.com/sathyabhat/ynab-weekly-wrap/internal/pause"
+)
+
+// unlessPaused skips job while the scheduled sends are paused. A pause that
+// can't be read doesn't stop the job.
+func (s *Scheduler) unlessPaused(name string, job func() error) func() error {
+	if s.pauses == nil {
+		return job
+	}
+	return func() error {
+		state, err := s.pauses.Current()
+		if err != nil {
+			log.Printf("Warning: could not check whether sends are paused: %v", err)
+		}
+		if state.Active(time.Now()) {
+			log.Printf("Skipping the %s, sends are paused until %s", strings.ToLower(name), state.Until.Format(time.RFC1123))
+			return nil
+		}
+		return job()
+	}
+}
+
+// resumeEnded clears a pause once it has ended, whether it ran its course
+// or was resumed early, and sends a wrap catching up on the days missed
+func (s *Scheduler) resumeEnded() {
+	ended, err := s.pauses.TakeEnded(time.Now())
+	if err != nil {
+		log.Printf("Warning: could not check whether the pause has ended: %v", err)
+		return
+	}
+	if ended == nil {
+		return
+	}
+
+	log.Printf("Resuming scheduled sends, paused since %s", ended.Since.Format(time.RFC1123))
+	start, end := catchUpRange(*ended)
+	if end.Before(start) {
+		return
+	}
+	s.runJob("Catch-up wrap", func() error { return s.RunRangeOnce(start, end) })()
+}
+
+// catchUpRange returns the days a pause covered, from the day it began
+// through the day before it ended, at midnight UTC like transaction dates.
+// The end is before the start for a pause of less than a day.
+func catchUpRange(state pause.State) (start, end time.Time) {
+	day := func(t time.Time) time.Time {
+		return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+	}
+	return day(state.Since), day(state.Until).AddDate(0, 0, -1)
+}
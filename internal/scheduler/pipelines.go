@@ -0,0 +1,877 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sathyabhat/ynab-weekly-wrap/internal/history"
+	"github.com/sathyabhat/ynab-weekly-wrap/internal/metrics"
+	"github.com/sathyabhat/ynab-weekly-wrap/internal/money"
+	"github.com/sathyabhat/ynab-weekly-wrap/internal/pipeline"
+	"github.com/sathyabhat/ynab-weekly-wrap/internal/processor"
+	"github.com/sathyabhat/ynab-weekly-wrap/internal/publisher"
+	"github.com/sathyabhat/ynab-weekly-wrap/internal/render"
+	"github.com/sathyabhat/ynab-weekly-wrap/internal/ynab"
+)
+
+func (s *Scheduler) runWeeklyWrap() error {
+	log.Println("Running weekly wrap...")
+
+	// Get current date and calculate week range
+	now := time.Now()
+	run := &pipeline.Run{
+		Name:        "Weekly wrap",
+		PeriodStart: now.AddDate(0, 0, -7),
+		PeriodEnd:   now,
+	}
+
+	return s.newPipeline(
+		pipeline.NewStage(pipeline.StageFetch, s.fetchWeekly),
+		pipeline.NewStage(pipeline.StageEnrich, s.enrichWeekly),
+		pipeline.NewStage(pipeline.StageAnalyze, s.analyzeWeekly),
+		pipeline.NewStage(pipeline.StageRender, s.renderWeekly),
+		pipeline.NewStage(pipeline.StageDeliver, s.deliverRun),
+	).Run(context.Background(), run)
+}
+
+// RunRangeOnce sends the weekly wrap for the days from start through end,
+// both inclusive, e.g. to look back at a past week. Budgets and balances are
+// today's, since YNAB doesn't keep them per day. Only week-long ranges are
+// exported, as history and the archive are kept per week.
+func (s *Scheduler) RunRangeOnce(start, end time.Time) error {
+	run, stages := s.rangeRun(start, end)
+	log.Printf("Running %s...", strings.ToLower(run.Name))
+
+	stages = append(stages, pipeline.NewStage(pipeline.StageDeliver, s.deliverRun))
+	return s.newPipelineExporting(s.rangeExporters(start, end), stages...).Run(context.Background(), run)
+}
+
+// rangeExporters returns the exporters for a wrap of the days from start
+// through end: none unless they make up a week. A range is usually in the
+// past, and its budgets and balances are today's, so it never becomes the
+// latest wrap and only fills in a snapshot history is missing, rather than
+// replacing the one taken at the time.
+func (s *Scheduler) rangeExporters(start, end time.Time) []pipeline.Exporter {
+	// Calendar days, so a week across a daylight saving change still counts
+	if y, m, d := start.AddDate(0, 0, 6).Date(); !(end.Year() == y && end.Month() == m && end.Day() == d) {
+		return nil
+	}
+
+	var exporters []pipeline.Exporter
+	for _, exporter := range s.exporters {
+		switch {
+		case s.latest != nil && exporter == pipeline.Exporter(s.latest):
+			continue
+		case s.history != nil && exporter == pipeline.Exporter(s.history):
+			week := history.WeekOf(endOfDay(end))
+			if _, err := s.history.LoadWeek(week); !errors.Is(err, history.ErrNotFound) {
+				log.Printf("Keeping the stored snapshot for %s", week)
+				continue
+			}
+		}
+		exporters = append(exporters, exporter)
+	}
+	return exporters
+}
+
+// RenderRange fetches and renders the weekly wrap for the days from start
+// through end without sending or storing it
+func (s *Scheduler) RenderRange(start, end time.Time) (*pipeline.Run, error) {
+	run, stages := s.rangeRun(start, end)
+	err := pipeline.New(stages...).Run(context.Background(), run)
+	return run, err
+}
+
+// rangeRun sets up a weekly wrap for the days from start through end, both
+// inclusive, with its stages up to rendering
+func (s *Scheduler) rangeRun(start, end time.Time) (*pipeline.Run, []pipeline.Stage) {
+	dateRange := start.Format("2006-01-02") + " to " + end.Format("2006-01-02")
+
+	// Like a scheduled run, the period covers the days after its start
+	// through its end
+	run := &pipeline.Run{
+		Name:        "Wrap " + dateRange,
+		PeriodStart: endOfDay(start.AddDate(0, 0, -1)),
+		PeriodEnd:   endOfDay(end),
+	}
+	analyze := func(ctx context.Context, run *pipeline.Run) error {
+		if err := s.analyzeWeekly(ctx, run); err != nil {
+			return err
+		}
+		run.Analysis.DateRange = dateRange
+		return nil
+	}
+
+	return run, []pipeline.Stage{
+		pipeline.NewStage(pipeline.StageFetch, s.fetchWeekly),
+		pipeline.NewStage(pipeline.StageEnrich, s.enrichWeekly),
+		pipeline.NewStage(pipeline.StageAnalyze, analyze),
+		pipeline.NewStage(pipeline.StageRender, s.renderWeekly),
+	}
+}
+
+// endOfDay returns the last second of day
+func endOfDay(day time.Time) time.Time {
+	return time.Date(day.Year(), day.Month(), day.Day(), 23, 59, 59, 0, day.Location())
+}
+
+func (s *Scheduler) runMonthlyWrap() error {
+	log.Println("Running monthly wrap...")
+
+	prev := time.Now().AddDate(0, -1, 0)
+	monthStart := time.Date(prev.Year(), prev.Month(), 1, 0, 0, 0, 0, time.UTC)
+	run := &pipeline.Run{
+		Name:        "Monthly wrap",
+		PeriodStart: monthStart,
+		PeriodEnd:   monthStart.AddDate(0, 1, -1),
+	}
+
+	return s.newPipeline(
+		pipeline.NewStage(pipeline.StageFetch, s.fetchMonthly),
+		pipeline.NewStage(pipeline.StageEnrich, s.enrichMonthly),
+		pipeline.NewStage(pipeline.StageAnalyze, s.analyzeMonthly),
+		pipeline.NewStage(pipeline.StageRender, s.renderMonthly),
+		pipeline.NewStage(pipeline.StageDeliver, s.deliverRun),
+	).Run(context.Background(), run)
+}
+
+func (s *Scheduler) runDailyDigest() error {
+	log.Println("Running daily digest...")
+
+	// The digest covers yesterday, in the schedule's timezone
+	yesterday := time.Now().In(s.config.Schedule.Location()).AddDate(0, 0, -1)
+	day := time.Date(yesterday.Year(), yesterday.Month(), yesterday.Day(), 0, 0, 0, 0, time.UTC)
+	run := &pipeline.Run{
+		Name:        "Daily digest",
+		PeriodStart: day.AddDate(0, 0, -((int(day.Weekday()) + 6) % 7)), // Monday
+		PeriodEnd:   day,
+	}
+
+	return s.newPipeline(
+		pipeline.NewStage(pipeline.StageFetch, s.fetchDaily),
+		pipeline.NewStage(pipeline.StageAnalyze, s.analyzeDaily),
+		pipeline.NewStage(pipeline.StageRender, s.renderDaily),
+		pipeline.NewStage(pipeline.StageDeliver, s.deliverRun),
+	).Run(context.Background(), run)
+}
+
+// runMeetingAgenda sends the agenda for the next budget meeting
+func (s *Scheduler) runMeetingAgenda() error {
+	log.Println("Running meeting agenda...")
+
+	now := time.Now().In(s.config.Schedule.Location())
+	run := &pipeline.Run{
+		Name:        "Meeting agenda",
+		PeriodStart: now.AddDate(0, 0, -agendaLookbackDays),
+		PeriodEnd:   now,
+	}
+
+	return s.newPipeline(
+		pipeline.NewStage(pipeline.StageFetch, s.fetchMeeting),
+		pipeline.NewStage(pipeline.StageAnalyze, s.analyzeMeeting),
+		pipeline.NewStage(pipeline.StageRender, s.renderMeeting),
+		pipeline.NewStage(pipeline.StageDeliver, s.deliverRun),
+	).Run(context.Background(), run)
+}
+
+// RunSummaryOnce sends the summary of a quarter or year
+func (s *Scheduler) RunSummaryOnce(period processor.SummaryPeriod) error {
+	log.Printf("Running %s summary for %s...", period.Kind, period.Label)
+
+	run := &pipeline.Run{
+		Name:        strings.ToUpper(period.Kind[:1]) + period.Kind[1:] + " summary",
+		PeriodStart: period.Start,
+		PeriodEnd:   period.End,
+	}
+	analyze := func(ctx context.Context, run *pipeline.Run) error {
+		return s.analyzeSummary(run, period)
+	}
+
+	return s.newPipeline(
+		pipeline.NewStage(pipeline.StageFetch, s.fetchSummary),
+		pipeline.NewStage(pipeline.StageAnalyze, analyze),
+		pipeline.NewStage(pipeline.StageRender, s.renderSummary),
+		pipeline.NewStage(pipeline.StageDeliver, s.deliverRun),
+	).Run(context.Background(), run)
+}
+
+// RerenderWeek analyzes and renders a stored weekly snapshot with the current
+// analyzer and formatter. Nothing is fetched from YNAB and nothing is sent.
+func (s *Scheduler) RerenderWeek(snapshot *history.Snapshot) (*pipeline.Run, error) {
+	if snapshot.Weekly == nil {
+		return nil, fmt.Errorf("snapshot %s has no weekly data", snapshot.Week)
+	}
+
+	run := &pipeline.Run{
+		Name:              "Weekly wrap " + snapshot.Week,
+		PeriodStart:       snapshot.PeriodStart,
+		PeriodEnd:         snapshot.PeriodEnd,
+		Weekly:            snapshot.Weekly,
+		PrevCategorySpend: snapshot.PrevCategorySpend,
+	}
+
+	err := pipeline.New(
+		pipeline.NewStage(pipeline.StageAnalyze, s.analyzeWeekly),
+		pipeline.NewStage(pipeline.StageRender, s.renderWeekly),
+	).Run(context.Background(), run)
+	return run, err
+}
+
+// newPipeline appends the export stage to the given stages and registers the
+// scheduler's hooks plus stage timing logs
+func (s *Scheduler) newPipeline(stages ...pipeline.Stage) *pipeline.Pipeline {
+	return s.newPipelineExporting(s.exporters, stages...)
+}
+
+// newPipelineExporting is newPipeline with the given exporters in place of
+// the scheduler's
+func (s *Scheduler) newPipelineExporting(exporters []pipeline.Exporter, stages ...pipeline.Stage) *pipeline.Pipeline {
+	p := pipeline.New(append(stages, pipeline.ExportStage(exporters...))...)
+	for _, hook := range s.hooks {
+		p.Use(hook)
+	}
+	return p.Use(pipeline.LogTimings(p)).Use(s.recordTimings(p))
+}
+
+// recordTimings is a hook that records the stage timings for the status
+// endpoint once the last stage has run or a stage fails
+func (s *Scheduler) recordTimings(p *pipeline.Pipeline) pipeline.Hook {
+	stages := p.Stages()
+	return pipeline.Hook{
+		AfterStage: func(stage string, run *pipeline.Run, timing pipeline.StageTiming) {
+			if timing.Err == nil && stage != stages[len(stages)-1] {
+				return
+			}
+			recorded := make([]metrics.Stage, len(run.Timings))
+			for i, t := range run.Timings {
+				recorded[i] = metrics.Stage{Name: t.Stage, Duration: t.Duration}
+				if t.Err != nil {
+					recorded[i].Error = t.Err.Error()
+				}
+			}
+			s.metrics.ObserveRun(run.Name, recorded, timing.Err)
+		},
+	}
+}
+
+// Status reports the last run of each wrap and recent API health
+func (s *Scheduler) Status() metrics.Status {
+	return s.metrics.Status()
+}
+
+// Latest returns the most recent weekly or monthly wrap and when it ran, or
+// nil before any has
+func (s *Scheduler) Latest() (*pipeline.Run, time.Time) {
+	if s.latest == nil {
+		return nil, time.Time{}
+	}
+	return s.latest.get()
+}
+
+// latestRun implements pipeline.Exporter, keeping the last weekly or monthly
+// run in memory. A run for an earlier period, such as a past week sent with
+// -week-of, doesn't replace a later one.
+type latestRun struct {
+	mu  sync.Mutex
+	run *pipeline.Run
+	at  time.Time
+}
+
+func (l *latestRun) Export(run *pipeline.Run) error {
+	if (run.Weekly == nil && run.Monthly == nil) || run.Analysis == nil {
+		return nil
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.run != nil && run.PeriodEnd.Before(l.run.PeriodEnd) {
+		return nil
+	}
+	l.run, l.at = run, time.Now()
+	return nil
+}
+
+func (l *latestRun) get() (*pipeline.Run, time.Time) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.run, l.at
+}
+
+// fetchWeekly fetches the week's budget data and the spending of the week
+// before it to compare with
+func (s *Scheduler) fetchWeekly(ctx context.Context, run *pipeline.Run) error {
+	weekStart, weekEnd := run.PeriodStart, run.PeriodEnd
+	log.Printf("Processing week from %s to %s", weekStart.Format("2006-01-02"), weekEnd.Format("2006-01-02"))
+
+	data, err := s.ynabClient.GetWeeklyData(weekStart, weekEnd)
+	if err != nil {
+		return fmt.Errorf("failed to get weekly data: %w", err)
+	}
+	data.Validate(time.Now())
+	run.Weekly = data
+
+	// The previous period is as long as this one and ends where it starts
+	prevWeekSpend, err := s.ynabClient.GetCategorySpend(weekStart.Add(-weekEnd.Sub(weekStart)), weekStart, s.config.Accounts.IncludeTransfers, s.config.Flags.Exclude)
+	if err != nil {
+		log.Printf("Warning: could not fetch previous week data for comparison: %v", err)
+		prevWeekSpend = nil
+	}
+	run.PrevCategorySpend = prevWeekSpend
+	return nil
+}
+
+// enrichWeekly fetches what the weekly analysis needs beyond the week's own
+// transactions: accounts, upcoming bills and past spending to compare with
+func (s *Scheduler) enrichWeekly(ctx context.Context, run *pipeline.Run) error {
+	// Budget months that don't follow the calendar need their own month-to-date
+	// spending, since YNAB's category activity is per calendar month. The
+	// savings rate needs the month's income, which activity doesn't give.
+	if s.config.Fiscal.MonthStartDay > 1 || s.config.Savings.Rate {
+		monthStart, _ := processor.FiscalMonth(run.PeriodEnd, s.config.Fiscal.MonthStartDay)
+		monthToDate, err := s.ynabClient.GetTransactions(monthStart, run.PeriodEnd)
+		if err != nil {
+			log.Printf("Warning: could not fetch month-to-date transactions: %v", err)
+		}
+		run.Weekly.MonthToDate = monthToDate
+	}
+
+	// Upcoming bills come out of what is safe to spend this week
+	scheduled, err := s.ynabClient.GetScheduledTransactions()
+	if err != nil {
+		log.Printf("Warning: could not fetch scheduled transactions: %v", err)
+	}
+	run.Weekly.Scheduled = scheduled
+
+	// Accounts tell how up to date the week's data is
+	accounts, err := s.ynabClient.GetAccounts()
+	if err != nil {
+		log.Printf("Warning: could not fetch accounts: %v", err)
+	}
+	run.Weekly.Accounts = accounts
+
+	// Age of Money goes in the overview, and is stored for next week's change
+	ageOfMoney, err := s.ynabClient.GetAgeOfMoney(run.PeriodEnd.Year(), int(run.PeriodEnd.Month()))
+	if err != nil {
+		log.Printf("Warning: could not fetch age of money: %v", err)
+	}
+	run.Weekly.AgeOfMoney = ageOfMoney
+
+	// The weeks before give each category's usual spending
+	period := run.PeriodEnd.Sub(run.PeriodStart)
+	baseline, err := s.ynabClient.GetCategorySpend(run.PeriodStart.Add(-processor.BaselineWeeks*period), run.PeriodStart, s.config.Accounts.IncludeTransfers, s.config.Flags.Exclude)
+	if err != nil {
+		log.Printf("Warning: could not fetch spending for the %d-week average: %v", processor.BaselineWeeks, err)
+	}
+	run.Weekly.Baseline = baseline
+
+	// A year of utility bills tells what each one usually comes to
+	if bills := s.config.Utilities.Bills; len(bills) > 0 {
+		past, err := s.ynabClient.GetTransactions(run.PeriodStart.AddDate(-1, 0, -7), run.PeriodStart.AddDate(0, 0, -1))
+		if err != nil {
+			log.Printf("Warning: could not fetch past utility bills: %v", err)
+		}
+		run.Weekly.PastBills = processor.UtilityBills(past, bills)
+	}
+
+	// Months of grocery trips show whether the average trip is creeping up
+	if groceries := s.config.Groceries; groceries.Category != "" {
+		trips, err := s.ynabClient.GetTransactions(processor.GroceryWindow(run.PeriodEnd, groceries.Months), run.PeriodEnd)
+		if err != nil {
+			log.Printf("Warning: could not fetch past grocery trips: %v", err)
+		}
+		run.Weekly.Groceries = processor.GroceryTrips(trips, groceries.Category)
+	}
+
+	// Transactions still to be approved become action items, and tell which
+	// of last week's were approved
+	if s.config.Actions.Enabled {
+		recent, err := s.ynabClient.GetTransactions(processor.ApprovalWindow(run.PeriodStart), run.PeriodEnd)
+		if err != nil {
+			log.Printf("Warning: could not fetch transactions to approve: %v", err)
+		}
+		run.Weekly.Unapproved = processor.UnapprovedTransactions(recent)
+	}
+	return nil
+}
+
+// analyzeWeekly analyzes the week, with the extra sections the
+// configuration turns on
+func (s *Scheduler) analyzeWeekly(ctx context.Context, run *pipeline.Run) error {
+	topCategoriesLimit := s.config.Thresholds.TopCategoriesCount
+	analysis, err := s.analyzer.AnalyzeWeeklyData(run.Weekly, run.PrevCategorySpend, topCategoriesLimit)
+	if err != nil {
+		return fmt.Errorf("failed to analyze weekly data: %w", err)
+	}
+	// Transactions flagged to be left out stay out of every section below too
+	transactions := s.analyzer.WithoutExcluded(run.Weekly.Transactions)
+	s.attachNotes(analysis)
+	s.attachNewPayees(run, analysis)
+	s.attachUnusual(run, analysis)
+	s.attachAgeOfMoney(run, analysis)
+	if s.config.Accounts.Balances {
+		analysis.Accounts = processor.AccountBalances(run.Weekly.Accounts)
+	}
+	if s.config.Accounts.NetWorth {
+		s.attachNetWorth(run, analysis)
+	}
+	if s.config.Accounts.Cards {
+		analysis.Cards = processor.CardPayments(run.Weekly.Accounts, run.Weekly.Categories, run.Weekly.Transactions)
+	}
+	if accounts := s.config.Accounts; accounts.Pending {
+		analysis.Attention = processor.AddPending(analysis.Attention, transactions, accounts.PendingList)
+	}
+	if !s.config.Categories.WeekAhead {
+		analysis.AheadFocus = nil
+	}
+	if savings := s.config.Savings; savings.Rate {
+		analysis.Savings = processor.CalculateSavings(transactions, s.analyzer.WithoutExcluded(run.Weekly.MonthToDate), savings.Target)
+	}
+	if s.config.Actions.Enabled {
+		s.attachActions(run, analysis)
+	}
+	if goals := s.config.Goals; goals.Progress {
+		analysis.Goals = processor.TrackGoals(run.Weekly.Categories, goals.Categories)
+	}
+	if groups := s.config.Accounts.Groups; len(groups) > 0 {
+		analysis.Groups = processor.GroupSpending(analysis.Spending, groups)
+	}
+	if bills := s.config.Utilities.Bills; len(bills) > 0 {
+		analysis.Utilities = processor.FindUtilitySpikes(run.Weekly.Transactions, run.Weekly.PastBills, bills, s.config.Utilities.SpikePercent)
+	}
+	if groceries := s.config.Groceries; groceries.Category != "" {
+		analysis.Groceries = processor.TrackGroceries(run.Weekly.Groceries, groceries.Category, run.PeriodStart, run.PeriodEnd, groceries.Months)
+	}
+	run.Analysis = analysis
+	return nil
+}
+
+// renderWeekly renders the weekly wrap for each destination
+func (s *Scheduler) renderWeekly(ctx context.Context, run *pipeline.Run) error {
+	report := func(analysis *processor.AnalysisResult) publisher.Report {
+		message := s.formatWithTemplate(s.weeklyTemplate, analysis, s.formatMessage)
+		return publisher.Report{
+			Title:        s.messages.T("weekly.title", analysis.DateRange),
+			Message:      message,
+			Analysis:     analysis,
+			Document:     render.Parse(message),
+			Transactions: run.Weekly.Transactions,
+		}
+	}
+	run.Report = report(run.Analysis)
+	s.addCurrencyVariants(&run.Report, func(currency money.Format) publisher.Report {
+		analysis := *run.Analysis
+		analysis.Currency = currency
+		return report(&analysis)
+	})
+	return nil
+}
+
+// fetchMonthly fetches the month's budget data and the spending of the month
+// before it to compare with
+func (s *Scheduler) fetchMonthly(ctx context.Context, run *pipeline.Run) error {
+	month := run.PeriodStart
+	log.Printf("Processing month: %s", month.Format("January 2006"))
+
+	data, err := s.ynabClient.GetMonthlyData(month.Year(), int(month.Month()))
+	if err != nil {
+		return fmt.Errorf("failed to get monthly data: %w", err)
+	}
+	data.Validate()
+	run.Monthly = data
+
+	prevMonth := month.AddDate(0, -1, 0)
+	prevCategorySpend, err := s.ynabClient.GetPrevMonthCategorySpend(prevMonth.Year(), int(prevMonth.Month()))
+	if err != nil {
+		log.Printf("Warning: could not fetch previous month data for comparison: %v", err)
+		prevCategorySpend = nil
+	}
+	run.PrevCategorySpend = prevCategorySpend
+	return nil
+}
+
+// hygieneMonths is how many months, including the wrap's, the category
+// tidy-up check looks at
+const hygieneMonths = 6
+
+// enrichMonthly adds the months the category tidy-up check looks at
+func (s *Scheduler) enrichMonthly(ctx context.Context, run *pipeline.Run) error {
+	if !s.config.Categories.Hygiene {
+		return nil
+	}
+
+	// The tidy-up check needs the categories' groups and several months of
+	// figures. Without them the wrap simply goes out without the section.
+	catalog, err := s.ynabClient.GetCategories()
+	if err != nil {
+		log.Printf("Warning: could not fetch categories for the tidy-up check: %v", err)
+		return nil
+	}
+	var past [][]ynab.Category
+	for i := 1; i < hygieneMonths; i++ {
+		month := run.PeriodStart.AddDate(0, -i, 0)
+		categories, err := s.ynabClient.GetMonthCategories(month.Year(), int(month.Month()))
+		if err != nil {
+			log.Printf("Warning: could not fetch %s for the tidy-up check: %v", month.Format("January 2006"), err)
+			break
+		}
+		past = append(past, categories)
+	}
+	run.Monthly.Catalog = catalog
+	run.Monthly.PastMonths = past
+	return nil
+}
+
+// analyzeMonthly analyzes the month, with the extra sections the
+// configuration turns on
+func (s *Scheduler) analyzeMonthly(ctx context.Context, run *pipeline.Run) error {
+	topCategoriesLimit := s.config.Thresholds.TopCategoriesCount
+	analysis, err := s.analyzer.AnalyzeMonthlyData(run.Monthly, run.PrevCategorySpend, topCategoriesLimit)
+	if err != nil {
+		return fmt.Errorf("failed to analyze monthly data: %w", err)
+	}
+	if run.Monthly.Catalog != nil {
+		months := append([][]ynab.Category{run.Monthly.Categories}, run.Monthly.PastMonths...)
+		analysis.Hygiene = s.analyzer.AssessCategoryHygiene(run.Monthly.Catalog, months)
+	}
+	if goals := s.config.Goals; goals.Progress {
+		analysis.Goals = processor.TrackGoals(run.Monthly.Categories, goals.Categories)
+	}
+	s.attachPatterns(run, analysis)
+	s.attachNotes(analysis)
+	run.Analysis = analysis
+	return nil
+}
+
+// renderMonthly renders the monthly wrap for each destination
+func (s *Scheduler) renderMonthly(ctx context.Context, run *pipeline.Run) error {
+	report := func(analysis *processor.AnalysisResult) publisher.Report {
+		message := s.formatWithTemplate(s.monthlyTemplate, analysis, s.formatMonthlyMessage)
+		return publisher.Report{
+			Title:        s.messages.T("monthly.title", analysis.DateRange),
+			Message:      message,
+			Analysis:     analysis,
+			Document:     render.Parse(message),
+			Transactions: run.Monthly.Transactions,
+		}
+	}
+	run.Report = report(run.Analysis)
+	s.addCurrencyVariants(&run.Report, func(currency money.Format) publisher.Report {
+		analysis := *run.Analysis
+		analysis.Currency = currency
+		return report(&analysis)
+	})
+	return nil
+}
+
+// fetchDaily fetches the day's transactions
+func (s *Scheduler) fetchDaily(ctx context.Context, run *pipeline.Run) error {
+	data, err := s.ynabClient.GetDailyData(run.PeriodStart, run.PeriodEnd)
+	if err != nil {
+		return fmt.Errorf("failed to get daily data: %w", err)
+	}
+	run.Daily = data
+	return nil
+}
+
+// analyzeDaily builds the daily digest
+func (s *Scheduler) analyzeDaily(ctx context.Context, run *pipeline.Run) error {
+	digest, err := s.analyzer.AnalyzeDailyData(run.Daily)
+	if err != nil {
+		return fmt.Errorf("failed to analyze daily data: %w", err)
+	}
+	run.Digest = digest
+	return nil
+}
+
+// renderDaily renders the daily digest for each destination
+func (s *Scheduler) renderDaily(ctx context.Context, run *pipeline.Run) error {
+	report := func(digest *processor.DailyDigest) publisher.Report {
+		message := s.formatDailyDigest(digest)
+		return publisher.Report{
+			Title:    s.messages.T("daily.title", digest.Day.Format("2006-01-02")),
+			Message:  message,
+			Document: render.Parse(message),
+		}
+	}
+	run.Report = report(run.Digest)
+	s.addCurrencyVariants(&run.Report, func(currency money.Format) publisher.Report {
+		digest := *run.Digest
+		digest.Currency = currency
+		return report(&digest)
+	})
+	return nil
+}
+
+// agendaLookbackDays is how far back the agenda looks for transactions still
+// to be approved or categorized
+const agendaLookbackDays = 30
+
+// fetchMeeting fetches what the budget meeting agenda is built from
+func (s *Scheduler) fetchMeeting(ctx context.Context, run *pipeline.Run) error {
+	data, err := s.ynabClient.GetMeetingData(run.PeriodStart, run.PeriodEnd)
+	if err != nil {
+		return fmt.Errorf("failed to get meeting data: %w", err)
+	}
+	run.Meeting = data
+	return nil
+}
+
+// analyzeMeeting builds the agenda for the next budget meeting
+func (s *Scheduler) analyzeMeeting(ctx context.Context, run *pipeline.Run) error {
+	meeting := s.config.Meeting.Next(run.PeriodEnd)
+	if meeting.IsZero() {
+		meeting = run.PeriodEnd // Run by hand without a meeting set
+	}
+	agenda, err := s.analyzer.BuildAgenda(run.Meeting, meeting, s.config.Meeting.LargeBill)
+	if err != nil {
+		return fmt.Errorf("failed to build meeting agenda: %w", err)
+	}
+	run.Agenda = agenda
+	return nil
+}
+
+// renderMeeting renders the meeting agenda for each destination
+func (s *Scheduler) renderMeeting(ctx context.Context, run *pipeline.Run) error {
+	report := func(agenda *processor.Agenda) publisher.Report {
+		message := s.formatAgenda(agenda)
+		return publisher.Report{
+			Title:    s.messages.T("meeting.title", agenda.Meeting.Format("Mon 2 Jan 15:04")),
+			Message:  message,
+			Document: render.Parse(message),
+		}
+	}
+	run.Report = report(run.Agenda)
+	s.addCurrencyVariants(&run.Report, func(currency money.Format) publisher.Report {
+		agenda := *run.Agenda
+		agenda.Currency = currency
+		return report(&agenda)
+	})
+	return nil
+}
+
+// summaryLimit is how many categories and payees a summary lists when the
+// top categories count is unlimited
+const summaryLimit = 10
+
+// fetchSummary fetches the transactions of the summary's period
+func (s *Scheduler) fetchSummary(ctx context.Context, run *pipeline.Run) error {
+	data, err := s.ynabClient.GetPeriodData(run.PeriodStart, run.PeriodEnd)
+	if err != nil {
+		return fmt.Errorf("failed to get summary data: %w", err)
+	}
+	run.Summary = data
+	return nil
+}
+
+// analyzeSummary totals the period's spending by category and payee
+func (s *Scheduler) analyzeSummary(run *pipeline.Run, period processor.SummaryPeriod) error {
+	limit := s.config.Thresholds.TopCategoriesCount
+	if limit == 0 {
+		limit = summaryLimit
+	}
+	totals, err := s.analyzer.SummarizePeriod(run.Summary, period, limit)
+	if err != nil {
+		return fmt.Errorf("failed to summarize %s: %w", period.Label, err)
+	}
+	run.Totals = totals
+	return nil
+}
+
+// renderSummary renders the period summary for each destination
+func (s *Scheduler) renderSummary(ctx context.Context, run *pipeline.Run) error {
+	report := func(totals *processor.PeriodSummary) publisher.Report {
+		message := s.formatSummary(totals)
+		return publisher.Report{
+			Title:    s.messages.T(totals.Period.Kind+".title", totals.Label),
+			Message:  message,
+			Document: render.Parse(message),
+		}
+	}
+	run.Report = report(run.Totals)
+	s.addCurrencyVariants(&run.Report, func(currency money.Format) publisher.Report {
+		totals := *run.Totals
+		totals.Currency = currency
+		return report(&totals)
+	})
+	return nil
+}
+
+// attachNotes adds the current category notes to the analysis. Notes are
+// context, not figures, so a notes file that can't be read doesn't stop
+// the wrap.
+func (s *Scheduler) attachNotes(analysis *processor.AnalysisResult) {
+	if s.notes == nil {
+		return
+	}
+	categoryNotes, err := s.notes.All()
+	if err != nil {
+		log.Printf("Warning: could not load category notes: %v", err)
+		return
+	}
+	if len(categoryNotes) > 0 {
+		analysis.Notes = categoryNotes
+	}
+}
+
+// minPayeeHistory is how far back stored snapshots must reach before new
+// payees are reported, so the first weeks of history don't flag every payee
+const minPayeeHistory = 28 * 24 * time.Hour
+
+// attachNewPayees lists the week's payees that don't appear in the stored
+// snapshots of the lookback window. Like notes, this is extra context, so
+// history that can't be read doesn't stop the wrap.
+func (s *Scheduler) attachNewPayees(run *pipeline.Run, analysis *processor.AnalysisResult) {
+	if s.history == nil || !s.config.History.NewPayees {
+		return
+	}
+
+	since := run.PeriodStart.AddDate(0, -s.config.History.NewPayeeMonths, 0)
+	index, err := s.history.Payees(since, run.PeriodStart)
+	if err != nil {
+		log.Printf("Warning: could not index past payees: %v", err)
+		return
+	}
+	if index.Earliest.IsZero() || run.PeriodStart.Sub(index.Earliest) < minPayeeHistory {
+		log.Println("Not enough stored history to spot new payees yet")
+		return
+	}
+	analysis.NewPayees = processor.NewPayees(run.Weekly.Transactions, index.Payees)
+}
+
+// attachUnusual lists the week's outflows that are far larger than their
+// category's outflows in the stored snapshots. As with new payees, nothing is
+// flagged until the snapshots reach back far enough to say what is usual.
+func (s *Scheduler) attachUnusual(run *pipeline.Run, analysis *processor.AnalysisResult) {
+	if s.history == nil || !s.config.History.Anomalies {
+		return
+	}
+
+	since := run.PeriodStart.AddDate(0, -s.config.History.AnomalyMonths, 0)
+	index, err := s.history.Outflows(since, run.PeriodStart)
+	if err != nil {
+		log.Printf("Warning: could not index past outflows: %v", err)
+		return
+	}
+	if index.Earliest.IsZero() || run.PeriodStart.Sub(index.Earliest) < minPayeeHistory {
+		log.Println("Not enough stored history to spot unusual transactions yet")
+		return
+	}
+	analysis.Unusual = processor.FindUnusual(run.Weekly.Transactions, index.Outflows, s.config.History.AnomalyStdDevs)
+}
+
+// attachPatterns looks for the days of the week the spending in the stored
+// snapshots clusters on. As with new payees, nothing is reported until the
+// snapshots reach back far enough.
+func (s *Scheduler) attachPatterns(run *pipeline.Run, analysis *processor.AnalysisResult) {
+	if s.history == nil || !s.config.History.Patterns {
+		return
+	}
+
+	until := run.PeriodEnd.AddDate(0, 0, 1)
+	since := until.AddDate(0, -s.config.History.PatternMonths, 0)
+	index, err := s.history.Weekdays(since, until)
+	if err != nil {
+		log.Printf("Warning: could not index past spending by weekday: %v", err)
+		return
+	}
+	if index.Earliest.IsZero() || until.Sub(index.Earliest) < minPayeeHistory {
+		log.Println("Not enough stored history to spot spending patterns yet")
+		return
+	}
+	analysis.Patterns = processor.FindSpendingPattern(index.Outflows, index.Overspent, s.config.History.PatternMonths)
+}
+
+// attachNetWorth totals the accounts and, when history is kept, looks up the
+// total in the snapshots of the week before and of the last week before the
+// one the budget month began in
+func (s *Scheduler) attachNetWorth(run *pipeline.Run, analysis *processor.AnalysisResult) {
+	if len(run.Weekly.Accounts) == 0 {
+		return
+	}
+	netWorth := &processor.NetWorth{Total: processor.TotalBalance(run.Weekly.Accounts)}
+	analysis.NetWorth = netWorth
+	if s.history == nil {
+		return
+	}
+
+	storedTotal := func(before string) *int64 {
+		snapshot, err := s.history.LatestBefore(before)
+		if err != nil {
+			if !errors.Is(err, history.ErrNotFound) {
+				log.Printf("Warning: could not read past net worth: %v", err)
+			}
+			return nil
+		}
+		if snapshot.Weekly == nil || len(snapshot.Weekly.Accounts) == 0 {
+			return nil
+		}
+		total := processor.TotalBalance(snapshot.Weekly.Accounts)
+		return &total
+	}
+	monthStart, _ := processor.FiscalMonth(run.PeriodEnd, s.config.Fiscal.MonthStartDay)
+	netWorth.LastWeek = storedTotal(history.WeekOf(run.PeriodEnd))
+	netWorth.MonthStart = storedTotal(history.WeekOf(monthStart))
+}
+
+// attachAgeOfMoney adds YNAB's Age of Money to the overview and, when history
+// is kept, what it was in the snapshot of the week before
+func (s *Scheduler) attachAgeOfMoney(run *pipeline.Run, analysis *processor.AnalysisResult) {
+	if run.Weekly.AgeOfMoney == nil {
+		return
+	}
+	ageOfMoney := &processor.AgeOfMoney{Days: *run.Weekly.AgeOfMoney}
+	analysis.Overview.AgeOfMoney = ageOfMoney
+	if s.history == nil {
+		return
+	}
+	snapshot, err := s.history.LatestBefore(history.WeekOf(run.PeriodEnd))
+	if err != nil {
+		if !errors.Is(err, history.ErrNotFound) {
+			log.Printf("Warning: could not read last week's age of money: %v", err)
+		}
+		return
+	}
+	if snapshot.Weekly != nil {
+		ageOfMoney.LastWeek = snapshot.Weekly.AgeOfMoney
+	}
+}
+
+// attachActions suggests this week's action items and, when history is kept,
+// checks last week's stored items against the budget as it is now
+func (s *Scheduler) attachActions(run *pipeline.Run, analysis *processor.AnalysisResult) {
+	analysis.Actions = s.analyzer.SuggestActions(run.Weekly.Categories, run.Weekly.Unapproved, run.PeriodStart)
+	if s.history == nil {
+		return
+	}
+	snapshot, err := s.history.LatestBefore(history.WeekOf(run.PeriodEnd))
+	if err != nil {
+		if !errors.Is(err, history.ErrNotFound) {
+			log.Printf("Warning: could not read last week's action items: %v", err)
+		}
+		return
+	}
+	if len(snapshot.Actions) > 0 {
+		analysis.FollowUp = processor.ReviewActions(snapshot.Actions, analysis.Actions, run.Weekly.Categories, run.Weekly.Unapproved)
+	}
+}
+
+// addCurrencyVariants renders the report again, with build, for each
+// destination that shows amounts in its own currency
+func (s *Scheduler) addCurrencyVariants(report *publisher.Report, build func(money.Format) publisher.Report) {
+	for destination, display := range s.config.Delivery.Currencies {
+		if report.Variants == nil {
+			report.Variants = make(map[string]publisher.Report)
+		}
+		report.Variants[destination] = build(money.ForCode(display.Code, display.Rate))
+	}
+}
+
+func (s *Scheduler) deliverRun(ctx context.Context, run *pipeline.Run) error {
+	result, err := s.deliverWithResult(run.Name, run.Report)
+	run.Delivery = result
+	return err
+}
@@ -0,0 +1,72 @@
+package scheduler
+
+import (
+	"bytes"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"text/template"
+
+	"github.com/sathyabhat/ynab-weekly-wrap/internal/money"
+	"github.com/sathyabhat/ynab-weekly-wrap/internal/processor"
+	"github.com/sathyabhat/ynab-weekly-wrap/internal/theme"
+)
+
+// loadTemplate parses a user-supplied message layout. The template is
+// executed with the *processor.AnalysisResult and writes the same **bold**
+// markup as the built-in formatters. An empty path returns nil, meaning the
+// built-in layout is used.
+func (s *Scheduler) loadTemplate(path string) (*template.Template, error) {
+	if path == "" {
+		return nil, nil
+	}
+	text, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read template: %w", err)
+	}
+
+	tmpl, err := template.New(filepath.Base(path)).Funcs(templateFuncs(money.USD, theme.Theme{})).Parse(string(text))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse template: %w", err)
+	}
+	return tmpl, nil
+}
+
+// templateFuncs are the helpers templates can use, formatting amounts in
+// the budget's currency and emoji in the configured theme
+func templateFuncs(currency money.Format, th theme.Theme) template.FuncMap {
+	return template.FuncMap{
+		// currency formats a milliunit amount, e.g. 12500 as $12.50
+		"currency": currency.Amount,
+		// percent formats a percentage without decimals, e.g. 64.4 as 64%
+		"percent": func(value float64) string {
+			return fmt.Sprintf("%.0f%%", value)
+		},
+		// emoji looks up one of the theme's emoji by name, for templates
+		// written in editors that handle emoji poorly
+		"emoji": func(name string) (string, error) {
+			if e, ok := th.Emoji(name); ok {
+				return e, nil
+			}
+			return "", fmt.Errorf("unknown emoji %q", name)
+		},
+	}
+}
+
+// formatWithTemplate renders the analysis with the user's template, falling
+// back to the built-in layout when there is no template or it fails, so a
+// broken template never stops the wrap
+func (s *Scheduler) formatWithTemplate(tmpl *template.Template, analysis *processor.AnalysisResult, builtin func(*processor.AnalysisResult) string) string {
+	if tmpl == nil {
+		return builtin(analysis)
+	}
+
+	var buf bytes.Buffer
+	tmpl = template.Must(tmpl.Clone()).Funcs(templateFuncs(analysis.Currency, s.theme))
+	if err := tmpl.Execute(&buf, analysis); err != nil {
+		log.Printf("Warning: template %s failed, using the built-in layout: %v", tmpl.Name(), err)
+		return builtin(analysis)
+	}
+	return buf.String()
+}
@@ -0,0 +1,97 @@
+package speech
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/sathyabhat/ynab-weekly-wrap/internal/processor"
+)
+
+// spokenCount is how many top categories the summary reads out
+const spokenCount = 3
+
+// Script writes a short spoken version of the wrap: the headline figures,
+// where most of the money went and what is over budget. It is meant to be
+// heard in under a minute, so the detail stays in the text report.
+func Script(heading string, analysis *processor.AnalysisResult) string {
+	var sentences []string
+	if heading != "" {
+		sentences = append(sentences, speakable(heading)+".")
+	}
+
+	if analysis.SafeToSpend != nil {
+		sentences = append(sentences, fmt.Sprintf("You can safely spend %s through Sunday.", dollars(analysis.SafeToSpend.Amount)))
+	}
+	switch {
+	case analysis.Overview == nil:
+	case analysis.NoBudget:
+		sentences = append(sentences, fmt.Sprintf("You spent %s.", dollars(analysis.Overview.TotalSpent)))
+	default:
+		sentences = append(sentences, fmt.Sprintf("You spent %s, which is %.0f percent of this month's budget.",
+			dollars(analysis.Overview.TotalSpent), analysis.Overview.HealthPercentage))
+	}
+
+	top := analysis.TopSpending
+	if len(top) > spokenCount {
+		top = top[:spokenCount]
+	}
+	if len(top) > 0 {
+		var parts []string
+		for _, category := range top {
+			parts = append(parts, fmt.Sprintf("%s at %s", speakable(category.Category), dollars(category.Spent)))
+		}
+		sentences = append(sentences, "Most went on "+joinList(parts)+".")
+	}
+
+	switch len(analysis.Concerns) {
+	case 0:
+		if analysis.NoBudget {
+			break // Spending only, so there is no budget to be within
+		}
+		sentences = append(sentences, "Every category is within budget.")
+	case 1:
+		concern := analysis.Concerns[0]
+		sentences = append(sentences, fmt.Sprintf("%s is over budget by %s.", speakable(concern.Category), dollars(concern.Over)))
+	default:
+		var names []string
+		for _, concern := range analysis.Concerns {
+			names = append(names, speakable(concern.Category))
+		}
+		sentences = append(sentences, fmt.Sprintf("%d categories are over budget: %s.", len(names), joinList(names)))
+	}
+
+	return strings.Join(sentences, " ")
+}
+
+// dollars reads milliunits as whole dollars
+func dollars(milliunits int64) string {
+	amount := (max(milliunits, -milliunits) + 500) / 1000
+	unit := "dollars"
+	if amount == 1 {
+		unit = "dollar"
+	}
+	if milliunits < 0 {
+		return fmt.Sprintf("minus %d %s", amount, unit)
+	}
+	return fmt.Sprintf("%d %s", amount, unit)
+}
+
+// speakable drops emoji and symbols, which speech engines read out by name
+func speakable(text string) string {
+	text = strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) || unicode.IsSpace(r) || unicode.IsPunct(r) {
+			return r
+		}
+		return -1
+	}, text)
+	return strings.Join(strings.Fields(text), " ")
+}
+
+// joinList joins items as "a, b and c"
+func joinList(items []string) string {
+	if len(items) < 2 {
+		return strings.Join(items, "")
+	}
+	return strings.Join(items[:len(items)-1], ", ") + " and " + items[len(items)-1]
+}
@@ -0,0 +1,79 @@
+package speech
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os/exec"
+	"strings"
+
+	"github.com/sathyabhat/ynab-weekly-wrap/internal/config"
+)
+
+// Synthesizer turns text into speech encoded as OGG/Opus, the format
+// Telegram plays as a voice message
+type Synthesizer interface {
+	Synthesize(ctx context.Context, text string) ([]byte, error)
+}
+
+// New returns the synthesizer the config asks for
+func New(cfg config.SpeechConfig) (Synthesizer, error) {
+	switch cfg.Provider {
+	case "", "espeak":
+		return &Espeak{Voice: cfg.Voice}, nil
+	case "command":
+		if cfg.Command == "" {
+			return nil, fmt.Errorf("the command speech provider needs a command")
+		}
+		return &Command{Command: cfg.Command}, nil
+	default:
+		return nil, fmt.Errorf("unknown speech provider %q", cfg.Provider)
+	}
+}
+
+// Espeak speaks with a local espeak-ng and converts its WAV output to
+// OGG/Opus with ffmpeg; both must be on the PATH
+type Espeak struct {
+	Voice string
+}
+
+func (e *Espeak) Synthesize(ctx context.Context, text string) ([]byte, error) {
+	args := []string{"--stdin", "--stdout"}
+	if e.Voice != "" {
+		args = append(args, "-v", e.Voice)
+	}
+	wav, err := run(ctx, []byte(text), "espeak-ng", args...)
+	if err != nil {
+		return nil, err
+	}
+	return run(ctx, wav, "ffmpeg", "-loglevel", "error", "-i", "pipe:0", "-c:a", "libopus", "-b:a", "32k", "-f", "ogg", "pipe:1")
+}
+
+// Command pipes the text through a shell command, so any TTS engine or
+// cloud API client can be plugged in. The command must write OGG/Opus.
+type Command struct {
+	Command string
+}
+
+func (c *Command) Synthesize(ctx context.Context, text string) ([]byte, error) {
+	return run(ctx, []byte(text), "sh", "-c", c.Command)
+}
+
+// run executes a program with input on stdin and returns its stdout
+func run(ctx context.Context, input []byte, name string, args ...string) ([]byte, error) {
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, name, args...)
+	cmd.Stdin = bytes.NewReader(input)
+	cmd.Stdout, cmd.Stderr = &stdout, &stderr
+
+	if err := cmd.Run(); err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return nil, fmt.Errorf("%s failed: %w: %s", name, err, msg)
+		}
+		return nil, fmt.Errorf("%s failed: %w", name, err)
+	}
+	if stdout.Len() == 0 {
+		return nil, fmt.Errorf("%s produced no audio", name)
+	}
+	return stdout.Bytes(), nil
+}
@@ -0,0 +1,70 @@
+package speech
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/sathyabhat/ynab-weekly-wrap/internal/config"
+	"github.com/sathyabhat/ynab-weekly-wrap/internal/processor"
+)
+
+func TestScript(t *testing.T) {
+	analysis := &processor.AnalysisResult{
+		Overview:    &processor.Overview{TotalSpent: 230_400, HealthPercentage: 64.2},
+		SafeToSpend: &processor.SafeToSpend{Amount: 1_000},
+		TopSpending: []processor.TopSpendingCategory{
+			{Category: "🛒 Groceries", Spent: 120_000},
+			{Category: "Dining Out", Spent: 60_000},
+			{Category: "Fuel", Spent: 40_000},
+			{Category: "Gifts", Spent: 10_400},
+		},
+		Concerns: []processor.CategoryConcernWithTransactions{{Category: "Dining Out", Over: 15_000}},
+	}
+
+	got := Script("Weekly Financial Wrap", analysis)
+	want := "Weekly Financial Wrap. You can safely spend 1 dollar through Sunday. " +
+		"You spent 230 dollars, which is 64 percent of this month's budget. " +
+		"Most went on Groceries at 120 dollars, Dining Out at 60 dollars and Fuel at 40 dollars. " +
+		"Dining Out is over budget by 15 dollars."
+	if got != want {
+		t.Errorf("Script:\n got %q\nwant %q", got, want)
+	}
+}
+
+func TestScript_SeveralConcerns(t *testing.T) {
+	analysis := &processor.AnalysisResult{
+		Concerns: []processor.CategoryConcernWithTransactions{{Category: "Dining Out"}, {Category: "Fuel"}, {Category: "Gifts"}},
+	}
+	if got := Script("", analysis); got != "3 categories are over budget: Dining Out, Fuel and Gifts." {
+		t.Errorf("Script: got %q", got)
+	}
+}
+
+func TestCommand_Synthesize(t *testing.T) {
+	synth, err := New(config.SpeechConfig{Provider: "command", Command: "tr a-z A-Z"})
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	audio, err := synth.Synthesize(context.Background(), "hello")
+	if err != nil {
+		t.Fatalf("Synthesize failed: %v", err)
+	}
+	if string(audio) != "HELLO" {
+		t.Errorf("expected the command's output, got %q", audio)
+	}
+}
+
+func TestCommand_Failure(t *testing.T) {
+	synth := &Command{Command: "echo 'voice not found' >&2; exit 3"}
+	_, err := synth.Synthesize(context.Background(), "hello")
+	if err == nil || !strings.Contains(err.Error(), "voice not found") {
+		t.Errorf("expected the command's error output, got %v", err)
+	}
+}
+
+func TestNew_CommandRequired(t *testing.T) {
+	if _, err := New(config.SpeechConfig{Provider: "command"}); err == nil {
+		t.Error("expected an error without a command")
+	}
+}
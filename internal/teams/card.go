@@ -0,0 +1,213 @@
+package teams
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/sathyabhat/ynab-weekly-wrap/internal/notes"
+	"github.com/sathyabhat/ynab-weekly-wrap/internal/processor"
+	"github.com/sathyabhat/ynab-weekly-wrap/internal/publisher"
+)
+
+const adaptiveCardSchema = "http://adaptivecards.io/schemas/adaptive-card.json"
+
+// severityMarks sets each over budget severity tier apart with an emoji and
+// a container style
+var severityMarks = map[processor.Severity]struct {
+	emoji string
+	style string
+}{
+	processor.SeveritySlight:   {"🟡 ", "warning"},
+	processor.SeverityModerate: {"🟠 ", "attention"},
+	processor.SeveritySevere:   {"🔴 ", "attention"},
+}
+
+// AdaptiveCard is the subset of the Adaptive Card schema used for the wrap
+type AdaptiveCard struct {
+	Schema  string         `json:"$schema"`
+	Type    string         `json:"type"`
+	Version string         `json:"version"`
+	Body    []CardElement  `json:"body"`
+	MSTeams map[string]any `json:"msteams,omitempty"`
+}
+
+// CardElement is a TextBlock, FactSet or Container
+type CardElement struct {
+	Type      string        `json:"type"`
+	Text      string        `json:"text,omitempty"`
+	Size      string        `json:"size,omitempty"`
+	Weight    string        `json:"weight,omitempty"`
+	Color     string        `json:"color,omitempty"`
+	IsSubtle  bool          `json:"isSubtle,omitempty"`
+	Wrap      bool          `json:"wrap,omitempty"`
+	Separator bool          `json:"separator,omitempty"`
+	Spacing   string        `json:"spacing,omitempty"`
+	Style     string        `json:"style,omitempty"`
+	Facts     []Fact        `json:"facts,omitempty"`
+	Items     []CardElement `json:"items,omitempty"`
+}
+
+// Fact is a single title/value row in a FactSet
+type Fact struct {
+	Title string `json:"title"`
+	Value string `json:"value"`
+}
+
+func newCard(body ...CardElement) AdaptiveCard {
+	return AdaptiveCard{
+		Schema:  adaptiveCardSchema,
+		Type:    "AdaptiveCard",
+		Version: "1.4",
+		Body:    body,
+		MSTeams: map[string]any{"width": "Full"},
+	}
+}
+
+func textBlock(text string) CardElement {
+	return CardElement{Type: "TextBlock", Text: text, Wrap: true}
+}
+
+// sectionHeading starts a new visually separated section
+func sectionHeading(text string) CardElement {
+	return CardElement{Type: "TextBlock", Text: text, Size: "Medium", Weight: "Bolder", Wrap: true, Separator: true, Spacing: "Medium"}
+}
+
+// buildCard renders the analysis as an Adaptive Card
+func buildCard(report publisher.Report) AdaptiveCard {
+	analysis := report.Analysis
+	currency := analysis.Currency
+
+	body := []CardElement{
+		{Type: "TextBlock", Text: "📊 " + report.Title, Size: "Large", Weight: "Bolder", Wrap: true},
+	}
+	if coverage := analysis.Coverage; coverage != nil {
+		body = append(body, CardElement{Type: "TextBlock", IsSubtle: true, Wrap: true,
+			Text: fmt.Sprintf("🗓️ Partial period: covers %s to %s (%d of %d days)",
+				coverage.From.Format("2006-01-02"), coverage.To.Format("2006-01-02"), coverage.Days, coverage.PeriodDays)})
+	}
+	if analysis.FirstReport {
+		body = append(body, CardElement{Type: "TextBlock", Text: "ℹ️ First report, comparisons unavailable", IsSubtle: true, Wrap: true})
+	}
+
+	if safe := analysis.SafeToSpend; safe != nil {
+		color := "Good"
+		if safe.Amount <= 0 {
+			color = "Attention"
+		}
+		body = append(body, CardElement{Type: "TextBlock", Size: "Large", Weight: "Bolder", Color: color, Wrap: true,
+			Text: fmt.Sprintf("Safe to spend through %s: %s", safe.Through.Format("Monday"), currency.Amount(safe.Amount))})
+	}
+
+	// Overview
+	overview := []Fact{{Title: "Total Spent", Value: currency.Amount(analysis.Overview.TotalSpent)}}
+	if !analysis.NoBudget {
+		overview = append(overview,
+			Fact{Title: "Total Budgeted", Value: currency.Amount(analysis.Overview.TotalBudgeted)},
+			Fact{Title: "Remaining", Value: currency.Amount(analysis.Overview.TotalBalance)},
+		)
+	}
+	if lumpy := analysis.Overview.LumpySpent; lumpy > 0 {
+		overview = append(overview, Fact{Title: "One-off/Annual", Value: currency.Amount(lumpy)})
+	}
+	body = append(body,
+		sectionHeading("💰 Overview"),
+		CardElement{Type: "FactSet", Facts: overview},
+	)
+
+	// Top spending
+	body = append(body, sectionHeading("🏆 Top Spending Categories"))
+	if len(analysis.TopSpending) == 0 {
+		body = append(body, textBlock("No spending in this period"))
+	} else {
+		var facts []Fact
+		for _, category := range analysis.TopSpending {
+			value := currency.Amount(category.Spent)
+			if analysis.HasPrevData {
+				value += fmt.Sprintf(" (%s)", currency.Delta(category.SpendDelta))
+			}
+			if !analysis.NoBudget {
+				value += " · balance " + currency.Amount(category.Balance)
+			}
+			if note := notes.Lookup(analysis.Notes, category.Category); note != "" {
+				value += " · 📝 " + note
+			}
+			facts = append(facts, Fact{Title: category.Category, Value: value})
+		}
+		body = append(body, CardElement{Type: "FactSet", Facts: facts})
+	}
+
+	if len(analysis.Payees) > 0 {
+		var facts []Fact
+		for _, payee := range analysis.Payees {
+			facts = append(facts, Fact{Title: payee.Payee, Value: fmt.Sprintf("%s across %d transactions", currency.Amount(payee.Spent), payee.Transactions)})
+		}
+		body = append(body, sectionHeading("🛒 Top Payees"), CardElement{Type: "FactSet", Facts: facts})
+	}
+
+	// Concerns
+	switch {
+	case analysis.NoBudget:
+		// Spending only, so nothing is over budget
+	case len(analysis.Concerns) == 0:
+		body = append(body, sectionHeading("⚠️ Over Budget Categories"),
+			CardElement{Type: "TextBlock", Text: "No categories over budget - great job! 🎉", Color: "Good", Wrap: true})
+	default:
+		body = append(body, sectionHeading("⚠️ Over Budget Categories"))
+		for _, concern := range analysis.Concerns {
+			mark, ok := severityMarks[concern.Severity]
+			if !ok {
+				mark.style = "attention"
+			}
+			body = append(body, CardElement{
+				Type:  "Container",
+				Style: mark.style,
+				Items: []CardElement{
+					{Type: "TextBlock", Text: mark.emoji + concern.Category, Weight: "Bolder", Wrap: true},
+					{Type: "FactSet", Facts: []Fact{
+						{Title: "Spent", Value: currency.Amount(concern.Spent)},
+						{Title: "Balance", Value: currency.Amount(concern.Balance)},
+						{Title: "Over by", Value: currency.Amount(concern.Over)},
+					}},
+				},
+			})
+		}
+	}
+
+	if hygiene := analysis.Hygiene; !hygiene.Empty() {
+		var facts []Fact
+		if len(hygiene.Unused) > 0 {
+			facts = append(facts, Fact{Title: fmt.Sprintf("Unused for %d+ months", hygiene.UnusedMonths), Value: strings.Join(hygiene.Unused, ", ")})
+		}
+		for _, hidden := range hygiene.HiddenBalances {
+			facts = append(facts, Fact{Title: hidden.Category, Value: "hidden but holds " + currency.Amount(hidden.Balance)})
+		}
+		for _, over := range hygiene.ChronicOver {
+			facts = append(facts, Fact{Title: over.Category, Value: fmt.Sprintf("overspent in %d of %d months (%s spent vs %s budgeted on average)",
+				over.Months, over.OfMonths, currency.Amount(over.AvgSpent), currency.Amount(over.AvgBudgeted))})
+		}
+		for _, under := range hygiene.ChronicUnder {
+			facts = append(facts, Fact{Title: under.Category, Value: fmt.Sprintf("under half spent in %d of %d months (%s spent vs %s budgeted on average)",
+				under.Months, under.OfMonths, currency.Amount(under.AvgSpent), currency.Amount(under.AvgBudgeted))})
+		}
+		body = append(body, sectionHeading("🧹 Budget Tidy-Up"), CardElement{Type: "FactSet", Facts: facts})
+	}
+
+	if len(analysis.NewPayees) > 0 {
+		body = append(body, sectionHeading("🆕 New Payees"), textBlock(strings.Join(analysis.NewPayees, ", ")))
+	}
+
+	// Notes for categories not listed above
+	var listed []string
+	for _, category := range analysis.TopSpending {
+		listed = append(listed, category.Category)
+	}
+	if unlisted := notes.Unlisted(analysis.Notes, listed); len(unlisted) > 0 {
+		var facts []Fact
+		for _, category := range unlisted {
+			facts = append(facts, Fact{Title: category, Value: analysis.Notes[category]})
+		}
+		body = append(body, sectionHeading("📝 Notes"), CardElement{Type: "FactSet", Facts: facts})
+	}
+
+	return newCard(body...)
+}
@@ -0,0 +1,85 @@
+package teams
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+
+	"github.com/sathyabhat/ynab-weekly-wrap/internal/publisher"
+)
+
+// WebhookPublisher implements the publisher.Publisher interface for Microsoft
+// Teams incoming webhooks (including Workflows "post to a channel" webhooks)
+type WebhookPublisher struct {
+	WebhookURL string
+}
+
+// WebhookRequest is the message payload carrying a single Adaptive Card
+type WebhookRequest struct {
+	Type        string       `json:"type"`
+	Attachments []Attachment `json:"attachments"`
+}
+
+// Attachment wraps an Adaptive Card for delivery
+type Attachment struct {
+	ContentType string       `json:"contentType"`
+	Content     AdaptiveCard `json:"content"`
+}
+
+// NewWebhookPublisher creates a new Teams webhook publisher
+func NewWebhookPublisher(webhookURL string) *WebhookPublisher {
+	return &WebhookPublisher{WebhookURL: webhookURL}
+}
+
+// Name identifies the destination in delivery summaries
+func (p *WebhookPublisher) Name() string {
+	return "teams"
+}
+
+// Publish sends the message as a single text block card
+func (p *WebhookPublisher) Publish(message string) error {
+	return p.send(newCard(textBlock(message)))
+}
+
+// PublishReport sends the report as an Adaptive Card with sections for the
+// overview, top spending and concerns
+func (p *WebhookPublisher) PublishReport(report publisher.Report) error {
+	if report.Analysis == nil {
+		return p.Publish(report.Message)
+	}
+	return p.send(buildCard(report))
+}
+
+func (p *WebhookPublisher) send(card AdaptiveCard) error {
+	log.Println("Sending Adaptive Card to Teams webhook")
+
+	payload := WebhookRequest{
+		Type: "message",
+		Attachments: []Attachment{{
+			ContentType: "application/vnd.microsoft.card.adaptive",
+			Content:     card,
+		}},
+	}
+
+	jsonData, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	resp, err := http.Post(p.WebhookURL, "application/json", bytes.NewBuffer(jsonData))
+	if err != nil {
+		return fmt.Errorf("failed to send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("teams webhook error (status %d): %s", resp.StatusCode, string(body))
+	}
+
+	log.Println("Teams message sent successfully")
+	return nil
+}
@@ -0,0 +1,109 @@
+package teams
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/sathyabhat/ynab-weekly-wrap/internal/processor"
+	"github.com/sathyabhat/ynab-weekly-wrap/internal/publisher"
+)
+
+func TestTeamsPublishReport_AdaptiveCard(t *testing.T) {
+	var payload WebhookRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		if err := json.Unmarshal(body, &payload); err != nil {
+			t.Errorf("Failed to unmarshal payload: %v", err)
+		}
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer server.Close()
+
+	p := NewWebhookPublisher(server.URL)
+	err := p.PublishReport(publisher.Report{
+		Title: "Weekly Financial Wrap - 2026-01-19 to 2026-01-26",
+		Analysis: &processor.AnalysisResult{
+			Overview: &processor.Overview{TotalSpent: 550_500, TotalBudgeted: 1_000_000, TotalBalance: 300_000},
+			TopSpending: []processor.TopSpendingCategory{
+				{Category: "Groceries", Spent: 200_000, Balance: 300_000},
+			},
+			Concerns: []processor.CategoryConcernWithTransactions{
+				{Category: "Dining", Spent: 350_000, Balance: -50_000, Over: 50_000},
+			},
+		},
+	})
+	if err != nil {
+		t.Fatalf("PublishReport failed: %v", err)
+	}
+
+	if payload.Type != "message" || len(payload.Attachments) != 1 {
+		t.Fatalf("unexpected payload: %+v", payload)
+	}
+	attachment := payload.Attachments[0]
+	if attachment.ContentType != "application/vnd.microsoft.card.adaptive" {
+		t.Errorf("ContentType: got %q", attachment.ContentType)
+	}
+
+	card := attachment.Content
+	if card.Type != "AdaptiveCard" || card.Schema != adaptiveCardSchema {
+		t.Errorf("unexpected card header: %+v", card)
+	}
+
+	var headings []string
+	var overview, concern *CardElement
+	for i, element := range card.Body {
+		if element.Separator {
+			headings = append(headings, element.Text)
+		}
+		if element.Type == "FactSet" && overview == nil {
+			overview = &card.Body[i]
+		}
+		if element.Type == "Container" {
+			concern = &card.Body[i]
+		}
+	}
+	if strings.Join(headings, "|") != "💰 Overview|🏆 Top Spending Categories|⚠️ Over Budget Categories" {
+		t.Errorf("unexpected sections: %v", headings)
+	}
+	if overview == nil || overview.Facts[0].Value != "$550.50" {
+		t.Errorf("unexpected overview: %+v", overview)
+	}
+	if concern == nil || concern.Items[0].Text != "Dining" || concern.Items[1].Facts[2].Value != "$50" {
+		t.Errorf("unexpected concern: %+v", concern)
+	}
+}
+
+func TestTeamsPublish_PlainMessage(t *testing.T) {
+	var payload WebhookRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewDecoder(r.Body).Decode(&payload)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	if err := NewWebhookPublisher(server.URL).Publish("🚨 **Critical Budget Alert**"); err != nil {
+		t.Fatalf("Publish failed: %v", err)
+	}
+
+	body := payload.Attachments[0].Content.Body
+	if len(body) != 1 || body[0].Text != "🚨 **Critical Budget Alert**" || !body[0].Wrap {
+		t.Errorf("unexpected card body: %+v", body)
+	}
+}
+
+func TestTeamsPublish_Error(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte("invalid card"))
+	}))
+	defer server.Close()
+
+	err := NewWebhookPublisher(server.URL).Publish("test")
+	if err == nil || !strings.Contains(err.Error(), "status 400") {
+		t.Errorf("expected status 400 error, got %v", err)
+	}
+}
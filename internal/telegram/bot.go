@@ -1,18 +1,40 @@
 package telegram
 
 import (
-	"bytes"
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"log"
+	"math/rand/v2"
 	"net/http"
+	"net/url"
+	"time"
 
 	"github.com/sathyabhat/ynab-weekly-wrap/internal/config"
+	"github.com/sathyabhat/ynab-weekly-wrap/internal/metrics"
+	"github.com/sathyabhat/ynab-weekly-wrap/internal/notes"
+	"github.com/sathyabhat/ynab-weekly-wrap/internal/oneoff"
+	"github.com/sathyabhat/ynab-weekly-wrap/internal/pause"
+	"github.com/sathyabhat/ynab-weekly-wrap/internal/publisher"
+	"github.com/sathyabhat/ynab-weekly-wrap/internal/render"
+	"github.com/sathyabhat/ynab-weekly-wrap/internal/speech"
 )
 
 type Bot struct {
-	config config.TelegramConfig
+	config  config.TelegramConfig
+	apiURL  string
+	client  *http.Client
+	sleep   func(time.Duration) // Waits between retries; replaced in tests
+	details *detailStore        // Detail sections behind the compact summary's buttons
+	speaker speech.Synthesizer  // Voices the spoken summary; nil when disabled
+	notes   *notes.Store        // Category notes set with /note; nil when disabled
+	sends   *oneoff.Store       // Wraps queued with /schedule; nil when disabled
+	pauses  *pause.Store        // Pause of the scheduled sends set with /pause; nil when disabled
+	pinned  int                 // Message ID of the wrap this bot last pinned
+	latest  *coverageStore      // Data coverage details of the last wrap, for /coverage
+	metrics *metrics.Recorder   // Records API call latencies; nil when disabled
 }
 
 // SendMessageRequest represents the request to send a message via Telegram API
@@ -22,41 +44,339 @@ type SendMessageRequest struct {
 	ParseMode             string `json:"parse_mode"`
 	MessageThreadID       int    `json:"message_thread_id,omitempty"`
 	DisableWebPagePreview bool   `json:"disable_web_page_preview"`
+	DisableNotification   bool   `json:"disable_notification,omitempty"`
+	ReplyToMessageID      int    `json:"reply_to_message_id,omitempty"`
+
+	ReplyMarkup *InlineKeyboardMarkup `json:"reply_markup,omitempty"`
+}
+
+// messageOptions are extras for the message(s) a document is sent as
+type messageOptions struct {
+	keyboard *InlineKeyboardMarkup // Attached to the last message
+	replyTo  int                   // Message the document replies to
 }
 
 // APIResponse represents a generic Telegram API response
 type APIResponse struct {
-	OK    bool        `json:"ok"`
-	Error string      `json:"error"`
-	Result json.RawMessage `json:"result"`
+	OK          bool                `json:"ok"`
+	ErrorCode   int                 `json:"error_code"`
+	Description string              `json:"description"`
+	Parameters  *ResponseParameters `json:"parameters"`
+	Result      json.RawMessage     `json:"result"`
+}
+
+// ResponseParameters carries extra information about a failed request
+type ResponseParameters struct {
+	RetryAfter int `json:"retry_after"` // Seconds to wait before retrying when rate limited
+}
+
+const (
+	telegramAPIURL = "https://api.telegram.org"
+
+	// maxMessageLength is Telegram's limit on the text of a single message
+	maxMessageLength = 4096
+)
+
+// BotOption configures optional Bot behaviour
+type BotOption func(*Bot)
+
+// WithSynthesizer sends a spoken summary as a voice message after each
+// report, voiced by s
+func WithSynthesizer(s speech.Synthesizer) BotOption {
+	return func(b *Bot) {
+		b.speaker = s
+	}
+}
+
+// WithNotes lets the chat set category notes with /note
+func WithNotes(store *notes.Store) BotOption {
+	return func(b *Bot) {
+		b.notes = store
+	}
+}
+
+// WithSends lets the chat queue a one-off wrap with /schedule
+func WithSends(store *oneoff.Store) BotOption {
+	return func(b *Bot) {
+		b.sends = store
+	}
+}
+
+// WithPauses lets the chat pause the scheduled sends with /pause and
+// resume them with /resume
+func WithPauses(store *pause.Store) BotOption {
+	return func(b *Bot) {
+		b.pauses = store
+	}
+}
+
+// WithMetrics records the latency and outcome of Bot API calls, apart from
+// the long polls for updates
+func WithMetrics(recorder *metrics.Recorder) BotOption {
+	return func(b *Bot) {
+		b.metrics = recorder
+	}
 }
 
-const telegramAPIURL = "https://api.telegram.org"
+func NewBot(telegramConfig config.TelegramConfig, opts ...BotOption) (*Bot, error) {
+	client, err := newHTTPClient(telegramConfig.ProxyURL)
+	if err != nil {
+		return nil, err
+	}
+
+	bot := &Bot{
+		config:  telegramConfig,
+		apiURL:  telegramAPIURL,
+		client:  client,
+		sleep:   time.Sleep,
+		details: newDetailStore(),
+		latest:  &coverageStore{},
+	}
+	for _, opt := range opts {
+		opt(bot)
+	}
+	return bot, nil
+}
+
+// newHTTPClient returns the client Bot API requests are sent with. Without a
+// proxy URL it is the default client, which still honours HTTPS_PROXY.
+func newHTTPClient(proxyURL string) (*http.Client, error) {
+	if proxyURL == "" {
+		return http.DefaultClient, nil
+	}
+
+	proxy, err := url.Parse(proxyURL)
+	if err != nil || proxy.Host == "" {
+		return nil, fmt.Errorf("invalid Telegram proxy URL: expected e.g. socks5://host:1080")
+	}
+	switch proxy.Scheme {
+	case "http", "https", "socks5", "socks5h":
+	default:
+		return nil, fmt.Errorf("unsupported Telegram proxy scheme %q: must be http, https, socks5 or socks5h", proxy.Scheme)
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = http.ProxyURL(proxy)
+	return &http.Client{Transport: transport}, nil
+}
 
-func NewBot(telegramConfig config.TelegramConfig) (*Bot, error) {
-	return &Bot{
-		config: telegramConfig,
-	}, nil
+// Name identifies the destination in delivery summaries. With several chats
+// configured each bot is named after its chat, and its topic if it has one,
+// so they can be told apart.
+func (b *Bot) Name() string {
+	if len(b.config.Chats) > 1 {
+		if b.config.TopicID > 0 {
+			return fmt.Sprintf("telegram:%d/%d", b.config.ChatID, b.config.TopicID)
+		}
+		return fmt.Sprintf("telegram:%d", b.config.ChatID)
+	}
+	return "telegram"
 }
 
 func (b *Bot) Publish(message string) error {
 	log.Printf("Sending message to chat ID: %d", b.config.ChatID)
 
-	return b.sendMessage(message)
+	_, err := b.sendMessage(context.Background(), render.Parse(message), true, messageOptions{})
+	return err
+}
+
+// PublishReport sends the report with no deadline; see PublishReportContext
+func (b *Bot) PublishReport(report publisher.Report) error {
+	return b.PublishReportContext(context.Background(), report)
 }
 
-func (b *Bot) sendMessage(message string) error {
-	// Telegram has a 4096 character limit
-	if len(message) > 4096 {
-		log.Printf("Message too long for Telegram (%d characters), truncating to 4096", len(message))
-		message = message[:4093] + "..."
+// PublishReportContext sends the report's rendered document, escaped for the
+// configured parse mode, followed by the summary card, chart, voice summary
+// and transactions CSV when enabled. Retries stop before they would wait past
+// ctx's deadline.
+func (b *Bot) PublishReportContext(ctx context.Context, report publisher.Report) error {
+	log.Printf("Sending report to chat ID: %d", b.config.ChatID)
+
+	var messageID int
+	var err error
+	if b.config.Compact && report.Analysis != nil {
+		messageID, err = b.publishCompact(ctx, report)
+	} else {
+		messageID, err = b.sendMessage(ctx, report.Doc(), true, messageOptions{})
+	}
+	if err != nil {
+		return err
+	}
+	if report.Analysis != nil && report.Analysis.Confidence != nil {
+		b.latest.set(coverageDetail(report))
+	}
+
+	// Pinning and the images are extras: the wrap itself has gone out, so a
+	// failure here shouldn't cause it to be sent again
+	if b.config.PinMessage {
+		if err := b.replacePin(messageID); err != nil {
+			log.Printf("Warning: could not pin the wrap: %v", err)
+		}
 	}
 
+	if b.config.SummaryCard && report.Analysis != nil {
+		if err := b.sendSummaryCard(ctx, report); err != nil {
+			log.Printf("Warning: could not send summary card: %v", err)
+		}
+	}
+	if b.config.Chart && report.Analysis != nil && len(report.Analysis.TopSpending) > 0 {
+		if err := b.sendChart(ctx, report); err != nil {
+			log.Printf("Warning: could not send chart: %v", err)
+		}
+	}
+	if b.speaker != nil && report.Analysis != nil {
+		if err := b.sendVoiceSummary(ctx, report); err != nil {
+			log.Printf("Warning: could not send voice summary: %v", err)
+		}
+	}
+	if b.config.AttachCSV && len(report.Transactions) > 0 {
+		if err := b.sendTransactionsCSV(ctx, report); err != nil {
+			log.Printf("Warning: could not send transactions CSV: %v", err)
+		}
+	}
+	return nil
+}
+
+// PublishUntruncated sends the whole message, leaving Telegram to reject
+// anything too long or with broken markdown. It is used to check a wrap
+// against the real API before it reaches the family chat.
+func (b *Bot) PublishUntruncated(message string) error {
+	log.Printf("Sending untruncated message (%d characters) to chat ID: %d", len(message), b.config.ChatID)
+
+	_, err := b.sendMessage(context.Background(), render.Parse(message), false, messageOptions{})
+	return err
+}
+
+// markup returns how text is written for the configured parse mode. The
+// legacy Markdown mode keeps the **bold** markup unescaped, as before.
+func (b *Bot) markup() render.Markup {
+	switch b.config.ParseMode {
+	case "Markdown":
+		return render.Markdown
+	case "HTML":
+		return render.TelegramHTML
+	default:
+		return render.MarkdownV2
+	}
+}
+
+func (b *Bot) parseMode() string {
+	if b.config.ParseMode == "" {
+		return "MarkdownV2"
+	}
+	return b.config.ParseMode
+}
+
+// sendMessage sends the document, split into several sequential messages
+// when it is longer than Telegram allows, and returns the ID of the first.
+// With split off the document goes out in one piece and Telegram is left to
+// reject it.
+func (b *Bot) sendMessage(ctx context.Context, doc *render.Document, split bool, opts messageOptions) (int, error) {
+	if !split {
+		return b.sendText(ctx, b.markup().Format(doc), opts)
+	}
+
+	chunks := b.split(doc)
+	if len(chunks) > 1 {
+		log.Printf("Message too long for Telegram, sending it in %d parts", len(chunks))
+	}
+	var first int
+	for i, chunk := range chunks {
+		chunkOpts := messageOptions{replyTo: opts.replyTo}
+		if i == len(chunks)-1 {
+			chunkOpts.keyboard = opts.keyboard
+		}
+		messageID, err := b.sendText(ctx, b.markup().Format(chunk), chunkOpts)
+		if err != nil {
+			if i > 0 {
+				return first, &PartialSendError{Part: i + 1, Parts: len(chunks), Err: err}
+			}
+			if len(chunks) > 1 {
+				return first, fmt.Errorf("part %d of %d: %w", i+1, len(chunks), err)
+			}
+			return first, err
+		}
+		if i == 0 {
+			first = messageID
+		}
+	}
+	return first, nil
+}
+
+// sendText sends one message, retrying failed requests with exponential
+// backoff, and returns its ID
+func (b *Bot) sendText(ctx context.Context, text string, opts messageOptions) (int, error) {
+	var messageID int
+	err := b.withRetry(ctx, func() error {
+		var err error
+		messageID, err = b.post(text, opts)
+		return err
+	})
+	return messageID, err
+}
+
+// withRetry runs send up to the configured number of attempts, backing off
+// between them. Errors Telegram marks as permanent, like a malformed
+// message, are returned straight away. It gives up early rather than wait
+// past ctx's deadline. Once the bot has retried, or was set to, the error is
+// wrapped in a RetryError so it isn't retried all over again by the caller.
+func (b *Bot) withRetry(ctx context.Context, send func() error) error {
+	attempts := max(b.config.RetryAttempts, 1)
+
+	var err error
+	for attempt := 1; attempt <= attempts; attempt++ {
+		err = send()
+		if err == nil || !isRetryable(err) {
+			return err
+		}
+		if attempt == attempts {
+			break
+		}
+		delay := b.backoff(attempt, err)
+		if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) < delay {
+			log.Printf("Telegram send attempt %d/%d failed, no time left to retry: %v", attempt, attempts, err)
+			return &RetryError{Attempts: attempt, Err: err}
+		}
+		log.Printf("Telegram send attempt %d/%d failed, retrying in %s: %v", attempt, attempts, delay, err)
+		b.sleep(delay)
+	}
+	if attempts > 1 {
+		return &RetryError{Attempts: attempts, Err: err}
+	}
+	return err
+}
+
+// backoff returns how long to wait after the given failed attempt. A rate
+// limit waits exactly as long as Telegram asks; anything else doubles the
+// retry delay each attempt, with jitter so several bots don't retry in step.
+func (b *Bot) backoff(attempt int, err error) time.Duration {
+	var apiErr *APIError
+	if errors.As(err, &apiErr) && apiErr.RetryAfter > 0 {
+		return apiErr.RetryAfter
+	}
+	delay := b.config.RetryDelay << (attempt - 1)
+	return delay/2 + rand.N(delay/2+1)
+}
+
+// isRetryable reports whether a failed send may succeed if tried again.
+// Network failures are; API errors only when they are temporary.
+func isRetryable(err error) bool {
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.Temporary()
+	}
+	return true
+}
+
+func (b *Bot) post(text string, opts messageOptions) (int, error) {
 	req := SendMessageRequest{
 		ChatID:                b.config.ChatID,
-		Text:                  message,
-		ParseMode:             "Markdown",
+		Text:                  text,
+		ParseMode:             b.parseMode(),
 		DisableWebPagePreview: true,
+		DisableNotification:   b.config.DisableNotification,
+		ReplyToMessageID:      opts.replyTo,
+		ReplyMarkup:           opts.keyboard,
 	}
 
 	// If topic ID is configured, add it to the request
@@ -65,34 +385,57 @@ func (b *Bot) sendMessage(message string) error {
 		log.Printf("Sending message to topic ID: %d", b.config.TopicID)
 	}
 
-	url := fmt.Sprintf("%s/bot%s/sendMessage", telegramAPIURL, b.config.BotToken)
-
-	jsonData, err := json.Marshal(req)
+	result, err := b.callJSON("sendMessage", req)
 	if err != nil {
-		return fmt.Errorf("failed to marshal request: %w", err)
+		return 0, err
+	}
+
+	var sent Message
+	if err := json.Unmarshal(result, &sent); err != nil {
+		return 0, fmt.Errorf("failed to parse sent message: %w", err)
 	}
 
-	resp, err := http.Post(url, "application/json", bytes.NewBuffer(jsonData))
+	log.Println("Message sent successfully")
+	return sent.MessageID, nil
+}
+
+// call posts a request to a Bot API method and returns its result, turning
+// an unsuccessful response into an APIError
+func (b *Bot) call(method, contentType string, body io.Reader) (json.RawMessage, error) {
+	start := time.Now()
+	result, err := b.do(method, contentType, body)
+	// getUpdates waits for updates, so its duration says nothing about latency
+	if method != "getUpdates" {
+		b.metrics.ObserveCall("telegram", time.Since(start), err)
+	}
+	return result, err
+}
+
+func (b *Bot) do(method, contentType string, body io.Reader) (json.RawMessage, error) {
+	endpoint := fmt.Sprintf("%s/bot%s/%s", b.apiURL, b.config.BotToken, method)
+
+	resp, err := b.client.Post(endpoint, contentType, body)
 	if err != nil {
-		return fmt.Errorf("failed to send request: %w", err)
+		return nil, fmt.Errorf("failed to send request: %w", err)
 	}
 	defer resp.Body.Close()
 
-	body, err := io.ReadAll(resp.Body)
+	respBody, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return fmt.Errorf("failed to read response: %w", err)
+		return nil, fmt.Errorf("failed to read response: %w", err)
 	}
 
 	var apiResp APIResponse
-	if err := json.Unmarshal(body, &apiResp); err != nil {
-		return fmt.Errorf("failed to parse response: %w", err)
+	if err := json.Unmarshal(respBody, &apiResp); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
 	}
 
 	if !apiResp.OK {
-		return fmt.Errorf("telegram API error: %s", apiResp.Error)
+		apiErr := &APIError{Code: apiResp.ErrorCode, Description: apiResp.Description}
+		if apiResp.Parameters != nil {
+			apiErr.RetryAfter = time.Duration(apiResp.Parameters.RetryAfter) * time.Second
+		}
+		return nil, apiErr
 	}
-
-	log.Println("Message sent successfully")
-	return nil
+	return apiResp.Result, nil
 }
-
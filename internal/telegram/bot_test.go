@@ -0,0 +1,649 @@
+package telegram
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sathyabhat/ynab-weekly-wrap/internal/config"
+	"github.com/sathyabhat/ynab-weekly-wrap/internal/metrics"
+	"github.com/sathyabhat/ynab-weekly-wrap/internal/processor"
+	"github.com/sathyabhat/ynab-weekly-wrap/internal/publisher"
+	"github.com/sathyabhat/ynab-weekly-wrap/internal/ynab"
+)
+
+func newTestBot(t *testing.T, handler http.HandlerFunc) *Bot {
+	t.Helper()
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	bot, err := NewBot(config.TelegramConfig{BotToken: "token", ChatID: 42})
+	if err != nil {
+		t.Fatalf("NewBot failed: %v", err)
+	}
+	bot.apiURL = server.URL
+	return bot
+}
+
+func TestPublish_Success(t *testing.T) {
+	var path string
+	bot := newTestBot(t, func(w http.ResponseWriter, r *http.Request) {
+		path = r.URL.Path
+		w.Write([]byte(`{"ok":true,"result":{}}`))
+	})
+
+	if err := bot.Publish("hello"); err != nil {
+		t.Fatalf("Publish failed: %v", err)
+	}
+	if path != "/bottoken/sendMessage" {
+		t.Errorf("path: got %q, want %q", path, "/bottoken/sendMessage")
+	}
+}
+
+func TestCall_RecordsMetrics(t *testing.T) {
+	recorder := metrics.NewRecorder()
+	bot := newTestBot(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"ok":true,"result":[]}`))
+	})
+	bot.metrics = recorder
+
+	if _, err := bot.call("sendMessage", "application/json", strings.NewReader("{}")); err != nil {
+		t.Fatalf("call failed: %v", err)
+	}
+	if _, err := bot.call("getUpdates", "application/json", strings.NewReader("{}")); err != nil {
+		t.Fatalf("call failed: %v", err)
+	}
+
+	if calls := recorder.Status().APIs["telegram"].Calls; calls != 1 {
+		t.Errorf("expected only the sendMessage call recorded, got %d calls", calls)
+	}
+}
+
+func TestPublish_RateLimited(t *testing.T) {
+	bot := newTestBot(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusTooManyRequests)
+		w.Write([]byte(`{"ok":false,"error_code":429,"description":"Too Many Requests: retry after 7","parameters":{"retry_after":7}}`))
+	})
+
+	err := bot.Publish("hello")
+	if !errors.Is(err, ErrTelegramRateLimited) {
+		t.Fatalf("expected ErrTelegramRateLimited, got %v", err)
+	}
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.RetryAfter != 7*time.Second {
+		t.Errorf("expected RetryAfter 7s, got %+v", apiErr)
+	}
+}
+
+func TestPublish_APIError(t *testing.T) {
+	bot := newTestBot(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(`{"ok":false,"error_code":400,"description":"Bad Request: chat not found"}`))
+	})
+
+	err := bot.Publish("hello")
+	if err == nil || errors.Is(err, ErrTelegramRateLimited) {
+		t.Fatalf("expected a non rate limit error, got %v", err)
+	}
+	if err.Error() != "telegram API error 400: Bad Request: chat not found" {
+		t.Errorf("unexpected error message: %v", err)
+	}
+}
+
+// newRetryingBot returns a test bot that retries up to attempts times and
+// records the delays it would have slept for
+func newRetryingBot(t *testing.T, attempts int, handler http.HandlerFunc) (*Bot, *[]time.Duration) {
+	t.Helper()
+	bot := newTestBot(t, handler)
+	bot.config.RetryAttempts = attempts
+	bot.config.RetryDelay = 100 * time.Millisecond
+
+	var delays []time.Duration
+	bot.sleep = func(d time.Duration) { delays = append(delays, d) }
+	return bot, &delays
+}
+
+func TestPublish_RetriesWithBackoff(t *testing.T) {
+	calls := 0
+	bot, delays := newRetryingBot(t, 3, func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if calls < 3 {
+			w.WriteHeader(http.StatusBadGateway)
+			w.Write([]byte(`{"ok":false,"error_code":502,"description":"Bad Gateway"}`))
+			return
+		}
+		w.Write([]byte(`{"ok":true,"result":{}}`))
+	})
+
+	if err := bot.Publish("hello"); err != nil {
+		t.Fatalf("Publish failed: %v", err)
+	}
+	if calls != 3 || len(*delays) != 2 {
+		t.Fatalf("expected 3 calls and 2 waits, got %d and %v", calls, *delays)
+	}
+	// Half the delay plus up to half again in jitter, doubling each attempt
+	if d := (*delays)[0]; d < 50*time.Millisecond || d > 100*time.Millisecond {
+		t.Errorf("first delay %s outside 50ms-100ms", d)
+	}
+	if d := (*delays)[1]; d < 100*time.Millisecond || d > 200*time.Millisecond {
+		t.Errorf("second delay %s outside 100ms-200ms", d)
+	}
+}
+
+func TestPublish_RetriesAfterRateLimit(t *testing.T) {
+	calls := 0
+	bot, delays := newRetryingBot(t, 3, func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if calls == 1 {
+			w.WriteHeader(http.StatusTooManyRequests)
+			w.Write([]byte(`{"ok":false,"error_code":429,"description":"Too Many Requests: retry after 7","parameters":{"retry_after":7}}`))
+			return
+		}
+		w.Write([]byte(`{"ok":true,"result":{}}`))
+	})
+
+	if err := bot.Publish("hello"); err != nil {
+		t.Fatalf("Publish failed: %v", err)
+	}
+	if len(*delays) != 1 || (*delays)[0] != 7*time.Second {
+		t.Errorf("expected to wait the 7s Telegram asked for, got %v", *delays)
+	}
+}
+
+func TestPublish_GivesUpAfterAttempts(t *testing.T) {
+	calls := 0
+	bot, _ := newRetryingBot(t, 3, func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte(`{"ok":false,"error_code":500,"description":"Internal Server Error"}`))
+	})
+
+	err := bot.Publish("hello")
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.Code != 500 {
+		t.Fatalf("expected the last 500 APIError, got %v", err)
+	}
+	if calls != 3 {
+		t.Errorf("expected 3 attempts, got %d", calls)
+	}
+	// The bot has retried, so delivery shouldn't retry the send again
+	var temp publisher.TemporaryError
+	if errors.As(err, &temp) && temp.Temporary() {
+		t.Errorf("expected a non-temporary error once retries are used up, got %v", err)
+	}
+}
+
+func TestPublishReportContext_StopsAtDeadline(t *testing.T) {
+	calls := 0
+	bot, delays := newRetryingBot(t, 3, func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.WriteHeader(http.StatusTooManyRequests)
+		w.Write([]byte(`{"ok":false,"error_code":429,"description":"Too Many Requests: retry after 30","parameters":{"retry_after":30}}`))
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	err := bot.PublishReportContext(ctx, publisher.Report{Message: "hello"})
+	if !errors.Is(err, ErrTelegramRateLimited) {
+		t.Fatalf("expected ErrTelegramRateLimited, got %v", err)
+	}
+	if calls != 1 || len(*delays) != 0 {
+		t.Errorf("expected no wait past the deadline, got %d calls and waits %v", calls, *delays)
+	}
+}
+
+func TestPublish_NoRetryOnBadRequest(t *testing.T) {
+	calls := 0
+	bot, delays := newRetryingBot(t, 3, func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(`{"ok":false,"error_code":400,"description":"Bad Request: can't parse entities"}`))
+	})
+
+	if err := bot.Publish("hello"); err == nil {
+		t.Fatal("expected an error")
+	}
+	if calls != 1 || len(*delays) != 0 {
+		t.Errorf("expected a single attempt, got %d calls and waits %v", calls, *delays)
+	}
+}
+
+func TestPublishUntruncated_SendsLongMessage(t *testing.T) {
+	var req SendMessageRequest
+	bot := newTestBot(t, func(w http.ResponseWriter, r *http.Request) {
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Errorf("Failed to decode request body: %v", err)
+		}
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(`{"ok":false,"error_code":400,"description":"Bad Request: message is too long"}`))
+	})
+
+	message := strings.Repeat("x", 5000)
+	err := bot.PublishUntruncated(message)
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.Code != 400 {
+		t.Fatalf("expected 400 APIError, got %v", err)
+	}
+	if len(req.Text) != len(message) {
+		t.Errorf("expected %d characters to be sent, got %d", len(message), len(req.Text))
+	}
+}
+
+func TestPublish_EscapesMarkdownV2(t *testing.T) {
+	var req SendMessageRequest
+	bot := newTestBot(t, func(w http.ResponseWriter, r *http.Request) {
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Errorf("Failed to decode request body: %v", err)
+		}
+		w.Write([]byte(`{"ok":true,"result":{}}`))
+	})
+
+	if err := bot.Publish("**7-Eleven *Store**: $12.50"); err != nil {
+		t.Fatalf("Publish failed: %v", err)
+	}
+	if req.ParseMode != "MarkdownV2" {
+		t.Errorf("parse mode: got %q, want MarkdownV2", req.ParseMode)
+	}
+	if want := `*7\-Eleven \*Store*: $12\.50`; req.Text != want {
+		t.Errorf("text: got %q, want %q", req.Text, want)
+	}
+}
+
+func TestPublish_LegacyMarkdown(t *testing.T) {
+	var req SendMessageRequest
+	bot := newTestBot(t, func(w http.ResponseWriter, r *http.Request) {
+		json.NewDecoder(r.Body).Decode(&req)
+		w.Write([]byte(`{"ok":true,"result":{}}`))
+	})
+	bot.config.ParseMode = "Markdown"
+
+	if err := bot.Publish("**Groceries**: $12.50"); err != nil {
+		t.Fatalf("Publish failed: %v", err)
+	}
+	if req.ParseMode != "Markdown" || req.Text != "**Groceries**: $12.50" {
+		t.Errorf("got %q in %q mode", req.Text, req.ParseMode)
+	}
+}
+
+func TestPublish_HTML(t *testing.T) {
+	var req SendMessageRequest
+	bot := newTestBot(t, func(w http.ResponseWriter, r *http.Request) {
+		json.NewDecoder(r.Body).Decode(&req)
+		w.Write([]byte(`{"ok":true,"result":{}}`))
+	})
+	bot.config.ParseMode = "HTML"
+
+	if err := bot.Publish("**7-Eleven *Store**: $12.50 <snacks & drinks>"); err != nil {
+		t.Fatalf("Publish failed: %v", err)
+	}
+	if want := "<b>7-Eleven *Store</b>: $12.50 &lt;snacks &amp; drinks&gt;"; req.ParseMode != "HTML" || req.Text != want {
+		t.Errorf("got %q in %q mode, want %q", req.Text, req.ParseMode, want)
+	}
+}
+
+func TestPublish_SplitsOnSections(t *testing.T) {
+	var texts []string
+	bot := newTestBot(t, func(w http.ResponseWriter, r *http.Request) {
+		var req SendMessageRequest
+		json.NewDecoder(r.Body).Decode(&req)
+		texts = append(texts, req.Text)
+		w.Write([]byte(`{"ok":true,"result":{}}`))
+	})
+
+	section := strings.TrimSuffix(strings.Repeat("**Dining-Out**: $1.00\n", 80), "\n")
+	message := strings.Join([]string{"Over budget\n" + section, "Upcoming\n" + section, "Savings\n" + section}, "\n\n")
+	if err := bot.Publish(message); err != nil {
+		t.Fatalf("Publish failed: %v", err)
+	}
+
+	if len(texts) < 2 {
+		t.Fatalf("expected the message to be split, got %d part(s)", len(texts))
+	}
+	var lines int
+	for i, text := range texts {
+		if len(text) > maxMessageLength {
+			t.Errorf("part %d: %d characters is over the limit", i+1, len(text))
+		}
+		if strings.HasPrefix(text, "\n") || strings.HasSuffix(text, "\n") {
+			t.Errorf("part %d: expected blank lines to be trimmed", i+1)
+		}
+		if !strings.HasSuffix(text, `*Dining\-Out*: $1\.00`) {
+			t.Errorf("part %d: expected to end on a section boundary, got tail %q", i+1, text[len(text)-20:])
+		}
+		lines += strings.Count(text, "Dining")
+	}
+	if lines != 240 {
+		t.Errorf("expected all 240 category lines to be sent, got %d", lines)
+	}
+}
+
+func TestPublish_SplitsLongSectionOnLines(t *testing.T) {
+	var texts []string
+	bot := newTestBot(t, func(w http.ResponseWriter, r *http.Request) {
+		var req SendMessageRequest
+		json.NewDecoder(r.Body).Decode(&req)
+		texts = append(texts, req.Text)
+		w.Write([]byte(`{"ok":true,"result":{}}`))
+	})
+
+	message := strings.TrimSuffix(strings.Repeat("**Groceries**: $1.00\n", 400), "\n") + "\n" + strings.Repeat("x", 5000)
+	if err := bot.Publish(message); err != nil {
+		t.Fatalf("Publish failed: %v", err)
+	}
+
+	for i, text := range texts {
+		if len(text) > maxMessageLength {
+			t.Errorf("part %d: %d characters is over the limit", i+1, len(text))
+		}
+	}
+	if last := texts[len(texts)-1]; !strings.HasSuffix(last, `xx\.\.\.`) {
+		t.Errorf("expected the overlong line to be cut with an ellipsis, got tail %q", last[len(last)-10:])
+	}
+}
+
+func TestPublish_SplitPartFails(t *testing.T) {
+	calls := 0
+	bot := newTestBot(t, func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if calls == 2 {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"ok":false,"error_code":400,"description":"Bad Request: can't parse entities"}`))
+			return
+		}
+		w.Write([]byte(`{"ok":true,"result":{}}`))
+	})
+
+	message := strings.Repeat("x", 3000) + "\n\n" + strings.Repeat("y", 3000) + "\n\n" + strings.Repeat("z", 3000)
+	err := bot.Publish(message)
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || !strings.HasPrefix(err.Error(), "part 2 of 3: ") {
+		t.Fatalf("expected part 2 to fail with an APIError, got %v", err)
+	}
+	if calls != 2 {
+		t.Errorf("expected sending to stop after the failed part, got %d calls", calls)
+	}
+}
+
+func TestPublish_SplitPartFailsNotRetriedWhole(t *testing.T) {
+	calls := 0
+	bot := newTestBot(t, func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if calls >= 2 {
+			w.WriteHeader(http.StatusInternalServerError)
+			w.Write([]byte(`{"ok":false,"error_code":500,"description":"Internal Server Error"}`))
+			return
+		}
+		w.Write([]byte(`{"ok":true,"result":{}}`))
+	})
+
+	message := strings.Repeat("x", 3000) + "\n\n" + strings.Repeat("y", 3000)
+	err := bot.Publish(message)
+	var partial *PartialSendError
+	if !errors.As(err, &partial) || partial.Part != 2 {
+		t.Fatalf("expected part 2 to fail, got %v", err)
+	}
+	var temp publisher.TemporaryError
+	if errors.As(err, &temp) && temp.Temporary() {
+		t.Errorf("expected a failure after the first part not to be temporary, got %v", err)
+	}
+}
+
+func TestPublishReport_SummaryCard(t *testing.T) {
+	var paths []string
+	var chatID, caption string
+	var photo []byte
+	bot := newTestBot(t, func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.URL.Path)
+		if strings.HasSuffix(r.URL.Path, "/sendPhoto") {
+			chatID, caption = r.FormValue("chat_id"), r.FormValue("caption")
+			if file, _, err := r.FormFile("photo"); err == nil {
+				photo, _ = io.ReadAll(file)
+			}
+		}
+		w.Write([]byte(`{"ok":true,"result":{}}`))
+	})
+	bot.config.SummaryCard = true
+
+	report := publisher.Report{
+		Title:   "Weekly Financial Wrap - 2026-06-01 to 2026-06-08",
+		Message: "**Total Spent**: $120",
+		Analysis: &processor.AnalysisResult{
+			Overview:  &processor.Overview{TotalSpent: 120_000, HealthPercentage: 40},
+			DateRange: "2026-06-01 to 2026-06-08",
+		},
+	}
+	if err := bot.PublishReport(report); err != nil {
+		t.Fatalf("PublishReport failed: %v", err)
+	}
+
+	if len(paths) != 2 || paths[0] != "/bottoken/sendMessage" || paths[1] != "/bottoken/sendPhoto" {
+		t.Fatalf("expected the text then the card, got %v", paths)
+	}
+	if chatID != "42" || caption != report.Title {
+		t.Errorf("chat_id %q caption %q", chatID, caption)
+	}
+	if !bytes.HasPrefix(photo, []byte("\x89PNG")) {
+		t.Errorf("expected a PNG upload, got %d bytes", len(photo))
+	}
+}
+
+func TestPublishReport_SummaryCardFailureKeepsReport(t *testing.T) {
+	bot := newTestBot(t, func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasSuffix(r.URL.Path, "/sendPhoto") {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"ok":false,"error_code":400,"description":"Bad Request: IMAGE_PROCESS_FAILED"}`))
+			return
+		}
+		w.Write([]byte(`{"ok":true,"result":{}}`))
+	})
+	bot.config.SummaryCard = true
+
+	report := publisher.Report{
+		Message:  "**Total Spent**: $120",
+		Analysis: &processor.AnalysisResult{Overview: &processor.Overview{TotalSpent: 120_000}},
+	}
+	if err := bot.PublishReport(report); err != nil {
+		t.Errorf("expected the delivered report to count as sent, got %v", err)
+	}
+}
+
+func TestPublishReport_Chart(t *testing.T) {
+	var paths []string
+	var caption string
+	bot := newTestBot(t, func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.URL.Path)
+		if strings.HasSuffix(r.URL.Path, "/sendPhoto") {
+			caption = r.FormValue("caption")
+		}
+		w.Write([]byte(`{"ok":true,"result":{}}`))
+	})
+	bot.config.Chart = true
+
+	report := publisher.Report{
+		Message: "**Total Spent**: $120",
+		Analysis: &processor.AnalysisResult{
+			Overview:    &processor.Overview{TotalSpent: 120_000},
+			TopSpending: []processor.TopSpendingCategory{{Category: "Groceries", Spent: 120_000}},
+			DateRange:   "2026-06-01 to 2026-06-08",
+		},
+	}
+	if err := bot.PublishReport(report); err != nil {
+		t.Fatalf("PublishReport failed: %v", err)
+	}
+
+	if len(paths) != 2 || paths[1] != "/bottoken/sendPhoto" {
+		t.Fatalf("expected the text then the chart, got %v", paths)
+	}
+	if caption != "Top spending: 2026-06-01 to 2026-06-08" {
+		t.Errorf("caption: got %q", caption)
+	}
+}
+
+func TestPublishReport_ChartSkippedWithoutSpending(t *testing.T) {
+	calls := 0
+	bot := newTestBot(t, func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.Write([]byte(`{"ok":true,"result":{}}`))
+	})
+	bot.config.Chart = true
+
+	report := publisher.Report{
+		Message:  "**Total Spent**: $0",
+		Analysis: &processor.AnalysisResult{Overview: &processor.Overview{}},
+	}
+	if err := bot.PublishReport(report); err != nil {
+		t.Fatalf("PublishReport failed: %v", err)
+	}
+	if calls != 1 {
+		t.Errorf("expected no chart for a week without spending, got %d calls", calls)
+	}
+}
+
+// fakeSpeaker records the script it was asked to voice
+type fakeSpeaker struct {
+	script string
+	err    error
+}
+
+func (f *fakeSpeaker) Synthesize(_ context.Context, text string) ([]byte, error) {
+	f.script = text
+	return []byte("OggS"), f.err
+}
+
+func TestPublishReport_VoiceSummary(t *testing.T) {
+	var paths []string
+	var voice []byte
+	bot := newTestBot(t, func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.URL.Path)
+		if file, _, err := r.FormFile("voice"); err == nil {
+			voice, _ = io.ReadAll(file)
+		}
+		w.Write([]byte(`{"ok":true,"result":{}}`))
+	})
+	speaker := &fakeSpeaker{}
+	WithSynthesizer(speaker)(bot)
+
+	report := publisher.Report{
+		Title:    "Weekly Financial Wrap - 2026-06-01 to 2026-06-08",
+		Message:  "**Total Spent**: $120",
+		Analysis: &processor.AnalysisResult{Overview: &processor.Overview{TotalSpent: 120_000}},
+	}
+	if err := bot.PublishReport(report); err != nil {
+		t.Fatalf("PublishReport failed: %v", err)
+	}
+
+	if len(paths) != 2 || paths[1] != "/bottoken/sendVoice" {
+		t.Fatalf("expected the text then the voice message, got %v", paths)
+	}
+	if string(voice) != "OggS" {
+		t.Errorf("expected the synthesized audio, got %q", voice)
+	}
+	if !strings.HasPrefix(speaker.script, "Weekly Financial Wrap. You spent 120 dollars") {
+		t.Errorf("unexpected script %q", speaker.script)
+	}
+}
+
+func TestPublishReport_VoiceSummaryFailureKeepsReport(t *testing.T) {
+	calls := 0
+	bot := newTestBot(t, func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.Write([]byte(`{"ok":true,"result":{}}`))
+	})
+	WithSynthesizer(&fakeSpeaker{err: errors.New("espeak-ng not found")})(bot)
+
+	report := publisher.Report{
+		Message:  "**Total Spent**: $120",
+		Analysis: &processor.AnalysisResult{Overview: &processor.Overview{TotalSpent: 120_000}},
+	}
+	if err := bot.PublishReport(report); err != nil {
+		t.Errorf("expected the delivered report to count as sent, got %v", err)
+	}
+	if calls != 1 {
+		t.Errorf("expected only the text to be sent, got %d calls", calls)
+	}
+}
+
+func TestPublishReport_TransactionsCSV(t *testing.T) {
+	var filename string
+	var document []byte
+	bot := newTestBot(t, func(w http.ResponseWriter, r *http.Request) {
+		if file, header, err := r.FormFile("document"); err == nil && strings.HasSuffix(r.URL.Path, "/sendDocument") {
+			filename = header.Filename
+			document, _ = io.ReadAll(file)
+		}
+		w.Write([]byte(`{"ok":true,"result":{}}`))
+	})
+	bot.config.AttachCSV = true
+
+	date := time.Date(2026, 6, 3, 0, 0, 0, 0, time.UTC)
+	report := publisher.Report{
+		Title:        "Weekly Financial Wrap - 2026-06-01 to 2026-06-08",
+		Message:      "**Total Spent**: $12.50",
+		Analysis:     &processor.AnalysisResult{DateRange: "2026-06-01 to 2026-06-08"},
+		Transactions: []ynab.Transaction{{Date: &date, PayeeName: "Corner Cafe", CategoryName: "Dining Out", Amount: -12_500}},
+	}
+	if err := bot.PublishReport(report); err != nil {
+		t.Fatalf("PublishReport failed: %v", err)
+	}
+
+	if filename != "transactions-2026-06-01-to-2026-06-08.csv" {
+		t.Errorf("filename: got %q", filename)
+	}
+	if !strings.Contains(string(document), "2026-06-03,Corner Cafe,Dining Out,-12.50,") {
+		t.Errorf("expected the transaction in the CSV, got %q", document)
+	}
+}
+
+func TestPublish_ThroughProxy(t *testing.T) {
+	var proxied string
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		proxied = r.URL.Host + r.URL.Path
+		w.Write([]byte(`{"ok":true,"result":{}}`))
+	}))
+	t.Cleanup(proxy.Close)
+
+	bot, err := NewBot(config.TelegramConfig{BotToken: "token", ChatID: 42, ProxyURL: proxy.URL})
+	if err != nil {
+		t.Fatalf("NewBot failed: %v", err)
+	}
+	bot.apiURL = "http://api.telegram.invalid"
+
+	if err := bot.Publish("hello"); err != nil {
+		t.Fatalf("Publish failed: %v", err)
+	}
+	if proxied != "api.telegram.invalid/bottoken/sendMessage" {
+		t.Errorf("expected the request to go through the proxy, got %q", proxied)
+	}
+}
+
+func TestNewBot_InvalidProxy(t *testing.T) {
+	for _, proxyURL := range []string{"ftp://proxy:21", "not a url", "socks5://"} {
+		if _, err := NewBot(config.TelegramConfig{BotToken: "token", ChatID: 42, ProxyURL: proxyURL}); err == nil {
+			t.Errorf("%q: expected error, got nil", proxyURL)
+		}
+	}
+}
+
+func TestName_MultipleChats(t *testing.T) {
+	single, _ := NewBot(config.TelegramConfig{ChatID: 42})
+	if single.Name() != "telegram" {
+		t.Errorf("single chat: got %q", single.Name())
+	}
+
+	chats := []config.TelegramChat{{ChatID: 42}, {ChatID: -100, TopicID: 3}, {ChatID: -100, TopicID: 7}}
+	multi, _ := NewBot(config.TelegramConfig{ChatID: 42, Chats: chats})
+	if multi.Name() != "telegram:42" {
+		t.Errorf("multiple chats: got %q", multi.Name())
+	}
+	topic, _ := NewBot(config.TelegramConfig{ChatID: -100, TopicID: 3, Chats: chats})
+	if topic.Name() != "telegram:-100/3" {
+		t.Errorf("chat topic: got %q", topic.Name())
+	}
+}
@@ -0,0 +1,276 @@
+package telegram
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sathyabhat/ynab-weekly-wrap/internal/oneoff"
+	"github.com/sathyabhat/ynab-weekly-wrap/internal/pause"
+	"github.com/sathyabhat/ynab-weekly-wrap/internal/publisher"
+	"github.com/sathyabhat/ynab-weekly-wrap/internal/render"
+)
+
+// handleCommand answers a command sent in the chat. Only /note, /schedule,
+// /pause, /resume and /coverage are understood; other messages are ignored.
+func (b *Bot) handleCommand(msg *Message) {
+	command, args, _ := strings.Cut(strings.TrimSpace(msg.Text), " ")
+	command, _, _ = strings.Cut(command, "@") // "/note@MyWrapBot" in groups
+
+	var reply string
+	switch {
+	case command == "/note" && b.notes != nil:
+		reply = b.noteCommand(args)
+	case command == "/schedule" && b.sends != nil:
+		reply = b.scheduleCommand(args, time.Now())
+	case command == "/pause" && b.pauses != nil:
+		reply = b.pauseCommand(args, time.Now())
+	case command == "/resume" && b.pauses != nil:
+		reply = b.resumeCommand(time.Now())
+	case command == "/coverage":
+		reply = b.latest.get()
+	default:
+		return
+	}
+
+	if _, err := b.sendMessage(context.Background(), render.Parse(reply), true, messageOptions{replyTo: msg.MessageID}); err != nil {
+		log.Printf("Warning: could not reply to %s: %v", command, err)
+	}
+}
+
+// noteCommand sets, clears or lists category notes and returns the reply:
+//
+//	/note Groceries switching to weekly meal prep
+//	/note Dining Out: cooking more this month
+//	/note Groceries        (clears the note)
+//	/note                  (lists the notes)
+func (b *Bot) noteCommand(args string) string {
+	category, note := parseNote(args)
+	if category == "" {
+		return b.listNotes()
+	}
+
+	if err := b.notes.Set(category, note); err != nil {
+		log.Printf("Warning: could not save note for %s: %v", category, err)
+		return "Sorry, the note couldn't be saved"
+	}
+	if note == "" {
+		return fmt.Sprintf("🗑️ Cleared the note for **%s**", category)
+	}
+	return fmt.Sprintf("📝 Noted for **%s**, shown in each wrap until cleared with /note %s", category, category)
+}
+
+// parseNote splits /note arguments into a category and note. A colon ends
+// the category, so names with spaces work; otherwise it is the first word.
+func parseNote(args string) (category, note string) {
+	args = strings.TrimSpace(args)
+	if before, after, ok := strings.Cut(args, ":"); ok {
+		return strings.TrimSpace(before), strings.TrimSpace(after)
+	}
+	category, note, _ = strings.Cut(args, " ")
+	return category, strings.TrimSpace(note)
+}
+
+func (b *Bot) listNotes() string {
+	all, err := b.notes.All()
+	if err != nil {
+		log.Printf("Warning: could not load notes: %v", err)
+		return "Sorry, the notes couldn't be loaded"
+	}
+	if len(all) == 0 {
+		return "No category notes. Add one with /note Groceries switching to weekly meal prep"
+	}
+
+	categories := make([]string, 0, len(all))
+	for category := range all {
+		categories = append(categories, category)
+	}
+	sort.Strings(categories)
+
+	lines := []string{"📝 **Category Notes**"}
+	for _, category := range categories {
+		lines = append(lines, fmt.Sprintf("• **%s**: %s", category, all[category]))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// scheduleCommand queues, cancels or lists one-off wraps and returns the
+// reply:
+//
+//	/schedule friday 6pm           (the weekly wrap)
+//	/schedule monthly 2026-10-30 18:00
+//	/schedule cancel 2
+//	/schedule                      (lists the queued wraps)
+func (b *Bot) scheduleCommand(args string, now time.Time) string {
+	args = strings.TrimSpace(args)
+	if args == "" {
+		return b.listSends()
+	}
+
+	if idStr, ok := strings.CutPrefix(args, "cancel "); ok {
+		id, err := strconv.Atoi(strings.TrimSpace(idStr))
+		if err != nil {
+			return "Usage: /schedule cancel 2, with the number from /schedule"
+		}
+		found, err := b.sends.Cancel(id)
+		if err != nil {
+			log.Printf("Warning: could not cancel scheduled send %d: %v", id, err)
+			return "Sorry, the send couldn't be cancelled"
+		}
+		if !found {
+			return fmt.Sprintf("No scheduled send #%d", id)
+		}
+		return fmt.Sprintf("🗑️ Cancelled scheduled send #%d", id)
+	}
+
+	period, when := "weekly", args
+	if first, rest, _ := strings.Cut(args, " "); first == "weekly" || first == "monthly" {
+		period, when = first, rest
+	}
+	at, err := oneoff.ParseWhen(when, now.In(b.sends.Location()))
+	if err != nil {
+		return "Sorry, " + err.Error()
+	}
+	send, err := b.sends.Add(at, period)
+	if err != nil {
+		log.Printf("Warning: could not schedule a %s wrap: %v", period, err)
+		return "Sorry, the send couldn't be scheduled"
+	}
+	return fmt.Sprintf("🗓️ The %s wrap will be sent on **%s** (#%d)", period, send.At.Format(sendTimeFormat), send.ID)
+}
+
+// sendTimeFormat shows when a queued wrap goes out, e.g. "Fri 23 Oct 18:00"
+const sendTimeFormat = "Mon 2 Jan 15:04"
+
+func (b *Bot) listSends() string {
+	pending, err := b.sends.Pending()
+	if err != nil {
+		log.Printf("Warning: could not load scheduled sends: %v", err)
+		return "Sorry, the scheduled sends couldn't be loaded"
+	}
+	if len(pending) == 0 {
+		return "No wraps scheduled. Queue one with /schedule friday 6pm"
+	}
+
+	lines := []string{"🗓️ **Scheduled Wraps**"}
+	for _, send := range pending {
+		lines = append(lines, fmt.Sprintf("• #%d %s wrap on **%s**", send.ID, send.Period, send.At.Format(sendTimeFormat)))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// pauseCommand pauses the scheduled sends, or says whether they are paused,
+// and returns the reply:
+//
+//	/pause 3w
+//	/pause 2026-11-01
+//	/pause              (whether sends are paused)
+func (b *Bot) pauseCommand(args string, now time.Time) string {
+	now = now.In(b.pauses.Location())
+	if strings.TrimSpace(args) == "" {
+		state, err := b.pauses.Current()
+		if err != nil {
+			log.Printf("Warning: could not load the pause: %v", err)
+			return "Sorry, the pause couldn't be loaded"
+		}
+		if !state.Active(now) {
+			return "Scheduled wraps are running. Pause them with /pause 3w"
+		}
+		return fmt.Sprintf("⏸️ Scheduled wraps are paused until **%s**. Resume them early with /resume", state.Until.Format(sendTimeFormat))
+	}
+
+	until, err := pause.ParseUntil(args, now)
+	if err != nil {
+		return "Sorry, " + err.Error()
+	}
+	state, err := b.pauses.Pause(now, until)
+	if err != nil {
+		log.Printf("Warning: could not pause the scheduled sends: %v", err)
+		return "Sorry, the scheduled wraps couldn't be paused"
+	}
+	return fmt.Sprintf("⏸️ Scheduled wraps are paused until **%s**, then a catch-up wrap covers the days missed. Resume early with /resume",
+		state.Until.Format(sendTimeFormat))
+}
+
+// resumeCommand ends the pause, leaving the scheduler to resume the sends
+// and catch up, and returns the reply
+func (b *Bot) resumeCommand(now time.Time) string {
+	state, err := b.pauses.Current()
+	if err != nil {
+		log.Printf("Warning: could not load the pause: %v", err)
+		return "Sorry, the pause couldn't be loaded"
+	}
+	if !state.Active(now) {
+		return "Scheduled wraps aren't paused"
+	}
+	if _, err := b.pauses.Resume(now); err != nil {
+		log.Printf("Warning: could not resume the scheduled sends: %v", err)
+		return "Sorry, the scheduled wraps couldn't be resumed"
+	}
+	return "▶️ Scheduled wraps resume within a minute, starting with a catch-up wrap of the days missed"
+}
+
+// maxCoverageUnapproved is how many unapproved transactions /coverage lists
+// before summing up the rest
+const maxCoverageUnapproved = 10
+
+// coverageStore keeps the /coverage reply for the last wrap sent, which the
+// poller reads while the scheduler publishes the next one
+type coverageStore struct {
+	mu   sync.Mutex
+	text string
+}
+
+func (c *coverageStore) set(text string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.text = text
+}
+
+func (c *coverageStore) get() string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.text == "" {
+		return "No wrap has been sent since the bot started. /coverage explains the data footer of the next one"
+	}
+	return c.text
+}
+
+// coverageDetail explains a wrap's data footer: the accounts that haven't
+// been updated recently and the transactions still waiting for approval
+func coverageDetail(report publisher.Report) string {
+	confidence, currency := report.Analysis.Confidence, report.Analysis.Currency
+	lines := []string{
+		"ℹ️ **Data Coverage** (" + report.Analysis.DateRange + ")",
+		fmt.Sprintf("%d of %d accounts updated in the last 48h (%.0f%%)",
+			confidence.UpdatedAccounts, confidence.ActiveAccounts, confidence.UpdatedPercent()),
+	}
+
+	if len(confidence.Stale) > 0 {
+		lines = append(lines, "", "**Not updated recently**")
+		for _, stale := range confidence.Stale {
+			lines = append(lines, fmt.Sprintf("• %s: last transaction %s", stale.Account, stale.LastTransaction.Format("Jan 2")))
+		}
+	}
+
+	if len(confidence.Unapproved) > 0 {
+		lines = append(lines, "", fmt.Sprintf("**%d unapproved**", len(confidence.Unapproved)))
+		for i, tx := range confidence.Unapproved {
+			if i == maxCoverageUnapproved {
+				lines = append(lines, fmt.Sprintf("...and %d more", len(confidence.Unapproved)-i))
+				break
+			}
+			date := ""
+			if tx.Date != nil {
+				date = tx.Date.Format("Jan 2") + " "
+			}
+			lines = append(lines, fmt.Sprintf("• %s%s: %s", date, tx.PayeeName, currency.Amount(-tx.Amount)))
+		}
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,170 @@
+package telegram
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sathyabhat/ynab-weekly-wrap/internal/notes"
+	"github.com/sathyabhat/ynab-weekly-wrap/internal/oneoff"
+	"github.com/sathyabhat/ynab-weekly-wrap/internal/pause"
+	"github.com/sathyabhat/ynab-weekly-wrap/internal/processor"
+	"github.com/sathyabhat/ynab-weekly-wrap/internal/ynab"
+)
+
+func TestParseNote(t *testing.T) {
+	cases := []struct {
+		args, category, note string
+	}{
+		{"Groceries switching to weekly meal prep", "Groceries", "switching to weekly meal prep"},
+		{"Dining Out: cooking more this month", "Dining Out", "cooking more this month"},
+		{" Groceries ", "Groceries", ""},
+		{"", "", ""},
+	}
+	for _, c := range cases {
+		category, note := parseNote(c.args)
+		if category != c.category || note != c.note {
+			t.Errorf("parseNote(%q): got %q, %q; want %q, %q", c.args, category, note, c.category, c.note)
+		}
+	}
+}
+
+func TestNoteCommand(t *testing.T) {
+	bot, calls := newRecordingBot(t, nil)
+	store := notes.NewStore(filepath.Join(t.TempDir(), "notes.json"), nil)
+	WithNotes(store)(bot)
+	poller := NewPoller(bot)
+
+	poller.dispatch(Update{Message: &Message{MessageID: 7, Chat: Chat{ID: 42}, Text: "/note@WrapBot Groceries switching to weekly meal prep"}})
+
+	if all, _ := store.All(); all["Groceries"] != "switching to weekly meal prep" {
+		t.Errorf("expected the note to be saved, got %v", all)
+	}
+	if len(*calls) != 1 || (*calls)[0].body["reply_to_message_id"] != float64(7) {
+		t.Fatalf("expected a reply to the command, got %+v", *calls)
+	}
+
+	poller.dispatch(Update{Message: &Message{MessageID: 8, Chat: Chat{ID: 42}, Text: "/note"}})
+	if text := (*calls)[1].body["text"].(string); !strings.Contains(text, "*Groceries*: switching to weekly meal prep") {
+		t.Errorf("expected the notes to be listed, got %q", text)
+	}
+
+	poller.dispatch(Update{Message: &Message{MessageID: 9, Chat: Chat{ID: 42}, Text: "/note groceries"}})
+	if all, _ := store.All(); len(all) != 0 {
+		t.Errorf("expected the note to be cleared, got %v", all)
+	}
+}
+
+func TestNoteCommand_IgnoresOtherChatsAndMessages(t *testing.T) {
+	bot, calls := newRecordingBot(t, nil)
+	store := notes.NewStore(filepath.Join(t.TempDir(), "notes.json"), nil)
+	WithNotes(store)(bot)
+	poller := NewPoller(bot)
+
+	poller.dispatch(Update{Message: &Message{Chat: Chat{ID: -7}, Text: "/note Groceries from a stranger"}})
+	poller.dispatch(Update{Message: &Message{Chat: Chat{ID: 42}, Text: "nice wrap this week"}})
+
+	if all, _ := store.All(); len(all) != 0 {
+		t.Errorf("expected no notes, got %v", all)
+	}
+	if len(*calls) != 0 {
+		t.Errorf("expected no replies, got %+v", *calls)
+	}
+}
+
+func TestScheduleCommand(t *testing.T) {
+	bot, calls := newRecordingBot(t, nil)
+	store := oneoff.NewStore(filepath.Join(t.TempDir(), "once.json"), time.UTC)
+	WithSends(store)(bot)
+	now := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+
+	reply := bot.scheduleCommand("monthly friday 6pm", now)
+	if reply != "🗓️ The monthly wrap will be sent on **Fri 16 Oct 18:00** (#1)" {
+		t.Errorf("unexpected reply %q", reply)
+	}
+	if pending, _ := store.Pending(); len(pending) != 1 || pending[0].Period != "monthly" {
+		t.Fatalf("expected a monthly send to be queued, got %+v", pending)
+	}
+
+	if reply := bot.scheduleCommand("", now); !strings.Contains(reply, "#1 monthly wrap on **Fri 16 Oct 18:00**") {
+		t.Errorf("expected the send to be listed, got %q", reply)
+	}
+	if reply := bot.scheduleCommand("someday", now); !strings.HasPrefix(reply, "Sorry, can't read") {
+		t.Errorf("expected an unreadable time to be explained, got %q", reply)
+	}
+	if reply := bot.scheduleCommand("cancel 1", now); reply != "🗑️ Cancelled scheduled send #1" {
+		t.Errorf("unexpected reply %q", reply)
+	}
+
+	NewPoller(bot).dispatch(Update{Message: &Message{MessageID: 7, Chat: Chat{ID: 42}, Text: "/schedule@WrapBot tomorrow 9am"}})
+	if pending, _ := store.Pending(); len(pending) != 1 || pending[0].Period != "weekly" {
+		t.Errorf("expected a weekly send to be queued from chat, got %+v", pending)
+	}
+	if len(*calls) != 1 || (*calls)[0].body["reply_to_message_id"] != float64(7) {
+		t.Errorf("expected a reply to the command, got %+v", *calls)
+	}
+}
+
+func TestPauseCommand(t *testing.T) {
+	bot, calls := newRecordingBot(t, nil)
+	store := pause.NewStore(filepath.Join(t.TempDir(), "pause.json"), time.UTC)
+	WithPauses(store)(bot)
+	now := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+
+	if reply := bot.pauseCommand("", now); !strings.HasPrefix(reply, "Scheduled wraps are running") {
+		t.Errorf("expected the sends to be running, got %q", reply)
+	}
+	if reply := bot.pauseCommand("a while", now); !strings.HasPrefix(reply, "Sorry, can't read") {
+		t.Errorf("expected an unreadable pause to be explained, got %q", reply)
+	}
+	if reply := bot.pauseCommand("3w", now); !strings.Contains(reply, "paused until **Fri 6 Nov 12:00**") {
+		t.Errorf("unexpected reply %q", reply)
+	}
+	if state, _ := store.Current(); !state.Active(now.AddDate(0, 0, 20)) {
+		t.Fatalf("expected the sends to be paused for three weeks, got %+v", state)
+	}
+	if reply := bot.resumeCommand(now.Add(time.Hour)); !strings.HasPrefix(reply, "▶️ Scheduled wraps resume") {
+		t.Errorf("unexpected reply %q", reply)
+	}
+	if state, _ := store.Current(); state.Active(now.Add(2 * time.Hour)) {
+		t.Errorf("expected the pause to have ended, got %+v", state)
+	}
+
+	NewPoller(bot).dispatch(Update{Message: &Message{MessageID: 7, Chat: Chat{ID: 42}, Text: "/resume@WrapBot"}})
+	if len(*calls) != 1 || (*calls)[0].body["text"] != "Scheduled wraps aren't paused" {
+		t.Errorf("expected a reply that nothing is paused, got %+v", *calls)
+	}
+}
+
+func TestCoverageCommand(t *testing.T) {
+	bot, calls := newRecordingBot(t, nil)
+	bot.config.Compact = false
+	poller := NewPoller(bot)
+
+	poller.dispatch(Update{Message: &Message{MessageID: 7, Chat: Chat{ID: 42}, Text: "/coverage"}})
+	if len(*calls) != 1 || !strings.HasPrefix((*calls)[0].body["text"].(string), "No wrap has been sent") {
+		t.Fatalf("expected a reply that nothing has been sent yet, got %+v", *calls)
+	}
+
+	date := time.Date(2026, 6, 6, 0, 0, 0, 0, time.UTC)
+	report := compactReport()
+	report.Analysis.DateRange = "2026-06-01 to 2026-06-08"
+	report.Analysis.Confidence = &processor.DataConfidence{
+		ActiveAccounts:  3,
+		UpdatedAccounts: 2,
+		Stale:           []processor.StaleAccount{{Account: "Savings", LastTransaction: date}},
+		Unapproved:      []ynab.Transaction{{PayeeName: "Bistro", Amount: -42_000, Date: &date}},
+	}
+	if err := bot.PublishReport(report); err != nil {
+		t.Fatalf("PublishReport failed: %v", err)
+	}
+
+	poller.dispatch(Update{Message: &Message{MessageID: 8, Chat: Chat{ID: 42}, Text: "/coverage"}})
+	text := (*calls)[len(*calls)-1].body["text"].(string)
+	for _, want := range []string{"*Data Coverage*", "2 of 3 accounts updated in the last 48h \\(67%\\)", "Savings: last transaction Jun 6", "Jun 6 Bistro: $42"} {
+		if !strings.Contains(text, want) {
+			t.Errorf("expected %q in the reply, got %q", want, text)
+		}
+	}
+}
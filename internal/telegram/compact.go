@@ -0,0 +1,182 @@
+package telegram
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/sathyabhat/ynab-weekly-wrap/internal/publisher"
+	"github.com/sathyabhat/ynab-weekly-wrap/internal/render"
+)
+
+// InlineKeyboardMarkup is a row-by-row set of buttons shown under a message
+type InlineKeyboardMarkup struct {
+	InlineKeyboard [][]InlineKeyboardButton `json:"inline_keyboard"`
+}
+
+// InlineKeyboardButton sends its CallbackData back to the bot when tapped
+type InlineKeyboardButton struct {
+	Text         string `json:"text"`
+	CallbackData string `json:"callback_data"`
+}
+
+// Detail sections a compact summary's buttons expand into
+const (
+	detailCategories   = "categories"
+	detailTransactions = "transactions"
+)
+
+// maxStoredReports is how many recent reports keep working buttons
+const maxStoredReports = 20
+
+// detailStore keeps the detail sections of recently sent compact summaries
+// in memory, so buttons keep working until the process restarts
+type detailStore struct {
+	mu      sync.Mutex
+	next    int
+	order   []string
+	reports map[string]map[string]*render.Document
+}
+
+func newDetailStore() *detailStore {
+	return &detailStore{reports: make(map[string]map[string]*render.Document)}
+}
+
+// add stores a report's sections and returns the ID its buttons refer to
+func (d *detailStore) add(sections map[string]*render.Document) string {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.next++
+	id := strconv.Itoa(d.next)
+	d.reports[id] = sections
+	d.order = append(d.order, id)
+	if len(d.order) > maxStoredReports {
+		delete(d.reports, d.order[0])
+		d.order = d.order[1:]
+	}
+	return id
+}
+
+func (d *detailStore) get(id, section string) *render.Document {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.reports[id][section]
+}
+
+// publishCompact sends a short summary with buttons that expand into the
+// full category breakdown and the transactions behind over-budget categories
+func (b *Bot) publishCompact(ctx context.Context, report publisher.Report) (int, error) {
+	sections := map[string]*render.Document{detailCategories: report.Doc()}
+	if transactions := transactionsDetail(report); transactions != nil {
+		sections[detailTransactions] = transactions
+	}
+	id := b.details.add(sections)
+
+	row := []InlineKeyboardButton{}
+	if sections[detailTransactions] != nil {
+		row = append(row, InlineKeyboardButton{Text: "Show transactions", CallbackData: "details:" + id + ":" + detailTransactions})
+	}
+	row = append(row, InlineKeyboardButton{Text: "Show all categories", CallbackData: "details:" + id + ":" + detailCategories})
+
+	keyboard := &InlineKeyboardMarkup{InlineKeyboard: [][]InlineKeyboardButton{row}}
+	return b.sendMessage(ctx, compactSummary(report), true, messageOptions{keyboard: keyboard})
+}
+
+// compactSummary is the headline figures of a report
+func compactSummary(report publisher.Report) *render.Document {
+	analysis := report.Analysis
+	lines := []render.Line{
+		{{Text: "📊 "}, {Text: report.Title, Bold: true}},
+		nil,
+	}
+	if safe := analysis.SafeToSpend; safe != nil {
+		lines = append(lines, render.Line{
+			{Text: "🛒 "},
+			{Text: fmt.Sprintf("Safe to spend through %s: %s", safe.Through.Format("Monday"), analysis.Currency.Amount(safe.Amount)), Bold: true},
+		})
+	}
+	if overview := analysis.Overview; overview != nil {
+		lines = append(lines, render.Line{{Text: "💰 "}, {Text: "Total Spent", Bold: true}, {Text: ": " + analysis.Currency.Amount(overview.TotalSpent)}})
+		if !analysis.NoBudget {
+			lines = append(lines, render.Line{{Text: fmt.Sprintf("📈 %.0f%% of the month's budget used", overview.HealthPercentage)}})
+		}
+	}
+	if analysis.NoBudget {
+		return &render.Document{Lines: lines}
+	}
+	switch over := len(analysis.Concerns); over {
+	case 0:
+		lines = append(lines, render.Line{{Text: "✅ No categories over budget"}})
+	case 1:
+		lines = append(lines, render.Line{{Text: "⚠️ 1 category over budget"}})
+	default:
+		lines = append(lines, render.Line{{Text: fmt.Sprintf("⚠️ %d categories over budget", over)}})
+	}
+	return &render.Document{Lines: lines}
+}
+
+// transactionsDetail lists the transactions in each over-budget category, or
+// returns nil when there are none
+func transactionsDetail(report publisher.Report) *render.Document {
+	var lines []render.Line
+	for _, concern := range report.Analysis.Concerns {
+		if len(concern.Transactions) == 0 {
+			continue
+		}
+		if len(lines) > 0 {
+			lines = append(lines, nil)
+		}
+		lines = append(lines, render.Line{{Text: concern.Category, Bold: true}, {Text: fmt.Sprintf(" (%s over)", report.Analysis.Currency.Amount(concern.Over))}})
+		for _, tx := range concern.Transactions {
+			date := ""
+			if tx.Date != nil {
+				date = tx.Date.Format("Jan 2") + " "
+			}
+			lines = append(lines, render.Line{{Text: fmt.Sprintf("• %s%s: %s", date, tx.PayeeName, report.Analysis.Currency.Amount(-tx.Amount))}})
+		}
+	}
+	if lines == nil {
+		return nil
+	}
+	return &render.Document{Lines: append([]render.Line{{{Text: "🧾 "}, {Text: "Over-budget transactions", Bold: true}}, nil}, lines...)}
+}
+
+// handleCallback answers a tapped button by sending the section it asks for
+// as a reply to the summary
+func (b *Bot) handleCallback(query *CallbackQuery) {
+	id, section, ok := parseDetailsData(query.Data)
+	doc := b.details.get(id, section)
+
+	notice := ""
+	if !ok || doc == nil {
+		notice = "These details are no longer available"
+	}
+	if err := b.answerCallback(query.ID, notice); err != nil {
+		log.Printf("Warning: could not answer callback query: %v", err)
+	}
+	if doc == nil {
+		return
+	}
+
+	opts := messageOptions{}
+	if query.Message != nil {
+		opts.replyTo = query.Message.MessageID
+	}
+	if _, err := b.sendMessage(context.Background(), doc, true, opts); err != nil {
+		log.Printf("Failed to send %s details: %v", section, err)
+	}
+}
+
+// parseDetailsData splits callback data of the form details:<id>:<section>
+func parseDetailsData(data string) (id, section string, ok bool) {
+	rest, ok := strings.CutPrefix(data, "details:")
+	if !ok {
+		return "", "", false
+	}
+	id, section, ok = strings.Cut(rest, ":")
+	return id, section, ok
+}
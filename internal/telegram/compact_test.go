@@ -0,0 +1,152 @@
+package telegram
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sathyabhat/ynab-weekly-wrap/internal/processor"
+	"github.com/sathyabhat/ynab-weekly-wrap/internal/publisher"
+	"github.com/sathyabhat/ynab-weekly-wrap/internal/ynab"
+)
+
+// apiCall is one request the test server received
+type apiCall struct {
+	method string
+	body   map[string]any
+}
+
+func newRecordingBot(t *testing.T, respond func(method string) string) (*Bot, *[]apiCall) {
+	t.Helper()
+	var calls []apiCall
+	bot := newTestBot(t, func(w http.ResponseWriter, r *http.Request) {
+		method := r.URL.Path[strings.LastIndex(r.URL.Path, "/")+1:]
+		var body map[string]any
+		json.NewDecoder(r.Body).Decode(&body)
+		calls = append(calls, apiCall{method: method, body: body})
+		if respond != nil {
+			if resp := respond(method); resp != "" {
+				w.Write([]byte(resp))
+				return
+			}
+		}
+		w.Write([]byte(`{"ok":true,"result":{}}`))
+	})
+	bot.config.Compact = true
+	return bot, &calls
+}
+
+func compactReport() publisher.Report {
+	date := time.Date(2026, 6, 3, 0, 0, 0, 0, time.UTC)
+	return publisher.Report{
+		Title:   "Weekly Financial Wrap - 2026-06-01 to 2026-06-08",
+		Message: "📊 **Weekly Financial Wrap**\n\n🏆 **Top Categories**\n• **Dining Out**: $80",
+		Analysis: &processor.AnalysisResult{
+			Overview: &processor.Overview{TotalSpent: 230_000, HealthPercentage: 64},
+			Concerns: []processor.CategoryConcernWithTransactions{{
+				Category:     "Dining Out",
+				Over:         30_000,
+				Transactions: []ynab.Transaction{{Date: &date, PayeeName: "7-Eleven", Amount: -12_500}},
+			}},
+		},
+	}
+}
+
+func TestPublishReport_Compact(t *testing.T) {
+	bot, calls := newRecordingBot(t, nil)
+
+	if err := bot.PublishReport(compactReport()); err != nil {
+		t.Fatalf("PublishReport failed: %v", err)
+	}
+	if len(*calls) != 1 {
+		t.Fatalf("expected one message, got %d", len(*calls))
+	}
+
+	body := (*calls)[0].body
+	text := body["text"].(string)
+	if !strings.Contains(text, "*Total Spent*: $230") || !strings.Contains(text, "1 category over budget") {
+		t.Errorf("expected the headline figures, got %q", text)
+	}
+	if strings.Contains(text, "Top Categories") {
+		t.Errorf("expected the breakdown to be left for the buttons, got %q", text)
+	}
+
+	markup, _ := json.Marshal(body["reply_markup"])
+	for _, want := range []string{`"Show transactions"`, `"details:1:transactions"`, `"Show all categories"`, `"details:1:categories"`} {
+		if !strings.Contains(string(markup), want) {
+			t.Errorf("expected %s in the keyboard, got %s", want, markup)
+		}
+	}
+}
+
+func TestHandleCallback_SendsDetails(t *testing.T) {
+	bot, calls := newRecordingBot(t, nil)
+	bot.PublishReport(compactReport())
+
+	bot.handleCallback(&CallbackQuery{ID: "q1", Data: "details:1:transactions", Message: &Message{MessageID: 99, Chat: Chat{ID: 42}}})
+
+	if len(*calls) != 3 || (*calls)[1].method != "answerCallbackQuery" || (*calls)[2].method != "sendMessage" {
+		t.Fatalf("expected the tap to be answered and the details sent, got %+v", *calls)
+	}
+	reply := (*calls)[2].body
+	if reply["reply_to_message_id"] != float64(99) {
+		t.Errorf("expected a reply to the summary, got %v", reply["reply_to_message_id"])
+	}
+	if text := reply["text"].(string); !strings.Contains(text, `Jun 3 7\-Eleven: $12\.5`) {
+		t.Errorf("expected the over-budget transactions, got %q", text)
+	}
+}
+
+func TestHandleCallback_Expired(t *testing.T) {
+	bot, calls := newRecordingBot(t, nil)
+
+	bot.handleCallback(&CallbackQuery{ID: "q1", Data: "details:7:categories", Message: &Message{MessageID: 99}})
+
+	if len(*calls) != 1 || (*calls)[0].method != "answerCallbackQuery" {
+		t.Fatalf("expected only an answer, got %+v", *calls)
+	}
+	if (*calls)[0].body["text"] != "These details are no longer available" {
+		t.Errorf("expected an expiry notice, got %v", (*calls)[0].body["text"])
+	}
+}
+
+func TestPoller_DispatchesToChat(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	polls := 0
+	bot, calls := newRecordingBot(t, func(method string) string {
+		if method != "getUpdates" {
+			return ""
+		}
+		polls++
+		if polls > 1 {
+			cancel()
+			return `{"ok":true,"result":[]}`
+		}
+		return `{"ok":true,"result":[
+			{"update_id":10,"callback_query":{"id":"q1","data":"details:1:categories","message":{"message_id":5,"chat":{"id":42}}}},
+			{"update_id":11,"callback_query":{"id":"q2","data":"details:1:categories","message":{"message_id":6,"chat":{"id":-7}}}}
+		]}`
+	})
+	bot.PublishReport(compactReport())
+
+	poller := NewPoller(bot)
+	poller.Run(ctx)
+
+	var answered []string
+	for _, call := range *calls {
+		if call.method == "answerCallbackQuery" {
+			answered = append(answered, call.body["callback_query_id"].(string))
+		}
+	}
+	if len(answered) != 1 || answered[0] != "q1" {
+		t.Errorf("expected only the tap in this bot's chat to be handled, got %v", answered)
+	}
+	if poller.offset != 12 {
+		t.Errorf("offset: got %d, want 12", poller.offset)
+	}
+}
@@ -0,0 +1,94 @@
+package telegram
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrTelegramRateLimited is returned when Telegram rejects a request with
+// 429 Too Many Requests
+var ErrTelegramRateLimited = errors.New("telegram rate limit exceeded")
+
+// APIError is an error response from the Telegram Bot API
+type APIError struct {
+	Code        int
+	Description string
+	RetryAfter  time.Duration // Set by Telegram when rate limited
+}
+
+func (e *APIError) Error() string {
+	if e.RetryAfter > 0 {
+		return fmt.Sprintf("telegram API error %d: %s (retry after %s)", e.Code, e.Description, e.RetryAfter)
+	}
+	return fmt.Sprintf("telegram API error %d: %s", e.Code, e.Description)
+}
+
+// Temporary reports whether the request may succeed if retried: rate limits
+// and server-side errors
+func (e *APIError) Temporary() bool {
+	return e.Code == 429 || e.Code >= 500
+}
+
+// Backoff returns the delay Telegram asked for before retrying
+func (e *APIError) Backoff() time.Duration {
+	return e.RetryAfter
+}
+
+// Is reports rate limit responses as ErrTelegramRateLimited
+func (e *APIError) Is(target error) bool {
+	return target == ErrTelegramRateLimited && e.Code == 429
+}
+
+// RetryError is returned when a send still fails after the bot's own
+// retries. It is never temporary, so the send isn't retried all over again
+// on top of them.
+type RetryError struct {
+	Attempts int // Attempts made before giving up
+	Err      error
+}
+
+func (e *RetryError) Error() string {
+	return fmt.Sprintf("gave up after %d attempt(s): %v", e.Attempts, e.Err)
+}
+
+func (e *RetryError) Unwrap() error {
+	return e.Err
+}
+
+// Temporary reports false, since the bot has already retried
+func (e *RetryError) Temporary() bool {
+	return false
+}
+
+// Backoff returns zero; the send isn't retried again
+func (e *RetryError) Backoff() time.Duration {
+	return 0
+}
+
+// PartialSendError is returned when a message split into parts fails after
+// its first parts went out. It is never temporary, whatever the cause: a
+// retry of the whole message would send those parts to the chat again.
+type PartialSendError struct {
+	Part  int // The part that failed, counting from 1
+	Parts int
+	Err   error
+}
+
+func (e *PartialSendError) Error() string {
+	return fmt.Sprintf("part %d of %d: %v", e.Part, e.Parts, e.Err)
+}
+
+func (e *PartialSendError) Unwrap() error {
+	return e.Err
+}
+
+// Temporary reports false, so the message isn't resent from the start
+func (e *PartialSendError) Temporary() bool {
+	return false
+}
+
+// Backoff returns zero; a partial send isn't retried
+func (e *PartialSendError) Backoff() time.Duration {
+	return 0
+}
@@ -0,0 +1,118 @@
+package telegram
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"log"
+	"mime/multipart"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/sathyabhat/ynab-weekly-wrap/internal/card"
+	"github.com/sathyabhat/ynab-weekly-wrap/internal/publisher"
+	"github.com/sathyabhat/ynab-weekly-wrap/internal/render"
+	"github.com/sathyabhat/ynab-weekly-wrap/internal/speech"
+)
+
+// speechTimeout bounds how long the spoken summary may take to generate
+const speechTimeout = 2 * time.Minute
+
+// SendPhoto sends a PNG image to the chat with a plain-text caption
+func (b *Bot) SendPhoto(image []byte, filename, caption string) error {
+	return b.sendFile(context.Background(), "sendPhoto", "photo", filename, image, caption)
+}
+
+// SendVoice sends OGG/Opus audio as a voice message
+func (b *Bot) SendVoice(audio []byte, filename, caption string) error {
+	return b.sendFile(context.Background(), "sendVoice", "voice", filename, audio, caption)
+}
+
+// SendDocument sends a file for download
+func (b *Bot) SendDocument(data []byte, filename, caption string) error {
+	return b.sendFile(context.Background(), "sendDocument", "document", filename, data, caption)
+}
+
+// sendSummaryCard sends the report's overview as an image card
+func (b *Bot) sendSummaryCard(ctx context.Context, report publisher.Report) error {
+	heading, _, _ := strings.Cut(report.Title, " - ")
+	image, err := card.Render(heading, report.Analysis)
+	if err != nil {
+		return err
+	}
+	return b.sendFile(ctx, "sendPhoto", "photo", "summary.png", image, report.Title)
+}
+
+// sendChart sends a bar chart of the report's top spending categories
+func (b *Bot) sendChart(ctx context.Context, report publisher.Report) error {
+	image, err := card.RenderChart(report.Analysis)
+	if err != nil {
+		return err
+	}
+	return b.sendFile(ctx, "sendPhoto", "photo", "chart.png", image, "Top spending: "+report.Analysis.DateRange)
+}
+
+// sendVoiceSummary reads out a short version of the report as a voice
+// message, for listening on the commute
+func (b *Bot) sendVoiceSummary(ctx context.Context, report publisher.Report) error {
+	heading, _, _ := strings.Cut(report.Title, " - ")
+	speechCtx, cancel := context.WithTimeout(ctx, speechTimeout)
+	defer cancel()
+
+	audio, err := b.speaker.Synthesize(speechCtx, speech.Script(heading, report.Analysis))
+	if err != nil {
+		return err
+	}
+	return b.sendFile(ctx, "sendVoice", "voice", "summary.ogg", audio, report.Title)
+}
+
+// sendTransactionsCSV attaches the period's transactions as a CSV file that
+// opens in a spreadsheet
+func (b *Bot) sendTransactionsCSV(ctx context.Context, report publisher.Report) error {
+	data, err := render.TransactionsCSV(report.Transactions)
+	if err != nil {
+		return err
+	}
+	filename := "transactions.csv"
+	if report.Analysis != nil && report.Analysis.DateRange != "" {
+		filename = "transactions-" + strings.ReplaceAll(report.Analysis.DateRange, " ", "-") + ".csv"
+	}
+	return b.sendFile(ctx, "sendDocument", "document", filename, data, report.Title)
+}
+
+// sendFile uploads data as a multipart form to a Bot API method such as
+// sendPhoto, retrying like text messages do
+func (b *Bot) sendFile(ctx context.Context, method, field, filename string, data []byte, caption string) error {
+	err := b.withRetry(ctx, func() error {
+		var body bytes.Buffer
+		form := multipart.NewWriter(&body)
+		form.WriteField("chat_id", strconv.FormatInt(b.config.ChatID, 10))
+		if b.config.TopicID > 0 {
+			form.WriteField("message_thread_id", strconv.Itoa(b.config.TopicID))
+		}
+		if caption != "" {
+			form.WriteField("caption", caption)
+		}
+		if b.config.DisableNotification {
+			form.WriteField("disable_notification", "true")
+		}
+		part, err := form.CreateFormFile(field, filename)
+		if err != nil {
+			return fmt.Errorf("failed to build %s request: %w", method, err)
+		}
+		part.Write(data)
+		if err := form.Close(); err != nil {
+			return fmt.Errorf("failed to build %s request: %w", method, err)
+		}
+
+		_, err = b.call(method, form.FormDataContentType(), &body)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	log.Printf("Sent %s (%d bytes) to chat ID: %d", filename, len(data), b.config.ChatID)
+	return nil
+}
@@ -0,0 +1,68 @@
+package telegram
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+)
+
+// replacePin pins the wrap's message and unpins the wrap pinned before it,
+// leaving pins from anyone else alone. After a restart the previous wrap is
+// found from the chat's pinned message.
+func (b *Bot) replacePin(messageID int) error {
+	previous := b.pinned
+	if previous == 0 {
+		previous = b.pinnedByBot()
+	}
+
+	_, err := b.callJSON("pinChatMessage", map[string]any{
+		"chat_id":              b.config.ChatID,
+		"message_id":           messageID,
+		"disable_notification": b.config.DisableNotification,
+	})
+	if err != nil {
+		return err
+	}
+	b.pinned = messageID
+
+	if previous != 0 && previous != messageID {
+		_, err := b.callJSON("unpinChatMessage", map[string]any{
+			"chat_id":    b.config.ChatID,
+			"message_id": previous,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to unpin the previous wrap: %w", err)
+		}
+	}
+	return nil
+}
+
+// pinnedByBot returns the chat's most recent pinned message if this bot sent
+// it, or 0
+func (b *Bot) pinnedByBot() int {
+	result, err := b.callJSON("getChat", map[string]any{"chat_id": b.config.ChatID})
+	if err != nil {
+		log.Printf("Warning: could not look up the pinned message: %v", err)
+		return 0
+	}
+
+	var chat struct {
+		PinnedMessage *Message `json:"pinned_message"`
+	}
+	if err := json.Unmarshal(result, &chat); err != nil || chat.PinnedMessage == nil || chat.PinnedMessage.From == nil {
+		return 0
+	}
+	if chat.PinnedMessage.From.ID != b.botID() {
+		return 0
+	}
+	return chat.PinnedMessage.MessageID
+}
+
+// botID returns the bot's user ID, the part of the token before the colon
+func (b *Bot) botID() int64 {
+	idStr, _, _ := strings.Cut(b.config.BotToken, ":")
+	id, _ := strconv.ParseInt(idStr, 10, 64)
+	return id
+}
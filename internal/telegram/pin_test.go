@@ -0,0 +1,76 @@
+package telegram
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/sathyabhat/ynab-weekly-wrap/internal/publisher"
+)
+
+func newPinningBot(t *testing.T, pinnedFrom int64) (*Bot, *[]apiCall) {
+	t.Helper()
+	messageID := 50
+	bot, calls := newRecordingBot(t, func(method string) string {
+		switch method {
+		case "sendMessage":
+			messageID++
+			return fmt.Sprintf(`{"ok":true,"result":{"message_id":%d}}`, messageID)
+		case "getChat":
+			return fmt.Sprintf(`{"ok":true,"result":{"id":42,"pinned_message":{"message_id":40,"from":{"id":%d}}}}`, pinnedFrom)
+		}
+		return ""
+	})
+	bot.config.BotToken = "123:secret"
+	bot.config.Compact = false
+	bot.config.PinMessage = true
+	bot.config.DisableNotification = true
+	return bot, calls
+}
+
+func methods(calls []apiCall) []string {
+	var names []string
+	for _, call := range calls {
+		names = append(names, call.method)
+	}
+	return names
+}
+
+func TestPublishReport_PinReplacesPreviousWrap(t *testing.T) {
+	bot, calls := newPinningBot(t, 123)
+	report := publisher.Report{Message: "**Total Spent**: $120"}
+
+	if err := bot.PublishReport(report); err != nil {
+		t.Fatalf("PublishReport failed: %v", err)
+	}
+	want := []string{"sendMessage", "getChat", "pinChatMessage", "unpinChatMessage"}
+	if got := methods(*calls); fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Fatalf("calls: got %v, want %v", got, want)
+	}
+	if (*calls)[0].body["disable_notification"] != true || (*calls)[2].body["disable_notification"] != true {
+		t.Errorf("expected the wrap and its pin to be silent")
+	}
+	if (*calls)[2].body["message_id"] != float64(51) || (*calls)[3].body["message_id"] != float64(40) {
+		t.Errorf("expected to pin 51 and unpin 40, got %v and %v", (*calls)[2].body["message_id"], (*calls)[3].body["message_id"])
+	}
+
+	// The next wrap replaces this one without asking Telegram again
+	*calls = nil
+	bot.PublishReport(report)
+	if got := methods(*calls); fmt.Sprint(got) != "[sendMessage pinChatMessage unpinChatMessage]" {
+		t.Fatalf("calls: got %v", got)
+	}
+	if (*calls)[2].body["message_id"] != float64(51) {
+		t.Errorf("expected the previous wrap to be unpinned, got %v", (*calls)[2].body["message_id"])
+	}
+}
+
+func TestPublishReport_PinKeepsOtherPins(t *testing.T) {
+	bot, calls := newPinningBot(t, 999)
+
+	if err := bot.PublishReport(publisher.Report{Message: "**Total Spent**: $120"}); err != nil {
+		t.Fatalf("PublishReport failed: %v", err)
+	}
+	if got := methods(*calls); fmt.Sprint(got) != "[sendMessage getChat pinChatMessage]" {
+		t.Errorf("expected someone else's pin to be left alone, got %v", got)
+	}
+}
@@ -0,0 +1,112 @@
+package telegram
+
+import (
+	"sort"
+
+	"github.com/sathyabhat/ynab-weekly-wrap/internal/render"
+)
+
+// split breaks the document into chunks that each fit in one message. It
+// breaks on the blank lines between sections where it can, falls back to
+// line breaks for a section that is too long on its own, and only cuts a
+// line that can't fit in a message by itself.
+func (b *Bot) split(doc *render.Document) []*render.Document {
+	var chunks []*render.Document
+	var current []render.Line
+	flush := func() {
+		if lines := trimBlankLines(current); len(lines) > 0 {
+			chunks = append(chunks, &render.Document{Lines: lines})
+		}
+		current = nil
+	}
+
+	for _, section := range sections(doc.Lines) {
+		if b.fits(append(current[:len(current):len(current)], section...)) {
+			current = append(current, section...)
+			continue
+		}
+		flush()
+		if b.fits(section) {
+			current = append(current, section...)
+			continue
+		}
+		for _, line := range section {
+			if !b.fits(append(current[:len(current):len(current)], line)) {
+				flush()
+			}
+			if !b.fits([]render.Line{line}) {
+				line = b.cutLine(line)
+			}
+			current = append(current, line)
+		}
+	}
+	flush()
+
+	return chunks
+}
+
+func (b *Bot) fits(lines []render.Line) bool {
+	return len(b.markup().Format(&render.Document{Lines: lines})) <= maxMessageLength
+}
+
+// cutLine shortens a line that is too long for a message on its own and
+// marks the cut with an ellipsis
+func (b *Bot) cutLine(line render.Line) render.Line {
+	ellipsis := render.Span{Text: "..."}
+	var cut render.Line
+	for _, span := range line {
+		if b.fits([]render.Line{append(cut[:len(cut):len(cut)], span, ellipsis)}) {
+			cut = append(cut, span)
+			continue
+		}
+		runes := []rune(span.Text)
+		partial := span
+		n := sort.Search(len(runes)+1, func(n int) bool {
+			partial.Text = string(runes[:n])
+			return !b.fits([]render.Line{append(cut[:len(cut):len(cut)], partial, ellipsis)})
+		}) - 1
+		if n > 0 {
+			partial.Text = string(runes[:n])
+			cut = append(cut, partial)
+		}
+		break
+	}
+	return append(cut, ellipsis)
+}
+
+// sections groups lines into runs separated by blank lines. Each section
+// keeps the blank lines that follow it, so chunks read like the original.
+func sections(lines []render.Line) [][]render.Line {
+	var out [][]render.Line
+	var current []render.Line
+	for _, line := range lines {
+		if !isBlank(line) && len(current) > 0 && isBlank(current[len(current)-1]) {
+			out = append(out, current)
+			current = nil
+		}
+		current = append(current, line)
+	}
+	if len(current) > 0 {
+		out = append(out, current)
+	}
+	return out
+}
+
+func trimBlankLines(lines []render.Line) []render.Line {
+	for len(lines) > 0 && isBlank(lines[0]) {
+		lines = lines[1:]
+	}
+	for len(lines) > 0 && isBlank(lines[len(lines)-1]) {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
+}
+
+func isBlank(line render.Line) bool {
+	for _, span := range line {
+		if span.Text != "" {
+			return false
+		}
+	}
+	return true
+}
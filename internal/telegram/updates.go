@@ -0,0 +1,160 @@
+package telegram
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"time"
+)
+
+// Update is an incoming update from the Bot API. Only button taps and
+// commands are used.
+type Update struct {
+	UpdateID      int            `json:"update_id"`
+	Message       *Message       `json:"message"`
+	CallbackQuery *CallbackQuery `json:"callback_query"`
+}
+
+// CallbackQuery is sent when someone taps an inline keyboard button
+type CallbackQuery struct {
+	ID      string   `json:"id"`
+	Data    string   `json:"data"`
+	Message *Message `json:"message"`
+}
+
+// Message is the part of a Telegram message the bot needs to reply to it
+type Message struct {
+	MessageID int    `json:"message_id"`
+	Chat      Chat   `json:"chat"`
+	From      *User  `json:"from"`
+	Text      string `json:"text"`
+}
+
+// User identifies who sent a message
+type User struct {
+	ID int64 `json:"id"`
+}
+
+// Chat identifies the chat a message was sent in
+type Chat struct {
+	ID int64 `json:"id"`
+}
+
+// pollTimeout is how long a getUpdates long poll waits for an update
+const pollTimeout = 30 * time.Second
+
+// pollErrorDelay is how long the poller waits after a failed getUpdates
+const pollErrorDelay = 5 * time.Second
+
+// allowedUpdates are the kinds of update the bot asks Telegram for
+var allowedUpdates = []string{"message", "callback_query"}
+
+// Poller receives updates for a bot token, by long polling getUpdates or,
+// with a webhook URL configured, as a webhook behind a reverse proxy. It
+// hands each button tap and command to the bot for the chat it came from.
+// Telegram allows one poller per token, so bots for several chats share one.
+type Poller struct {
+	bots   map[int64]*Bot
+	client *Bot // Makes the getUpdates calls
+	offset int
+}
+
+func NewPoller(bots ...*Bot) *Poller {
+	p := &Poller{bots: make(map[int64]*Bot, len(bots))}
+	for _, bot := range bots {
+		p.bots[bot.config.ChatID] = bot
+		if p.client == nil {
+			p.client = bot
+		}
+	}
+	return p
+}
+
+// Run receives updates until the context is cancelled
+func (p *Poller) Run(ctx context.Context) {
+	if p.client == nil {
+		return
+	}
+	log.Printf("Listening for Telegram updates in %d chat(s)", len(p.bots))
+
+	if p.client.config.WebhookURL != "" {
+		p.runWebhook(ctx)
+		return
+	}
+
+	// getUpdates is refused while a webhook is registered, e.g. after
+	// switching back from webhook mode
+	if _, err := p.client.callJSON("deleteWebhook", map[string]any{}); err != nil {
+		log.Printf("Warning: could not remove the Telegram webhook: %v", err)
+	}
+
+	for ctx.Err() == nil {
+		updates, err := p.getUpdates()
+		if err != nil {
+			log.Printf("Warning: could not get Telegram updates: %v", err)
+			select {
+			case <-time.After(pollErrorDelay):
+			case <-ctx.Done():
+			}
+			continue
+		}
+		for _, update := range updates {
+			p.offset = update.UpdateID + 1
+			p.dispatch(update)
+		}
+	}
+}
+
+// dispatch routes a button tap or command to the bot for its chat. Updates
+// from other chats are ignored.
+func (p *Poller) dispatch(update Update) {
+	switch {
+	case update.CallbackQuery != nil && update.CallbackQuery.Message != nil:
+		if bot, ok := p.bots[update.CallbackQuery.Message.Chat.ID]; ok {
+			bot.handleCallback(update.CallbackQuery)
+		}
+	case update.Message != nil:
+		if bot, ok := p.bots[update.Message.Chat.ID]; ok {
+			bot.handleCommand(update.Message)
+		}
+	}
+}
+
+func (p *Poller) getUpdates() ([]Update, error) {
+	result, err := p.client.callJSON("getUpdates", map[string]any{
+		"offset":          p.offset,
+		"timeout":         int(pollTimeout.Seconds()),
+		"allowed_updates": allowedUpdates,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var updates []Update
+	if err := json.Unmarshal(result, &updates); err != nil {
+		return nil, fmt.Errorf("failed to parse updates: %w", err)
+	}
+	return updates, nil
+}
+
+// answerCallback stops the button's loading spinner, optionally showing a
+// short notice
+func (b *Bot) answerCallback(queryID, text string) error {
+	req := map[string]any{"callback_query_id": queryID}
+	if text != "" {
+		req["text"] = text
+	}
+	_, err := b.callJSON("answerCallbackQuery", req)
+	return err
+}
+
+// callJSON calls a Bot API method with a JSON body
+func (b *Bot) callJSON(method string, req any) (json.RawMessage, error) {
+	jsonData, err := json.Marshal(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
+	}
+	return b.call(method, "application/json", bytes.NewReader(jsonData))
+}
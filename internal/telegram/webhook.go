@@ -0,0 +1,73 @@
+package telegram
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"time"
+)
+
+// webhookSecretHeader carries the secret token Telegram sends with each
+// webhook update
+const webhookSecretHeader = "X-Telegram-Bot-Api-Secret-Token"
+
+// maxUpdateSize bounds the body of a webhook request
+const maxUpdateSize = 1 << 20
+
+// runWebhook registers the webhook with Telegram and serves updates on the
+// configured address until the context is cancelled. TLS is left to the
+// reverse proxy in front of it.
+func (p *Poller) runWebhook(ctx context.Context) {
+	cfg := p.client.config
+	_, err := p.client.callJSON("setWebhook", map[string]any{
+		"url":             cfg.WebhookURL,
+		"secret_token":    cfg.WebhookSecret,
+		"allowed_updates": allowedUpdates,
+	})
+	if err != nil {
+		log.Printf("Warning: could not register the Telegram webhook: %v", err)
+		return
+	}
+
+	server := &http.Server{
+		Addr:              cfg.WebhookAddr,
+		Handler:           p.webhookHandler(),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	go func() {
+		<-ctx.Done()
+		server.Shutdown(context.Background())
+	}()
+
+	log.Printf("Telegram webhook listening on %s for %s", cfg.WebhookAddr, cfg.WebhookURL)
+	if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		log.Printf("Warning: Telegram webhook stopped: %v", err)
+	}
+}
+
+// webhookHandler accepts updates pushed by Telegram, rejecting requests
+// without the secret token set when the webhook was registered
+func (p *Poller) webhookHandler() http.Handler {
+	secret := []byte(p.client.config.WebhookSecret)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		if subtle.ConstantTimeCompare([]byte(r.Header.Get(webhookSecretHeader)), secret) != 1 {
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+
+		var update Update
+		if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxUpdateSize)).Decode(&update); err != nil {
+			http.Error(w, "invalid update", http.StatusBadRequest)
+			return
+		}
+		p.dispatch(update)
+		w.WriteHeader(http.StatusOK)
+	})
+}
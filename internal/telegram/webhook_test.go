@@ -0,0 +1,78 @@
+package telegram
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestWebhookHandler_RejectsWrongSecret(t *testing.T) {
+	bot, _ := newRecordingBot(t, nil)
+	bot.config.WebhookSecret = "s3cret"
+	handler := NewPoller(bot).webhookHandler()
+
+	for _, secret := range []string{"", "wrong"} {
+		req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"update_id":1}`))
+		if secret != "" {
+			req.Header.Set(webhookSecretHeader, secret)
+		}
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		if rec.Code != http.StatusUnauthorized {
+			t.Errorf("secret %q: got status %d, want 401", secret, rec.Code)
+		}
+	}
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+	if rec.Code != http.StatusMethodNotAllowed {
+		t.Errorf("GET: got status %d, want 405", rec.Code)
+	}
+}
+
+func TestWebhookHandler_DispatchesUpdate(t *testing.T) {
+	bot, calls := newRecordingBot(t, nil)
+	bot.config.WebhookSecret = "s3cret"
+	bot.PublishReport(compactReport())
+	handler := NewPoller(bot).webhookHandler()
+
+	body := `{"update_id":10,"callback_query":{"id":"q1","data":"details:1:categories","message":{"message_id":5,"chat":{"id":42}}}}`
+	req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body))
+	req.Header.Set(webhookSecretHeader, "s3cret")
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("got status %d, want 200", rec.Code)
+	}
+	if got := methods(*calls); !strings.Contains(strings.Join(got, ","), "answerCallbackQuery") {
+		t.Errorf("expected the tap to be answered, got calls %v", got)
+	}
+}
+
+func TestPoller_RegistersWebhook(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	bot, calls := newRecordingBot(t, func(method string) string {
+		if method == "setWebhook" {
+			cancel()
+		}
+		return ""
+	})
+	bot.config.WebhookURL = "https://wrap.example.com/telegram"
+	bot.config.WebhookAddr = "127.0.0.1:0"
+	bot.config.WebhookSecret = "s3cret"
+
+	NewPoller(bot).Run(ctx)
+
+	if len(*calls) != 1 || (*calls)[0].method != "setWebhook" {
+		t.Fatalf("expected only setWebhook, got %v", methods(*calls))
+	}
+	req := (*calls)[0].body
+	if req["url"] != "https://wrap.example.com/telegram" || req["secret_token"] != "s3cret" {
+		t.Errorf("unexpected setWebhook request: %v", req)
+	}
+}
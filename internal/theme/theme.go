@@ -0,0 +1,120 @@
+// Package theme holds the emoji that mark each section of a wrap, so they can
+// be swapped or left out for clients that render emoji poorly.
+package theme
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// defaultEmoji are the emoji of the built-in layouts, by name
+var defaultEmoji = map[string]string{
+	"chart":     "📊",
+	"money":     "💰",
+	"cart":      "🛒",
+	"trophy":    "🏆",
+	"warning":   "⚠️",
+	"party":     "🎉",
+	"target":    "🎯",
+	"scales":    "⚖️",
+	"rocket":    "🚀",
+	"note":      "📝",
+	"package":   "📦",
+	"new":       "🆕",
+	"broom":     "🧹",
+	"calendar":  "🗓️",
+	"info":      "ℹ️",
+	"up":        "📈",
+	"down":      "📉",
+	"check":     "✅",
+	"envelope":  "✉️",
+	"alert":     "🚨",
+	"cash":      "💸",
+	"plug":      "🔌",
+	"detective": "🕵️",
+	"pin":       "📌",
+	"bank":      "🏦",
+	"people":    "👥",
+	"seedling":  "🌱",
+	"flag":      "🏁",
+	"hourglass": "⏳",
+	"card":      "💳",
+	"inbox":     "📥",
+	"traffic":   "🚦",
+	"slight":    "🟡",
+	"moderate":  "🟠",
+	"severe":    "🔴",
+	"bulb":      "💡",
+	"flagged":   "🚩",
+}
+
+// Theme maps emoji names to what is shown for them. The zero Theme shows the
+// default emoji.
+type Theme struct {
+	emoji map[string]string
+}
+
+// New returns the named theme, "default" or "plain" (no emoji), with
+// overrides applied. An override with an empty value leaves that emoji out.
+func New(name string, overrides map[string]string) (Theme, error) {
+	emoji := make(map[string]string, len(defaultEmoji))
+	switch name {
+	case "", "default":
+		for key, value := range defaultEmoji {
+			emoji[key] = value
+		}
+	case "plain":
+		for key := range defaultEmoji {
+			emoji[key] = ""
+		}
+	default:
+		return Theme{}, fmt.Errorf("unknown theme %q, must be default or plain", name)
+	}
+
+	for key, value := range overrides {
+		if _, ok := defaultEmoji[key]; !ok {
+			return Theme{}, fmt.Errorf("unknown emoji %q, available: %s", key, strings.Join(Names(), ", "))
+		}
+		emoji[key] = value
+	}
+	return Theme{emoji: emoji}, nil
+}
+
+// Names lists the emoji names a theme can set
+func Names() []string {
+	names := make([]string, 0, len(defaultEmoji))
+	for name := range defaultEmoji {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Emoji returns the emoji shown for name, and whether name is known
+func (t Theme) Emoji(name string) (string, bool) {
+	if t.emoji == nil {
+		emoji, ok := defaultEmoji[name]
+		return emoji, ok
+	}
+	emoji, ok := t.emoji[name]
+	return emoji, ok
+}
+
+// Icon returns the emoji for name followed by a space, ready to go before a
+// heading, or "" when the theme leaves it out
+func (t Theme) Icon(name string) string {
+	if emoji, _ := t.Emoji(name); emoji != "" {
+		return emoji + " "
+	}
+	return ""
+}
+
+// Suffix returns a space followed by the emoji for name, ready to go after
+// text, or "" when the theme leaves it out
+func (t Theme) Suffix(name string) string {
+	if emoji, _ := t.Emoji(name); emoji != "" {
+		return " " + emoji
+	}
+	return ""
+}
@@ -0,0 +1,41 @@
+package theme
+
+import "testing"
+
+func TestNew(t *testing.T) {
+	plain, err := New("plain", map[string]string{"warning": "!"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := plain.Icon("chart"); got != "" {
+		t.Errorf("plain theme should leave emoji out, got %q", got)
+	}
+	if got := plain.Icon("warning"); got != "! " {
+		t.Errorf("expected the override, got %q", got)
+	}
+
+	custom, err := New("default", map[string]string{"money": "💵", "party": ""})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := custom.Icon("money") + custom.Icon("trophy") + "done" + custom.Suffix("party"); got != "💵 🏆 done" {
+		t.Errorf("got %q", got)
+	}
+
+	if _, err := New("fancy", nil); err == nil {
+		t.Error("expected an error for an unknown theme")
+	}
+	if _, err := New("default", map[string]string{"unicorn": "🦄"}); err == nil {
+		t.Error("expected an error for an unknown emoji name")
+	}
+}
+
+func TestZeroTheme_UsesDefaults(t *testing.T) {
+	var zero Theme
+	if got := zero.Icon("chart"); got != "📊 " {
+		t.Errorf("got %q", got)
+	}
+	if _, ok := zero.Emoji("unicorn"); ok {
+		t.Error("unknown names should not be found")
+	}
+}
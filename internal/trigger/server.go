@@ -0,0 +1,191 @@
+package trigger
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"log"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sathyabhat/ynab-weekly-wrap/internal/config"
+	"github.com/sathyabhat/ynab-weekly-wrap/internal/metrics"
+	"github.com/sathyabhat/ynab-weekly-wrap/internal/pipeline"
+	"github.com/sathyabhat/ynab-weekly-wrap/internal/publisher"
+)
+
+// Runner runs and delivers a wrap, and reports how recent runs went. The
+// scheduler implements it.
+type Runner interface {
+	RunOnce() error
+	RunMonthlyOnce() error
+	RunDailyOnce() error
+	Status() metrics.Status
+	Latest() (*pipeline.Run, time.Time) // Last weekly or monthly wrap and when it ran; nil before any has
+}
+
+// Response is the JSON body returned to the caller
+type Response struct {
+	Status string `json:"status"`          // "sent" or "error"
+	Period string `json:"period"`          // "weekly", "monthly" or "daily"
+	Error  string `json:"error,omitempty"` // Why the wrap failed
+}
+
+// Overview is the latest wrap's headline figures, kept small for an iOS
+// Shortcut or home-screen widget. Amounts are in currency units, each with
+// the text to display alongside.
+type Overview struct {
+	Period          string    `json:"period"` // "weekly" or "monthly"
+	DateRange       string    `json:"date_range"`
+	UpdatedAt       time.Time `json:"updated_at"` // When the wrap ran
+	Currency        string    `json:"currency"`   // ISO code, e.g. USD
+	TotalSpent      float64   `json:"total_spent"`
+	TotalSpentText  string    `json:"total_spent_text"`
+	SafeToSpend     *float64  `json:"safe_to_spend,omitempty"` // Weekly wraps only
+	SafeToSpendText string    `json:"safe_to_spend_text,omitempty"`
+	OverBudget      int       `json:"over_budget"` // Categories over budget
+}
+
+// Server accepts authenticated requests to generate and send a wrap on
+// demand. Only one wrap runs at a time; a request made while one is running
+// is turned away rather than queued.
+type Server struct {
+	config config.TriggerConfig
+	runner Runner
+	busy   sync.Mutex
+}
+
+func NewServer(triggerConfig config.TriggerConfig, runner Runner) *Server {
+	return &Server{config: triggerConfig, runner: runner}
+}
+
+// Handler returns the routes: POST /wrap sends the weekly wrap,
+// POST /wrap?period=monthly or ?period=daily sends the monthly wrap or the
+// daily digest, GET /status reports stage timings and API health, and
+// GET /overview returns the latest wrap's figures
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /wrap", s.handleWrap)
+	mux.HandleFunc("GET /status", s.handleStatus)
+	mux.HandleFunc("GET /overview", s.handleOverview)
+	return mux
+}
+
+// ListenAndServe serves the trigger endpoint until the listener fails
+func (s *Server) ListenAndServe() error {
+	log.Printf("Trigger endpoint listening on %s", s.config.Addr)
+	server := &http.Server{
+		Addr:              s.config.Addr,
+		Handler:           s.Handler(),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	return server.ListenAndServe()
+}
+
+func (s *Server) handleWrap(w http.ResponseWriter, r *http.Request) {
+	if !s.authorized(r) {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	period := r.URL.Query().Get("period")
+	var run func() error
+	switch period {
+	case "", "weekly":
+		period, run = "weekly", s.runner.RunOnce
+	case "monthly":
+		run = s.runner.RunMonthlyOnce
+	case "daily":
+		run = s.runner.RunDailyOnce
+	default:
+		http.Error(w, "period must be weekly, monthly or daily", http.StatusBadRequest)
+		return
+	}
+
+	if !s.busy.TryLock() {
+		http.Error(w, "a wrap is already running", http.StatusConflict)
+		return
+	}
+	defer s.busy.Unlock()
+
+	log.Printf("Trigger: running %s wrap on demand", period)
+	resp := Response{Status: "sent", Period: period}
+	status := http.StatusOK
+	if err := run(); err != nil {
+		log.Printf("Trigger: %s wrap failed: %v", period, err)
+		resp.Status, resp.Error = "error", publisher.SafeError(err)
+		status = http.StatusInternalServerError
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(resp)
+}
+
+func (s *Server) handleStatus(w http.ResponseWriter, r *http.Request) {
+	if !s.authorized(r) {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(s.runner.Status())
+}
+
+func (s *Server) handleOverview(w http.ResponseWriter, r *http.Request) {
+	if !s.authorized(r) {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+	run, at := s.runner.Latest()
+	if run == nil {
+		http.Error(w, "no wrap has run yet", http.StatusNotFound)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	json.NewEncoder(w).Encode(newOverview(run, at))
+}
+
+// newOverview collects a run's headline figures
+func newOverview(run *pipeline.Run, at time.Time) Overview {
+	analysis := run.Analysis
+	currency := analysis.Currency
+	overview := Overview{
+		Period:     "weekly",
+		DateRange:  analysis.DateRange,
+		UpdatedAt:  at,
+		Currency:   currency.ISOCode,
+		OverBudget: len(analysis.Concerns),
+	}
+	if run.Weekly == nil {
+		overview.Period = "monthly"
+	}
+	if analysis.Overview != nil {
+		overview.TotalSpent = units(analysis.Overview.TotalSpent)
+		overview.TotalSpentText = currency.Amount(analysis.Overview.TotalSpent)
+	}
+	if safe := analysis.SafeToSpend; safe != nil {
+		amount := units(safe.Amount)
+		overview.SafeToSpend, overview.SafeToSpendText = &amount, currency.Amount(safe.Amount)
+	}
+	return overview
+}
+
+// units converts milliunits to currency units
+func units(milliunits int64) float64 {
+	return float64(milliunits) / 1000
+}
+
+// authorized checks the token from an "Authorization: Bearer" header, or the
+// token query parameter for tools that can't set headers
+func (s *Server) authorized(r *http.Request) bool {
+	token := r.URL.Query().Get("token")
+	if header := r.Header.Get("Authorization"); header != "" {
+		token = strings.TrimPrefix(header, "Bearer ")
+	}
+	if s.config.Token == "" || token == "" {
+		return false
+	}
+	return subtle.ConstantTimeCompare([]byte(token), []byte(s.config.Token)) == 1
+}
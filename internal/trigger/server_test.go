@@ -0,0 +1,241 @@
+package trigger
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/sathyabhat/ynab-weekly-wrap/internal/config"
+	"github.com/sathyabhat/ynab-weekly-wrap/internal/metrics"
+	"github.com/sathyabhat/ynab-weekly-wrap/internal/money"
+	"github.com/sathyabhat/ynab-weekly-wrap/internal/pipeline"
+	"github.com/sathyabhat/ynab-weekly-wrap/internal/processor"
+	"github.com/sathyabhat/ynab-weekly-wrap/internal/ynab"
+)
+
+type fakeRunner struct {
+	weekly, monthly, daily int
+	err                    error
+	started, block         chan struct{} // When set, RunOnce signals started and waits for block
+	latest                 *pipeline.Run
+}
+
+func (f *fakeRunner) RunOnce() error {
+	f.weekly++
+	if f.block != nil {
+		close(f.started)
+		<-f.block
+	}
+	return f.err
+}
+
+func (f *fakeRunner) RunMonthlyOnce() error {
+	f.monthly++
+	return f.err
+}
+
+func (f *fakeRunner) RunDailyOnce() error {
+	f.daily++
+	return f.err
+}
+
+func (f *fakeRunner) Status() metrics.Status {
+	recorder := metrics.NewRecorder()
+	recorder.ObserveRun("Weekly wrap", []metrics.Stage{{Name: "fetch", Duration: time.Second}}, nil)
+	recorder.ObserveCall("ynab", 200*time.Millisecond, nil)
+	return recorder.Status()
+}
+
+func (f *fakeRunner) Latest() (*pipeline.Run, time.Time) {
+	return f.latest, time.Date(2026, 1, 26, 9, 0, 0, 0, time.UTC)
+}
+
+func newTestServer(runner Runner) *Server {
+	return NewServer(config.TriggerConfig{Addr: ":0", Token: "s3cret"}, runner)
+}
+
+func post(t *testing.T, handler http.Handler, target, auth string) *httptest.ResponseRecorder {
+	t.Helper()
+	req := httptest.NewRequest(http.MethodPost, target, nil)
+	if auth != "" {
+		req.Header.Set("Authorization", auth)
+	}
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestHandleWrap_Weekly(t *testing.T) {
+	runner := &fakeRunner{}
+	rec := post(t, newTestServer(runner).Handler(), "/wrap", "Bearer s3cret")
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status: got %d, want 200", rec.Code)
+	}
+	var resp Response
+	if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if resp.Status != "sent" || resp.Period != "weekly" || runner.weekly != 1 {
+		t.Errorf("got %+v after %d weekly runs", resp, runner.weekly)
+	}
+}
+
+func TestHandleWrap_MonthlyWithQueryToken(t *testing.T) {
+	runner := &fakeRunner{}
+	rec := post(t, newTestServer(runner).Handler(), "/wrap?period=monthly&token=s3cret", "")
+
+	if rec.Code != http.StatusOK || runner.monthly != 1 || runner.weekly != 0 {
+		t.Errorf("status %d, %d monthly / %d weekly runs", rec.Code, runner.monthly, runner.weekly)
+	}
+}
+
+func TestHandleWrap_Daily(t *testing.T) {
+	runner := &fakeRunner{}
+	rec := post(t, newTestServer(runner).Handler(), "/wrap?period=daily", "Bearer s3cret")
+
+	var resp Response
+	if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if rec.Code != http.StatusOK || resp.Period != "daily" || runner.daily != 1 || runner.weekly != 0 {
+		t.Errorf("status %d, got %+v after %d daily / %d weekly runs", rec.Code, resp, runner.daily, runner.weekly)
+	}
+}
+
+func TestHandleWrap_Unauthorized(t *testing.T) {
+	runner := &fakeRunner{}
+	handler := newTestServer(runner).Handler()
+
+	for _, auth := range []string{"", "Bearer wrong", "s3cre"} {
+		if rec := post(t, handler, "/wrap", auth); rec.Code != http.StatusUnauthorized {
+			t.Errorf("auth %q: got %d, want 401", auth, rec.Code)
+		}
+	}
+	if runner.weekly != 0 {
+		t.Errorf("expected no runs, got %d", runner.weekly)
+	}
+}
+
+func TestHandleWrap_NoTokenConfigured(t *testing.T) {
+	runner := &fakeRunner{}
+	server := NewServer(config.TriggerConfig{Addr: ":0"}, runner)
+
+	if rec := post(t, server.Handler(), "/wrap", "Bearer "); rec.Code != http.StatusUnauthorized {
+		t.Errorf("got %d, want 401", rec.Code)
+	}
+}
+
+func TestHandleWrap_BadPeriod(t *testing.T) {
+	rec := post(t, newTestServer(&fakeRunner{}).Handler(), "/wrap?period=yearly", "Bearer s3cret")
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("got %d, want 400", rec.Code)
+	}
+}
+
+func TestHandleWrap_RunFails(t *testing.T) {
+	runner := &fakeRunner{err: errors.New("YNAB unreachable")}
+	rec := post(t, newTestServer(runner).Handler(), "/wrap", "Bearer s3cret")
+
+	var resp Response
+	json.NewDecoder(rec.Body).Decode(&resp)
+	if rec.Code != http.StatusInternalServerError || resp.Status != "error" || resp.Error != "YNAB unreachable" {
+		t.Errorf("got %d %+v", rec.Code, resp)
+	}
+}
+
+func TestHandleWrap_AlreadyRunning(t *testing.T) {
+	runner := &fakeRunner{started: make(chan struct{}), block: make(chan struct{})}
+	handler := newTestServer(runner).Handler()
+
+	done := make(chan int)
+	go func() { done <- post(t, handler, "/wrap", "Bearer s3cret").Code }()
+	<-runner.started
+
+	if rec := post(t, handler, "/wrap?period=monthly", "Bearer s3cret"); rec.Code != http.StatusConflict {
+		t.Errorf("second request: got %d, want 409", rec.Code)
+	}
+	close(runner.block)
+	if code := <-done; code != http.StatusOK {
+		t.Errorf("first request: got %d, want 200", code)
+	}
+}
+
+func TestHandleStatus(t *testing.T) {
+	handler := newTestServer(&fakeRunner{}).Handler()
+
+	req := httptest.NewRequest(http.MethodGet, "/status", nil)
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	if rec.Code != http.StatusUnauthorized {
+		t.Errorf("without a token: got %d, want 401", rec.Code)
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/status?token=s3cret", nil)
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status: got %d, want 200", rec.Code)
+	}
+	var status metrics.Status
+	if err := json.NewDecoder(rec.Body).Decode(&status); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if len(status.Runs) != 1 || status.Runs[0].Stages[0].Millis != 1000 {
+		t.Errorf("runs: got %+v", status.Runs)
+	}
+	if status.APIs["ynab"].P50 != 200 {
+		t.Errorf("ynab latency: got %+v", status.APIs["ynab"])
+	}
+}
+
+func TestHandleOverview(t *testing.T) {
+	runner := &fakeRunner{latest: &pipeline.Run{
+		Weekly: &ynab.WeeklyData{},
+		Analysis: &processor.AnalysisResult{
+			Overview:    &processor.Overview{TotalSpent: 120_500},
+			SafeToSpend: &processor.SafeToSpend{Amount: 85_000},
+			Concerns:    []processor.CategoryConcernWithTransactions{{}, {}},
+			Currency:    money.USD,
+			DateRange:   "2026-01-19 to 2026-01-25",
+		},
+	}}
+	req := httptest.NewRequest(http.MethodGet, "/overview?token=s3cret", nil)
+	rec := httptest.NewRecorder()
+	newTestServer(runner).Handler().ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status: got %d, want 200", rec.Code)
+	}
+	var overview Overview
+	if err := json.NewDecoder(rec.Body).Decode(&overview); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if overview.Period != "weekly" || overview.TotalSpent != 120.5 || overview.TotalSpentText != "$120.50" || overview.OverBudget != 2 {
+		t.Errorf("got %+v", overview)
+	}
+	if overview.SafeToSpend == nil || *overview.SafeToSpend != 85 || overview.SafeToSpendText != "$85" {
+		t.Errorf("safe to spend: got %v %q, want 85 $85", overview.SafeToSpend, overview.SafeToSpendText)
+	}
+}
+
+func TestHandleOverview_NoWrapYet(t *testing.T) {
+	handler := newTestServer(&fakeRunner{}).Handler()
+
+	req := httptest.NewRequest(http.MethodGet, "/overview", nil)
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	if rec.Code != http.StatusUnauthorized {
+		t.Errorf("without a token: got %d, want 401", rec.Code)
+	}
+
+	req.Header.Set("Authorization", "Bearer s3cret")
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("got %d, want 404", rec.Code)
+	}
+}
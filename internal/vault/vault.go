@@ -0,0 +1,92 @@
+// Package vault writes each wrap as a Markdown note with YAML frontmatter
+// into a local folder, such as an Obsidian vault, where Dataview can query
+// the figures across weeks.
+package vault
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/sathyabhat/ynab-weekly-wrap/internal/pipeline"
+	"github.com/sathyabhat/ynab-weekly-wrap/internal/render"
+)
+
+// Vault implements pipeline.Exporter, writing every weekly and monthly wrap
+// to dir/<year>/<period end>-<kind>.md
+type Vault struct {
+	dir string
+}
+
+// New creates a vault writing notes under dir
+func New(dir string) *Vault {
+	return &Vault{dir: dir}
+}
+
+// Export writes the run's wrap, replacing the note when the period's wrap
+// was written before
+func (v *Vault) Export(run *pipeline.Run) error {
+	kind := ""
+	switch {
+	case run.Weekly != nil:
+		kind = "weekly"
+	case run.Monthly != nil:
+		kind = "monthly"
+	}
+	if kind == "" || run.Report.Message == "" {
+		return nil
+	}
+
+	path := filepath.Join(v.dir, run.PeriodEnd.Format("2006"), run.PeriodEnd.Format("2006-01-02")+"-"+kind+".md")
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("failed to create vault directory: %w", err)
+	}
+	if err := os.WriteFile(path, []byte(Note(run, kind)), 0o644); err != nil {
+		return fmt.Errorf("failed to write vault note: %w", err)
+	}
+	log.Printf("Wrote %s", path)
+	return nil
+}
+
+// Note renders a run as a Markdown note. The frontmatter holds the period,
+// the totals in currency units, the number of categories over budget and
+// the budget health percentage, under names Dataview can query, e.g.
+//
+//	TABLE total_spent, over_budget FROM #weekly-wrap SORT period_end DESC
+func Note(run *pipeline.Run, kind string) string {
+	var b strings.Builder
+	b.WriteString("---\n")
+	field := func(name, value string) {
+		b.WriteString(name + ": " + value + "\n")
+	}
+	field("title", strconv.Quote(run.Report.Title))
+	field("kind", kind)
+	field("period_start", run.PeriodStart.Format("2006-01-02"))
+	field("period_end", run.PeriodEnd.Format("2006-01-02"))
+	if analysis := run.Analysis; analysis != nil {
+		if analysis.Currency.ISOCode != "" {
+			field("currency", analysis.Currency.ISOCode)
+		}
+		if overview := analysis.Overview; overview != nil {
+			field("total_spent", units(overview.TotalSpent))
+			field("total_budgeted", units(overview.TotalBudgeted))
+			field("total_balance", units(overview.TotalBalance))
+			field("health_score", strconv.FormatFloat(overview.HealthPercentage, 'f', 1, 64))
+		}
+		field("over_budget", strconv.Itoa(len(analysis.Concerns)))
+	}
+	field("tags", "[budget, "+kind+"-wrap]")
+	b.WriteString("---\n\n")
+
+	b.WriteString("# " + run.Report.Title + "\n\n")
+	b.WriteString(render.MarkdownFile.Format(run.Report.Doc()) + "\n")
+	return b.String()
+}
+
+// units writes milliunits as a plain decimal number of currency units
+func units(milliunits int64) string {
+	return strconv.FormatFloat(float64(milliunits)/1000, 'f', -1, 64)
+}
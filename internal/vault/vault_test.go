@@ -0,0 +1,81 @@
+package vault
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sathyabhat/ynab-weekly-wrap/internal/money"
+	"github.com/sathyabhat/ynab-weekly-wrap/internal/pipeline"
+	"github.com/sathyabhat/ynab-weekly-wrap/internal/processor"
+	"github.com/sathyabhat/ynab-weekly-wrap/internal/publisher"
+	"github.com/sathyabhat/ynab-weekly-wrap/internal/ynab"
+)
+
+func weeklyRun() *pipeline.Run {
+	return &pipeline.Run{
+		PeriodStart: time.Date(2026, 1, 19, 0, 0, 0, 0, time.UTC),
+		PeriodEnd:   time.Date(2026, 1, 25, 23, 59, 59, 0, time.UTC),
+		Weekly:      &ynab.WeeklyData{},
+		Analysis: &processor.AnalysisResult{
+			Overview: &processor.Overview{TotalSpent: 120_500, TotalBudgeted: 2_000_000, TotalBalance: 880_000, HealthPercentage: 56.04},
+			Concerns: []processor.CategoryConcernWithTransactions{{}, {}},
+			Currency: money.USD,
+		},
+		Report: publisher.Report{
+			Title:   "Weekly Financial Wrap - 2026-01-19 to 2026-01-25",
+			Message: "💰 **Total Spent**: $120.50\n• Groceries",
+		},
+	}
+}
+
+func TestNote(t *testing.T) {
+	want := `---
+title: "Weekly Financial Wrap - 2026-01-19 to 2026-01-25"
+kind: weekly
+period_start: 2026-01-19
+period_end: 2026-01-25
+currency: USD
+total_spent: 120.5
+total_budgeted: 2000
+total_balance: 880
+health_score: 56.0
+over_budget: 2
+tags: [budget, weekly-wrap]
+---
+
+# Weekly Financial Wrap - 2026-01-19 to 2026-01-25
+
+💰 **Total Spent**: $120.50  
+• Groceries
+`
+	if got := Note(weeklyRun(), "weekly"); got != want {
+		t.Errorf("got:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestExport(t *testing.T) {
+	dir := t.TempDir()
+	v := New(dir)
+	if err := v.Export(weeklyRun()); err != nil {
+		t.Fatalf("Export failed: %v", err)
+	}
+	data, err := os.ReadFile(filepath.Join(dir, "2026", "2026-01-25-weekly.md"))
+	if err != nil {
+		t.Fatalf("expected the note to be written: %v", err)
+	}
+	if !strings.HasPrefix(string(data), "---\ntitle:") {
+		t.Errorf("expected the note to start with frontmatter, got:\n%s", data)
+	}
+
+	// Daily digests and other runs aren't kept
+	if err := v.Export(&pipeline.Run{Report: publisher.Report{Message: "x"}}); err != nil {
+		t.Fatalf("Export failed: %v", err)
+	}
+	entries, _ := os.ReadDir(filepath.Join(dir, "2026"))
+	if len(entries) != 1 {
+		t.Errorf("expected only the weekly note, got %v", entries)
+	}
+}
@@ -0,0 +1,115 @@
+package webhook
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/sathyabhat/ynab-weekly-wrap/internal/processor"
+	"github.com/sathyabhat/ynab-weekly-wrap/internal/publisher"
+)
+
+// SignatureHeader carries the hex-encoded HMAC-SHA256 of the request body
+// when a secret is configured, in the form "sha256=<hex>"
+const SignatureHeader = "X-Wrap-Signature-256"
+
+// Publisher implements the publisher.Publisher interface by POSTing the wrap
+// as JSON to an arbitrary URL (Home Assistant, n8n, custom services, ...)
+type Publisher struct {
+	URL    string
+	Secret string // Optional HMAC secret used to sign the payload
+}
+
+// Payload is the JSON body sent to the webhook
+type Payload struct {
+	Title    string                    `json:"title,omitempty"`
+	Message  string                    `json:"message"`
+	Analysis *processor.AnalysisResult `json:"analysis,omitempty"`
+	SentAt   time.Time                 `json:"sent_at"`
+}
+
+// NewPublisher creates a new generic webhook publisher
+func NewPublisher(url, secret string) *Publisher {
+	return &Publisher{
+		URL:    url,
+		Secret: secret,
+	}
+}
+
+// Name identifies the destination in delivery summaries
+func (p *Publisher) Name() string {
+	return "webhook"
+}
+
+// host returns the webhook's host for the logs, leaving out the path and
+// query, which often carry a token
+func (p *Publisher) host() string {
+	u, err := url.Parse(p.URL)
+	if err != nil || u.Host == "" {
+		return "(invalid URL)"
+	}
+	return u.Host
+}
+
+// Publish sends a message-only payload
+func (p *Publisher) Publish(message string) error {
+	return p.post(Payload{Message: message, SentAt: time.Now().UTC()})
+}
+
+// PublishReport sends the report including the full analysis result
+func (p *Publisher) PublishReport(report publisher.Report) error {
+	return p.post(Payload{
+		Title:    report.Title,
+		Message:  report.Message,
+		Analysis: report.Analysis,
+		SentAt:   time.Now().UTC(),
+	})
+}
+
+func (p *Publisher) post(payload Payload) error {
+	log.Printf("Sending payload to webhook %s", p.host())
+
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to marshal webhook payload: %w", err)
+	}
+
+	req, err := http.NewRequest(http.MethodPost, p.URL, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create webhook request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if p.Secret != "" {
+		req.Header.Set(SignatureHeader, "sha256="+Sign(body, p.Secret))
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send webhook request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		respBody, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("webhook error (status %d): %s", resp.StatusCode, string(respBody))
+	}
+
+	log.Println("Webhook payload sent successfully")
+	return nil
+}
+
+// Sign returns the hex-encoded HMAC-SHA256 of body using secret, so receivers
+// can verify the payload came from this service
+func Sign(body []byte, secret string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
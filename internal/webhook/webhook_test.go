@@ -0,0 +1,96 @@
+package webhook
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/sathyabhat/ynab-weekly-wrap/internal/processor"
+	"github.com/sathyabhat/ynab-weekly-wrap/internal/publisher"
+)
+
+func TestPublishReport_SendsAnalysisWithSignature(t *testing.T) {
+	var body []byte
+	var signature string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Content-Type") != "application/json" {
+			t.Errorf("Expected application/json content type, got %s", r.Header.Get("Content-Type"))
+		}
+		body, _ = io.ReadAll(r.Body)
+		signature = r.Header.Get(SignatureHeader)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	p := NewPublisher(server.URL, "s3cret")
+	err := p.PublishReport(publisher.Report{
+		Title:   "Weekly Financial Wrap",
+		Message: "text",
+		Analysis: &processor.AnalysisResult{
+			Overview:  &processor.Overview{TotalSpent: 550_000},
+			DateRange: "2026-01-19 to 2026-01-26",
+		},
+	})
+	if err != nil {
+		t.Fatalf("PublishReport failed: %v", err)
+	}
+
+	if signature != "sha256="+Sign(body, "s3cret") {
+		t.Errorf("signature mismatch: got %q", signature)
+	}
+
+	var payload map[string]any
+	if err := json.Unmarshal(body, &payload); err != nil {
+		t.Fatalf("Failed to unmarshal payload: %v", err)
+	}
+	analysis, ok := payload["analysis"].(map[string]any)
+	if !ok {
+		t.Fatalf("payload missing analysis: %s", body)
+	}
+	overview := analysis["overview"].(map[string]any)
+	if overview["total_spent"] != float64(550_000) {
+		t.Errorf("total_spent: got %v, want 550000", overview["total_spent"])
+	}
+	if analysis["date_range"] != "2026-01-19 to 2026-01-26" {
+		t.Errorf("date_range: got %v", analysis["date_range"])
+	}
+}
+
+func TestPublish_NoSignatureWithoutSecret(t *testing.T) {
+	var signature string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		signature = r.Header.Get(SignatureHeader)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	if err := NewPublisher(server.URL, "").Publish("hello"); err != nil {
+		t.Fatalf("Publish failed: %v", err)
+	}
+	if signature != "" {
+		t.Errorf("expected no signature header, got %q", signature)
+	}
+}
+
+func TestPublish_ErrorStatus(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	if err := NewPublisher(server.URL, "").Publish("hello"); err == nil {
+		t.Fatal("expected error for non-2xx status, got nil")
+	}
+}
+
+func TestHost_LeavesOutPathAndQuery(t *testing.T) {
+	p := NewPublisher("https://hooks.example.com:8443/api/webhook/secret-token?key=abc", "")
+	if got := p.host(); got != "hooks.example.com:8443" {
+		t.Errorf("got %q, want only the host", got)
+	}
+	if got := NewPublisher("not a url", "").host(); got != "(invalid URL)" {
+		t.Errorf("got %q for an invalid URL", got)
+	}
+}
@@ -0,0 +1,114 @@
+package whatsapp
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"unicode/utf8"
+
+	"github.com/sathyabhat/ynab-weekly-wrap/internal/config"
+	"github.com/sathyabhat/ynab-weekly-wrap/internal/publisher"
+	"github.com/sathyabhat/ynab-weekly-wrap/internal/render"
+)
+
+const (
+	graphAPIURL = "https://graph.facebook.com/v20.0"
+
+	// maxBodyLength is the Cloud API limit for a text message body
+	maxBodyLength = 4096
+)
+
+// Publisher implements the publisher.Publisher interface through the
+// WhatsApp Business Cloud API
+type Publisher struct {
+	config config.WhatsAppConfig
+	apiURL string
+}
+
+// Message is a Cloud API text message
+type Message struct {
+	MessagingProduct string `json:"messaging_product"`
+	RecipientType    string `json:"recipient_type"`
+	To               string `json:"to"`
+	Type             string `json:"type"`
+	Text             Text   `json:"text"`
+}
+
+// Text is the body of a text message
+type Text struct {
+	PreviewURL bool   `json:"preview_url"`
+	Body       string `json:"body"`
+}
+
+// NewPublisher creates a new WhatsApp Cloud API publisher
+func NewPublisher(whatsappConfig config.WhatsAppConfig) *Publisher {
+	return &Publisher{config: whatsappConfig, apiURL: graphAPIURL}
+}
+
+// Name identifies the destination in delivery summaries
+func (p *Publisher) Name() string {
+	return "whatsapp"
+}
+
+// Publish sends the message to the configured recipient
+func (p *Publisher) Publish(message string) error {
+	return p.sendDocument(render.Parse(message))
+}
+
+// PublishReport sends the report's rendered document to the recipient
+func (p *Publisher) PublishReport(report publisher.Report) error {
+	return p.sendDocument(report.Doc())
+}
+
+func (p *Publisher) sendDocument(doc *render.Document) error {
+	log.Printf("Sending WhatsApp message to %s", p.config.Recipient)
+
+	body, err := json.Marshal(Message{
+		MessagingProduct: "whatsapp",
+		RecipientType:    "individual",
+		To:               p.config.Recipient,
+		Type:             "text",
+		Text:             Text{Body: Format(doc)},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to marshal WhatsApp message: %w", err)
+	}
+
+	endpoint := fmt.Sprintf("%s/%s/messages", p.apiURL, p.config.PhoneNumberID)
+	req, err := http.NewRequest(http.MethodPost, endpoint, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create WhatsApp request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+p.config.Token)
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send WhatsApp message: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		respBody, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("whatsapp API error (status %d): %s", resp.StatusCode, string(respBody))
+	}
+
+	log.Println("WhatsApp message sent successfully")
+	return nil
+}
+
+// Format writes the document in WhatsApp's markup and truncates it to the
+// Cloud API's body limit
+func Format(doc *render.Document) string {
+	message := render.WhatsApp.Format(doc)
+
+	if utf8.RuneCountInString(message) > maxBodyLength {
+		log.Printf("Message too long for WhatsApp (%d characters), truncating to %d", utf8.RuneCountInString(message), maxBodyLength)
+		runes := []rune(message)
+		message = string(runes[:maxBodyLength-3]) + "..."
+	}
+	return message
+}
@@ -0,0 +1,70 @@
+package whatsapp
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/sathyabhat/ynab-weekly-wrap/internal/config"
+	"github.com/sathyabhat/ynab-weekly-wrap/internal/render"
+)
+
+func TestPublish_SendsTextMessage(t *testing.T) {
+	var got Message
+	var path, auth string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path = r.URL.Path
+		auth = r.Header.Get("Authorization")
+		if err := json.NewDecoder(r.Body).Decode(&got); err != nil {
+			t.Errorf("Failed to decode request body: %v", err)
+		}
+		w.Write([]byte(`{"messages":[{"id":"wamid.1"}]}`))
+	}))
+	defer server.Close()
+
+	p := NewPublisher(config.WhatsAppConfig{PhoneNumberID: "1055", Token: "token", Recipient: "15551234567"})
+	p.apiURL = server.URL
+
+	if err := p.Publish("📊 **Weekly Financial Wrap**\n• **Groceries**: $120"); err != nil {
+		t.Fatalf("Publish failed: %v", err)
+	}
+
+	if path != "/1055/messages" || auth != "Bearer token" {
+		t.Errorf("unexpected request: path %q auth %q", path, auth)
+	}
+	if got.MessagingProduct != "whatsapp" || got.To != "15551234567" || got.Type != "text" {
+		t.Errorf("unexpected message: %+v", got)
+	}
+	if want := "📊 *Weekly Financial Wrap*\n• *Groceries*: $120"; got.Text.Body != want {
+		t.Errorf("body: got %q, want %q", got.Text.Body, want)
+	}
+}
+
+func TestPublish_Error(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(`{"error":{"message":"Re-engagement message"}}`))
+	}))
+	defer server.Close()
+
+	p := NewPublisher(config.WhatsAppConfig{PhoneNumberID: "1055", Token: "token", Recipient: "15551234567"})
+	p.apiURL = server.URL
+
+	err := p.Publish("hello")
+	if err == nil || !strings.Contains(err.Error(), "Re-engagement") {
+		t.Fatalf("expected API error, got %v", err)
+	}
+}
+
+func TestFormat_Truncates(t *testing.T) {
+	got := Format(render.Parse(strings.Repeat("é", 5000)))
+	if n := utf8.RuneCountInString(got); n != maxBodyLength {
+		t.Errorf("length: got %d, want %d", n, maxBodyLength)
+	}
+	if !strings.HasSuffix(got, "...") {
+		t.Errorf("expected truncated message to end with ...")
+	}
+}
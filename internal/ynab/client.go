@@ -3,12 +3,17 @@ package ynab
 import (
 	"fmt"
 	"log"
+	"slices"
 	"time"
 
 	"github.com/brunomvsouza/ynab.go"
 	"github.com/brunomvsouza/ynab.go/api"
+	ynabbudget "github.com/brunomvsouza/ynab.go/api/budget"
+	ynabcategory "github.com/brunomvsouza/ynab.go/api/category"
 	ynabtransaction "github.com/brunomvsouza/ynab.go/api/transaction"
 	"github.com/sathyabhat/ynab-weekly-wrap/internal/config"
+	"github.com/sathyabhat/ynab-weekly-wrap/internal/metrics"
+	"github.com/sathyabhat/ynab-weekly-wrap/internal/money"
 )
 
 // dataFetcher abstracts the YNAB API calls used by Client,
@@ -16,21 +21,56 @@ import (
 type dataFetcher interface {
 	getBudget(budgetID string) (*Budget, error)
 	getCategories(budgetID string) ([]Category, error)
+	getCategory(budgetID, categoryID string) (*Category, error)
 	getTransactions(budgetID string, start, end time.Time) ([]Transaction, error)
 	getMonthCategories(budgetID string, year, month int) ([]Category, error)
 	getMonthCategoryActivity(budgetID string, year, month int) (map[string]int64, error)
+	getAccounts(budgetID string) ([]Account, error)
+	getScheduledTransactions(budgetID string) ([]ScheduledTransaction, error)
+	getCurrencyFormat(budgetID string) (money.Format, error)
+	getAgeOfMoney(budgetID string, year, month int) (*int, error)
 }
 
 type Client struct {
-	config  config.YNABConfig
-	fetcher dataFetcher
+	config   config.YNABConfig
+	fetcher  dataFetcher
+	recorder *metrics.Recorder
+	scope    *categoryScope // Categories fetched in quick paths; nil fetches all
 }
 
-func NewClient(ynabConfig config.YNABConfig) *Client {
-	return &Client{
-		config:  ynabConfig,
-		fetcher: &apiClient{client: ynab.NewClient(ynabConfig.APIToken)},
+// ClientOption configures optional Client behaviour
+type ClientOption func(*Client)
+
+// WithMetrics records the latency and outcome of every YNAB API call
+func WithMetrics(recorder *metrics.Recorder) ClientOption {
+	return func(c *Client) {
+		c.recorder = recorder
+	}
+}
+
+func NewClient(ynabConfig config.YNABConfig, opts ...ClientOption) *Client {
+	c := &Client{config: ynabConfig}
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	c.fetcher = &apiClient{client: newServicer(ynabConfig.APIToken, ynabConfig.ReadOnly, c.recorder)}
+
+	// Merge in a partner's budget, which may belong to a different YNAB account
+	if partner := ynabConfig.Partner; partner.BudgetID != "" {
+		token := partner.APIToken
+		if token == "" {
+			token = ynabConfig.APIToken
+		}
+		c.fetcher = &mergedFetcher{
+			primary:         c.fetcher,
+			partner:         &apiClient{client: newServicer(token, ynabConfig.ReadOnly, c.recorder)},
+			partnerBudgetID: partner.BudgetID,
+			categoryMap:     partner.CategoryMap,
+		}
 	}
+
+	return c
 }
 
 func (c *Client) GetWeeklyData(weekStart, weekEnd time.Time) (*WeeklyData, error) {
@@ -62,6 +102,36 @@ func (c *Client) GetWeeklyData(weekStart, weekEnd time.Time) (*WeeklyData, error
 	}, nil
 }
 
+// GetDailyData returns the current categories and the transactions dated
+// from weekStart through day, for the daily digest. Only the categories in
+// the client's scope are fetched when it has one.
+func (c *Client) GetDailyData(weekStart, day time.Time) (*DailyData, error) {
+	log.Printf("Fetching daily data for %s", day.Format("2006-01-02"))
+
+	budget, err := c.fetcher.getBudget(c.config.BudgetID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get budget: %w", err)
+	}
+
+	categories, err := c.getScopedCategories()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get categories: %w", err)
+	}
+
+	transactions, err := c.fetcher.getTransactions(c.config.BudgetID, weekStart, day)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get transactions: %w", err)
+	}
+
+	return &DailyData{
+		Budget:       budget,
+		Categories:   categories,
+		Transactions: transactions,
+		WeekStart:    weekStart,
+		Day:          day,
+	}, nil
+}
+
 func (c *Client) GetMonthlyData(year, month int) (*MonthlyData, error) {
 	monthStart := time.Date(year, time.Month(month), 1, 0, 0, 0, 0, time.UTC)
 	monthEnd := monthStart.AddDate(0, 1, -1)
@@ -88,11 +158,177 @@ func (c *Client) GetMonthlyData(year, month int) (*MonthlyData, error) {
 	}, nil
 }
 
+// GetPeriodData returns each month's category figures and the transactions
+// from start through end, for summaries over several months. start is
+// expected to be the first of a month.
+func (c *Client) GetPeriodData(start, end time.Time) (*PeriodData, error) {
+	log.Printf("Fetching data from %s to %s", start.Format("2006-01-02"), end.Format("2006-01-02"))
+
+	budget, err := c.fetcher.getBudget(c.config.BudgetID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get budget: %w", err)
+	}
+
+	var months [][]Category
+	for month := start; !month.After(end); month = month.AddDate(0, 1, 0) {
+		categories, err := c.fetcher.getMonthCategories(c.config.BudgetID, month.Year(), int(month.Month()))
+		if err != nil {
+			return nil, fmt.Errorf("failed to get categories for %s: %w", month.Format("January 2006"), err)
+		}
+		months = append(months, categories)
+	}
+
+	transactions, err := c.fetcher.getTransactions(c.config.BudgetID, start, end)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get transactions: %w", err)
+	}
+
+	return &PeriodData{
+		Budget:       budget,
+		Months:       months,
+		Transactions: transactions,
+		Start:        start,
+		End:          end,
+	}, nil
+}
+
+// GetMeetingData returns what the budget meeting agenda needs, with the
+// transactions dated from since through until
+func (c *Client) GetMeetingData(since, until time.Time) (*MeetingData, error) {
+	log.Printf("Fetching meeting data since %s", since.Format("2006-01-02"))
+
+	budget, err := c.fetcher.getBudget(c.config.BudgetID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get budget: %w", err)
+	}
+
+	categories, err := c.fetcher.getCategories(c.config.BudgetID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get categories: %w", err)
+	}
+
+	transactions, err := c.fetcher.getTransactions(c.config.BudgetID, since, until)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get transactions: %w", err)
+	}
+
+	scheduled, err := c.fetcher.getScheduledTransactions(c.config.BudgetID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get scheduled transactions: %w", err)
+	}
+
+	return &MeetingData{
+		Budget:       budget,
+		Categories:   categories,
+		Transactions: transactions,
+		Scheduled:    scheduled,
+		Since:        since,
+		Until:        until,
+	}, nil
+}
+
+// GetCategories returns the budget's current categories with their groups
+func (c *Client) GetCategories() ([]Category, error) {
+	categories, err := c.fetcher.getCategories(c.config.BudgetID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get categories: %w", err)
+	}
+	return categories, nil
+}
+
+// GetMonthCategories returns each category's budgeted, activity and balance
+// for a month. Groups are not filled in.
+func (c *Client) GetMonthCategories(year, month int) ([]Category, error) {
+	categories, err := c.fetcher.getMonthCategories(c.config.BudgetID, year, month)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get categories for %04d-%02d: %w", year, month, err)
+	}
+	return categories, nil
+}
+
 func (c *Client) GetPrevMonthCategorySpend(year, month int) (map[string]int64, error) {
 	log.Printf("Fetching category activity for %04d-%02d", year, month)
 	return c.fetcher.getMonthCategoryActivity(c.config.BudgetID, year, month)
 }
 
+// GetCategorySpend returns outflows per category name between start and end,
+// e.g. for the previous week's comparison baseline. Transfers to tracking
+// accounts, which are booked against a category, count only with transfers
+// set. Transactions flagged one of excludeFlags don't count.
+func (c *Client) GetCategorySpend(start, end time.Time, transfers bool, excludeFlags []string) (map[string]int64, error) {
+	log.Printf("Fetching category spend from %s to %s", start.Format("2006-01-02"), end.Format("2006-01-02"))
+
+	transactions, err := c.fetcher.getTransactions(c.config.BudgetID, start, end)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get transactions: %w", err)
+	}
+
+	spend := make(map[string]int64)
+	for _, tx := range transactions {
+		if tx.Deleted || tx.CategoryID == nil || tx.Amount >= 0 || (tx.TransferAccountID != nil && !transfers) {
+			continue
+		}
+		if tx.FlagColor != "" && slices.Contains(excludeFlags, tx.FlagColor) {
+			continue
+		}
+		spend[tx.CategoryName] += -tx.Amount
+	}
+	return spend, nil
+}
+
+// GetAccounts returns all open accounts in the budget
+func (c *Client) GetAccounts() ([]Account, error) {
+	accounts, err := c.fetcher.getAccounts(c.config.BudgetID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get accounts: %w", err)
+	}
+
+	var open []Account
+	for _, account := range accounts {
+		if !account.Closed {
+			open = append(open, account)
+		}
+	}
+	return open, nil
+}
+
+// GetScheduledTransactions returns the upcoming scheduled transactions in the budget
+func (c *Client) GetScheduledTransactions() ([]ScheduledTransaction, error) {
+	scheduled, err := c.fetcher.getScheduledTransactions(c.config.BudgetID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get scheduled transactions: %w", err)
+	}
+	return scheduled, nil
+}
+
+// GetTransactions returns the non-deleted transactions dated within [start, end]
+func (c *Client) GetTransactions(start, end time.Time) ([]Transaction, error) {
+	transactions, err := c.fetcher.getTransactions(c.config.BudgetID, start, end)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get transactions: %w", err)
+	}
+	return transactions, nil
+}
+
+// GetCurrency returns how the budget formats amounts
+func (c *Client) GetCurrency() (money.Format, error) {
+	format, err := c.fetcher.getCurrencyFormat(c.config.BudgetID)
+	if err != nil {
+		return money.Format{}, fmt.Errorf("failed to get budget settings: %w", err)
+	}
+	return format, nil
+}
+
+// GetAgeOfMoney returns YNAB's Age of Money for a month, in days, or nil when
+// the budget doesn't have one yet
+func (c *Client) GetAgeOfMoney(year, month int) (*int, error) {
+	days, err := c.fetcher.getAgeOfMoney(c.config.BudgetID, year, month)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get age of money for %04d-%02d: %w", year, month, err)
+	}
+	return days, nil
+}
+
 // apiClient is the real implementation of dataFetcher, delegating to the YNAB library.
 type apiClient struct {
 	client ynab.ClientServicer
@@ -101,29 +337,89 @@ type apiClient struct {
 func (a *apiClient) getBudget(budgetID string) (*Budget, error) {
 	budgetData, err := a.client.Budget().GetBudget(budgetID, nil)
 	if err != nil {
-		return nil, err
+		return nil, classifyError(err)
 	}
 
 	if budgetData == nil || budgetData.Budget == nil {
-		return nil, fmt.Errorf("no budget data returned")
+		return nil, fmt.Errorf("budget: %w", ErrNoData)
 	}
 
 	budget := &Budget{
-		ID:   budgetData.Budget.ID,
-		Name: budgetData.Budget.Name,
+		ID:       budgetData.Budget.ID,
+		Name:     budgetData.Budget.Name,
+		Currency: currencyFormat(budgetData.Budget.CurrencyFormat),
+	}
+
+	// The budget export already includes every transaction, so note when the
+	// data starts to tell a brand new budget apart from a quiet week
+	for _, t := range budgetData.Budget.Transactions {
+		if t == nil || t.Deleted {
+			continue
+		}
+		if budget.DataStart == nil || t.Date.Time.Before(*budget.DataStart) {
+			date := t.Date.Time
+			budget.DataStart = &date
+		}
 	}
 
 	return budget, nil
 }
 
+func (a *apiClient) getCurrencyFormat(budgetID string) (money.Format, error) {
+	settings, err := a.client.Budget().GetBudgetSettings(budgetID)
+	if err != nil {
+		return money.Format{}, classifyError(err)
+	}
+	if settings == nil {
+		return money.Format{}, fmt.Errorf("budget settings: %w", ErrNoData)
+	}
+	return currencyFormat(settings.CurrencyFormat), nil
+}
+
+func (a *apiClient) getAgeOfMoney(budgetID string, year, month int) (*int, error) {
+	date, err := api.DateFromString(fmt.Sprintf("%04d-%02d-01", year, month))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse month date: %w", err)
+	}
+	monthData, err := a.client.Month().GetMonth(budgetID, date)
+	if err != nil {
+		return nil, classifyError(err)
+	}
+	if monthData == nil {
+		return nil, fmt.Errorf("month: %w", ErrNoData)
+	}
+	if monthData.AgeOfMoney == nil {
+		return nil, nil
+	}
+	days := int(*monthData.AgeOfMoney)
+	return &days, nil
+}
+
+// currencyFormat converts YNAB's currency format, which some budgets don't
+// have, leaving the zero Format that formats as US dollars
+func currencyFormat(format *ynabbudget.CurrencyFormat) money.Format {
+	if format == nil {
+		return money.Format{}
+	}
+	return money.Format{
+		ISOCode:          format.ISOCode,
+		Symbol:           format.CurrencySymbol,
+		SymbolFirst:      format.SymbolFirst,
+		DisplaySymbol:    format.DisplaySymbol,
+		DecimalDigits:    int(format.DecimalDigits),
+		DecimalSeparator: format.DecimalSeparator,
+		GroupSeparator:   format.GroupSeparator,
+	}
+}
+
 func (a *apiClient) getCategories(budgetID string) ([]Category, error) {
 	categoriesData, err := a.client.Category().GetCategories(budgetID, nil)
 	if err != nil {
-		return nil, err
+		return nil, classifyError(err)
 	}
 
 	if categoriesData == nil {
-		return nil, fmt.Errorf("no categories data returned")
+		return nil, fmt.Errorf("categories: %w", ErrNoData)
 	}
 
 	var categories []Category
@@ -140,7 +436,14 @@ func (a *apiClient) getCategories(budgetID string) ([]Category, error) {
 					Deleted: group.Deleted,
 				},
 				Budgeted: cat.Budgeted,
+				Activity: cat.Activity,
 				Balance:  cat.Balance,
+				GoalType: goalType(cat.GoalType),
+				Hidden:   cat.Hidden,
+
+				GoalTarget:  ptrToInt64(cat.GoalTarget),
+				GoalPercent: goalPercent(cat.GoalPercentageComplete),
+				GoalMonth:   goalMonth(cat.GoalTargetMonth),
 			}
 			categories = append(categories, category)
 		}
@@ -149,6 +452,29 @@ func (a *apiClient) getCategories(budgetID string) ([]Category, error) {
 	return categories, nil
 }
 
+func (a *apiClient) getCategory(budgetID, categoryID string) (*Category, error) {
+	cat, err := a.client.Category().GetCategory(budgetID, categoryID)
+	if err != nil {
+		return nil, classifyError(err)
+	}
+	if cat == nil {
+		return nil, fmt.Errorf("category %s: %w", categoryID, ErrNoData)
+	}
+	return &Category{
+		ID:              cat.ID,
+		Name:            cat.Name,
+		CategoryGroupID: cat.CategoryGroupID,
+		Budgeted:        cat.Budgeted,
+		Activity:        cat.Activity,
+		Balance:         cat.Balance,
+		GoalType:        goalType(cat.GoalType),
+		GoalTarget:      ptrToInt64(cat.GoalTarget),
+		GoalPercent:     goalPercent(cat.GoalPercentageComplete),
+		GoalMonth:       goalMonth(cat.GoalTargetMonth),
+		Hidden:          cat.Hidden,
+	}, nil
+}
+
 func (a *apiClient) getTransactions(budgetID string, start, end time.Time) ([]Transaction, error) {
 	sinceDate, err := api.DateFromString(start.Format("2006-01-02"))
 	if err != nil {
@@ -161,11 +487,11 @@ func (a *apiClient) getTransactions(budgetID string, start, end time.Time) ([]Tr
 
 	transactionsData, err := a.client.Transaction().GetTransactions(budgetID, filter)
 	if err != nil {
-		return nil, err
+		return nil, classifyError(err)
 	}
 
 	if transactionsData == nil {
-		return nil, fmt.Errorf("no transactions data returned")
+		return nil, fmt.Errorf("transactions: %w", ErrNoData)
 	}
 
 	var transactions []Transaction
@@ -187,6 +513,13 @@ func (a *apiClient) getTransactions(budgetID string, start, end time.Time) ([]Tr
 			CategoryID:   t.CategoryID,
 			CategoryName: ptrToString(t.CategoryName),
 			Deleted:      t.Deleted,
+
+			Unapproved:        !t.Approved,
+			Uncleared:         t.Cleared == ynabtransaction.ClearingStatusUncleared,
+			TransferAccountID: t.TransferAccountID,
+		}
+		if t.FlagColor != nil {
+			transaction.FlagColor = string(*t.FlagColor)
 		}
 		transactions = append(transactions, transaction)
 	}
@@ -203,11 +536,11 @@ func (a *apiClient) getMonthCategories(budgetID string, year, month int) ([]Cate
 
 	monthData, err := a.client.Month().GetMonth(budgetID, date)
 	if err != nil {
-		return nil, err
+		return nil, classifyError(err)
 	}
 
 	if monthData == nil {
-		return nil, fmt.Errorf("no month data returned")
+		return nil, fmt.Errorf("month: %w", ErrNoData)
 	}
 
 	var categories []Category
@@ -221,11 +554,25 @@ func (a *apiClient) getMonthCategories(budgetID string, year, month int) ([]Cate
 			Budgeted: cat.Budgeted,
 			Activity: cat.Activity,
 			Balance:  cat.Balance,
+			GoalType: goalType(cat.GoalType),
+			Hidden:   cat.Hidden,
+
+			GoalTarget:  ptrToInt64(cat.GoalTarget),
+			GoalPercent: goalPercent(cat.GoalPercentageComplete),
+			GoalMonth:   goalMonth(cat.GoalTargetMonth),
 		})
 	}
 	return categories, nil
 }
 
+// goalType returns the category's YNAB goal type, or "" when it has no goal
+func goalType(goal *ynabcategory.Goal) string {
+	if goal == nil {
+		return ""
+	}
+	return string(*goal)
+}
+
 func (a *apiClient) getMonthCategoryActivity(budgetID string, year, month int) (map[string]int64, error) {
 	monthStr := fmt.Sprintf("%04d-%02d-01", year, month)
 	date, err := api.DateFromString(monthStr)
@@ -235,11 +582,11 @@ func (a *apiClient) getMonthCategoryActivity(budgetID string, year, month int) (
 
 	monthData, err := a.client.Month().GetMonth(budgetID, date)
 	if err != nil {
-		return nil, err
+		return nil, classifyError(err)
 	}
 
 	if monthData == nil {
-		return nil, fmt.Errorf("no month data returned")
+		return nil, fmt.Errorf("month: %w", ErrNoData)
 	}
 
 	result := make(map[string]int64)
@@ -252,6 +599,62 @@ func (a *apiClient) getMonthCategoryActivity(budgetID string, year, month int) (
 	return result, nil
 }
 
+func (a *apiClient) getAccounts(budgetID string) ([]Account, error) {
+	accountsData, err := a.client.Account().GetAccounts(budgetID, nil)
+	if err != nil {
+		return nil, classifyError(err)
+	}
+
+	if accountsData == nil {
+		return nil, fmt.Errorf("accounts: %w", ErrNoData)
+	}
+
+	var accounts []Account
+	for _, acc := range accountsData.Accounts {
+		if acc == nil || acc.Deleted {
+			continue
+		}
+		accounts = append(accounts, Account{
+			ID:               acc.ID,
+			Name:             acc.Name,
+			Type:             string(acc.Type),
+			OnBudget:         acc.OnBudget,
+			Closed:           acc.Closed,
+			Balance:          acc.Balance,
+			ClearedBalance:   acc.ClearedBalance,
+			UnclearedBalance: acc.UnclearedBalance,
+		})
+	}
+	return accounts, nil
+}
+
+func (a *apiClient) getScheduledTransactions(budgetID string) ([]ScheduledTransaction, error) {
+	scheduledData, err := a.client.Transaction().GetScheduledTransactions(budgetID)
+	if err != nil {
+		return nil, classifyError(err)
+	}
+
+	var scheduled []ScheduledTransaction
+	for _, st := range scheduledData {
+		if st == nil || st.Deleted {
+			continue
+		}
+		dateNext := st.DateNext.Time
+		scheduled = append(scheduled, ScheduledTransaction{
+			ID:                st.ID,
+			DateNext:          &dateNext,
+			Frequency:         string(st.Frequency),
+			Amount:            st.Amount,
+			AccountID:         st.AccountID,
+			AccountName:       st.AccountName,
+			PayeeName:         ptrToString(st.PayeeName),
+			CategoryName:      ptrToString(st.CategoryName),
+			TransferAccountID: st.TransferAccountID,
+		})
+	}
+	return scheduled, nil
+}
+
 // Helper functions to convert between types
 func ptrToString(s *string) string {
 	if s == nil {
@@ -260,3 +663,28 @@ func ptrToString(s *string) string {
 	return *s
 }
 
+func ptrToInt64(n *int64) int64 {
+	if n == nil {
+		return 0
+	}
+	return *n
+}
+
+// goalPercent returns how much of a category's goal is funded, or 0 without
+// a goal
+func goalPercent(percent *uint16) int {
+	if percent == nil {
+		return 0
+	}
+	return int(*percent)
+}
+
+// goalMonth returns when a category's goal is due, or nil when it has no
+// target date
+func goalMonth(month *api.Date) *time.Time {
+	if month == nil {
+		return nil
+	}
+	due := month.Time
+	return &due
+}
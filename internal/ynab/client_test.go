@@ -1,9 +1,14 @@
 package ynab
 
 import (
+	"errors"
 	"fmt"
 	"testing"
 	"time"
+
+	"github.com/brunomvsouza/ynab.go/api"
+	ynabbudget "github.com/brunomvsouza/ynab.go/api/budget"
+	"github.com/sathyabhat/ynab-weekly-wrap/internal/money"
 )
 
 // mockFetcher implements dataFetcher for unit tests.
@@ -18,6 +23,17 @@ type mockFetcher struct {
 	monthCategoriesErr error
 	transactionsErr  error
 	monthActivityErr error
+	accounts         []Account
+	accountsErr      error
+	scheduled        []ScheduledTransaction
+	scheduledErr     error
+	currency         money.Format
+	currencyErr      error
+	ageOfMoney       *int
+	ageOfMoneyErr    error
+	categoryErr      error
+	categoryCalls    int
+	categoriesCalls  int
 
 	// captured args
 	capturedBudgetID   string
@@ -33,9 +49,23 @@ func (m *mockFetcher) getBudget(budgetID string) (*Budget, error) {
 }
 
 func (m *mockFetcher) getCategories(budgetID string) ([]Category, error) {
+	m.categoriesCalls++
 	return m.categories, m.categoriesErr
 }
 
+func (m *mockFetcher) getCategory(budgetID, categoryID string) (*Category, error) {
+	m.categoryCalls++
+	if m.categoryErr != nil {
+		return nil, m.categoryErr
+	}
+	for _, cat := range m.categories {
+		if cat.ID == categoryID {
+			return &cat, nil
+		}
+	}
+	return nil, fmt.Errorf("category %s: %w", categoryID, ErrNoData)
+}
+
 func (m *mockFetcher) getTransactions(budgetID string, start, end time.Time) ([]Transaction, error) {
 	m.capturedStart = start
 	m.capturedEnd = end
@@ -52,6 +82,24 @@ func (m *mockFetcher) getMonthCategoryActivity(budgetID string, year, month int)
 	return m.monthActivity, m.monthActivityErr
 }
 
+func (m *mockFetcher) getAccounts(budgetID string) ([]Account, error) {
+	return m.accounts, m.accountsErr
+}
+
+func (m *mockFetcher) getScheduledTransactions(budgetID string) ([]ScheduledTransaction, error) {
+	return m.scheduled, m.scheduledErr
+}
+
+func (m *mockFetcher) getCurrencyFormat(budgetID string) (money.Format, error) {
+	return m.currency, m.currencyErr
+}
+
+func (m *mockFetcher) getAgeOfMoney(budgetID string, year, month int) (*int, error) {
+	m.capturedMonthYear = year
+	m.capturedMonthMonth = month
+	return m.ageOfMoney, m.ageOfMoneyErr
+}
+
 func newClientWithFetcher(budgetID string, f dataFetcher) *Client {
 	c := &Client{fetcher: f}
 	c.config.BudgetID = budgetID
@@ -220,3 +268,203 @@ func TestGetWeeklyData_PassesDateRange(t *testing.T) {
 		t.Errorf("WeekEnd: got %v, want %v", data.WeekEnd, weekEnd)
 	}
 }
+
+// ── GetDailyData ──────────────────────────────────────────────────────────────
+
+func TestGetDailyData_FetchesWeekToDate(t *testing.T) {
+	weekStart := time.Date(2026, 2, 16, 0, 0, 0, 0, time.UTC)
+	day := time.Date(2026, 2, 18, 0, 0, 0, 0, time.UTC)
+
+	mock := &mockFetcher{budget: testBudget(), categories: testCategories()}
+	c := newClientWithFetcher("b1", mock)
+
+	data, err := c.GetDailyData(weekStart, day)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if !mock.capturedStart.Equal(weekStart) || !mock.capturedEnd.Equal(day) {
+		t.Errorf("transactions fetched for %v to %v, want %v to %v", mock.capturedStart, mock.capturedEnd, weekStart, day)
+	}
+	if !data.Day.Equal(day) || len(data.Categories) != len(testCategories()) {
+		t.Errorf("unexpected data: %+v", data)
+	}
+}
+
+// ── GetPeriodData ─────────────────────────────────────────────────────────────
+
+func TestGetPeriodData_FetchesEachMonth(t *testing.T) {
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2026, 3, 31, 0, 0, 0, 0, time.UTC)
+
+	mock := &mockFetcher{budget: testBudget(), monthCategories: testCategories()}
+	c := newClientWithFetcher("b1", mock)
+
+	data, err := c.GetPeriodData(start, end)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(data.Months) != 3 {
+		t.Errorf("expected 3 months of categories, got %d", len(data.Months))
+	}
+	if mock.capturedMonthYear != 2026 || mock.capturedMonthMonth != 3 {
+		t.Errorf("expected March 2026 fetched last, got %04d-%02d", mock.capturedMonthYear, mock.capturedMonthMonth)
+	}
+	if !mock.capturedStart.Equal(start) || !mock.capturedEnd.Equal(end) {
+		t.Errorf("transactions fetched for %v to %v, want %v to %v", mock.capturedStart, mock.capturedEnd, start, end)
+	}
+}
+
+func TestGetMeetingData(t *testing.T) {
+	since := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	until := time.Date(2026, 1, 25, 0, 0, 0, 0, time.UTC)
+	rent := time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC)
+
+	mock := &mockFetcher{
+		budget:     testBudget(),
+		categories: testCategories(),
+		scheduled:  []ScheduledTransaction{{ID: "s1", DateNext: &rent, Amount: -2_000_000, PayeeName: "Landlord"}},
+	}
+	c := newClientWithFetcher("b1", mock)
+
+	data, err := c.GetMeetingData(since, until)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(data.Categories) != len(testCategories()) || len(data.Scheduled) != 1 {
+		t.Errorf("got %d categories and %d scheduled, want %d and 1", len(data.Categories), len(data.Scheduled), len(testCategories()))
+	}
+	if !mock.capturedStart.Equal(since) || !mock.capturedEnd.Equal(until) {
+		t.Errorf("transactions fetched for %v to %v, want %v to %v", mock.capturedStart, mock.capturedEnd, since, until)
+	}
+
+	mock.scheduledErr = errors.New("boom")
+	if _, err := c.GetMeetingData(since, until); err == nil {
+		t.Error("expected an error when scheduled transactions can't be fetched")
+	}
+}
+
+// ── GetCategorySpend ──────────────────────────────────────────────────────────
+
+func TestGetCategorySpend_SumsOutflowsByCategory(t *testing.T) {
+	catID := "c1"
+	mock := &mockFetcher{transactions: []Transaction{
+		{ID: "t1", Amount: -40_000, CategoryID: &catID, CategoryName: "Groceries"},
+		{ID: "t2", Amount: -10_000, CategoryID: &catID, CategoryName: "Groceries"},
+		{ID: "t3", Amount: 500_000, CategoryID: &catID, CategoryName: "Inflow"},
+		{ID: "t4", Amount: -99_000, CategoryName: "Transfer"}, // no category
+		{ID: "t5", Amount: -5_000, CategoryID: &catID, CategoryName: "Groceries", Deleted: true},
+		{ID: "t6", Amount: -300_000, CategoryID: &catID, CategoryName: "Retirement", TransferAccountID: new(string)}, // to a tracking account
+		{ID: "t7", Amount: -25_000, CategoryID: &catID, CategoryName: "Groceries", FlagColor: "purple"}, // to be reimbursed
+	}}
+	c := newClientWithFetcher("b1", mock)
+
+	spend, err := c.GetCategorySpend(time.Now().AddDate(0, 0, -14), time.Now().AddDate(0, 0, -7), false, []string{"purple"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(spend) != 1 || spend["Groceries"] != 50_000 {
+		t.Errorf("spend: got %v, want map[Groceries:50000]", spend)
+	}
+
+	spend, err = c.GetCategorySpend(time.Now().AddDate(0, 0, -14), time.Now().AddDate(0, 0, -7), true, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(spend) != 2 || spend["Retirement"] != 300_000 || spend["Groceries"] != 75_000 {
+		t.Errorf("spend: got %v, want the transfer and the flagged purchase included", spend)
+	}
+}
+
+func TestGetCategorySpend_Error(t *testing.T) {
+	mock := &mockFetcher{transactionsErr: fmt.Errorf("api down")}
+	c := newClientWithFetcher("b1", mock)
+
+	if _, err := c.GetCategorySpend(time.Now().AddDate(0, 0, -14), time.Now(), false, nil); err == nil {
+		t.Fatal("expected error, got nil")
+	}
+}
+
+// ── GetAccounts ───────────────────────────────────────────────────────────────
+
+func TestGetAccounts_SkipsClosedAccounts(t *testing.T) {
+	mock := &mockFetcher{accounts: []Account{
+		{ID: "a1", Name: "Checking", Type: "checking", Balance: 1_000_000},
+		{ID: "a2", Name: "Old Savings", Type: "savings", Closed: true},
+	}}
+	c := newClientWithFetcher("b1", mock)
+
+	accounts, err := c.GetAccounts()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(accounts) != 1 || accounts[0].ID != "a1" {
+		t.Errorf("expected only the open account, got %+v", accounts)
+	}
+}
+
+func TestGetAccounts_Error(t *testing.T) {
+	mock := &mockFetcher{accountsErr: fmt.Errorf("API error")}
+	c := newClientWithFetcher("b1", mock)
+
+	if _, err := c.GetAccounts(); err == nil {
+		t.Fatal("expected error, got nil")
+	}
+}
+
+func TestGetAgeOfMoney(t *testing.T) {
+	days := 42
+	mock := &mockFetcher{ageOfMoney: &days}
+	c := newClientWithFetcher("b1", mock)
+
+	got, err := c.GetAgeOfMoney(2026, 10)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got == nil || *got != 42 {
+		t.Errorf("expected 42 days, got %v", got)
+	}
+	if mock.capturedMonthYear != 2026 || mock.capturedMonthMonth != 10 {
+		t.Errorf("expected October 2026 to be fetched, got %d-%d", mock.capturedMonthYear, mock.capturedMonthMonth)
+	}
+
+	mock.ageOfMoneyErr = fmt.Errorf("API error")
+	if _, err := c.GetAgeOfMoney(2026, 10); err == nil {
+		t.Fatal("expected error, got nil")
+	}
+}
+
+func TestCurrencyFormat_MapsBudgetSettings(t *testing.T) {
+	format := currencyFormat(&ynabbudget.CurrencyFormat{
+		ISOCode:          "EUR",
+		CurrencySymbol:   "€",
+		DisplaySymbol:    true,
+		DecimalDigits:    2,
+		DecimalSeparator: ",",
+		GroupSeparator:   ".",
+	})
+
+	if got := format.Amount(-1_234_500); got != "-1.234,50€" {
+		t.Errorf("expected -1.234,50€, got %s", got)
+	}
+	if got := currencyFormat(nil).Amount(5_000); got != "$5" {
+		t.Errorf("expected a missing format to fall back to dollars, got %s", got)
+	}
+}
+
+// ── classifyError ─────────────────────────────────────────────────────────────
+
+func TestClassifyError_Unauthorized(t *testing.T) {
+	err := classifyError(&api.Error{ID: "401", Name: "unauthorized", Detail: "Unauthorized"})
+	if !errors.Is(err, ErrYNABAuth) {
+		t.Errorf("expected ErrYNABAuth, got %v", err)
+	}
+}
+
+func TestClassifyError_OtherErrorsUnchanged(t *testing.T) {
+	original := &api.Error{ID: "500", Name: "internal_server_error"}
+	if err := classifyError(original); err != original {
+		t.Errorf("expected original error, got %v", err)
+	}
+}
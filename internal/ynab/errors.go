@@ -0,0 +1,25 @@
+package ynab
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/brunomvsouza/ynab.go/api"
+)
+
+var (
+	// ErrYNABAuth is returned when YNAB rejects the API token
+	ErrYNABAuth = errors.New("YNAB authentication failed")
+	// ErrNoData is returned when YNAB responds without the requested data
+	ErrNoData = errors.New("no data returned")
+)
+
+// classifyError maps YNAB API errors onto the package's error values so
+// callers can use errors.Is instead of inspecting status codes
+func classifyError(err error) error {
+	var apiErr *api.Error
+	if errors.As(err, &apiErr) && apiErr.ID == "401" {
+		return fmt.Errorf("%w: %s", ErrYNABAuth, apiErr.Detail)
+	}
+	return err
+}
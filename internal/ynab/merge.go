@@ -0,0 +1,175 @@
+package ynab
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/sathyabhat/ynab-weekly-wrap/internal/money"
+)
+
+// mergedFetcher combines a household's two budgets so the rest of the app
+// sees one. Partner categories are renamed through categoryMap onto the
+// primary budget's names, and categories with the same name are added
+// together, so the analyzer reports them as one.
+type mergedFetcher struct {
+	primary         dataFetcher
+	partner         dataFetcher
+	partnerBudgetID string
+	categoryMap     map[string]string // Partner category name -> primary category name
+}
+
+func (m *mergedFetcher) categoryName(name string) string {
+	if mapped, ok := m.categoryMap[name]; ok {
+		return mapped
+	}
+	return name
+}
+
+func (m *mergedFetcher) getBudget(budgetID string) (*Budget, error) {
+	primary, err := m.primary.getBudget(budgetID)
+	if err != nil {
+		return nil, err
+	}
+	partner, err := m.partner.getBudget(m.partnerBudgetID)
+	if err != nil {
+		return nil, fmt.Errorf("partner budget: %w", err)
+	}
+
+	merged := *primary
+	merged.Name = primary.Name + " + " + partner.Name
+	// Comparisons need data in both budgets, so the household's data starts
+	// when the newer budget's does
+	if partner.DataStart != nil && (merged.DataStart == nil || partner.DataStart.After(*merged.DataStart)) {
+		merged.DataStart = partner.DataStart
+	}
+	return &merged, nil
+}
+
+func (m *mergedFetcher) getCategories(budgetID string) ([]Category, error) {
+	primary, err := m.primary.getCategories(budgetID)
+	if err != nil {
+		return nil, err
+	}
+	partner, err := m.partner.getCategories(m.partnerBudgetID)
+	if err != nil {
+		return nil, fmt.Errorf("partner budget: %w", err)
+	}
+	return m.mergeCategories(primary, partner), nil
+}
+
+// getCategory isn't supported: a category's figures span both budgets
+func (m *mergedFetcher) getCategory(budgetID, categoryID string) (*Category, error) {
+	return nil, errors.New("single categories can't be fetched from merged budgets")
+}
+
+func (m *mergedFetcher) getMonthCategories(budgetID string, year, month int) ([]Category, error) {
+	primary, err := m.primary.getMonthCategories(budgetID, year, month)
+	if err != nil {
+		return nil, err
+	}
+	partner, err := m.partner.getMonthCategories(m.partnerBudgetID, year, month)
+	if err != nil {
+		return nil, fmt.Errorf("partner budget: %w", err)
+	}
+	return m.mergeCategories(primary, partner), nil
+}
+
+// mergeCategories adds each partner category onto the primary category with
+// the same (mapped) name, keeping the primary's ID and group. Partner
+// categories without a match are appended under their mapped name.
+func (m *mergedFetcher) mergeCategories(primary, partner []Category) []Category {
+	merged := append([]Category(nil), primary...)
+	index := make(map[string]int, len(merged))
+	for i, cat := range merged {
+		index[cat.Name] = i
+	}
+
+	for _, cat := range partner {
+		cat.Name = m.categoryName(cat.Name)
+		if i, ok := index[cat.Name]; ok {
+			merged[i].Budgeted += cat.Budgeted
+			merged[i].Activity += cat.Activity
+			merged[i].Balance += cat.Balance
+			continue
+		}
+		index[cat.Name] = len(merged)
+		merged = append(merged, cat)
+	}
+	return merged
+}
+
+func (m *mergedFetcher) getTransactions(budgetID string, start, end time.Time) ([]Transaction, error) {
+	primary, err := m.primary.getTransactions(budgetID, start, end)
+	if err != nil {
+		return nil, err
+	}
+	partner, err := m.partner.getTransactions(m.partnerBudgetID, start, end)
+	if err != nil {
+		return nil, fmt.Errorf("partner budget: %w", err)
+	}
+
+	for i := range partner {
+		partner[i].CategoryName = m.categoryName(partner[i].CategoryName)
+	}
+	return append(primary, partner...), nil
+}
+
+func (m *mergedFetcher) getMonthCategoryActivity(budgetID string, year, month int) (map[string]int64, error) {
+	primary, err := m.primary.getMonthCategoryActivity(budgetID, year, month)
+	if err != nil {
+		return nil, err
+	}
+	partner, err := m.partner.getMonthCategoryActivity(m.partnerBudgetID, year, month)
+	if err != nil {
+		return nil, fmt.Errorf("partner budget: %w", err)
+	}
+
+	merged := make(map[string]int64, len(primary)+len(partner))
+	for name, spent := range primary {
+		merged[name] += spent
+	}
+	for name, spent := range partner {
+		merged[m.categoryName(name)] += spent
+	}
+	return merged, nil
+}
+
+func (m *mergedFetcher) getAccounts(budgetID string) ([]Account, error) {
+	primary, err := m.primary.getAccounts(budgetID)
+	if err != nil {
+		return nil, err
+	}
+	partner, err := m.partner.getAccounts(m.partnerBudgetID)
+	if err != nil {
+		return nil, fmt.Errorf("partner budget: %w", err)
+	}
+	return append(primary, partner...), nil
+}
+
+func (m *mergedFetcher) getScheduledTransactions(budgetID string) ([]ScheduledTransaction, error) {
+	primary, err := m.primary.getScheduledTransactions(budgetID)
+	if err != nil {
+		return nil, err
+	}
+	partner, err := m.partner.getScheduledTransactions(m.partnerBudgetID)
+	if err != nil {
+		return nil, fmt.Errorf("partner budget: %w", err)
+	}
+	for i := range partner {
+		partner[i].CategoryName = m.categoryName(partner[i].CategoryName)
+	}
+	return append(primary, partner...), nil
+}
+
+// getCurrencyFormat uses the primary budget's format; the partner's amounts
+// are added as they are, so both budgets are assumed to share a currency
+func (m *mergedFetcher) getCurrencyFormat(budgetID string) (money.Format, error) {
+	return m.primary.getCurrencyFormat(budgetID)
+}
+
+// getAgeOfMoney uses the primary budget's Age of Money, as money can't be
+// aged across two budgets
+func (m *mergedFetcher) getAgeOfMoney(budgetID string, year, month int) (*int, error) {
+	return m.primary.getAgeOfMoney(budgetID, year, month)
+}
@@ -0,0 +1,85 @@
+package ynab
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func newMergedClient(primary, partner *mockFetcher) *Client {
+	return newClientWithFetcher("b1", &mergedFetcher{
+		primary:         primary,
+		partner:         partner,
+		partnerBudgetID: "b2",
+		categoryMap:     map[string]string{"Food": "Groceries"},
+	})
+}
+
+func TestMergedFetcher_WeeklyData(t *testing.T) {
+	early := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	late := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	catID := "p1"
+	primary := &mockFetcher{
+		budget:       &Budget{ID: "b1", Name: "Alex", DataStart: &early},
+		categories:   testCategories(),
+		transactions: []Transaction{{ID: "t1", Amount: -10_000, CategoryName: "Groceries"}},
+	}
+	partner := &mockFetcher{
+		budget: &Budget{ID: "b2", Name: "Sam", DataStart: &late},
+		categories: []Category{
+			{ID: catID, Name: "Food", Budgeted: 300_000, Balance: 100_000},
+			{ID: "p2", Name: "Hobbies", Budgeted: 50_000},
+		},
+		transactions: []Transaction{{ID: "t2", Amount: -20_000, CategoryID: &catID, CategoryName: "Food"}},
+	}
+
+	data, err := newMergedClient(primary, partner).GetWeeklyData(time.Now().AddDate(0, 0, -7), time.Now())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if partner.capturedBudgetID != "b2" {
+		t.Errorf("partner budget ID: got %q, want b2", partner.capturedBudgetID)
+	}
+	if data.Budget.Name != "Alex + Sam" || !data.Budget.DataStart.Equal(late) {
+		t.Errorf("budget: got %+v", data.Budget)
+	}
+
+	if len(data.Categories) != 3 {
+		t.Fatalf("categories: got %d, want 3", len(data.Categories))
+	}
+	groceries := data.Categories[0]
+	if groceries.ID != "c1" || groceries.Budgeted != 800_000 || groceries.Balance != 400_000 {
+		t.Errorf("expected Food to merge into Groceries, got %+v", groceries)
+	}
+	if data.Categories[2].Name != "Hobbies" {
+		t.Errorf("expected unmatched partner category to be kept, got %+v", data.Categories[2])
+	}
+
+	if len(data.Transactions) != 2 || data.Transactions[1].CategoryName != "Groceries" {
+		t.Errorf("transactions: got %+v", data.Transactions)
+	}
+}
+
+func TestMergedFetcher_MonthActivity(t *testing.T) {
+	primary := &mockFetcher{monthActivity: map[string]int64{"Groceries": 100_000}}
+	partner := &mockFetcher{monthActivity: map[string]int64{"Food": 40_000, "Hobbies": 5_000}}
+
+	spend, err := newMergedClient(primary, partner).GetPrevMonthCategorySpend(2026, 2)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if spend["Groceries"] != 140_000 || spend["Hobbies"] != 5_000 {
+		t.Errorf("spend: got %v", spend)
+	}
+}
+
+func TestMergedFetcher_PartnerErrorFails(t *testing.T) {
+	primary := &mockFetcher{accounts: []Account{{ID: "a1"}}}
+	partner := &mockFetcher{accountsErr: ErrYNABAuth}
+
+	_, err := newMergedClient(primary, partner).GetAccounts()
+	if !errors.Is(err, ErrYNABAuth) {
+		t.Errorf("expected partner error to be returned, got %v", err)
+	}
+}
@@ -2,11 +2,15 @@ package ynab
 
 import (
 	"time"
+
+	"github.com/sathyabhat/ynab-weekly-wrap/internal/money"
 )
 
 type Budget struct {
-	ID   string `json:"id"`
-	Name string `json:"name"`
+	ID        string       `json:"id"`
+	Name      string       `json:"name"`
+	DataStart *time.Time   `json:"data_start"` // Date of the earliest transaction in the budget (nil if unknown)
+	Currency  money.Format `json:"currency"`   // How the budget formats amounts
 }
 
 type Category struct {
@@ -17,6 +21,11 @@ type Category struct {
 	Budgeted        int64         `json:"budgeted"`
 	Activity        int64         `json:"activity"` // total spend for the month in milliunits (negative = spending)
 	Balance         int64         `json:"balance"`
+	GoalType        string        `json:"goal_type,omitempty"`    // YNAB goal type (TB, TBD, MF, NEED, DEBT), empty without a goal
+	GoalTarget      int64         `json:"goal_target,omitempty"`  // Goal amount in milliunits
+	GoalPercent     int           `json:"goal_percent,omitempty"` // How much of the goal is funded, 0-100
+	GoalMonth       *time.Time    `json:"goal_month,omitempty"`   // When a goal with a target date is due
+	Hidden          bool          `json:"hidden,omitempty"`
 }
 
 type CategoryGroup struct {
@@ -38,21 +47,99 @@ type Transaction struct {
 	CategoryID   *string    `json:"category_id"`
 	CategoryName string     `json:"category_name"`
 	Deleted      bool       `json:"deleted"`
+	// Stored as unapproved rather than approved so snapshots saved before it
+	// read as approved
+	Unapproved        bool    `json:"unapproved,omitempty"`
+	Uncleared         bool    `json:"uncleared,omitempty"`           // Not yet cleared by the bank
+	TransferAccountID *string `json:"transfer_account_id,omitempty"` // Set for transfers between accounts
+	FlagColor         string  `json:"flag_color,omitempty"`          // red, orange, yellow, green, blue or purple; "" when unflagged
+}
+
+type Account struct {
+	ID               string `json:"id"`
+	Name             string `json:"name"`
+	Type             string `json:"type"` // checking, savings, cash, creditCard, ...
+	OnBudget         bool   `json:"on_budget"`
+	Closed           bool   `json:"closed"`
+	Balance          int64  `json:"balance"`
+	ClearedBalance   int64  `json:"cleared_balance"`
+	UnclearedBalance int64  `json:"uncleared_balance"`
+}
+
+type ScheduledTransaction struct {
+	ID                string     `json:"id"`
+	DateNext          *time.Time `json:"date_next"`
+	Frequency         string     `json:"frequency"` // never, daily, weekly, everyOtherWeek, monthly, ...
+	Amount            int64      `json:"amount"`
+	AccountID         string     `json:"account_id"`
+	AccountName       string     `json:"account_name"`
+	PayeeName         string     `json:"payee_name"`
+	CategoryName      string     `json:"category_name"`
+	TransferAccountID *string    `json:"transfer_account_id"`
 }
 
 type WeeklyData struct {
 	Budget       *Budget
 	Categories   []Category
 	Transactions []Transaction
+	MonthToDate  []Transaction          // Transactions since the start of a non-calendar budget month, or any month for the savings rate
+	Scheduled    []ScheduledTransaction // Upcoming scheduled transactions, for the safe-to-spend forecast
+	Accounts     []Account              // Open accounts, for the data confidence footer; nil when unknown
+	Baseline     map[string]int64       // Spending per category over the weeks before, for averages; nil when unknown
+	PastBills    []Transaction          // Utility bills of the year before, for spotting spikes; nil unless configured
+	Groceries    []Transaction          // Grocery trips over the months before, for the trip trend; nil unless configured
+	Unapproved   []Transaction          // Transactions still to be approved, for action items; nil unless configured
+	AgeOfMoney   *int                   // YNAB's Age of Money in days for the week's month; nil when unknown
+	Validation   *Validation            // Inconsistent records repaired or left out; nil when there were none
 	WeekStart    time.Time
 	WeekEnd      time.Time
 }
 
+// DailyData is what the daily digest needs: the current categories and the
+// week's transactions up to and including Day
+type DailyData struct {
+	Budget       *Budget
+	Categories   []Category
+	Transactions []Transaction // From WeekStart through Day
+	WeekStart    time.Time
+	Day          time.Time
+}
+
+// PeriodData is what a quarterly or yearly summary needs: each month's
+// category figures and the transactions across the period
+type PeriodData struct {
+	Budget       *Budget
+	Months       [][]Category // One per month from Start, in order
+	Transactions []Transaction
+	Start        time.Time
+	End          time.Time // Last day of the period
+}
+
+// MeetingData is what the budget meeting agenda needs: the current
+// categories, recent transactions to find those still to be approved or
+// categorized, and the upcoming scheduled transactions
+type MeetingData struct {
+	Budget       *Budget
+	Categories   []Category
+	Transactions []Transaction // From Since through Until
+	Scheduled    []ScheduledTransaction
+	Since        time.Time
+	Until        time.Time
+}
+
 type MonthlyData struct {
 	Budget       *Budget
 	Categories   []Category
 	Transactions []Transaction
 	MonthStart   time.Time
 	MonthEnd     time.Time
+
+	// For the category tidy-up check: the current categories with their
+	// groups, and each category's figures for the months before MonthStart,
+	// most recent first
+	Catalog    []Category
+	PastMonths [][]Category
+
+	Validation *Validation // Inconsistent records repaired or left out; nil when there were none
 }
 
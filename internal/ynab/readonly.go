@@ -0,0 +1,99 @@
+package ynab
+
+import (
+	"errors"
+
+	"github.com/brunomvsouza/ynab.go"
+	"github.com/brunomvsouza/ynab.go/api"
+	"github.com/brunomvsouza/ynab.go/api/account"
+	ynabbudget "github.com/brunomvsouza/ynab.go/api/budget"
+	ynabcategory "github.com/brunomvsouza/ynab.go/api/category"
+	"github.com/brunomvsouza/ynab.go/api/month"
+	"github.com/brunomvsouza/ynab.go/api/payee"
+	ynabtransaction "github.com/brunomvsouza/ynab.go/api/transaction"
+	"github.com/brunomvsouza/ynab.go/api/user"
+	"github.com/sathyabhat/ynab-weekly-wrap/internal/metrics"
+)
+
+// ErrReadOnly is returned for any YNAB write attempted in read-only mode
+var ErrReadOnly = errors.New("YNAB writes are disabled in read-only mode")
+
+// readOnlyTransport passes reads through to the YNAB API and refuses every
+// write, so no feature can change the budget however it is configured
+type readOnlyTransport struct {
+	api.ClientReader
+}
+
+func (readOnlyTransport) POST(url string, responseModel interface{}, requestBody []byte) error {
+	return ErrReadOnly
+}
+
+func (readOnlyTransport) PUT(url string, responseModel interface{}, requestBody []byte) error {
+	return ErrReadOnly
+}
+
+func (readOnlyTransport) PATCH(url string, responseModel interface{}, requestBody []byte) error {
+	return ErrReadOnly
+}
+
+func (readOnlyTransport) DELETE(url string, responseModel interface{}) error {
+	return ErrReadOnly
+}
+
+// transportServicer is a ynab.ClientServicer whose services all go through
+// a transport wrapping the library's client, to refuse writes or time calls
+type transportServicer struct {
+	user        *user.Service
+	budget      *ynabbudget.Service
+	account     *account.Service
+	category    *ynabcategory.Service
+	payee       *payee.Service
+	month       *month.Service
+	transaction *ynabtransaction.Service
+}
+
+func newTransportServicer(transport api.ClientReaderWriter) ynab.ClientServicer {
+	return &transportServicer{
+		user:        user.NewService(transport),
+		budget:      ynabbudget.NewService(transport),
+		account:     account.NewService(transport),
+		category:    ynabcategory.NewService(transport),
+		payee:       payee.NewService(transport),
+		month:       month.NewService(transport),
+		transaction: ynabtransaction.NewService(transport),
+	}
+}
+
+// newReadOnlyServicer wraps reader, usually the library's client, so only
+// its reads reach the API
+func newReadOnlyServicer(reader api.ClientReader) ynab.ClientServicer {
+	return newTransportServicer(readOnlyTransport{reader})
+}
+
+func (s *transportServicer) User() *user.Service                   { return s.user }
+func (s *transportServicer) Budget() *ynabbudget.Service           { return s.budget }
+func (s *transportServicer) Account() *account.Service             { return s.account }
+func (s *transportServicer) Category() *ynabcategory.Service       { return s.category }
+func (s *transportServicer) Payee() *payee.Service                 { return s.payee }
+func (s *transportServicer) Month() *month.Service                 { return s.month }
+func (s *transportServicer) Transaction() *ynabtransaction.Service { return s.transaction }
+
+// newServicer creates the YNAB library client for a token, read-only when
+// requested and timing its calls when a recorder is given
+func newServicer(token string, readOnly bool, recorder *metrics.Recorder) ynab.ClientServicer {
+	client := ynab.NewClient(token)
+	if !readOnly && recorder == nil {
+		return client
+	}
+	transport, ok := client.(api.ClientReaderWriter)
+	if !ok {
+		panic("ynab: the YNAB client's transport can't be wrapped")
+	}
+	if recorder != nil {
+		transport = timedTransport{next: transport, recorder: recorder}
+	}
+	if readOnly {
+		return newReadOnlyServicer(transport)
+	}
+	return newTransportServicer(transport)
+}
@@ -0,0 +1,89 @@
+package ynab
+
+import (
+	"errors"
+	"testing"
+
+	ynabtransaction "github.com/brunomvsouza/ynab.go/api/transaction"
+	"github.com/sathyabhat/ynab-weekly-wrap/internal/metrics"
+)
+
+// recordingReader stands in for the YNAB API, recording the URLs read
+type recordingReader struct {
+	urls []string
+}
+
+func (r *recordingReader) GET(url string, responseModel interface{}) error {
+	r.urls = append(r.urls, url)
+	return nil
+}
+
+func TestReadOnlyServicer_RefusesWrites(t *testing.T) {
+	reader := &recordingReader{}
+	client := newReadOnlyServicer(reader)
+
+	if _, err := client.Transaction().GetTransactions("b1", nil); err != nil {
+		t.Fatalf("reads should pass through, got %v", err)
+	}
+	if len(reader.urls) != 1 || reader.urls[0] != "/budgets/b1/transactions" {
+		t.Errorf("expected the read to reach the API, got %v", reader.urls)
+	}
+
+	_, err := client.Transaction().CreateTransaction("b1", ynabtransaction.PayloadTransaction{})
+	if !errors.Is(err, ErrReadOnly) {
+		t.Errorf("expected ErrReadOnly for a write, got %v", err)
+	}
+	if _, err := client.Transaction().DeleteTransaction("b1", "t1"); !errors.Is(err, ErrReadOnly) {
+		t.Errorf("expected ErrReadOnly for a delete, got %v", err)
+	}
+	if len(reader.urls) != 1 {
+		t.Errorf("writes should never reach the API, got %v", reader.urls)
+	}
+}
+
+func TestNewServicer_ReadOnly(t *testing.T) {
+	if _, ok := newServicer("token", true, nil).(*transportServicer); !ok {
+		t.Error("expected a read-only client when read-only is set")
+	}
+	if _, ok := newServicer("token", false, nil).(*transportServicer); ok {
+		t.Error("expected the library client when read-only is not set")
+	}
+}
+
+// failingWriter stands in for the YNAB API, failing every call
+type failingWriter struct {
+	recordingReader
+}
+
+func (failingWriter) POST(url string, responseModel interface{}, requestBody []byte) error {
+	return errors.New("boom")
+}
+
+func (failingWriter) PUT(url string, responseModel interface{}, requestBody []byte) error {
+	return errors.New("boom")
+}
+
+func (failingWriter) PATCH(url string, responseModel interface{}, requestBody []byte) error {
+	return errors.New("boom")
+}
+
+func (failingWriter) DELETE(url string, responseModel interface{}) error {
+	return errors.New("boom")
+}
+
+func TestTimedTransport_RecordsCalls(t *testing.T) {
+	recorder := metrics.NewRecorder()
+	client := newTransportServicer(timedTransport{next: &failingWriter{}, recorder: recorder})
+
+	if _, err := client.Transaction().GetTransactions("b1", nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := client.Transaction().DeleteTransaction("b1", "t1"); err == nil {
+		t.Fatal("expected the write to fail")
+	}
+
+	api := recorder.Status().APIs["ynab"]
+	if api.Calls != 2 || api.ErrorRate != 0.5 {
+		t.Errorf("expected 2 calls with half failing, got %+v", api)
+	}
+}
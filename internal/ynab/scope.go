@@ -0,0 +1,98 @@
+package ynab
+
+import (
+	"log"
+	"strings"
+	"sync"
+)
+
+// maxScopedCategories is the most categories fetched one at a time. Past
+// this, a single fetch of every category costs fewer API requests.
+const maxScopedCategories = 10
+
+// categoryScope limits the categories fetched in quick paths, such as the
+// daily digest, to the ones named in the configuration. The first fetch
+// loads every category to find the named ones; later fetches ask YNAB for
+// just those.
+type categoryScope struct {
+	names map[string]bool // Category or group names, lowercased
+
+	mu       sync.Mutex
+	resolved []Category // Named categories with their groups; nil until resolved
+}
+
+func newCategoryScope(names []string) *categoryScope {
+	scope := &categoryScope{names: make(map[string]bool, len(names))}
+	for _, name := range names {
+		scope.names[strings.ToLower(strings.TrimSpace(name))] = true
+	}
+	return scope
+}
+
+// includes reports whether a category or its group is named in the scope
+func (s *categoryScope) includes(cat Category) bool {
+	return s.names[strings.ToLower(cat.Name)] || (cat.CategoryGroup.Name != "" && s.names[strings.ToLower(cat.CategoryGroup.Name)])
+}
+
+// WithCategoryScope fetches only the named categories, or the categories in
+// the named groups, in quick paths such as the daily digest, so they stay
+// fast on budgets with hundreds of categories. Wraps still fetch every
+// category. Budgets merged with a partner's always fetch every category.
+func WithCategoryScope(names []string) ClientOption {
+	return func(c *Client) {
+		if len(names) > 0 {
+			c.scope = newCategoryScope(names)
+		}
+	}
+}
+
+// getScopedCategories returns the categories in the client's scope, or
+// every category when it has none. Anything unexpected, such as a named
+// category that has since been deleted, falls back to fetching every
+// category and finding the named ones again.
+func (c *Client) getScopedCategories() ([]Category, error) {
+	if c.scope == nil || c.config.Partner.BudgetID != "" {
+		return c.fetcher.getCategories(c.config.BudgetID)
+	}
+	c.scope.mu.Lock()
+	defer c.scope.mu.Unlock()
+
+	if c.scope.resolved != nil && len(c.scope.resolved) <= maxScopedCategories {
+		categories, err := c.fetchEach(c.scope.resolved)
+		if err == nil {
+			return categories, nil
+		}
+		log.Printf("Warning: could not fetch scoped categories, fetching all: %v", err)
+	}
+
+	all, err := c.fetcher.getCategories(c.config.BudgetID)
+	if err != nil {
+		return nil, err
+	}
+	resolved := []Category{}
+	for _, cat := range all {
+		if c.scope.includes(cat) {
+			resolved = append(resolved, cat)
+		}
+	}
+	c.scope.resolved = resolved
+	if len(resolved) > maxScopedCategories {
+		log.Printf("Category scope covers %d categories, fetching all of them each time", len(resolved))
+	}
+	return resolved, nil
+}
+
+// fetchEach fetches the current figures of each category, keeping the
+// groups they were resolved with
+func (c *Client) fetchEach(categories []Category) ([]Category, error) {
+	fetched := make([]Category, 0, len(categories))
+	for _, cat := range categories {
+		current, err := c.fetcher.getCategory(c.config.BudgetID, cat.ID)
+		if err != nil {
+			return nil, err
+		}
+		current.CategoryGroup = cat.CategoryGroup
+		fetched = append(fetched, *current)
+	}
+	return fetched, nil
+}
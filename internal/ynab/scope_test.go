@@ -0,0 +1,71 @@
+package ynab
+
+import (
+	"errors"
+	"testing"
+)
+
+func scopedCategories() []Category {
+	return []Category{
+		{ID: "c1", Name: "Groceries", Balance: 300_000, CategoryGroup: CategoryGroup{Name: "Everyday"}},
+		{ID: "c2", Name: "Transport", Balance: 50_000, CategoryGroup: CategoryGroup{Name: "Everyday"}},
+		{ID: "c3", Name: "Rent", Balance: 0, CategoryGroup: CategoryGroup{Name: "Bills"}},
+	}
+}
+
+func TestGetScopedCategories_FetchesNamedCategoriesOnly(t *testing.T) {
+	mock := &mockFetcher{categories: scopedCategories()}
+	c := newClientWithFetcher("b1", mock)
+	WithCategoryScope([]string{"everyday"})(c)
+
+	first, err := c.getScopedCategories()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(first) != 2 || mock.categoriesCalls != 1 {
+		t.Fatalf("first fetch: got %d categories after %d full fetches, want 2 after 1", len(first), mock.categoriesCalls)
+	}
+
+	mock.categories[0].Balance = 100_000
+	second, err := c.getScopedCategories()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if mock.categoriesCalls != 1 || mock.categoryCalls != 2 {
+		t.Errorf("expected the named categories fetched singly, got %d full and %d single fetches", mock.categoriesCalls, mock.categoryCalls)
+	}
+	if second[0].Balance != 100_000 || second[0].CategoryGroup.Name != "Everyday" {
+		t.Errorf("expected current figures with the group kept, got %+v", second[0])
+	}
+}
+
+func TestGetScopedCategories_FallsBackToFullFetch(t *testing.T) {
+	mock := &mockFetcher{categories: scopedCategories()}
+	c := newClientWithFetcher("b1", mock)
+	WithCategoryScope([]string{"Rent"})(c)
+
+	if _, err := c.getScopedCategories(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	mock.categoryErr = errors.New("category deleted")
+	categories, err := c.getScopedCategories()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(categories) != 1 || mock.categoriesCalls != 2 {
+		t.Errorf("expected a full fetch after the single fetch failed, got %d categories after %d full fetches", len(categories), mock.categoriesCalls)
+	}
+}
+
+func TestGetScopedCategories_WithoutScope(t *testing.T) {
+	mock := &mockFetcher{categories: scopedCategories()}
+	c := newClientWithFetcher("b1", mock)
+
+	categories, err := c.getScopedCategories()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(categories) != 3 || mock.categoryCalls != 0 {
+		t.Errorf("expected every category in one fetch, got %d categories and %d single fetches", len(categories), mock.categoryCalls)
+	}
+}
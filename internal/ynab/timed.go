@@ -0,0 +1,45 @@
+package ynab
+
+import (
+	"time"
+
+	"github.com/brunomvsouza/ynab.go/api"
+	"github.com/sathyabhat/ynab-weekly-wrap/internal/metrics"
+)
+
+// timedTransport records how long each YNAB API call takes and whether it
+// failed, for the status endpoint
+type timedTransport struct {
+	next     api.ClientReaderWriter
+	recorder *metrics.Recorder
+}
+
+func (t timedTransport) observe(start time.Time, err error) error {
+	t.recorder.ObserveCall("ynab", time.Since(start), err)
+	return err
+}
+
+func (t timedTransport) GET(url string, responseModel interface{}) error {
+	start := time.Now()
+	return t.observe(start, t.next.GET(url, responseModel))
+}
+
+func (t timedTransport) POST(url string, responseModel interface{}, requestBody []byte) error {
+	start := time.Now()
+	return t.observe(start, t.next.POST(url, responseModel, requestBody))
+}
+
+func (t timedTransport) PUT(url string, responseModel interface{}, requestBody []byte) error {
+	start := time.Now()
+	return t.observe(start, t.next.PUT(url, responseModel, requestBody))
+}
+
+func (t timedTransport) PATCH(url string, responseModel interface{}, requestBody []byte) error {
+	start := time.Now()
+	return t.observe(start, t.next.PATCH(url, responseModel, requestBody))
+}
+
+func (t timedTransport) DELETE(url string, responseModel interface{}) error {
+	start := time.Now()
+	return t.observe(start, t.next.DELETE(url, responseModel))
+}
@@ -0,0 +1,87 @@
+package ynab
+
+import (
+	"log"
+	"time"
+)
+
+// Validation counts the inconsistent records found in fetched data, each
+// repaired or left out before it reaches the analysis
+type Validation struct {
+	// Transactions whose category isn't in the budget, e.g. one deleted
+	// since; they are counted as uncategorized
+	UnknownCategory int `json:"unknown_category,omitempty"`
+	// Categories budgeted below zero, which would turn percentages upside
+	// down; they are counted as budgeted at zero
+	NegativeBudgeted int `json:"negative_budgeted,omitempty"`
+	// Transactions dated after today, entered ahead of time; they are left
+	// out until their date comes
+	FutureDated int `json:"future_dated,omitempty"`
+}
+
+// Empty reports whether nothing needed repairing
+func (v *Validation) Empty() bool {
+	return v == nil || *v == Validation{}
+}
+
+// Validate checks the week's data before it is analyzed, repairing or
+// leaving out inconsistent records as Validation describes. What it found is
+// logged and kept in d.Validation. now is when the wrap runs.
+func (d *WeeklyData) Validate(now time.Time) {
+	var v Validation
+	v.NegativeBudgeted = clampBudgeted(d.Categories)
+
+	known := make(map[string]bool, 2*len(d.Categories))
+	for _, cat := range d.Categories {
+		known[cat.ID] = true
+		known[cat.Name] = true
+	}
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	transactions := d.Transactions[:0]
+	for _, tx := range d.Transactions {
+		if tx.Date != nil && tx.Date.After(today) {
+			v.FutureDated++
+			continue
+		}
+		// Merged partner budgets keep their own category IDs, so a
+		// category is known by its ID or its name
+		if tx.CategoryID != nil && !known[*tx.CategoryID] && !known[tx.CategoryName] {
+			v.UnknownCategory++
+			tx.CategoryID = nil
+		}
+		transactions = append(transactions, tx)
+	}
+	d.Transactions = transactions
+	d.Validation = v.log("weekly")
+}
+
+// Validate checks the month's categories before they are analyzed, like
+// WeeklyData.Validate
+func (d *MonthlyData) Validate() {
+	v := Validation{NegativeBudgeted: clampBudgeted(d.Categories)}
+	d.Validation = v.log("monthly")
+}
+
+// clampBudgeted sets negative budgeted amounts to zero and returns how many
+// there were
+func clampBudgeted(categories []Category) int {
+	count := 0
+	for i := range categories {
+		if categories[i].Budgeted < 0 {
+			categories[i].Budgeted = 0
+			count++
+		}
+	}
+	return count
+}
+
+// log reports the counts for a wrap of the given kind, returning v, or nil
+// when there is nothing to report
+func (v Validation) log(kind string) *Validation {
+	if v.Empty() {
+		return nil
+	}
+	log.Printf("Validated %s data: %d transactions with unknown categories counted as uncategorized, %d negative budgets counted as zero, %d future-dated transactions left out",
+		kind, v.UnknownCategory, v.NegativeBudgeted, v.FutureDated)
+	return &v
+}
@@ -0,0 +1,71 @@
+package ynab
+
+import (
+	"testing"
+	"time"
+)
+
+func TestWeeklyData_Validate(t *testing.T) {
+	now := time.Date(2026, 1, 21, 15, 0, 0, 0, time.UTC)
+	today := time.Date(2026, 1, 21, 0, 0, 0, 0, time.UTC)
+	tomorrow := today.AddDate(0, 0, 1)
+	groceries, gone, partner := "c1", "c-deleted", "p1"
+	data := &WeeklyData{
+		Categories: []Category{
+			{ID: "c1", Name: "Groceries", Budgeted: 500_000},
+			{ID: "c2", Name: "Dining", Budgeted: -20_000},
+		},
+		Transactions: []Transaction{
+			{ID: "t1", Date: &today, Amount: -10_000, CategoryID: &groceries, CategoryName: "Groceries"},
+			{ID: "t2", Date: &today, Amount: -20_000, CategoryID: &gone, CategoryName: "Old Category"},
+			{ID: "t3", Date: &today, Amount: -30_000, CategoryID: &partner, CategoryName: "Dining"}, // merged partner budget
+			{ID: "t4", Date: &tomorrow, Amount: -40_000, CategoryID: &groceries, CategoryName: "Groceries"},
+			{ID: "t5", Date: &today, Amount: -50_000},
+		},
+	}
+
+	data.Validate(now)
+
+	want := Validation{UnknownCategory: 1, NegativeBudgeted: 1, FutureDated: 1}
+	if data.Validation == nil || *data.Validation != want {
+		t.Fatalf("Validation: got %+v, want %+v", data.Validation, want)
+	}
+	if len(data.Transactions) != 4 {
+		t.Fatalf("expected the future-dated transaction left out, got %d transactions", len(data.Transactions))
+	}
+	if data.Transactions[1].CategoryID != nil {
+		t.Error("expected the transaction with an unknown category to be uncategorized")
+	}
+	if data.Transactions[2].CategoryID == nil {
+		t.Error("expected a category known by name to be kept")
+	}
+	if data.Categories[1].Budgeted != 0 {
+		t.Errorf("expected the negative budget counted as zero, got %d", data.Categories[1].Budgeted)
+	}
+}
+
+func TestWeeklyData_ValidateConsistent(t *testing.T) {
+	today := time.Date(2026, 1, 21, 0, 0, 0, 0, time.UTC)
+	catID := "c1"
+	data := &WeeklyData{
+		Categories:   []Category{{ID: "c1", Name: "Groceries", Budgeted: 500_000}},
+		Transactions: []Transaction{{ID: "t1", Date: &today, Amount: -10_000, CategoryID: &catID, CategoryName: "Groceries"}},
+	}
+
+	data.Validate(today)
+
+	if data.Validation != nil {
+		t.Errorf("expected no Validation for consistent data, got %+v", data.Validation)
+	}
+	if len(data.Transactions) != 1 {
+		t.Errorf("expected the transaction kept, got %d", len(data.Transactions))
+	}
+}
+
+func TestMonthlyData_Validate(t *testing.T) {
+	data := &MonthlyData{Categories: []Category{{Name: "Dining", Budgeted: -20_000}}}
+	data.Validate()
+	if data.Validation == nil || data.Validation.NegativeBudgeted != 1 || data.Categories[0].Budgeted != 0 {
+		t.Errorf("expected the negative budget counted as zero, got %+v", data.Validation)
+	}
+}